@@ -10,11 +10,11 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	v4 "github.com/nspcc-dev/neofs-s3-gw/api/auth/signer/v4"
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
@@ -24,9 +24,6 @@ import (
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 )
 
-// authorizationFieldRegexp -- is regexp for credentials with Base58 encoded cid and oid and '0' (zero) as delimiter.
-var authorizationFieldRegexp = regexp.MustCompile(`AWS4-HMAC-SHA256 Credential=(?P<access_key_id>[^/]+)/(?P<date>[^/]+)/(?P<region>[^/]*)/(?P<service>[^/]+)/aws4_request,\s*SignedHeaders=(?P<signed_header_fields>.+),\s*Signature=(?P<v4_signature>.+)`)
-
 // postPolicyCredentialRegexp -- is regexp for credentials when uploading file using POST with policy.
 var postPolicyCredentialRegexp = regexp.MustCompile(`(?P<access_key_id>[^/]+)/(?P<date>[^/]+)/(?P<region>[^/]*)/(?P<service>[^/]+)/aws4_request`)
 
@@ -34,19 +31,23 @@ type (
 	// Center is a user authentication interface.
 	Center interface {
 		Authenticate(request *http.Request) (*Box, error)
+		// CacheStats returns statistics of the access box cache.
+		CacheStats() cache.Stats
 	}
 
 	// Box contains access box and additional info.
 	Box struct {
-		AccessBox  *accessbox.Box
-		ClientTime time.Time
+		AccessBox   *accessbox.Box
+		AccessKeyID string
+		ClientTime  time.Time
 	}
 
 	center struct {
-		reg                        *RegexpSubmatcher
 		postReg                    *RegexpSubmatcher
 		cli                        tokens.Credentials
 		allowedAccessKeyIDPrefixes []string // empty slice means all access key ids are allowed
+		nonces                     *PresignNonces
+		epochs                     *epochCache // nil means expiration of credentials isn't checked
 	}
 
 	prs int
@@ -65,9 +66,18 @@ type (
 
 const (
 	accessKeyPartsNum  = 2
-	authHeaderPartsNum = 6
+	credentialPartsNum = 5
 	maxFormSizeMemory  = 50 * 1048576 // 50 MB
 
+	// signatureV4Algorithm is the only supported algorithm of POST policy signatures.
+	signatureV4Algorithm = "AWS4-HMAC-SHA256"
+
+	// Elements of Authorization header.
+	authCredentialElem    = signatureV4Algorithm + " Credential="
+	authSignedHeadersElem = "SignedHeaders="
+	authSignatureElem     = "Signature="
+	authScopeTerminator   = "aws4_request"
+
 	AmzAlgorithm     = "X-Amz-Algorithm"
 	AmzCredential    = "X-Amz-Credential"
 	AmzSignature     = "X-Amz-Signature"
@@ -91,36 +101,105 @@ func (p prs) Seek(_ int64, _ int) (int64, error) {
 
 var _ io.ReadSeeker = prs(0)
 
-// New creates an instance of AuthCenter.
-func New(neoFS tokens.NeoFS, key *keys.PrivateKey, prefixes []string, config *cache.Config) Center {
+// New creates an instance of AuthCenter. Nonces of presigned URLs are checked if nonces aren't nil.
+// Credentials with expired bearer tokens are rejected with ExpiredToken error if epochs aren't nil.
+func New(neoFS tokens.NeoFS, key *keys.PrivateKey, prefixes []string, config *cache.Config, nonces *PresignNonces, epochs EpochSource) Center {
 	return &center{
 		cli:                        tokens.New(neoFS, key, config),
-		reg:                        NewRegexpMatcher(authorizationFieldRegexp),
 		postReg:                    NewRegexpMatcher(postPolicyCredentialRegexp),
 		allowedAccessKeyIDPrefixes: prefixes,
+		nonces:                     nonces,
+		epochs:                     newEpochCache(epochs),
 	}
 }
 
-func (c *center) parseAuthHeader(header string) (*authHeader, error) {
-	submatches := c.reg.GetSubmatches(header)
-	if len(submatches) != authHeaderPartsNum {
-		return nil, apiErrors.GetAPIError(apiErrors.ErrAuthorizationHeaderMalformed)
+// parseAuthHeader parses Authorization header of the form
+// "AWS4-HMAC-SHA256 Credential={access key id}/{date}/{region}/{service}/aws4_request, SignedHeaders={fields}, Signature={signature}".
+// The header is parsed on every request, so it's scanned without regular expressions and intermediate maps.
+func parseAuthHeader(header string) (*authHeader, error) {
+	malformed := apiErrors.GetAPIError(apiErrors.ErrAuthorizationHeaderMalformed)
+
+	start := strings.Index(header, authCredentialElem)
+	if start < 0 {
+		return nil, malformed
 	}
+	rest := header[start+len(authCredentialElem):]
 
-	accessKey := strings.Split(submatches["access_key_id"], "0")
-	if len(accessKey) != accessKeyPartsNum {
+	var scope [credentialPartsNum]string
+	for i := range scope {
+		sep := byte('/')
+		if i == len(scope)-1 {
+			sep = ','
+		}
+		end := strings.IndexByte(rest, sep)
+		if end < 0 {
+			return nil, malformed
+		}
+		scope[i], rest = rest[:end], rest[end+1:]
+	}
+	// the region may be empty, other scope elements may not
+	if scope[0] == "" || scope[1] == "" || scope[3] == "" || scope[4] != authScopeTerminator {
+		return nil, malformed
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	if !strings.HasPrefix(rest, authSignedHeadersElem) {
+		return nil, malformed
+	}
+	rest = rest[len(authSignedHeadersElem):]
+
+	end := strings.LastIndex(rest, ",")
+	if end <= 0 {
+		return nil, malformed
+	}
+	signedHeaders, rest := rest[:end], strings.TrimLeft(rest[end+1:], " \t")
+	if !strings.HasPrefix(rest, authSignatureElem) || len(rest) == len(authSignatureElem) {
+		return nil, malformed
+	}
+
+	if strings.Count(scope[0], "0") != accessKeyPartsNum-1 {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidAccessKeyID)
 	}
 
-	signedFields := strings.Split(submatches["signed_header_fields"], ";")
+	return &authHeader{
+		AccessKeyID:  scope[0],
+		Service:      scope[3],
+		Region:       scope[2],
+		SignatureV4:  rest[len(authSignatureElem):],
+		SignedFields: strings.Split(signedHeaders, ";"),
+		Date:         scope[1],
+	}, nil
+}
+
+// authHeaderSignature returns the signature of Authorization header made by the signer.
+func authHeaderSignature(header string) string {
+	if i := strings.LastIndex(header, authSignatureElem); i >= 0 {
+		return header[i+len(authSignatureElem):]
+	}
+	return ""
+}
+
+// parsePresignedHeader parses authentication parameters of presigned request from its query.
+func parsePresignedHeader(queryValues url.Values) (*authHeader, error) {
+	creds := strings.Split(queryValues.Get(AmzCredential), "/")
+	if len(creds) != credentialPartsNum || creds[4] != authScopeTerminator {
+		return nil, fmt.Errorf("bad X-Amz-Credential")
+	}
+
+	expiration, err := time.ParseDuration(queryValues.Get(AmzExpires) + "s")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse X-Amz-Expires: %w", err)
+	}
 
 	return &authHeader{
-		AccessKeyID:  submatches["access_key_id"],
-		Service:      submatches["service"],
-		Region:       submatches["region"],
-		SignatureV4:  submatches["v4_signature"],
-		SignedFields: signedFields,
-		Date:         submatches["date"],
+		AccessKeyID:  creds[0],
+		Service:      creds[3],
+		Region:       creds[2],
+		SignatureV4:  queryValues.Get(AmzSignature),
+		SignedFields: strings.Split(queryValues.Get(AmzSignedHeaders), ";"),
+		Date:         creds[1],
+		IsPresigned:  true,
+		Expiration:   expiration,
 	}, nil
 }
 
@@ -132,6 +211,10 @@ func (a *authHeader) getAddress() (oid.Address, error) {
 	return addr, nil
 }
 
+func (c *center) CacheStats() cache.Stats {
+	return c.cli.CacheStats()
+}
+
 func (c *center) Authenticate(r *http.Request) (*Box, error) {
 	var (
 		err                  error
@@ -141,23 +224,10 @@ func (c *center) Authenticate(r *http.Request) (*Box, error) {
 	)
 
 	queryValues := r.URL.Query()
-	if queryValues.Get(AmzAlgorithm) == "AWS4-HMAC-SHA256" {
-		creds := strings.Split(queryValues.Get(AmzCredential), "/")
-		if len(creds) != 5 || creds[4] != "aws4_request" {
-			return nil, fmt.Errorf("bad X-Amz-Credential")
-		}
-		authHdr = &authHeader{
-			AccessKeyID:  creds[0],
-			Service:      creds[3],
-			Region:       creds[2],
-			SignatureV4:  queryValues.Get(AmzSignature),
-			SignedFields: queryValues[AmzSignedHeaders],
-			Date:         creds[1],
-			IsPresigned:  true,
-		}
-		authHdr.Expiration, err = time.ParseDuration(queryValues.Get(AmzExpires) + "s")
+	if queryValues.Get(AmzAlgorithm) == signatureV4Algorithm {
+		authHdr, err = parsePresignedHeader(queryValues)
 		if err != nil {
-			return nil, fmt.Errorf("couldn't parse X-Amz-Expires: %w", err)
+			return nil, err
 		}
 		signatureDateTimeStr = queryValues.Get(AmzDate)
 	} else {
@@ -168,7 +238,7 @@ func (c *center) Authenticate(r *http.Request) (*Box, error) {
 			}
 			return nil, ErrNoAuthorizationHeader
 		}
-		authHdr, err = c.parseAuthHeader(authHeaderField[0])
+		authHdr, err = parseAuthHeader(authHeaderField[0])
 		if err != nil {
 			return nil, err
 		}
@@ -200,7 +270,17 @@ func (c *center) Authenticate(r *http.Request) (*Box, error) {
 		return nil, err
 	}
 
-	result := &Box{AccessBox: box}
+	if authHdr.IsPresigned {
+		if err = c.checkNonce(queryValues.Get(NonceQuery), authHdr.AccessKeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = c.checkExpiration(r.Context(), box); err != nil {
+		return nil, err
+	}
+
+	result := &Box{AccessBox: box, AccessKeyID: authHdr.AccessKeyID}
 	if needClientTime {
 		result.ClientTime = signatureDateTime
 	}
@@ -222,6 +302,22 @@ func (c center) checkAccessKeyID(accessKeyID string) error {
 	return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
 }
 
+// checkNonce checks the nonce of the presigned request. The nonce is used once, the request without
+// the nonce is rejected if nonces are required.
+func (c *center) checkNonce(nonce, accessKeyID string) error {
+	if nonce == "" {
+		if c.nonces != nil && c.nonces.Required() {
+			return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+		}
+		return nil
+	}
+
+	if c.nonces == nil || !c.nonces.use(nonce, accessKeyID, time.Now()) {
+		return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+	}
+	return nil
+}
+
 func (c *center) checkFormData(r *http.Request) (*Box, error) {
 	if err := r.ParseMultipartForm(maxFormSizeMemory); err != nil {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidArgument)
@@ -236,11 +332,19 @@ func (c *center) checkFormData(r *http.Request) (*Box, error) {
 		return nil, ErrNoAuthorizationHeader
 	}
 
+	if MultipartFormValue(r, "x-amz-algorithm") != signatureV4Algorithm {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureVersionNotSupported)
+	}
+
 	submatches := c.postReg.GetSubmatches(MultipartFormValue(r, "x-amz-credential"))
 	if len(submatches) != 4 {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrAuthorizationHeaderMalformed)
 	}
 
+	if err := c.checkAccessKeyID(submatches["access_key_id"]); err != nil {
+		return nil, err
+	}
+
 	signatureDateTime, err := time.Parse("20060102T150405Z", MultipartFormValue(r, "x-amz-date"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse x-amz-date field: %w", err)
@@ -260,21 +364,30 @@ func (c *center) checkFormData(r *http.Request) (*Box, error) {
 	service, region := submatches["service"], submatches["region"]
 
 	signature := signStr(secret, service, region, signatureDateTime, policy)
-	if signature != MultipartFormValue(r, "x-amz-signature") {
+	if !hmac.Equal([]byte(signature), []byte(MultipartFormValue(r, "x-amz-signature"))) {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureDoesNotMatch)
 	}
 
-	return &Box{AccessBox: box}, nil
+	if err = c.checkExpiration(r.Context(), box); err != nil {
+		return nil, err
+	}
+
+	return &Box{AccessBox: box, AccessKeyID: submatches["access_key_id"]}, nil
 }
 
+// cloneRequest returns a copy of the request with signed headers only, which is modified by the signer.
+// Values of headers are shared with the original request, since the signer replaces them only.
 func cloneRequest(r *http.Request, authHeader *authHeader) *http.Request {
-	otherRequest := r.Clone(context.TODO())
-	otherRequest.Header = make(http.Header)
+	otherRequest := r.WithContext(context.TODO())
+	otherURL := *r.URL
+	otherRequest.URL = &otherURL
+	otherRequest.Header = make(http.Header, len(authHeader.SignedFields)+2)
 
 	for key, val := range r.Header {
 		for _, name := range authHeader.SignedFields {
 			if strings.EqualFold(key, name) {
 				otherRequest.Header[key] = val
+				break
 			}
 		}
 	}
@@ -289,10 +402,6 @@ func cloneRequest(r *http.Request, authHeader *authHeader) *http.Request {
 }
 
 func (c *center) checkSign(authHeader *authHeader, box *accessbox.Box, request *http.Request, signatureDateTime time.Time) error {
-	awsCreds := credentials.NewStaticCredentials(authHeader.AccessKeyID, box.Gate.AccessKey, "")
-	signer := v4.NewSigner(awsCreds)
-
-	var signature string
 	if authHeader.IsPresigned {
 		now := time.Now()
 		if signatureDateTime.Add(authHeader.Expiration).Before(now) {
@@ -301,16 +410,27 @@ func (c *center) checkSign(authHeader *authHeader, box *accessbox.Box, request *
 		if now.Before(signatureDateTime) {
 			return apiErrors.GetAPIError(apiErrors.ErrBadRequest)
 		}
+	}
+
+	return c.checkSignature(authHeader, box, request, signatureDateTime)
+}
+
+// checkSignature compares the signature of the request with the one calculated with the secret from the box.
+func (c *center) checkSignature(authHeader *authHeader, box *accessbox.Box, request *http.Request, signatureDateTime time.Time) error {
+	signer := v4.NewStaticSigner(authHeader.AccessKeyID, box.Gate.AccessKey)
+	signer.DisableURIPathEscaping = true
+
+	var signature string
+	if authHeader.IsPresigned {
 		if _, err := signer.Presign(request, nil, authHeader.Service, authHeader.Region, authHeader.Expiration, signatureDateTime); err != nil {
 			return fmt.Errorf("failed to pre-sign temporary HTTP request: %w", err)
 		}
 		signature = request.URL.Query().Get(AmzSignature)
 	} else {
-		signer.DisableURIPathEscaping = true
 		if _, err := signer.Sign(request, nil, authHeader.Service, authHeader.Region, signatureDateTime); err != nil {
 			return fmt.Errorf("failed to sign temporary HTTP request: %w", err)
 		}
-		signature = c.reg.GetSubmatches(request.Header.Get(AuthorizationHdr))["v4_signature"]
+		signature = authHeaderSignature(request.Header.Get(AuthorizationHdr))
 	}
 
 	if authHeader.SignatureV4 != signature {
@@ -320,6 +440,12 @@ func (c *center) checkSign(authHeader *authHeader, box *accessbox.Box, request *
 	return nil
 }
 
+// SignPostPolicy returns the signature of the base64 encoded policy of the POST form
+// made with the secret access key the same way the gateway checks it.
+func SignPostPolicy(secret, region string, t time.Time, policy string) string {
+	return signStr(secret, "s3", region, t, policy)
+}
+
 func signStr(secret, service, region string, t time.Time, strToSign string) string {
 	creds := deriveKey(secret, service, region, t)
 	signature := hmacSHA256(creds, []byte(strToSign))
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-api-go/v2/acl"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+)
+
+type (
+	// EpochSource provides the current NeoFS epoch to detect credentials with expired bearer tokens.
+	EpochSource interface {
+		CurrentEpoch(context.Context) (uint64, error)
+	}
+
+	// epochCache caches the current epoch to avoid network requests on every authentication,
+	// epochs last much longer than the cache.
+	epochCache struct {
+		src EpochSource
+
+		mu      sync.Mutex
+		epoch   uint64
+		updated time.Time
+	}
+)
+
+// epochCacheLifetime is the time the current epoch is cached for.
+const epochCacheLifetime = 10 * time.Second
+
+func newEpochCache(src EpochSource) *epochCache {
+	if src == nil {
+		return nil
+	}
+	return &epochCache{src: src}
+}
+
+func (e *epochCache) current(ctx context.Context, now time.Time) (uint64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.updated.IsZero() && now.Sub(e.updated) < epochCacheLifetime {
+		return e.epoch, nil
+	}
+
+	epoch, err := e.src.CurrentEpoch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	e.epoch, e.updated = epoch, now
+	return epoch, nil
+}
+
+// checkExpiration returns ExpiredToken error if the bearer token of the box is expired in the current epoch.
+// The check is skipped if the current epoch is unavailable, NeoFS rejects requests with the expired token anyway.
+func (c *center) checkExpiration(ctx context.Context, box *accessbox.Box) error {
+	if c.epochs == nil || box.Gate == nil || box.Gate.BearerToken == nil {
+		return nil
+	}
+
+	var token acl.BearerToken
+	box.Gate.BearerToken.WriteToV2(&token)
+	lifetime := token.GetBody().GetLifetime()
+	if lifetime == nil {
+		return nil
+	}
+
+	epoch, err := c.epochs.current(ctx, time.Now())
+	if err != nil || lifetime.GetExp() >= epoch {
+		return nil
+	}
+
+	metrics.ExpiredCredentials()
+	return apiErrors.GetAPIErrorWithError(apiErrors.ErrExpiredToken,
+		fmt.Errorf("bearer token expired after epoch %d, current epoch is %d, reissue the credentials", lifetime.GetExp(), epoch))
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+)
+
+type (
+	// TOTPValidator validates time-based one-time passwords (RFC 6238) of MFA devices. Codes have 6 digits
+	// and are generated with HMAC-SHA1 every 30 seconds, codes of adjacent periods are accepted to tolerate
+	// clock skew. Every code is accepted once. Every device belongs to the owner and is accepted only for
+	// buckets of this owner. The device is locked out for a while after several invalid codes in a row,
+	// so codes can't be brute-forced.
+	TOTPValidator struct {
+		mu      sync.Mutex
+		devices map[string]*mfaDevice
+	}
+
+	// MFADevice is a configuration of the MFA device.
+	MFADevice struct {
+		Serial string
+		// Secret is a base32-encoded secret as it's shown by authenticator applications.
+		Secret string
+		// Owner is a user ID or a hex-encoded public key of the owner of buckets protected by the device.
+		Owner string
+	}
+
+	mfaDevice struct {
+		owner  user.ID
+		secret []byte
+		// lastStep is the time step of the last accepted code.
+		lastStep uint64
+		// failures is a number of invalid codes since the last accepted code or lockout.
+		failures int
+		// lockedUntil is the end of the lockout after mfaMaxFailures invalid codes.
+		lockedUntil time.Time
+	}
+)
+
+const (
+	totpPeriod = 30 * time.Second
+	totpDigits = 6
+	totpSkew   = 1
+
+	// mfaMaxFailures is a number of invalid codes in a row locking out the device for mfaLockout.
+	mfaMaxFailures = 5
+	mfaLockout     = 15 * time.Minute
+)
+
+var (
+	errUnknownMFADevice = errors.New("unknown MFA device")
+	errMFADeviceOwner   = errors.New("MFA device doesn't belong to the bucket owner")
+	errInvalidMFACode   = errors.New("invalid MFA code")
+	errMFADeviceLocked  = errors.New("MFA device is locked out after invalid codes")
+)
+
+// NewTOTPValidator creates a validator of MFA devices, every device must have an owner.
+func NewTOTPValidator(devices []MFADevice) (*TOTPValidator, error) {
+	v := &TOTPValidator{devices: make(map[string]*mfaDevice, len(devices))}
+	for _, d := range devices {
+		if _, ok := v.devices[d.Serial]; ok {
+			return nil, fmt.Errorf("duplicated MFA device '%s'", d.Serial)
+		}
+
+		owner, err := parseMFADeviceOwner(d.Owner)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner of MFA device '%s': %w", d.Serial, err)
+		}
+
+		key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(strings.TrimRight(d.Secret, "=")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret of MFA device '%s': %w", d.Serial, err)
+		}
+		v.devices[d.Serial] = &mfaDevice{owner: owner, secret: key}
+	}
+	return v, nil
+}
+
+// parseMFADeviceOwner decodes the user ID or the public key of the device owner.
+func parseMFADeviceOwner(owner string) (user.ID, error) {
+	var id user.ID
+	if owner == "" {
+		return id, errors.New("owner is missing")
+	}
+
+	if key, err := keys.NewPublicKeyFromString(owner); err == nil {
+		user.IDFromKey(&id, (ecdsa.PublicKey)(*key))
+		return id, nil
+	}
+
+	scriptHash, err := address.StringToUint160(owner)
+	if err != nil {
+		return id, fmt.Errorf("neither user ID nor public key: %w", err)
+	}
+	id.SetScriptHash(scriptHash)
+	return id, nil
+}
+
+// Validate checks the current code of the MFA device with the serial number, the device must belong
+// to the owner of the bucket.
+func (v *TOTPValidator) Validate(owner user.ID, serial, code string) error {
+	return v.validate(owner, serial, code, time.Now())
+}
+
+func (v *TOTPValidator) validate(owner user.ID, serial, code string, now time.Time) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	device, ok := v.devices[serial]
+	if !ok {
+		return errUnknownMFADevice
+	}
+	if !device.owner.Equals(owner) {
+		return errMFADeviceOwner
+	}
+	if now.Before(device.lockedUntil) {
+		return errMFADeviceLocked
+	}
+
+	current := uint64(now.Unix()) / uint64(totpPeriod/time.Second)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= device.lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(device.secret, step)), []byte(code)) == 1 {
+			device.lastStep = step
+			device.failures = 0
+			return nil
+		}
+	}
+
+	if device.failures++; device.failures >= mfaMaxFailures {
+		device.failures = 0
+		device.lockedUntil = now.Add(mfaLockout)
+	}
+
+	return errInvalidMFACode
+}
+
+// totpCode generates the code of the time step as described in RFC 4226.
+func totpCode(secret []byte, step uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], step)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
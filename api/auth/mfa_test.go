@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	usertest "github.com/nspcc-dev/neofs-sdk-go/user/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPValidator(t *testing.T) {
+	const serial = "arn:aws:iam::123456789012:mfa/user"
+
+	owner := *usertest.ID()
+
+	// secret of RFC 6238 test vectors
+	v, err := NewTOTPValidator([]MFADevice{{Serial: serial, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Owner: owner.EncodeToString()}})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1234567890, code: "005924"},
+	} {
+		require.Equal(t, tc.code, totpCode(v.devices[serial].secret, uint64(tc.unix)/30))
+	}
+
+	now := time.Unix(1234567890, 0)
+	require.ErrorIs(t, v.validate(owner, "unknown", "005924", now), errUnknownMFADevice)
+	require.ErrorIs(t, v.validate(owner, serial, "000000", now), errInvalidMFACode)
+
+	// the device isn't accepted for buckets of other owners
+	require.ErrorIs(t, v.validate(*usertest.ID(), serial, "005924", now), errMFADeviceOwner)
+
+	// the code of the previous period is accepted, but only once
+	require.NoError(t, v.validate(owner, serial, "005924", now.Add(totpPeriod)))
+	require.ErrorIs(t, v.validate(owner, serial, "005924", now.Add(totpPeriod)), errInvalidMFACode)
+
+	_, err = NewTOTPValidator([]MFADevice{{Serial: serial, Secret: "not base32!", Owner: owner.EncodeToString()}})
+	require.Error(t, err)
+
+	_, err = NewTOTPValidator([]MFADevice{{Serial: serial, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"}})
+	require.Error(t, err)
+}
+
+func TestTOTPValidatorLockout(t *testing.T) {
+	const serial = "arn:aws:iam::123456789012:mfa/user"
+
+	owner := *usertest.ID()
+	v, err := NewTOTPValidator([]MFADevice{{Serial: serial, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Owner: owner.EncodeToString()}})
+	require.NoError(t, err)
+
+	now := time.Unix(1234567890, 0)
+
+	// the accepted code resets the number of failures
+	for i := 0; i < mfaMaxFailures-1; i++ {
+		require.ErrorIs(t, v.validate(owner, serial, "000000", now), errInvalidMFACode)
+	}
+	require.NoError(t, v.validate(owner, serial, "005924", now))
+
+	for i := 0; i < mfaMaxFailures; i++ {
+		require.ErrorIs(t, v.validate(owner, serial, "000000", now), errInvalidMFACode)
+	}
+
+	// the valid code isn't accepted during the lockout
+	later := now.Add(totpPeriod)
+	code := totpCode(v.devices[serial].secret, uint64(later.Unix())/30)
+	require.ErrorIs(t, v.validate(owner, serial, code, later), errMFADeviceLocked)
+
+	// the device is accepted again after the lockout
+	later = now.Add(mfaLockout)
+	code = totpCode(v.devices[serial].secret, uint64(later.Unix())/30)
+	require.NoError(t, v.validate(owner, serial, code, later))
+}
+
+func TestTOTPValidatorOwnerKey(t *testing.T) {
+	const serial = "arn:aws:iam::123456789012:mfa/user"
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	var owner user.ID
+	user.IDFromKey(&owner, (ecdsa.PublicKey)(*key.PublicKey()))
+
+	v, err := NewTOTPValidator([]MFADevice{{Serial: serial, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Owner: hex.EncodeToString(key.PublicKey().Bytes())}})
+	require.NoError(t, err)
+	require.True(t, owner.Equals(v.devices[serial].owner))
+
+	_, err = NewTOTPValidator([]MFADevice{{Serial: serial, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Owner: "owner"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// PresignNonces stores nonces issued by the gateway to protect presigned URLs from replays.
+	// The nonce is signed as a query parameter of the presigned URL and is removed on the first use,
+	// so the URL is accepted once within its expiration period.
+	PresignNonces struct {
+		mu       sync.Mutex
+		required bool
+		nonces   map[string]presignNonce
+		// perKey is a number of issued nonces by access key ids.
+		perKey map[string]int
+	}
+
+	presignNonce struct {
+		accessKeyID string
+		expiration  time.Time
+	}
+)
+
+const (
+	// NonceQuery is a query parameter of the presigned URL with the nonce issued by the gateway.
+	NonceQuery = "X-Neofs-Nonce"
+
+	presignNonceSize = 32
+
+	// maxPresignNonces is a number of nonces kept by the gateway.
+	maxPresignNonces = 100000
+	// maxPresignNoncesPerKey is a number of nonces kept for the single access key id.
+	maxPresignNoncesPerKey = 1000
+)
+
+// NewPresignNonces creates an empty nonce storage. If required is set, presigned URLs without the nonce are rejected.
+func NewPresignNonces(required bool) *PresignNonces {
+	return &PresignNonces{
+		required: required,
+		nonces:   make(map[string]presignNonce),
+		perKey:   make(map[string]int),
+	}
+}
+
+// SetRequired sets whether presigned URLs without the nonce are rejected.
+func (p *PresignNonces) SetRequired(required bool) {
+	p.mu.Lock()
+	p.required = required
+	p.mu.Unlock()
+}
+
+// Required returns true if presigned URLs without the nonce are rejected.
+func (p *PresignNonces) Required() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.required
+}
+
+// Issue creates the nonce for URLs presigned with the access key id, the nonce must be used
+// before the expiration. SlowDown error is returned if too many nonces are issued by the gateway
+// or for the access key id, expired nonces are removed then.
+func (p *PresignNonces) Issue(accessKeyID string, lifetime time.Duration, now time.Time) (string, time.Time, error) {
+	raw := make([]byte, presignNonceSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	nonce := hex.EncodeToString(raw)
+	expiration := now.Add(lifetime)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.limitExceeded(accessKeyID) {
+		for key, val := range p.nonces {
+			if !now.Before(val.expiration) {
+				p.remove(key, val)
+			}
+		}
+		if p.limitExceeded(accessKeyID) {
+			return "", time.Time{}, apiErrors.GetAPIError(apiErrors.ErrSlowDown)
+		}
+	}
+	p.nonces[nonce] = presignNonce{accessKeyID: accessKeyID, expiration: expiration}
+	p.perKey[accessKeyID]++
+
+	return nonce, expiration, nil
+}
+
+func (p *PresignNonces) limitExceeded(accessKeyID string) bool {
+	return len(p.nonces) >= maxPresignNonces || p.perKey[accessKeyID] >= maxPresignNoncesPerKey
+}
+
+func (p *PresignNonces) remove(nonce string, val presignNonce) {
+	delete(p.nonces, nonce)
+	if p.perKey[val.accessKeyID]--; p.perKey[val.accessKeyID] <= 0 {
+		delete(p.perKey, val.accessKeyID)
+	}
+}
+
+// use removes the nonce and returns true if it was issued for the access key id and isn't expired.
+func (p *PresignNonces) use(nonce, accessKeyID string, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	val, ok := p.nonces[nonce]
+	if !ok || val.accessKeyID != accessKeyID {
+		return false
+	}
+	p.remove(nonce, val)
+
+	return now.Before(val.expiration)
+}
@@ -65,6 +65,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -85,8 +86,19 @@ const (
 
 	// emptyStringSHA256 is a SHA256 of an empty string.
 	emptyStringSHA256 = `e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855`
+
+	// signingKeyCacheSize is a max number of cached signing keys, the cache is cleared when it's full.
+	signingKeyCacheSize = 4096
 )
 
+// signingKeys caches signing keys derived from secret access keys. The key is the same for all
+// requests signed with the secret for the same day, region and service, so four HMAC calculations
+// are saved on every signature check.
+var signingKeys = struct {
+	sync.RWMutex
+	m map[string][]byte
+}{m: make(map[string][]byte)}
+
 var ignoredPresignHeaders = rules{
 	blacklist{
 		mapRule{
@@ -213,6 +225,10 @@ type Signer struct {
 	// UnsignedPayload will prevent signing of the payload. This will only
 	// work for services that have support for this.
 	UnsignedPayload bool
+
+	// staticCredentials are used instead of Credentials if they are set,
+	// so they aren't retrieved on every signing.
+	staticCredentials *credentials.Value
 }
 
 // NewSigner returns a Signer pointer configured with the credentials and optional
@@ -230,6 +246,25 @@ func NewSigner(credentials *credentials.Credentials, options ...func(*Signer)) *
 	return v4
 }
 
+// NewStaticSigner returns a Signer pointer configured with the static credentials.
+// Unlike the signer with credentials.NewStaticCredentials, it doesn't retrieve
+// the credentials on every signing, so it's cheaper for requests signed once.
+func NewStaticSigner(accessKeyID, secretAccessKey string, options ...func(*Signer)) *Signer {
+	v4 := &Signer{
+		staticCredentials: &credentials.Value{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			ProviderName:    credentials.StaticProviderName,
+		},
+	}
+
+	for _, option := range options {
+		option(v4)
+	}
+
+	return v4
+}
+
 type signingCtx struct {
 	ServiceName      string
 	Region           string
@@ -246,6 +281,9 @@ type signingCtx struct {
 	isPresign       bool
 	unsignedPayload bool
 
+	formattedTime      string
+	formattedShortTime string
+
 	bodyDigest       string
 	signedHeaders    string
 	canonicalHeaders string
@@ -346,10 +384,14 @@ func (v4 Signer) signWithBody(r *http.Request, body io.ReadSeeker, service, regi
 		ctx.handlePresignRemoval()
 	}
 
-	var err error
-	ctx.credValues, err = v4.Credentials.GetWithContext(requestContext(r))
-	if err != nil {
-		return http.Header{}, err
+	if v4.staticCredentials != nil {
+		ctx.credValues = *v4.staticCredentials
+	} else {
+		var err error
+		ctx.credValues, err = v4.Credentials.GetWithContext(requestContext(r))
+		if err != nil {
+			return http.Header{}, err
+		}
 	}
 
 	ctx.sanitizeHostForHeader()
@@ -548,12 +590,9 @@ func (ctx *signingCtx) build(disableHeaderHoisting bool) error {
 	if ctx.isPresign {
 		ctx.Request.URL.RawQuery += "&" + signatureQueryKey + "=" + ctx.signature
 	} else {
-		parts := []string{
-			authHeaderPrefix + " Credential=" + ctx.credValues.AccessKeyID + "/" + ctx.credentialString,
-			"SignedHeaders=" + ctx.signedHeaders,
-			authHeaderSignatureElem + ctx.signature,
-		}
-		ctx.Request.Header.Set(authorizationHeader, strings.Join(parts, ", "))
+		ctx.Request.Header.Set(authorizationHeader, authHeaderPrefix+" Credential="+ctx.credValues.AccessKeyID+"/"+ctx.credentialString+
+			", SignedHeaders="+ctx.signedHeaders+
+			", "+authHeaderSignatureElem+ctx.signature)
 	}
 
 	return nil
@@ -584,17 +623,21 @@ func GetSignedRequestSignature(r *http.Request) ([]byte, error) {
 }
 
 func (ctx *signingCtx) buildTime() {
+	ctx.formattedTime = formatTime(ctx.Time)
+	// the short time is the date prefix of the time
+	ctx.formattedShortTime = ctx.formattedTime[:len(shortTimeFormat)]
+
 	if ctx.isPresign {
 		duration := int64(ctx.ExpireTime / time.Second)
-		ctx.Query.Set("X-Amz-Date", formatTime(ctx.Time))
+		ctx.Query.Set("X-Amz-Date", ctx.formattedTime)
 		ctx.Query.Set("X-Amz-Expires", strconv.FormatInt(duration, 10))
 	} else {
-		ctx.Request.Header.Set("X-Amz-Date", formatTime(ctx.Time))
+		ctx.Request.Header.Set("X-Amz-Date", ctx.formattedTime)
 	}
 }
 
 func (ctx *signingCtx) buildCredentialString() {
-	ctx.credentialString = buildSigningScope(ctx.Region, ctx.ServiceName, ctx.Time)
+	ctx.credentialString = ctx.formattedShortTime + "/" + ctx.Region + "/" + ctx.ServiceName + "/" + awsV4Request
 
 	if ctx.isPresign {
 		ctx.Query.Set("X-Amz-Credential", ctx.credValues.AccessKeyID+"/"+ctx.credentialString)
@@ -615,14 +658,14 @@ func buildQuery(r rule, header http.Header) (url.Values, http.Header) {
 	return query, unsignedHeaders
 }
 func (ctx *signingCtx) buildCanonicalHeaders(r rule, header http.Header) {
-	var headers []string
-	headers = append(headers, "host")
+	headers := make([]string, 1, len(header)+1)
+	headers[0] = "host"
 	for k, v := range header {
 		if !r.IsValid(k) {
 			continue // ignored header
 		}
 		if ctx.SignedHeaderVals == nil {
-			ctx.SignedHeaderVals = make(http.Header)
+			ctx.SignedHeaderVals = make(http.Header, len(header))
 		}
 
 		lowerCaseKey := strings.ToLower(k)
@@ -643,21 +686,42 @@ func (ctx *signingCtx) buildCanonicalHeaders(r rule, header http.Header) {
 		ctx.Query.Set("X-Amz-SignedHeaders", ctx.signedHeaders)
 	}
 
-	headerValues := make([]string, len(headers))
+	host := ctx.Request.Host
+	if host == "" {
+		host = ctx.Request.URL.Host
+	}
+
+	size := len(headers) * 2
+	for _, k := range headers {
+		size += len(k)
+		for _, v := range ctx.SignedHeaderVals[k] {
+			size += len(v) + 1
+		}
+	}
+
+	// lines are written to the single buffer instead of joining them,
+	// multiple spaces of values are stripped on write
+	var b strings.Builder
+	b.Grow(size + len(host))
 	for i, k := range headers {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(k)
+		b.WriteByte(':')
 		if k == "host" {
-			if ctx.Request.Host != "" {
-				headerValues[i] = "host:" + ctx.Request.Host
-			} else {
-				headerValues[i] = "host:" + ctx.Request.URL.Host
+			writeStrippedSpaces(&b, host, true)
+			continue
+		}
+		vals := ctx.SignedHeaderVals[k]
+		for j, v := range vals {
+			if j > 0 {
+				b.WriteByte(',')
 			}
-		} else {
-			headerValues[i] = k + ":" +
-				strings.Join(ctx.SignedHeaderVals[k], ",")
+			writeStrippedSpaces(&b, v, j == len(vals)-1)
 		}
 	}
-	stripExcessSpaces(headerValues)
-	ctx.canonicalHeaders = strings.Join(headerValues, "\n")
+	ctx.canonicalHeaders = b.String()
 }
 
 func (ctx *signingCtx) buildCanonicalString() {
@@ -680,16 +744,15 @@ func (ctx *signingCtx) buildCanonicalString() {
 }
 
 func (ctx *signingCtx) buildStringToSign() {
-	ctx.stringToSign = strings.Join([]string{
-		authHeaderPrefix,
-		formatTime(ctx.Time),
-		ctx.credentialString,
-		hex.EncodeToString(hashSHA256([]byte(ctx.canonicalString))),
-	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(ctx.canonicalString))
+	ctx.stringToSign = authHeaderPrefix + "\n" +
+		ctx.formattedTime + "\n" +
+		ctx.credentialString + "\n" +
+		hex.EncodeToString(canonicalHash[:])
 }
 
 func (ctx *signingCtx) buildSignature() {
-	creds := deriveSigningKey(ctx.Region, ctx.ServiceName, ctx.credValues.SecretAccessKey, ctx.Time)
+	creds := cachedSigningKey(ctx.Region, ctx.ServiceName, ctx.credValues.SecretAccessKey, ctx.formattedShortTime)
 	signature := hmacSHA256(creds, []byte(ctx.stringToSign))
 	ctx.signature = hex.EncodeToString(signature)
 }
@@ -758,9 +821,8 @@ func hmacSHA256(key []byte, data []byte) []byte {
 }
 
 func hashSHA256(data []byte) []byte {
-	hash := sha256.New()
-	hash.Write(data)
-	return hash.Sum(nil)
+	hash := sha256.Sum256(data)
+	return hash[:]
 }
 
 func makeSha256Reader(reader io.ReadSeeker) (hashBytes []byte, err error) {
@@ -788,45 +850,24 @@ func makeSha256Reader(reader io.ReadSeeker) (hashBytes []byte, err error) {
 
 const doubleSpace = "  "
 
-// stripExcessSpaces will rewrite the passed in slice's string values to not
-// contain multiple side-by-side spaces.
-func stripExcessSpaces(vals []string) {
-	var j, k, l, m, spaces int
-	for i, str := range vals {
-		// Trim trailing spaces
-		for j = len(str) - 1; j >= 0 && str[j] == ' '; j-- {
-		}
+// writeStrippedSpaces writes the header value to the builder without multiple side-by-side spaces.
+// Trailing spaces are trimmed if the value ends the line of the canonical header. Leading spaces
+// are kept as the line starts with the header name.
+func writeStrippedSpaces(b *strings.Builder, str string, lineEnd bool) {
+	if lineEnd {
+		str = strings.TrimRight(str, " ")
+	}
 
-		// Trim leading spaces
-		for k = 0; k < j && str[k] == ' '; k++ {
-		}
-		str = str[k : j+1]
+	if !strings.Contains(str, doubleSpace) {
+		b.WriteString(str)
+		return
+	}
 
-		// Strip multiple spaces.
-		j = strings.Index(str, doubleSpace)
-		if j < 0 {
-			vals[i] = str
+	for i := 0; i < len(str); i++ {
+		if str[i] == ' ' && i > 0 && str[i-1] == ' ' {
 			continue
 		}
-
-		buf := []byte(str)
-		for k, m, l = j, j, len(buf); k < l; k++ {
-			if buf[k] == ' ' {
-				if spaces == 0 {
-					// First space.
-					buf[m] = buf[k]
-					m++
-				}
-				spaces++
-			} else {
-				// End of multiple spaces.
-				spaces = 0
-				buf[m] = buf[k]
-				m++
-			}
-		}
-
-		vals[i] = string(buf[:m])
+		b.WriteByte(str[i])
 	}
 }
 
@@ -839,6 +880,32 @@ func buildSigningScope(region, service string, dt time.Time) string {
 	}, "/")
 }
 
+// cachedSigningKey returns the signing key derived by deriveSigningKey from the cache.
+func cachedSigningKey(region, service, secretKey, shortTime string) []byte {
+	cacheKey := shortTime + "/" + region + "/" + service + "/" + secretKey
+
+	signingKeys.RLock()
+	key, ok := signingKeys.m[cacheKey]
+	signingKeys.RUnlock()
+	if ok {
+		return key
+	}
+
+	hmacDate := hmacSHA256([]byte("AWS4"+secretKey), []byte(shortTime))
+	hmacRegion := hmacSHA256(hmacDate, []byte(region))
+	hmacService := hmacSHA256(hmacRegion, []byte(service))
+	key = hmacSHA256(hmacService, []byte(awsV4Request))
+
+	signingKeys.Lock()
+	if len(signingKeys.m) >= signingKeyCacheSize {
+		signingKeys.m = make(map[string][]byte)
+	}
+	signingKeys.m[cacheKey] = key
+	signingKeys.Unlock()
+
+	return key
+}
+
 func deriveSigningKey(region, service, secretKey string, dt time.Time) []byte {
 	hmacDate := hmacSHA256([]byte("AWS4"+secretKey), []byte(formatShortTime(dt)))
 	hmacRegion := hmacSHA256(hmacDate, []byte(region))
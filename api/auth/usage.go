@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+)
+
+type (
+	// KeyUsage records the last successful authentication of access keys, so stale keys can be found and revoked.
+	// Usage is kept in memory of the gateway, every gateway of the deployment reports its own requests only.
+	KeyUsage struct {
+		mu   sync.Mutex
+		keys map[string]KeyUse
+	}
+
+	// KeyUse is the last use of the access key.
+	KeyUse struct {
+		AccessKeyID string
+		// Owner is the issuer of the bearer token of the access box.
+		Owner    user.ID
+		LastUsed time.Time
+		SourceIP string
+	}
+)
+
+// NewKeyUsage creates an empty storage of access keys usage.
+func NewKeyUsage() *KeyUsage {
+	return &KeyUsage{keys: make(map[string]KeyUse)}
+}
+
+// Use records the use of the access key at the time from the source IP.
+func (k *KeyUsage) Use(accessKeyID string, owner user.ID, sourceIP string, now time.Time) {
+	k.mu.Lock()
+	k.keys[accessKeyID] = KeyUse{
+		AccessKeyID: accessKeyID,
+		Owner:       owner,
+		LastUsed:    now,
+		SourceIP:    sourceIP,
+	}
+	k.mu.Unlock()
+}
+
+// UseBox records the use of the access key of the authenticated box,
+// boxes without bearer token are skipped as their owner is unknown.
+func (k *KeyUsage) UseBox(box *Box, sourceIP string, now time.Time) {
+	if box.AccessBox.Gate == nil || box.AccessBox.Gate.BearerToken == nil {
+		return
+	}
+	k.Use(box.AccessKeyID, bearer.ResolveIssuer(*box.AccessBox.Gate.BearerToken), sourceIP, now)
+}
+
+// List returns the last uses of the access keys of the owner sorted by access key id. If unusedSince isn't zero,
+// only keys not used since the time are returned.
+func (k *KeyUsage) List(owner user.ID, unusedSince time.Time) []KeyUse {
+	k.mu.Lock()
+	res := make([]KeyUse, 0, len(k.keys))
+	for _, use := range k.keys {
+		if !use.Owner.Equals(owner) || !unusedSince.IsZero() && !use.LastUsed.Before(unusedSince) {
+			continue
+		}
+		res = append(res, use)
+	}
+	k.mu.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].AccessKeyID < res[j].AccessKeyID
+	})
+
+	return res
+}
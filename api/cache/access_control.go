@@ -4,14 +4,13 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/bluele/gcache"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"go.uber.org/zap"
 )
 
 // AccessControlCache provides lru cache for objects.
 type AccessControlCache struct {
-	cache  gcache.Cache
+	cache  *lruCache
 	logger *zap.Logger
 }
 
@@ -33,8 +32,12 @@ func DefaultAccessControlConfig(logger *zap.Logger) *Config {
 
 // NewAccessControlCache creates an object of AccessControlCache.
 func NewAccessControlCache(config *Config) *AccessControlCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &AccessControlCache{cache: gc, logger: config.Logger}
+	return &AccessControlCache{cache: newLRUCache(config), logger: config.Logger}
+}
+
+// Stats returns statistics of the cache.
+func (o *AccessControlCache) Stats() Stats {
+	return o.cache.Stats()
 }
 
 // Get returns true if such key exists.
@@ -64,6 +67,11 @@ func (o *AccessControlCache) Delete(owner user.ID, key string) bool {
 	return o.cache.Remove(cacheKey(owner, key))
 }
 
+// Purge deletes all entries from cache.
+func (o *AccessControlCache) Purge() {
+	o.cache.Purge()
+}
+
 func cacheKey(owner user.ID, key string) string {
 	return owner.EncodeToString() + key
 }
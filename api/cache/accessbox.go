@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/bluele/gcache"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"go.uber.org/zap"
@@ -14,7 +13,7 @@ type (
 	// AccessBoxCache stores an access box by its address.
 	AccessBoxCache struct {
 		logger *zap.Logger
-		cache  gcache.Cache
+		cache  *lruCache
 	}
 
 	// Config stores expiration params for cache.
@@ -43,9 +42,12 @@ func DefaultAccessBoxConfig(logger *zap.Logger) *Config {
 
 // NewAccessBoxCache creates an object of BucketCache.
 func NewAccessBoxCache(config *Config) *AccessBoxCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
+	return &AccessBoxCache{cache: newLRUCache(config), logger: config.Logger}
+}
 
-	return &AccessBoxCache{cache: gc, logger: config.Logger}
+// Stats returns statistics of the cache.
+func (o *AccessBoxCache) Stats() Stats {
+	return o.cache.Stats()
 }
 
 // Get returns a cached object.
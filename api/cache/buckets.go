@@ -4,14 +4,13 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/bluele/gcache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"go.uber.org/zap"
 )
 
 // BucketCache contains cache with objects and the lifetime of cache entries.
 type BucketCache struct {
-	cache  gcache.Cache
+	cache  *lruCache
 	logger *zap.Logger
 }
 
@@ -33,8 +32,12 @@ func DefaultBucketConfig(logger *zap.Logger) *Config {
 
 // NewBucketCache creates an object of BucketCache.
 func NewBucketCache(config *Config) *BucketCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &BucketCache{cache: gc, logger: config.Logger}
+	return &BucketCache{cache: newLRUCache(config), logger: config.Logger}
+}
+
+// Stats returns statistics of the cache.
+func (o *BucketCache) Stats() Stats {
+	return o.cache.Stats()
 }
 
 // Get returns a cached object.
@@ -63,3 +66,8 @@ func (o *BucketCache) Put(bkt *data.BucketInfo) error {
 func (o *BucketCache) Delete(key string) bool {
 	return o.cache.Remove(key)
 }
+
+// Purge deletes all entries from cache.
+func (o *BucketCache) Purge() {
+	o.cache.Purge()
+}
@@ -47,6 +47,24 @@ func TestBucketsCacheType(t *testing.T) {
 	assertInvalidCacheEntry(t, cache.Get(bktInfo.Name), observedLog)
 }
 
+func TestCacheStats(t *testing.T) {
+	logger, _ := getObservedLogger()
+	config := DefaultBucketConfig(logger)
+	config.Size = 2
+	cache := NewBucketCache(config)
+
+	for _, name := range []string{"bucket1", "bucket2", "bucket3"} {
+		require.NoError(t, cache.Put(&data.BucketInfo{Name: name}))
+	}
+
+	require.Nil(t, cache.Get("bucket1"))
+	require.NotNil(t, cache.Get("bucket2"))
+	require.True(t, cache.Delete("bucket3"))
+	require.False(t, cache.Delete("bucket3"))
+
+	require.Equal(t, Stats{Hits: 1, Misses: 1, Evictions: 1, Entries: 1}, cache.Stats())
+}
+
 func TestObjectNamesCacheType(t *testing.T) {
 	logger, observedLog := getObservedLogger()
 	cache := NewObjectsNameCache(DefaultObjectsNameConfig(logger))
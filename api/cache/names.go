@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/bluele/gcache"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"go.uber.org/zap"
 )
@@ -13,7 +12,7 @@ import (
 // This cache contains mapping nice names to object addresses.
 // Key is bucketName+objectName.
 type ObjectsNameCache struct {
-	cache  gcache.Cache
+	cache  *lruCache
 	logger *zap.Logger
 }
 
@@ -35,8 +34,12 @@ func DefaultObjectsNameConfig(logger *zap.Logger) *Config {
 
 // NewObjectsNameCache creates an object of ObjectsNameCache.
 func NewObjectsNameCache(config *Config) *ObjectsNameCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &ObjectsNameCache{cache: gc, logger: config.Logger}
+	return &ObjectsNameCache{cache: newLRUCache(config), logger: config.Logger}
+}
+
+// Stats returns statistics of the cache.
+func (o *ObjectsNameCache) Stats() Stats {
+	return o.cache.Stats()
 }
 
 // Get returns a cached object. Returns nil if value is missing.
@@ -65,3 +68,8 @@ func (o *ObjectsNameCache) Put(key string, address oid.Address) error {
 func (o *ObjectsNameCache) Delete(key string) bool {
 	return o.cache.Remove(key)
 }
+
+// Purge deletes all entries from cache.
+func (o *ObjectsNameCache) Purge() {
+	o.cache.Purge()
+}
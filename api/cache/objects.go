@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/bluele/gcache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"go.uber.org/zap"
@@ -12,7 +11,7 @@ import (
 
 // ObjectsCache provides lru cache for objects.
 type ObjectsCache struct {
-	cache  gcache.Cache
+	cache  *lruCache
 	logger *zap.Logger
 }
 
@@ -34,8 +33,12 @@ func DefaultObjectsConfig(logger *zap.Logger) *Config {
 
 // New creates an object of ObjectHeadersCache.
 func New(config *Config) *ObjectsCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &ObjectsCache{cache: gc, logger: config.Logger}
+	return &ObjectsCache{cache: newLRUCache(config), logger: config.Logger}
+}
+
+// Stats returns statistics of the cache.
+func (o *ObjectsCache) Stats() Stats {
+	return o.cache.Stats()
 }
 
 // GetObject returns a cached object info.
@@ -64,3 +67,8 @@ func (o *ObjectsCache) PutObject(obj *data.ExtendedObjectInfo) error {
 func (o *ObjectsCache) Delete(address oid.Address) bool {
 	return o.cache.Remove(address)
 }
+
+// Purge deletes all entries from cache.
+func (o *ObjectsCache) Purge() {
+	o.cache.Purge()
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/bluele/gcache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"go.uber.org/zap"
@@ -28,7 +27,7 @@ import (
 type (
 	// ObjectsListCache contains cache for ListObjects and ListObjectVersions.
 	ObjectsListCache struct {
-		cache  gcache.Cache
+		cache  *lruCache
 		logger *zap.Logger
 	}
 
@@ -60,10 +59,19 @@ func (k *ObjectsListKey) String() string {
 	return k.cid.EncodeToString() + k.prefix + strconv.FormatBool(k.latestOnly)
 }
 
+// CID returns the container ID of the key.
+func (k *ObjectsListKey) CID() cid.ID {
+	return k.cid
+}
+
 // NewObjectsListCache is a constructor which creates an object of ListObjectsCache with the given lifetime of entries.
 func NewObjectsListCache(config *Config) *ObjectsListCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &ObjectsListCache{cache: gc, logger: config.Logger}
+	return &ObjectsListCache{cache: newLRUCache(config), logger: config.Logger}
+}
+
+// Stats returns statistics of the cache.
+func (l *ObjectsListCache) Stats() Stats {
+	return l.cache.Stats()
 }
 
 // GetVersions returns a list of ObjectInfo.
@@ -104,6 +112,11 @@ func (l *ObjectsListCache) CleanCacheEntriesContainingObject(objectName string,
 	}
 }
 
+// Purge deletes all entries from cache.
+func (l *ObjectsListCache) Purge() {
+	l.cache.Purge()
+}
+
 // CreateObjectsListCacheKey returns ObjectsListKey with the given CID, prefix and latestOnly flag.
 func CreateObjectsListCacheKey(cnr cid.ID, prefix string, latestOnly bool) ObjectsListKey {
 	p := ObjectsListKey{
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"sync/atomic"
+
+	"github.com/bluele/gcache"
+)
+
+type (
+	// Stats contains lookup counters of the cache, the number of evicted entries and the current
+	// number of entries. Evicted entries are removed because the cache is full or their lifetime is over,
+	// explicitly deleted entries aren't counted.
+	Stats struct {
+		Hits      uint64
+		Misses    uint64
+		Evictions uint64
+		Entries   int
+	}
+
+	// lruCache is LRU cache counting evictions of its entries.
+	lruCache struct {
+		gcache.Cache
+
+		// removed counts all entries removed from the cache, deleted counts explicitly deleted ones.
+		removed uint64
+		deleted uint64
+	}
+)
+
+func newLRUCache(config *Config) *lruCache {
+	c := &lruCache{}
+	c.Cache = gcache.New(config.Size).LRU().Expiration(config.Lifetime).
+		EvictedFunc(func(interface{}, interface{}) {
+			atomic.AddUint64(&c.removed, 1)
+		}).
+		Build()
+
+	return c
+}
+
+// Remove deletes the entry from the cache.
+func (c *lruCache) Remove(key interface{}) bool {
+	ok := c.Cache.Remove(key)
+	if ok {
+		atomic.AddUint64(&c.deleted, 1)
+	}
+	return ok
+}
+
+// Stats returns statistics of the cache. The number of entries includes expired entries which
+// aren't evicted yet.
+func (c *lruCache) Stats() Stats {
+	// the entry is counted as removed before it's counted as deleted
+	deleted := atomic.LoadUint64(&c.deleted)
+	removed := atomic.LoadUint64(&c.removed)
+
+	return Stats{
+		Hits:      c.HitCount(),
+		Misses:    c.MissCount(),
+		Evictions: removed - deleted,
+		Entries:   c.Len(false),
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/bluele/gcache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
 	"go.uber.org/zap"
 )
 
@@ -13,7 +13,7 @@ import (
 // This cache contains "system" objects (bucket versioning settings, tagging object etc.).
 // Key is bucketName+systemFilePath.
 type SystemCache struct {
-	cache  gcache.Cache
+	cache  *lruCache
 	logger *zap.Logger
 }
 
@@ -35,8 +35,12 @@ func DefaultSystemConfig(logger *zap.Logger) *Config {
 
 // NewSystemCache creates an object of SystemCache.
 func NewSystemCache(config *Config) *SystemCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &SystemCache{cache: gc, logger: config.Logger}
+	return &SystemCache{cache: newLRUCache(config), logger: config.Logger}
+}
+
+// Stats returns statistics of the cache.
+func (o *SystemCache) Stats() Stats {
+	return o.cache.Stats()
 }
 
 // GetObject returns a cached object.
@@ -119,6 +123,118 @@ func (o *SystemCache) GetNotificationConfiguration(key string) *data.Notificatio
 	return result
 }
 
+func (o *SystemCache) GetLifecycleConfiguration(key string) *data.LifecycleConfiguration {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.LifecycleConfiguration)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetInventoryConfigurations(key string) *data.InventoryConfigurations {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.InventoryConfigurations)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetReplicationConfiguration(key string) *data.ReplicationConfiguration {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.ReplicationConfiguration)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetAnalyticsConfigurations(key string) *data.AnalyticsConfigurations {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.AnalyticsConfigurations)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetMetricsConfigurations(key string) *data.MetricsConfigurations {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.MetricsConfigurations)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetBucketPolicy(key string) *policy.Policy {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*policy.Policy)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+func (o *SystemCache) GetWebsiteConfiguration(key string) *data.WebsiteConfiguration {
+	entry, err := o.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.WebsiteConfiguration)
+	if !ok {
+		o.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
 // GetTagging returns tags of a bucket or an object.
 func (o *SystemCache) GetTagging(key string) map[string]string {
 	entry, err := o.cache.Get(key)
@@ -156,6 +272,34 @@ func (o *SystemCache) PutNotificationConfiguration(key string, obj *data.Notific
 	return o.cache.Set(key, obj)
 }
 
+func (o *SystemCache) PutLifecycleConfiguration(key string, obj *data.LifecycleConfiguration) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutInventoryConfigurations(key string, obj *data.InventoryConfigurations) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutReplicationConfiguration(key string, obj *data.ReplicationConfiguration) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutAnalyticsConfigurations(key string, obj *data.AnalyticsConfigurations) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutMetricsConfigurations(key string, obj *data.MetricsConfigurations) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutBucketPolicy(key string, obj *policy.Policy) error {
+	return o.cache.Set(key, obj)
+}
+
+func (o *SystemCache) PutWebsiteConfiguration(key string, obj *data.WebsiteConfiguration) error {
+	return o.cache.Set(key, obj)
+}
+
 // PutTagging puts tags of a bucket or an object.
 func (o *SystemCache) PutTagging(key string, tagSet map[string]string) error {
 	return o.cache.Set(key, tagSet)
@@ -165,3 +309,8 @@ func (o *SystemCache) PutTagging(key string, tagSet map[string]string) error {
 func (o *SystemCache) Delete(key string) bool {
 	return o.cache.Remove(key)
 }
+
+// Purge deletes all entries from cache.
+func (o *SystemCache) Purge() {
+	o.cache.Purge()
+}
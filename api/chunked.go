@@ -0,0 +1,158 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// StreamingPayloadPrefix starts X-Amz-Content-Sha256 header value of the aws-chunked payload,
+// e.g. STREAMING-AWS4-HMAC-SHA256-PAYLOAD or STREAMING-UNSIGNED-PAYLOAD-TRAILER.
+const StreamingPayloadPrefix = "STREAMING-"
+
+// maxChunkTrailers limits the number of trailing headers of the aws-chunked payload.
+const maxChunkTrailers = 16
+
+var errMalformedChunk = errors.New("malformed aws-chunked payload")
+
+// ChunkedReader decodes the payload sent with aws-chunked content encoding. Chunk signatures
+// are skipped, trailing headers sent after the last chunk are available once the payload is read.
+type ChunkedReader struct {
+	r *bufio.Reader
+
+	// left is a number of unread bytes of the current chunk.
+	left int64
+	// started is set after the first chunk header, every next chunk header follows CRLF.
+	started bool
+	trailer http.Header
+	err     error
+}
+
+// IsStreamingPayload checks if the request payload is sent with aws-chunked content encoding.
+func IsStreamingPayload(header http.Header) bool {
+	return strings.HasPrefix(header.Get(AmzContentSha256), StreamingPayloadPrefix)
+}
+
+// NewChunkedReader creates the reader of the decoded aws-chunked payload.
+func NewChunkedReader(r io.Reader) *ChunkedReader {
+	return &ChunkedReader{
+		r:       bufio.NewReader(r),
+		trailer: make(http.Header),
+	}
+}
+
+// Read implements io.Reader.
+func (c *ChunkedReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if c.left == 0 {
+		if c.err = c.nextChunk(); c.err != nil {
+			return 0, c.err
+		}
+	}
+
+	if int64(len(p)) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	c.err = err
+	return n, err
+}
+
+// Trailer returns trailing headers of the payload, they are available after the payload is read.
+func (c *ChunkedReader) Trailer() http.Header {
+	return c.trailer
+}
+
+func (c *ChunkedReader) nextChunk() error {
+	if c.started {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+		if len(line) != 0 {
+			return fmt.Errorf("%w: missing chunk data delimiter", errMalformedChunk)
+		}
+	}
+	c.started = true
+
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+
+	// chunk header is 'hex-size[;chunk-signature=signature]'
+	if i := bytes.IndexByte(line, ';'); i >= 0 {
+		line = line[:i]
+	}
+	size, err := strconv.ParseUint(string(bytes.TrimSpace(line)), 16, 63)
+	if err != nil {
+		return fmt.Errorf("%w: invalid chunk size: %s", errMalformedChunk, err.Error())
+	}
+	if size == 0 {
+		if err = c.readTrailer(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+
+	c.left = int64(size)
+	return nil
+}
+
+// readTrailer reads 'key:value' lines after the last chunk up to the empty line or the end of the payload,
+// some clients don't send the final CRLF.
+func (c *ChunkedReader) readTrailer() error {
+	for i := 0; ; i++ {
+		line, err := c.readLine()
+		eof := err == io.ErrUnexpectedEOF
+		if err != nil && !eof {
+			return err
+		}
+		if len(line) == 0 {
+			return nil
+		}
+		if i == maxChunkTrailers {
+			return fmt.Errorf("%w: too many trailing headers", errMalformedChunk)
+		}
+
+		sep := bytes.IndexByte(line, ':')
+		if sep <= 0 {
+			return fmt.Errorf("%w: invalid trailing header", errMalformedChunk)
+		}
+		c.trailer.Add(string(bytes.TrimSpace(line[:sep])), string(bytes.TrimSpace(line[sep+1:])))
+		if eof {
+			return nil
+		}
+	}
+}
+
+// readLine reads the line without CRLF, the line is valid until the next read. The incomplete line
+// is returned with io.ErrUnexpectedEOF.
+func (c *ChunkedReader) readLine() ([]byte, error) {
+	line, err := c.r.ReadSlice('\n')
+	switch {
+	case err == io.EOF:
+		return bytes.TrimSuffix(line, []byte{'\r'}), io.ErrUnexpectedEOF
+	case err == bufio.ErrBufferFull:
+		return nil, fmt.Errorf("%w: line is too long", errMalformedChunk)
+	case err != nil:
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(line[:len(line)-1], []byte{'\r'}), nil
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkedReader(t *testing.T) {
+	const signature = "ad80c730a21e5b8d04586a2213dd63b9a0e99e0e2307b0ade35a65485a288648"
+
+	for _, tc := range []struct {
+		name    string
+		payload string
+		trailer map[string]string
+	}{
+		{
+			name: "signed",
+			payload: "5;chunk-signature=" + signature + "\r\nhello\r\n" +
+				"6;chunk-signature=" + signature + "\r\n world\r\n" +
+				"0;chunk-signature=" + signature + "\r\n\r\n",
+		},
+		{
+			name: "signed with trailer",
+			payload: "b;chunk-signature=" + signature + "\r\nhello world\r\n" +
+				"0;chunk-signature=" + signature + "\r\n" +
+				"x-amz-checksum-crc32c:yZRlqg==\r\n" +
+				"x-amz-trailer-signature:" + signature + "\r\n\r\n",
+			trailer: map[string]string{"x-amz-checksum-crc32c": "yZRlqg==", "x-amz-trailer-signature": signature},
+		},
+		{
+			name:    "unsigned with trailer without final CRLF",
+			payload: "B\r\nhello world\r\n0\r\nx-amz-checksum-crc32:DUoRhQ==\r\n",
+			trailer: map[string]string{"x-amz-checksum-crc32": "DUoRhQ=="},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewChunkedReader(strings.NewReader(tc.payload))
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, "hello world", string(data))
+			require.Len(t, r.Trailer(), len(tc.trailer))
+			for key, val := range tc.trailer {
+				require.Equal(t, val, r.Trailer().Get(key))
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name    string
+		payload string
+	}{
+		{name: "invalid size", payload: "x\r\nhello\r\n0\r\n\r\n"},
+		{name: "no delimiter", payload: "5\r\nhello world\r\n0\r\n\r\n"},
+		{name: "invalid trailer", payload: "5\r\nhello\r\n0\r\ntrailer\r\n\r\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := io.ReadAll(NewChunkedReader(strings.NewReader(tc.payload)))
+			require.ErrorIs(t, err, errMalformedChunk)
+		})
+	}
+
+	_, err := io.ReadAll(NewChunkedReader(strings.NewReader("b\r\nhello")))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
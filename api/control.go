@@ -0,0 +1,184 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// ControlState keeps the state of the gateway managed by the control service: maintenance mode,
+	// revoked credentials and request quotas of access keys. The state isn't persisted and is reset
+	// on restart of the gateway, credentials revoked in the configuration are revoked on start.
+	ControlState struct {
+		// maintenanceSince is unix time in nanoseconds since the gateway is in maintenance mode,
+		// zero if the gateway serves requests.
+		maintenanceSince int64
+
+		mu      sync.RWMutex
+		revoked map[string]struct{}
+		quotas  map[string]*quotaBucket
+	}
+
+	// quotaBucket is a token bucket which limits the request rate of the access key.
+	quotaBucket struct {
+		mu     sync.Mutex
+		rps    uint32
+		tokens float64
+		last   time.Time
+	}
+)
+
+// NewControlState returns the state of the gateway serving requests without quotas and revoked credentials.
+func NewControlState() *ControlState {
+	return &ControlState{
+		revoked: make(map[string]struct{}),
+		quotas:  make(map[string]*quotaBucket),
+	}
+}
+
+// SetMaintenance switches maintenance mode, it returns true if the mode is changed.
+func (c *ControlState) SetMaintenance(enabled bool) bool {
+	if !enabled {
+		return atomic.SwapInt64(&c.maintenanceSince, 0) != 0
+	}
+	return atomic.CompareAndSwapInt64(&c.maintenanceSince, 0, time.Now().UnixNano())
+}
+
+// Maintenance checks if the gateway is in maintenance mode and returns the time since the mode is enabled.
+func (c *ControlState) Maintenance() (bool, time.Time) {
+	since := atomic.LoadInt64(&c.maintenanceSince)
+	if since == 0 {
+		return false, time.Time{}
+	}
+	return true, time.Unix(0, since)
+}
+
+// SetRevoked revokes the credentials with the access key id or restores them.
+func (c *ControlState) SetRevoked(accessKeyID string, revoked bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if revoked {
+		c.revoked[accessKeyID] = struct{}{}
+	} else {
+		delete(c.revoked, accessKeyID)
+	}
+}
+
+// IsRevoked checks if the credentials with the access key id are revoked.
+func (c *ControlState) IsRevoked(accessKeyID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, revoked := c.revoked[accessKeyID]
+	return revoked
+}
+
+// Revoked returns sorted access key ids of the revoked credentials.
+func (c *ControlState) Revoked() []string {
+	c.mu.RLock()
+	res := make([]string, 0, len(c.revoked))
+	for accessKeyID := range c.revoked {
+		res = append(res, accessKeyID)
+	}
+	c.mu.RUnlock()
+
+	sort.Strings(res)
+	return res
+}
+
+// SetQuota limits the number of requests per second made with the access key id, zero removes the limit.
+func (c *ControlState) SetQuota(accessKeyID string, rps uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if rps == 0 {
+		delete(c.quotas, accessKeyID)
+		return
+	}
+	c.quotas[accessKeyID] = &quotaBucket{rps: rps, tokens: float64(rps), last: time.Now()}
+}
+
+// Quotas returns the number of requests per second allowed for access key ids with quotas.
+func (c *ControlState) Quotas() map[string]uint32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	res := make(map[string]uint32, len(c.quotas))
+	for accessKeyID, bucket := range c.quotas {
+		res[accessKeyID] = bucket.rps
+	}
+	return res
+}
+
+// check returns an error if the request made with the access key id must be rejected.
+func (c *ControlState) check(accessKeyID string, now time.Time) error {
+	if maintenance, _ := c.Maintenance(); maintenance {
+		return errors.GetAPIError(errors.ErrBusy)
+	}
+	if accessKeyID == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	_, revoked := c.revoked[accessKeyID]
+	bucket := c.quotas[accessKeyID]
+	c.mu.RUnlock()
+
+	if revoked {
+		return errors.GetAPIError(errors.ErrInvalidAccessKeyID)
+	}
+	if bucket != nil && !bucket.take(now) {
+		return errors.GetAPIError(errors.ErrSlowDown)
+	}
+	return nil
+}
+
+// take refills the bucket and consumes a token, it returns false if there are no tokens left.
+func (b *quotaBucket) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * float64(b.rps)
+		if b.tokens > float64(b.rps) {
+			b.tokens = float64(b.rps)
+		}
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Middleware rejects requests in maintenance mode, requests with the revoked credentials and requests
+// exceeding the quota of the access key.
+func (c *ControlState) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accessKeyID, _ := r.Context().Value(AccessKeyID).(string)
+		if err := c.check(accessKeyID, time.Now()); err != nil {
+			WriteErrorResponse(w, GetReqInfo(r.Context()), err)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// checkControlState returns middleware of the control state, nil state disables it.
+func checkControlState(state *ControlState) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if state == nil {
+			return h
+		}
+		return state.Middleware(h)
+	}
+}
@@ -0,0 +1,76 @@
+package data
+
+import "encoding/xml"
+
+const (
+	// AnalyticsSchemaV1 is the only output schema version of storage class analysis exports.
+	AnalyticsSchemaV1 = "V_1"
+	// AnalyticsFormatCSV is the only format of storage class analysis exports.
+	AnalyticsFormatCSV = "CSV"
+)
+
+type (
+	// AnalyticsConfiguration stores analytics configuration of a bucket.
+	AnalyticsConfiguration struct {
+		XMLName              xml.Name                      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AnalyticsConfiguration" json:"-"`
+		ID                   string                        `xml:"Id" json:"Id"`
+		Filter               *AnalyticsFilter              `xml:"Filter,omitempty" json:"Filter,omitempty"`
+		StorageClassAnalysis AnalyticsStorageClassAnalysis `xml:"StorageClassAnalysis" json:"StorageClassAnalysis"`
+	}
+
+	// AnalyticsConfigurations stores all analytics configurations of a bucket in a single system object.
+	AnalyticsConfigurations struct {
+		XMLName        xml.Name                 `xml:"AnalyticsConfigurations" json:"-"`
+		Configurations []AnalyticsConfiguration `xml:"AnalyticsConfiguration" json:"AnalyticsConfigurations"`
+	}
+
+	// AnalyticsFilter selects objects analyzed by the analytics configuration.
+	AnalyticsFilter struct {
+		Prefix string                `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tag    *LifecycleTag         `xml:"Tag,omitempty" json:"Tag,omitempty"`
+		And    *AnalyticsAndOperator `xml:"And,omitempty" json:"And,omitempty"`
+	}
+
+	// AnalyticsAndOperator combines several conditions of the analytics filter.
+	AnalyticsAndOperator struct {
+		Prefix string         `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tags   []LifecycleTag `xml:"Tag" json:"Tags"`
+	}
+
+	// AnalyticsStorageClassAnalysis sets where results of the storage class analysis are exported,
+	// the analysis without the export isn't written anywhere.
+	AnalyticsStorageClassAnalysis struct {
+		DataExport *AnalyticsDataExport `xml:"DataExport,omitempty" json:"DataExport,omitempty"`
+	}
+
+	// AnalyticsDataExport sets the schema and the destination of the storage class analysis export.
+	AnalyticsDataExport struct {
+		OutputSchemaVersion string                     `xml:"OutputSchemaVersion" json:"OutputSchemaVersion"`
+		Destination         AnalyticsExportDestination `xml:"Destination" json:"Destination"`
+	}
+
+	// AnalyticsExportDestination is a bucket the storage class analysis is exported to.
+	AnalyticsExportDestination struct {
+		S3BucketDestination AnalyticsS3BucketDestination `xml:"S3BucketDestination" json:"S3BucketDestination"`
+	}
+
+	// AnalyticsS3BucketDestination sets the bucket by ARN, the format and the key prefix of analysis exports.
+	AnalyticsS3BucketDestination struct {
+		BucketAccountID string `xml:"BucketAccountId,omitempty" json:"BucketAccountId,omitempty"`
+		Bucket          string `xml:"Bucket" json:"Bucket"`
+		Format          string `xml:"Format" json:"Format"`
+		Prefix          string `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+	}
+)
+
+// FilterPrefix returns the key prefix of objects the configuration analyzes.
+func (c *AnalyticsConfiguration) FilterPrefix() string {
+	switch {
+	case c.Filter == nil:
+		return ""
+	case c.Filter.And != nil:
+		return c.Filter.And.Prefix
+	default:
+		return c.Filter.Prefix
+	}
+}
@@ -2,6 +2,7 @@ package data
 
 import (
 	"encoding/xml"
+	"strings"
 	"time"
 
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
@@ -10,13 +11,66 @@ import (
 )
 
 const (
-	bktSettingsObject                  = ".s3-settings"
-	bktCORSConfigurationObject         = ".s3-cors"
-	bktNotificationConfigurationObject = ".s3-notifications"
+	// DefaultSystemObjectPrefix is the default prefix of names of bucket system objects.
+	DefaultSystemObjectPrefix = ".s3-"
+
+	bktSettingsObject                  = DefaultSystemObjectPrefix + "settings"
+	bktCORSConfigurationObject         = DefaultSystemObjectPrefix + "cors"
+	bktNotificationConfigurationObject = DefaultSystemObjectPrefix + "notifications"
+	bktConfigHistoryObject             = DefaultSystemObjectPrefix + "config-history"
+	bktPackIndexObject                 = DefaultSystemObjectPrefix + "packs"
+	bktLifecycleConfigurationObject    = DefaultSystemObjectPrefix + "lifecycle"
+	bktPolicyObject                    = DefaultSystemObjectPrefix + "policy"
+	bktWebsiteConfigurationObject      = DefaultSystemObjectPrefix + "website"
+	bktInventoryConfigurationObject    = DefaultSystemObjectPrefix + "inventory"
+	bktReplicationConfigurationObject  = DefaultSystemObjectPrefix + "replication"
+	bktAnalyticsConfigurationObject    = DefaultSystemObjectPrefix + "analytics"
+	bktMetricsConfigurationObject      = DefaultSystemObjectPrefix + "metrics"
 
 	VersioningUnversioned = "Unversioned"
 	VersioningEnabled     = "Enabled"
 	VersioningSuspended   = "Suspended"
+
+	// MFADeleteEnabled requires MFA to delete object versions and to change the versioning state of the bucket.
+	MFADeleteEnabled = "Enabled"
+	// MFADeleteDisabled allows deletion of object versions without MFA.
+	MFADeleteDisabled = "Disabled"
+
+	// ETagAlgorithmSHA256 is the default algorithm, ETag is SHA256 checksum of the object payload.
+	ETagAlgorithmSHA256 = "SHA256"
+	// ETagAlgorithmMD5 makes ETag MD5 checksum of the object payload as AWS S3 does for non-multipart objects.
+	ETagAlgorithmMD5 = "MD5"
+	// ETagAlgorithmCID makes ETag the NeoFS object ID.
+	ETagAlgorithmCID = "CID"
+
+	// NotificationQueuePolicyDrop is the default policy, events are dropped if the queue of the notification target is full.
+	NotificationQueuePolicyDrop = "drop"
+	// NotificationQueuePolicyPark makes the request wait for space in the full queue of the notification target
+	// up to the park timeout before the event is dropped.
+	NotificationQueuePolicyPark = "park"
+
+	// ObjectOwnershipObjectWriter is the default object ownership, the writer owns the object.
+	ObjectOwnershipObjectWriter = "ObjectWriter"
+	// ObjectOwnershipBucketOwnerPreferred makes the bucket owner own the objects written
+	// with bucket-owner-full-control canned ACL.
+	ObjectOwnershipBucketOwnerPreferred = "BucketOwnerPreferred"
+	// ObjectOwnershipBucketOwnerEnforced disables ACL, the bucket owner owns every object in the bucket.
+	ObjectOwnershipBucketOwnerEnforced = "BucketOwnerEnforced"
+
+	// RequestPayerBucketOwner is the default payer, the bucket owner pays for requests to the bucket.
+	RequestPayerBucketOwner = "BucketOwner"
+	// RequestPayerRequester makes the requester pay for requests to the bucket.
+	RequestPayerRequester = "Requester"
+
+	// AccelerateStatusEnabled allows requests to the bucket through accelerated endpoints.
+	AccelerateStatusEnabled = "Enabled"
+	// AccelerateStatusSuspended denies requests to the bucket through accelerated endpoints.
+	AccelerateStatusSuspended = "Suspended"
+
+	// StorageClassStandard is a storage class of objects stored in the bucket container.
+	StorageClassStandard = "STANDARD"
+	// AttributeStorageClass is an attribute of NeoFS objects put with the storage class other than STANDARD.
+	AttributeStorageClass = "S3-Storage-Class"
 )
 
 type (
@@ -32,6 +86,7 @@ type (
 
 	// ObjectInfo holds S3 object data.
 	ObjectInfo struct {
+		// ID is an id of NeoFS object with the payload, it's the pack object for packed objects.
 		ID             oid.ID
 		CID            cid.ID
 		IsDir          bool
@@ -45,6 +100,21 @@ type (
 		HashSum     string
 		Owner       user.ID
 		Headers     map[string]string
+		// FilePath is the name the object was created with, it differs from Name after RenameObject.
+		FilePath string
+
+		// Pack is set if the object payload is stored in the pack object.
+		Pack *PackInfo
+		// Replica is set if the object is copied to the secondary container, it's read
+		// if the object can't be read from the bucket container.
+		Replica *ReplicaInfo
+		// ReplicationStatus is a state of the bucket replication of the object version.
+		ReplicationStatus string
+		// Archive is set if the object is transitioned to the archive storage class, its payload
+		// can be read only from the restored copy.
+		Archive *ArchiveInfo
+		// Restore is a state of the restored copy of the archived object, nil if restore isn't requested.
+		Restore *RestoreInfo
 	}
 
 	// NotificationInfo store info to send s3 notification.
@@ -57,8 +127,51 @@ type (
 
 	// BucketSettings stores settings such as versioning.
 	BucketSettings struct {
-		Versioning        string                   `json:"versioning"`
+		Versioning string `json:"versioning"`
+		// MFADelete is a state of MFA delete of the versioned bucket, empty value means MFA delete is never configured.
+		MFADelete         string                   `json:"mfa_delete,omitempty"`
 		LockConfiguration *ObjectLockConfiguration `json:"lock_configuration"`
+		TrashRetention    time.Duration            `json:"trash_retention,omitempty"`
+		// ETagAlgorithm is a source of ETag of new objects, empty value means ETagAlgorithmSHA256.
+		ETagAlgorithm string `json:"etag_algorithm,omitempty"`
+		// NotificationQueuePolicy is a policy of events sent to full queues of notification targets,
+		// empty value means NotificationQueuePolicyDrop.
+		NotificationQueuePolicy string `json:"notification_queue_policy,omitempty"`
+		// SyncReplication makes every new object be stored in the secondary container too, nil disables it.
+		SyncReplication *SyncReplication `json:"sync_replication,omitempty"`
+		// PublicAccessBlock restricts public access to the bucket, nil means the configuration isn't set.
+		PublicAccessBlock *PublicAccessBlockConfiguration `json:"public_access_block,omitempty"`
+		// ObjectOwnership is an owner of new objects of the bucket, empty value means ownership controls aren't set
+		// and ObjectOwnershipObjectWriter is used.
+		ObjectOwnership string `json:"object_ownership,omitempty"`
+		// RequestPayer is a payer of requests to the bucket, empty value means RequestPayerBucketOwner.
+		RequestPayer string `json:"request_payer,omitempty"`
+		// AccelerateStatus is a status of transfer acceleration, empty value means acceleration is never configured.
+		AccelerateStatus string `json:"accelerate_status,omitempty"`
+		// Features overrides values of feature flags of the deployment for the bucket.
+		Features map[string]bool `json:"features,omitempty"`
+		// UploadValidation is a set of rules new objects of the bucket must pass, nil disables validation.
+		UploadValidation *UploadValidation `json:"upload_validation,omitempty"`
+		// ReadOnly rejects all requests modifying the bucket, its objects and configuration.
+		ReadOnly bool `json:"read_only,omitempty"`
+		// Frozen rejects requests deleting or overwriting objects of the bucket and stops their expiration.
+		Frozen bool `json:"frozen,omitempty"`
+	}
+
+	// SyncReplication is a configuration of synchronous replication of bucket objects.
+	SyncReplication struct {
+		// Network is a name of the NeoFS network of the secondary container, empty value means the bucket network.
+		Network   string `json:"network,omitempty"`
+		Container cid.ID `json:"container"`
+	}
+
+	// PublicAccessBlockConfiguration stores public access block configuration of a bucket.
+	PublicAccessBlockConfiguration struct {
+		XMLName               xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PublicAccessBlockConfiguration" json:"-"`
+		BlockPublicAcls       bool     `xml:"BlockPublicAcls" json:"BlockPublicAcls"`
+		IgnorePublicAcls      bool     `xml:"IgnorePublicAcls" json:"IgnorePublicAcls"`
+		BlockPublicPolicy     bool     `xml:"BlockPublicPolicy" json:"BlockPublicPolicy"`
+		RestrictPublicBuckets bool     `xml:"RestrictPublicBuckets" json:"RestrictPublicBuckets"`
 	}
 
 	// CORSConfiguration stores CORS configuration of a request.
@@ -76,6 +189,16 @@ type (
 		ExposeHeaders  []string `xml:"ExposeHeader" json:"ExposeHeaders"`
 		MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty" json:"MaxAgeSeconds,omitempty"`
 	}
+
+	// ConfigChange stores info about a change of bucket configuration.
+	ConfigChange struct {
+		Type          string    `json:"type"`
+		Owner         string    `json:"owner"`
+		Time          time.Time `json:"time"`
+		PreviousValue string    `json:"previous_value,omitempty"`
+		// Value is the configuration after the change, it's saved for bucket policy only.
+		Value string `json:"value,omitempty"`
+	}
 )
 
 // NotificationInfoFromObject creates new NotificationInfo from ObjectInfo.
@@ -88,6 +211,35 @@ func NotificationInfoFromObject(objInfo *ObjectInfo) *NotificationInfo {
 	}
 }
 
+// systemObjectNames are names of bucket system objects with the default prefix.
+var systemObjectNames = []string{
+	bktSettingsObject,
+	bktCORSConfigurationObject,
+	bktNotificationConfigurationObject,
+	bktConfigHistoryObject,
+	bktPackIndexObject,
+	bktLifecycleConfigurationObject,
+	bktPolicyObject,
+	bktWebsiteConfigurationObject,
+	bktInventoryConfigurationObject,
+	bktReplicationConfigurationObject,
+	bktAnalyticsConfigurationObject,
+	bktMetricsConfigurationObject,
+}
+
+// IsSystemObjectName checks if the name is a name of a bucket system object with the prefix instead of the default one.
+func IsSystemObjectName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	for _, sysName := range systemObjectNames {
+		if name[len(prefix):] == sysName[len(DefaultSystemObjectPrefix):] {
+			return true
+		}
+	}
+	return false
+}
+
 // SettingsObjectName is a system name for a bucket settings file.
 func (b *BucketInfo) SettingsObjectName() string { return bktSettingsObject }
 
@@ -98,8 +250,65 @@ func (b *BucketInfo) NotificationConfigurationObjectName() string {
 	return bktNotificationConfigurationObject
 }
 
+// ConfigHistoryObjectName returns a system name for a bucket configuration history file.
+func (b *BucketInfo) ConfigHistoryObjectName() string { return bktConfigHistoryObject }
+
+// LifecycleConfigurationObjectName returns a system name for a bucket lifecycle configuration file.
+func (b *BucketInfo) LifecycleConfigurationObjectName() string {
+	return bktLifecycleConfigurationObject
+}
+
+// PolicyObjectName returns a system name for a bucket policy file.
+func (b *BucketInfo) PolicyObjectName() string { return bktPolicyObject }
+
+// WebsiteConfigurationObjectName returns a system name for a bucket website configuration file.
+func (b *BucketInfo) WebsiteConfigurationObjectName() string { return bktWebsiteConfigurationObject }
+
+// InventoryConfigurationObjectName returns a system name for a bucket inventory configurations file.
+func (b *BucketInfo) InventoryConfigurationObjectName() string {
+	return bktInventoryConfigurationObject
+}
+
+// ReplicationConfigurationObjectName returns a system name for a bucket replication configuration file.
+func (b *BucketInfo) ReplicationConfigurationObjectName() string {
+	return bktReplicationConfigurationObject
+}
+
+// AnalyticsConfigurationObjectName returns a system name for a bucket analytics configurations file.
+func (b *BucketInfo) AnalyticsConfigurationObjectName() string {
+	return bktAnalyticsConfigurationObject
+}
+
+// MetricsConfigurationObjectName returns a system name for a bucket metrics configurations file.
+func (b *BucketInfo) MetricsConfigurationObjectName() string {
+	return bktMetricsConfigurationObject
+}
+
+// PackIndexObjectName returns a system name for a bucket pack index file.
+func (b *BucketInfo) PackIndexObjectName() string { return bktPackIndexObject }
+
 // VersionID returns object version from ObjectInfo.
-func (o *ObjectInfo) VersionID() string { return o.ID.EncodeToString() }
+func (o *ObjectInfo) VersionID() string { return o.VersionOID().EncodeToString() }
+
+// VersionOID returns id of the object version. It's the id of NeoFS object with the payload
+// except packed objects, which keep the id of the original object.
+func (o *ObjectInfo) VersionOID() oid.ID {
+	if o.Pack != nil {
+		return o.Pack.VersionOID
+	}
+	return o.ID
+}
+
+// StorageClass returns the storage class of the object.
+func (o *ObjectInfo) StorageClass() string {
+	if o.Archive != nil {
+		return o.Archive.StorageClass
+	}
+	if class := o.Headers[AttributeStorageClass]; class != "" {
+		return class
+	}
+	return StorageClassStandard
+}
 
 // NiceName returns object name for cache.
 func (o *ObjectInfo) NiceName() string { return o.Bucket + "/" + o.Name }
@@ -108,7 +317,7 @@ func (o *ObjectInfo) NiceName() string { return o.Bucket + "/" + o.Name }
 func (o *ObjectInfo) Address() oid.Address {
 	var addr oid.Address
 	addr.SetContainer(o.CID)
-	addr.SetObject(o.ID)
+	addr.SetObject(o.VersionOID())
 
 	return addr
 }
@@ -117,6 +326,11 @@ func (b BucketSettings) Unversioned() bool {
 	return b.Versioning == VersioningUnversioned
 }
 
+// TrashEnabled checks if deleted objects of unversioned bucket must be moved to the bucket trash.
+func (b BucketSettings) TrashEnabled() bool {
+	return b.Unversioned() && b.TrashRetention > 0
+}
+
 func (b BucketSettings) VersioningEnabled() bool {
 	return b.Versioning == VersioningEnabled
 }
@@ -124,3 +338,18 @@ func (b BucketSettings) VersioningEnabled() bool {
 func (b BucketSettings) VersioningSuspended() bool {
 	return b.Versioning == VersioningSuspended
 }
+
+// MFADeleteEnabled checks if deletion of object versions requires MFA.
+func (b BucketSettings) MFADeleteEnabled() bool {
+	return b.MFADelete == MFADeleteEnabled
+}
+
+// RequesterPays checks if requesters pay for requests to the bucket.
+func (b BucketSettings) RequesterPays() bool {
+	return b.RequestPayer == RequestPayerRequester
+}
+
+// AccelerateEnabled checks if the bucket can be accessed through accelerated endpoints.
+func (b BucketSettings) AccelerateEnabled() bool {
+	return b.AccelerateStatus == AccelerateStatusEnabled
+}
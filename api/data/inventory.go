@@ -0,0 +1,76 @@
+package data
+
+import "encoding/xml"
+
+const (
+	// InventoryFormatCSV is a format of gzipped CSV inventory reports.
+	InventoryFormatCSV = "CSV"
+	// InventoryFormatParquet is a format of Parquet inventory reports.
+	InventoryFormatParquet = "Parquet"
+	// InventoryFormatORC is a format of ORC inventory reports, it's not supported.
+	InventoryFormatORC = "ORC"
+
+	// InventoryFrequencyDaily makes inventory reports generated every day.
+	InventoryFrequencyDaily = "Daily"
+	// InventoryFrequencyWeekly makes inventory reports generated every week starting on Sunday.
+	InventoryFrequencyWeekly = "Weekly"
+
+	// InventoryVersionsAll makes inventory reports include all object versions.
+	InventoryVersionsAll = "All"
+	// InventoryVersionsCurrent makes inventory reports include the latest object versions only.
+	InventoryVersionsCurrent = "Current"
+
+	// Optional fields of inventory reports.
+	InventoryFieldSize             = "Size"
+	InventoryFieldLastModifiedDate = "LastModifiedDate"
+	InventoryFieldETag             = "ETag"
+	InventoryFieldStorageClass     = "StorageClass"
+)
+
+type (
+	// InventoryConfiguration stores inventory configuration of a bucket.
+	InventoryConfiguration struct {
+		XMLName                xml.Name                 `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InventoryConfiguration" json:"-"`
+		Destination            InventoryDestination     `xml:"Destination" json:"Destination"`
+		IsEnabled              bool                     `xml:"IsEnabled" json:"IsEnabled"`
+		Filter                 *InventoryFilter         `xml:"Filter,omitempty" json:"Filter,omitempty"`
+		ID                     string                   `xml:"Id" json:"Id"`
+		IncludedObjectVersions string                   `xml:"IncludedObjectVersions" json:"IncludedObjectVersions"`
+		OptionalFields         *InventoryOptionalFields `xml:"OptionalFields,omitempty" json:"OptionalFields,omitempty"`
+		Schedule               InventorySchedule        `xml:"Schedule" json:"Schedule"`
+	}
+
+	// InventoryConfigurations stores all inventory configurations of a bucket in a single system object.
+	InventoryConfigurations struct {
+		XMLName        xml.Name                 `xml:"InventoryConfigurations" json:"-"`
+		Configurations []InventoryConfiguration `xml:"InventoryConfiguration" json:"InventoryConfigurations"`
+	}
+
+	// InventoryDestination is a bucket inventory reports are written to.
+	InventoryDestination struct {
+		S3BucketDestination InventoryS3BucketDestination `xml:"S3BucketDestination" json:"S3BucketDestination"`
+	}
+
+	// InventoryS3BucketDestination sets the bucket by ARN, the format and the key prefix of inventory reports.
+	InventoryS3BucketDestination struct {
+		AccountID string `xml:"AccountId,omitempty" json:"AccountId,omitempty"`
+		Bucket    string `xml:"Bucket" json:"Bucket"`
+		Format    string `xml:"Format" json:"Format"`
+		Prefix    string `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+	}
+
+	// InventoryFilter selects objects included in inventory reports.
+	InventoryFilter struct {
+		Prefix string `xml:"Prefix" json:"Prefix"`
+	}
+
+	// InventoryOptionalFields are fields of objects included in inventory reports besides the bucket and the key.
+	InventoryOptionalFields struct {
+		Fields []string `xml:"Field" json:"Fields"`
+	}
+
+	// InventorySchedule sets how often inventory reports are generated.
+	InventorySchedule struct {
+		Frequency string `xml:"Frequency" json:"Frequency"`
+	}
+)
@@ -0,0 +1,68 @@
+package data
+
+import "encoding/xml"
+
+const (
+	// LifecycleStatusEnabled makes the lifecycle rule applied.
+	LifecycleStatusEnabled = "Enabled"
+	// LifecycleStatusDisabled makes the lifecycle rule ignored.
+	LifecycleStatusDisabled = "Disabled"
+)
+
+type (
+	// LifecycleConfiguration stores lifecycle configuration of a bucket.
+	LifecycleConfiguration struct {
+		XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LifecycleConfiguration" json:"-"`
+		Rules   []LifecycleRule `xml:"Rule" json:"Rules"`
+	}
+
+	// LifecycleRule stores the expiration and transition rule of objects.
+	LifecycleRule struct {
+		ID     string           `xml:"ID,omitempty" json:"ID,omitempty"`
+		Status string           `xml:"Status" json:"Status"`
+		Filter *LifecycleFilter `xml:"Filter,omitempty" json:"Filter,omitempty"`
+		// Prefix is a deprecated filter of objects by the key prefix, Filter is used instead.
+		Prefix     string               `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Expiration *LifecycleExpiration `xml:"Expiration,omitempty" json:"Expiration,omitempty"`
+		// Transitions move objects to archive storage classes.
+		Transitions []LifecycleTransition `xml:"Transition" json:"Transitions,omitempty"`
+
+		// Not supported actions.
+		NoncurrentVersionExpiration    *struct{}  `xml:"NoncurrentVersionExpiration" json:"-"`
+		NoncurrentVersionTransitions   []struct{} `xml:"NoncurrentVersionTransition" json:"-"`
+		AbortIncompleteMultipartUpload *struct{}  `xml:"AbortIncompleteMultipartUpload" json:"-"`
+	}
+
+	// LifecycleFilter selects objects the lifecycle rule applies to.
+	LifecycleFilter struct {
+		Prefix string                `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tag    *LifecycleTag         `xml:"Tag,omitempty" json:"Tag,omitempty"`
+		And    *LifecycleAndOperator `xml:"And,omitempty" json:"And,omitempty"`
+	}
+
+	// LifecycleAndOperator combines the prefix and tags filters.
+	LifecycleAndOperator struct {
+		Prefix string         `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tags   []LifecycleTag `xml:"Tag" json:"Tags,omitempty"`
+	}
+
+	// LifecycleTag is an object tag the lifecycle rule filter matches.
+	LifecycleTag struct {
+		Key   string `xml:"Key" json:"Key"`
+		Value string `xml:"Value" json:"Value"`
+	}
+
+	// LifecycleExpiration sets when objects expire: in Days after creation or at the Date (ISO 8601, midnight UTC).
+	LifecycleExpiration struct {
+		Days int    `xml:"Days,omitempty" json:"Days,omitempty"`
+		Date string `xml:"Date,omitempty" json:"Date,omitempty"`
+	}
+
+	// LifecycleTransition sets when objects are moved to the storage class: in Days after creation
+	// or at the Date (ISO 8601, midnight UTC).
+	LifecycleTransition struct {
+		Days         int    `xml:"Days,omitempty" json:"Days,omitempty"`
+		Date         string `xml:"Date,omitempty" json:"Date,omitempty"`
+		StorageClass string `xml:"StorageClass" json:"StorageClass"`
+	}
+)
@@ -0,0 +1,46 @@
+package data
+
+import "encoding/xml"
+
+type (
+	// MetricsConfiguration stores request metrics configuration of a bucket.
+	MetricsConfiguration struct {
+		XMLName xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ MetricsConfiguration" json:"-"`
+		ID      string         `xml:"Id" json:"Id"`
+		Filter  *MetricsFilter `xml:"Filter,omitempty" json:"Filter,omitempty"`
+	}
+
+	// MetricsConfigurations stores all metrics configurations of a bucket in a single system object.
+	MetricsConfigurations struct {
+		XMLName        xml.Name               `xml:"MetricsConfigurations" json:"-"`
+		Configurations []MetricsConfiguration `xml:"MetricsConfiguration" json:"MetricsConfigurations"`
+	}
+
+	// MetricsFilter selects requests counted by the metrics configuration,
+	// the configuration without the filter counts all requests to the bucket.
+	MetricsFilter struct {
+		Prefix         string              `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tag            *LifecycleTag       `xml:"Tag,omitempty" json:"Tag,omitempty"`
+		AccessPointArn string              `xml:"AccessPointArn,omitempty" json:"AccessPointArn,omitempty"`
+		And            *MetricsAndOperator `xml:"And,omitempty" json:"And,omitempty"`
+	}
+
+	// MetricsAndOperator combines several conditions of the metrics filter.
+	MetricsAndOperator struct {
+		Prefix         string         `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tags           []LifecycleTag `xml:"Tag" json:"Tags"`
+		AccessPointArn string         `xml:"AccessPointArn,omitempty" json:"AccessPointArn,omitempty"`
+	}
+)
+
+// FilterPrefix returns the key prefix of objects the configuration counts requests to.
+func (c *MetricsConfiguration) FilterPrefix() string {
+	switch {
+	case c.Filter == nil:
+		return ""
+	case c.Filter.And != nil:
+		return c.Filter.And.Prefix
+	default:
+		return c.Filter.Prefix
+	}
+}
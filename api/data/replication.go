@@ -0,0 +1,102 @@
+package data
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const (
+	// ReplicationRuleEnabled is a status of the replication rule and of the delete marker replication.
+	ReplicationRuleEnabled = "Enabled"
+	// ReplicationRuleDisabled is a status of the disabled replication rule and of the delete marker replication.
+	ReplicationRuleDisabled = "Disabled"
+
+	// ReplicationStatusPending is a state of the object version waiting for the replication.
+	ReplicationStatusPending = "PENDING"
+	// ReplicationStatusCompleted is a state of the object version copied to the destination bucket.
+	ReplicationStatusCompleted = "COMPLETED"
+	// ReplicationStatusFailed is a state of the object version which couldn't be copied to the destination bucket.
+	ReplicationStatusFailed = "FAILED"
+	// ReplicationStatusReplica is a state of the object version created by the replication.
+	ReplicationStatusReplica = "REPLICA"
+)
+
+type (
+	// ReplicationConfiguration stores replication configuration of a bucket.
+	ReplicationConfiguration struct {
+		XMLName xml.Name          `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ReplicationConfiguration" json:"-"`
+		Role    string            `xml:"Role" json:"Role"`
+		Rules   []ReplicationRule `xml:"Rule" json:"Rules"`
+	}
+
+	// ReplicationRule describes objects copied to the destination bucket.
+	ReplicationRule struct {
+		ID       string             `xml:"ID,omitempty" json:"ID,omitempty"`
+		Priority int                `xml:"Priority,omitempty" json:"Priority,omitempty"`
+		Status   string             `xml:"Status" json:"Status"`
+		Filter   *ReplicationFilter `xml:"Filter,omitempty" json:"Filter,omitempty"`
+		// Prefix is a filter of the rule in the first version of the configuration schema.
+		Prefix                  string                   `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Destination             ReplicationDestination   `xml:"Destination" json:"Destination"`
+		DeleteMarkerReplication *DeleteMarkerReplication `xml:"DeleteMarkerReplication,omitempty" json:"DeleteMarkerReplication,omitempty"`
+	}
+
+	// ReplicationFilter selects objects copied by the replication rule.
+	ReplicationFilter struct {
+		Prefix string                  `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tag    *LifecycleTag           `xml:"Tag,omitempty" json:"Tag,omitempty"`
+		And    *ReplicationAndOperator `xml:"And,omitempty" json:"And,omitempty"`
+	}
+
+	// ReplicationAndOperator combines several conditions of the replication filter.
+	ReplicationAndOperator struct {
+		Prefix string         `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+		Tags   []LifecycleTag `xml:"Tag" json:"Tags"`
+	}
+
+	// ReplicationDestination is a bucket the objects are copied to.
+	ReplicationDestination struct {
+		Bucket       string `xml:"Bucket" json:"Bucket"`
+		Account      string `xml:"Account,omitempty" json:"Account,omitempty"`
+		StorageClass string `xml:"StorageClass,omitempty" json:"StorageClass,omitempty"`
+	}
+
+	// DeleteMarkerReplication specifies whether delete markers are copied to the destination bucket.
+	DeleteMarkerReplication struct {
+		Status string `xml:"Status" json:"Status"`
+	}
+)
+
+// FilterPrefix returns the key prefix of objects the rule copies.
+func (r *ReplicationRule) FilterPrefix() string {
+	switch {
+	case r.Filter == nil:
+		return r.Prefix
+	case r.Filter.And != nil:
+		return r.Filter.And.Prefix
+	default:
+		return r.Filter.Prefix
+	}
+}
+
+// DeleteMarkersReplicated returns true if the rule copies delete markers.
+func (r *ReplicationRule) DeleteMarkersReplicated() bool {
+	return r.DeleteMarkerReplication != nil && r.DeleteMarkerReplication.Status == ReplicationRuleEnabled
+}
+
+// MatchRule returns the enabled rule with the highest priority which copies the object with the key,
+// nil is returned if there is no such rule.
+func (c *ReplicationConfiguration) MatchRule(key string) *ReplicationRule {
+	var matched *ReplicationRule
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Status != ReplicationRuleEnabled || !strings.HasPrefix(key, rule.FilterPrefix()) {
+			continue
+		}
+		if matched == nil || rule.Priority > matched.Priority {
+			matched = rule
+		}
+	}
+
+	return matched
+}
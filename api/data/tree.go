@@ -18,6 +18,21 @@ type NodeVersion struct {
 	BaseNodeVersion
 	DeleteMarker  *DeleteMarkerInfo
 	IsUnversioned bool
+	Pack          *PackInfo
+	// Replica is set if the object is copied to the secondary container by synchronous replication.
+	Replica *ReplicaInfo
+	// Metadata is an encoded user metadata and content type of the object updated without
+	// re-storing of the payload. It overrides headers of the NeoFS object.
+	Metadata string
+	// ReplicationStatus is a state of the bucket replication set on the version creation,
+	// the state updated by the replication is stored separately.
+	ReplicationStatus string
+	// Archive is set if the object is transitioned to the archive storage class.
+	Archive *ArchiveInfo
+	// ChecksumAlgorithm and Checksum are set if the payload checksum is sent in the trailer of the request,
+	// so it can't be saved in the header of the NeoFS object, which is sent before the payload.
+	ChecksumAlgorithm string
+	Checksum          string
 }
 
 func (v NodeVersion) IsDeleteMarker() bool {
@@ -31,6 +46,130 @@ type DeleteMarkerInfo struct {
 	Owner   user.ID
 }
 
+// PackInfo is used to save location of the small object packed into a bigger NeoFS object.
+// The original object is no longer stored in NeoFS, so its headers are saved in Meta.
+type PackInfo struct {
+	// OID is an id of the pack object.
+	OID oid.ID
+	// Offset is an offset of the object payload in the pack object payload.
+	Offset uint64
+	// Meta is an encoded headers of the original object.
+	Meta string
+	// VersionOID is an id of the original object, which is kept as the version id of the packed object.
+	// It isn't stored in the tree node, because the node keeps it as the object id.
+	VersionOID oid.ID
+}
+
+// ReplicaInfo is used to save location of the object copy stored by synchronous replication.
+type ReplicaInfo struct {
+	// Network is a name of the NeoFS network of the replica, empty name means the bucket network.
+	Network string
+	// Container is an id of the secondary container.
+	Container cid.ID
+	// OID is an id of the replica object.
+	OID oid.ID
+}
+
+// ArchiveInfo is used to save location of the object transitioned to the archive storage class.
+// The object is deleted from the bucket container, its id is kept as the version id.
+type ArchiveInfo struct {
+	// StorageClass is a name of the archive storage class.
+	StorageClass string
+	// Container is an id of the archive container.
+	Container cid.ID
+	// OID is an id of the archived object.
+	OID oid.ID
+}
+
+// RestoreInfo is a state of the temporary copy of the archived object restored to the bucket container.
+// The state is stored apart from the version node, so restore of any version doesn't change the latest one.
+type RestoreInfo struct {
+	// Ongoing is set until the object is copied to the bucket container.
+	Ongoing bool
+	// Days is a number of days the restored copy is kept.
+	Days int
+	// OID is an id of the restored copy, it's empty while the restore is ongoing.
+	OID oid.ID
+	// Expiry is a time when the restored copy is deleted, it's zero while the restore is ongoing.
+	Expiry time.Time
+}
+
+// Restored checks if the restored copy of the archived object can be read.
+func (r *RestoreInfo) Restored() bool {
+	return r != nil && !r.Ongoing
+}
+
+// TrashVersion is an object version moved to the bucket trash instead of deletion.
+// Trash is stored apart from object versions, so it doesn't affect object keys and listings.
+type TrashVersion struct {
+	// ID is an id of the trash node.
+	ID uint64
+	// Version is the object version, its FilePath is the original key of the object.
+	Version *NodeVersion
+	// Deleted is a time when the object was moved to the trash.
+	Deleted time.Time
+	// Tags is a tag set of the object version.
+	Tags map[string]string
+}
+
+// Name returns an identifier of the object in the bucket trash.
+// Object ID makes the name unique even if the same key is deleted several times at the same moment.
+func (t *TrashVersion) Name() string {
+	return strconv.FormatInt(t.Deleted.UnixMilli(), 10) + "-" + t.Version.OID.EncodeToString()
+}
+
+// MaxVersionsShards is the max number of shards the version tree keys with the prefix are split into.
+const MaxVersionsShards = 256
+
+// VersionsShard is a range of the version tree keys with first-level names after prefix from First to Last.
+// Shards don't intersect and all versions of an object belong to the same shard, so shards can be fetched
+// concurrently.
+type VersionsShard struct {
+	// Prefix is a path of the parent of shard root nodes.
+	Prefix string
+	// First and Last are the first and the last file names of shard root nodes.
+	First, Last string
+	// NodeIDs are ids of shard root nodes: intermediate nodes and object versions with names in the range.
+	NodeIDs []uint64
+}
+
+// Contains checks whether the first-level name after the shard prefix is in the range of the shard.
+func (s *VersionsShard) Contains(name string) bool {
+	return s.First <= name && name <= s.Last
+}
+
+// SplitVersionsShards splits sorted first-level names after the prefix into at most MaxVersionsShards ranges
+// with the same number of distinct names, equal names are put into the same range. It returns the shards
+// without node ids and the index of the shard of every name.
+func SplitVersionsShards(prefix string, names []string) ([]*VersionsShard, []int) {
+	var distinct int
+	for i := range names {
+		if i == 0 || names[i] != names[i-1] {
+			distinct++
+		}
+	}
+	shardSize := (distinct + MaxVersionsShards - 1) / MaxVersionsShards
+
+	var (
+		shards  []*VersionsShard
+		indexes = make([]int, len(names))
+		inShard int
+	)
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			if len(shards) == 0 || inShard == shardSize {
+				shards = append(shards, &VersionsShard{Prefix: prefix, First: name})
+				inShard = 0
+			}
+			inShard++
+			shards[len(shards)-1].Last = name
+		}
+		indexes[i] = len(shards) - 1
+	}
+
+	return shards, indexes
+}
+
 // ExtendedObjectInfo contains additional node info to be able to sort versions by timestamp.
 type ExtendedObjectInfo struct {
 	ObjectInfo  *ObjectInfo
@@ -43,7 +182,7 @@ func (e ExtendedObjectInfo) Version() string {
 		return UnversionedObjectVersionID
 	}
 
-	return e.ObjectInfo.ID.EncodeToString()
+	return e.ObjectInfo.VersionID()
 }
 
 // BaseNodeVersion is minimal node info from tree service.
@@ -86,6 +225,9 @@ type PartInfo struct {
 	Size     int64
 	ETag     string
 	Created  time.Time
+	// ChecksumAlgorithm and Checksum (base64-encoded) are set if the part checksum was requested.
+	ChecksumAlgorithm string
+	Checksum          string
 }
 
 // ToHeaderString form short part representation to use in S3-Completed-Parts header.
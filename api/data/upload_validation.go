@@ -0,0 +1,100 @@
+package data
+
+import (
+	"fmt"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// UploadValidation is a set of rules checked on uploads of new objects to the bucket.
+type UploadValidation struct {
+	// ContentTypes are allowed media types of objects, "type/*" allows all subtypes of the type.
+	// Empty list allows any content type.
+	ContentTypes []string `json:"content_types,omitempty"`
+	// MaxKeyLength is a max length of object keys in bytes, zero means no limit.
+	MaxKeyLength int `json:"max_key_length,omitempty"`
+	// FilenamePattern is a regular expression the last segment of object keys must match, empty value allows any name.
+	FilenamePattern string `json:"filename_pattern,omitempty"`
+	// RequiredMetadata are keys of user metadata every object must have.
+	RequiredMetadata []string `json:"required_metadata,omitempty"`
+}
+
+// Validate checks the rules are well-formed.
+func (v *UploadValidation) Validate() error {
+	for _, contentType := range v.ContentTypes {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil || !strings.Contains(contentType, "/") {
+			return fmt.Errorf("invalid content type '%s'", contentType)
+		}
+	}
+	if v.MaxKeyLength < 0 {
+		return fmt.Errorf("negative max key length %d", v.MaxKeyLength)
+	}
+	if _, err := regexp.Compile(v.FilenamePattern); err != nil {
+		return fmt.Errorf("invalid filename pattern: %w", err)
+	}
+	for _, key := range v.RequiredMetadata {
+		if len(key) == 0 {
+			return fmt.Errorf("empty required metadata key")
+		}
+	}
+	return nil
+}
+
+// Check returns an error describing the first rule violated by the new object with the key,
+// the content type and the user metadata.
+func (v *UploadValidation) Check(key, contentType string, metadata map[string]string) error {
+	if v.MaxKeyLength > 0 && len(key) > v.MaxKeyLength {
+		return fmt.Errorf("object key is longer than %d bytes", v.MaxKeyLength)
+	}
+
+	if len(v.FilenamePattern) != 0 {
+		re, err := regexp.Compile(v.FilenamePattern)
+		if err != nil {
+			return fmt.Errorf("invalid filename pattern: %w", err)
+		}
+		if filename := path.Base(key); !re.MatchString(filename) {
+			return fmt.Errorf("filename '%s' doesn't match pattern '%s'", filename, v.FilenamePattern)
+		}
+	}
+
+	if len(v.ContentTypes) != 0 && !v.contentTypeAllowed(contentType) {
+		return fmt.Errorf("content type '%s' is not allowed, allowed types: %s", contentType, strings.Join(v.ContentTypes, ", "))
+	}
+
+	for _, required := range v.RequiredMetadata {
+		if !hasMetadataKey(metadata, required) {
+			return fmt.Errorf("required metadata key '%s' is missing", required)
+		}
+	}
+
+	return nil
+}
+
+func (v *UploadValidation) contentTypeAllowed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range v.ContentTypes {
+		if allowed, _, err = mime.ParseMediaType(allowed); err != nil {
+			continue
+		}
+		if allowed == mediaType || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMetadataKey checks metadata keys case-insensitively since they are HTTP header names.
+func hasMetadataKey(metadata map[string]string, key string) bool {
+	for k := range metadata {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
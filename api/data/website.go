@@ -0,0 +1,52 @@
+package data
+
+import "encoding/xml"
+
+type (
+	// WebsiteConfiguration stores website hosting configuration of a bucket.
+	WebsiteConfiguration struct {
+		XMLName               xml.Name                      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ WebsiteConfiguration" json:"-"`
+		RedirectAllRequestsTo *WebsiteRedirectAllRequestsTo `xml:"RedirectAllRequestsTo,omitempty" json:"RedirectAllRequestsTo,omitempty"`
+		IndexDocument         *WebsiteIndexDocument         `xml:"IndexDocument,omitempty" json:"IndexDocument,omitempty"`
+		ErrorDocument         *WebsiteErrorDocument         `xml:"ErrorDocument,omitempty" json:"ErrorDocument,omitempty"`
+		RoutingRules          []WebsiteRoutingRule          `xml:"RoutingRules>RoutingRule,omitempty" json:"RoutingRules,omitempty"`
+	}
+
+	// WebsiteRedirectAllRequestsTo redirects all requests to the website endpoint of the bucket to another host.
+	WebsiteRedirectAllRequestsTo struct {
+		HostName string `xml:"HostName" json:"HostName"`
+		Protocol string `xml:"Protocol,omitempty" json:"Protocol,omitempty"`
+	}
+
+	// WebsiteIndexDocument is a suffix appended to requests for a directory.
+	WebsiteIndexDocument struct {
+		Suffix string `xml:"Suffix" json:"Suffix"`
+	}
+
+	// WebsiteErrorDocument is a key of the object returned when a 4XX error occurs.
+	WebsiteErrorDocument struct {
+		Key string `xml:"Key" json:"Key"`
+	}
+
+	// WebsiteRoutingRule redirects requests matching the condition, the rule without condition matches all requests.
+	WebsiteRoutingRule struct {
+		Condition *WebsiteCondition `xml:"Condition,omitempty" json:"Condition,omitempty"`
+		Redirect  WebsiteRedirect   `xml:"Redirect" json:"Redirect"`
+	}
+
+	// WebsiteCondition matches requests by the key prefix and the error code of the response.
+	WebsiteCondition struct {
+		HTTPErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty" json:"HttpErrorCodeReturnedEquals,omitempty"`
+		KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty" json:"KeyPrefixEquals,omitempty"`
+	}
+
+	// WebsiteRedirect describes the redirect location, empty values keep the values of the request.
+	WebsiteRedirect struct {
+		HostName         string `xml:"HostName,omitempty" json:"HostName,omitempty"`
+		HTTPRedirectCode string `xml:"HttpRedirectCode,omitempty" json:"HttpRedirectCode,omitempty"`
+		Protocol         string `xml:"Protocol,omitempty" json:"Protocol,omitempty"`
+		// ReplaceKeyPrefixWith replaces the prefix of the condition, the empty value removes the prefix.
+		ReplaceKeyPrefixWith *string `xml:"ReplaceKeyPrefixWith,omitempty" json:"ReplaceKeyPrefixWith,omitempty"`
+		ReplaceKeyWith       string  `xml:"ReplaceKeyWith,omitempty" json:"ReplaceKeyWith,omitempty"`
+	}
+)
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// StorageState keeps availability of the storage network. While the storage is unavailable,
+	// the gateway works in degraded mode: read requests are served from the caches with the Warning
+	// header, mutations and reads which can't be served from the caches fail fast with SlowDown error.
+	StorageState struct {
+		// degradedSince is unix time in nanoseconds since the storage is unavailable, zero if it's available.
+		degradedSince int64
+		readTimeout   time.Duration
+	}
+
+	// degradedResponseWriter replaces the internal error of the read request which isn't completed
+	// in time in degraded mode with SlowDown error.
+	degradedResponseWriter struct {
+		http.ResponseWriter
+		ctx      context.Context
+		replaced bool
+	}
+)
+
+const (
+	hdrWarning = "Warning"
+
+	// staleResponseWarning is a value of the Warning header of responses served in degraded mode.
+	staleResponseWarning = `110 - "Response is Stale"`
+
+	defaultDegradedReadTimeout = 2 * time.Second
+)
+
+// NewStorageState returns the state of the available storage. Read requests served in degraded mode
+// are limited by the timeout, so the requests which need the storage don't hang.
+func NewStorageState(readTimeout time.Duration) *StorageState {
+	if readTimeout <= 0 {
+		readTimeout = defaultDegradedReadTimeout
+	}
+
+	return &StorageState{readTimeout: readTimeout}
+}
+
+// SetAvailable updates availability of the storage, it returns true if the availability is changed.
+func (s *StorageState) SetAvailable(available bool) bool {
+	if available {
+		return atomic.SwapInt64(&s.degradedSince, 0) != 0
+	}
+	return atomic.CompareAndSwapInt64(&s.degradedSince, 0, time.Now().UnixNano())
+}
+
+// Degraded checks if the storage is unavailable and returns the time since it's unavailable.
+func (s *StorageState) Degraded() (bool, time.Time) {
+	since := atomic.LoadInt64(&s.degradedSince)
+	if since == 0 {
+		return false, time.Time{}
+	}
+	return true, time.Unix(0, since)
+}
+
+// Middleware serves requests in degraded mode while the storage is unavailable.
+func (s *StorageState) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if degraded, _ := s.Degraded(); !degraded {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
+			WriteErrorResponse(w, GetReqInfo(r.Context()), errors.GetAPIError(errors.ErrSlowDown))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), s.readTimeout)
+		defer cancel()
+
+		w.Header().Set(hdrWarning, staleResponseWarning)
+		h.ServeHTTP(&degradedResponseWriter{ResponseWriter: w, ctx: ctx}, r.WithContext(ctx))
+	})
+}
+
+func (w *degradedResponseWriter) WriteHeader(code int) {
+	if code == http.StatusInternalServerError && w.ctx.Err() != nil {
+		w.replaced = true
+		WriteErrorResponse(w.ResponseWriter, GetReqInfo(w.ctx), errors.GetAPIError(errors.ErrSlowDown))
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *degradedResponseWriter) Write(p []byte) (int, error) {
+	if w.replaced {
+		// body of the replaced response is discarded
+		return len(p), nil
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *degradedResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// degradation returns middleware of the storage state, nil state disables degraded mode.
+func degradation(state *StorageState) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if state == nil {
+			return h
+		}
+		return state.Middleware(h)
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageStateMiddleware(t *testing.T) {
+	state := NewStorageState(10 * time.Millisecond)
+
+	var called int
+	h := state.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		if r.URL.Path == "/cached" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		// request needs the storage, so it's completed by the deadline
+		<-r.Context().Done()
+		WriteErrorResponse(w, GetReqInfo(r.Context()), errors.GetAPIError(errors.ErrInternalError))
+	}))
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve(http.MethodPut, "/cached")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get(hdrWarning))
+
+	require.True(t, state.SetAvailable(false))
+	require.False(t, state.SetAvailable(false))
+	degraded, since := state.Degraded()
+	require.True(t, degraded)
+	require.False(t, since.IsZero())
+
+	w = serve(http.MethodPut, "/cached")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Contains(t, w.Body.String(), "SlowDown")
+	require.Equal(t, 1, called)
+
+	w = serve(http.MethodHead, "/cached")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, staleResponseWarning, w.Header().Get(hdrWarning))
+
+	w = serve(http.MethodGet, "/storage")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Contains(t, w.Body.String(), "SlowDown")
+	require.NotContains(t, w.Body.String(), "InternalError")
+
+	require.True(t, state.SetAvailable(true))
+	degraded, _ = state.Degraded()
+	require.False(t, degraded)
+}
@@ -41,6 +41,7 @@ const (
 	ErrInvalidEncodingMethod
 	ErrInvalidMaxUploads
 	ErrInvalidMaxParts
+	ErrInvalidMaxBuckets
 	ErrInvalidPartNumberMarker
 	ErrInvalidAttributeName
 	ErrInvalidPartNumber
@@ -51,6 +52,7 @@ const (
 	ErrInvalidCopyDest
 	ErrInvalidPolicyDocument
 	ErrInvalidObjectState
+	ErrRestoreAlreadyInProgress
 	ErrMalformedXML
 	ErrMissingContentLength
 	ErrMissingContentMD5
@@ -58,11 +60,15 @@ const (
 	ErrMissingSecurityHeader
 	ErrNoSuchBucket
 	ErrNoSuchBucketPolicy
+	ErrNoSuchPublicAccessBlockConfiguration
+	ErrOwnershipControlsNotFoundError
+	ErrAccessControlListNotSupported
 	ErrNoSuchBucketLifecycle
 	ErrNoSuchLifecycleConfiguration
 	ErrNoSuchBucketSSEConfig
 	ErrNoSuchCORSConfiguration
 	ErrNoSuchWebsiteConfiguration
+	ErrNoSuchConfiguration
 	ErrReplicationConfigurationNotFoundError
 	ErrNoSuchKey
 	ErrNoSuchUpload
@@ -87,6 +93,7 @@ const (
 	ErrBucketNotEmpty
 	ErrAllAccessDisabled
 	ErrMalformedPolicy
+	ErrMalformedACLError
 	ErrMissingFields
 	ErrMissingCredTag
 	ErrCredMalformed
@@ -104,6 +111,7 @@ const (
 	ErrNegativeExpires
 	ErrAuthHeaderEmpty
 	ErrExpiredPresignRequest
+	ErrExpiredToken
 	ErrRequestNotReadyYet
 	ErrUnsignedHeaders
 	ErrMissingDateHeader
@@ -177,6 +185,8 @@ const (
 	ErrOperationMaxedOut
 	ErrInvalidRequest
 	ErrInvalidStorageClass
+	ErrBucketReadOnly
+	ErrBucketFrozen
 
 	ErrMalformedJSON
 	ErrInsecureClientRequest
@@ -338,6 +348,12 @@ var errorCodes = errorCodeMap{
 		Description:    "Argument max-parts must be an integer between 0 and 2147483647",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidMaxBuckets: {
+		ErrCode:        ErrInvalidMaxBuckets,
+		Code:           "InvalidArgument",
+		Description:    "Argument max-buckets must be an integer between 1 and 10000",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidPartNumberMarker: {
 		ErrCode:        ErrInvalidPartNumberMarker,
 		Code:           "InvalidArgument",
@@ -470,6 +486,24 @@ var errorCodes = errorCodeMap{
 		Description:    "The bucket policy does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchPublicAccessBlockConfiguration: {
+		ErrCode:        ErrNoSuchPublicAccessBlockConfiguration,
+		Code:           "NoSuchPublicAccessBlockConfiguration",
+		Description:    "The public access block configuration was not found",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrOwnershipControlsNotFoundError: {
+		ErrCode:        ErrOwnershipControlsNotFoundError,
+		Code:           "OwnershipControlsNotFoundError",
+		Description:    "The bucket ownership controls were not found",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAccessControlListNotSupported: {
+		ErrCode:        ErrAccessControlListNotSupported,
+		Code:           "AccessControlListNotSupported",
+		Description:    "The bucket does not allow ACLs",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrNoSuchBucketLifecycle: {
 		ErrCode:        ErrNoSuchBucketLifecycle,
 		Code:           "NoSuchBucketLifecycle",
@@ -590,6 +624,12 @@ var errorCodes = errorCodeMap{
 		Description:    "The operation is not valid for the current state of the object.",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrRestoreAlreadyInProgress: {
+		ErrCode:        ErrRestoreAlreadyInProgress,
+		Code:           "RestoreAlreadyInProgress",
+		Description:    "Object restore is already in progress.",
+		HTTPStatusCode: http.StatusConflict,
+	},
 	ErrAuthorizationHeaderMalformed: {
 		ErrCode:        ErrAuthorizationHeaderMalformed,
 		Code:           "AuthorizationHeaderMalformed",
@@ -638,6 +678,12 @@ var errorCodes = errorCodeMap{
 		Description:    "Policy has invalid resource.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrMalformedACLError: {
+		ErrCode:        ErrMalformedACLError,
+		Code:           "MalformedACLError",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrMissingFields: {
 		ErrCode:        ErrMissingFields,
 		Code:           "MissingFields",
@@ -752,6 +798,12 @@ var errorCodes = errorCodeMap{
 		Description:    "Request has expired",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrExpiredToken: {
+		ErrCode:        ErrExpiredToken,
+		Code:           "ExpiredToken",
+		Description:    "The provided token has expired.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrRequestNotReadyYet: {
 		ErrCode:        ErrRequestNotReadyYet,
 		Code:           "AccessDenied",
@@ -850,6 +902,12 @@ var errorCodes = errorCodeMap{
 		Description:    "The specified bucket does not have a website configuration",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchConfiguration: {
+		ErrCode:        ErrNoSuchConfiguration,
+		Code:           "NoSuchConfiguration",
+		Description:    "The specified configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
 	ErrReplicationConfigurationNotFoundError: {
 		ErrCode:        ErrReplicationConfigurationNotFoundError,
 		Code:           "ReplicationConfigurationNotFoundError",
@@ -1123,6 +1181,18 @@ var errorCodes = errorCodeMap{
 		Description:    "A timeout exceeded while waiting to proceed with the request, please reduce your request rate",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrBucketReadOnly: {
+		ErrCode:        ErrBucketReadOnly,
+		Code:           "BucketReadOnly",
+		Description:    "The bucket is read-only, its objects and configuration can't be modified",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrBucketFrozen: {
+		ErrCode:        ErrBucketFrozen,
+		Code:           "BucketFrozen",
+		Description:    "The bucket is frozen, its objects can't be deleted or overwritten",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrUnsupportedMetadata: {
 		ErrCode:        ErrUnsupportedMetadata,
 		Code:           "InvalidArgument",
@@ -0,0 +1,150 @@
+package features
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Names of feature flags.
+const (
+	// Select enables SelectObjectContent requests.
+	Select = "select"
+	// Website enables serving of bucket websites by the website endpoint.
+	Website = "website"
+	// Concatenate enables server-side objects concatenation extension.
+	Concatenate = "concatenate"
+	// Rename enables RenameObject requests.
+	Rename = "rename"
+	// Search enables search extension over object metadata and tags.
+	Search = "search"
+	// MetadataUpdate enables object metadata update extension.
+	MetadataUpdate = "metadata_update"
+	// DownloadTokens enables single-use download tokens extension.
+	DownloadTokens = "download_tokens"
+)
+
+type (
+	// Flag describes an experimental behavior of the gateway gated by the feature flag.
+	Flag struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		// Default is a value of the flag if it's not configured.
+		Default bool `json:"default"`
+	}
+
+	// State is a value of the flag in the deployment.
+	State struct {
+		Flag
+		Enabled bool `json:"enabled"`
+	}
+
+	// Registry keeps values of feature flags configured for the deployment,
+	// they can be overridden for the bucket. Nil registry uses default values.
+	Registry struct {
+		mu     sync.RWMutex
+		values map[string]bool
+	}
+)
+
+// knownFlags are all flags of the gateway sorted by name.
+var knownFlags = []Flag{
+	{Name: Concatenate, Description: "Server-side objects concatenation extension", Default: true},
+	{Name: DownloadTokens, Description: "Single-use download tokens extension", Default: true},
+	{Name: MetadataUpdate, Description: "Object metadata update extension", Default: true},
+	{Name: Rename, Description: "RenameObject operation for unversioned buckets", Default: true},
+	{Name: Search, Description: "Search extension over object metadata and tags", Default: true},
+	{Name: Select, Description: "SelectObjectContent for CSV, JSON and Parquet objects", Default: true},
+	{Name: Website, Description: "Static website hosting by the website endpoint", Default: true},
+}
+
+// Flags returns all known flags.
+func Flags() []Flag {
+	flags := make([]Flag, len(knownFlags))
+	copy(flags, knownFlags)
+	return flags
+}
+
+func lookup(name string) (Flag, bool) {
+	for _, flag := range knownFlags {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return Flag{}, false
+}
+
+// Check returns an error if any of the flags is unknown.
+func Check(values map[string]bool) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := lookup(name); !ok {
+			return fmt.Errorf("unknown feature flag '%s'", name)
+		}
+	}
+	return nil
+}
+
+// NewRegistry creates a registry with the configured values of flags,
+// flags missing in the values get their default values.
+func NewRegistry(values map[string]bool) (*Registry, error) {
+	r := new(Registry)
+	return r, r.Update(values)
+}
+
+// Update replaces the configured values of flags.
+func (r *Registry) Update(values map[string]bool) error {
+	if err := Check(values); err != nil {
+		return err
+	}
+
+	copied := make(map[string]bool, len(values))
+	for name, value := range values {
+		copied[name] = value
+	}
+
+	r.mu.Lock()
+	r.values = copied
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Enabled checks if the flag is enabled for the bucket with the overrides.
+// Unknown flags are always disabled.
+func (r *Registry) Enabled(name string, overrides map[string]bool) bool {
+	if value, ok := overrides[name]; ok {
+		return value
+	}
+
+	flag, ok := lookup(name)
+	if !ok {
+		return false
+	}
+
+	if r == nil {
+		return flag.Default
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if value, ok := r.values[name]; ok {
+		return value
+	}
+	return flag.Default
+}
+
+// States returns values of all flags for the bucket with the overrides, nil overrides
+// return values of the deployment.
+func (r *Registry) States(overrides map[string]bool) []State {
+	states := make([]State, len(knownFlags))
+	for i, flag := range knownFlags {
+		states[i] = State{Flag: flag, Enabled: r.Enabled(flag.Name, overrides)}
+	}
+	return states
+}
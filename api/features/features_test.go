@@ -0,0 +1,32 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	var nilRegistry *Registry
+	require.True(t, nilRegistry.Enabled(Select, nil))
+	require.False(t, nilRegistry.Enabled("unknown", nil))
+
+	_, err := NewRegistry(map[string]bool{"unknown": true})
+	require.Error(t, err)
+
+	r, err := NewRegistry(map[string]bool{Select: false})
+	require.NoError(t, err)
+	require.False(t, r.Enabled(Select, nil))
+	require.True(t, r.Enabled(Select, map[string]bool{Select: true}))
+	require.True(t, r.Enabled(Website, nil))
+	require.False(t, r.Enabled(Website, map[string]bool{Website: false}))
+
+	require.NoError(t, r.Update(nil))
+	require.True(t, r.Enabled(Select, nil))
+
+	states := r.States(map[string]bool{Rename: false})
+	require.Len(t, states, len(Flags()))
+	for _, state := range states {
+		require.Equal(t, state.Name != Rename, state.Enabled, state.Name)
+	}
+}
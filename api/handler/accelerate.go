@@ -0,0 +1,106 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+var (
+	errAccelerateNotConfigured = stderrors.New("transfer acceleration is not configured on this bucket")
+	errAccelerateBucketName    = stderrors.New("transfer acceleration is not supported for buckets with periods in their names")
+)
+
+func (h *handler) GetBucketAccelerateHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, &AccelerateConfiguration{Status: settings.AccelerateStatus}); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func (h *handler) PutBucketAccelerateHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := &AccelerateConfiguration{}
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "couldn't parse accelerate configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if conf.Status != data.AccelerateStatusEnabled && conf.Status != data.AccelerateStatusSuspended {
+		h.logAndSendError(w, "invalid accelerate status", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+	if conf.Status == data.AccelerateStatusEnabled && strings.Contains(bktInfo.Name, ".") {
+		h.logAndSendError(w, "invalid bucket name", reqInfo, errors.GetAPIErrorWithError(errors.ErrInvalidRequest, errAccelerateBucketName))
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	// settings pointer is stored in the cache, so modify a copy of the settings
+	newSettings := *settings
+	newSettings.AccelerateStatus = conf.Status
+
+	if err = h.obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &newSettings,
+	}); err != nil {
+		h.logAndSendError(w, "couldn't put accelerate configuration", reqInfo, err)
+	}
+}
+
+// CheckAccelerate checks the request sent through the accelerated endpoint. It sends InvalidRequest error
+// and returns false if transfer acceleration of the bucket isn't enabled.
+func (h *handler) CheckAccelerate(w http.ResponseWriter, r *http.Request) bool {
+	reqInfo := api.GetReqInfo(r.Context())
+	if reqInfo.BucketName == "" {
+		return true
+	}
+
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
+	if err != nil {
+		// the handler reports the error itself
+		return true
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return false
+	}
+	if !settings.AccelerateEnabled() {
+		h.logAndSendError(w, "accelerated request", reqInfo, errors.GetAPIErrorWithError(errors.ErrInvalidRequest, errAccelerateNotConfigured))
+		return false
+	}
+
+	return true
+}
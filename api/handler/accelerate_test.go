@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketAccelerate(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-accelerate"
+	createTestBucket(hc, bktName)
+
+	require.Empty(t, getBucketAccelerate(hc, bktName))
+	checkAccelerate(hc, bktName, false)
+
+	putBucketAccelerate(hc, bktName, "On", http.StatusBadRequest)
+	putBucketAccelerate(hc, bktName, data.AccelerateStatusEnabled, http.StatusOK)
+	require.Equal(t, data.AccelerateStatusEnabled, getBucketAccelerate(hc, bktName))
+	checkAccelerate(hc, bktName, true)
+
+	putBucketAccelerate(hc, bktName, data.AccelerateStatusSuspended, http.StatusOK)
+	require.Equal(t, data.AccelerateStatusSuspended, getBucketAccelerate(hc, bktName))
+	checkAccelerate(hc, bktName, false)
+
+	dottedName := "bucket.for.accelerate"
+	createTestBucket(hc, dottedName)
+	putBucketAccelerate(hc, dottedName, data.AccelerateStatusEnabled, http.StatusBadRequest)
+	putBucketAccelerate(hc, dottedName, data.AccelerateStatusSuspended, http.StatusOK)
+}
+
+func getBucketAccelerate(hc *handlerContext, bktName string) string {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketAccelerateHandler(w, r)
+
+	conf := &AccelerateConfiguration{}
+	readResponse(hc.t, w, http.StatusOK, conf)
+	return conf.Status
+}
+
+func putBucketAccelerate(hc *handlerContext, bktName, status string, code int) {
+	w, r := prepareTestRequest(hc, bktName, "", &AccelerateConfiguration{Status: status})
+	hc.Handler().PutBucketAccelerateHandler(w, r)
+	assertStatus(hc.t, w, code)
+}
+
+func checkAccelerate(hc *handlerContext, bktName string, ok bool) {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	require.Equal(hc.t, ok, hc.Handler().CheckAccelerate(w, r))
+	if !ok {
+		assertStatus(hc.t, w, http.StatusBadRequest)
+	}
+}
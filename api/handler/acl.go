@@ -6,7 +6,6 @@ import (
 	"crypto/elliptic"
 	"encoding/hex"
 	"encoding/json"
-	"encoding/xml"
 	stderrors "errors"
 	"fmt"
 	"net/http"
@@ -20,10 +19,13 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"go.uber.org/zap"
 )
 
@@ -33,6 +35,9 @@ var (
 		eacl.OperationSearch, eacl.OperationRange, eacl.OperationRangeHash}
 	fullOps = []eacl.Operation{eacl.OperationGet, eacl.OperationHead, eacl.OperationPut,
 		eacl.OperationDelete, eacl.OperationSearch, eacl.OperationRange, eacl.OperationRangeHash}
+
+	// operations of ACP resources: GET stands for READ_ACP and PUT stands for WRITE_ACP permission.
+	acpOps = []eacl.Operation{eacl.OperationGet, eacl.OperationPut}
 )
 
 var actionToOpMap = map[string][]eacl.Operation{
@@ -67,6 +72,8 @@ const (
 	awsPermFullControl amazonS3Permission = "FULL_CONTROL"
 	awsPermWrite       amazonS3Permission = "WRITE"
 	awsPermRead        amazonS3Permission = "READ"
+	awsPermWriteACP    amazonS3Permission = "WRITE_ACP"
+	awsPermReadACP     amazonS3Permission = "READ_ACP"
 )
 
 // enum of Amazon S3 ACL permission grantees.
@@ -118,16 +125,28 @@ type resourceInfo struct {
 	Bucket  string
 	Object  string
 	Version string
+	// ACP marks the resource with READ_ACP and WRITE_ACP grants of the bucket or the object.
+	// Storage nodes don't know such permissions, so records of ACP resources never match
+	// any request and are checked by the gateway only.
+	ACP bool
 }
 
 func (r *resourceInfo) Name() string {
+	var prefix string
+	if r.ACP {
+		prefix = acpResourcePrefix
+	}
 	if len(r.Object) == 0 {
-		return r.Bucket
+		return prefix + r.Bucket
 	}
 	if len(r.Version) == 0 {
-		return r.Bucket + "/" + r.Object
+		return prefix + r.Bucket + "/" + r.Object
 	}
-	return r.Bucket + "/" + r.Object + ":" + r.Version
+	return prefix + r.Bucket + "/" + r.Object + ":" + r.Version
+}
+
+func (r *resourceInfo) Equal(other resourceInfo) bool {
+	return r.Bucket == other.Bucket && r.Object == other.Object && r.Version == other.Version && r.ACP == other.ACP
 }
 
 func (r *resourceInfo) IsBucket() bool {
@@ -147,6 +166,10 @@ func (a astOperation) IsGroupGrantee() bool {
 const (
 	serviceRecordResourceKey    = "Resource"
 	serviceRecordGroupLengthKey = "GroupLength"
+
+	acpRecordResourceKey = "ACPResource"
+	// bucket names can't contain colon, so the prefix never clashes with the bucket resource.
+	acpResourcePrefix = "acp:"
 )
 
 type ServiceRecord struct {
@@ -179,6 +202,11 @@ func (h *handler) GetBucketACLHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.checkACP(r.Context(), bucketACL, &resourceInfo{Bucket: bktInfo.Name}, false); err != nil {
+		h.logAndSendError(w, "access to bucket acl denied", reqInfo, err)
+		return
+	}
+
 	if err = api.EncodeToResponse(w, h.encodeBucketACL(bktInfo.Name, bucketACL)); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 		return
@@ -223,7 +251,7 @@ func (h *handler) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
 			h.logAndSendError(w, "could not parse bucket acl", reqInfo, err)
 			return
 		}
-	} else if err = xml.NewDecoder(r.Body).Decode(list); err != nil {
+	} else if err = api.NewXMLDecoder(r.Body).Decode(list); err != nil {
 		h.logAndSendError(w, "could not parse bucket acl", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
 		return
 	}
@@ -241,14 +269,33 @@ func (h *handler) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err = h.updateBucketACL(r, astBucket, bktInfo, token); err != nil {
+	if err = h.checkWriteACP(r.Context(), bktInfo, resInfo); err != nil {
+		h.logAndSendError(w, "access to bucket acl denied", reqInfo, err)
+		return
+	}
+
+	disabled, err := h.checkPutACLDisabled(r, bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "acl is disabled in the bucket", reqInfo, err)
+		return
+	} else if disabled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err = h.checkPublicACL(r.Context(), bktInfo, list); err != nil {
+		h.logAndSendError(w, "public bucket acl is blocked", reqInfo, err)
+		return
+	}
+
+	if _, err = h.updateBucketACL(r, astBucket, bktInfo, token, layer.ConfigTypeACL); err != nil {
 		h.logAndSendError(w, "could not update bucket acl", reqInfo, err)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.BucketInfo, sessionToken *session.Container) (bool, error) {
+func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.BucketInfo, sessionToken *session.Container, configType string) (bool, error) {
 	bucketACL, err := h.obj.GetBucketACL(r.Context(), bktInfo)
 	if err != nil {
 		return false, fmt.Errorf("could not get bucket eacl: %w", err)
@@ -277,6 +324,8 @@ func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.
 		BktInfo:      bktInfo,
 		EACL:         table,
 		SessionToken: sessionToken,
+		ConfigType:   configType,
+		CopiesNumber: h.cfg.CopiesNumber,
 	}
 
 	if err = h.obj.PutBucketACL(r.Context(), p); err != nil {
@@ -313,7 +362,18 @@ func (h *handler) GetObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = api.EncodeToResponse(w, h.encodeObjectACL(bucketACL, reqInfo.BucketName, objInfo.VersionID())); err != nil {
+	resInfo := &resourceInfo{
+		Bucket:  reqInfo.BucketName,
+		Object:  reqInfo.ObjectName,
+		Version: objInfo.VersionID(),
+	}
+
+	if err = h.checkACP(r.Context(), bucketACL, resInfo, false); err != nil {
+		h.logAndSendError(w, "access to object acl denied", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, h.encodeObjectACL(bucketACL, resInfo)); err != nil {
 		h.logAndSendError(w, "failed to encode response", reqInfo, err)
 	}
 }
@@ -358,7 +418,7 @@ func (h *handler) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 			h.logAndSendError(w, "could not parse bucket acl", reqInfo, err)
 			return
 		}
-	} else if err = xml.NewDecoder(r.Body).Decode(list); err != nil {
+	} else if err = api.NewXMLDecoder(r.Body).Decode(list); err != nil {
 		h.logAndSendError(w, "could not parse bucket acl", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
 		return
 	}
@@ -369,13 +429,32 @@ func (h *handler) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 		Version: objInfo.VersionID(),
 	}
 
+	if err = h.checkWriteACP(r.Context(), bktInfo, resInfo); err != nil {
+		h.logAndSendError(w, "access to object acl denied", reqInfo, err)
+		return
+	}
+
+	disabled, err := h.checkPutACLDisabled(r, bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "acl is disabled in the bucket", reqInfo, err)
+		return
+	} else if disabled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err = h.checkPublicACL(r.Context(), bktInfo, list); err != nil {
+		h.logAndSendError(w, "public object acl is blocked", reqInfo, err)
+		return
+	}
+
 	astObject, err := aclToAst(list, resInfo)
 	if err != nil {
 		h.logAndSendError(w, "could not translate acl to ast", reqInfo, err)
 		return
 	}
 
-	updated, err := h.updateBucketACL(r, astObject, bktInfo, token)
+	updated, err := h.updateBucketACL(r, astObject, bktInfo, token, "")
 	if err != nil {
 		h.logAndSendError(w, "could not update bucket acl", reqInfo, err)
 		return
@@ -403,15 +482,13 @@ func (h *handler) GetBucketPolicyHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	bucketACL, err := h.obj.GetBucketACL(r.Context(), bktInfo)
+	bktPolicy, err := h.obj.GetBucketPolicy(r.Context(), bktInfo)
 	if err != nil {
-		h.logAndSendError(w, "could not fetch bucket acl", reqInfo, err)
+		h.logAndSendError(w, "could not get bucket policy", reqInfo, err)
 		return
 	}
 
-	ast := tableToAst(bucketACL.EACL, reqInfo.BucketName)
-	bktPolicy := astToPolicy(ast)
-
+	w.Header().Set(api.ContentType, "application/json")
 	w.WriteHeader(http.StatusOK)
 
 	if err = json.NewEncoder(w).Encode(bktPolicy); err != nil {
@@ -447,22 +524,107 @@ func (h *handler) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	bktPolicy := &bucketPolicy{Bucket: reqInfo.BucketName}
-	if err = json.NewDecoder(r.Body).Decode(bktPolicy); err != nil {
-		h.logAndSendError(w, "could not parse bucket policy", reqInfo, err)
+	bktPolicy, err := policy.Parse(r.Body)
+	if err != nil {
+		h.logAndSendError(w, "could not parse bucket policy", reqInfo, errors.GetAPIErrorWithError(errors.ErrMalformedPolicy, err))
+		return
+	}
+	if err = bktPolicy.Validate(reqInfo.BucketName); err != nil {
+		h.logAndSendError(w, "invalid bucket policy", reqInfo, errors.GetAPIErrorWithError(errors.ErrMalformedPolicy, err))
+		return
+	}
+
+	if err = h.checkPublicPolicy(r.Context(), bktInfo, bktPolicy); err != nil {
+		h.logAndSendError(w, "public bucket policy is blocked", reqInfo, err)
 		return
 	}
 
-	astPolicy, err := policyToAst(bktPolicy)
+	astPolicy, err := policyToAst(formLegacyPolicy(bktPolicy, reqInfo.BucketName))
 	if err != nil {
 		h.logAndSendError(w, "could not translate policy to ast", reqInfo, err)
 		return
 	}
 
-	if _, err = h.updateBucketACL(r, astPolicy, bktInfo, token); err != nil {
+	// policy change is saved to the bucket history with the policy document
+	if _, err = h.updateBucketACL(r, astPolicy, bktInfo, token, ""); err != nil {
 		h.logAndSendError(w, "could not update bucket acl", reqInfo, err)
 		return
 	}
+
+	p := &layer.PutBucketPolicyParams{
+		BktInfo:      bktInfo,
+		Policy:       bktPolicy,
+		CopiesNumber: h.cfg.CopiesNumber,
+	}
+
+	if err = h.obj.PutBucketPolicy(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not put bucket policy", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) DeleteBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketPolicy(r.Context(), bktInfo); err != nil {
+		h.logAndSendError(w, "could not delete bucket policy", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// formLegacyPolicy converts the policy document to the form which is translated to eACL records.
+// Every principal of the statement results in a separate statement. Resources with wildcards
+// can't be expressed by eACL filters, so they are evaluated by the gateway only.
+func formLegacyPolicy(p *policy.Policy, bucket string) *bucketPolicy {
+	res := &bucketPolicy{
+		Version: p.Version,
+		ID:      p.ID,
+		Bucket:  bucket,
+	}
+
+	for _, st := range p.Statement {
+		var resources []string
+		for _, resource := range st.Resource {
+			if !strings.ContainsAny(resource, "*?") {
+				resources = append(resources, resource)
+			}
+		}
+		if len(resources) == 0 {
+			continue
+		}
+
+		var principals []principal
+		if len(st.Principal.AWS) != 0 {
+			principals = append(principals, principal{AWS: allUsersWildcard})
+		}
+		for _, user := range st.Principal.CanonicalUser {
+			if user == allUsersWildcard {
+				principals = append(principals, principal{AWS: allUsersWildcard})
+				continue
+			}
+			principals = append(principals, principal{CanonicalUser: user})
+		}
+
+		for _, prn := range principals {
+			res.Statement = append(res.Statement, statement{
+				Sid:       st.Sid,
+				Effect:    st.Effect,
+				Principal: prn,
+				Action:    st.Action,
+				Resource:  resources,
+			})
+		}
+	}
+
+	return res
 }
 
 func parseACLHeaders(header http.Header, key *keys.PublicKey) (*AccessControlPolicy, error) {
@@ -494,6 +656,12 @@ func parseACLHeaders(header http.Header, key *keys.PublicKey) (*AccessControlPol
 	if acp.AccessControlList, err = addGrantees(acp.AccessControlList, header, api.AmzGrantWrite); err != nil {
 		return nil, fmt.Errorf("add grantees write: %w", err)
 	}
+	if acp.AccessControlList, err = addGrantees(acp.AccessControlList, header, api.AmzGrantReadACP); err != nil {
+		return nil, fmt.Errorf("add grantees read acp: %w", err)
+	}
+	if acp.AccessControlList, err = addGrantees(acp.AccessControlList, header, api.AmzGrantWriteACP); err != nil {
+		return nil, fmt.Errorf("add grantees write acp: %w", err)
+	}
 
 	return acp, nil
 }
@@ -535,6 +703,10 @@ func grantHdrToPermission(grant string) (amazonS3Permission, error) {
 		return awsPermRead, nil
 	case api.AmzGrantWrite:
 		return awsPermWrite, nil
+	case api.AmzGrantReadACP:
+		return awsPermReadACP, nil
+	case api.AmzGrantWriteACP:
+		return awsPermWriteACP, nil
 	}
 	return "", fmt.Errorf("unsuppoted header: %s", grant)
 }
@@ -584,14 +756,22 @@ func formGrantee(granteeType, value string) (*Grantee, error) {
 
 func addPredefinedACP(acp *AccessControlPolicy, cannedACL string) (*AccessControlPolicy, error) {
 	switch cannedACL {
-	case basicACLPrivate:
+	case basicACLPrivate, layer.CannedACLBucketOwnerFullControl:
+		// the bucket owner is the container owner, so only the writer is granted explicitly
 	case basicACLPublic:
+		// FULL_CONTROL isn't granted, because it allows all users to manage ACL
 		acp.AccessControlList = append(acp.AccessControlList, &Grant{
 			Grantee: &Grantee{
 				URI:  allUsersGroup,
 				Type: granteeGroup,
 			},
-			Permission: awsPermFullControl,
+			Permission: awsPermRead,
+		}, &Grant{
+			Grantee: &Grantee{
+				URI:  allUsersGroup,
+				Type: granteeGroup,
+			},
+			Permission: awsPermWrite,
 		})
 	case cannedACLAuthRead:
 		fallthrough
@@ -853,8 +1033,7 @@ func removeUsers(resource *astResource, astOperation *astOperation, users []stri
 
 func getParentResource(parent *ast, resource *astResource) *astResource {
 	for _, parentResource := range parent.Resources {
-		if resource.Bucket == parentResource.Bucket && resource.Object == parentResource.Object &&
-			resource.Version == parentResource.Version {
+		if resource.Equal(parentResource.resourceInfo) {
 			return parentResource
 		}
 	}
@@ -936,7 +1115,10 @@ func formRecords(resource *astResource) ([]*eacl.Record, error) {
 			// Unknown role is used, because it is ignored when keys are set
 			eacl.AddFormedTarget(record, eacl.RoleUnknown, targetKeys...)
 		}
-		if len(resource.Object) != 0 {
+		if resource.ACP {
+			// the filter never matches, so storage nodes ignore the record
+			record.AddFilter(eacl.HeaderFromService, eacl.MatchUnknown, acpRecordResourceKey, resource.Name())
+		} else if len(resource.Object) != 0 {
 			if len(resource.Version) != 0 {
 				var id oid.ID
 				if err := id.DecodeString(resource.Version); err != nil {
@@ -1034,6 +1216,12 @@ func policyToAst(bktPolicy *bucketPolicy) (*ast, error) {
 }
 
 func resourceInfoFromName(name, bucketName string) resourceInfo {
+	if strings.HasPrefix(name, acpResourcePrefix) {
+		resInfo := resourceInfoFromName(strings.TrimPrefix(name, acpResourcePrefix), bucketName)
+		resInfo.ACP = true
+		return resInfo
+	}
+
 	resInfo := resourceInfo{Bucket: bucketName}
 	if name != bucketName {
 		versionedObject := strings.TrimPrefix(name, bucketName+"/")
@@ -1052,73 +1240,6 @@ func resourceInfoFromName(name, bucketName string) resourceInfo {
 	return resInfo
 }
 
-func astToPolicy(ast *ast) *bucketPolicy {
-	bktPolicy := &bucketPolicy{}
-
-	for _, resource := range ast.Resources {
-		allowed, denied := triageOperations(resource.Operations)
-		handleResourceOperations(bktPolicy, allowed, eacl.ActionAllow, resource.Name())
-		handleResourceOperations(bktPolicy, denied, eacl.ActionDeny, resource.Name())
-	}
-
-	return bktPolicy
-}
-
-func handleResourceOperations(bktPolicy *bucketPolicy, list []*astOperation, eaclAction eacl.Action, resourceName string) {
-	userOpsMap := make(map[string][]eacl.Operation)
-
-	for _, op := range list {
-		if !op.IsGroupGrantee() {
-			for _, user := range op.Users {
-				userOps := userOpsMap[user]
-				userOps = append(userOps, op.Op)
-				userOpsMap[user] = userOps
-			}
-		} else {
-			userOps := userOpsMap[allUsersGroup]
-			userOps = append(userOps, op.Op)
-			userOpsMap[allUsersGroup] = userOps
-		}
-	}
-
-	for user, userOps := range userOpsMap {
-		var actions []string
-	LOOP:
-		for action, ops := range actionToOpMap {
-			for _, op := range ops {
-				if !contains(userOps, op) {
-					continue LOOP
-				}
-			}
-			actions = append(actions, action)
-		}
-		if len(actions) != 0 {
-			state := statement{
-				Effect:    actionToEffect(eaclAction),
-				Principal: principal{CanonicalUser: user},
-				Action:    actions,
-				Resource:  []string{arnAwsPrefix + resourceName},
-			}
-			if user == allUsersGroup {
-				state.Principal = principal{AWS: allUsersWildcard}
-			}
-			bktPolicy.Statement = append(bktPolicy.Statement, state)
-		}
-	}
-}
-
-func triageOperations(operations []*astOperation) ([]*astOperation, []*astOperation) {
-	var allowed, denied []*astOperation
-	for _, op := range operations {
-		if op.Action == eacl.ActionAllow {
-			allowed = append(allowed, op)
-		} else {
-			denied = append(denied, op)
-		}
-	}
-	return allowed, denied
-}
-
 func addTo(list []*astOperation, userID string, op eacl.Operation, groupGrantee bool, action eacl.Action) []*astOperation {
 	var found *astOperation
 	for _, astop := range list {
@@ -1182,6 +1303,9 @@ func aclToAst(acl *AccessControlPolicy, resInfo *resourceInfo) (*ast, error) {
 		if grant.Grantee.Type == granteeAmazonCustomerByEmail || (grant.Grantee.Type == granteeGroup && grant.Grantee.URI != allUsersGroup) {
 			return nil, stderrors.New("unsupported grantee type")
 		}
+		if !isValidPermission(grant.Permission) {
+			return nil, errors.GetAPIErrorWithError(errors.ErrMalformedACLError, fmt.Errorf("unsupported permission: %s", grant.Permission))
+		}
 
 		var groupGrantee bool
 		if grant.Grantee.Type == granteeGroup {
@@ -1198,9 +1322,72 @@ func aclToAst(acl *AccessControlPolicy, resInfo *resourceInfo) (*ast, error) {
 	}
 
 	res.Resources = []*astResource{resource}
+	if acpResource := aclToACPResource(acl, resInfo); acpResource != nil {
+		res.Resources = append(res.Resources, acpResource)
+	}
 	return res, nil
 }
 
+// aclToACPResource forms the ACP resource with READ_ACP and WRITE_ACP grants of the ACL.
+// It returns nil if the ACL grants nothing but the owner access.
+func aclToACPResource(acl *AccessControlPolicy, resInfo *resourceInfo) *astResource {
+	resource := &astResource{resourceInfo: *resInfo}
+	resource.ACP = true
+
+	// private canned ACL revokes grants of all users
+	if len(acl.AccessControlList) < 2 {
+		for _, op := range acpOps {
+			resource.Operations = append(resource.Operations, &astOperation{
+				Op:     op,
+				Action: eacl.ActionDeny,
+			})
+		}
+	}
+
+	for _, grant := range acl.AccessControlList {
+		groupGrantee := grant.Grantee.Type == granteeGroup
+		if !groupGrantee && grant.Grantee.ID == acl.Owner.ID {
+			continue
+		}
+
+		for _, op := range permissionToACPOperations(grant.Permission) {
+			resource.Operations = addTo(resource.Operations, grant.Grantee.ID, op, groupGrantee, eacl.ActionAllow)
+		}
+	}
+
+	if len(resource.Operations) == 0 {
+		return nil
+	}
+
+	return resource
+}
+
+// addACPRecords appends records of the ACP resource to the bucket eACL table
+// formed by bucketACLToTable.
+func addACPRecords(table *eacl.Table, acl *AccessControlPolicy, resInfo *resourceInfo) error {
+	// new bucket denies everything to others by default
+	if len(acl.AccessControlList) < 2 {
+		return nil
+	}
+
+	resource := aclToACPResource(acl, resInfo)
+	if resource == nil {
+		return nil
+	}
+
+	acpTable, err := astToTable(&ast{Resources: []*astResource{resource}})
+	if err != nil {
+		return err
+	}
+
+	records := acpTable.Records()
+	for i := range records {
+		table.AddRecord(&records[i])
+	}
+
+	return nil
+}
+
 func aclToPolicy(acl *AccessControlPolicy, resInfo *resourceInfo) (*bucketPolicy, error) {
 	if resInfo.Bucket == "" {
 		return nil, fmt.Errorf("resource bucket must not be empty")
@@ -1305,15 +1492,16 @@ func effectToAction(effect string) eacl.Action {
 	return eacl.ActionUnknown
 }
 
-func actionToEffect(action eacl.Action) string {
-	switch action {
-	case eacl.ActionAllow:
-		return "Allow"
-	case eacl.ActionDeny:
-		return "Deny"
-	default:
-		return ""
+func permissionToACPOperations(permission amazonS3Permission) []eacl.Operation {
+	switch permission {
+	case awsPermFullControl:
+		return acpOps
+	case awsPermReadACP:
+		return []eacl.Operation{eacl.OperationGet}
+	case awsPermWriteACP:
+		return []eacl.Operation{eacl.OperationPut}
 	}
+	return nil
 }
 
 func permissionToOperations(permission amazonS3Permission) []eacl.Operation {
@@ -1332,7 +1520,7 @@ func isWriteOperation(op eacl.Operation) bool {
 	return op == eacl.OperationDelete || op == eacl.OperationPut
 }
 
-func (h *handler) encodeObjectACL(bucketACL *layer.BucketACL, bucketName, objectVersion string) *AccessControlPolicy {
+func (h *handler) encodeObjectACL(bucketACL *layer.BucketACL, resInfo *resourceInfo) *AccessControlPolicy {
 	res := &AccessControlPolicy{
 		Owner: Owner{
 			ID:          bucketACL.Info.Owner.String(),
@@ -1340,47 +1528,35 @@ func (h *handler) encodeObjectACL(bucketACL *layer.BucketACL, bucketName, object
 		},
 	}
 
-	m := make(map[string][]eacl.Operation)
+	granteeOps := make(map[string]map[eacl.Operation]eacl.Action)
+	granteeACPOps := make(map[string]map[eacl.Operation]eacl.Action)
 
-	astList := tableToAst(bucketACL.EACL, bucketName)
+	astList := tableToAst(bucketACL.EACL, resInfo.Bucket)
 
 	for _, resource := range astList.Resources {
-		if resource.Version != objectVersion {
+		if resource.Object != resInfo.Object || (resource.Version != "" && resource.Version != resInfo.Version) {
 			continue
 		}
 
-		for _, op := range resource.Operations {
-			if op.Action != eacl.ActionAllow {
-				continue
-			}
-
-			if len(op.Users) == 0 {
-				list := append(m[allUsersGroup], op.Op)
-				m[allUsersGroup] = list
-			} else {
-				for _, user := range op.Users {
-					list := append(m[user], op.Op)
-					m[user] = list
-				}
-			}
+		if resource.ACP {
+			collectGranteeOps(granteeACPOps, resource)
+		} else {
+			collectGranteeOps(granteeOps, resource)
 		}
 	}
 
-	for key, val := range m {
-		permission := awsPermFullControl
-		read := true
-		for op := eacl.OperationGet; op <= eacl.OperationRangeHash; op++ {
-			if !contains(val, op) && !isWriteOperation(op) {
-				read = false
-			}
-		}
-
-		if read {
-			permission = awsPermFullControl
-		} else {
-			h.log.Warn("some acl not fully mapped")
+	grantees := make([]string, 0, len(granteeOps))
+	for key := range granteeOps {
+		grantees = append(grantees, key)
+	}
+	for key := range granteeACPOps {
+		if _, ok := granteeOps[key]; !ok {
+			grantees = append(grantees, key)
 		}
+	}
+	sort.Strings(grantees)
 
+	for _, key := range grantees {
 		var grantee *Grantee
 		if key == allUsersGroup {
 			grantee = NewGrantee(granteeGroup)
@@ -1390,18 +1566,135 @@ func (h *handler) encodeObjectACL(bucketACL *layer.BucketACL, bucketName, object
 			grantee.ID = key
 		}
 
-		grant := &Grant{
-			Grantee:    grantee,
-			Permission: permission,
+		isOwner := key != allUsersGroup && isOwnerKey(bucketACL.Info.Owner, key)
+		for _, permission := range opsToPermissions(granteeOps[key], granteeACPOps[key], resInfo.IsBucket(), isOwner) {
+			res.AccessControlList = append(res.AccessControlList, &Grant{
+				Grantee:    grantee,
+				Permission: permission,
+			})
 		}
-		res.AccessControlList = append(res.AccessControlList, grant)
 	}
 
 	return res
 }
 
 func (h *handler) encodeBucketACL(bucketName string, bucketACL *layer.BucketACL) *AccessControlPolicy {
-	return h.encodeObjectACL(bucketACL, bucketName, "")
+	return h.encodeObjectACL(bucketACL, &resourceInfo{Bucket: bucketName})
+}
+
+// collectGranteeOps stores the actions of the resource operations for every grantee.
+// The last operation of the resource forms the first eACL record, so it takes precedence.
+func collectGranteeOps(granteeOps map[string]map[eacl.Operation]eacl.Action, resource *astResource) {
+	for i := len(resource.Operations) - 1; i >= 0; i-- {
+		astOp := resource.Operations[i]
+		users := astOp.Users
+		if astOp.IsGroupGrantee() {
+			users = []string{allUsersGroup}
+		}
+
+		for _, user := range users {
+			ops, ok := granteeOps[user]
+			if !ok {
+				ops = make(map[eacl.Operation]eacl.Action)
+				granteeOps[user] = ops
+			}
+			if _, ok = ops[astOp.Op]; !ok {
+				ops[astOp.Op] = astOp.Action
+			}
+		}
+	}
+}
+
+func opsToPermissions(ops, acpOperations map[eacl.Operation]eacl.Action, isBucket, isOwner bool) []amazonS3Permission {
+	read := allowsAll(ops, readOps)
+	write := isBucket && allowsAll(ops, writeOps)
+	// the owner can always read and modify the ACL
+	readACP := isOwner || acpOperations[eacl.OperationGet] == eacl.ActionAllow
+	writeACP := isOwner || acpOperations[eacl.OperationPut] == eacl.ActionAllow
+
+	if read && (write || !isBucket) && readACP && writeACP {
+		return []amazonS3Permission{awsPermFullControl}
+	}
+
+	var res []amazonS3Permission
+	if read {
+		res = append(res, awsPermRead)
+	}
+	if write {
+		res = append(res, awsPermWrite)
+	}
+	if readACP {
+		res = append(res, awsPermReadACP)
+	}
+	if writeACP {
+		res = append(res, awsPermWriteACP)
+	}
+
+	return res
+}
+
+func allowsAll(ops map[eacl.Operation]eacl.Action, list []eacl.Operation) bool {
+	for _, op := range list {
+		if ops[op] != eacl.ActionAllow {
+			return false
+		}
+	}
+	return true
+}
+
+func isOwnerKey(owner user.ID, hexKey string) bool {
+	key, err := keys.NewPublicKeyFromString(hexKey)
+	if err != nil {
+		return false
+	}
+
+	var id user.ID
+	user.IDFromKey(&id, (ecdsa.PublicKey)(*key))
+	return id.Equals(owner)
+}
+
+// checkACP checks READ_ACP (or WRITE_ACP if write is set) permission of the requester.
+// Storage nodes don't know these permissions, so they are checked by the gateway.
+func (h *handler) checkACP(ctx context.Context, bucketACL *layer.BucketACL, resInfo *resourceInfo, write bool) error {
+	var requester string
+	if box, err := layer.GetBoxData(ctx); err == nil && box.Gate.BearerToken != nil {
+		if bucketACL.Info.Owner.Equals(bearer.ResolveIssuer(*box.Gate.BearerToken)) {
+			return nil
+		}
+
+		key, err := h.bearerTokenIssuerKey(ctx)
+		if err != nil {
+			return fmt.Errorf("couldn't get requester key: %w", err)
+		}
+		requester = hex.EncodeToString(key.Bytes())
+	}
+
+	op := eacl.OperationGet
+	if write {
+		op = eacl.OperationPut
+	}
+
+	acpInfo := *resInfo
+	acpInfo.ACP = true
+
+	for _, resource := range tableToAst(bucketACL.EACL, resInfo.Bucket).Resources {
+		if !resource.Equal(acpInfo) {
+			continue
+		}
+
+		for i := len(resource.Operations) - 1; i >= 0; i-- {
+			astOp := resource.Operations[i]
+			if astOp.Op != op || !(astOp.IsGroupGrantee() || containsStr(astOp.Users, requester)) {
+				continue
+			}
+			if astOp.Action == eacl.ActionAllow {
+				return nil
+			}
+			break
+		}
+	}
+
+	return errors.GetAPIError(errors.ErrAccessDenied)
 }
 
 func contains(list []eacl.Operation, op eacl.Operation) bool {
@@ -1464,8 +1757,25 @@ func bucketACLToTable(acp *AccessControlPolicy) (*eacl.Table, error) {
 	return table, nil
 }
 
+func (h *handler) checkWriteACP(ctx context.Context, bktInfo *data.BucketInfo, resInfo *resourceInfo) error {
+	bucketACL, err := h.obj.GetBucketACL(ctx, bktInfo)
+	if err != nil {
+		return fmt.Errorf("could not get bucket eacl: %w", err)
+	}
+
+	return h.checkACP(ctx, bucketACL, resInfo, true)
+}
+
+func isValidPermission(permission amazonS3Permission) bool {
+	switch permission {
+	case awsPermFullControl, awsPermRead, awsPermWrite, awsPermReadACP, awsPermWriteACP:
+		return true
+	}
+	return false
+}
+
 func isValidGrant(grant *Grant) bool {
-	return (grant.Permission == awsPermFullControl || grant.Permission == awsPermRead || grant.Permission == awsPermWrite) &&
+	return isValidPermission(grant.Permission) &&
 		(grant.Grantee.Type == granteeCanonicalUser || (grant.Grantee.Type == granteeGroup && grant.Grantee.URI == allUsersGroup))
 }
 
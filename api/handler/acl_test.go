@@ -8,6 +8,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	errorsStd "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +17,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
@@ -1307,6 +1311,84 @@ func TestPutBucketACL(t *testing.T) {
 	checkLastRecords(t, tc, bktInfo, eacl.ActionDeny)
 }
 
+func TestBucketACLGrants(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName := "bucket-for-acl-grants"
+
+	box, key := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+	ownerID := hex.EncodeToString(key.PublicKey().Bytes())
+
+	readerBox, readerKey := createAccessBox(t)
+	readerID := hex.EncodeToString(readerKey.PublicKey().Bytes())
+
+	putBucketACL(t, hc, bktName, box, map[string]string{
+		api.AmzGrantRead:    "id=\"" + readerID + "\"",
+		api.AmzGrantReadACP: "id=\"" + readerID + "\"",
+	})
+
+	acl := getBucketACL(hc, bktName, readerBox, http.StatusOK)
+	require.ElementsMatch(t, []*Grant{{
+		Grantee:    &Grantee{ID: ownerID, Type: granteeCanonicalUser},
+		Permission: awsPermFullControl,
+	}, {
+		Grantee:    &Grantee{ID: readerID, Type: granteeCanonicalUser},
+		Permission: awsPermRead,
+	}, {
+		Grantee:    &Grantee{ID: readerID, Type: granteeCanonicalUser},
+		Permission: awsPermReadACP,
+	}}, acl.AccessControlList)
+
+	strangerBox, _ := createAccessBox(t)
+	getBucketACL(hc, bktName, strangerBox, http.StatusForbidden)
+
+	// reader has no WRITE_ACP permission
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	r.Header.Set(api.AmzACL, "private")
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, readerBox))
+	hc.Handler().PutBucketACLHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	invalid := &AccessControlPolicy{
+		Owner: Owner{ID: ownerID},
+		AccessControlList: []*Grant{{
+			Grantee:    &Grantee{ID: readerID, Type: granteeCanonicalUser},
+			Permission: "READ_ALL",
+		}},
+	}
+	w, r = prepareTestRequest(hc, bktName, "", invalid)
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().PutBucketACLHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+}
+
+func TestBucketACLConfigHistory(t *testing.T) {
+	tc := prepareHandlerContext(t)
+	bktName, objName := "bucket-for-acl-history", "object"
+
+	box, _ := createAccessBox(t)
+	bktInfo := createBucket(t, tc, bktName, box)
+
+	w, r := prepareTestPayloadRequest(tc, bktName, objName, bytes.NewReader([]byte("content")))
+	r.Header.Set(api.AmzACL, "public-read")
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	ctx := context.WithValue(tc.Context(), api.BoxData, box)
+	history, err := tc.Layer().GetBucketConfigHistory(ctx, bktInfo)
+	require.NoError(t, err)
+	require.Empty(t, history, "object acl must not be saved in bucket history")
+
+	putBucketACL(t, tc, bktName, box, map[string]string{api.AmzACL: "public-read"})
+
+	history, err = tc.Layer().GetBucketConfigHistory(ctx, bktInfo)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, layer.ConfigTypeACL, history[0].Type)
+	require.NotEmpty(t, history[0].PreviousValue)
+}
+
 func TestBucketPolicy(t *testing.T) {
 	hc := prepareHandlerContext(t)
 	bktName := "bucket-for-policy"
@@ -1314,17 +1396,7 @@ func TestBucketPolicy(t *testing.T) {
 	box, key := createAccessBox(t)
 	createBucket(t, hc, bktName, box)
 
-	bktPolicy := getBucketPolicy(hc, bktName)
-	for _, st := range bktPolicy.Statement {
-		if st.Effect == "Allow" {
-			require.Equal(t, hex.EncodeToString(key.PublicKey().Bytes()), st.Principal.CanonicalUser)
-			require.Equal(t, []string{arnAwsPrefix + bktName}, st.Resource)
-		} else {
-			require.Equal(t, allUsersWildcard, st.Principal.AWS)
-			require.Equal(t, "Deny", st.Effect)
-			require.Equal(t, []string{arnAwsPrefix + bktName}, st.Resource)
-		}
-	}
+	getBucketPolicy(hc, bktName, http.StatusNotFound)
 
 	newPolicy := &bucketPolicy{
 		Statement: []statement{{
@@ -1335,29 +1407,222 @@ func TestBucketPolicy(t *testing.T) {
 		}},
 	}
 
-	putBucketPolicy(hc, bktName, newPolicy, box, http.StatusInternalServerError)
+	putBucketPolicy(hc, bktName, newPolicy, box, http.StatusBadRequest)
 
 	newPolicy.Statement[0].Resource[0] = arnAwsPrefix + bktName
+	newPolicy.Statement = append(newPolicy.Statement, statement{
+		Effect:    "Deny",
+		Principal: principal{CanonicalUser: hex.EncodeToString(key.PublicKey().Bytes())},
+		Action:    []string{s3PutObject},
+		Resource:  []string{arnAwsPrefix + bktName + "/*"},
+	})
+	putBucketPolicy(hc, bktName, newPolicy, box, http.StatusOK)
+
+	bktPolicy := getBucketPolicy(hc, bktName, http.StatusOK)
+	require.Len(t, bktPolicy.Statement, 2)
+	require.Equal(t, policy.EffectAllow, bktPolicy.Statement[0].Effect)
+	require.Equal(t, policy.StringList{allUsersWildcard}, bktPolicy.Statement[0].Principal.AWS)
+	require.Equal(t, policy.StringList{s3GetObject}, bktPolicy.Statement[0].Action)
+	require.Equal(t, policy.EffectDeny, bktPolicy.Statement[1].Effect)
+	require.Equal(t, policy.StringList{hex.EncodeToString(key.PublicKey().Bytes())}, bktPolicy.Statement[1].Principal.CanonicalUser)
+
+	deleteBucketPolicy(hc, bktName, box)
+	getBucketPolicy(hc, bktName, http.StatusNotFound)
+}
+
+func TestBucketPolicyConfigHistory(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName := "bucket-for-policy-history"
+
+	box, _ := createAccessBox(t)
+	bktInfo := createBucket(t, hc, bktName, box)
+
+	newPolicy := &bucketPolicy{
+		Statement: []statement{{
+			Effect:    "Allow",
+			Principal: principal{AWS: allUsersWildcard},
+			Action:    []string{s3GetObject},
+			Resource:  []string{arnAwsPrefix + bktName + "/first"},
+		}},
+	}
+	putBucketPolicy(hc, bktName, newPolicy, box, http.StatusOK)
+
+	newPolicy.Statement[0].Resource[0] = arnAwsPrefix + bktName + "/second"
 	putBucketPolicy(hc, bktName, newPolicy, box, http.StatusOK)
 
-	bktPolicy = getBucketPolicy(hc, bktName)
-	for _, st := range bktPolicy.Statement {
-		if st.Effect == "Allow" && st.Principal.AWS == allUsersWildcard {
-			require.Equal(t, []string{arnAwsPrefix + bktName}, st.Resource)
-			require.ElementsMatch(t, []string{s3GetObject, s3ListBucket}, st.Action)
+	deleteBucketPolicy(hc, bktName, box)
+
+	ctx := context.WithValue(hc.Context(), api.BoxData, box)
+	history, err := hc.Layer().GetBucketConfigHistory(ctx, bktInfo)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	for _, change := range history {
+		require.Equal(t, layer.ConfigTypePolicy, change.Type)
+	}
+	require.Empty(t, history[0].PreviousValue)
+	require.Contains(t, history[0].Value, bktName+"/first")
+	require.Contains(t, history[1].PreviousValue, bktName+"/first")
+	require.Contains(t, history[1].Value, bktName+"/second")
+	require.Contains(t, history[2].PreviousValue, bktName+"/second")
+	require.Empty(t, history[2].Value)
+}
+
+func TestCheckBucketPolicy(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName, objName := "bucket-for-policy-check", "object"
+
+	box, key := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+
+	otherBox, _ := createAccessBox(t)
+
+	newPolicy := &bucketPolicy{
+		Statement: []statement{{
+			Effect:    "Deny",
+			Principal: principal{AWS: allUsersWildcard},
+			Action:    []string{"s3:*"},
+			Resource:  []string{arnAwsPrefix + bktName, arnAwsPrefix + bktName + "/*"},
+		}, {
+			Effect:    "Deny",
+			Principal: principal{CanonicalUser: hex.EncodeToString(key.PublicKey().Bytes())},
+			Action:    []string{s3PutObject},
+			Resource:  []string{arnAwsPrefix + bktName + "/" + objName},
+		}},
+	}
+	putBucketPolicy(hc, bktName, newPolicy, box, http.StatusOK)
+
+	checkBucketPolicy(hc, bktName, objName, "GetObject", otherBox, http.StatusForbidden)
+	checkBucketPolicy(hc, bktName, "", "ListObjectsV2", nil, http.StatusForbidden)
+	checkBucketPolicy(hc, bktName, objName, "PutObject", box, http.StatusForbidden)
+	checkBucketPolicy(hc, bktName, "", "DeleteBucketPolicy", otherBox, http.StatusForbidden)
+
+	// owner is never locked out of the policy management
+	checkBucketPolicy(hc, bktName, "", "DeleteBucketPolicy", box, http.StatusOK)
+	deleteBucketPolicy(hc, bktName, box)
+
+	checkBucketPolicy(hc, bktName, objName, "GetObject", otherBox, http.StatusOK)
+}
+
+// faultyPolicyClient fails to read bucket policies.
+type faultyPolicyClient struct {
+	layer.Client
+	err error
+}
+
+func (c *faultyPolicyClient) GetBucketPolicy(context.Context, *data.BucketInfo) (*policy.Policy, error) {
+	return nil, c.err
+}
+
+// faultySettingsClient fails to read bucket settings.
+type faultySettingsClient struct {
+	layer.Client
+	err error
+}
+
+func (c *faultySettingsClient) GetBucketSettings(context.Context, *data.BucketInfo) (*data.BucketSettings, error) {
+	return nil, c.err
+}
+
+func TestCheckBucketPolicySettingsFailure(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName, objName := "bucket-for-settings-failure", "object"
+
+	box, _ := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+
+	// flags of the bucket can't be checked, so requests are denied
+	hc.h.obj = &faultySettingsClient{Client: hc.h.obj, err: errorsStd.New("tree service is unavailable")}
+	checkBucketPolicy(hc, bktName, objName, "GetObject", box, http.StatusInternalServerError)
+	checkBucketPolicy(hc, bktName, objName, "DeleteObject", box, http.StatusInternalServerError)
+}
+
+func TestCheckBucketPolicyFailure(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName, objName := "bucket-for-policy-failure", "object"
+
+	box, _ := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+
+	hc.h.obj = &faultyPolicyClient{Client: hc.h.obj, err: errorsStd.New("tree service is unavailable")}
+	checkBucketPolicy(hc, bktName, objName, "GetObject", box, http.StatusInternalServerError)
+	checkBucketPolicy(hc, bktName, "", "ListObjectsV2", nil, http.StatusInternalServerError)
+
+	// missing policy doesn't deny requests
+	hc.h.obj.(*faultyPolicyClient).err = errors.GetAPIError(errors.ErrNoSuchBucketPolicy)
+	checkBucketPolicy(hc, bktName, objName, "GetObject", box, http.StatusOK)
+}
+
+func TestBucketFlags(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName, objName := "bucket-for-flags", "object"
+
+	bktInfo := createTestBucket(hc, bktName)
+	putObject(t, hc, bktName, objName)
+
+	checkFlags := func(method, route string, code errors.ErrorCode) {
+		w, r := prepareTestRequest(hc, bktName, objName, nil)
+		r.Method = method
+		api.GetReqInfo(r.Context()).API = route
+
+		allowed := hc.Handler().CheckBucketPolicy(w, r)
+		if code == 0 {
+			require.True(t, allowed, route)
+			return
 		}
+		require.False(t, allowed, route)
+		assertS3Error(t, w, errors.GetAPIError(code))
+	}
+
+	settings, err := hc.Layer().GetBucketSettings(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	settings.ReadOnly = true
+	require.NoError(t, hc.Layer().PutBucketSettings(hc.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: settings}))
+
+	checkFlags(http.MethodGet, "GetObject", 0)
+	checkFlags(http.MethodPost, "SelectObjectContent", 0)
+	checkFlags(http.MethodPut, "PutObject", errors.ErrBucketReadOnly)
+	checkFlags(http.MethodPut, "PutBucketPolicy", errors.ErrBucketReadOnly)
+	checkFlags(http.MethodDelete, "DeleteObject", errors.ErrBucketReadOnly)
+
+	settings.ReadOnly, settings.Frozen = false, true
+	require.NoError(t, hc.Layer().PutBucketSettings(hc.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: settings}))
+
+	checkFlags(http.MethodPut, "PutObject", 0)
+	checkFlags(http.MethodDelete, "DeleteObject", errors.ErrBucketFrozen)
+	checkFlags(http.MethodPost, "DeleteMultipleObjects", errors.ErrBucketFrozen)
+
+	// new objects can be uploaded to the frozen bucket, existing ones can't be overwritten
+	putObject(t, hc, bktName, "new-object")
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader([]byte("content")))
+	hc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrBucketFrozen))
+}
+
+func checkBucketPolicy(hc *handlerContext, bktName, objName, route string, box *accessbox.Box, status int) {
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	if box != nil {
+		r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
 	}
+	api.GetReqInfo(r.Context()).API = route
+
+	allowed := hc.Handler().CheckBucketPolicy(w, r)
+	require.Equal(hc.t, status == http.StatusOK, allowed)
+	assertStatus(hc.t, w, status)
 }
 
-func getBucketPolicy(hc *handlerContext, bktName string) *bucketPolicy {
+func getBucketPolicy(hc *handlerContext, bktName string, status int) *policy.Policy {
 	w, r := prepareTestRequest(hc, bktName, "", nil)
 	hc.Handler().GetBucketPolicyHandler(w, r)
+	assertStatus(hc.t, w, status)
+
+	if status != http.StatusOK {
+		return nil
+	}
 
-	assertStatus(hc.t, w, http.StatusOK)
-	policy := &bucketPolicy{}
-	err := json.NewDecoder(w.Result().Body).Decode(policy)
+	bktPolicy, err := policy.Parse(w.Result().Body)
 	require.NoError(hc.t, err)
-	return policy
+	return bktPolicy
 }
 
 func putBucketPolicy(hc *handlerContext, bktName string, bktPolicy *bucketPolicy, box *accessbox.Box, status int) {
@@ -1371,6 +1636,28 @@ func putBucketPolicy(hc *handlerContext, bktName string, bktPolicy *bucketPolicy
 	assertStatus(hc.t, w, status)
 }
 
+func deleteBucketPolicy(hc *handlerContext, bktName string, box *accessbox.Box) {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().DeleteBucketPolicyHandler(w, r)
+	assertStatus(hc.t, w, http.StatusNoContent)
+}
+
+func getBucketACL(hc *handlerContext, bktName string, box *accessbox.Box, status int) *AccessControlPolicy {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().GetBucketACLHandler(w, r)
+	assertStatus(hc.t, w, status)
+
+	if status != http.StatusOK {
+		return nil
+	}
+
+	acl := &AccessControlPolicy{}
+	readResponse(hc.t, w, status, acl)
+	return acl
+}
+
 func checkLastRecords(t *testing.T, tc *handlerContext, bktInfo *data.BucketInfo, action eacl.Action) {
 	bktACL, err := tc.Layer().GetBucketACL(tc.Context(), bktInfo)
 	require.NoError(t, err)
@@ -1388,7 +1675,7 @@ func checkLastRecords(t *testing.T, tc *handlerContext, bktInfo *data.BucketInfo
 	}
 }
 
-func createAccessBox(t *testing.T) (*accessbox.Box, *keys.PrivateKey) {
+func createAccessBox(t testing.TB) (*accessbox.Box, *keys.PrivateKey) {
 	key, err := keys.NewPrivateKey()
 	require.NoError(t, err)
 
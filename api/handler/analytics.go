@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+// maxAnalyticsConfigurationsList is the number of analytics configurations returned by a list request as AWS S3 does.
+const maxAnalyticsConfigurationsList = 100
+
+// ListAnalyticsConfigurationsResult is a response of ListBucketAnalyticsConfigurations request.
+type ListAnalyticsConfigurationsResult struct {
+	XMLName                    struct{}                      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAnalyticsConfigurationsResult" json:"-"`
+	AnalyticsConfigurationList []data.AnalyticsConfiguration `xml:"AnalyticsConfiguration"`
+	IsTruncated                bool                          `xml:"IsTruncated"`
+	ContinuationToken          string                        `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken      string                        `xml:"NextContinuationToken,omitempty"`
+}
+
+func (h *handler) GetBucketAnalyticsConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketAnalyticsConfiguration(r.Context(), bktInfo, r.URL.Query().Get("id"))
+	if err != nil {
+		h.logAndSendError(w, "could not get analytics configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode analytics configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) ListBucketAnalyticsConfigurationsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	configurations, err := h.obj.ListBucketAnalyticsConfigurations(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not list analytics configurations", reqInfo, err)
+		return
+	}
+
+	// configurations are sorted by id, the continuation token is the id of the last returned configuration
+	token := r.URL.Query().Get("continuation-token")
+	start := sort.Search(len(configurations), func(i int) bool {
+		return configurations[i].ID > token
+	})
+	configurations = configurations[start:]
+
+	resp := &ListAnalyticsConfigurationsResult{
+		AnalyticsConfigurationList: configurations,
+		ContinuationToken:          token,
+	}
+	if len(configurations) > maxAnalyticsConfigurationsList {
+		resp.AnalyticsConfigurationList = configurations[:maxAnalyticsConfigurationsList]
+		resp.IsTruncated = true
+		resp.NextContinuationToken = resp.AnalyticsConfigurationList[maxAnalyticsConfigurationsList-1].ID
+	}
+
+	if err = api.EncodeToResponse(w, resp); err != nil {
+		h.logAndSendError(w, "could not encode analytics configurations to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketAnalyticsConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := new(data.AnalyticsConfiguration)
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "could not parse analytics configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if id := r.URL.Query().Get("id"); id != conf.ID {
+		h.logAndSendError(w, "invalid analytics id", reqInfo, errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+			fmt.Errorf("id '%s' doesn't match configuration id '%s'", id, conf.ID)))
+		return
+	}
+
+	p := &layer.PutBucketAnalyticsParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+		CopiesNumber:  h.cfg.CopiesNumber,
+	}
+
+	if err = h.obj.PutBucketAnalyticsConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not put analytics configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketAnalyticsConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketAnalyticsConfiguration(r.Context(), bktInfo, r.URL.Query().Get("id")); err != nil {
+		h.logAndSendError(w, "could not delete analytics configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
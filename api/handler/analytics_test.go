@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketAnalytics(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, dstBktName := "bucket-for-analytics", "bucket-for-analytics-exports"
+	bktInfo := createTestBucket(hc, bktName)
+	createTestBucket(hc, dstBktName)
+
+	putObjectContent(hc, bktName, "logs/a", "content")
+	putObjectContent(hc, bktName, "data/skipped", "content")
+
+	query := url.Values{"analytics": []string{""}, "id": []string{"logs"}}
+	w, r := prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketAnalyticsConfigurationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchConfiguration))
+
+	conf := &data.AnalyticsConfiguration{
+		ID:     "logs",
+		Filter: &data.AnalyticsFilter{Prefix: "logs/"},
+		StorageClassAnalysis: data.AnalyticsStorageClassAnalysis{DataExport: &data.AnalyticsDataExport{
+			OutputSchemaVersion: data.AnalyticsSchemaV1,
+			Destination: data.AnalyticsExportDestination{S3BucketDestination: data.AnalyticsS3BucketDestination{
+				Bucket: "arn:aws:s3:::" + dstBktName,
+				Format: data.AnalyticsFormatCSV,
+				Prefix: "analytics",
+			}},
+		}},
+	}
+	w, r = prepareTestFullRequest(hc, bktName, "", query, conf)
+	hc.Handler().PutBucketAnalyticsConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	tagged := &data.AnalyticsConfiguration{ID: "tagged", Filter: &data.AnalyticsFilter{Tag: &data.LifecycleTag{Key: "k", Value: "v"}}}
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"analytics": []string{""}, "id": []string{tagged.ID}}, tagged)
+	hc.Handler().PutBucketAnalyticsConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusNotImplemented)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketAnalyticsConfigurationHandler(w, r)
+	actual := &data.AnalyticsConfiguration{}
+	parseTestResponse(t, w, actual)
+	require.Equal(t, conf.Filter, actual.Filter)
+	require.Equal(t, conf.StorageClassAnalysis, actual.StorageClassAnalysis)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"analytics": []string{""}}, nil)
+	hc.Handler().ListBucketAnalyticsConfigurationsHandler(w, r)
+	list := &ListAnalyticsConfigurationsResult{}
+	parseTestResponse(t, w, list)
+	require.Len(t, list.AnalyticsConfigurationList, 1)
+
+	written, err := hc.Layer().WriteAnalyticsExports(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, 1, written)
+
+	// the export of the current day is written once
+	written, err = hc.Layer().WriteAnalyticsExports(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Zero(t, written)
+
+	keys := listObjectKeys(t, hc, dstBktName)
+	require.Len(t, keys, 1)
+	require.True(t, strings.HasPrefix(keys[0], "analytics/"+bktName+"/logs/"))
+
+	records, err := csv.NewReader(strings.NewReader(getObjectContent(t, hc, dstBktName, keys[0]))).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "ObjectCount", records[0][5])
+	require.Equal(t, []string{"logs", "logs/", "STANDARD", "000-014", "1"}, records[1][1:6])
+	require.Equal(t, "ALL", records[len(records)-1][4])
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().DeleteBucketAnalyticsConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketAnalyticsConfigurationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchConfiguration))
+}
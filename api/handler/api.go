@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"html/template"
+	"net/http"
 	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"go.uber.org/zap"
 )
 
@@ -14,8 +21,12 @@ type (
 	handler struct {
 		log         *zap.Logger
 		obj         layer.Client
+		lifecycle   layer.LifecycleService
+		replication layer.ReplicationService
 		notificator Notificator
 		cfg         *Config
+
+		downloadTokens *downloadTokens
 	}
 
 	Notificator interface {
@@ -29,6 +40,45 @@ type (
 		DefaultMaxAge      int
 		NotificatorEnabled bool
 		CopiesNumber       uint32
+		SOSAPIEnabled      bool
+		// StorageClasses are numbers of object copies by names of storage classes accepted in x-amz-storage-class
+		// header besides STANDARD, which uses CopiesNumber.
+		StorageClasses map[string]uint32
+		// Features are feature flags of the deployment, nil value enables the default features.
+		Features *features.Registry
+		// PresignNonces are nonces of presigned URLs shared with the auth center, nil value disables nonces.
+		PresignNonces *auth.PresignNonces
+		// ListingTemplate is a template of HTML listings for browsers, nil value disables HTML listings.
+		ListingTemplate *template.Template
+		// Scanner scans new objects for malware, nil value disables scanning.
+		Scanner Scanner
+		// CompleteKeepAlive is an interval of whitespace written to the response of the long
+		// CompleteMultipartUpload, zero value disables it.
+		CompleteKeepAlive time.Duration
+		// MFA validates codes of MFA devices for buckets with MFA delete, nil value disables MFA delete.
+		MFA MFAValidator
+		// Operators recognizes requests of gateway operators, nil value means there are no operators.
+		Operators Operators
+		// PreserveMetadataCase keeps the case of user metadata keys instead of lowercasing them as AWS S3 does.
+		PreserveMetadataCase bool
+	}
+
+	// MFAValidator validates one-time codes of MFA devices sent in x-amz-mfa header.
+	MFAValidator interface {
+		// Validate checks the code of the MFA device with the serial number, the device must belong to the owner.
+		Validate(owner user.ID, serial, code string) error
+	}
+
+	// Operators recognizes requests made with credentials of gateway operators.
+	Operators interface {
+		// Allowed checks whether the request is made by an operator.
+		Allowed(r *http.Request) bool
+	}
+
+	// Scanner scans payloads of new objects in background and quarantines infected ones.
+	Scanner interface {
+		// Enqueue schedules scanning of the created object.
+		Enqueue(ctx context.Context, bktInfo *data.BucketInfo, info *data.NotificationInfo)
 	}
 
 	PlacementPolicy interface {
@@ -64,7 +114,11 @@ func New(log *zap.Logger, obj layer.Client, notificator Notificator, cfg *Config
 	return &handler{
 		log:         log,
 		obj:         obj,
+		lifecycle:   obj,
+		replication: obj,
 		cfg:         cfg,
 		notificator: notificator,
+
+		downloadTokens: newDownloadTokens(),
 	}, nil
 }
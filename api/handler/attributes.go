@@ -23,6 +23,9 @@ type (
 	}
 
 	Checksum struct {
+		ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+		ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+		ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
 		ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
 	}
 
@@ -36,9 +39,9 @@ type (
 	}
 
 	Part struct {
-		ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
-		PartNumber     int    `xml:"PartNumber,omitempty"`
-		Size           int    `xml:"Size,omitempty"`
+		Checksum
+		PartNumber int `xml:"PartNumber,omitempty"`
+		Size       int `xml:"Size,omitempty"`
 	}
 
 	GetObjectAttributesArgs struct {
@@ -186,11 +189,15 @@ func encodeToObjectAttributesResponse(info *data.ObjectInfo, p *GetObjectAttribu
 		case eTag:
 			resp.ETag = info.HashSum
 		case storageClass:
-			resp.StorageClass = "STANDARD"
+			resp.StorageClass = info.StorageClass()
 		case objectSize:
-			resp.ObjectSize = info.Size
+			size, err := plainObjectSize(info)
+			if err != nil {
+				return nil, err
+			}
+			resp.ObjectSize = size
 		case checksum:
-			resp.Checksum = &Checksum{ChecksumSHA256: info.HashSum}
+			resp.Checksum = newChecksum(info.Headers[layer.AttributeChecksumAlgorithm], info.Headers[layer.AttributeChecksum])
 		case objectParts:
 			parts, err := formUploadAttributes(info, p.MaxParts, p.PartNumberMarker)
 			if err != nil {
@@ -212,6 +219,13 @@ func formUploadAttributes(info *data.ObjectInfo, maxParts, marker int) (*ObjectP
 	}
 
 	partInfos := strings.Split(completedParts, ",")
+	// checksums are stored if the parts were uploaded with the checksum algorithm
+	var checksums []string
+	if val := info.Headers[layer.AttributePartsChecksums]; val != "" {
+		checksums = strings.Split(val, ",")
+	}
+	algorithm := info.Headers[layer.AttributeChecksumAlgorithm]
+
 	parts := make([]Part, len(partInfos))
 	for i, p := range partInfos {
 		part, err := layer.ParseCompletedPartHeader(p)
@@ -219,9 +233,13 @@ func formUploadAttributes(info *data.ObjectInfo, maxParts, marker int) (*ObjectP
 			return nil, fmt.Errorf("invalid completed part: %w", err)
 		}
 		parts[i] = Part{
-			PartNumber:     part.PartNumber,
-			Size:           int(part.Size),
-			ChecksumSHA256: part.ETag,
+			PartNumber: part.PartNumber,
+			Size:       int(part.Size),
+		}
+		if len(checksums) == len(partInfos) {
+			if cs := newChecksum(algorithm, checksums[i]); cs != nil {
+				parts[i].Checksum = *cs
+			}
 		}
 	}
 
@@ -255,3 +273,39 @@ func formUploadAttributes(info *data.ObjectInfo, maxParts, marker int) (*ObjectP
 
 	return res, nil
 }
+
+// newChecksum returns the checksum of the algorithm, nil is returned if there is no checksum.
+func newChecksum(algorithm, value string) *Checksum {
+	if value == "" {
+		return nil
+	}
+
+	res := new(Checksum)
+	switch algorithm {
+	case layer.ChecksumCRC32:
+		res.ChecksumCRC32 = value
+	case layer.ChecksumCRC32C:
+		res.ChecksumCRC32C = value
+	case layer.ChecksumSHA1:
+		res.ChecksumSHA1 = value
+	case layer.ChecksumSHA256:
+		res.ChecksumSHA256 = value
+	default:
+		return nil
+	}
+
+	return res
+}
+
+// plainObjectSize returns the size of the object payload, the decrypted size is returned for encrypted objects.
+func plainObjectSize(info *data.ObjectInfo) (int64, error) {
+	if !layer.FormEncryptionInfo(info.Headers).Enabled {
+		return info.Size, nil
+	}
+
+	size, err := strconv.ParseInt(info.Headers[layer.AttributeDecryptedSize], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid decrypted size header: %w", err)
+	}
+	return size, nil
+}
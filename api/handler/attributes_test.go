@@ -1,10 +1,17 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash/crc32"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,12 +32,105 @@ func TestGetObjectPartsAttributes(t *testing.T) {
 	etag, _ := uploadPart(hc, bktName, objMultipartName, multipartUpload.UploadID, 1, partSize)
 	completeMultipartUpload(hc, bktName, objMultipartName, multipartUpload.UploadID, []string{etag})
 
-	result = getObjectAttributes(hc, bktName, objMultipartName, objectParts)
+	result = getObjectAttributes(hc, bktName, objMultipartName, objectParts, checksum)
 	require.NotNil(t, result.ObjectParts)
 	require.Len(t, result.ObjectParts.Parts, 1)
-	require.Equal(t, etag, result.ObjectParts.Parts[0].ChecksumSHA256)
+	require.Empty(t, result.ObjectParts.Parts[0].ChecksumSHA256)
 	require.Equal(t, partSize, result.ObjectParts.Parts[0].Size)
 	require.Equal(t, 1, result.ObjectParts.PartsCount)
+	require.Nil(t, result.Checksum)
+}
+
+func TestGetObjectChecksumAttributes(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName, objMultipartName := "bucket-get-checksum-attributes", "object", "object-multipart"
+	createTestBucket(hc, bktName)
+
+	content := []byte("content")
+	sum := sha256.Sum256(content)
+	contentChecksum := base64.StdEncoding.EncodeToString(sum[:])
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader(content))
+	r.Header.Set(api.AmzChecksumPrefix+layer.ChecksumSHA256, base64.StdEncoding.EncodeToString(make([]byte, sha256.Size)))
+	hc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrBadDigest))
+
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader(content))
+	r.Header.Set(api.AmzChecksumPrefix+layer.ChecksumSHA256, contentChecksum)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, contentChecksum, w.Header().Get(api.AmzChecksumPrefix+layer.ChecksumSHA256))
+
+	result := getObjectAttributes(hc, bktName, objName, eTag, checksum, objectSize, storageClass, objectParts)
+	require.Equal(t, w.Header().Get(api.ETag), result.ETag)
+	require.NotNil(t, result.Checksum)
+	require.Equal(t, contentChecksum, result.Checksum.ChecksumSHA256)
+	require.EqualValues(t, len(content), result.ObjectSize)
+	require.Equal(t, "STANDARD", result.StorageClass)
+	require.Nil(t, result.ObjectParts)
+
+	multipartUpload := createMultipartUpload(hc, bktName, objMultipartName, map[string]string{api.AmzChecksumAlgorithm: layer.ChecksumSHA256})
+	w = uploadPartWithHeaders(hc, bktName, objMultipartName, multipartUpload.UploadID, 1, content,
+		map[string]string{api.AmzChecksumPrefix + layer.ChecksumSHA256: contentChecksum})
+	assertStatus(t, w, http.StatusOK)
+	completeMultipartUpload(hc, bktName, objMultipartName, multipartUpload.UploadID, []string{w.Header().Get(api.ETag)})
+
+	compositeSum := sha256.Sum256(sum[:])
+	result = getObjectAttributes(hc, bktName, objMultipartName, checksum, objectParts)
+	require.NotNil(t, result.Checksum)
+	require.Equal(t, base64.StdEncoding.EncodeToString(compositeSum[:])+"-1", result.Checksum.ChecksumSHA256)
+	require.NotNil(t, result.ObjectParts)
+	require.Len(t, result.ObjectParts.Parts, 1)
+	require.Equal(t, contentChecksum, result.ObjectParts.Parts[0].ChecksumSHA256)
+	require.Equal(t, len(content), result.ObjectParts.Parts[0].Size)
+}
+
+func TestGetObjectChecksumMode(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-checksum-mode", "object"
+	createTestBucket(hc, bktName)
+
+	content := []byte("content")
+	sum := crc32.ChecksumIEEE(content)
+	contentChecksum := base64.StdEncoding.EncodeToString([]byte{byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)})
+	checksumHeader := api.AmzChecksumPrefix + layer.ChecksumCRC32
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader(content))
+	r.Header.Set(checksumHeader, contentChecksum)
+	r.Header.Set(api.AmzSdkChecksumAlgorithm, layer.ChecksumSHA256)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader(content))
+	r.Header.Set(checksumHeader, contentChecksum)
+	r.Header.Set(api.AmzSdkChecksumAlgorithm, "crc32")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	for _, tc := range []struct {
+		name     string
+		headers  map[string]string
+		checksum string
+	}{
+		{name: "no mode"},
+		{name: "enabled", headers: map[string]string{api.AmzChecksumMode: checksumModeEnabled}, checksum: contentChecksum},
+		{name: "range", headers: map[string]string{api.AmzChecksumMode: checksumModeEnabled, "Range": "bytes=0-1"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, r := prepareTestRequest(hc, bktName, objName, nil)
+			setHeaders(r, tc.headers)
+			hc.Handler().HeadObjectHandler(w, r)
+			assertStatus(t, w, http.StatusOK)
+			require.Equal(t, tc.checksum, w.Header().Get(checksumHeader))
+
+			w, r = prepareTestRequest(hc, bktName, objName, nil)
+			setHeaders(r, tc.headers)
+			hc.Handler().GetObjectHandler(w, r)
+			require.Equal(t, tc.checksum, w.Header().Get(checksumHeader))
+		})
+	}
 }
 
 func getObjectAttributes(hc *handlerContext, bktName, objName string, attrs ...string) *GetObjectAttributesResponse {
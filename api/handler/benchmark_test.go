@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/workload"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// Environment variables to run BenchmarkWorkload against the gateway of the dev-env instead of the mocked backend.
+const (
+	benchEndpointEnv        = "S3_GW_BENCH_ENDPOINT"
+	benchAccessKeyIDEnv     = "S3_GW_BENCH_ACCESS_KEY_ID"
+	benchSecretAccessKeyEnv = "S3_GW_BENCH_SECRET_ACCESS_KEY"
+	benchBucketEnv          = "S3_GW_BENCH_BUCKET"
+)
+
+const benchBucket = "bench-bucket"
+
+var benchSizes = []int{1 << 10, 64 << 10, 1 << 20}
+
+// prepareBenchRouter creates S3 API routes served by the mocked NeoFS backend with the bucket for benchmarks.
+func prepareBenchRouter(b *testing.B) http.Handler {
+	hc := newHandlerContext(b)
+	box, _ := createAccessBox(b)
+
+	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
+	api.Attach(router, nil, nil, api.NewMaxClientsMiddleware(1024, 0), nil, nil, nil, nil, hc.Handler(), &conformanceCenter{box: box}, nil, zap.NewNop())
+
+	benchRequest(b, router, http.MethodPut, "/"+benchBucket, nil)
+	return router
+}
+
+func benchRequest(b *testing.B, h http.Handler, method, uri string, payload []byte) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, uri, bytes.NewReader(payload)))
+	if w.Code != http.StatusOK && w.Code != http.StatusNoContent {
+		b.Fatalf("%s %s: status %d: %s", method, uri, w.Code, w.Body.String())
+	}
+}
+
+func BenchmarkPutObject(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(sizeName(size), func(b *testing.B) {
+			router := prepareBenchRouter(b)
+			payload := make([]byte, size)
+
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				benchRequest(b, router, http.MethodPut, fmt.Sprintf("/%s/object-%d", benchBucket, i), payload)
+			}
+		})
+	}
+}
+
+func BenchmarkGetObject(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(sizeName(size), func(b *testing.B) {
+			router := prepareBenchRouter(b)
+			benchRequest(b, router, http.MethodPut, "/"+benchBucket+"/object", make([]byte, size))
+
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				benchRequest(b, router, http.MethodGet, "/"+benchBucket+"/object", nil)
+			}
+		})
+	}
+}
+
+func BenchmarkListObjectsV2(b *testing.B) {
+	router := prepareBenchRouter(b)
+	for i := 0; i < 1000; i++ {
+		benchRequest(b, router, http.MethodPut, fmt.Sprintf("/%s/dir-%d/object-%d", benchBucket, i%10, i), []byte("content"))
+	}
+
+	for _, tc := range []struct {
+		name  string
+		query string
+	}{
+		{name: "flat", query: "list-type=2&max-keys=100"},
+		{name: "delimiter", query: "list-type=2&delimiter=%2F"},
+		{name: "prefix", query: "list-type=2&prefix=dir-5%2F"},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				benchRequest(b, router, http.MethodGet, "/"+benchBucket+"?"+tc.query, nil)
+			}
+		})
+	}
+}
+
+// BenchmarkWorkload runs the mixed workload, every iteration is an operation. The workload is sent
+// to the gateway of the dev-env if S3_GW_BENCH_* environment variables are set.
+func BenchmarkWorkload(b *testing.B) {
+	for _, tc := range []struct {
+		name string
+		mix  map[workload.Op]int
+	}{
+		{name: "write-heavy", mix: map[workload.Op]int{workload.OpPut: 80, workload.OpGet: 15, workload.OpList: 5}},
+		{name: "read-heavy", mix: map[workload.Op]int{workload.OpPut: 10, workload.OpGet: 80, workload.OpList: 10}},
+		{name: "listing", mix: map[workload.Op]int{workload.OpPut: 30, workload.OpList: 70}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			endpoint, doer, bucket := "", workload.Doer(nil), benchBucket
+			if endpoint = os.Getenv(benchEndpointEnv); endpoint != "" {
+				doer = workload.NewSignedDoer(http.DefaultClient, os.Getenv(benchAccessKeyIDEnv), os.Getenv(benchSecretAccessKeyEnv), "us-east-1")
+				bucket = os.Getenv(benchBucketEnv)
+			} else {
+				doer = workload.HandlerDoer{Handler: prepareBenchRouter(b)}
+			}
+
+			gen, err := workload.New(strings.TrimSuffix(endpoint, "/"), doer, workload.Config{
+				Bucket:      bucket,
+				Operations:  b.N,
+				Concurrency: 8,
+				Mix:         tc.mix,
+				Prefixes:    16,
+				ListMaxKeys: 100,
+			})
+			require.NoError(b, err)
+
+			b.ResetTimer()
+			report, err := gen.Run(context.Background())
+			require.NoError(b, err)
+			b.StopTimer()
+
+			for _, stats := range report.Ops {
+				require.Zerof(b, stats.Errors, "%s errors", stats.Op)
+				b.ReportMetric(float64(stats.P99.Microseconds()), string(stats.Op)+"-p99-us")
+			}
+			b.ReportMetric(float64(report.Allocs)/float64(b.N), "allocs/op")
+
+			var buf strings.Builder
+			_, _ = report.WriteTo(&buf)
+			b.Log("\n" + buf.String())
+		})
+	}
+}
+
+func sizeName(size int) string {
+	if size >= 1<<20 {
+		return fmt.Sprintf("%dMiB", size>>20)
+	}
+	return fmt.Sprintf("%dKiB", size>>10)
+}
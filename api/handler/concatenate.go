@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+type (
+	// ConcatenateObjects is a request body of objects concatenation.
+	ConcatenateObjects struct {
+		XMLName xml.Name            `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ConcatenateObjects"`
+		Sources []ConcatenateSource `xml:"Source"`
+	}
+
+	// ConcatenateSource is a source object of concatenation. ETag is optional and
+	// is checked against the source object if it's set.
+	ConcatenateSource struct {
+		Key       string `xml:"Key"`
+		VersionID string `xml:"VersionId,omitempty"`
+		ETag      string `xml:"ETag,omitempty"`
+	}
+
+	// ConcatenateObjectsResponse is a response of objects concatenation.
+	ConcatenateObjectsResponse struct {
+		XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ConcatenateObjectsResult" json:"-"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+		Size    int64    `xml:"Size"`
+	}
+)
+
+// ConcatenateObjectsHandler creates a new object from payloads of existing objects of the bucket.
+func (h *handler) ConcatenateObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.checkFeature(r.Context(), bktInfo, features.Concatenate); err != nil {
+		h.logAndSendError(w, "feature is disabled", reqInfo, err)
+		return
+	}
+
+	reqBody := new(ConcatenateObjects)
+	if err = api.NewXMLDecoder(r.Body).Decode(reqBody); err != nil {
+		h.logAndSendError(w, "could not read concatenate objects xml", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+	if len(reqBody.Sources) == 0 || len(reqBody.Sources) > layer.UploadMaxPartNumber {
+		h.logAndSendError(w, "invalid number of sources", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	metadata := h.parseMetadata(r)
+	if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
+		metadata[api.ContentType] = contentType
+	}
+
+	copiesNumber, err := getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	if err != nil {
+		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
+	p := &layer.ConcatenateObjectsParams{
+		BktInfo:      bktInfo,
+		Object:       reqInfo.ObjectName,
+		Sources:      make([]*data.ObjectInfo, 0, len(reqBody.Sources)),
+		Header:       metadata,
+		CopiesNumber: copiesNumber,
+	}
+
+	if p.Lock, err = formObjectLock(r.Context(), bktInfo, settings.LockConfiguration, r.Header); err != nil {
+		h.logAndSendError(w, "could not form object lock", reqInfo, err)
+		return
+	}
+
+	for _, src := range reqBody.Sources {
+		srcObjInfo, err := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{
+			BktInfo:   bktInfo,
+			Object:    src.Key,
+			VersionID: src.VersionID,
+		})
+		if err != nil {
+			h.logAndSendError(w, "could not find source object", reqInfo, err, zap.String("source", src.Key))
+			return
+		}
+
+		if len(src.ETag) > 0 && strings.Trim(src.ETag, "\"") != srcObjInfo.HashSum {
+			h.logAndSendError(w, "source object etag mismatch", reqInfo,
+				errors.GetAPIError(errors.ErrPreconditionFailed), zap.String("source", src.Key))
+			return
+		}
+
+		p.Sources = append(p.Sources, srcObjInfo)
+	}
+
+	extendedObjInfo, err := h.obj.ConcatenateObjects(r.Context(), p)
+	if err != nil {
+		h.logAndSendError(w, "could not concatenate objects", reqInfo, err)
+		return
+	}
+	objInfo := extendedObjInfo.ObjectInfo
+
+	if settings.VersioningEnabled() {
+		w.Header().Set(api.AmzVersionID, objInfo.VersionID())
+	}
+
+	resp := ConcatenateObjectsResponse{
+		Bucket: objInfo.Bucket,
+		Key:    objInfo.Name,
+		ETag:   objInfo.HashSum,
+		Size:   objInfo.Size,
+	}
+
+	if err = api.EncodeToResponse(w, resp); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
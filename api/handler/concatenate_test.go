@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcatenateObjects(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-concatenation", "concatenated"
+	createTestBucket(hc, bktName)
+
+	contents := []string{"first-", "", "second-", "third"}
+	req := &ConcatenateObjects{}
+	for i, content := range contents {
+		srcName := "log-" + string(rune('a'+i))
+		putObjectContent(hc, bktName, srcName, content)
+		req.Sources = append(req.Sources, ConcatenateSource{Key: srcName})
+	}
+
+	w, r := prepareTestRequest(hc, bktName, objName, req)
+	r.Header.Set(api.ContentType, "text/plain")
+	hc.Handler().ConcatenateObjectsHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	resp := &ConcatenateObjectsResponse{}
+	parseTestResponse(t, w, resp)
+	require.Equal(t, objName, resp.Key)
+	require.EqualValues(t, len("first-second-third"), resp.Size)
+
+	content := getObjectRange(t, hc, bktName, objName, 0, int(resp.Size)-1)
+	require.Equal(t, "first-second-third", string(content))
+
+	attrs := getObjectAttributes(hc, bktName, objName, objectParts)
+	require.Equal(t, len(contents), attrs.ObjectParts.PartsCount)
+}
+
+func TestConcatenateObjectsErrors(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-concatenation", "concatenated"
+	createTestBucket(hc, bktName)
+	putObjectContent(hc, bktName, "source", "content")
+
+	for _, tc := range []struct {
+		name    string
+		sources []ConcatenateSource
+		err     errors.ErrorCode
+	}{
+		{name: "no sources", err: errors.ErrMalformedXML},
+		{name: "missed source", sources: []ConcatenateSource{{Key: "source"}, {Key: "missed"}}, err: errors.ErrNoSuchKey},
+		{name: "etag mismatch", sources: []ConcatenateSource{{Key: "source", ETag: "etag"}}, err: errors.ErrPreconditionFailed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, r := prepareTestRequest(hc, bktName, objName, &ConcatenateObjects{Sources: tc.sources})
+			hc.Handler().ConcatenateObjectsHandler(w, r)
+			assertS3Error(t, w, errors.GetAPIError(tc.err))
+		})
+	}
+}
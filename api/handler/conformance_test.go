@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// conformanceVectorsDir contains recorded S3 request/response exchanges, see conformanceVector.
+const conformanceVectorsDir = "testdata/conformance"
+
+// conformanceAny matches any value of the response header or XML element.
+const conformanceAny = "*"
+
+// conformanceVar is a placeholder of the value captured from the response and used in the next requests,
+// e.g. {{upload_id}}.
+var conformanceVar = regexp.MustCompile(`{{(\w+)}}`)
+
+// conformanceVector is a sequence of exchanges replayed against the gateway with the mocked NeoFS backend
+// on the empty storage. Field names of exchanges match the wire log, so captured requests
+// can be added to vectors as they are.
+type conformanceVector struct {
+	// Source is a test case the exchanges are recorded from, e.g. ceph/s3-tests test name.
+	Source    string                `json:"source"`
+	Exchanges []conformanceExchange `json:"exchanges"`
+}
+
+// conformanceExchange is a request and the expected response. Only specified response headers are checked,
+// the expected XML body may skip some elements of the response. Values of headers and XML elements are either exact values, '*'
+// or placeholders capturing the value.
+type conformanceExchange struct {
+	Method          string      `json:"method"`
+	URI             string      `json:"uri"`
+	RequestHeaders  http.Header `json:"request_headers"`
+	RequestBody     string      `json:"request_body"`
+	Status          int         `json:"status"`
+	ResponseHeaders http.Header `json:"response_headers"`
+	ResponseBody    string      `json:"response_body"`
+}
+
+// xmlNode is a generic XML element.
+type xmlNode struct {
+	name     string
+	text     string
+	children []*xmlNode
+}
+
+// conformanceCenter authenticates all requests with the same access box allowing to create buckets,
+// recorded signatures aren't verified.
+type conformanceCenter struct {
+	box *accessbox.Box
+}
+
+func (c *conformanceCenter) Authenticate(*http.Request) (*auth.Box, error) {
+	return &auth.Box{AccessBox: c.box}, nil
+}
+
+func (c *conformanceCenter) CacheStats() cache.Stats {
+	return cache.Stats{}
+}
+
+func TestConformanceVectors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(conformanceVectorsDir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			var vector conformanceVector
+			require.NoError(t, json.Unmarshal(data, &vector))
+			require.NotEmpty(t, vector.Exchanges)
+
+			replayConformanceVector(t, &vector)
+		})
+	}
+}
+
+func replayConformanceVector(t *testing.T, vector *conformanceVector) {
+	hc := prepareHandlerContext(t)
+	box, _ := createAccessBox(t)
+	center := &conformanceCenter{box: box}
+
+	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
+	api.Attach(router, nil, nil, api.NewMaxClientsMiddleware(1, 0), nil, nil, nil, nil, hc.Handler(), center, nil, zap.NewNop())
+
+	vars := make(map[string]string)
+	for i, ex := range vector.Exchanges {
+		body := expandConformanceVars(t, ex.RequestBody, vars)
+		r := httptest.NewRequest(ex.Method, expandConformanceVars(t, ex.URI, vars), strings.NewReader(body))
+		for key, values := range ex.RequestHeaders {
+			r.Header.Del(key)
+			for _, val := range values {
+				r.Header.Add(key, expandConformanceVars(t, val, vars))
+			}
+		}
+		if host := r.Header.Get("Host"); host != "" {
+			r.Host = host
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		resp := w.Result()
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		msg := fmt.Sprintf("exchange %d (%s %s) of %s, response: %s", i, ex.Method, ex.URI, vector.Source, respBody)
+		require.Equal(t, ex.Status, resp.StatusCode, msg)
+
+		for key, values := range ex.ResponseHeaders {
+			actual := resp.Header.Values(key)
+			require.Len(t, actual, len(values), msg)
+			for j := range values {
+				require.Truef(t, matchConformanceValue(values[j], actual[j], vars), "header %s: %s != %s", key, values[j], actual[j])
+			}
+		}
+
+		if ex.ResponseBody == "" {
+			continue
+		}
+		expected, err := parseXMLNode([]byte(ex.ResponseBody))
+		if err != nil {
+			require.Equal(t, ex.ResponseBody, string(respBody), msg)
+			continue
+		}
+		actual, err := parseXMLNode(respBody)
+		require.NoError(t, err, msg)
+		require.True(t, matchXMLNode(expected, actual, vars), msg)
+	}
+}
+
+func expandConformanceVars(t *testing.T, s string, vars map[string]string) string {
+	return conformanceVar.ReplaceAllStringFunc(s, func(placeholder string) string {
+		val, ok := vars[conformanceVar.FindStringSubmatch(placeholder)[1]]
+		require.Truef(t, ok, "unknown variable %s", placeholder)
+		return val
+	})
+}
+
+// matchConformanceValue checks the actual value and captures it if the expected value is a placeholder.
+func matchConformanceValue(expected, actual string, vars map[string]string) bool {
+	if expected == conformanceAny {
+		return true
+	}
+	if m := conformanceVar.FindStringSubmatch(expected); m != nil && m[0] == expected {
+		if val, ok := vars[m[1]]; ok {
+			return val == actual
+		}
+		vars[m[1]] = actual
+		return true
+	}
+	return expected == actual
+}
+
+// matchXMLNode checks that the actual element contains the expected children, repeated children
+// must be in the same order. Clients don't depend on the order of children with different names.
+func matchXMLNode(expected, actual *xmlNode, vars map[string]string) bool {
+	if expected.name != actual.name {
+		return false
+	}
+	if len(expected.children) == 0 {
+		return matchConformanceValue(expected.text, actual.text, vars)
+	}
+
+	// next is an index of the actual child to start the search of the element with the name from
+	next := make(map[string]int)
+	for _, child := range expected.children {
+		j := next[child.name]
+		for ; j < len(actual.children); j++ {
+			if child.name == actual.children[j].name {
+				break
+			}
+		}
+		if j == len(actual.children) || !matchXMLNode(child, actual.children[j], vars) {
+			return false
+		}
+		next[child.name] = j + 1
+	}
+	return true
+}
+
+func parseXMLNode(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: tok.Name.Local}
+			if len(stack) == 0 {
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) != 0 {
+				stack[len(stack)-1].text += strings.TrimSpace(string(tok))
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return root, nil
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAfterWrite(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	t.Run("put", func(t *testing.T) {
+		bktName, objName := "bucket-raw-put", "object"
+		createTestBucket(hc, bktName)
+		warmUpCaches(t, hc, bktName, objName)
+
+		putObjectContent(hc, bktName, objName, "content")
+		require.Equal(t, "content", getObjectContent(t, hc, bktName, objName))
+		require.Equal(t, []string{objName}, listObjectKeys(t, hc, bktName))
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		bktName, objName := "bucket-raw-overwrite", "object"
+		createTestBucket(hc, bktName)
+		putObjectContent(hc, bktName, objName, "content")
+		warmUpCaches(t, hc, bktName, objName)
+
+		putObjectContent(hc, bktName, objName, "new content")
+		require.Equal(t, "new content", getObjectContent(t, hc, bktName, objName))
+		headObject(t, hc, bktName, objName, nil, http.StatusOK)
+		require.Equal(t, []string{objName}, listObjectKeys(t, hc, bktName))
+	})
+
+	t.Run("delete unversioned", func(t *testing.T) {
+		bktName, objName := "bucket-raw-delete", "object"
+		createTestBucket(hc, bktName)
+		putObjectContent(hc, bktName, objName, "content")
+		warmUpCaches(t, hc, bktName, objName)
+
+		deleteObject(t, hc, bktName, objName, emptyVersion)
+		checkNotFound(t, hc, bktName, objName, emptyVersion)
+		require.Empty(t, listObjectKeys(t, hc, bktName))
+	})
+
+	t.Run("delete marker", func(t *testing.T) {
+		bktName, objName := "bucket-raw-delete-marker", "object"
+		createVersionedBucketAndObject(t, hc, bktName, objName)
+		warmUpCaches(t, hc, bktName, objName)
+
+		deleteObject(t, hc, bktName, objName, emptyVersion)
+		checkNotFound(t, hc, bktName, objName, emptyVersion)
+		require.Empty(t, listObjectKeys(t, hc, bktName))
+		require.Len(t, listVersions(t, hc, bktName).DeleteMarker, 1)
+	})
+
+	t.Run("delete latest version", func(t *testing.T) {
+		bktName, objName := "bucket-raw-delete-version", "object"
+		createTestBucket(hc, bktName)
+		putBucketVersioning(t, hc, bktName, true)
+		putObjectContent(hc, bktName, objName, "content")
+		putObjectContent(hc, bktName, objName, "new content")
+		warmUpCaches(t, hc, bktName, objName)
+
+		versions := listVersions(t, hc, bktName)
+		require.Len(t, versions.Version, 2)
+		latest := versions.Version[0]
+		require.True(t, latest.IsLatest)
+
+		deleteObject(t, hc, bktName, objName, latest.VersionID)
+		checkNotFound(t, hc, bktName, objName, latest.VersionID)
+		require.Equal(t, "content", getObjectContent(t, hc, bktName, objName))
+		require.Len(t, listVersions(t, hc, bktName).Version, 1)
+	})
+
+	t.Run("complete multipart upload", func(t *testing.T) {
+		bktName, objName := "bucket-raw-multipart", "object"
+		createTestBucket(hc, bktName)
+		putObjectContent(hc, bktName, objName, "content")
+		warmUpCaches(t, hc, bktName, objName)
+
+		multipartInfo := createMultipartUpload(hc, bktName, objName, nil)
+		etag, body := uploadPart(hc, bktName, objName, multipartInfo.UploadID, 1, 10)
+		completeMultipartUpload(hc, bktName, objName, multipartInfo.UploadID, []string{etag})
+
+		require.Equal(t, string(body), getObjectContent(t, hc, bktName, objName))
+		require.Equal(t, []string{objName}, listObjectKeys(t, hc, bktName))
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		bktName, srcName, dstName := "bucket-raw-copy", "source", "object"
+		createTestBucket(hc, bktName)
+		putObjectContent(hc, bktName, srcName, "new content")
+		putObjectContent(hc, bktName, dstName, "content")
+		warmUpCaches(t, hc, bktName, dstName)
+
+		copyObject(t, hc, bktName, srcName, dstName, CopyMeta{}, http.StatusOK)
+		require.Equal(t, "new content", getObjectContent(t, hc, bktName, dstName))
+	})
+}
+
+// warmUpCaches fills caches of the object and bucket listings, so stale data would be served after change.
+func warmUpCaches(t *testing.T, hc *handlerContext, bktName, objName string) {
+	query := make(url.Values)
+	query.Add(api.QueryVersionID, emptyVersion)
+	w, r := prepareTestFullRequest(hc, bktName, objName, query, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+
+	listObjectKeys(t, hc, bktName)
+	listVersions(t, hc, bktName)
+}
+
+func getObjectContent(t *testing.T, hc *handlerContext, bktName, objName string) string {
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	content, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func listObjectKeys(t *testing.T, hc *handlerContext, bktName string) []string {
+	var keys []string
+	for _, obj := range listObjectsV1(t, hc, bktName, "", "", "", -1).Contents {
+		keys = append(keys, obj.Key)
+	}
+	return keys
+}
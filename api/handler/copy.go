@@ -19,6 +19,10 @@ type copyObjectArgs struct {
 	Conditional       *conditionalArgs
 	MetadataDirective string
 	TaggingDirective  string
+	// EncryptionChanged is set if the object is copied with another customer key.
+	EncryptionChanged bool
+	// StorageClassChanged is set if the storage class of the copy is specified.
+	StorageClassChanged bool
 }
 
 const (
@@ -34,6 +38,9 @@ func path2BucketObject(path string) (string, string, error) {
 	if len(matches) != 2 {
 		return "", "", errors.GetAPIError(errors.ErrInvalidRequest)
 	}
+	if err := api.CheckObjectName(matches["object_name"]); err != nil {
+		return "", "", err
+	}
 
 	return matches["bucket_name"], matches["object_name"], nil
 }
@@ -84,12 +91,23 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.checkPublicACLHeaders(r, dstBktInfo); err != nil {
+		h.logAndSendError(w, "public object acl is blocked", reqInfo, err)
+		return
+	}
+
 	settings, err := h.obj.GetBucketSettings(r.Context(), dstBktInfo)
 	if err != nil {
 		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
 		return
 	}
 
+	if err = checkACLHeadersAllowed(settings, r); err != nil {
+		h.logAndSendError(w, "acl is disabled in the bucket", reqInfo, err)
+		return
+	}
+	containsACL = containsACL && !aclDisabled(settings)
+
 	if containsACL {
 		if sessionTokenEACL, err = getSessionTokenSetEACL(r.Context()); err != nil {
 			h.logAndSendError(w, "could not get eacl session token from a box", reqInfo, err)
@@ -116,7 +134,7 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if args.MetadataDirective == replaceDirective {
-		metadata = parseMetadata(r)
+		metadata = h.parseMetadata(r)
 	}
 
 	if args.TaggingDirective == replaceDirective {
@@ -142,13 +160,19 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	srcEncryptionParams, err := formCopySourceEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid copy source sse headers", reqInfo, err)
+		return
+	}
+
 	encryptionParams, err := formEncryptionParams(r)
 	if err != nil {
 		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
 		return
 	}
 
-	if err = encryptionParams.MatchObjectEncryption(layer.FormEncryptionInfo(srcObjInfo.Headers)); err != nil {
+	if err = srcEncryptionParams.MatchObjectEncryption(layer.FormEncryptionInfo(srcObjInfo.Headers)); err != nil {
 		h.logAndSendError(w, "encryption doesn't match object", reqInfo, errors.GetAPIError(errors.ErrBadRequest), zap.Error(err))
 		return
 	}
@@ -158,30 +182,35 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.obj.CheckQuarantine(r.Context(), srcObjPrm.BktInfo, srcObjInfo); err != nil {
+		h.logAndSendError(w, "could not check source object quarantine", reqInfo, err)
+		return
+	}
+
 	if metadata == nil {
-		if len(srcObjInfo.ContentType) > 0 {
-			srcObjInfo.Headers[api.ContentType] = srcObjInfo.ContentType
-		}
-		metadata = srcObjInfo.Headers
+		metadata = copyObjectMetadata(srcObjInfo)
 	} else if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
 		metadata[api.ContentType] = contentType
 	}
 
-	copiesNumber, err := getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	copiesNumber, err := h.formStorageClass(r.Header, metadata)
 	if err != nil {
-		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		h.logAndSendError(w, "invalid storage class", reqInfo, err)
 		return
 	}
 
 	params := &layer.CopyObjectParams{
-		SrcObject:   srcObjInfo,
-		ScrBktInfo:  srcObjPrm.BktInfo,
-		DstBktInfo:  dstBktInfo,
-		DstObject:   reqInfo.ObjectName,
-		SrcSize:     srcObjInfo.Size,
-		Header:      metadata,
-		Encryption:  encryptionParams,
-		CopiesNuber: copiesNumber,
+		SrcObject:     srcObjInfo,
+		ScrBktInfo:    srcObjPrm.BktInfo,
+		DstBktInfo:    dstBktInfo,
+		DstObject:     reqInfo.ObjectName,
+		SrcSize:       srcObjInfo.Size,
+		Header:        metadata,
+		SrcEncryption: srcEncryptionParams,
+		Encryption:    encryptionParams,
+		CopiesNuber:   copiesNumber,
+
+		BucketOwnerFullControl: isBucketOwnerFullControl(r),
 	}
 
 	params.Lock, err = formObjectLock(r.Context(), dstBktInfo, settings.LockConfiguration, r.Header)
@@ -191,13 +220,25 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	additional := []zap.Field{zap.String("src_bucket_name", srcBucket), zap.String("src_object_name", srcObject)}
-	extendedDstObjInfo, err := h.obj.CopyObject(r.Context(), params)
+	var extendedDstObjInfo *data.ExtendedObjectInfo
+	if isMetadataOnlyCopy(reqInfo, srcBucket, srcObject, versionID, settings, args) && params.Lock == nil {
+		// copying to itself with replaced metadata doesn't need payload re-storing
+		extendedDstObjInfo, err = h.obj.UpdateObjectMetadata(r.Context(), &layer.UpdateObjectMetadataParams{
+			BktInfo:  dstBktInfo,
+			Settings: settings,
+			Object:   reqInfo.ObjectName,
+			Header:   metadata,
+		})
+	} else {
+		extendedDstObjInfo, err = h.obj.CopyObject(r.Context(), params)
+	}
 	if err != nil {
 		h.logAndSendError(w, "couldn't copy object", reqInfo, err, additional...)
 		return
 	}
 	dstObjInfo := extendedDstObjInfo.ObjectInfo
 
+	h.setExpirationHeader(r.Context(), w.Header(), dstBktInfo, dstObjInfo, tagSet)
 	if err = api.EncodeToResponse(w, &CopyObjectResponse{LastModified: dstObjInfo.Created.UTC().Format(time.RFC3339), ETag: dstObjInfo.HashSum}); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err, additional...)
 		return
@@ -253,9 +294,7 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		h.log.Error("couldn't send notification: %w", zap.Error(err))
 	}
 
-	if encryptionParams.Enabled() {
-		addSSECHeaders(w.Header(), r.Header)
-	}
+	addEncryptionHeaders(w.Header(), r.Header, encryptionParams)
 }
 
 func isCopyingToItselfForbidden(reqInfo *api.ReqInfo, srcBucket string, srcObject string, settings *data.BucketSettings, args *copyObjectArgs) bool {
@@ -267,7 +306,21 @@ func isCopyingToItselfForbidden(reqInfo *api.ReqInfo, srcBucket string, srcObjec
 		return false
 	}
 
-	return args.MetadataDirective != replaceDirective
+	return args.MetadataDirective != replaceDirective && !args.EncryptionChanged && !args.StorageClassChanged
+}
+
+// isMetadataOnlyCopy checks if the latest version of the object of the unversioned bucket
+// is copied to itself with replaced metadata only.
+func isMetadataOnlyCopy(reqInfo *api.ReqInfo, srcBucket, srcObject, versionID string, settings *data.BucketSettings, args *copyObjectArgs) bool {
+	if reqInfo.BucketName != srcBucket || reqInfo.ObjectName != srcObject {
+		return false
+	}
+
+	if !settings.Unversioned() || (len(versionID) > 0 && versionID != data.UnversionedObjectVersionID) {
+		return false
+	}
+
+	return args.MetadataDirective == replaceDirective && !args.EncryptionChanged && !args.StorageClassChanged
 }
 
 func parseCopyObjectArgs(headers http.Header) (*copyObjectArgs, error) {
@@ -278,13 +331,19 @@ func parseCopyObjectArgs(headers http.Header) (*copyObjectArgs, error) {
 	}
 
 	if args.IfModifiedSince, err = parseHTTPTime(headers.Get(api.AmzCopyIfModifiedSince)); err != nil {
-		return nil, err
+		return nil, errors.GetAPIErrorWithError(errors.ErrInvalidArgument, err)
 	}
 	if args.IfUnmodifiedSince, err = parseHTTPTime(headers.Get(api.AmzCopyIfUnmodifiedSince)); err != nil {
-		return nil, err
+		return nil, errors.GetAPIErrorWithError(errors.ErrInvalidArgument, err)
 	}
 
-	copyArgs := &copyObjectArgs{Conditional: args}
+	copyArgs := &copyObjectArgs{
+		Conditional: args,
+		EncryptionChanged: headers.Get(api.AmzServerSideEncryptionCustomerKey) !=
+			headers.Get(api.AmzCopySourceServerSideEncryptionCustomerKey) ||
+			len(headers.Get(api.AmzServerSideEncryption)) > 0,
+		StorageClassChanged: len(headers.Get(api.AmzStorageClass)) > 0,
+	}
 
 	copyArgs.MetadataDirective = headers.Get(api.AmzMetadataDirective)
 	if !isValidDirective(copyArgs.MetadataDirective) {
@@ -303,3 +362,32 @@ func isValidDirective(directive string) bool {
 	return len(directive) == 0 ||
 		directive == replaceDirective || directive == copyDirective
 }
+
+// copyObjectMetadata returns user metadata of the source object for COPY directive.
+// Attributes describing payload of the source object (multipart parts, encryption)
+// are skipped, they are set by the layer for the new object if needed.
+func copyObjectMetadata(objInfo *data.ObjectInfo) map[string]string {
+	metadata := make(map[string]string, len(objInfo.Headers)+1)
+	for key, val := range objInfo.Headers {
+		switch key {
+		case layer.UploadCompletedParts,
+			layer.UploadCompletedID,
+			layer.AttributePartsChecksums,
+			layer.AttributeEncryptionAlgorithm,
+			layer.AttributeDecryptedSize,
+			layer.AttributeHMACSalt,
+			layer.AttributeHMACKey,
+			layer.AttributeWrappedKey,
+			layer.AttributeKMSKeyID,
+			data.AttributeStorageClass:
+			continue
+		}
+		metadata[key] = val
+	}
+
+	if len(objInfo.ContentType) > 0 {
+		metadata[api.ContentType] = objInfo.ContentType
+	}
+
+	return metadata
+}
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/xml"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,6 +67,79 @@ func TestCopyToItself(t *testing.T) {
 	copyObject(t, tc, bktName, objName, objName, copyMeta, http.StatusOK)
 }
 
+func TestCopyMultipartObjectMetadata(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName, objToCopy := "bucket-for-copy", "object-from-copy", "object-to-copy"
+	bktInfo := createTestBucket(tc, bktName)
+
+	headers := map[string]string{
+		api.ContentType:              "text/plain",
+		api.MetadataPrefix + "Color": "red",
+	}
+	multipartInfo := createMultipartUpload(tc, bktName, objName, headers)
+	etag, _ := uploadPart(tc, bktName, objName, multipartInfo.UploadID, 1, 10)
+	completeMultipartUpload(tc, bktName, objName, multipartInfo.UploadID, []string{"\"" + etag + "\""})
+
+	copyObject(t, tc, bktName, objName, objToCopy, CopyMeta{}, http.StatusOK)
+
+	srcInfo, err := tc.Layer().GetObjectInfo(tc.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: objName})
+	require.NoError(t, err)
+	require.NotContains(t, srcInfo.Headers, api.ContentType)
+	require.Contains(t, srcInfo.Headers, layer.UploadCompletedParts)
+
+	dstInfo, err := tc.Layer().GetObjectInfo(tc.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: objToCopy})
+	require.NoError(t, err)
+	require.Equal(t, "text/plain", dstInfo.ContentType)
+	require.Equal(t, "red", dstInfo.Headers["color"])
+	require.NotContains(t, dstInfo.Headers, layer.UploadCompletedParts)
+}
+
+func TestCopyObjectConditional(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-conditional-copy", "object"
+	_, objInfo := createBucketAndObject(hc, bktName, objName)
+
+	before := objInfo.Created.Add(-time.Minute).Format(http.TimeFormat)
+	after := objInfo.Created.Add(time.Minute).Format(http.TimeFormat)
+
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{name: "if-match", headers: map[string]string{api.AmzCopyIfMatch: objInfo.HashSum}, status: http.StatusOK},
+		{name: "quoted if-match", headers: map[string]string{api.AmzCopyIfMatch: `"` + objInfo.HashSum + `"`}, status: http.StatusOK},
+		{name: "if-match mismatch", headers: map[string]string{api.AmzCopyIfMatch: "etag"}, status: http.StatusPreconditionFailed},
+		{name: "if-none-match", headers: map[string]string{api.AmzCopyIfNoneMatch: "etag"}, status: http.StatusOK},
+		{name: "if-none-match mismatch", headers: map[string]string{api.AmzCopyIfNoneMatch: objInfo.HashSum}, status: http.StatusPreconditionFailed},
+		{name: "if-modified-since", headers: map[string]string{api.AmzCopyIfModifiedSince: before}, status: http.StatusOK},
+		{name: "if-modified-since mismatch", headers: map[string]string{api.AmzCopyIfModifiedSince: after}, status: http.StatusPreconditionFailed},
+		{name: "if-unmodified-since", headers: map[string]string{api.AmzCopyIfUnmodifiedSince: after}, status: http.StatusOK},
+		{name: "if-unmodified-since mismatch", headers: map[string]string{api.AmzCopyIfUnmodifiedSince: before}, status: http.StatusPreconditionFailed},
+		{name: "if-match and if-unmodified-since mismatch", headers: map[string]string{
+			api.AmzCopyIfMatch:           objInfo.HashSum,
+			api.AmzCopyIfUnmodifiedSince: before,
+		}, status: http.StatusOK},
+		{name: "if-none-match mismatch and if-modified-since", headers: map[string]string{
+			api.AmzCopyIfNoneMatch:     objInfo.HashSum,
+			api.AmzCopyIfModifiedSince: before,
+		}, status: http.StatusPreconditionFailed},
+		{name: "malformed date", headers: map[string]string{api.AmzCopyIfModifiedSince: "yesterday"}, status: http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, r := prepareTestRequest(hc, bktName, objName+"-copy", nil)
+			r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+			for key, val := range tc.headers {
+				r.Header.Set(key, val)
+			}
+			hc.Handler().CopyObjectHandler(w, r)
+			assertStatus(t, w, tc.status)
+		})
+	}
+}
+
 func copyObject(t *testing.T, tc *handlerContext, bktName, fromObject, toObject string, copyMeta CopyMeta, statusCode int) {
 	w, r := prepareTestRequest(tc, bktName, toObject, nil)
 	r.Header.Set(api.AmzCopySource, bktName+"/"+fromObject)
@@ -163,6 +240,14 @@ func TestSourceCopyRegexp(t *testing.T) {
 			path: "invalid+bucket/object",
 			err:  true,
 		},
+		{
+			path: "bucket/dir/../object",
+			err:  true,
+		},
+		{
+			path: "bucket/object\x00",
+			err:  true,
+		},
 		{
 			path: "invaliDBucket/object",
 			err:  true,
@@ -185,3 +270,61 @@ func TestSourceCopyRegexp(t *testing.T) {
 		})
 	}
 }
+
+func TestCopyObjectMetadataRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		preserveCase bool
+		expected     map[string]string
+	}{
+		{
+			name: "lowercase",
+			expected: map[string]string{
+				"X-Amz-Meta-my-key":            "Value With  Spaces ünïcode",
+				"X-Amz-Meta-content-type":      "text/html",
+				"X-Amz-Meta-s3-decrypted-size": "1",
+			},
+		},
+		{
+			name:         "preserved case",
+			preserveCase: true,
+			expected: map[string]string{
+				"X-Amz-Meta-My-Key":            "Value With  Spaces ünïcode",
+				"X-Amz-Meta-content-type":      "text/html",
+				"X-Amz-Meta-s3-decrypted-size": "1",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hc := prepareHandlerContext(t)
+			hc.Handler().cfg.PreserveMetadataCase = tc.preserveCase
+
+			bktName, objName, copyName := "bucket-for-metadata", "object", "object-copy"
+			createTestBucket(hc, bktName)
+
+			w, r := prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader([]byte("content")))
+			r.Header.Set(api.MetadataPrefix+"my-key", "Value With  Spaces ünïcode")
+			r.Header.Set(api.MetadataPrefix+"content-type", "text/html")
+			r.Header.Set(api.MetadataPrefix+"s3-decrypted-size", "1")
+			hc.Handler().PutObjectHandler(w, r)
+			assertStatus(t, w, http.StatusOK)
+
+			copyObject(t, hc, bktName, objName, copyName, CopyMeta{}, http.StatusOK)
+
+			for _, name := range []string{objName, copyName} {
+				w, r = prepareTestRequest(hc, bktName, name, nil)
+				hc.Handler().HeadObjectHandler(w, r)
+				assertStatus(t, w, http.StatusOK)
+
+				metadata := make(map[string]string)
+				for key, val := range w.Header() {
+					if strings.HasPrefix(strings.ToLower(key), strings.ToLower(api.MetadataPrefix)) {
+						metadata[key] = val[0]
+					}
+				}
+				require.Equal(t, tc.expected, metadata, name)
+				require.Equal(t, "7", w.Header().Get(api.ContentLength), name)
+			}
+		})
+	}
+}
@@ -72,6 +72,7 @@ func (h *handler) DeleteBucketCorsHandler(w http.ResponseWriter, r *http.Request
 
 	if err = h.obj.DeleteBucketCORS(r.Context(), bktInfo); err != nil {
 		h.logAndSendError(w, "could not delete cors", reqInfo, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
@@ -105,32 +106,21 @@ func (h *handler) AppendCORSHeaders(w http.ResponseWriter, r *http.Request) {
 
 	for _, rule := range cors.CORSRules {
 		for _, o := range rule.AllowedOrigins {
-			if o == origin {
-				for _, m := range rule.AllowedMethods {
-					if m == r.Method {
-						w.Header().Set(api.AccessControlAllowOrigin, origin)
-						w.Header().Set(api.AccessControlAllowMethods, strings.Join(rule.AllowedMethods, ", "))
-						w.Header().Set(api.AccessControlAllowCredentials, "true")
-						w.Header().Set(api.Vary, api.Origin)
-						return
-					}
-				}
+			if !originMatches(o, origin) || !sliceContains(rule.AllowedMethods, r.Method) {
+				continue
 			}
-			if o == wildcard {
-				for _, m := range rule.AllowedMethods {
-					if m == r.Method {
-						if withCredentials {
-							w.Header().Set(api.AccessControlAllowOrigin, origin)
-							w.Header().Set(api.AccessControlAllowCredentials, "true")
-							w.Header().Set(api.Vary, api.Origin)
-						} else {
-							w.Header().Set(api.AccessControlAllowOrigin, o)
-						}
-						w.Header().Set(api.AccessControlAllowMethods, strings.Join(rule.AllowedMethods, ", "))
-						return
-					}
-				}
+			if o == wildcard && !withCredentials {
+				w.Header().Set(api.AccessControlAllowOrigin, o)
+			} else {
+				w.Header().Set(api.AccessControlAllowOrigin, origin)
+				w.Header().Set(api.AccessControlAllowCredentials, "true")
+				w.Header().Set(api.Vary, api.Origin)
+			}
+			w.Header().Set(api.AccessControlAllowMethods, strings.Join(rule.AllowedMethods, ", "))
+			if rule.ExposeHeaders != nil {
+				w.Header().Set(api.AccessControlExposeHeaders, strings.Join(rule.ExposeHeaders, ", "))
 			}
+			return
 		}
 	}
 }
@@ -146,6 +136,7 @@ func (h *handler) Preflight(w http.ResponseWriter, r *http.Request) {
 	origin := r.Header.Get(api.Origin)
 	if origin == "" {
 		h.logAndSendError(w, "origin request header needed", reqInfo, errors.GetAPIError(errors.ErrBadRequest))
+		return
 	}
 
 	method := r.Header.Get(api.AccessControlRequestMethod)
@@ -168,13 +159,18 @@ func (h *handler) Preflight(w http.ResponseWriter, r *http.Request) {
 
 	for _, rule := range cors.CORSRules {
 		for _, o := range rule.AllowedOrigins {
-			if o == origin || o == wildcard {
+			if originMatches(o, origin) {
 				for _, m := range rule.AllowedMethods {
 					if m == method {
 						if !checkSubslice(rule.AllowedHeaders, headers) {
 							continue
 						}
-						w.Header().Set(api.AccessControlAllowOrigin, o)
+						if o == wildcard {
+							w.Header().Set(api.AccessControlAllowOrigin, o)
+						} else {
+							w.Header().Set(api.AccessControlAllowOrigin, origin)
+							w.Header().Set(api.Vary, api.Origin)
+						}
 						w.Header().Set(api.AccessControlAllowMethods, strings.Join(rule.AllowedMethods, ", "))
 						if headers != nil {
 							w.Header().Set(api.AccessControlAllowHeaders, requestHeaders)
@@ -200,6 +196,18 @@ func (h *handler) Preflight(w http.ResponseWriter, r *http.Request) {
 	h.logAndSendError(w, "Forbidden", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
 }
 
+// originMatches checks the origin of the request against the allowed origin of the CORS rule.
+// The allowed origin can contain one wildcard matching any substring, e.g. 'http://*.example.com'.
+func originMatches(allowed, origin string) bool {
+	i := strings.Index(allowed, wildcard)
+	if i < 0 {
+		return allowed == origin
+	}
+	prefix, suffix := allowed[:i], allowed[i+len(wildcard):]
+
+	return len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+}
+
 func checkSubslice(slice []string, subSlice []string) bool {
 	if sliceContains(slice, wildcard) {
 		return true
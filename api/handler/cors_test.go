@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketCORS(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-cors"
+	bktInfo := createTestBucket(hc, bktName)
+
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketCorsHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchCORSConfiguration))
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeleteBucketCorsHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	cors := &data.CORSConfiguration{
+		CORSRules: []data.CORSRule{{
+			AllowedMethods: []string{http.MethodGet, http.MethodPut},
+			AllowedOrigins: []string{"*"},
+		}},
+	}
+	w, r = prepareTestRequest(hc, bktName, "", cors)
+	hc.Handler().PutBucketCorsHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketCorsHandler(w, r)
+	actual := &data.CORSConfiguration{}
+	parseTestResponse(t, w, actual)
+	require.Equal(t, cors.CORSRules, actual.CORSRules)
+
+	stored := getCORSFromMockedNeoFS(t, hc, bktInfo)
+	require.Equal(t, cors.CORSRules, stored.CORSRules)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeleteBucketCorsHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketCorsHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchCORSConfiguration))
+}
+
+func TestPutBucketCORSWithoutNamespace(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-cors"
+	bktInfo := createTestBucket(hc, bktName)
+
+	body := `<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`
+	w, r := prepareTestRequestWithQuery(hc, bktName, "", nil, []byte(body))
+	hc.Handler().PutBucketCorsHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	stored := getCORSFromMockedNeoFS(t, hc, bktInfo)
+	require.Equal(t, []string{http.MethodGet}, stored.CORSRules[0].AllowedMethods)
+	require.Equal(t, []string{"*"}, stored.CORSRules[0].AllowedOrigins)
+}
+
+func TestBucketCORSConfigHistory(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-cors-history"
+	bktInfo := createTestBucket(hc, bktName)
+
+	cors := &data.CORSConfiguration{
+		CORSRules: []data.CORSRule{{
+			AllowedMethods: []string{http.MethodGet},
+			AllowedOrigins: []string{"*"},
+		}},
+	}
+	for _, origin := range []string{"first.example.com", "second.example.com"} {
+		cors.CORSRules[0].AllowedOrigins = []string{origin}
+		w, r := prepareTestRequest(hc, bktName, "", cors)
+		hc.Handler().PutBucketCorsHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+	}
+
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeleteBucketCorsHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	history, err := hc.Layer().GetBucketConfigHistory(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	for _, change := range history {
+		require.Equal(t, layer.ConfigTypeCORS, change.Type)
+		require.Equal(t, hc.owner.EncodeToString(), change.Owner)
+	}
+	require.Empty(t, history[0].PreviousValue)
+	require.Contains(t, history[1].PreviousValue, "first.example.com")
+	require.Contains(t, history[2].PreviousValue, "second.example.com")
+}
+
+func getCORSFromMockedNeoFS(t *testing.T, hc *handlerContext, bktInfo *data.BucketInfo) *data.CORSConfiguration {
+	for _, obj := range hc.MockedPool().Objects() {
+		for _, attr := range obj.Attributes() {
+			if attr.Key() == object.AttributeFilePath && attr.Value() == bktInfo.CORSObjectName() {
+				cors := &data.CORSConfiguration{}
+				require.NoError(t, xml.NewDecoder(bytes.NewReader(obj.Payload())).Decode(cors))
+				return cors
+			}
+		}
+	}
+
+	t.Fatalf("cors object not found")
+	return nil
+}
+
+func TestCORSPostObjectFromWebsite(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-uploads"
+	createTestBucket(hc, bktName)
+	site := "http://app.website.example.com"
+
+	cors := &data.CORSConfiguration{
+		CORSRules: []data.CORSRule{{
+			AllowedMethods: []string{http.MethodPost},
+			AllowedOrigins: []string{"http://*.website.example.com"},
+			AllowedHeaders: []string{"*"},
+			ExposeHeaders:  []string{api.ETag, api.Location},
+		}},
+	}
+	w, r := prepareTestRequest(hc, bktName, "", cors)
+	hc.Handler().PutBucketCorsHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	for origin, status := range map[string]int{
+		site:                         http.StatusOK,
+		"http://website.example.com": http.StatusForbidden,
+		"http://app.example.com":     http.StatusForbidden,
+	} {
+		w, r = prepareTestRequest(hc, bktName, "", nil)
+		r.Method = http.MethodOptions
+		r.Header.Set(api.Origin, origin)
+		r.Header.Set(api.AccessControlRequestMethod, http.MethodPost)
+		r.Header.Set(api.AccessControlRequestHeaders, api.ContentType)
+		hc.Handler().Preflight(w, r)
+		assertStatus(t, w, status)
+		if status == http.StatusOK {
+			require.Equal(t, origin, w.Header().Get(api.AccessControlAllowOrigin))
+		}
+	}
+
+	policy := map[string]interface{}{
+		"expiration": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		"conditions": []interface{}{
+			map[string]string{"bucket": bktName},
+			[]interface{}{"starts-with", "$key", "uploads/"},
+			[]interface{}{"starts-with", "$redirect", site},
+		},
+	}
+	fields := map[string]string{
+		"key":      "uploads/${filename}",
+		"redirect": site + "/done.html",
+	}
+
+	w, r = preparePostObjectRequest(hc, bktName, policy, fields, "content")
+	r.Header.Set(api.Origin, site)
+	hc.Handler().AppendCORSHeaders(w, r)
+	hc.Handler().PostObject(w, r)
+	assertStatus(t, w, http.StatusSeeOther)
+	require.Equal(t, site, w.Header().Get(api.AccessControlAllowOrigin))
+	require.Equal(t, api.ETag+", "+api.Location, w.Header().Get(api.AccessControlExposeHeaders))
+	require.True(t, strings.HasPrefix(w.Header().Get(api.Location), site+"/done.html?"))
+}
+
+func TestPutBucketCORSWithSeveralWildcards(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-cors"
+	createTestBucket(hc, bktName)
+
+	cors := &data.CORSConfiguration{
+		CORSRules: []data.CORSRule{{
+			AllowedMethods: []string{http.MethodGet},
+			AllowedOrigins: []string{"http://*.*.example.com"},
+		}},
+	}
+	w, r := prepareTestRequest(hc, bktName, "", cors)
+	hc.Handler().PutBucketCorsHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+}
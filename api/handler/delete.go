@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/md5"
 	"encoding/xml"
+	stderrors "errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
@@ -77,10 +83,29 @@ func (h *handler) DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if bktSettings.MFADeleteEnabled() && len(versionID) != 0 {
+		if err = h.checkMFA(r, bktInfo); err != nil {
+			h.logAndSendError(w, "mfa check failed", reqInfo, err)
+			return
+		}
+	}
+
+	if err = h.checkDeletePreconditions(r, bktInfo, reqInfo.ObjectName, versionID); err != nil {
+		h.logAndSendError(w, "precondition failed", reqInfo, err)
+		return
+	}
+
+	bypass, err := h.bypassGovernance(r, bktInfo, reqInfo.ObjectName)
+	if err != nil {
+		h.logAndSendError(w, "couldn't bypass governance retention", reqInfo, err)
+		return
+	}
+
 	p := &layer.DeleteObjectParams{
-		BktInfo:  bktInfo,
-		Objects:  versionedObject,
-		Settings: bktSettings,
+		BktInfo:          bktInfo,
+		Objects:          versionedObject,
+		Settings:         bktSettings,
+		BypassGovernance: bypass,
 	}
 	deletedObjects := h.obj.DeleteObjects(r.Context(), p)
 	deletedObject := deletedObjects[0]
@@ -141,7 +166,79 @@ func (h *handler) DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// checkDeletePreconditions checks conditional delete headers against the object version to delete, so the object
+// is deleted only if it hasn't changed since the client read it. PreconditionFailed error is returned if ETag,
+// last modification time or size of the version doesn't match 'If-Match', 'x-amz-if-match-last-modified-time'
+// or 'x-amz-if-match-size' headers, or if ETag matches 'If-None-Match' header. NoSuchKey error is returned
+// if the version doesn't exist.
+func (h *handler) checkDeletePreconditions(r *http.Request, bktInfo *data.BucketInfo, object, versionID string) error {
+	ifMatch, ifNoneMatch := r.Header.Get(api.IfMatch), r.Header.Get(api.IfNoneMatch)
+	modifiedStr, sizeStr := r.Header.Get(api.AmzIfMatchLastModifiedTime), r.Header.Get(api.AmzIfMatchSize)
+	if len(ifMatch) == 0 && len(ifNoneMatch) == 0 && len(modifiedStr) == 0 && len(sizeStr) == 0 {
+		return nil
+	}
+
+	lastModified, err := parseHTTPTime(modifiedStr)
+	if err != nil {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, err)
+	}
+	size := int64(-1)
+	if len(sizeStr) != 0 {
+		if size, err = strconv.ParseInt(sizeStr, 10, 64); err != nil || size < 0 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid size '%s'", sizeStr))
+		}
+	}
+
+	objInfo, err := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{
+		BktInfo:   bktInfo,
+		Object:    object,
+		VersionID: versionID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(ifMatch) > 0 && ifMatch != "*" && !etagMatches(ifMatch, objInfo.HashSum) ||
+		len(ifNoneMatch) > 0 && (ifNoneMatch == "*" || etagMatches(ifNoneMatch, objInfo.HashSum)) ||
+		lastModified != nil && !objInfo.Created.Truncate(time.Second).Equal(*lastModified) ||
+		size >= 0 && objInfo.Size != size {
+		return errors.GetAPIError(errors.ErrPreconditionFailed)
+	}
+
+	return nil
+}
+
+// bypassGovernance parses x-amz-bypass-governance-retention header and checks the requester is allowed to bypass
+// governance retention of the object.
+func (h *handler) bypassGovernance(r *http.Request, bktInfo *data.BucketInfo, object string) (bool, error) {
+	bypass, err := parseBypassGovernance(r)
+	if err != nil || !bypass {
+		return false, err
+	}
+
+	return true, h.checkBypassGovernance(r, bktInfo, object)
+}
+
+// parseBypassGovernance parses x-amz-bypass-governance-retention header.
+func parseBypassGovernance(r *http.Request) (bool, error) {
+	bypassStr := r.Header.Get(api.AmzBypassGovernanceRetention)
+	if len(bypassStr) == 0 {
+		return false, nil
+	}
+
+	bypass, err := strconv.ParseBool(bypassStr)
+	if err != nil {
+		return false, errors.GetAPIError(errors.ErrInvalidArgument)
+	}
+
+	return bypass, nil
+}
+
 func isErrObjectLocked(err error) bool {
+	if stderrors.Is(err, layer.ErrObjectLocked) {
+		return true
+	}
+
 	switch err.(type) {
 	default:
 		return strings.Contains(err.Error(), "object is locked")
@@ -157,7 +254,12 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 
 	// Content-Md5 is required and should be set
 	// http://docs.aws.amazon.com/AmazonS3/latest/API/multiobjectdeleteapi.html
-	if _, ok := r.Header[api.ContentMD5]; !ok {
+	contentMD5, err := formContentMD5(r.Header)
+	if err != nil {
+		h.logAndSendError(w, "invalid Content-MD5", reqInfo, err)
+		return
+	}
+	if contentMD5 == nil {
 		h.logAndSendError(w, "missing Content-MD5", reqInfo, errors.GetAPIError(errors.ErrMissingContentMD5))
 		return
 	}
@@ -169,9 +271,19 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logAndSendError(w, "couldn't read body", reqInfo, errors.GetAPIError(errors.ErrIncompleteBody))
+		return
+	}
+	if sum := md5.Sum(body); !bytes.Equal(sum[:], contentMD5) {
+		h.logAndSendError(w, "Content-MD5 mismatch", reqInfo, errors.GetAPIError(errors.ErrBadDigest))
+		return
+	}
+
 	// Unmarshal list of keys to be deleted.
 	requested := &DeleteObjectsRequest{}
-	if err := xml.NewDecoder(r.Body).Decode(requested); err != nil {
+	if err = api.NewXMLDecoder(bytes.NewReader(body)).Decode(requested); err != nil {
 		h.logAndSendError(w, "couldn't decode body", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
 		return
 	}
@@ -204,6 +316,13 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if bktSettings.MFADeleteEnabled() && hasVersionIDs(toRemove) {
+		if err = h.checkMFA(r, bktInfo); err != nil {
+			h.logAndSendError(w, "mfa check failed", reqInfo, err)
+			return
+		}
+	}
+
 	marshaler := zapcore.ArrayMarshalerFunc(func(encoder zapcore.ArrayEncoder) error {
 		for _, obj := range toRemove {
 			encoder.AppendString(obj.String())
@@ -211,23 +330,50 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 		return nil
 	})
 
+	bypass, err := parseBypassGovernance(r)
+	if err != nil {
+		h.logAndSendError(w, "couldn't bypass governance retention", reqInfo, err)
+		return
+	}
+
+	// the bypass is allowed by the bucket policy per object, so only denied objects fail
+	var deletedObjects []*layer.VersionedObject
+	allowed := toRemove
+	if bypass {
+		allowed = make([]*layer.VersionedObject, 0, len(toRemove))
+		for _, obj := range toRemove {
+			if err = h.checkBypassGovernance(r, bktInfo, obj.Name); err != nil {
+				if !errors.IsS3Error(err, errors.ErrAccessDenied) {
+					h.logAndSendError(w, "couldn't bypass governance retention", reqInfo, err)
+					return
+				}
+				obj.Error = err
+				deletedObjects = append(deletedObjects, obj)
+				continue
+			}
+			allowed = append(allowed, obj)
+		}
+	}
+
 	p := &layer.DeleteObjectParams{
-		BktInfo:  bktInfo,
-		Objects:  toRemove,
-		Settings: bktSettings,
+		BktInfo:          bktInfo,
+		Objects:          allowed,
+		Settings:         bktSettings,
+		BypassGovernance: bypass,
 	}
-	deletedObjects := h.obj.DeleteObjects(r.Context(), p)
+	deletedObjects = append(deletedObjects, h.obj.DeleteObjects(r.Context(), p)...)
 
 	var errs []error
 	for _, obj := range deletedObjects {
 		if obj.Error != nil {
-			code := "BadRequest"
-			if s3err, ok := obj.Error.(errors.Error); ok {
-				code = s3err.Code
+			// internal errors aren't sent to the client, they are logged below
+			s3err := errors.GetAPIError(errors.ErrInternalError)
+			if err, ok := transformToS3Error(obj.Error).(errors.Error); ok {
+				s3err = err
 			}
 			response.Errors = append(response.Errors, DeleteError{
-				Code:      code,
-				Message:   obj.Error.Error(),
+				Code:      s3err.Code,
+				Message:   s3err.Description,
 				Key:       obj.Name,
 				VersionID: obj.VersionID,
 			})
@@ -260,6 +406,16 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// hasVersionIDs checks if any of the objects is deleted by the version id.
+func hasVersionIDs(objects []*layer.VersionedObject) bool {
+	for _, obj := range objects {
+		if obj.VersionID != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *handler) DeleteBucketHandler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
 	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
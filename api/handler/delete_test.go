@@ -2,12 +2,24 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	stderrors "errors"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	usertest "github.com/nspcc-dev/neofs-sdk-go/user/test"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,6 +56,135 @@ func TestDeleteObject(t *testing.T) {
 	require.False(t, existInMockedNeoFS(tc, bktInfo, objInfo))
 }
 
+func TestDeleteObjectConditional(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-conditional-removal", "object"
+	_, objInfo := createBucketAndObject(hc, bktName, objName)
+	lastModified := objInfo.Created.UTC().Format(http.TimeFormat)
+
+	for _, tc := range []struct {
+		header, value string
+		status        int
+	}{
+		{header: api.IfMatch, value: "etag", status: http.StatusPreconditionFailed},
+		{header: api.IfNoneMatch, value: `"` + objInfo.HashSum + `"`, status: http.StatusPreconditionFailed},
+		{header: api.AmzIfMatchLastModifiedTime, value: objInfo.Created.Add(time.Hour).UTC().Format(http.TimeFormat), status: http.StatusPreconditionFailed},
+		{header: api.AmzIfMatchLastModifiedTime, value: "yesterday", status: http.StatusBadRequest},
+		{header: api.AmzIfMatchSize, value: strconv.FormatInt(objInfo.Size+1, 10), status: http.StatusPreconditionFailed},
+		{header: api.AmzIfMatchSize, value: "-1", status: http.StatusBadRequest},
+	} {
+		w, r := prepareTestRequest(hc, bktName, objName, nil)
+		r.Header.Set(tc.header, tc.value)
+		hc.Handler().DeleteObjectHandler(w, r)
+		assertStatus(t, w, tc.status)
+	}
+	checkFound(t, hc, bktName, objName, emptyVersion)
+
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	r.Header.Set(api.IfMatch, `"`+objInfo.HashSum+`"`)
+	r.Header.Set(api.AmzIfMatchLastModifiedTime, lastModified)
+	r.Header.Set(api.AmzIfMatchSize, strconv.FormatInt(objInfo.Size, 10))
+	hc.Handler().DeleteObjectHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	checkNotFound(t, hc, bktName, objName, emptyVersion)
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	r.Header.Set(api.IfMatch, objInfo.HashSum)
+	hc.Handler().DeleteObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchKey))
+}
+
+func TestDeleteObjectsContentMD5(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-delete-objects-md5", "object"
+	createBucketAndObject(hc, bktName, objName)
+
+	body, err := xml.Marshal(&DeleteObjectsRequest{Objects: []ObjectIdentifier{{ObjectName: objName}}})
+	require.NoError(t, err)
+	sum := md5.Sum(body)
+	otherSum := md5.Sum([]byte("other"))
+
+	for _, tc := range []struct {
+		name       string
+		contentMD5 string
+		err        errors.ErrorCode
+	}{
+		{name: "missing", err: errors.ErrMissingContentMD5},
+		{name: "invalid", contentMD5: "invalid", err: errors.ErrInvalidDigest},
+		{name: "short", contentMD5: base64.StdEncoding.EncodeToString(sum[:8]), err: errors.ErrInvalidDigest},
+		{name: "mismatch", contentMD5: base64.StdEncoding.EncodeToString(otherSum[:]), err: errors.ErrBadDigest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, r := prepareTestRequestWithQuery(hc, bktName, "", nil, body)
+			if tc.contentMD5 != "" {
+				r.Header.Set(api.ContentMD5, tc.contentMD5)
+			}
+			hc.Handler().DeleteMultipleObjectsHandler(w, r)
+			assertS3Error(t, w, errors.GetAPIError(tc.err))
+		})
+	}
+	checkFound(t, hc, bktName, objName, emptyVersion)
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, "", nil, body)
+	r.Header.Set(api.ContentMD5, base64.StdEncoding.EncodeToString(sum[:]))
+	hc.Handler().DeleteMultipleObjectsHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	checkNotFound(t, hc, bktName, objName, emptyVersion)
+}
+
+func TestDeleteObjectsConcurrently(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, foreignName := "bucket-delete-objects", "foreign-object"
+	bktInfo := createTestBucket(hc, bktName)
+
+	// the object of the other owner can't be deleted
+	box, _ := createAccessBox(t)
+	w, r := prepareTestPayloadRequest(hc, bktName, foreignName, strings.NewReader("content"))
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	foreignErr := DeleteError{Code: "AccessDenied", Message: errors.GetAPIError(errors.ErrAccessDenied).Description, Key: foreignName}
+
+	for _, quiet := range []bool{false, true} {
+		objects := []ObjectIdentifier{{ObjectName: foreignName}}
+		for i := 0; i < 50; i++ {
+			objName := "object-" + strconv.Itoa(i)
+			createTestObject(hc, bktInfo, objName)
+			// repeated keys are deleted sequentially
+			objects = append(objects, ObjectIdentifier{ObjectName: objName}, ObjectIdentifier{ObjectName: objName})
+		}
+
+		resp := deleteObjects(hc, bktName, objects, quiet)
+		require.Equal(t, []DeleteError{foreignErr}, resp.Errors)
+		if quiet {
+			require.Empty(t, resp.DeletedObjects)
+		} else {
+			require.Len(t, resp.DeletedObjects, 100)
+		}
+
+		for i := 0; i < 50; i++ {
+			checkNotFound(t, hc, bktName, "object-"+strconv.Itoa(i), emptyVersion)
+		}
+	}
+}
+
+func deleteObjects(hc *handlerContext, bktName string, objects []ObjectIdentifier, quiet bool) *DeleteObjectsResponse {
+	body, err := xml.Marshal(&DeleteObjectsRequest{Quiet: quiet, Objects: objects})
+	require.NoError(hc.t, err)
+	sum := md5.Sum(body)
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, "", nil, body)
+	r.Header.Set(api.ContentMD5, base64.StdEncoding.EncodeToString(sum[:]))
+	hc.Handler().DeleteMultipleObjectsHandler(w, r)
+
+	resp := &DeleteObjectsResponse{}
+	readResponse(hc.t, w, http.StatusOK, resp)
+	return resp
+}
+
 func TestDeleteObjectFromSuspended(t *testing.T) {
 	tc := prepareHandlerContext(t)
 	bktName, objName := "bucket-versioned-for-removal", "object-to-delete"
@@ -243,6 +384,124 @@ func TestDeleteObjectCheckMarkerReturn(t *testing.T) {
 	require.Equal(t, deleteMarkerVersion, deleteMarkerVersion2)
 }
 
+func TestDeleteObjectToTrash(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-with-trash", "dir/object-to-delete"
+	bktInfo, objInfo := createBucketAndObject(tc, bktName, objName)
+	setTrashRetention(t, tc, bktInfo, time.Hour)
+
+	deleteObject(t, tc, bktName, objName, emptyVersion)
+	checkNotFound(t, tc, bktName, objName, emptyVersion)
+	require.True(t, existInMockedNeoFS(tc, bktInfo, objInfo))
+
+	require.Empty(t, listObjectsV1(t, tc, bktName, "", "", "", -1).Contents)
+	require.Empty(t, listObjectsV1(t, tc, bktName, "", "/", "", -1).CommonPrefixes)
+	require.Empty(t, listVersions(t, tc, bktName).Version)
+
+	trash, err := tc.Layer().ListTrash(tc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, objName, trash[0].Key)
+	require.Equal(t, trash[0].Deleted.Add(time.Hour), trash[0].Expires)
+
+	putObject(t, tc, bktName, objName)
+	_, err = tc.Layer().RestoreTrashObject(tc.Context(), bktInfo, trash[0].Name)
+	require.ErrorIs(t, err, layer.ErrObjectExists)
+
+	deleteObject(t, tc, bktName, objName, emptyVersion)
+	_, err = tc.Layer().RestoreTrashObject(tc.Context(), bktInfo, trash[0].Name)
+	require.NoError(t, err)
+	checkFound(t, tc, bktName, objName, emptyVersion)
+
+	trash, err = tc.Layer().ListTrash(tc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+}
+
+func TestDeleteObjectFromTrashAfterRetention(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-with-trash", "object-to-delete"
+	bktInfo, objInfo := createBucketAndObject(tc, bktName, objName)
+	setTrashRetention(t, tc, bktInfo, time.Hour)
+
+	deleteObject(t, tc, bktName, objName, emptyVersion)
+
+	purged, err := tc.Layer().PurgeExpiredTrash(tc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Zero(t, purged)
+	require.True(t, existInMockedNeoFS(tc, bktInfo, objInfo))
+
+	later := context.WithValue(tc.Context(), api.ClientTime, time.Now().Add(2*time.Hour))
+	purged, err = tc.Layer().PurgeExpiredTrash(later, bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+	require.False(t, existInMockedNeoFS(tc, bktInfo, objInfo))
+
+	trash, err := tc.Layer().ListTrash(tc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Empty(t, trash)
+}
+
+func TestPurgeExpiredTrashOfFrozenBucket(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-with-trash", "object-to-delete"
+	bktInfo, objInfo := createBucketAndObject(tc, bktName, objName)
+	setTrashRetention(t, tc, bktInfo, time.Hour)
+
+	deleteObject(t, tc, bktName, objName, emptyVersion)
+
+	settings, err := tc.Layer().GetBucketSettings(tc.Context(), bktInfo)
+	require.NoError(t, err)
+	settings.Frozen = true
+	err = tc.Layer().PutBucketSettings(tc.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	later := context.WithValue(tc.Context(), api.ClientTime, time.Now().Add(2*time.Hour))
+	purged, err := tc.Layer().PurgeExpiredTrash(later, bktInfo)
+	require.NoError(t, err)
+	require.Zero(t, purged)
+	require.True(t, existInMockedNeoFS(tc, bktInfo, objInfo))
+
+	trash, err := tc.Layer().ListTrash(tc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+}
+
+func TestDeleteObjectWithTrashPrefix(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-with-trash", ".s3-trash/object"
+	bktInfo, objInfo := createBucketAndObject(tc, bktName, objName)
+	require.Len(t, listObjectsV1(t, tc, bktName, "", "", "", -1).Contents, 1)
+
+	setTrashRetention(t, tc, bktInfo, time.Hour)
+	deleteObject(t, tc, bktName, objName, emptyVersion)
+
+	later := context.WithValue(tc.Context(), api.ClientTime, time.Now().Add(time.Minute))
+	_, err := tc.Layer().PurgeExpiredTrash(later, bktInfo)
+	require.NoError(t, err)
+	require.True(t, existInMockedNeoFS(tc, bktInfo, objInfo))
+
+	trash, err := tc.Layer().ListTrash(tc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, objName, trash[0].Key)
+}
+
+func setTrashRetention(t *testing.T, tc *handlerContext, bktInfo *data.BucketInfo, retention time.Duration) {
+	err := tc.Layer().PutBucketSettings(tc.Context(), &layer.PutSettingsParams{
+		BktInfo: bktInfo,
+		Settings: &data.BucketSettings{
+			Versioning:     data.VersioningUnversioned,
+			TrashRetention: retention,
+		},
+	})
+	require.NoError(t, err)
+}
+
 func createBucketAndObject(tc *handlerContext, bktName, objName string) (*data.BucketInfo, *data.ObjectInfo) {
 	bktInfo := createTestBucket(tc, bktName)
 
@@ -262,6 +521,88 @@ func createVersionedBucketAndObject(t *testing.T, tc *handlerContext, bktName, o
 	return bktInfo, objInfo
 }
 
+type testMFAValidator struct {
+	owner user.ID
+	codes map[string]string
+}
+
+func (v testMFAValidator) Validate(owner user.ID, serial, code string) error {
+	if !v.owner.Equals(owner) {
+		return stderrors.New("device of another owner")
+	}
+	if v.codes[serial] != code {
+		return stderrors.New("invalid code")
+	}
+	return nil
+}
+
+func TestDeleteObjectVersionWithMFA(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-mfa-delete", "object"
+	createTestBucket(hc, bktName)
+	putObject(t, hc, bktName, objName)
+
+	mfaConfig := &VersioningConfiguration{Status: data.VersioningEnabled, MfaDelete: data.MFADeleteEnabled}
+	w, r := prepareTestRequest(hc, bktName, "", mfaConfig)
+	r.Header.Set(api.AmzMFA, "device 123456")
+	hc.Handler().PutBucketVersioningHandler(w, r)
+	assertStatus(t, w, http.StatusNotImplemented)
+
+	// devices of other owners aren't accepted
+	hc.h.cfg.MFA = testMFAValidator{owner: *usertest.ID(), codes: map[string]string{"device": "123456"}}
+
+	w, r = prepareTestRequest(hc, bktName, "", mfaConfig)
+	r.Header.Set(api.AmzMFA, "device 123456")
+	hc.Handler().PutBucketVersioningHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	hc.h.cfg.MFA = testMFAValidator{owner: hc.owner, codes: map[string]string{"device": "123456"}}
+
+	w, r = prepareTestRequest(hc, bktName, "", mfaConfig)
+	hc.Handler().PutBucketVersioningHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	w, r = prepareTestRequest(hc, bktName, "", mfaConfig)
+	r.Header.Set(api.AmzMFA, "device 123456")
+	hc.Handler().PutBucketVersioningHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketVersioningHandler(w, r)
+	versioning := &VersioningConfiguration{}
+	parseTestResponse(t, w, versioning)
+	require.Equal(t, data.MFADeleteEnabled, versioning.MfaDelete)
+
+	// versioning of the bucket can't be suspended without MFA
+	w, r = prepareTestRequest(hc, bktName, "", &VersioningConfiguration{Status: data.VersioningSuspended})
+	hc.Handler().PutBucketVersioningHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	// delete markers are created without MFA
+	deleteObject(t, hc, bktName, objName, emptyVersion)
+	versions := listVersions(t, hc, bktName)
+	require.Len(t, versions.Version, 1)
+	versionID := versions.Version[0].VersionID
+
+	query := url.Values{api.QueryVersionID: []string{versionID}}
+	for _, header := range []string{"", "device 000000", "device"} {
+		w, r = prepareTestFullRequest(hc, bktName, objName, query, nil)
+		if header != "" {
+			r.Header.Set(api.AmzMFA, header)
+		}
+		hc.Handler().DeleteObjectHandler(w, r)
+		require.NotEqual(t, http.StatusNoContent, w.Code, header)
+	}
+	checkFound(t, hc, bktName, objName, versionID)
+
+	w, r = prepareTestFullRequest(hc, bktName, objName, query, nil)
+	r.Header.Set(api.AmzMFA, "device 123456")
+	hc.Handler().DeleteObjectHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	checkNotFound(t, hc, bktName, objName, versionID)
+}
+
 func putBucketVersioning(t *testing.T, tc *handlerContext, bktName string, enabled bool) {
 	cfg := &VersioningConfiguration{Status: "Suspended"}
 	if enabled {
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"go.uber.org/zap"
+)
+
+type (
+	// DownloadTokenResponse is a response of download token creation.
+	DownloadTokenResponse struct {
+		XMLName    xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DownloadTokenResult" json:"-"`
+		Bucket     string   `xml:"Bucket"`
+		Key        string   `xml:"Key"`
+		VersionID  string   `xml:"VersionId,omitempty"`
+		Token      string   `xml:"Token"`
+		Expiration string   `xml:"Expiration"`
+	}
+
+	// downloadTokens stores single-use tokens to download objects without request signing.
+	// Unlike presigned URLs, the token is removed on the first use.
+	downloadTokens struct {
+		mu     sync.Mutex
+		tokens map[string]*downloadToken
+		// perKey is a number of stored tokens by access key ids which created them.
+		perKey map[string]int
+		// sweeper removes expired tokens periodically while there are stored tokens.
+		sweeper *time.Timer
+	}
+
+	downloadToken struct {
+		accessKeyID string
+		box         *accessbox.Box
+		bucket      string
+		object      string
+		versionID   string
+		expiration  time.Time
+		// inUse is set while the download with the token is served.
+		inUse bool
+	}
+
+	// downloadTokenWriter records the status of the response to the download with the token.
+	downloadTokenWriter struct {
+		http.ResponseWriter
+		status int
+	}
+)
+
+const (
+	downloadTokenQuery   = "downloadToken"
+	tokenExpiresQuery    = "expires"
+	downloadTokenSize    = 32
+	defaultTokenLifetime = 5 * time.Minute
+	maxTokenLifetime     = 24 * time.Hour
+
+	// maxDownloadTokens is a number of tokens stored by the gateway.
+	maxDownloadTokens = 100000
+	// maxDownloadTokensPerKey is a number of tokens stored for the single access key id.
+	maxDownloadTokensPerKey = 1000
+	// downloadTokensSweepInterval is an interval of expired tokens removal.
+	downloadTokensSweepInterval = time.Minute
+)
+
+func newDownloadTokens() *downloadTokens {
+	return &downloadTokens{
+		tokens: make(map[string]*downloadToken),
+		perKey: make(map[string]int),
+	}
+}
+
+// add stores the token, SlowDown error is returned if too many tokens are stored
+// by the gateway or for the access key id of the token.
+func (d *downloadTokens) add(token string, t *downloadToken, now time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.limitExceeded(t.accessKeyID) {
+		d.removeExpired(now)
+		if d.limitExceeded(t.accessKeyID) {
+			return errors.GetAPIError(errors.ErrSlowDown)
+		}
+	}
+
+	d.tokens[token] = t
+	d.perKey[t.accessKeyID]++
+
+	if d.sweeper == nil {
+		d.sweeper = time.AfterFunc(downloadTokensSweepInterval, d.sweep)
+	}
+
+	return nil
+}
+
+func (d *downloadTokens) limitExceeded(accessKeyID string) bool {
+	return len(d.tokens) >= maxDownloadTokens || d.perKey[accessKeyID] >= maxDownloadTokensPerKey
+}
+
+// sweep removes expired tokens and reschedules itself until all tokens are removed.
+func (d *downloadTokens) sweep() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.removeExpired(time.Now())
+	if len(d.tokens) == 0 {
+		d.sweeper = nil
+		return
+	}
+	d.sweeper.Reset(downloadTokensSweepInterval)
+}
+
+func (d *downloadTokens) removeExpired(now time.Time) {
+	for key, val := range d.tokens {
+		if !val.inUse && !now.Before(val.expiration) {
+			d.remove(key, val)
+		}
+	}
+}
+
+func (d *downloadTokens) remove(token string, t *downloadToken) {
+	delete(d.tokens, token)
+	if d.perKey[t.accessKeyID]--; d.perKey[t.accessKeyID] <= 0 {
+		delete(d.perKey, t.accessKeyID)
+	}
+}
+
+// use returns the token if it isn't expired and isn't used by another download.
+// The token is kept until the download is done.
+func (d *downloadTokens) use(token string, now time.Time) *downloadToken {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, ok := d.tokens[token]
+	if !ok || t.inUse {
+		return nil
+	}
+
+	if !now.Before(t.expiration) {
+		d.remove(token, t)
+		return nil
+	}
+
+	t.inUse = true
+	return t
+}
+
+// done removes the used token. If the download failed because of the gateway or the storage,
+// the token is kept to be used again.
+func (d *downloadTokens) done(token string, failed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, ok := d.tokens[token]
+	if !ok {
+		return
+	}
+
+	if failed {
+		t.inUse = false
+		return
+	}
+	d.remove(token, t)
+}
+
+func (w *downloadTokenWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *downloadTokenWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// CreateDownloadTokenHandler mints a single-use token to download the object with credentials of the request.
+func (h *handler) CreateDownloadTokenHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	box, ok := r.Context().Value(api.BoxData).(*accessbox.Box)
+	if !ok {
+		h.logAndSendError(w, "anonymous download token", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return
+	}
+	accessKeyID, _ := r.Context().Value(api.AccessKeyID).(string)
+
+	lifetime := defaultTokenLifetime
+	if expires := reqInfo.URL.Query().Get(tokenExpiresQuery); len(expires) > 0 {
+		seconds, err := strconv.Atoi(expires)
+		if err != nil || seconds <= 0 || time.Duration(seconds)*time.Second > maxTokenLifetime {
+			h.logAndSendError(w, "invalid token lifetime", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+			return
+		}
+		lifetime = time.Duration(seconds) * time.Second
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.checkFeature(r.Context(), bktInfo, features.DownloadTokens); err != nil {
+		h.logAndSendError(w, "feature is disabled", reqInfo, err)
+		return
+	}
+
+	versionID := reqInfo.URL.Query().Get(api.QueryVersionID)
+	p := &layer.HeadObjectParams{
+		BktInfo:   bktInfo,
+		Object:    reqInfo.ObjectName,
+		VersionID: versionID,
+	}
+
+	// the token is minted only for objects available to the requester
+	if _, err = h.obj.GetObjectInfo(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not find object", reqInfo, err)
+		return
+	}
+
+	rawToken := make([]byte, downloadTokenSize)
+	if _, err = rand.Read(rawToken); err != nil {
+		h.logAndSendError(w, "could not generate token", reqInfo, err)
+		return
+	}
+	token := hex.EncodeToString(rawToken)
+
+	now := time.Now()
+	expiration := now.Add(lifetime)
+	err = h.downloadTokens.add(token, &downloadToken{
+		accessKeyID: accessKeyID,
+		box:         box,
+		bucket:      reqInfo.BucketName,
+		object:      reqInfo.ObjectName,
+		versionID:   versionID,
+		expiration:  expiration,
+	}, now)
+	if err != nil {
+		h.logAndSendError(w, "too many download tokens", reqInfo, err)
+		return
+	}
+
+	h.log.Debug("download token is created",
+		zap.String("bucket", reqInfo.BucketName),
+		zap.String("object", reqInfo.ObjectName),
+		zap.Time("expiration", expiration))
+
+	response := &DownloadTokenResponse{
+		Bucket:     reqInfo.BucketName,
+		Key:        reqInfo.ObjectName,
+		VersionID:  versionID,
+		Token:      token,
+		Expiration: expiration.UTC().Format(time.RFC3339),
+	}
+
+	if err = api.EncodeToResponse(w, response); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+// useDownloadToken returns the request context with credentials of the download token.
+// The token is valid once for the object it was created for, it must be released by
+// downloadTokens.done after the download.
+func (h *handler) useDownloadToken(r *http.Request, reqInfo *api.ReqInfo, token string) (context.Context, error) {
+	t := h.downloadTokens.use(token, time.Now())
+	if t == nil {
+		return nil, errors.GetAPIError(errors.ErrAccessDenied)
+	}
+
+	if t.bucket != reqInfo.BucketName || t.object != reqInfo.ObjectName ||
+		t.versionID != reqInfo.URL.Query().Get(api.QueryVersionID) {
+		h.downloadTokens.done(token, false)
+		return nil, errors.GetAPIError(errors.ErrAccessDenied)
+	}
+
+	return context.WithValue(r.Context(), api.BoxData, t.box), nil
+}
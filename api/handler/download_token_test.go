@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadToken(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-download-token", "object"
+	createTestBucket(hc, bktName)
+	putObjectContent(hc, bktName, objName, "content")
+
+	token := createDownloadToken(t, hc, bktName, objName, nil)
+	w := downloadWithToken(hc, bktName, objName, token)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "content", w.Body.String())
+
+	// token is single-use
+	w = downloadWithToken(hc, bktName, objName, token)
+	assertStatus(t, w, http.StatusForbidden)
+
+	// token is valid only for its object
+	putObjectContent(hc, bktName, "other", "content")
+	token = createDownloadToken(t, hc, bktName, objName, nil)
+	w = downloadWithToken(hc, bktName, "other", token)
+	assertStatus(t, w, http.StatusForbidden)
+
+	// expired token is rejected
+	token = createDownloadToken(t, hc, bktName, objName, url.Values{tokenExpiresQuery: []string{"1"}})
+	hc.h.downloadTokens.tokens[token].expiration = time.Now().Add(-time.Second)
+	w = downloadWithToken(hc, bktName, objName, token)
+	assertStatus(t, w, http.StatusForbidden)
+
+	// token isn't minted for missing objects, anonymous requests and invalid lifetime
+	w, r := prepareTestRequestWithQuery(hc, bktName, "missing", url.Values{downloadTokenQuery: []string{""}}, nil)
+	hc.Handler().CreateDownloadTokenHandler(w, r)
+	assertStatus(t, w, http.StatusNotFound)
+
+	w, r = prepareTestRequestWithQuery(hc, bktName, objName, url.Values{downloadTokenQuery: []string{""}}, nil)
+	r = r.WithContext(api.SetReqInfo(context.Background(), api.GetReqInfo(r.Context())))
+	hc.Handler().CreateDownloadTokenHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	w, r = prepareTestRequestWithQuery(hc, bktName, objName, url.Values{tokenExpiresQuery: []string{"100000"}}, nil)
+	hc.Handler().CreateDownloadTokenHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+}
+
+func TestDownloadTokensStorage(t *testing.T) {
+	tokens := newDownloadTokens()
+	now := time.Now()
+
+	newToken := func(accessKeyID string) *downloadToken {
+		return &downloadToken{accessKeyID: accessKeyID, expiration: now.Add(time.Minute)}
+	}
+
+	require.NoError(t, tokens.add("token", newToken("key"), now))
+
+	// token isn't used by concurrent downloads
+	require.NotNil(t, tokens.use("token", now))
+	require.Nil(t, tokens.use("token", now))
+
+	// token is kept if the download failed
+	tokens.done("token", true)
+	require.NotNil(t, tokens.use("token", now))
+	tokens.done("token", false)
+	require.Nil(t, tokens.use("token", now))
+
+	// number of tokens of the access key is limited
+	for i := 0; i < maxDownloadTokensPerKey; i++ {
+		require.NoError(t, tokens.add(strconv.Itoa(i), newToken("key"), now))
+	}
+	err := tokens.add("over limit", newToken("key"), now)
+	require.True(t, errors.IsS3Error(err, errors.ErrSlowDown))
+	require.NoError(t, tokens.add("other key", newToken("other"), now))
+
+	// expired tokens are removed when the limit is reached
+	err = tokens.add("after expiration", newToken("key"), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, tokens.tokens, 1)
+}
+
+func createDownloadToken(t *testing.T, hc *handlerContext, bktName, objName string, query url.Values) string {
+	if query == nil {
+		query = make(url.Values)
+	}
+	query.Set(downloadTokenQuery, "")
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, objName, query, nil)
+	hc.Handler().CreateDownloadTokenHandler(w, r)
+	response := &DownloadTokenResponse{}
+	readResponse(t, w, http.StatusOK, response)
+	require.Equal(t, objName, response.Key)
+
+	return response.Token
+}
+
+func downloadWithToken(hc *handlerContext, bktName, objName, token string) *httptest.ResponseRecorder {
+	w, r := prepareTestRequestWithQuery(hc, bktName, objName, url.Values{downloadTokenQuery: []string{token}}, nil)
+	r = r.WithContext(api.SetReqInfo(context.Background(), api.GetReqInfo(r.Context())))
+	hc.Handler().GetObjectHandler(w, r)
+	return w
+}
@@ -18,10 +18,13 @@ import (
 )
 
 const (
-	aes256Key       = "MTIzNDU2Nzg5MHF3ZXJ0eXVpb3Bhc2RmZ2hqa2x6eGM="
-	aes256KeyMD5    = "NtkH/y2maPit+yUkhq4Q7A=="
-	partNumberQuery = "partNumber"
-	uploadIDQuery   = "uploadId"
+	aes256Key    = "MTIzNDU2Nzg5MHF3ZXJ0eXVpb3Bhc2RmZ2hqa2x6eGM="
+	aes256KeyMD5 = "NtkH/y2maPit+yUkhq4Q7A=="
+	// otherAES256Key is base64 of "abcdefghijklmnopqrstuvwxyz012345".
+	otherAES256Key    = "YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXowMTIzNDU="
+	otherAES256KeyMD5 = "NX6C25NPxF9KJbS4Pci9GQ=="
+	partNumberQuery   = "partNumber"
+	uploadIDQuery     = "uploadId"
 )
 
 func TestSimpleGetEncrypted(t *testing.T) {
@@ -255,6 +258,223 @@ func TestMultipartEncrypted(t *testing.T) {
 	require.Equal(t, part2[0:], part2Range)
 }
 
+func TestCopyEncrypted(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-c-copy", "object-to-copy"
+	createTestBucket(hc, bktName)
+
+	content := "content"
+	putEncryptedObject(t, hc, bktName, objName, content)
+
+	// wrong key is rejected
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	setEncryptHeadersWithKey(r, otherAES256Key, otherAES256KeyMD5)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	// key of the source object is required
+	w, r = prepareTestRequest(hc, bktName, "copy-without-key", nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	// decrypted copy
+	w, r = prepareTestRequest(hc, bktName, "plain-copy", nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	setCopySourceEncryptHeaders(r)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, content, getObjectContent(t, hc, bktName, "plain-copy"))
+
+	// key rotation in place
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	setCopySourceEncryptHeaders(r)
+	setEncryptHeadersWithKey(r, otherAES256Key, otherAES256KeyMD5)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, otherAES256KeyMD5, w.Header().Get(api.AmzServerSideEncryptionCustomerKeyMD5))
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	setEncryptHeadersWithKey(r, otherAES256Key, otherAES256KeyMD5)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, content, w.Body.String())
+
+	// part copy from the encrypted object
+	multipartInfo := createMultipartUpload(hc, bktName, "multipart-copy", nil)
+	query := make(url.Values)
+	query.Set(uploadIDQuery, multipartInfo.UploadID)
+	query.Set(partNumberQuery, "1")
+	w, r = prepareTestRequestWithQuery(hc, bktName, "multipart-copy", query, nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	r.TLS = &tls.ConnectionState{}
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerAlgorithm, layer.AESEncryptionAlgorithm)
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerKey, otherAES256Key)
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerKeyMD5, otherAES256KeyMD5)
+	hc.Handler().UploadPartCopy(w, r)
+	partCopy := &UploadPartCopyResponse{}
+	readResponse(t, w, http.StatusOK, partCopy)
+
+	completeMultipartUpload(hc, bktName, "multipart-copy", multipartInfo.UploadID, []string{partCopy.ETag})
+	require.Equal(t, content, getObjectContent(t, hc, bktName, "multipart-copy"))
+}
+
+func TestManagedEncryption(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-s3", "object-to-encrypt"
+	bktInfo := createTestBucket(hc, bktName)
+
+	content := "content"
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader(content))
+	r.Header.Set(api.AmzServerSideEncryption, layer.AESEncryptionAlgorithm)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, layer.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+
+	objInfo, err := hc.Layer().GetObjectInfo(hc.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: objName})
+	require.NoError(t, err)
+	obj, err := hc.MockedPool().ReadObject(hc.Context(), layer.PrmObjectRead{Container: bktInfo.CID, Object: objInfo.ID})
+	require.NoError(t, err)
+	encryptedContent, err := io.ReadAll(obj.Payload)
+	require.NoError(t, err)
+	require.NotEqual(t, content, string(encryptedContent))
+
+	// object is decrypted transparently
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, content, w.Body.String())
+	require.Equal(t, layer.AESEncryptionAlgorithm, w.Header().Get(api.AmzServerSideEncryption))
+	require.Equal(t, strconv.Itoa(len(content)), w.Header().Get(api.ContentLength))
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	r.Header.Set("Range", "bytes=2-4")
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusPartialContent)
+	require.Equal(t, content[2:5], w.Body.String())
+
+	// the data key isn't unwrapped for the ciphertext copied to other bucket with its headers
+	var copiedAttrs [][2]string
+	for _, stored := range hc.MockedPool().Objects() {
+		if id, _ := stored.ID(); id.Equals(objInfo.ID) {
+			for _, attr := range stored.Attributes() {
+				copiedAttrs = append(copiedAttrs, [2]string{attr.Key(), attr.Value()})
+			}
+		}
+	}
+	otherBktInfo := createTestBucket(hc, "bucket-for-copied-ciphertext")
+	copiedInfo := *objInfo
+	copiedInfo.CID = otherBktInfo.CID
+	copiedInfo.ID, err = hc.MockedPool().CreateObject(hc.Context(), layer.PrmObjectCreate{
+		Container:  otherBktInfo.CID,
+		Creator:    otherBktInfo.Owner,
+		Attributes: copiedAttrs,
+		Payload:    bytes.NewReader(encryptedContent),
+	})
+	require.NoError(t, err)
+	err = hc.Layer().GetObject(hc.Context(), &layer.GetObjectParams{ObjectInfo: &copiedInfo, BucketInfo: otherBktInfo, Writer: io.Discard})
+	require.ErrorContains(t, err, "unwrap data key")
+
+	// customer key isn't accepted
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	setEncryptHeaders(r)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	// unknown encryption method
+	w, r = prepareTestPayloadRequest(hc, bktName, "kms", strings.NewReader(content))
+	r.Header.Set(api.AmzServerSideEncryption, "aws:kms:dsse")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	// copy of the encrypted object is encrypted only if requested
+	copyObject(t, hc, bktName, objName, "plain-copy", CopyMeta{}, http.StatusOK)
+	require.Equal(t, content, getObjectContent(t, hc, bktName, "plain-copy"))
+	objInfo, err = hc.Layer().GetObjectInfo(hc.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: "plain-copy"})
+	require.NoError(t, err)
+	require.False(t, layer.FormEncryptionInfo(objInfo.Headers).Enabled)
+
+	w, r = prepareTestRequest(hc, bktName, "encrypted-copy", nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/plain-copy")
+	r.Header.Set(api.AmzServerSideEncryption, layer.AESEncryptionAlgorithm)
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, content, getObjectContent(t, hc, bktName, "encrypted-copy"))
+
+	// parts of multipart upload are encrypted with the key of the upload
+	multipartInfo := createMultipartUpload(hc, bktName, "multipart", map[string]string{
+		api.AmzServerSideEncryption: layer.AESEncryptionAlgorithm,
+	})
+	part1ETag, part1 := uploadPart(hc, bktName, "multipart", multipartInfo.UploadID, 1, 5*1048576)
+	part2ETag, part2 := uploadPart(hc, bktName, "multipart", multipartInfo.UploadID, 2, 5)
+	completeMultipartUpload(hc, bktName, "multipart", multipartInfo.UploadID, []string{part1ETag, part2ETag})
+	require.Equal(t, string(append(part1, part2...)), getObjectContent(t, hc, bktName, "multipart"))
+
+	// the renamed object is decrypted with the name it was created with
+	renameObject(hc, bktName, objName, "renamed", nil, http.StatusOK)
+	require.Equal(t, content, getObjectContent(t, hc, bktName, "renamed"))
+}
+
+func TestKMSEncryption(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-sse-kms", "object-to-encrypt"
+	bktInfo := createTestBucket(hc, bktName)
+
+	content := "content"
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader(content))
+	r.Header.Set(api.AmzServerSideEncryption, layer.KMSEncryptionMethod)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, layer.KMSEncryptionMethod, w.Header().Get(api.AmzServerSideEncryption))
+
+	// only the wrapped data key is stored in object headers
+	objInfo, err := hc.Layer().GetObjectInfo(hc.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: objName})
+	require.NoError(t, err)
+	encInfo := layer.FormEncryptionInfo(objInfo.Headers)
+	require.Equal(t, testKMSDefaultKey, encInfo.KMSKeyID)
+	require.NotEmpty(t, encInfo.WrappedKey)
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, content, w.Body.String())
+	require.Equal(t, layer.KMSEncryptionMethod, w.Header().Get(api.AmzServerSideEncryption))
+	require.Equal(t, testKMSDefaultKey, w.Header().Get(api.AmzServerSideEncryptionAwsKmsKeyID))
+
+	// the KMS key is chosen by the request
+	w, r = prepareTestPayloadRequest(hc, bktName, "other", strings.NewReader(content))
+	r.Header.Set(api.AmzServerSideEncryption, layer.KMSEncryptionMethod)
+	r.Header.Set(api.AmzServerSideEncryptionAwsKmsKeyID, testKMSOtherKey)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, testKMSOtherKey, w.Header().Get(api.AmzServerSideEncryptionAwsKmsKeyID))
+	require.Equal(t, content, getObjectContent(t, hc, bktName, "other"))
+
+	// the KMS key is allowed only for SSE-KMS
+	w, r = prepareTestPayloadRequest(hc, bktName, "invalid", strings.NewReader(content))
+	r.Header.Set(api.AmzServerSideEncryption, layer.AESEncryptionAlgorithm)
+	r.Header.Set(api.AmzServerSideEncryptionAwsKmsKeyID, testKMSOtherKey)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	// parts of multipart upload are encrypted with the data key of the upload
+	multipartInfo := createMultipartUpload(hc, bktName, "multipart", map[string]string{
+		api.AmzServerSideEncryption: layer.KMSEncryptionMethod,
+	})
+	part1ETag, part1 := uploadPart(hc, bktName, "multipart", multipartInfo.UploadID, 1, 5*1048576)
+	part2ETag, part2 := uploadPart(hc, bktName, "multipart", multipartInfo.UploadID, 2, 5)
+	completeMultipartUpload(hc, bktName, "multipart", multipartInfo.UploadID, []string{part1ETag, part2ETag})
+	require.Equal(t, string(append(part1, part2...)), getObjectContent(t, hc, bktName, "multipart"))
+
+	objInfo, err = hc.Layer().GetObjectInfo(hc.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: "multipart"})
+	require.NoError(t, err)
+	require.Equal(t, testKMSDefaultKey, layer.FormEncryptionInfo(objInfo.Headers).KMSKeyID)
+}
+
 func putEncryptedObject(t *testing.T, tc *handlerContext, bktName, objName, content string) {
 	body := bytes.NewReader([]byte(content))
 	w, r := prepareTestPayloadRequest(tc, bktName, objName, body)
@@ -285,10 +505,21 @@ func getEncryptedObjectRange(t *testing.T, tc *handlerContext, bktName, objName
 }
 
 func setEncryptHeaders(r *http.Request) {
+	setEncryptHeadersWithKey(r, aes256Key, aes256KeyMD5)
+}
+
+func setEncryptHeadersWithKey(r *http.Request, key, keyMD5 string) {
 	r.TLS = &tls.ConnectionState{}
 	r.Header.Set(api.AmzServerSideEncryptionCustomerAlgorithm, layer.AESEncryptionAlgorithm)
-	r.Header.Set(api.AmzServerSideEncryptionCustomerKey, aes256Key)
-	r.Header.Set(api.AmzServerSideEncryptionCustomerKeyMD5, aes256KeyMD5)
+	r.Header.Set(api.AmzServerSideEncryptionCustomerKey, key)
+	r.Header.Set(api.AmzServerSideEncryptionCustomerKeyMD5, keyMD5)
+}
+
+func setCopySourceEncryptHeaders(r *http.Request) {
+	r.TLS = &tls.ConnectionState{}
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerAlgorithm, layer.AESEncryptionAlgorithm)
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerKey, aes256Key)
+	r.Header.Set(api.AmzCopySourceServerSideEncryptionCustomerKeyMD5, aes256KeyMD5)
 }
 
 func setHeaders(r *http.Request, header map[string]string) {
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// checkFeature returns NotImplemented error if the feature is disabled for the bucket
+// by the deployment configuration or by the bucket settings.
+func (h *handler) checkFeature(ctx context.Context, bktInfo *data.BucketInfo, name string) error {
+	settings, err := h.obj.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+
+	if !h.cfg.Features.Enabled(name, settings.Features) {
+		return errors.GetAPIError(errors.ErrNotImplemented)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-features", "object"
+	bktInfo, _ := createBucketAndObject(hc, bktName, objName)
+
+	registry, err := features.NewRegistry(map[string]bool{features.Rename: false})
+	require.NoError(t, err)
+	hc.Handler().cfg.Features = registry
+
+	w := renameObject(hc, bktName, objName, "renamed", nil, http.StatusNotImplemented)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNotImplemented))
+
+	setBucketFeatures(t, hc, bktInfo, map[string]bool{features.Rename: true})
+	renameObject(hc, bktName, objName, "renamed", nil, http.StatusOK)
+
+	require.NoError(t, registry.Update(nil))
+	setBucketFeatures(t, hc, bktInfo, map[string]bool{features.Rename: false})
+	renameObject(hc, bktName, "renamed", objName, nil, http.StatusNotImplemented)
+}
+
+func setBucketFeatures(t *testing.T, hc *handlerContext, bktInfo *data.BucketInfo, flags map[string]bool) {
+	settings, err := hc.Layer().GetBucketSettings(hc.Context(), bktInfo)
+	require.NoError(t, err)
+
+	newSettings := *settings
+	newSettings.Features = flags
+	err = hc.Layer().PutBucketSettings(hc.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings})
+	require.NoError(t, err)
+}
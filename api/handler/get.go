@@ -1,6 +1,7 @@
 package handler
 
 import (
+	errorsStd "errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -12,9 +13,16 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"go.uber.org/zap"
 )
 
+// errAnonymousOverrides is the reason of rejection of anonymous requests with response-* query parameters.
+var errAnonymousOverrides = errorsStd.New("request specific response headers cannot be used for anonymous GET requests")
+
+// checksumModeEnabled is the value of x-amz-checksum-mode header to return the object checksum.
+const checksumModeEnabled = "ENABLED"
+
 type conditionalArgs struct {
 	IfModifiedSince   *time.Time
 	IfUnmodifiedSince *time.Time
@@ -45,7 +53,13 @@ func fetchRangeHeader(headers http.Header, fullSize uint64) (*layer.RangeParams,
 
 	if len(arr[0]) == 0 {
 		end, err1 = strconv.ParseUint(arr[1], base, bitSize)
-		start = fullSize - end
+		if end == 0 {
+			return nil, errors.GetAPIError(errors.ErrInvalidRange)
+		}
+		// suffix length greater than the object size selects the whole object
+		if end < fullSize {
+			start = fullSize - end
+		}
 		end = fullSize - 1
 	} else if len(arr[1]) == 0 {
 		start, err0 = strconv.ParseUint(arr[0], base, bitSize)
@@ -58,25 +72,50 @@ func fetchRangeHeader(headers http.Header, fullSize uint64) (*layer.RangeParams,
 		}
 	}
 
-	if err0 != nil || err1 != nil || start > end || start > fullSize {
+	if err0 != nil || err1 != nil || start > end || start >= fullSize {
 		return nil, errors.GetAPIError(errors.ErrInvalidRange)
 	}
 	return &layer.RangeParams{Start: start, End: end}, nil
 }
 
+// overrideResponseHeaders sets response headers from response-* query parameters.
 func overrideResponseHeaders(h http.Header, query url.Values) {
 	for key, value := range query {
-		if hdr, ok := api.ResponseModifiers[strings.ToLower(key)]; ok {
-			h[hdr] = value
+		if hdr, ok := api.ResponseModifiers[strings.ToLower(key)]; ok && len(value) > 0 {
+			h.Set(hdr, value[0])
 		}
 	}
 }
 
+// hasResponseOverrides checks if the query contains response-* parameters overriding response headers.
+func hasResponseOverrides(query url.Values) bool {
+	for key := range query {
+		if _, ok := api.ResponseModifiers[strings.ToLower(key)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func addSSECHeaders(responseHeader http.Header, requestHeader http.Header) {
 	responseHeader.Set(api.AmzServerSideEncryptionCustomerAlgorithm, requestHeader.Get(api.AmzServerSideEncryptionCustomerAlgorithm))
 	responseHeader.Set(api.AmzServerSideEncryptionCustomerKeyMD5, requestHeader.Get(api.AmzServerSideEncryptionCustomerKeyMD5))
 }
 
+// addEncryptionHeaders sets response headers describing encryption of the written object.
+func addEncryptionHeaders(responseHeader http.Header, requestHeader http.Header, enc encryption.Params) {
+	if enc.KMS() {
+		responseHeader.Set(api.AmzServerSideEncryption, layer.KMSEncryptionMethod)
+		if len(enc.KMSKeyID()) > 0 {
+			responseHeader.Set(api.AmzServerSideEncryptionAwsKmsKeyID, enc.KMSKeyID())
+		}
+	} else if enc.Managed() {
+		responseHeader.Set(api.AmzServerSideEncryption, layer.AESEncryptionAlgorithm)
+	} else if enc.Enabled() {
+		addSSECHeaders(responseHeader, requestHeader)
+	}
+}
+
 func writeHeaders(h http.Header, requestHeader http.Header, extendedInfo *data.ExtendedObjectInfo, tagSetLength int, isBucketUnversioned bool) {
 	info := extendedInfo.ObjectInfo
 	if len(info.ContentType) > 0 && h.Get(api.ContentType) == "" {
@@ -84,19 +123,37 @@ func writeHeaders(h http.Header, requestHeader http.Header, extendedInfo *data.E
 	}
 	h.Set(api.LastModified, info.Created.UTC().Format(http.TimeFormat))
 
-	if len(info.Headers[layer.AttributeEncryptionAlgorithm]) > 0 {
+	if encInfo := layer.FormEncryptionInfo(info.Headers); encInfo.Enabled {
 		h.Set(api.ContentLength, info.Headers[layer.AttributeDecryptedSize])
-		addSSECHeaders(h, requestHeader)
+		if encInfo.KMS() {
+			h.Set(api.AmzServerSideEncryption, layer.KMSEncryptionMethod)
+			h.Set(api.AmzServerSideEncryptionAwsKmsKeyID, encInfo.KMSKeyID)
+		} else if encInfo.Managed() {
+			h.Set(api.AmzServerSideEncryption, layer.AESEncryptionAlgorithm)
+		} else {
+			addSSECHeaders(h, requestHeader)
+		}
 	} else {
 		h.Set(api.ContentLength, strconv.FormatInt(info.Size, 10))
 	}
 
 	h.Set(api.ETag, info.HashSum)
-	h.Set(api.AmzTaggingCount, strconv.Itoa(tagSetLength))
+	if tagSetLength > 0 {
+		h.Set(api.AmzTaggingCount, strconv.Itoa(tagSetLength))
+	}
 
 	if !isBucketUnversioned {
 		h.Set(api.AmzVersionID, extendedInfo.Version())
 	}
+	if len(info.ReplicationStatus) != 0 {
+		h.Set(api.AmzReplicationStatus, info.ReplicationStatus)
+	}
+	if storageClass := info.StorageClass(); storageClass != data.StorageClassStandard {
+		h.Set(api.AmzStorageClass, storageClass)
+	}
+	if info.Archive != nil && info.Restore != nil {
+		h.Set(api.AmzRestore, restoreHeader(info.Restore))
+	}
 
 	if cacheControl := info.Headers[api.CacheControl]; cacheControl != "" {
 		h.Set(api.CacheControl, cacheControl)
@@ -105,6 +162,13 @@ func writeHeaders(h http.Header, requestHeader http.Header, extendedInfo *data.E
 		h.Set(api.Expires, expires)
 	}
 
+	// the checksum is calculated over the whole payload, so it isn't returned for range requests
+	if requestHeader.Get(api.AmzChecksumMode) == checksumModeEnabled && requestHeader.Get("Range") == "" {
+		if checksum := info.Headers[layer.AttributeChecksum]; checksum != "" {
+			h.Set(api.AmzChecksumPrefix+info.Headers[layer.AttributeChecksumAlgorithm], checksum)
+		}
+	}
+
 	for key, val := range info.Headers {
 		if layer.IsSystemHeader(key) {
 			continue
@@ -120,18 +184,51 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 		reqInfo = api.GetReqInfo(r.Context())
 	)
 
+	if token := reqInfo.URL.Query().Get(downloadTokenQuery); len(token) > 0 {
+		ctx, err := h.useDownloadToken(r, reqInfo, token)
+		if err != nil {
+			h.logAndSendError(w, "invalid download token", reqInfo, err)
+			return
+		}
+		r = r.WithContext(ctx)
+
+		// token isn't burnt if the download failed because of the gateway or the storage
+		tw := &downloadTokenWriter{ResponseWriter: w}
+		w = tw
+		defer func() {
+			h.downloadTokens.done(token, tw.status >= http.StatusInternalServerError)
+		}()
+	}
+
 	conditional, err := parseConditionalHeaders(r.Header)
 	if err != nil {
 		h.logAndSendError(w, "could not parse request params", reqInfo, err)
 		return
 	}
 
+	query := reqInfo.URL.Query()
+	if hasResponseOverrides(query) && !layer.IsAuthenticatedRequest(r.Context()) {
+		h.logAndSendError(w, "response headers override in anonymous request", reqInfo,
+			errors.GetAPIErrorWithError(errors.ErrInvalidRequest, errAnonymousOverrides))
+		return
+	}
+
 	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
 	if err != nil {
 		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
 		return
 	}
 
+	if h.cfg.SOSAPIEnabled && isSOSAPIObject(reqInfo.ObjectName) {
+		h.serveSOSAPIObject(w, r, reqInfo)
+		return
+	}
+
+	if strings.HasSuffix(reqInfo.ObjectName, api.SlashSeparator) && h.htmlListingRequested(r) {
+		h.writeHTMLListing(w, r, bktInfo, reqInfo.ObjectName)
+		return
+	}
+
 	p := &layer.HeadObjectParams{
 		BktInfo:   bktInfo,
 		Object:    reqInfo.ObjectName,
@@ -150,19 +247,25 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if info.Archive != nil && !info.Restore.Restored() {
+		h.logAndSendError(w, "archived object isn't restored", reqInfo, errors.GetAPIError(errors.ErrInvalidObjectState))
+		return
+	}
+
 	encryptionParams, err := formEncryptionParams(r)
 	if err != nil {
 		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
 		return
 	}
 
-	if err = encryptionParams.MatchObjectEncryption(layer.FormEncryptionInfo(info.Headers)); err != nil {
+	encInfo := layer.FormEncryptionInfo(info.Headers)
+	if err = encryptionParams.MatchObjectEncryption(encInfo); err != nil {
 		h.logAndSendError(w, "encryption doesn't match object", reqInfo, errors.GetAPIError(errors.ErrBadRequest), zap.Error(err))
 		return
 	}
 
 	fullSize := info.Size
-	if encryptionParams.Enabled() {
+	if encInfo.Enabled {
 		if fullSize, err = strconv.ParseInt(info.Headers[layer.AttributeDecryptedSize], 10, 64); err != nil {
 			h.logAndSendError(w, "invalid decrypted size header", reqInfo, errors.GetAPIError(errors.ErrBadRequest))
 			return
@@ -170,6 +273,9 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if params, err = fetchRangeHeader(r.Header, uint64(fullSize)); err != nil {
+		if errors.IsS3Error(err, errors.ErrInvalidRange) {
+			w.Header().Set(api.ContentRange, fmt.Sprintf("bytes */%d", fullSize))
+		}
 		h.logAndSendError(w, "could not parse range header", reqInfo, err)
 		return
 	}
@@ -186,8 +292,9 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if layer.IsAuthenticatedRequest(r.Context()) {
-		overrideResponseHeaders(w.Header(), reqInfo.URL.Query())
+	if h.obj.Quarantined(tagSet) {
+		h.logAndSendError(w, "object is quarantined", reqInfo, errors.GetAPIErrorWithError(errors.ErrAccessDenied, layer.ErrQuarantined))
+		return
 	}
 
 	if err = h.setLockingHeaders(bktInfo, lockInfo, w.Header()); err != nil {
@@ -202,8 +309,14 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeHeaders(w.Header(), r.Header, extendedInfo, len(tagSet), bktSettings.Unversioned())
+	if len(p.VersionID) == 0 {
+		// only the latest versions expire
+		h.setExpirationHeader(r.Context(), w.Header(), bktInfo, info, tagSet)
+	}
+	// overrides take precedence over headers of the object
+	overrideResponseHeaders(w.Header(), query)
 	if params != nil {
-		writeRangeHeaders(w, params, info.Size)
+		writeRangeHeaders(w, params, fullSize)
 	} else {
 		w.WriteHeader(http.StatusOK)
 	}
@@ -221,10 +334,10 @@ func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func checkPreconditions(info *data.ObjectInfo, args *conditionalArgs) error {
-	if len(args.IfMatch) > 0 && args.IfMatch != info.HashSum {
+	if len(args.IfMatch) > 0 && !etagMatches(args.IfMatch, info.HashSum) {
 		return errors.GetAPIError(errors.ErrPreconditionFailed)
 	}
-	if len(args.IfNoneMatch) > 0 && args.IfNoneMatch == info.HashSum {
+	if len(args.IfNoneMatch) > 0 && etagMatches(args.IfNoneMatch, info.HashSum) {
 		return errors.GetAPIError(errors.ErrNotModified)
 	}
 	if args.IfModifiedSince != nil && info.Created.Before(*args.IfModifiedSince) {
@@ -239,6 +352,12 @@ func checkPreconditions(info *data.ObjectInfo, args *conditionalArgs) error {
 	return nil
 }
 
+// etagMatches compares the ETag from the conditional header with the object ETag,
+// clients can send the ETag in quotes.
+func etagMatches(header, etag string) bool {
+	return strings.Trim(header, `"`) == etag
+}
+
 func parseConditionalHeaders(headers http.Header) (*conditionalArgs, error) {
 	var err error
 	args := &conditionalArgs{
@@ -2,16 +2,22 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/s3select"
+	"github.com/nspcc-dev/neofs-s3-gw/api/scanning"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestFetchRangeHeader(t *testing.T) {
@@ -35,6 +41,9 @@ func TestFetchRangeHeader(t *testing.T) {
 		{header: "bytes:-", err: true},
 		{header: "bytes=0-0", fullSize: 0, err: true},
 		{header: "bytes=10-20", fullSize: 5, err: true},
+		{header: "bytes=5-", fullSize: 5, err: true},
+		{header: "bytes=-0", fullSize: 5, err: true},
+		{header: "bytes=-10", expected: &layer.RangeParams{Start: 0, End: 4}, fullSize: 5, err: false},
 	} {
 		h := make(http.Header)
 		h.Add("Range", tc.header)
@@ -170,6 +179,83 @@ func TestGetRange(t *testing.T) {
 	require.Equal(t, "bcdef", string(end))
 }
 
+func TestGetRangeOfVersion(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-range-of-version", "object-to-range"
+	createTestBucket(tc, bktName)
+	putBucketVersioning(t, tc, bktName, true)
+
+	content := "123456789abcdef"
+	putObjectContent(tc, bktName, objName, content)
+	versions := listVersions(t, tc, bktName)
+	require.Len(t, versions.Version, 1)
+	versionID := versions.Version[0].VersionID
+	putObjectContent(tc, bktName, objName, "12345")
+
+	query := make(url.Values)
+	query.Add(api.QueryVersionID, versionID)
+	w, r := prepareTestFullRequest(tc, bktName, objName, query, nil)
+	r.Header.Set("Range", "bytes=10-20")
+	tc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusPartialContent)
+	require.Equal(t, "bytes 10-14/15", w.Header().Get(api.ContentRange))
+	payload, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	require.Equal(t, content[10:], string(payload))
+
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	r.Header.Set("Range", "bytes=10-20")
+	tc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidRange))
+	require.Equal(t, "bytes */5", w.Header().Get(api.ContentRange))
+}
+
+func TestGetObjectResponseOverrides(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-overrides", "object"
+	createTestBucket(hc, bktName)
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader([]byte("content")))
+	r.Header.Set(api.ContentType, "text/plain")
+	r.Header.Set(api.CacheControl, "no-cache")
+	r.Header.Set(api.Expires, "Thu, 01 Dec 1994 16:00:00 GMT")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	query := make(url.Values)
+	query.Set("response-content-type", "application/json")
+	query.Set("response-content-disposition", `attachment; filename="file.json"`)
+	query.Set("response-content-encoding", "gzip")
+	query.Set("response-content-language", "en-US")
+	query.Set("response-cache-control", "max-age=60")
+	query.Set("response-expires", "Wed, 21 Oct 2015 07:28:00 GMT")
+
+	w, r = prepareTestFullRequest(hc, bktName, objName, query, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "application/json", w.Header().Get(api.ContentType))
+	require.Equal(t, `attachment; filename="file.json"`, w.Header().Get(api.ContentDisposition))
+	require.Equal(t, "gzip", w.Header().Get(api.ContentEncoding))
+	require.Equal(t, "en-US", w.Header().Get(api.ContentLanguage))
+	require.Equal(t, "max-age=60", w.Header().Get(api.CacheControl))
+	require.Equal(t, "Wed, 21 Oct 2015 07:28:00 GMT", w.Header().Get(api.Expires))
+	require.Equal(t, "content", w.Body.String())
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "text/plain", w.Header().Get(api.ContentType))
+	require.Equal(t, "no-cache", w.Header().Get(api.CacheControl))
+	require.Equal(t, "Thu, 01 Dec 1994 16:00:00 GMT", w.Header().Get(api.Expires))
+
+	w, r = prepareTestFullRequest(hc, bktName, objName, query, nil)
+	r = r.WithContext(api.SetReqInfo(context.Background(), api.GetReqInfo(r.Context())))
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIErrorWithError(errors.ErrInvalidRequest, errAnonymousOverrides))
+}
+
 func putObjectContent(hc *handlerContext, bktName, objName, content string) {
 	body := bytes.NewReader([]byte(content))
 	w, r := prepareTestPayloadRequest(hc, bktName, objName, body)
@@ -186,3 +272,122 @@ func getObjectRange(t *testing.T, tc *handlerContext, bktName, objName string, s
 	require.NoError(t, err)
 	return content
 }
+
+func TestGetQuarantinedObject(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-scanning"
+	createTestBucket(hc, bktName)
+
+	scanner := scanning.NewScanner(zap.NewNop(), hc.Layer(), testScanHook{}, scanning.Config{Workers: 1})
+	hc.h.cfg.Scanner = scanner
+
+	putObjectContent(hc, bktName, "clean", "content")
+	putObjectContent(hc, bktName, "infected", "EICAR content")
+
+	// objects are queued by the requests, so they are scanned after all puts
+	scanner.Start(context.Background())
+	scanner.Stop()
+
+	require.Equal(t, "content", getObjectContent(t, hc, bktName, "clean"))
+	require.Empty(t, getObjectTagging(t, hc, bktName, "clean", "").TagSet)
+
+	w, r := prepareTestRequest(hc, bktName, "infected", nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	tagging := getObjectTagging(t, hc, bktName, "infected", "")
+	require.Equal(t, []Tag{{Key: scanning.DefaultQuarantineTagKey, Value: scanning.DefaultQuarantineTagValue}}, tagging.TagSet)
+
+	headObject(t, hc, bktName, "infected", nil, http.StatusForbidden)
+	copyObject(t, hc, bktName, "infected", "copy", CopyMeta{}, http.StatusForbidden)
+
+	w, r = prepareTestRequest(hc, bktName, "concatenated", &ConcatenateObjects{
+		Sources: []ConcatenateSource{{Key: "clean"}, {Key: "infected"}},
+	})
+	hc.Handler().ConcatenateObjectsHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	w, r = prepareTestRequest(hc, bktName, "infected", &SelectObjectContentRequest{
+		Expression:          "SELECT * FROM S3Object",
+		ExpressionType:      "SQL",
+		InputSerialization:  &s3select.InputSerialization{CSV: &s3select.CSVInput{}},
+		OutputSerialization: &s3select.OutputSerialization{CSV: &s3select.CSVOutput{}},
+	})
+	hc.Handler().SelectObjectContentHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	putBucketWebsite(hc, bktName, &data.WebsiteConfiguration{IndexDocument: &data.WebsiteIndexDocument{Suffix: "index.html"}}, http.StatusOK)
+	serveWebsite(hc, bktName, "clean", http.StatusOK)
+	serveWebsite(hc, bktName, "infected", http.StatusForbidden)
+
+	// only operators can drop the quarantine tag
+	w, r = prepareTestRequest(hc, bktName, "infected", &Tagging{TagSet: []Tag{{Key: "reviewed", Value: "true"}}})
+	hc.Handler().PutObjectTaggingHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	w, r = prepareTestRequest(hc, bktName, "infected", nil)
+	hc.Handler().DeleteObjectTaggingHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	putObjectTagging(t, hc, bktName, "infected", map[string]string{
+		scanning.DefaultQuarantineTagKey: scanning.DefaultQuarantineTagValue,
+		"reviewed":                       "false",
+	})
+
+	hc.h.cfg.Operators = testOperators{}
+	w, r = prepareTestRequest(hc, bktName, "infected", nil)
+	hc.Handler().DeleteObjectTaggingHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	require.Equal(t, "EICAR content", getObjectContent(t, hc, bktName, "infected"))
+}
+
+// testOperators recognizes all requests as requests of operators.
+type testOperators struct{}
+
+func (testOperators) Allowed(*http.Request) bool {
+	return true
+}
+
+type testScanHook struct{}
+
+func (testScanHook) Scan(_ context.Context, _ *scanning.Object, payload io.Reader) (*scanning.Result, error) {
+	content, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &scanning.Result{Infected: bytes.HasPrefix(content, []byte("EICAR")), Signature: "EICAR-Test-File"}, nil
+}
+
+func TestGetZeroByteObjectAndDeleteMarker(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-delete-marker", "object"
+	createTestBucket(hc, bktName)
+	putBucketVersioning(t, hc, bktName, true)
+	putObjectContent(hc, bktName, objName, "")
+
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "0", w.Header().Get(api.ContentLength))
+	require.Empty(t, w.Body.Bytes())
+
+	markerVersion, isDeleteMarker := deleteObject(t, hc, bktName, objName, "")
+	require.True(t, isDeleteMarker)
+
+	query := make(url.Values)
+	query.Add(api.QueryVersionID, markerVersion)
+
+	w, r = prepareTestFullRequest(hc, bktName, objName, query, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrMethodNotAllowed))
+	require.Equal(t, "true", w.Header().Get(api.AmzDeleteMarker))
+	require.Equal(t, markerVersion, w.Header().Get(api.AmzVersionID))
+	require.NotEmpty(t, w.Header().Get(api.LastModified))
+
+	w, r = prepareTestFullRequest(hc, bktName, objName, query, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusMethodNotAllowed)
+	require.Equal(t, "true", w.Header().Get(api.AmzDeleteMarker))
+}
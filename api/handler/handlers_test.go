@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"math/rand"
 	"net/http"
@@ -15,10 +16,14 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/api/scanning"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	cidtest "github.com/nspcc-dev/neofs-sdk-go/container/id/test"
 	"github.com/nspcc-dev/neofs-sdk-go/netmap"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
@@ -27,6 +32,54 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	testKMSDefaultKey = "default-key"
+	testKMSOtherKey   = "other-key"
+
+	testArchiveStorageClass = "GLACIER"
+)
+
+// testKMS wraps data keys with master keys named by KMS key IDs.
+type testKMS struct {
+	keys map[string]*encryption.MasterKey
+}
+
+func newTestKMS(t testing.TB, keyIDs ...string) *testKMS {
+	kms := &testKMS{keys: make(map[string]*encryption.MasterKey, len(keyIDs))}
+	for _, keyID := range keyIDs {
+		masterKey, err := encryption.DeriveMasterKey([]byte(keyID))
+		require.NoError(t, err)
+		kms.keys[keyID] = masterKey
+	}
+	return kms
+}
+
+func (k *testKMS) GenerateDataKey(_ context.Context, keyID string) ([]byte, []byte, error) {
+	masterKey, ok := k.keys[keyID]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown kms key '%s'", keyID)
+	}
+
+	params, err := masterKey.NewParams(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return params.Key(), params.WrappedKey(), nil
+}
+
+func (k *testKMS) Decrypt(_ context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	masterKey, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown kms key '%s'", keyID)
+	}
+
+	params, err := masterKey.UnwrapParams(wrappedKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	return params.Key(), nil
+}
+
 type handlerContext struct {
 	owner   user.ID
 	t       *testing.T
@@ -64,6 +117,14 @@ func (p *placementPolicyMock) Get(string) (netmap.PlacementPolicy, bool) {
 }
 
 func prepareHandlerContext(t *testing.T) *handlerContext {
+	hc := newHandlerContext(t)
+	hc.t = t
+	return hc
+}
+
+// newHandlerContext creates the handler context without the test, it's used by benchmarks
+// which don't call test helpers.
+func newHandlerContext(t testing.TB) *handlerContext {
 	key, err := keys.NewPrivateKey()
 	require.NoError(t, err)
 
@@ -78,28 +139,42 @@ func prepareHandlerContext(t *testing.T) *handlerContext {
 	var owner user.ID
 	user.IDFromKey(&owner, key.PrivateKey.PublicKey)
 
+	masterKey, err := encryption.DeriveMasterKey(key.Bytes())
+	require.NoError(t, err)
+
 	layerCfg := &layer.Config{
 		Caches:      layer.DefaultCachesConfigs(zap.NewExample()),
 		AnonKey:     layer.AnonymousKey{Key: key},
 		Resolver:    testResolver,
 		TreeService: layer.NewTreeService(),
+		MasterKey:   masterKey,
+		KMS:         newTestKMS(t, testKMSDefaultKey, testKMSOtherKey),
+		KMSKeyID:    testKMSDefaultKey,
+
+		ArchiveStorageClasses: map[string]cid.ID{testArchiveStorageClass: cidtest.ID()},
+		QuarantineTagKey:      scanning.DefaultQuarantineTagKey,
+		QuarantineTagValue:    scanning.DefaultQuarantineTagValue,
 	}
 
 	var pp netmap.PlacementPolicy
 	err = pp.DecodeString("REP 1")
 	require.NoError(t, err)
 
+	obj := layer.NewLayer(l, tp, layerCfg)
 	h := &handler{
-		log: l,
-		obj: layer.NewLayer(l, tp, layerCfg),
+		log:         l,
+		obj:         obj,
+		lifecycle:   obj,
+		replication: obj,
 		cfg: &Config{
-			Policy: &placementPolicyMock{defaultPolicy: pp},
+			Policy:        &placementPolicyMock{defaultPolicy: pp},
+			PresignNonces: auth.NewPresignNonces(false),
 		},
+		downloadTokens: newDownloadTokens(),
 	}
 
 	return &handlerContext{
 		owner:   owner,
-		t:       t,
 		h:       h,
 		tp:      tp,
 		context: context.WithValue(context.Background(), api.BoxData, newTestAccessBox(t, key)),
@@ -34,6 +34,11 @@ func (h *handler) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.cfg.SOSAPIEnabled && isSOSAPIObject(reqInfo.ObjectName) {
+		h.serveSOSAPIObject(w, r, reqInfo)
+		return
+	}
+
 	conditional, err := parseConditionalHeaders(r.Header)
 	if err != nil {
 		h.logAndSendError(w, "could not parse request params", reqInfo, err)
@@ -81,8 +86,16 @@ func (h *handler) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.obj.Quarantined(tagSet) {
+		h.logAndSendError(w, "object is quarantined", reqInfo, errors.GetAPIErrorWithError(errors.ErrAccessDenied, layer.ErrQuarantined))
+		return
+	}
+
 	if len(info.ContentType) == 0 {
-		if info.ContentType = layer.MimeByFilePath(info.Name); len(info.ContentType) == 0 {
+		if info.ContentType = layer.MimeByFilePath(info.Name); len(info.ContentType) == 0 && info.Size == 0 {
+			// there is no payload to detect content type, e.g. directory marker object
+			info.ContentType = http.DetectContentType(nil)
+		} else if len(info.ContentType) == 0 {
 			buffer := bytes.NewBuffer(make([]byte, 0, sizeToDetectType))
 			getParams := &layer.GetObjectParams{
 				ObjectInfo: info,
@@ -110,6 +123,10 @@ func (h *handler) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeHeaders(w.Header(), r.Header, extendedInfo, len(tagSet), bktSettings.Unversioned())
+	if len(p.VersionID) == 0 {
+		// only the latest versions expire
+		h.setExpirationHeader(r.Context(), w.Header(), bktInfo, info, tagSet)
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
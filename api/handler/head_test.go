@@ -86,7 +86,47 @@ func TestInvalidAccessThroughCache(t *testing.T) {
 	assertStatus(t, w, http.StatusForbidden)
 }
 
-func newTestAccessBox(t *testing.T, key *keys.PrivateKey) *accessbox.Box {
+func TestHeadObjectLockHeaders(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-lock-headers"
+	bktInfo := createTestBucketWithLock(tc, bktName, nil)
+
+	objName := "obj-without-lock"
+	createTestObject(tc, bktInfo, objName)
+
+	w, r := prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, legalHoldOff, w.Header().Get(api.AmzObjectLockLegalHold))
+	require.Empty(t, w.Header().Get(api.AmzObjectLockMode))
+	require.Empty(t, w.Header().Get(api.AmzObjectLockRetainUntilDate))
+
+	objName = "obj-with-lock"
+	untilDate := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	r.Header.Set(api.AmzObjectLockMode, complianceMode)
+	r.Header.Set(api.AmzObjectLockLegalHold, legalHoldOn)
+	r.Header.Set(api.AmzObjectLockRetainUntilDate, untilDate)
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, legalHoldOn, w.Header().Get(api.AmzObjectLockLegalHold))
+	require.Equal(t, complianceMode, w.Header().Get(api.AmzObjectLockMode))
+	require.Equal(t, untilDate, w.Header().Get(api.AmzObjectLockRetainUntilDate))
+
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, legalHoldOn, w.Header().Get(api.AmzObjectLockLegalHold))
+	require.Equal(t, complianceMode, w.Header().Get(api.AmzObjectLockMode))
+	require.Equal(t, untilDate, w.Header().Get(api.AmzObjectLockRetainUntilDate))
+}
+
+func newTestAccessBox(t testing.TB, key *keys.PrivateKey) *accessbox.Box {
 	var err error
 	if key == nil {
 		key, err = keys.NewPrivateKey()
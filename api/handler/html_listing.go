@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+)
+
+type (
+	// htmlListing is the data of the HTML listing template.
+	htmlListing struct {
+		Bucket string
+		Prefix string
+		// Parent is a link to the parent directory, it's empty for the bucket root.
+		Parent      string
+		Entries     []htmlListingEntry
+		IsTruncated bool
+	}
+
+	htmlListingEntry struct {
+		Name         string
+		Href         string
+		IsDir        bool
+		Size         int64
+		LastModified time.Time
+	}
+)
+
+const defaultListingTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Index of {{.Bucket}}/{{.Prefix}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.2em 1.5em 0.2em 0; text-align: left; }
+td.size { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Index of {{.Bucket}}/{{.Prefix}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Last modified</th></tr>
+{{- if .Parent}}
+<tr><td><a href="{{.Parent}}">../</a></td><td></td><td></td></tr>
+{{- end}}
+{{- range .Entries}}
+<tr><td><a href="{{.Href}}">{{.Name}}</a></td>
+{{- if .IsDir}}<td></td><td></td>{{else}}<td class="size">{{.Size}}</td><td>{{.LastModified.UTC.Format "2006-01-02 15:04:05"}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- if .IsTruncated}}
+<p>The listing is truncated.</p>
+{{- end}}
+</body>
+</html>
+`
+
+// ParseListingTemplate parses the template of HTML listings, the default template is used if the text is empty.
+func ParseListingTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultListingTemplate
+	}
+	return template.New("listing").Parse(text)
+}
+
+// htmlListingRequested returns true if the anonymous request of the browser gets the HTML listing of
+// the directory instead of the XML response.
+func (h *handler) htmlListingRequested(r *http.Request) bool {
+	if h.cfg.ListingTemplate == nil {
+		return false
+	}
+	if _, ok := r.Context().Value(api.BoxData).(*accessbox.Box); ok {
+		return false
+	}
+
+	for _, accept := range strings.Split(r.Header.Get(api.Accept), ",") {
+		if mediaType := strings.TrimSpace(strings.Split(accept, ";")[0]); mediaType == "text/html" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeHTMLListing writes the HTML listing of objects and directories of the bucket with the prefix.
+// Links are absolute paths of the request, so the listing works on the bucket and on the website endpoints.
+func (h *handler) writeHTMLListing(w http.ResponseWriter, r *http.Request, bktInfo *data.BucketInfo, prefix string) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if err := h.checkListingPolicy(r.Context(), bktInfo); err != nil {
+		h.logAndSendError(w, "listing is denied", reqInfo, err)
+		return
+	}
+
+	list, err := h.obj.ListObjectsV1(r.Context(), &layer.ListObjectsParamsV1{
+		ListObjectsParamsCommon: layer.ListObjectsParamsCommon{
+			BktInfo:   bktInfo,
+			Delimiter: api.SlashSeparator,
+			MaxKeys:   maxObjectList,
+			Prefix:    prefix,
+		},
+	})
+	if err != nil {
+		h.logAndSendError(w, "could not list objects", reqInfo, err)
+		return
+	}
+
+	// the path of the bucket root is the request path without the prefix
+	root := strings.TrimSuffix(r.URL.Path, prefix)
+	if !strings.HasSuffix(root, api.SlashSeparator) {
+		root += api.SlashSeparator
+	}
+
+	listing := &htmlListing{
+		Bucket:      bktInfo.Name,
+		Prefix:      prefix,
+		IsTruncated: list.IsTruncated,
+	}
+	if prefix != "" {
+		listing.Parent = root + escapeKey(path.Dir(strings.TrimSuffix(prefix, api.SlashSeparator))+api.SlashSeparator)
+		if listing.Parent == root+"./" {
+			listing.Parent = root
+		}
+	}
+
+	for _, dir := range list.Prefixes {
+		listing.Entries = append(listing.Entries, htmlListingEntry{
+			Name:  strings.TrimPrefix(dir, prefix),
+			Href:  root + escapeKey(dir),
+			IsDir: true,
+		})
+	}
+	for _, obj := range list.Objects {
+		if obj.Name == prefix {
+			// the object of the directory itself
+			continue
+		}
+		listing.Entries = append(listing.Entries, htmlListingEntry{
+			Name:         strings.TrimPrefix(obj.Name, prefix),
+			Href:         root + escapeKey(obj.Name),
+			Size:         obj.Size,
+			LastModified: obj.Created,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err = h.cfg.ListingTemplate.Execute(&buf, listing); err != nil {
+		h.logAndSendError(w, "could not execute listing template", reqInfo, err)
+		return
+	}
+
+	w.Header().Set(api.ContentType, "text/html; charset=utf-8")
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// escapeKey escapes path segments of the object key.
+func escapeKey(key string) string {
+	segments := strings.Split(key, api.SlashSeparator)
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, api.SlashSeparator)
+}
+
+// checkListingPolicy evaluates the bucket policy for the anonymous listing. The listing of the directory
+// is served by object and website routes, so the policy is evaluated for s3:ListBucket action here.
+func (h *handler) checkListingPolicy(ctx context.Context, bktInfo *data.BucketInfo) error {
+	decision := policy.NoDecision
+	bktPolicy, err := h.obj.GetBucketPolicy(ctx, bktInfo)
+	if err != nil {
+		if !errors.IsS3Error(err, errors.ErrNoSuchBucketPolicy) {
+			return err
+		}
+		bktPolicy = nil
+	} else {
+		decision = bktPolicy.Evaluate(policy.Request{
+			Action:   s3ListBucket,
+			Resource: policy.ArnPrefix + bktInfo.Name,
+		})
+	}
+
+	if decision == policy.Deny {
+		return errors.GetAPIError(errors.ErrAccessDenied)
+	}
+
+	return h.checkPublicAccess(ctx, bktInfo, bktPolicy, decision)
+}
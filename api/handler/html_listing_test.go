@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLListing(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-html-listing"
+	createTestBucket(hc, bktName)
+
+	putObjectContent(hc, bktName, "index.txt", "content")
+	putObjectContent(hc, bktName, "dir/b c.txt", "content")
+	putObjectContent(hc, bktName, "dir/sub/d", "content")
+
+	tmpl, err := ParseListingTemplate("")
+	require.NoError(t, err)
+	hc.h.cfg.ListingTemplate = tmpl
+
+	anonymousRequest := func(objName, path string) (*httptest.ResponseRecorder, *http.Request) {
+		w, r := prepareTestRequestWithQuery(hc, bktName, objName, make(url.Values), nil)
+		r = r.WithContext(api.SetReqInfo(context.Background(), api.GetReqInfo(r.Context())))
+		r.Method = http.MethodGet
+		r.URL.Path = path
+		r.Header.Set(api.Accept, "text/html,application/xhtml+xml;q=0.9")
+		return w, r
+	}
+
+	w, r := anonymousRequest("", "/"+bktName)
+	hc.Handler().ListObjectsV1Handler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "text/html; charset=utf-8", w.Header().Get(api.ContentType))
+	body := w.Body.String()
+	require.Contains(t, body, `<a href="/`+bktName+`/dir/">dir/</a>`)
+	require.Contains(t, body, `<a href="/`+bktName+`/index.txt">index.txt</a>`)
+	require.NotContains(t, body, "../")
+
+	w, r = anonymousRequest("dir/", "/"+bktName+"/dir/")
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	body = w.Body.String()
+	require.Contains(t, body, `<a href="/`+bktName+`/">../</a>`)
+	require.Contains(t, body, `<a href="/`+bktName+`/dir/sub/">sub/</a>`)
+	require.Contains(t, body, `<a href="/`+bktName+`/dir/b%20c.txt">b c.txt</a>`)
+	require.NotContains(t, body, "index.txt")
+
+	// signed requests of SDKs get XML listing
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	r.Header.Set(api.Accept, "text/html")
+	hc.Handler().ListObjectsV1Handler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.True(t, strings.HasPrefix(w.Body.String(), "<?xml"))
+}
+
+func TestHTMLListingDeniedByPolicy(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-denied-html-listing"
+	box, _ := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+	putObjectContent(hc, bktName, "dir/obj", "content")
+
+	tmpl, err := ParseListingTemplate("")
+	require.NoError(t, err)
+	hc.h.cfg.ListingTemplate = tmpl
+
+	putBucketPolicy(hc, bktName, &bucketPolicy{
+		Statement: []statement{{
+			Effect:    "Deny",
+			Principal: principal{AWS: allUsersWildcard},
+			Action:    []string{s3ListBucket},
+			Resource:  []string{arnAwsPrefix + bktName},
+		}},
+	}, box, http.StatusOK)
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, "dir/", make(url.Values), nil)
+	r = r.WithContext(api.SetReqInfo(context.Background(), api.GetReqInfo(r.Context())))
+	r.URL.Path = "/" + bktName + "/dir/"
+	r.Header.Set(api.Accept, "text/html")
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+	require.NotContains(t, w.Body.String(), "obj")
+}
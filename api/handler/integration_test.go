@@ -0,0 +1,451 @@
+//go:build integration
+// +build integration
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	cidtest "github.com/nspcc-dev/neofs-sdk-go/container/id/test"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	// s3aContractTests are the test suites of hadoop-aws module run against the gateway.
+	s3aContractTests = "ITestS3AContract*"
+
+	// s3aContractProfile is auth-keys.xml of hadoop-aws module,
+	// see docs/hadoop_s3a.md for details.
+	s3aContractProfile = `<configuration>
+  <property>
+    <name>test.fs.s3a.name</name>
+    <value>s3a://%s/</value>
+  </property>
+  <property>
+    <name>fs.contract.test.fs.s3a</name>
+    <value>${test.fs.s3a.name}</value>
+  </property>
+  <property>
+    <name>fs.s3a.endpoint</name>
+    <value>%s</value>
+  </property>
+  <property>
+    <name>fs.s3a.connection.ssl.enabled</name>
+    <value>false</value>
+  </property>
+  <property>
+    <name>fs.s3a.path.style.access</name>
+    <value>true</value>
+  </property>
+  <property>
+    <name>fs.s3a.access.key</name>
+    <value>%s</value>
+  </property>
+  <property>
+    <name>fs.s3a.secret.key</name>
+    <value>%s</value>
+  </property>
+  <property>
+    <name>test.fs.s3a.encryption.enabled</name>
+    <value>false</value>
+  </property>
+  <property>
+    <name>test.fs.s3a.sts.enabled</name>
+    <value>false</value>
+  </property>
+  <property>
+    <name>fs.s3a.scale.test.csvfile</name>
+    <value></value>
+  </property>
+</configuration>
+`
+)
+
+// integrationServer is HTTP server with S3 API routes served by mocked NeoFS backend
+// and credentials of the access box which is issued for the server.
+type integrationServer struct {
+	*httptest.Server
+	accessKeyID     string
+	secretAccessKey string
+	nonces          *auth.PresignNonces
+}
+
+// integrationCredentials stores access boxes in memory.
+type integrationCredentials struct {
+	objects map[string][]byte
+}
+
+func (c *integrationCredentials) CreateObject(_ context.Context, prm tokens.PrmObjectCreate) (oid.ID, error) {
+	var id oid.ID
+	id.SetSHA256(sha256.Sum256(prm.Payload))
+
+	var addr oid.Address
+	addr.SetContainer(prm.Container)
+	addr.SetObject(id)
+	c.objects[addr.EncodeToString()] = prm.Payload
+
+	return id, nil
+}
+
+func (c *integrationCredentials) ReadObjectPayload(_ context.Context, addr oid.Address) ([]byte, error) {
+	payload, ok := c.objects[addr.EncodeToString()]
+	if !ok {
+		return nil, fmt.Errorf("object not found %s", addr)
+	}
+	return payload, nil
+}
+
+// prepareIntegrationServer starts HTTP server with S3 API routes served by mocked NeoFS backend.
+// Requests are authenticated by the gateway auth center, so they must be signed
+// with the credentials of the returned server.
+func prepareIntegrationServer(t *testing.T, buckets ...string) *integrationServer {
+	hc := prepareHandlerContext(t)
+	for _, bktName := range buckets {
+		createTestBucket(hc, bktName)
+	}
+
+	gateKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	bearerToken := hc.Context().Value(api.BoxData).(*accessbox.Box).Gate.BearerToken
+	box, secrets, err := accessbox.PackTokens([]*accessbox.GateData{accessbox.NewGateData(gateKey.PublicKey(), bearerToken)})
+	require.NoError(t, err)
+
+	credsNeoFS := &integrationCredentials{objects: make(map[string][]byte)}
+	cacheCfg := cache.DefaultAccessBoxConfig(zap.NewNop())
+	addr, err := tokens.New(credsNeoFS, gateKey, cacheCfg).Put(hc.Context(), cidtest.ID(), hc.owner, box, math.MaxUint64, gateKey.PublicKey())
+	require.NoError(t, err)
+
+	center := auth.New(credsNeoFS, gateKey, nil, cacheCfg, hc.h.cfg.PresignNonces, nil)
+
+	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
+	api.Attach(router, nil, nil, api.NewMaxClientsMiddleware(1, 0), nil, nil, nil, nil, hc.Handler(), center, nil, zap.NewNop())
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	return &integrationServer{
+		Server:          srv,
+		accessKeyID:     strings.ReplaceAll(addr.EncodeToString(), "/", "0"),
+		secretAccessKey: secrets.AccessKey,
+		nonces:          hc.h.cfg.PresignNonces,
+	}
+}
+
+func TestIntegrationAuth(t *testing.T) {
+	bktName := "auth"
+	srv := prepareIntegrationServer(t, bktName)
+
+	for _, tc := range []struct {
+		name   string
+		secret string
+		status int
+	}{
+		{name: "valid signature", secret: srv.secretAccessKey, status: http.StatusOK},
+		{name: "invalid signature", secret: "invalid-secret-access-key", status: http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/"+bktName+"?list-type=2", nil)
+			require.NoError(t, err)
+
+			signer := v4.NewSigner(credentials.NewStaticCredentials(srv.accessKeyID, tc.secret, ""))
+			_, err = signer.Sign(req, nil, "s3", "us-east-1", time.Now())
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestIntegrationPresignedMultipart(t *testing.T) {
+	bktName, objName := "presigned", "unload/data 0.csv"
+	srv := prepareIntegrationServer(t, bktName)
+	objURL := srv.URL + "/" + bktName + "/unload/data%200.csv"
+
+	signer := v4.NewSigner(credentials.NewStaticCredentials(srv.accessKeyID, srv.secretAccessKey, ""))
+	signer.DisableURIPathEscaping = true
+
+	presignedDo := func(method, query string, header map[string]string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, objURL+"?"+query, nil)
+		require.NoError(t, err)
+		for key, val := range header {
+			req.Header.Set(key, val)
+		}
+		_, err = signer.Presign(req, nil, "s3", "us-east-1", time.Hour, time.Now())
+		require.NoError(t, err)
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		require.Equal(t, http.StatusOK, resp.StatusCode, "%s %s", method, query)
+		return resp
+	}
+
+	resp := presignedDo(http.MethodPost, "uploads", map[string]string{
+		api.ContentType:        "text/csv",
+		api.ContentDisposition: "attachment",
+	}, nil)
+	createResponse := &InitiateMultipartUploadResponse{}
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(createResponse))
+
+	content := []byte("a,b\n1,2\n")
+	resp = presignedDo(http.MethodPut, "partNumber=1&uploadId="+createResponse.UploadID, map[string]string{
+		api.ContentType: "text/csv",
+	}, content)
+	etag := resp.Header.Get(api.ETag)
+
+	completeUpload, err := xml.Marshal(&CompleteMultipartUpload{
+		Parts: []*layer.CompletedPart{{ETag: etag, PartNumber: 1}},
+	})
+	require.NoError(t, err)
+	presignedDo(http.MethodPost, "uploadId="+createResponse.UploadID, nil, completeUpload)
+
+	resp = presignedDo(http.MethodGet, "", nil, nil)
+	payload, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, content, payload, objName)
+	require.Equal(t, "text/csv", resp.Header.Get(api.ContentType))
+}
+
+func TestIntegrationPresignNonce(t *testing.T) {
+	bktName := "nonce"
+	srv := prepareIntegrationServer(t, bktName)
+	objURL := srv.URL + "/" + bktName + "/object"
+
+	signer := v4.NewSigner(credentials.NewStaticCredentials(srv.accessKeyID, srv.secretAccessKey, ""))
+	signer.DisableURIPathEscaping = true
+
+	do := func(req *http.Request) int {
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	content := []byte("content")
+	req, err := http.NewRequest(http.MethodPut, objURL, nil)
+	require.NoError(t, err)
+	_, err = signer.Sign(req, bytes.NewReader(content), "s3", "us-east-1", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, do(req))
+
+	issueNonce := func() string {
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/?presignNonce", nil)
+		require.NoError(t, err)
+		_, err = signer.Sign(req, nil, "s3", "us-east-1", time.Now())
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		nonce := &PresignNonceResponse{}
+		require.NoError(t, xml.NewDecoder(resp.Body).Decode(nonce))
+		require.Equal(t, auth.NonceQuery, nonce.Query)
+		return nonce.Nonce
+	}
+
+	presignedGet := func(nonce string) *http.Request {
+		reqURL := objURL
+		if nonce != "" {
+			reqURL += "?" + auth.NonceQuery + "=" + nonce
+		}
+		req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+		require.NoError(t, err)
+		_, err = signer.Presign(req, nil, "s3", "us-east-1", time.Hour, time.Now())
+		require.NoError(t, err)
+		return req
+	}
+
+	req = presignedGet(issueNonce())
+	require.Equal(t, http.StatusOK, do(req))
+	// the replay of the URL is rejected
+	require.Equal(t, http.StatusForbidden, do(req))
+
+	require.Equal(t, http.StatusForbidden, do(presignedGet("nonce-not-issued-by-gateway")))
+	require.Equal(t, http.StatusOK, do(presignedGet("")))
+
+	srv.nonces.SetRequired(true)
+	require.Equal(t, http.StatusForbidden, do(presignedGet("")))
+	require.Equal(t, http.StatusOK, do(presignedGet(issueNonce())))
+}
+
+func TestIntegrationRclone(t *testing.T) {
+	if _, err := exec.LookPath("rclone"); err != nil {
+		t.Skip("rclone binary not found")
+	}
+
+	bktName := "rclone"
+	srv := prepareIntegrationServer(t, bktName)
+
+	env := append(os.Environ(),
+		"RCLONE_CONFIG_NEOFS_TYPE=s3",
+		"RCLONE_CONFIG_NEOFS_PROVIDER=Other",
+		"RCLONE_CONFIG_NEOFS_ENDPOINT="+srv.URL,
+		"RCLONE_CONFIG_NEOFS_ACCESS_KEY_ID="+srv.accessKeyID,
+		"RCLONE_CONFIG_NEOFS_SECRET_ACCESS_KEY="+srv.secretAccessKey,
+		"RCLONE_CONFIG_NEOFS_FORCE_PATH_STYLE=true",
+		// multipart upload for files bigger than 5MB
+		"RCLONE_CONFIG_NEOFS_UPLOAD_CUTOFF=5M",
+		"RCLONE_CONFIG_NEOFS_CHUNK_SIZE=5M",
+		// small pages to check listing markers
+		"RCLONE_CONFIG_NEOFS_LIST_CHUNK=2",
+		// mocked backend isn't supposed to be used concurrently
+		"RCLONE_TRANSFERS=1",
+		"RCLONE_CHECKERS=1",
+	)
+
+	srcDir := t.TempDir()
+	prepareIntegrationFiles(t, srcDir)
+	remote := "neofs:" + bktName
+
+	for _, listVersion := range []string{"1", "2"} {
+		listEnv := append(env, "RCLONE_CONFIG_NEOFS_LIST_VERSION="+listVersion)
+
+		runIntegrationCmd(t, listEnv, "rclone", "sync", srcDir, remote)
+		runIntegrationCmd(t, listEnv, "rclone", "check", srcDir, remote)
+
+		require.NoError(t, os.Remove(filepath.Join(srcDir, "dir", "file 1")))
+		runIntegrationCmd(t, listEnv, "rclone", "sync", srcDir, remote)
+		runIntegrationCmd(t, listEnv, "rclone", "check", srcDir, remote)
+
+		// server-side copy must keep metadata (modification time and md5 of multipart objects)
+		runIntegrationCmd(t, listEnv, "rclone", "copyto", remote+"/big", remote+"/big-copy")
+		runIntegrationCmd(t, listEnv, "rclone", "check", "--one-way", remote+"/big", remote+"/big-copy")
+		runIntegrationCmd(t, listEnv, "rclone", "deletefile", remote+"/big-copy")
+
+		prepareIntegrationFiles(t, srcDir)
+	}
+}
+
+func TestIntegrationRestic(t *testing.T) {
+	if _, err := exec.LookPath("restic"); err != nil {
+		t.Skip("restic binary not found")
+	}
+
+	bktName := "restic"
+	srv := prepareIntegrationServer(t, bktName)
+
+	env := append(os.Environ(),
+		"RESTIC_REPOSITORY=s3:"+srv.URL+"/"+bktName,
+		"RESTIC_PASSWORD=integration",
+		"AWS_ACCESS_KEY_ID="+srv.accessKeyID,
+		"AWS_SECRET_ACCESS_KEY="+srv.secretAccessKey,
+	)
+	options := []string{"--no-cache", "-o", "s3.connections=1"}
+
+	srcDir := t.TempDir()
+	prepareIntegrationFiles(t, srcDir)
+
+	runIntegrationCmd(t, env, "restic", append(options, "init")...)
+	runIntegrationCmd(t, env, "restic", append(options, "backup", srcDir)...)
+	runIntegrationCmd(t, env, "restic", append(options, "check", "--read-data")...)
+
+	dstDir := t.TempDir()
+	runIntegrationCmd(t, env, "restic", append(options, "restore", "latest", "--target", dstDir)...)
+
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		expected, err := os.ReadFile(path)
+		require.NoError(t, err)
+		actual, err := os.ReadFile(filepath.Join(dstDir, path))
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(expected, actual), "restored file %s differs", path)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// TestIntegrationS3AContract runs S3A contract tests from hadoop-aws module sources
+// which are located in S3A_HADOOP_AWS_DIR directory.
+func TestIntegrationS3AContract(t *testing.T) {
+	hadoopAWSDir := os.Getenv("S3A_HADOOP_AWS_DIR")
+	if hadoopAWSDir == "" {
+		t.Skip("S3A_HADOOP_AWS_DIR is not set")
+	}
+	if _, err := exec.LookPath("mvn"); err != nil {
+		t.Skip("mvn binary not found")
+	}
+
+	bktName := "s3a-contract-tests"
+	srv := prepareIntegrationServer(t, bktName)
+
+	authKeys := filepath.Join(hadoopAWSDir, "src", "test", "resources", "auth-keys.xml")
+	profile := fmt.Sprintf(s3aContractProfile, bktName, srv.URL, srv.accessKeyID, srv.secretAccessKey)
+	require.NoError(t, os.WriteFile(authKeys, []byte(profile), 0644))
+	t.Cleanup(func() {
+		_ = os.Remove(authKeys)
+	})
+
+	cmd := exec.Command("mvn", "--batch-mode", "verify",
+		"-Dtest=none", "-Dsurefire.failIfNoSpecifiedTests=false", "-Dit.test="+s3aContractTests)
+	cmd.Dir = hadoopAWSDir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "S3A contract tests:\n%s", output)
+}
+
+// prepareIntegrationFiles creates small files with nested directories and names
+// which require url encoding and one file big enough for multipart upload.
+func prepareIntegrationFiles(t *testing.T, dir string) {
+	files := map[string]int{
+		"file":              10,
+		"dir/file 1":        100,
+		"dir/file+2":        1000,
+		"dir/sub/file%3":    0,
+		"another dir/файл":  10000,
+		"big":               12 << 20,
+		"dir/sub/sub/empty": 0,
+	}
+
+	for name, size := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+
+		content := make([]byte, size)
+		_, err := rand.Read(content)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, content, 0644))
+	}
+}
+
+func runIntegrationCmd(t *testing.T, env []string, name string, args ...string) {
+	cmd := exec.Command(name, args...)
+	cmd.Env = env
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s %v:\n%s", name, args, output)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+// maxInventoryConfigurationsList is the number of inventory configurations returned by a list request as AWS S3 does.
+const maxInventoryConfigurationsList = 100
+
+// ListInventoryConfigurationsResult is a response of ListBucketInventoryConfigurations request.
+type ListInventoryConfigurationsResult struct {
+	XMLName                 struct{}                      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListInventoryConfigurationsResult" json:"-"`
+	InventoryConfigurations []data.InventoryConfiguration `xml:"InventoryConfiguration"`
+	IsTruncated             bool                          `xml:"IsTruncated"`
+	ContinuationToken       string                        `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken   string                        `xml:"NextContinuationToken,omitempty"`
+}
+
+func (h *handler) GetBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketInventoryConfiguration(r.Context(), bktInfo, r.URL.Query().Get("id"))
+	if err != nil {
+		h.logAndSendError(w, "could not get inventory configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode inventory configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) ListBucketInventoryConfigurationsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	configurations, err := h.obj.ListBucketInventoryConfigurations(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not list inventory configurations", reqInfo, err)
+		return
+	}
+
+	// configurations are sorted by id, the continuation token is the id of the last returned configuration
+	token := r.URL.Query().Get("continuation-token")
+	start := sort.Search(len(configurations), func(i int) bool {
+		return configurations[i].ID > token
+	})
+	configurations = configurations[start:]
+
+	resp := &ListInventoryConfigurationsResult{
+		InventoryConfigurations: configurations,
+		ContinuationToken:       token,
+	}
+	if len(configurations) > maxInventoryConfigurationsList {
+		resp.InventoryConfigurations = configurations[:maxInventoryConfigurationsList]
+		resp.IsTruncated = true
+		resp.NextContinuationToken = resp.InventoryConfigurations[maxInventoryConfigurationsList-1].ID
+	}
+
+	if err = api.EncodeToResponse(w, resp); err != nil {
+		h.logAndSendError(w, "could not encode inventory configurations to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := new(data.InventoryConfiguration)
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "could not parse inventory configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if id := r.URL.Query().Get("id"); id != conf.ID {
+		h.logAndSendError(w, "invalid inventory id", reqInfo, errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+			fmt.Errorf("id '%s' doesn't match configuration id '%s'", id, conf.ID)))
+		return
+	}
+
+	p := &layer.PutBucketInventoryParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+		CopiesNumber:  h.cfg.CopiesNumber,
+	}
+
+	if err = h.obj.PutBucketInventoryConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not put inventory configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketInventoryConfiguration(r.Context(), bktInfo, r.URL.Query().Get("id")); err != nil {
+		h.logAndSendError(w, "could not delete inventory configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketInventory(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, dstBktName := "bucket-for-inventory", "bucket-for-inventory-reports"
+	bktInfo := createTestBucket(hc, bktName)
+	createTestBucket(hc, dstBktName)
+
+	putObjectContent(hc, bktName, "logs/a b", "content")
+	putObjectContent(hc, bktName, "data/skipped", "content")
+
+	query := url.Values{"inventory": []string{""}, "id": []string{"logs"}}
+	w, r := prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketInventoryConfigurationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchConfiguration))
+
+	conf := &data.InventoryConfiguration{
+		ID:        "logs",
+		IsEnabled: true,
+		Destination: data.InventoryDestination{S3BucketDestination: data.InventoryS3BucketDestination{
+			Bucket: "arn:aws:s3:::" + dstBktName,
+			Format: data.InventoryFormatCSV,
+			Prefix: "reports",
+		}},
+		Filter:                 &data.InventoryFilter{Prefix: "logs/"},
+		IncludedObjectVersions: data.InventoryVersionsCurrent,
+		OptionalFields:         &data.InventoryOptionalFields{Fields: []string{data.InventoryFieldSize, data.InventoryFieldETag}},
+		Schedule:               data.InventorySchedule{Frequency: data.InventoryFrequencyDaily},
+	}
+	w, r = prepareTestFullRequest(hc, bktName, "", query, conf)
+	hc.Handler().PutBucketInventoryConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	invalid := *conf
+	invalid.ID = "other"
+	w, r = prepareTestFullRequest(hc, bktName, "", query, &invalid)
+	hc.Handler().PutBucketInventoryConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	invalid.Destination.S3BucketDestination.Format = data.InventoryFormatORC
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"inventory": []string{""}, "id": []string{"other"}}, &invalid)
+	hc.Handler().PutBucketInventoryConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusNotImplemented)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketInventoryConfigurationHandler(w, r)
+	actual := &data.InventoryConfiguration{}
+	parseTestResponse(t, w, actual)
+	require.Equal(t, conf.ID, actual.ID)
+	require.Equal(t, conf.Destination, actual.Destination)
+	require.Equal(t, conf.OptionalFields, actual.OptionalFields)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"inventory": []string{""}}, nil)
+	hc.Handler().ListBucketInventoryConfigurationsHandler(w, r)
+	list := &ListInventoryConfigurationsResult{}
+	parseTestResponse(t, w, list)
+	require.Len(t, list.InventoryConfigurations, 1)
+	require.False(t, list.IsTruncated)
+
+	written, err := hc.Layer().WriteInventoryReports(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, 1, written)
+
+	// the report of the current day is written once
+	written, err = hc.Layer().WriteInventoryReports(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Zero(t, written)
+
+	var manifestKey string
+	for _, key := range listObjectKeys(t, hc, dstBktName) {
+		if strings.HasSuffix(key, "/manifest.json") {
+			manifestKey = key
+		}
+	}
+	require.True(t, strings.HasPrefix(manifestKey, "reports/"+bktName+"/logs/"))
+
+	var manifest struct {
+		SourceBucket string `json:"sourceBucket"`
+		FileFormat   string `json:"fileFormat"`
+		FileSchema   string `json:"fileSchema"`
+		Files        []struct {
+			Key string `json:"key"`
+		} `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getObjectContent(t, hc, dstBktName, manifestKey)), &manifest))
+	require.Equal(t, bktName, manifest.SourceBucket)
+	require.Equal(t, data.InventoryFormatCSV, manifest.FileFormat)
+	require.Equal(t, "Bucket, Key, Size, ETag", manifest.FileSchema)
+	require.Len(t, manifest.Files, 1)
+
+	gz, err := gzip.NewReader(bytes.NewReader([]byte(getObjectContent(t, hc, dstBktName, manifest.Files[0].Key))))
+	require.NoError(t, err)
+	report, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(report), `"`+bktName+`","logs%2Fa+b","7","`))
+	require.Equal(t, 1, strings.Count(string(report), "\n"))
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().DeleteBucketInventoryConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketInventoryConfigurationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchConfiguration))
+}
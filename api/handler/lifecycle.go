@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+func (h *handler) GetBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.lifecycle.GetBucketLifecycleConfiguration(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get lifecycle configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode lifecycle configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := new(data.LifecycleConfiguration)
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "could not parse lifecycle configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	p := &layer.PutBucketLifecycleParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+		CopiesNumber:  h.cfg.CopiesNumber,
+	}
+
+	if err = h.lifecycle.PutBucketLifecycleConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not put lifecycle configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.lifecycle.DeleteBucketLifecycleConfiguration(r.Context(), bktInfo); err != nil {
+		h.logAndSendError(w, "could not delete lifecycle configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setExpirationHeader sets x-amz-expiration header if the latest object version expires according to
+// the bucket lifecycle configuration. Failures are logged only, the header is informational.
+func (h *handler) setExpirationHeader(ctx context.Context, header http.Header, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo, tagSet map[string]string) {
+	conf, err := h.lifecycle.GetBucketLifecycleConfiguration(ctx, bktInfo)
+	if err != nil {
+		if !errors.IsS3Error(err, errors.ErrNoSuchLifecycleConfiguration) {
+			h.log.Warn("couldn't get lifecycle configuration to set expiration header", zap.Error(err),
+				zap.String("bucket", bktInfo.Name), zap.String("object", objInfo.Name))
+		}
+		return
+	}
+
+	expiry, ruleID := layer.LifecycleExpiration(conf, objInfo.Name, objInfo.Created, tagSet)
+	if expiry.IsZero() {
+		return
+	}
+
+	header.Set(api.AmzExpiration, fmt.Sprintf(`expiry-date="%s", rule-id="%s"`, expiry.Format(http.TimeFormat), ruleID))
+}
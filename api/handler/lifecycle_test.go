@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	errorsStd "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketLifecycle(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-lifecycle"
+	bktInfo := createTestBucket(hc, bktName)
+
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketLifecycleHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchLifecycleConfiguration))
+
+	putObject(t, hc, bktName, "logs/old")
+	putObject(t, hc, bktName, "data/old")
+
+	conf := &data.LifecycleConfiguration{
+		Rules: []data.LifecycleRule{{
+			ID:         "expire-logs",
+			Status:     data.LifecycleStatusEnabled,
+			Filter:     &data.LifecycleFilter{Prefix: "logs/"},
+			Expiration: &data.LifecycleExpiration{Days: 1},
+		}},
+	}
+	w, r = prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketLifecycleHandler(w, r)
+	actual := &data.LifecycleConfiguration{}
+	parseTestResponse(t, w, actual)
+	require.Equal(t, conf.Rules, actual.Rules)
+
+	expired, err := hc.Layer().ExpireObjects(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Zero(t, expired)
+
+	later := context.WithValue(hc.Context(), api.ClientTime, time.Now().Add(3*24*time.Hour))
+	expired, err = hc.Layer().ExpireObjects(later, bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+
+	checkNotFound(t, hc, bktName, "logs/old", emptyVersion)
+	checkFound(t, hc, bktName, "data/old", emptyVersion)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeleteBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketLifecycleHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchLifecycleConfiguration))
+}
+
+func TestPutBucketLifecycleInvalid(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-lifecycle-invalid"
+	createTestBucket(hc, bktName)
+
+	for _, tc := range []struct {
+		name string
+		rule data.LifecycleRule
+	}{
+		{
+			name: "invalid status",
+			rule: data.LifecycleRule{Status: "On", Expiration: &data.LifecycleExpiration{Days: 1}},
+		},
+		{
+			name: "no expiration",
+			rule: data.LifecycleRule{Status: data.LifecycleStatusEnabled},
+		},
+		{
+			name: "days and date",
+			rule: data.LifecycleRule{Status: data.LifecycleStatusEnabled, Expiration: &data.LifecycleExpiration{Days: 1, Date: "2030-01-01T00:00:00Z"}},
+		},
+		{
+			name: "date not at midnight",
+			rule: data.LifecycleRule{Status: data.LifecycleStatusEnabled, Expiration: &data.LifecycleExpiration{Date: "2030-01-01T10:00:00Z"}},
+		},
+		{
+			name: "unknown storage class",
+			rule: data.LifecycleRule{Status: data.LifecycleStatusEnabled, Transitions: []data.LifecycleTransition{{Days: 1, StorageClass: "DEEP_ARCHIVE"}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := &data.LifecycleConfiguration{Rules: []data.LifecycleRule{tc.rule}}
+			w, r := prepareTestRequest(hc, bktName, "", conf)
+			hc.Handler().PutBucketLifecycleHandler(w, r)
+			require.NotEqual(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestObjectExpirationHeader(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-expiration-header"
+	createTestBucket(hc, bktName)
+
+	conf := &data.LifecycleConfiguration{
+		Rules: []data.LifecycleRule{{
+			ID:         "expire-logs",
+			Status:     data.LifecycleStatusEnabled,
+			Filter:     &data.LifecycleFilter{Prefix: "logs/"},
+			Expiration: &data.LifecycleExpiration{Days: 30},
+		}, {
+			ID:         "expire-temp",
+			Status:     data.LifecycleStatusEnabled,
+			Filter:     &data.LifecycleFilter{Tag: &data.LifecycleTag{Key: "temp", Value: "true"}},
+			Expiration: &data.LifecycleExpiration{Days: 1},
+		}, {
+			ID:         "disabled",
+			Status:     data.LifecycleStatusDisabled,
+			Expiration: &data.LifecycleExpiration{Days: 1},
+		}},
+	}
+	w, r := prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestPayloadRequest(hc, bktName, "logs/obj", bytes.NewReader([]byte("content")))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	expected := `expiry-date="` + time.Now().UTC().Add(31*24*time.Hour).Truncate(24*time.Hour).Format(http.TimeFormat) + `", rule-id="expire-logs"`
+	require.Equal(t, expected, w.Header().Get(api.AmzExpiration))
+
+	w, r = prepareTestRequest(hc, bktName, "logs/obj", nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, expected, w.Header().Get(api.AmzExpiration))
+
+	w, r = prepareTestRequest(hc, bktName, "logs/obj", nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, expected, w.Header().Get(api.AmzExpiration))
+
+	// the earliest rule applies
+	w, r = prepareTestPayloadRequest(hc, bktName, "logs/temp", bytes.NewReader([]byte("content")))
+	r.Header.Set(api.AmzTagging, "temp=true")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Contains(t, w.Header().Get(api.AmzExpiration), `rule-id="expire-temp"`)
+
+	w, r = prepareTestRequest(hc, bktName, "logs/temp", nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Contains(t, w.Header().Get(api.AmzExpiration), `rule-id="expire-temp"`)
+
+	w, r = prepareTestPayloadRequest(hc, bktName, "data/obj", bytes.NewReader([]byte("content")))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header().Get(api.AmzExpiration))
+
+	w, r = prepareTestRequest(hc, bktName, "logs/copy", nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/data/obj")
+	hc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, expected, w.Header().Get(api.AmzExpiration))
+}
+
+func TestObjectExpirationHeaderLifecycleFailure(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	lifecycle := layer.NewTestLifecycleService()
+	hc.h.lifecycle = lifecycle
+
+	bktName, objName := "bucket-for-expiration-header-failure", "logs/obj"
+	createTestBucket(hc, bktName)
+
+	conf := &data.LifecycleConfiguration{
+		Rules: []data.LifecycleRule{{
+			ID:         "expire-logs",
+			Status:     data.LifecycleStatusEnabled,
+			Filter:     &data.LifecycleFilter{Prefix: "logs/"},
+			Expiration: &data.LifecycleExpiration{Days: 30},
+		}},
+	}
+	w, r := prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	putObject(t, hc, bktName, objName)
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Contains(t, w.Header().Get(api.AmzExpiration), `rule-id="expire-logs"`)
+
+	// the header is informational, so the object is returned without it
+	lifecycle.Err = errorsStd.New("lifecycle is unavailable")
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header().Get(api.AmzExpiration))
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusInternalServerError)
+}
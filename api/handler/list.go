@@ -2,13 +2,19 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
 )
 
-const maxObjectList = 1000 // Limit number of objects in a listObjectsResponse/listObjectsVersionsResponse.
+const (
+	maxObjectList = 1000  // Limit number of objects in a listObjectsResponse/listObjectsVersionsResponse.
+	maxBucketList = 10000 // Limit of max-buckets of ListBuckets.
+)
 
 // ListBucketsHandler handles bucket listing requests.
 func (h *handler) ListBucketsHandler(w http.ResponseWriter, r *http.Request) {
@@ -18,14 +24,20 @@ func (h *handler) ListBucketsHandler(w http.ResponseWriter, r *http.Request) {
 		reqInfo = api.GetReqInfo(r.Context())
 	)
 
-	list, err := h.obj.ListBuckets(r.Context())
+	params, err := parseListBucketsArgs(reqInfo)
+	if err != nil {
+		h.logAndSendError(w, "failed to parse arguments", reqInfo, err)
+		return
+	}
+
+	list, err := h.obj.ListBuckets(r.Context(), params)
 	if err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 		return
 	}
 
-	if len(list) > 0 {
-		own = list[0].Owner
+	if len(list.Buckets) > 0 {
+		own = list.Buckets[0].Owner
 	}
 
 	res = &ListBucketsResponse{
@@ -33,16 +45,42 @@ func (h *handler) ListBucketsHandler(w http.ResponseWriter, r *http.Request) {
 			ID:          own.String(),
 			DisplayName: own.String(),
 		},
+		ContinuationToken: list.ContinuationToken,
+		Prefix:            params.Prefix,
 	}
 
-	for _, item := range list {
+	for _, item := range list.Buckets {
 		res.Buckets.Buckets = append(res.Buckets.Buckets, Bucket{
 			Name:         item.Name,
 			CreationDate: item.Created.UTC().Format(time.RFC3339),
+			BucketRegion: item.LocationConstraint,
 		})
 	}
 
-	if err = api.EncodeToResponse(w, res); err != nil {
+	if err = api.EncodeListingToResponse(w, reqInfo, res); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
+
+func parseListBucketsArgs(reqInfo *api.ReqInfo) (*layer.ListBucketsParams, error) {
+	var (
+		err         error
+		res         layer.ListBucketsParams
+		queryValues = reqInfo.URL.Query()
+	)
+
+	if queryValues.Get("max-buckets") != "" {
+		res.MaxBuckets, err = strconv.Atoi(queryValues.Get("max-buckets"))
+		if err != nil || res.MaxBuckets < 1 || res.MaxBuckets > maxBucketList {
+			return nil, errors.GetAPIError(errors.ErrInvalidMaxBuckets)
+		}
+	}
+
+	if res.ContinuationToken, err = parseContinuationToken(queryValues); err != nil {
+		return nil, err
+	}
+
+	res.Prefix = queryValues.Get("prefix")
+
+	return &res, nil
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -41,7 +40,7 @@ func (h *handler) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *htt
 	}
 
 	lockingConf := &data.ObjectLockConfiguration{}
-	if err = xml.NewDecoder(r.Body).Decode(lockingConf); err != nil {
+	if err = api.NewXMLDecoder(r.Body).Decode(lockingConf); err != nil {
 		h.logAndSendError(w, "couldn't parse locking configuration", reqInfo, err)
 		return
 	}
@@ -121,7 +120,7 @@ func (h *handler) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	legalHold := &data.LegalHold{}
-	if err = xml.NewDecoder(r.Body).Decode(legalHold); err != nil {
+	if err = api.NewXMLDecoder(r.Body).Decode(legalHold); err != nil {
 		h.logAndSendError(w, "couldn't parse legal hold configuration", reqInfo, err)
 		return
 	}
@@ -204,7 +203,7 @@ func (h *handler) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	retention := &data.Retention{}
-	if err = xml.NewDecoder(r.Body).Decode(retention); err != nil {
+	if err = api.NewXMLDecoder(r.Body).Decode(retention); err != nil {
 		h.logAndSendError(w, "couldn't parse object retention", reqInfo, err)
 		return
 	}
@@ -215,6 +214,13 @@ func (h *handler) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if lock.Retention.ByPassedGovernance {
+		if err = h.checkBypassGovernance(r, bktInfo, reqInfo.ObjectName); err != nil {
+			h.logAndSendError(w, "couldn't bypass governance retention", reqInfo, err)
+			return
+		}
+	}
+
 	p := &layer.PutLockInfoParams{
 		ObjVersion: &layer.ObjectVersion{
 			BktInfo:    bktInfo,
@@ -379,6 +385,26 @@ func formObjectLock(ctx context.Context, bktInfo *data.BucketInfo, defaultConfig
 	return objectLock, nil
 }
 
+// writeObjectLockHeaders writes the effective object lock of the new object, so clients can check
+// that the lock took effect without HeadObject.
+func writeObjectLockHeaders(h http.Header, lock *data.ObjectLock) {
+	if lock == nil {
+		return
+	}
+
+	if lock.Retention != nil {
+		mode := governanceMode
+		if lock.Retention.IsCompliance {
+			mode = complianceMode
+		}
+		h.Set(api.AmzObjectLockMode, mode)
+		h.Set(api.AmzObjectLockRetainUntilDate, lock.Retention.Until.UTC().Format(time.RFC3339))
+	}
+	if lock.LegalHold != nil && lock.LegalHold.Enabled {
+		h.Set(api.AmzObjectLockLegalHold, legalHoldOn)
+	}
+}
+
 func existLockHeaders(header http.Header) bool {
 	return header.Get(api.AmzObjectLockMode) != "" ||
 		header.Get(api.AmzObjectLockLegalHold) != "" ||
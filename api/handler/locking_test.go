@@ -3,16 +3,22 @@ package handler
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
 	"github.com/stretchr/testify/require"
 )
 
@@ -478,13 +484,13 @@ func TestObjectRetention(t *testing.T) {
 	getObjectRetention(hc, bktName, objName, retention, 0)
 
 	retention = &data.Retention{Mode: governanceMode, RetainUntilDate: time.Now().UTC().Add(time.Minute).Format(time.RFC3339)}
-	putObjectRetention(hc, bktName, objName, retention, false, apiErrors.ErrInternalError)
+	putObjectRetention(hc, bktName, objName, retention, false, apiErrors.ErrAccessDenied)
 
 	retention = &data.Retention{Mode: complianceMode, RetainUntilDate: time.Now().Add(time.Minute).UTC().Format(time.RFC3339)}
 	putObjectRetention(hc, bktName, objName, retention, true, 0)
 	getObjectRetention(hc, bktName, objName, retention, 0)
 
-	putObjectRetention(hc, bktName, objName, retention, true, apiErrors.ErrInternalError)
+	putObjectRetention(hc, bktName, objName, retention, true, apiErrors.ErrAccessDenied)
 }
 
 func getObjectRetention(hc *handlerContext, bktName, objName string, retention *data.Retention, errCode apiErrors.ErrorCode) {
@@ -548,9 +554,40 @@ func TestPutObjectWithLock(t *testing.T) {
 	r.Header.Set(api.AmzObjectLockRetainUntilDate, time.Now().Add(2*24*time.Hour).Format(time.RFC3339))
 	hc.Handler().PutObjectHandler(w, r)
 	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, complianceMode, w.Header().Get(api.AmzObjectLockMode))
+	require.Equal(t, r.Header.Get(api.AmzObjectLockRetainUntilDate), w.Header().Get(api.AmzObjectLockRetainUntilDate))
+	require.Equal(t, legalHoldOn, w.Header().Get(api.AmzObjectLockLegalHold))
 
 	getObjectRetentionApproximate(hc, bktName, objOverride, complianceMode, time.Now().Add(2*24*time.Hour))
 	getObjectLegalHold(hc, bktName, objOverride, legalHoldOn)
+
+	objMultipart := "obj-multipart-retention"
+	multipartUpload := createMultipartUpload(hc, bktName, objMultipart, map[string]string{api.AmzObjectLockLegalHold: legalHoldOn})
+	etag, _ := uploadPart(hc, bktName, objMultipart, multipartUpload.UploadID, 1, 10)
+	w = completeMultipartUploadRequest(hc, bktName, objMultipart, multipartUpload.UploadID, etag, "")
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, governanceMode, w.Header().Get(api.AmzObjectLockMode))
+	require.NotEmpty(t, w.Header().Get(api.AmzObjectLockRetainUntilDate))
+	require.Equal(t, legalHoldOn, w.Header().Get(api.AmzObjectLockLegalHold))
+
+	getObjectRetentionApproximate(hc, bktName, objMultipart, governanceMode, time.Now().Add(24*time.Hour))
+	getObjectLegalHold(hc, bktName, objMultipart, legalHoldOn)
+
+	objMultipartOverride := "obj-multipart-override-retention"
+	until := time.Now().Add(3 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	multipartUpload = createMultipartUpload(hc, bktName, objMultipartOverride, map[string]string{
+		api.AmzObjectLockMode:            complianceMode,
+		api.AmzObjectLockRetainUntilDate: until,
+	})
+	etag, _ = uploadPart(hc, bktName, objMultipartOverride, multipartUpload.UploadID, 1, 10)
+	w = completeMultipartUploadRequest(hc, bktName, objMultipartOverride, multipartUpload.UploadID, etag, "")
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, complianceMode, w.Header().Get(api.AmzObjectLockMode))
+	require.Equal(t, until, w.Header().Get(api.AmzObjectLockRetainUntilDate))
+	require.Empty(t, w.Header().Get(api.AmzObjectLockLegalHold))
+
+	getObjectRetentionApproximate(hc, bktName, objMultipartOverride, complianceMode, time.Now().Add(3*24*time.Hour))
+	getObjectLegalHold(hc, bktName, objMultipartOverride, legalHoldOff)
 }
 
 func getObjectRetentionApproximate(hc *handlerContext, bktName, objName, mode string, untilDate time.Time) {
@@ -586,6 +623,18 @@ func TestPutLockErrors(t *testing.T) {
 	headers[api.AmzObjectLockRetainUntilDate] = "dummy"
 	putObjectWithLockFailed(t, hc, bktName, objName, headers, apiErrors.ErrInvalidRetentionDate)
 
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	setHeaders(r, headers)
+	hc.Handler().CreateMultipartUploadHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrInvalidRetentionDate))
+
+	bktNoLock := "bucket-lock-disabled"
+	createTestBucket(hc, bktNoLock)
+	w, r = prepareTestRequest(hc, bktNoLock, objName, nil)
+	r.Header.Set(api.AmzObjectLockLegalHold, legalHoldOn)
+	hc.Handler().CreateMultipartUploadHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrObjectLockConfigurationNotFound))
+
 	putObject(t, hc, bktName, objName)
 
 	retention := &data.Retention{Mode: governanceMode}
@@ -632,3 +681,145 @@ func assertRetentionApproximate(t *testing.T, w *httptest.ResponseRecorder, rete
 
 	require.InDelta(t, expectedUntil.Unix(), actualUntil.Unix(), delta)
 }
+
+func TestDeleteObjectWithRetention(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-lock-enabled"
+	bktInfo := createTestBucketWithLock(hc, bktName, nil)
+
+	governed := createTestObject(hc, bktInfo, "governed")
+	retention := &data.Retention{Mode: governanceMode, RetainUntilDate: time.Now().Add(time.Minute).UTC().Format(time.RFC3339)}
+	putObjectRetention(hc, bktName, governed.Name, retention, false, 0)
+
+	deleteObjectVersion(hc, bktName, governed.Name, governed.VersionID(), false, http.StatusForbidden)
+	checkFound(t, hc, bktName, governed.Name, governed.VersionID())
+
+	// the bucket policy takes precedence over the ownership of the bucket
+	bktPolicy, err := policy.Parse(strings.NewReader(`{"Statement":[{"Effect":"Deny","Principal":"*",` +
+		`"Action":"s3:BypassGovernanceRetention","Resource":"arn:aws:s3:::` + bktName + `/*"}]}`))
+	require.NoError(t, err)
+	bktInfo, err = hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+	err = hc.Layer().PutBucketPolicy(hc.Context(), &layer.PutBucketPolicyParams{BktInfo: bktInfo, Policy: bktPolicy})
+	require.NoError(t, err)
+
+	deleteObjectVersion(hc, bktName, governed.Name, governed.VersionID(), true, http.StatusForbidden)
+	checkFound(t, hc, bktName, governed.Name, governed.VersionID())
+
+	err = hc.Layer().DeleteBucketPolicy(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	deleteObjectVersion(hc, bktName, governed.Name, governed.VersionID(), true, http.StatusNoContent)
+	checkNotFound(t, hc, bktName, governed.Name, governed.VersionID())
+
+	complied := createTestObject(hc, bktInfo, "complied")
+	retention = &data.Retention{Mode: complianceMode, RetainUntilDate: time.Now().Add(time.Minute).UTC().Format(time.RFC3339)}
+	putObjectRetention(hc, bktName, complied.Name, retention, false, 0)
+
+	deleteObjectVersion(hc, bktName, complied.Name, complied.VersionID(), true, http.StatusForbidden)
+	checkFound(t, hc, bktName, complied.Name, complied.VersionID())
+}
+
+func TestDeleteObjectsWithRetention(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-lock-enabled"
+	bktInfo := createTestBucketWithLock(hc, bktName, nil)
+
+	retention := &data.Retention{Mode: governanceMode, RetainUntilDate: time.Now().Add(time.Minute).UTC().Format(time.RFC3339)}
+	denied := createTestObject(hc, bktInfo, "denied/obj")
+	putObjectRetention(hc, bktName, denied.Name, retention, false, 0)
+	allowed := createTestObject(hc, bktInfo, "allowed/obj")
+	putObjectRetention(hc, bktName, allowed.Name, retention, false, 0)
+
+	bktPolicy, err := policy.Parse(strings.NewReader(`{"Statement":[{"Effect":"Deny","Principal":"*",` +
+		`"Action":"s3:BypassGovernanceRetention","Resource":"arn:aws:s3:::` + bktName + `/denied/*"}]}`))
+	require.NoError(t, err)
+	bktInfo, err = hc.Layer().GetBucketInfo(hc.Context(), bktName)
+	require.NoError(t, err)
+	err = hc.Layer().PutBucketPolicy(hc.Context(), &layer.PutBucketPolicyParams{BktInfo: bktInfo, Policy: bktPolicy})
+	require.NoError(t, err)
+
+	// the bypass is checked per object, so only the denied object isn't deleted
+	body, err := xml.Marshal(&DeleteObjectsRequest{Objects: []ObjectIdentifier{
+		{ObjectName: denied.Name, VersionID: denied.VersionID()},
+		{ObjectName: allowed.Name, VersionID: allowed.VersionID()},
+	}})
+	require.NoError(t, err)
+	sum := md5.Sum(body)
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, "", nil, body)
+	r.Header.Set(api.ContentMD5, base64.StdEncoding.EncodeToString(sum[:]))
+	r.Header.Set(api.AmzBypassGovernanceRetention, strconv.FormatBool(true))
+	hc.Handler().DeleteMultipleObjectsHandler(w, r)
+
+	resp := &DeleteObjectsResponse{}
+	readResponse(t, w, http.StatusOK, resp)
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, denied.Name, resp.Errors[0].Key)
+	require.Equal(t, "AccessDenied", resp.Errors[0].Code)
+	require.Len(t, resp.DeletedObjects, 1)
+	require.Equal(t, allowed.Name, resp.DeletedObjects[0].ObjectName)
+
+	checkFound(t, hc, bktName, denied.Name, denied.VersionID())
+	checkNotFound(t, hc, bktName, allowed.Name, allowed.VersionID())
+}
+
+func deleteObjectVersion(hc *handlerContext, bktName, objName, version string, byPass bool, status int) {
+	query := url.Values{api.QueryVersionID: []string{version}}
+	w, r := prepareTestFullRequest(hc, bktName, objName, query, nil)
+	if byPass {
+		r.Header.Set(api.AmzBypassGovernanceRetention, strconv.FormatBool(true))
+	}
+	hc.Handler().DeleteObjectHandler(w, r)
+	assertStatus(hc.t, w, status)
+}
+
+func TestDeleteObjectWithLegalHold(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-lock-enabled"
+	bktInfo := createTestBucketWithLock(hc, bktName, nil)
+
+	objInfo := createTestObject(hc, bktInfo, "held")
+	putObjectLegalHold(hc, bktName, objInfo.Name, legalHoldOn)
+
+	// legal hold can't be bypassed
+	deleteObjectVersion(hc, bktName, objInfo.Name, objInfo.VersionID(), true, http.StatusForbidden)
+	checkFound(t, hc, bktName, objInfo.Name, objInfo.VersionID())
+
+	resp := deleteObjects(hc, bktName, []ObjectIdentifier{{ObjectName: objInfo.Name, VersionID: objInfo.VersionID()}}, false)
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, "AccessDenied", resp.Errors[0].Code)
+	checkFound(t, hc, bktName, objInfo.Name, objInfo.VersionID())
+
+	putObjectLegalHold(hc, bktName, objInfo.Name, legalHoldOff)
+	deleteObjectVersion(hc, bktName, objInfo.Name, objInfo.VersionID(), false, http.StatusNoContent)
+	checkNotFound(t, hc, bktName, objInfo.Name, objInfo.VersionID())
+}
+
+func TestOverwriteUnversionedObjectWithLock(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-lock-enabled", "object"
+	bktInfo := createTestBucketWithLock(hc, bktName, nil)
+
+	// versioning of lock enabled buckets can't be suspended by requests, so it's reset in the layer
+	err := hc.Layer().PutBucketSettings(hc.Context(), &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningUnversioned},
+	})
+	require.NoError(t, err)
+
+	createTestObject(hc, bktInfo, objName)
+	putObjectLegalHold(hc, bktName, objName, legalHoldOn)
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader("content"))
+	hc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrObjectLocked))
+
+	putObjectLegalHold(hc, bktName, objName, legalHoldOff)
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader("content"))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+}
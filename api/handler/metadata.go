@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+// UpdateObjectMetadataHandler replaces user metadata and content type of the object
+// without re-storing of its payload.
+func (h *handler) UpdateObjectMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.checkFeature(r.Context(), bktInfo, features.MetadataUpdate); err != nil {
+		h.logAndSendError(w, "feature is disabled", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
+	metadata := h.parseMetadata(r)
+	if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
+		metadata[api.ContentType] = contentType
+	}
+	if cacheControl := r.Header.Get(api.CacheControl); len(cacheControl) > 0 {
+		metadata[api.CacheControl] = cacheControl
+	}
+	if expires := r.Header.Get(api.Expires); len(expires) > 0 {
+		metadata[api.Expires] = expires
+	}
+
+	p := &layer.UpdateObjectMetadataParams{
+		BktInfo:  bktInfo,
+		Settings: settings,
+		Object:   reqInfo.ObjectName,
+		Header:   metadata,
+	}
+
+	extendedObjInfo, err := h.obj.UpdateObjectMetadata(r.Context(), p)
+	if err != nil {
+		h.logAndSendError(w, "couldn't update object metadata", reqInfo, err)
+		return
+	}
+	objInfo := extendedObjInfo.ObjectInfo
+
+	h.log.Info("object metadata is updated",
+		zap.String("bucket", bktInfo.Name),
+		zap.String("object", reqInfo.ObjectName),
+		zap.Stringer("object_id", objInfo.ID))
+
+	w.Header().Set(api.ETag, objInfo.HashSum)
+	api.WriteSuccessResponseHeadersOnly(w)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateObjectMetadata(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-metadata", "object"
+	createTestBucket(hc, bktName)
+	putObjectContent(hc, bktName, objName, "content")
+
+	updateObjectMetadata(t, hc, bktName, objName, map[string]string{
+		api.ContentType:            "text/plain",
+		api.MetadataPrefix + "Key": "value",
+	}, http.StatusOK)
+	require.Len(t, listOIDsFromMockedNeoFS(t, hc, bktName), 1)
+
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "text/plain", w.Header().Get(api.ContentType))
+	require.Equal(t, []string{"value"}, w.Header()[api.MetadataPrefix+"key"])
+	require.Equal(t, "content", getObjectContent(t, hc, bktName, objName))
+
+	// copying to itself with replaced metadata doesn't store the payload again
+	copyObject(t, hc, bktName, objName, objName, CopyMeta{
+		MetadataDirective: replaceDirective,
+		Metadata:          map[string]string{"Other": "other-value"},
+	}, http.StatusOK)
+	require.Len(t, listOIDsFromMockedNeoFS(t, hc, bktName), 1)
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header()[api.MetadataPrefix+"key"])
+	require.Equal(t, []string{"other-value"}, w.Header()[api.MetadataPrefix+"other"])
+
+	// new object version resets updated metadata
+	putObjectContent(hc, bktName, objName, "new content")
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header()[api.MetadataPrefix+"other"])
+
+	updateObjectMetadata(t, hc, bktName, "missing", nil, http.StatusNotFound)
+
+	versionedBktName := "versioned-bucket-for-metadata"
+	createVersionedBucketAndObject(t, hc, versionedBktName, objName)
+	updateObjectMetadata(t, hc, versionedBktName, objName, nil, http.StatusNotImplemented)
+}
+
+func updateObjectMetadata(t *testing.T, hc *handlerContext, bktName, objName string, headers map[string]string, status int) {
+	w, r := prepareTestRequestWithQuery(hc, bktName, objName, url.Values{"metadata": []string{""}}, nil)
+	for key, val := range headers {
+		r.Header.Set(key, val)
+	}
+	hc.Handler().UpdateObjectMetadataHandler(w, r)
+	assertStatus(t, w, status)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+// maxMetricsConfigurationsList is the number of metrics configurations returned by a list request as AWS S3 does.
+const maxMetricsConfigurationsList = 100
+
+// ListMetricsConfigurationsResult is a response of ListBucketMetricsConfigurations request.
+type ListMetricsConfigurationsResult struct {
+	XMLName                  struct{}                    `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListMetricsConfigurationsResult" json:"-"`
+	MetricsConfigurationList []data.MetricsConfiguration `xml:"MetricsConfiguration"`
+	IsTruncated              bool                        `xml:"IsTruncated"`
+	ContinuationToken        string                      `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken    string                      `xml:"NextContinuationToken,omitempty"`
+}
+
+func (h *handler) GetBucketMetricsConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketMetricsConfiguration(r.Context(), bktInfo, r.URL.Query().Get("id"))
+	if err != nil {
+		h.logAndSendError(w, "could not get metrics configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode metrics configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) ListBucketMetricsConfigurationsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	configurations, err := h.obj.ListBucketMetricsConfigurations(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not list metrics configurations", reqInfo, err)
+		return
+	}
+
+	// configurations are sorted by id, the continuation token is the id of the last returned configuration
+	token := r.URL.Query().Get("continuation-token")
+	start := sort.Search(len(configurations), func(i int) bool {
+		return configurations[i].ID > token
+	})
+	configurations = configurations[start:]
+
+	resp := &ListMetricsConfigurationsResult{
+		MetricsConfigurationList: configurations,
+		ContinuationToken:        token,
+	}
+	if len(configurations) > maxMetricsConfigurationsList {
+		resp.MetricsConfigurationList = configurations[:maxMetricsConfigurationsList]
+		resp.IsTruncated = true
+		resp.NextContinuationToken = resp.MetricsConfigurationList[maxMetricsConfigurationsList-1].ID
+	}
+
+	if err = api.EncodeToResponse(w, resp); err != nil {
+		h.logAndSendError(w, "could not encode metrics configurations to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketMetricsConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := new(data.MetricsConfiguration)
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "could not parse metrics configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if id := r.URL.Query().Get("id"); id != conf.ID {
+		h.logAndSendError(w, "invalid metrics id", reqInfo, errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+			fmt.Errorf("id '%s' doesn't match configuration id '%s'", id, conf.ID)))
+		return
+	}
+
+	p := &layer.PutBucketMetricsParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+		CopiesNumber:  h.cfg.CopiesNumber,
+	}
+
+	if err = h.obj.PutBucketMetricsConfiguration(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not put metrics configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketMetricsConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketMetricsConfiguration(r.Context(), bktInfo, r.URL.Query().Get("id")); err != nil {
+		h.logAndSendError(w, "could not delete metrics configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MatchMetricsConfigurations returns ids of the bucket metrics configurations which count the request.
+// Requests to objects are matched by the key prefix of the configuration filter, bucket requests are
+// counted by configurations without the prefix only.
+func (h *handler) MatchMetricsConfigurations(r *http.Request) []string {
+	reqInfo := api.GetReqInfo(r.Context())
+	if reqInfo.BucketName == "" {
+		return nil
+	}
+	switch reqInfo.API {
+	case "Options", "CreateBucket", "ListBuckets":
+		return nil
+	}
+
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
+	if err != nil {
+		// the handler reports the error itself
+		return nil
+	}
+
+	configurations, err := h.obj.ListBucketMetricsConfigurations(r.Context(), bktInfo)
+	if err != nil {
+		h.log.Warn("couldn't get bucket metrics configurations", zap.String("bucket", bktInfo.Name), zap.Error(err))
+		return nil
+	}
+
+	var ids []string
+	for i := range configurations {
+		prefix := configurations[i].FilterPrefix()
+		if prefix == "" || reqInfo.ObjectName != "" && strings.HasPrefix(reqInfo.ObjectName, prefix) {
+			ids = append(ids, configurations[i].ID)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketMetricsConfiguration(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-metrics"
+	createTestBucket(hc, bktName)
+
+	query := url.Values{"metrics": []string{""}, "id": []string{"logs"}}
+	w, r := prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketMetricsConfigurationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchConfiguration))
+
+	conf := &data.MetricsConfiguration{ID: "logs", Filter: &data.MetricsFilter{Prefix: "logs/"}}
+	w, r = prepareTestFullRequest(hc, bktName, "", query, conf)
+	hc.Handler().PutBucketMetricsConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	entire := &data.MetricsConfiguration{ID: "EntireBucket"}
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"metrics": []string{""}, "id": []string{entire.ID}}, entire)
+	hc.Handler().PutBucketMetricsConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	tagged := &data.MetricsConfiguration{ID: "tagged", Filter: &data.MetricsFilter{Tag: &data.LifecycleTag{Key: "k", Value: "v"}}}
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"metrics": []string{""}, "id": []string{tagged.ID}}, tagged)
+	hc.Handler().PutBucketMetricsConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusNotImplemented)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketMetricsConfigurationHandler(w, r)
+	actual := &data.MetricsConfiguration{}
+	parseTestResponse(t, w, actual)
+	require.Equal(t, conf.ID, actual.ID)
+	require.Equal(t, conf.Filter, actual.Filter)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"metrics": []string{""}}, nil)
+	hc.Handler().ListBucketMetricsConfigurationsHandler(w, r)
+	list := &ListMetricsConfigurationsResult{}
+	parseTestResponse(t, w, list)
+	require.Len(t, list.MetricsConfigurationList, 2)
+	require.False(t, list.IsTruncated)
+
+	_, r = prepareTestFullRequest(hc, bktName, "logs/today", nil, nil)
+	require.Equal(t, []string{"EntireBucket", "logs"}, hc.Handler().MatchMetricsConfigurations(r))
+	_, r = prepareTestFullRequest(hc, bktName, "data/today", nil, nil)
+	require.Equal(t, []string{"EntireBucket"}, hc.Handler().MatchMetricsConfigurations(r))
+	_, r = prepareTestFullRequest(hc, bktName, "", nil, nil)
+	require.Equal(t, []string{"EntireBucket"}, hc.Handler().MatchMetricsConfigurations(r))
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().DeleteBucketMetricsConfigurationHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().GetBucketMetricsConfigurationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchConfiguration))
+}
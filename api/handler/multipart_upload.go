@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"encoding/base64"
 	"encoding/xml"
+	stderrors "errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +34,13 @@ type (
 		Bucket  string   `xml:"Bucket"`
 		Key     string   `xml:"Key"`
 		ETag    string   `xml:"ETag"`
+
+		// Version and object lock are returned in the body only if the response was started
+		// to keep the connection alive, so headers with them couldn't be sent.
+		VersionID                 string `xml:"VersionId,omitempty"`
+		ObjectLockMode            string `xml:"ObjectLockMode,omitempty"`
+		ObjectLockRetainUntilDate string `xml:"ObjectLockRetainUntilDate,omitempty"`
+		ObjectLockLegalHoldStatus string `xml:"ObjectLockLegalHoldStatus,omitempty"`
 	}
 
 	ListMultipartUploadsResponse struct {
@@ -60,6 +72,7 @@ type (
 		PartNumberMarker     int           `xml:"PartNumberMarker,omitempty"`
 		StorageClass         string        `xml:"StorageClass,omitempty"`
 		UploadID             string        `xml:"UploadId"`
+		ChecksumAlgorithm    string        `xml:"ChecksumAlgorithm,omitempty"`
 	}
 
 	MultipartUpload struct {
@@ -116,19 +129,40 @@ func (h *handler) CreateMultipartUploadHandler(w http.ResponseWriter, r *http.Re
 		Data: &layer.UploadData{},
 	}
 
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
+	if err = checkACLHeadersAllowed(settings, r); err != nil {
+		h.logAndSendError(w, "acl is disabled in the bucket", reqInfo, err)
+		return
+	}
+
 	if containsACLHeaders(r) {
 		key, err := h.bearerTokenIssuerKey(r.Context())
 		if err != nil {
 			h.logAndSendError(w, "couldn't get gate key", reqInfo, err)
 			return
 		}
-		if _, err = parseACLHeaders(r.Header, key); err != nil {
+		acl, err := parseACLHeaders(r.Header, key)
+		if err != nil {
 			h.logAndSendError(w, "could not parse acl", reqInfo, err)
 			return
 		}
+		if err = h.checkPublicACL(r.Context(), bktInfo, acl); err != nil {
+			h.logAndSendError(w, "public object acl is blocked", reqInfo, err)
+			return
+		}
 		p.Data.ACLHeaders = formACLHeadersForMultipart(r.Header)
 	}
 
+	if p.Data.Lock, err = formObjectLock(r.Context(), bktInfo, settings.LockConfiguration, r.Header); err != nil {
+		h.logAndSendError(w, "could not form object lock", reqInfo, err, additional...)
+		return
+	}
+
 	if len(r.Header.Get(api.AmzTagging)) > 0 {
 		p.Data.TagSet, err = parseTaggingHeader(r.Header)
 		if err != nil {
@@ -143,14 +177,25 @@ func (h *handler) CreateMultipartUploadHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	p.Header = parseMetadata(r)
+	if p.ChecksumAlgorithm = strings.ToUpper(r.Header.Get(api.AmzChecksumAlgorithm)); p.ChecksumAlgorithm != "" &&
+		!layer.IsChecksumAlgorithm(p.ChecksumAlgorithm) {
+		h.logAndSendError(w, "invalid checksum algorithm", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+
+	p.Header = h.parseMetadata(r)
 	if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
 		p.Header[api.ContentType] = contentType
 	}
 
-	p.CopiesNumber, err = getCopiesNumberOrDefault(p.Header, h.cfg.CopiesNumber)
+	if err = checkUploadValidation(settings, reqInfo.ObjectName, p.Header); err != nil {
+		h.logAndSendError(w, "object violates upload validation rules", reqInfo, err)
+		return
+	}
+
+	p.CopiesNumber, err = h.formStorageClass(r.Header, p.Header)
 	if err != nil {
-		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		h.logAndSendError(w, "invalid storage class", reqInfo, err)
 		return
 	}
 
@@ -159,8 +204,9 @@ func (h *handler) CreateMultipartUploadHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if p.Info.Encryption.Enabled() {
-		addSSECHeaders(w.Header(), r.Header)
+	addEncryptionHeaders(w.Header(), r.Header, p.Info.Encryption)
+	if p.ChecksumAlgorithm != "" {
+		w.Header().Set(api.AmzChecksumAlgorithm, p.ChecksumAlgorithm)
 	}
 
 	resp := InitiateMultipartUploadResponse{
@@ -175,6 +221,99 @@ func (h *handler) CreateMultipartUploadHandler(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// formChecksum returns the checksum algorithm and the expected checksum of the payload
+// from x-amz-checksum-* headers. Only the algorithm is returned if the client asks to
+// calculate the checksum with x-amz-sdk-checksum-algorithm header.
+func formChecksum(header http.Header) (string, string, error) {
+	var algorithm, checksum string
+	for _, alg := range layer.ChecksumAlgorithms {
+		value := header.Get(api.AmzChecksumPrefix + alg)
+		if value == "" {
+			continue
+		}
+		if algorithm != "" {
+			return "", "", errors.GetAPIErrorWithError(errors.ErrInvalidRequest, stderrors.New("multiple checksum headers"))
+		}
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return "", "", errors.GetAPIError(errors.ErrInvalidDigest)
+		}
+		algorithm, checksum = alg, value
+	}
+
+	sdkAlgorithm := strings.ToUpper(header.Get(api.AmzSdkChecksumAlgorithm))
+	if sdkAlgorithm != "" && !layer.IsChecksumAlgorithm(sdkAlgorithm) {
+		return "", "", errors.GetAPIError(errors.ErrInvalidArgument)
+	}
+	if algorithm == "" {
+		algorithm = sdkAlgorithm
+	} else if sdkAlgorithm != "" && sdkAlgorithm != algorithm {
+		return "", "", errors.GetAPIErrorWithError(errors.ErrInvalidRequest, stderrors.New("checksum header doesn't match the checksum algorithm"))
+	}
+
+	return algorithm, checksum, nil
+}
+
+// requestPayload is a payload of PutObject and UploadPart requests with its checksum parameters.
+type requestPayload struct {
+	reader            io.Reader
+	size              int64
+	checksumAlgorithm string
+	checksum          string
+	// trailingChecksum is set if the checksum is sent in the trailer of the aws-chunked payload.
+	trailingChecksum func() string
+}
+
+// formPayload returns the request payload decoded from aws-chunked encoding if the client streams it.
+// The checksum listed in X-Amz-Trailer header is read after the payload.
+func formPayload(r *http.Request) (*requestPayload, error) {
+	var (
+		p = &requestPayload{
+			reader: r.Body,
+			size:   r.ContentLength,
+		}
+		err error
+	)
+
+	if p.checksumAlgorithm, p.checksum, err = formChecksum(r.Header); err != nil {
+		return nil, err
+	}
+
+	if !api.IsStreamingPayload(r.Header) {
+		return p, nil
+	}
+
+	if p.size, err = strconv.ParseInt(r.Header.Get(api.AmzDecodedContentLength), 10, 64); err != nil || p.size < 0 {
+		return nil, errors.GetAPIError(errors.ErrMissingContentLength)
+	}
+	chunked := api.NewChunkedReader(r.Body)
+	p.reader = chunked
+
+	trailer := r.Header.Get(api.AmzTrailer)
+	if trailer == "" {
+		return p, nil
+	}
+
+	if !strings.HasPrefix(strings.ToLower(trailer), strings.ToLower(api.AmzChecksumPrefix)) {
+		return nil, errors.GetAPIErrorWithError(errors.ErrInvalidRequest, fmt.Errorf("unsupported trailer '%s'", trailer))
+	}
+	algorithm := strings.ToUpper(trailer[len(api.AmzChecksumPrefix):])
+	if !layer.IsChecksumAlgorithm(algorithm) {
+		return nil, errors.GetAPIError(errors.ErrInvalidArgument)
+	}
+	if p.checksum != "" || p.checksumAlgorithm != "" && p.checksumAlgorithm != algorithm {
+		return nil, errors.GetAPIErrorWithError(errors.ErrInvalidRequest, stderrors.New("checksum trailer doesn't match the checksum headers"))
+	}
+
+	p.checksumAlgorithm = algorithm
+	p.trailingChecksum = func() string {
+		// the payload may be read up to its size only, so the rest is read to get the trailer
+		_, _ = io.Copy(io.Discard, chunked)
+		return chunked.Trailer().Get(trailer)
+	}
+
+	return p, nil
+}
+
 func formACLHeadersForMultipart(header http.Header) map[string]string {
 	result := make(map[string]string)
 
@@ -190,6 +329,12 @@ func formACLHeadersForMultipart(header http.Header) map[string]string {
 	if value := header.Get(api.AmzGrantWrite); value != "" {
 		result[api.AmzGrantWrite] = value
 	}
+	if value := header.Get(api.AmzGrantReadACP); value != "" {
+		result[api.AmzGrantReadACP] = value
+	}
+	if value := header.Get(api.AmzGrantWriteACP); value != "" {
+		result[api.AmzGrantWriteACP] = value
+	}
 
 	return result
 }
@@ -222,8 +367,6 @@ func (h *handler) UploadPartHandler(w http.ResponseWriter, r *http.Request) {
 			Key:      reqInfo.ObjectName,
 		},
 		PartNumber: partNumber,
-		Size:       r.ContentLength,
-		Reader:     r.Body,
 	}
 
 	p.Info.Encryption, err = formEncryptionParams(r)
@@ -232,14 +375,23 @@ func (h *handler) UploadPartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	payload, err := formPayload(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid payload headers", reqInfo, err)
+		return
+	}
+	p.Reader, p.Size = payload.reader, payload.size
+	p.ChecksumAlgorithm, p.Checksum, p.TrailingChecksum = payload.checksumAlgorithm, payload.checksum, payload.trailingChecksum
+
 	hash, err := h.obj.UploadPart(r.Context(), p)
 	if err != nil {
 		h.logAndSendError(w, "could not upload a part", reqInfo, err, additional...)
 		return
 	}
 
-	if p.Info.Encryption.Enabled() {
-		addSSECHeaders(w.Header(), r.Header)
+	addEncryptionHeaders(w.Header(), r.Header, p.Info.Encryption)
+	if p.Checksum != "" {
+		w.Header().Set(api.AmzChecksumPrefix+p.ChecksumAlgorithm, p.Checksum)
 	}
 
 	w.Header().Set(api.ETag, hash)
@@ -337,7 +489,13 @@ func (h *handler) UploadPartCopy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = p.Info.Encryption.MatchObjectEncryption(layer.FormEncryptionInfo(srcInfo.Headers)); err != nil {
+	p.SrcEncryption, err = formCopySourceEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid copy source sse headers", reqInfo, err)
+		return
+	}
+
+	if err = p.SrcEncryption.MatchObjectEncryption(layer.FormEncryptionInfo(srcInfo.Headers)); err != nil {
 		h.logAndSendError(w, "encryption doesn't match object", reqInfo, errors.GetAPIError(errors.ErrBadRequest), zap.Error(err))
 		return
 	}
@@ -353,9 +511,7 @@ func (h *handler) UploadPartCopy(w http.ResponseWriter, r *http.Request) {
 		LastModified: info.Created.UTC().Format(time.RFC3339),
 	}
 
-	if p.Info.Encryption.Enabled() {
-		addSSECHeaders(w.Header(), r.Header)
-	}
+	addEncryptionHeaders(w.Header(), r.Header, p.Info.Encryption)
 
 	if err = api.EncodeToResponse(w, response); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
@@ -384,7 +540,7 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 	)
 
 	reqBody := new(CompleteMultipartUpload)
-	if err = xml.NewDecoder(r.Body).Decode(reqBody); err != nil {
+	if err = api.NewXMLDecoder(r.Body).Decode(reqBody); err != nil {
 		h.logAndSendError(w, "could not read complete multipart upload xml", reqInfo,
 			errors.GetAPIError(errors.ErrMalformedXML), additional...)
 		return
@@ -394,18 +550,33 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	if err = h.checkWritePreconditions(r, bktInfo, reqInfo.ObjectName); err != nil {
+		h.logAndSendError(w, "precondition failed", reqInfo, err, additional...)
+		return
+	}
+
 	c := &layer.CompleteMultipartParams{
 		Info:  uploadInfo,
 		Parts: reqBody.Parts,
 	}
 
+	// assembling of the object can take a long time, the response is kept alive until it's done
+	keepAlive := api.NewKeepAliveWriter(w, h.cfg.CompleteKeepAlive)
 	uploadData, extendedObjInfo, err := h.obj.CompleteMultipartUpload(r.Context(), c)
+	keepAlive.Stop()
+	w = keepAlive
 	if err != nil {
 		h.logAndSendError(w, "could not complete multipart upload", reqInfo, err, additional...)
 		return
 	}
 	objInfo := extendedObjInfo.ObjectInfo
 
+	bktSettings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
 	if len(uploadData.TagSet) != 0 {
 		tagPrm := &layer.PutObjectTaggingParams{
 			ObjectVersion: &layer.ObjectVersion{
@@ -443,7 +614,7 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 			h.logAndSendError(w, "could not translate acl of completed multipart upload to ast", reqInfo, err, additional...)
 			return
 		}
-		if _, err = h.updateBucketACL(r, astObject, bktInfo, sessionTokenSetEACL); err != nil {
+		if _, err = h.updateBucketACL(r, astObject, bktInfo, sessionTokenSetEACL, ""); err != nil {
 			h.logAndSendError(w, "could not update bucket acl while completing multipart upload", reqInfo, err, additional...)
 			return
 		}
@@ -459,11 +630,6 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 		h.log.Error("couldn't send notification: %w", zap.Error(err))
 	}
 
-	bktSettings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
-	if err != nil {
-		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
-	}
-
 	response := CompleteMultipartUploadResponse{
 		Bucket: objInfo.Bucket,
 		ETag:   objInfo.HashSum,
@@ -473,6 +639,15 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 	if bktSettings.VersioningEnabled() {
 		w.Header().Set(api.AmzVersionID, objInfo.VersionID())
 	}
+	writeObjectLockHeaders(w.Header(), uploadData.Lock)
+
+	if keepAlive.Started() {
+		// headers of the started response are already sent
+		response.VersionID = w.Header().Get(api.AmzVersionID)
+		response.ObjectLockMode = w.Header().Get(api.AmzObjectLockMode)
+		response.ObjectLockRetainUntilDate = w.Header().Get(api.AmzObjectLockRetainUntilDate)
+		response.ObjectLockLegalHoldStatus = w.Header().Get(api.AmzObjectLockLegalHold)
+	}
 
 	if err = api.EncodeToResponse(w, response); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
@@ -522,7 +697,7 @@ func (h *handler) ListMultipartUploadsHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if err = api.EncodeToResponse(w, encodeListMultipartUploadsToResponse(list, p)); err != nil {
+	if err = api.EncodeListingToResponse(w, reqInfo, encodeListMultipartUploadsToResponse(list, p)); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
@@ -557,8 +732,8 @@ func (h *handler) ListPartsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if queryValues.Get("part-number-marker") != "" {
-		if partNumberMarker, err = strconv.Atoi(queryValues.Get("part-number-marker")); err != nil || partNumberMarker <= 0 {
-			h.logAndSendError(w, "invalid PartNumberMarker", reqInfo, err, additional...)
+		if partNumberMarker, err = strconv.Atoi(queryValues.Get("part-number-marker")); err != nil || partNumberMarker < 0 {
+			h.logAndSendError(w, "invalid PartNumberMarker", reqInfo, errors.GetAPIError(errors.ErrInvalidPartNumberMarker), additional...)
 			return
 		}
 	}
@@ -585,7 +760,7 @@ func (h *handler) ListPartsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = api.EncodeToResponse(w, encodeListPartsToResponse(list, p)); err != nil {
+	if err = api.EncodeListingToResponse(w, reqInfo, encodeListPartsToResponse(list, p)); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
@@ -626,14 +801,14 @@ func encodeListMultipartUploadsToResponse(info *layer.ListMultipartUploadsInfo,
 	res := ListMultipartUploadsResponse{
 		Bucket:             params.Bkt.Name,
 		CommonPrefixes:     fillPrefixes(info.Prefixes, params.EncodingType),
-		Delimiter:          params.Delimiter,
+		Delimiter:          s3PathEncode(params.Delimiter, params.EncodingType),
 		EncodingType:       params.EncodingType,
 		IsTruncated:        info.IsTruncated,
-		KeyMarker:          params.KeyMarker,
+		KeyMarker:          s3PathEncode(params.KeyMarker, params.EncodingType),
 		MaxUploads:         params.MaxUploads,
-		NextKeyMarker:      info.NextKeyMarker,
+		NextKeyMarker:      s3PathEncode(info.NextKeyMarker, params.EncodingType),
 		NextUploadIDMarker: info.NextUploadIDMarker,
-		Prefix:             params.Prefix,
+		Prefix:             s3PathEncode(params.Prefix, params.EncodingType),
 		UploadIDMarker:     params.UploadIDMarker,
 	}
 
@@ -645,7 +820,7 @@ func encodeListMultipartUploadsToResponse(info *layer.ListMultipartUploadsInfo,
 				ID:          u.Owner.String(),
 				DisplayName: u.Owner.String(),
 			},
-			Key: u.Key,
+			Key: s3PathEncode(u.Key, params.EncodingType),
 			Owner: Owner{
 				ID:          u.Owner.String(),
 				DisplayName: u.Owner.String(),
@@ -676,8 +851,9 @@ func encodeListPartsToResponse(info *layer.ListPartsInfo, params *layer.ListPart
 			ID:          info.Owner.String(),
 			DisplayName: info.Owner.String(),
 		},
-		PartNumberMarker: params.PartNumberMarker,
-		UploadID:         params.Info.UploadID,
-		Parts:            info.Parts,
+		PartNumberMarker:  params.PartNumberMarker,
+		UploadID:          params.Info.UploadID,
+		Parts:             info.Parts,
+		ChecksumAlgorithm: info.ChecksumAlgorithm,
 	}
 }
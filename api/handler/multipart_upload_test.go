@@ -0,0 +1,392 @@
+package handler
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteMultipartUploadRetry(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-complete-retry", "object-for-complete-retry"
+	createTestBucket(hc, bktName)
+
+	multipartInfo := createMultipartUpload(hc, bktName, objName, nil)
+	etag, _ := uploadPart(hc, bktName, objName, multipartInfo.UploadID, 1, 10)
+
+	w := completeMultipartUploadRequest(hc, bktName, objName, multipartInfo.UploadID, etag, "")
+	result := &CompleteMultipartUploadResponse{}
+	readResponse(t, w, http.StatusOK, result)
+
+	// the same parts are already completed
+	w = completeMultipartUploadRequest(hc, bktName, objName, multipartInfo.UploadID, etag, "")
+	retryResult := &CompleteMultipartUploadResponse{}
+	readResponse(t, w, http.StatusOK, retryResult)
+	require.Equal(t, result.ETag, retryResult.ETag)
+
+	// the object completed by another upload isn't returned
+	w = completeMultipartUploadRequest(hc, bktName, objName, "5b2b6f3c-8a1e-4e3d-9a52-1f0c6e7d2a41", etag, "")
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchUpload))
+
+	// other parts can't be completed
+	w = completeMultipartUploadRequest(hc, bktName, objName, multipartInfo.UploadID, "\"invalid\"", "")
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchUpload))
+}
+
+func TestCompleteMultipartUploadIfNoneMatch(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-complete-if-none-match", "object-for-complete-if-none-match"
+	createTestBucket(hc, bktName)
+
+	multipartInfo := createMultipartUpload(hc, bktName, objName, nil)
+	etag, _ := uploadPart(hc, bktName, objName, multipartInfo.UploadID, 1, 10)
+	w := completeMultipartUploadRequest(hc, bktName, objName, multipartInfo.UploadID, etag, "*")
+	assertStatus(t, w, http.StatusOK)
+
+	multipartInfo = createMultipartUpload(hc, bktName, objName, nil)
+	etag, _ = uploadPart(hc, bktName, objName, multipartInfo.UploadID, 1, 10)
+	w = completeMultipartUploadRequest(hc, bktName, objName, multipartInfo.UploadID, etag, "*")
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrPreconditionFailed))
+}
+
+func TestCompleteMultipartUploadMD5ETag(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-md5-etag", "object-for-md5-etag"
+	bktInfo := createTestBucket(hc, bktName)
+	err := hc.Layer().PutBucketSettings(hc.Context(), &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &data.BucketSettings{ETagAlgorithm: data.ETagAlgorithmMD5},
+	})
+	require.NoError(t, err)
+
+	multipartInfo := createMultipartUpload(hc, bktName, objName, nil)
+	etag1, part1 := uploadPart(hc, bktName, objName, multipartInfo.UploadID, 1, 5*1048576)
+	etag2, part2 := uploadPart(hc, bktName, objName, multipartInfo.UploadID, 2, 10)
+
+	md5Part1, md5Part2 := md5.Sum(part1), md5.Sum(part2)
+	require.Equal(t, hex.EncodeToString(md5Part1[:]), strings.Trim(etag1, "\""))
+	require.Equal(t, hex.EncodeToString(md5Part2[:]), strings.Trim(etag2, "\""))
+
+	completeMultipartUpload(hc, bktName, objName, multipartInfo.UploadID, []string{etag1, etag2})
+
+	expected := md5.Sum(append(md5Part1[:], md5Part2[:]...))
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, hex.EncodeToString(expected[:])+"-2", w.Header().Get(api.ETag))
+}
+
+func TestListPartsChecksums(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-parts-checksums", "object-for-parts-checksums"
+	createTestBucket(hc, bktName)
+
+	part1, part2 := []byte("first part"), []byte("second part")
+	sha256Sum := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	multipartInfo := createMultipartUpload(hc, bktName, objName, map[string]string{api.AmzChecksumAlgorithm: "sha256"})
+
+	w := uploadPartWithHeaders(hc, bktName, objName, multipartInfo.UploadID, 1, part1,
+		map[string]string{api.AmzChecksumPrefix + layer.ChecksumSHA256: sha256Sum(part2)})
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrBadDigest))
+
+	w = uploadPartWithHeaders(hc, bktName, objName, multipartInfo.UploadID, 1, part1,
+		map[string]string{api.AmzChecksumPrefix + layer.ChecksumCRC32: "AAAAAA=="})
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w = uploadPartWithHeaders(hc, bktName, objName, multipartInfo.UploadID, 1, part1,
+		map[string]string{api.AmzChecksumPrefix + layer.ChecksumSHA256: sha256Sum(part1)})
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, sha256Sum(part1), w.Header().Get(api.AmzChecksumPrefix+layer.ChecksumSHA256))
+	etag1 := w.Header().Get(api.ETag)
+
+	// checksum is calculated with the upload algorithm if the part one isn't provided
+	w = uploadPartWithHeaders(hc, bktName, objName, multipartInfo.UploadID, 2, part2, nil)
+	assertStatus(t, w, http.StatusOK)
+	etag2 := w.Header().Get(api.ETag)
+
+	list := listParts(hc, bktName, objName, multipartInfo.UploadID)
+	require.Equal(t, layer.ChecksumSHA256, list.ChecksumAlgorithm)
+	require.Len(t, list.Parts, 2)
+	require.Equal(t, etag1, list.Parts[0].ETag)
+	require.EqualValues(t, len(part1), list.Parts[0].Size)
+	require.Equal(t, sha256Sum(part1), list.Parts[0].ChecksumSHA256)
+	require.Equal(t, etag2, list.Parts[1].ETag)
+	require.EqualValues(t, len(part2), list.Parts[1].Size)
+	require.Equal(t, sha256Sum(part2), list.Parts[1].ChecksumSHA256)
+
+	// upload without checksum algorithm accepts any part checksum
+	multipartInfo = createMultipartUpload(hc, bktName, objName, nil)
+	crc32Sum := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc32Sum, crc32.ChecksumIEEE(part1))
+
+	w = uploadPartWithHeaders(hc, bktName, objName, multipartInfo.UploadID, 1, part1,
+		map[string]string{api.AmzChecksumPrefix + layer.ChecksumCRC32: base64.StdEncoding.EncodeToString(crc32Sum)})
+	assertStatus(t, w, http.StatusOK)
+	w = uploadPartWithHeaders(hc, bktName, objName, multipartInfo.UploadID, 2, part2, nil)
+	assertStatus(t, w, http.StatusOK)
+
+	list = listParts(hc, bktName, objName, multipartInfo.UploadID)
+	require.Empty(t, list.ChecksumAlgorithm)
+	require.Len(t, list.Parts, 2)
+	require.Equal(t, base64.StdEncoding.EncodeToString(crc32Sum), list.Parts[0].ChecksumCRC32)
+	require.Empty(t, list.Parts[1].ChecksumCRC32)
+	require.Empty(t, list.Parts[1].ChecksumSHA256)
+}
+
+func uploadPartWithHeaders(hc *handlerContext, bktName, objName, uploadID string, num int, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	query := make(url.Values)
+	query.Set(uploadIDQuery, uploadID)
+	query.Set(partNumberQuery, strconv.Itoa(num))
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, objName, query, body)
+	setHeaders(r, headers)
+	hc.Handler().UploadPartHandler(w, r)
+
+	return w
+}
+
+func TestListPartsPagination(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-parts-pagination", "object-for-parts-pagination"
+	createTestBucket(hc, bktName)
+
+	multipartInfo := createMultipartUpload(hc, bktName, objName, nil)
+	for _, num := range []int{1, 2, 3, 5, 8} {
+		uploadPart(hc, bktName, objName, multipartInfo.UploadID, num, 10)
+	}
+
+	partNumbers := func(list *ListPartsResponse) []int {
+		res := make([]int, len(list.Parts))
+		for i, part := range list.Parts {
+			res[i] = part.PartNumber
+		}
+		return res
+	}
+
+	list := listPartsPage(hc, bktName, objName, multipartInfo.UploadID, "2", "")
+	require.Equal(t, []int{1, 2}, partNumbers(list))
+	require.True(t, list.IsTruncated)
+	require.Equal(t, 2, list.NextPartNumberMarker)
+
+	list = listPartsPage(hc, bktName, objName, multipartInfo.UploadID, "2", "2")
+	require.Equal(t, []int{3, 5}, partNumbers(list))
+	require.True(t, list.IsTruncated)
+	require.Equal(t, 5, list.NextPartNumberMarker)
+
+	list = listPartsPage(hc, bktName, objName, multipartInfo.UploadID, "2", "4")
+	require.Equal(t, []int{5, 8}, partNumbers(list))
+	require.False(t, list.IsTruncated)
+	require.Equal(t, 8, list.NextPartNumberMarker)
+
+	list = listPartsPage(hc, bktName, objName, multipartInfo.UploadID, "", "8")
+	require.Empty(t, list.Parts)
+	require.False(t, list.IsTruncated)
+
+	list = listPartsPage(hc, bktName, objName, multipartInfo.UploadID, "0", "")
+	require.Empty(t, list.Parts)
+	require.True(t, list.IsTruncated)
+
+	query := make(url.Values)
+	query.Set(uploadIDQuery, multipartInfo.UploadID)
+	query.Set("part-number-marker", "-1")
+	w, r := prepareTestRequestWithQuery(hc, bktName, objName, query, nil)
+	hc.Handler().ListPartsHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidPartNumberMarker))
+}
+
+func TestListMultipartUploads(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-listing-uploads"
+	createTestBucket(hc, bktName)
+
+	uploadIDs := make(map[string][]string)
+	for _, objName := range []string{"dir/a", "dir/b", "dir::sub/c", "obj", "obj", "other"} {
+		id := createMultipartUpload(hc, bktName, objName, nil).UploadID
+		uploadIDs[objName] = append(uploadIDs[objName], id)
+	}
+	sort.Strings(uploadIDs["obj"])
+
+	uploads := func(list *ListMultipartUploadsResponse) []string {
+		res := make([]string, len(list.Uploads))
+		for i, u := range list.Uploads {
+			res[i] = u.Key + "/" + u.UploadID
+		}
+		return res
+	}
+	prefixes := func(list *ListMultipartUploadsResponse) []string {
+		res := make([]string, len(list.CommonPrefixes))
+		for i, p := range list.CommonPrefixes {
+			res[i] = p.Prefix
+		}
+		return res
+	}
+
+	list := listMultipartUploads(hc, bktName, map[string]string{"prefix": "dir/"})
+	require.Equal(t, []string{"dir/a/" + uploadIDs["dir/a"][0], "dir/b/" + uploadIDs["dir/b"][0]}, uploads(list))
+
+	list = listMultipartUploads(hc, bktName, map[string]string{"delimiter": "::"})
+	require.Equal(t, []string{"dir::"}, prefixes(list))
+	require.Len(t, list.Uploads, 5)
+
+	// pages: "dir/a", "dir/b", "dir::", "obj" (two uploads), "other"
+	list = listMultipartUploads(hc, bktName, map[string]string{"delimiter": "::", "max-uploads": "2"})
+	require.Equal(t, []string{"dir/a/" + uploadIDs["dir/a"][0], "dir/b/" + uploadIDs["dir/b"][0]}, uploads(list))
+	require.True(t, list.IsTruncated)
+	require.Equal(t, "dir/b", list.NextKeyMarker)
+	require.Equal(t, uploadIDs["dir/b"][0], list.NextUploadIDMarker)
+
+	list = listMultipartUploads(hc, bktName, map[string]string{
+		"delimiter":        "::",
+		"max-uploads":      "1",
+		"key-marker":       list.NextKeyMarker,
+		"upload-id-marker": list.NextUploadIDMarker,
+	})
+	require.Equal(t, []string{"dir::"}, prefixes(list))
+	require.Empty(t, list.Uploads)
+	require.True(t, list.IsTruncated)
+	require.Equal(t, "dir::", list.NextKeyMarker)
+	require.Empty(t, list.NextUploadIDMarker)
+
+	list = listMultipartUploads(hc, bktName, map[string]string{"delimiter": "::", "max-uploads": "1", "key-marker": list.NextKeyMarker})
+	require.Equal(t, []string{"obj/" + uploadIDs["obj"][0]}, uploads(list))
+	require.True(t, list.IsTruncated)
+	require.Equal(t, "obj", list.NextKeyMarker)
+	require.Equal(t, uploadIDs["obj"][0], list.NextUploadIDMarker)
+
+	list = listMultipartUploads(hc, bktName, map[string]string{
+		"delimiter":        "::",
+		"key-marker":       list.NextKeyMarker,
+		"upload-id-marker": list.NextUploadIDMarker,
+	})
+	require.Equal(t, []string{"obj/" + uploadIDs["obj"][1], "other/" + uploadIDs["other"][0]}, uploads(list))
+	require.False(t, list.IsTruncated)
+
+	// upload-id-marker is ignored without key-marker, key-marker alone skips all uploads of the key
+	list = listMultipartUploads(hc, bktName, map[string]string{"upload-id-marker": uploadIDs["obj"][0]})
+	require.Len(t, list.Uploads, 6)
+	list = listMultipartUploads(hc, bktName, map[string]string{"key-marker": "obj"})
+	require.Equal(t, []string{"other/" + uploadIDs["other"][0]}, uploads(list))
+
+	list = listMultipartUploads(hc, bktName, map[string]string{"prefix": "dir::", "encoding-type": "url"})
+	require.Equal(t, "dir%3A%3A", list.Prefix)
+	require.Equal(t, "dir%3A%3Asub/c", list.Uploads[0].Key)
+}
+
+// slowCompleteClient completes multipart uploads after the delay.
+type slowCompleteClient struct {
+	layer.Client
+	delay time.Duration
+}
+
+func (c *slowCompleteClient) CompleteMultipartUpload(ctx context.Context, p *layer.CompleteMultipartParams) (*layer.UploadData, *data.ExtendedObjectInfo, error) {
+	time.Sleep(c.delay)
+	return c.Client.CompleteMultipartUpload(ctx, p)
+}
+
+func TestCompleteMultipartUploadKeepAlive(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-complete-keep-alive", "object"
+	createTestBucketWithLock(hc, bktName, nil)
+
+	hc.h.cfg.CompleteKeepAlive = 10 * time.Millisecond
+	hc.h.obj = &slowCompleteClient{Client: hc.h.obj, delay: 100 * time.Millisecond}
+
+	upload := createMultipartUpload(hc, bktName, objName, map[string]string{api.AmzObjectLockLegalHold: legalHoldOn})
+	etag, _ := uploadPart(hc, bktName, objName, upload.UploadID, 1, 5)
+
+	w := completeMultipartUploadRequest(hc, bktName, objName, upload.UploadID, etag, "")
+	require.True(t, strings.HasPrefix(w.Body.String(), xml.Header+" "))
+
+	// headers of the result are returned in the body of the started response
+	response := &CompleteMultipartUploadResponse{}
+	readResponse(t, w, http.StatusOK, response)
+	require.NotEmpty(t, response.VersionID)
+	require.Equal(t, legalHoldOn, response.ObjectLockLegalHoldStatus)
+
+	versions := listVersions(t, hc, bktName)
+	require.Len(t, versions.Version, 1)
+	require.Equal(t, versions.Version[0].VersionID, response.VersionID)
+}
+
+func listMultipartUploads(hc *handlerContext, bktName string, params map[string]string) *ListMultipartUploadsResponse {
+	query := make(url.Values)
+	query.Set("uploads", "")
+	for key, val := range params {
+		query.Set(key, val)
+	}
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, "", query, nil)
+	hc.Handler().ListMultipartUploadsHandler(w, r)
+
+	list := &ListMultipartUploadsResponse{}
+	readResponse(hc.t, w, http.StatusOK, list)
+	return list
+}
+
+func listParts(hc *handlerContext, bktName, objName, uploadID string) *ListPartsResponse {
+	return listPartsPage(hc, bktName, objName, uploadID, "", "")
+}
+
+func listPartsPage(hc *handlerContext, bktName, objName, uploadID, maxParts, partNumberMarker string) *ListPartsResponse {
+	query := make(url.Values)
+	query.Set(uploadIDQuery, uploadID)
+	if maxParts != "" {
+		query.Set("max-parts", maxParts)
+	}
+	if partNumberMarker != "" {
+		query.Set("part-number-marker", partNumberMarker)
+	}
+
+	w, r := prepareTestRequestWithQuery(hc, bktName, objName, query, nil)
+	hc.Handler().ListPartsHandler(w, r)
+
+	list := &ListPartsResponse{}
+	readResponse(hc.t, w, http.StatusOK, list)
+	return list
+}
+
+func completeMultipartUploadRequest(hc *handlerContext, bktName, objName, uploadID, etag, ifNoneMatch string) *httptest.ResponseRecorder {
+	query := make(url.Values)
+	query.Set(uploadIDQuery, uploadID)
+	complete := &CompleteMultipartUpload{
+		Parts: []*layer.CompletedPart{{ETag: etag, PartNumber: 1}},
+	}
+
+	w, r := prepareTestFullRequest(hc, bktName, objName, query, complete)
+	if ifNoneMatch != "" {
+		r.Header.Set(api.IfNoneMatch, ifNoneMatch)
+	}
+	hc.Handler().CompleteMultipartUploadHandler(w, r)
+
+	return w
+}
@@ -24,6 +24,8 @@ type (
 		ReqInfo          *api.ReqInfo
 		User             string
 		Time             time.Time
+		// QueuePolicy is a policy of the event if the queue of the notification target is full.
+		QueuePolicy string
 	}
 
 	NotificationConfiguration struct {
@@ -36,6 +38,7 @@ const (
 	filterRuleSuffixName = "suffix"
 	filterRulePrefixName = "prefix"
 
+	eventObjectCreatedPrefix                          = "s3:ObjectCreated:"
 	EventObjectCreated                                = "s3:ObjectCreated:*"
 	EventObjectCreatedPut                             = "s3:ObjectCreated:Put"
 	EventObjectCreatedPost                            = "s3:ObjectCreated:Post"
@@ -104,7 +107,7 @@ func (h *handler) PutBucketNotificationHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	conf := &data.NotificationConfiguration{}
-	if err = xml.NewDecoder(r.Body).Decode(conf); err != nil {
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
 		h.logAndSendError(w, "couldn't decode notification configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
 		return
 	}
@@ -149,6 +152,11 @@ func (h *handler) GetBucketNotificationHandler(w http.ResponseWriter, r *http.Re
 }
 
 func (h *handler) sendNotifications(ctx context.Context, p *SendNotificationParams) error {
+	// created objects are scanned regardless of notification configuration
+	if h.cfg.Scanner != nil && strings.HasPrefix(p.Event, eventObjectCreatedPrefix) {
+		h.cfg.Scanner.Enqueue(ctx, p.BktInfo, p.NotificationInfo)
+	}
+
 	if !h.cfg.NotificatorEnabled {
 		return nil
 	}
@@ -166,6 +174,12 @@ func (h *handler) sendNotifications(ctx context.Context, p *SendNotificationPara
 		p.User = bearer.ResolveIssuer(*box.Gate.BearerToken).EncodeToString()
 	}
 
+	settings, err := h.obj.GetBucketSettings(ctx, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("failed to get bucket settings: %w", err)
+	}
+	p.QueuePolicy = settings.NotificationQueuePolicy
+
 	p.Time = layer.TimeNow(ctx)
 
 	topics := filterSubjects(conf, p.Event, p.NotificationInfo.Name)
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"encoding/base64"
+	stderrors "errors"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -10,9 +12,10 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
-	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 )
 
+var errVersionMarkerWithoutKeyMarker = stderrors.New("a version-id marker cannot be specified without a key marker")
+
 // ListObjectsV1Handler handles objects listing requests for API version 1.
 func (h *handler) ListObjectsV1Handler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
@@ -27,13 +30,18 @@ func (h *handler) ListObjectsV1Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.htmlListingRequested(r) {
+		h.writeHTMLListing(w, r, params.BktInfo, params.Prefix)
+		return
+	}
+
 	list, err := h.obj.ListObjectsV1(r.Context(), params)
 	if err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 		return
 	}
 
-	if err = api.EncodeToResponse(w, encodeV1(params, list)); err != nil {
+	if err = api.EncodeListingToResponse(w, reqInfo, encodeV1(params, list)); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
@@ -42,12 +50,12 @@ func encodeV1(p *layer.ListObjectsParamsV1, list *layer.ListObjectsInfoV1) *List
 	res := &ListObjectsV1Response{
 		Name:         p.BktInfo.Name,
 		EncodingType: p.Encode,
-		Marker:       p.Marker,
-		Prefix:       p.Prefix,
+		Marker:       s3PathEncode(p.Marker, p.Encode),
+		Prefix:       s3PathEncode(p.Prefix, p.Encode),
 		MaxKeys:      p.MaxKeys,
-		Delimiter:    p.Delimiter,
+		Delimiter:    s3PathEncode(p.Delimiter, p.Encode),
 		IsTruncated:  list.IsTruncated,
-		NextMarker:   list.NextMarker,
+		NextMarker:   s3PathEncode(list.NextMarker, p.Encode),
 	}
 
 	res.CommonPrefixes = fillPrefixes(list.Prefixes, p.Encode)
@@ -77,7 +85,7 @@ func (h *handler) ListObjectsV2Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = api.EncodeToResponse(w, encodeV2(params, list)); err != nil {
+	if err = api.EncodeListingToResponse(w, reqInfo, encodeV2(params, list)); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
@@ -165,8 +173,7 @@ func parseListObjectArgs(reqInfo *api.ReqInfo) (*layer.ListObjectsParamsCommon,
 
 func parseContinuationToken(queryValues url.Values) (string, error) {
 	if val, ok := queryValues["continuation-token"]; ok {
-		var objID oid.ID
-		if err := objID.DecodeString(val[0]); err != nil {
+		if _, err := base64.RawURLEncoding.DecodeString(val[0]); err != nil || len(val[0]) == 0 {
 			return "", errors.GetAPIError(errors.ErrIncorrectContinuationToken)
 		}
 		return val[0], nil
@@ -196,6 +203,7 @@ func fillContents(src []*data.ObjectInfo, encode string, fetchOwner bool) []Obje
 			Size:         obj.Size,
 			LastModified: obj.Created.UTC().Format(time.RFC3339),
 			ETag:         obj.HashSum,
+			StorageClass: obj.StorageClass(),
 		}
 
 		if fetchOwner {
@@ -229,8 +237,8 @@ func (h *handler) ListBucketObjectVersionsHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	response := encodeListObjectVersionsToResponse(info, p.BktInfo.Name)
-	if err = api.EncodeToResponse(w, response); err != nil {
+	response := encodeListObjectVersionsToResponse(p, info)
+	if err = api.EncodeListingToResponse(w, reqInfo, response); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
@@ -244,52 +252,65 @@ func parseListObjectVersionsRequest(reqInfo *api.ReqInfo) (*layer.ListObjectVers
 
 	if queryValues.Get("max-keys") == "" {
 		res.MaxKeys = maxObjectList
-	} else if res.MaxKeys, err = strconv.Atoi(queryValues.Get("max-keys")); err != nil || res.MaxKeys <= 0 {
+	} else if res.MaxKeys, err = strconv.Atoi(queryValues.Get("max-keys")); err != nil || res.MaxKeys < 0 {
 		return nil, errors.GetAPIError(errors.ErrInvalidMaxKeys)
+	} else if res.MaxKeys > maxObjectList {
+		res.MaxKeys = maxObjectList
 	}
 
 	res.Prefix = queryValues.Get("prefix")
-	res.KeyMarker = queryValues.Get("marker")
+	res.KeyMarker = queryValues.Get("key-marker")
 	res.Delimiter = queryValues.Get("delimiter")
 	res.Encode = queryValues.Get("encoding-type")
 	res.VersionIDMarker = queryValues.Get("version-id-marker")
 
+	if res.Encode != "" && res.Encode != urlEncodingType {
+		return nil, errors.GetAPIError(errors.ErrInvalidEncodingMethod)
+	}
+	if res.VersionIDMarker != "" && res.KeyMarker == "" {
+		return nil, errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errVersionMarkerWithoutKeyMarker)
+	}
+
 	return &res, nil
 }
 
-func encodeListObjectVersionsToResponse(info *layer.ListObjectVersionsInfo, bucketName string) *ListObjectsVersionsResponse {
+func encodeListObjectVersionsToResponse(p *layer.ListObjectVersionsParams, info *layer.ListObjectVersionsInfo) *ListObjectsVersionsResponse {
+	encode := p.Encode
 	res := ListObjectsVersionsResponse{
-		Name:                bucketName,
+		Name:                p.BktInfo.Name,
+		Prefix:              s3PathEncode(p.Prefix, encode),
+		Delimiter:           s3PathEncode(p.Delimiter, encode),
+		MaxKeys:             p.MaxKeys,
+		EncodingType:        encode,
 		IsTruncated:         info.IsTruncated,
-		KeyMarker:           info.KeyMarker,
-		NextKeyMarker:       info.NextKeyMarker,
+		KeyMarker:           s3PathEncode(info.KeyMarker, encode),
+		NextKeyMarker:       s3PathEncode(info.NextKeyMarker, encode),
 		NextVersionIDMarker: info.NextVersionIDMarker,
 		VersionIDMarker:     info.VersionIDMarker,
 	}
 
-	for _, prefix := range info.CommonPrefixes {
-		res.CommonPrefixes = append(res.CommonPrefixes, CommonPrefix{Prefix: prefix})
-	}
+	res.CommonPrefixes = fillPrefixes(info.CommonPrefixes, encode)
 
 	for _, ver := range info.Version {
 		res.Version = append(res.Version, ObjectVersionResponse{
 			IsLatest:     ver.IsLatest,
-			Key:          ver.ObjectInfo.Name,
+			Key:          s3PathEncode(ver.ObjectInfo.Name, encode),
 			LastModified: ver.ObjectInfo.Created.UTC().Format(time.RFC3339),
 			Owner: Owner{
 				ID:          ver.ObjectInfo.Owner.String(),
 				DisplayName: ver.ObjectInfo.Owner.String(),
 			},
-			Size:      ver.ObjectInfo.Size,
-			VersionID: ver.Version(),
-			ETag:      ver.ObjectInfo.HashSum,
+			Size:         ver.ObjectInfo.Size,
+			VersionID:    ver.Version(),
+			ETag:         ver.ObjectInfo.HashSum,
+			StorageClass: ver.ObjectInfo.StorageClass(),
 		})
 	}
 	// this loop is not starting till versioning is not implemented
 	for _, del := range info.DeleteMarker {
 		res.DeleteMarker = append(res.DeleteMarker, DeleteMarkerEntry{
 			IsLatest:     del.IsLatest,
-			Key:          del.ObjectInfo.Name,
+			Key:          s3PathEncode(del.ObjectInfo.Name, encode),
 			LastModified: del.ObjectInfo.Created.UTC().Format(time.RFC3339),
 			Owner: Owner{
 				ID:          del.ObjectInfo.Owner.String(),
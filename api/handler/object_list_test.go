@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strconv"
 	"testing"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,14 +26,14 @@ func TestParseContinuationToken(t *testing.T) {
 
 	t.Run("invalid not empty token", func(t *testing.T) {
 		var queryValues = map[string][]string{
-			"continuation-token": {"asd"},
+			"continuation-token": {"as*d"},
 		}
 		_, err = parseContinuationToken(queryValues)
 		require.Error(t, err)
 	})
 
 	t.Run("valid token", func(t *testing.T) {
-		tokenStr := "75BTT5Z9o79XuKdUeGqvQbqDnxu6qWcR5EhxW8BXFf8t"
+		tokenStr := "ZGlyL2EgYg" // dir/a b
 		var queryValues = map[string][]string{
 			"continuation-token": {tokenStr},
 		}
@@ -129,6 +132,172 @@ func TestS3BucketListV2DelimiterPrefix(t *testing.T) {
 	validateListV2(t, tc, bktName, prefix, delim, "", 2, false, true, []string{"boo/bar"}, []string{"boo/baz/"})
 }
 
+func TestS3BucketListV1EncodedMarker(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-listing-encoded"
+	bktInfo, _ := createBucketAndObject(tc, bktName, "dir/a b")
+	createTestObject(tc, bktInfo, "dir/c d")
+
+	query := prepareCommonListObjectsQuery("dir/", "/", 1)
+	query.Add("encoding-type", urlEncodingType)
+	query.Add("marker", "dir/ ")
+
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	tc.Handler().ListObjectsV1Handler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	res := &ListObjectsV1Response{}
+	parseTestResponse(t, w, res)
+
+	require.True(t, res.IsTruncated)
+	require.Equal(t, "dir/%20", res.Marker)
+	require.Equal(t, "dir/a%20b", res.NextMarker)
+	require.Equal(t, "dir/a%20b", res.Contents[0].Key)
+}
+
+func TestS3BucketListV2ContinuationTokenRemovedObject(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-listing-token"
+	bktInfo, _ := createBucketAndObject(tc, bktName, "a")
+	createTestObject(tc, bktInfo, "b")
+	createTestObject(tc, bktInfo, "c")
+
+	token := validateListV2(t, tc, bktName, "", "", "", 1, true, false, []string{"a"}, []string{})
+
+	// the next object is removed between requests, listing must go on
+	deleteObject(t, tc, bktName, "b", emptyVersion)
+	validateListV2(t, tc, bktName, "", "", token, 1, false, true, []string{"c"}, []string{})
+}
+
+func TestS3BucketListVersionsEncoded(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-versions-encoded"
+	bktInfo, _ := createBucketAndObject(tc, bktName, "dir/a b")
+	createTestObject(tc, bktInfo, "e f/g")
+	createTestObject(tc, bktInfo, "h i")
+
+	query := make(url.Values)
+	query.Add("encoding-type", urlEncodingType)
+	query.Add("delimiter", "/")
+
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	tc.Handler().ListBucketObjectVersionsHandler(w, r)
+	res := &ListObjectsVersionsResponse{}
+	parseTestResponse(t, w, res)
+
+	require.Equal(t, urlEncodingType, res.EncodingType)
+	require.Len(t, res.CommonPrefixes, 2)
+	require.Equal(t, "dir/", res.CommonPrefixes[0].Prefix)
+	require.Equal(t, "e%20f/", res.CommonPrefixes[1].Prefix)
+	require.Len(t, res.Version, 1)
+	require.Equal(t, "h%20i", res.Version[0].Key)
+}
+
+func TestListObjectVersionsPagination(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-versions-pagination"
+	createTestBucket(hc, bktName)
+	putBucketVersioning(t, hc, bktName, true)
+
+	putObjectContent(hc, bktName, "a", "content")
+	putObjectContent(hc, bktName, "a", "content2")
+	putObjectContent(hc, bktName, "b", "content")
+	putObjectContent(hc, bktName, "dir/c", "content")
+	putObjectContent(hc, bktName, "dir/d", "content")
+
+	all := listVersionsWithQuery(t, hc, bktName, url.Values{"delimiter": []string{"/"}})
+	require.False(t, all.IsTruncated)
+	require.Len(t, all.Version, 3)
+	require.Len(t, all.CommonPrefixes, 1)
+
+	var (
+		keys   []string
+		query  = url.Values{"delimiter": []string{"/"}, "max-keys": []string{"1"}}
+		result = listVersionsWithQuery(t, hc, bktName, query)
+	)
+	for {
+		require.Equal(t, 1, result.MaxKeys)
+		require.Equal(t, 1, len(result.Version)+len(result.CommonPrefixes))
+		for _, v := range result.Version {
+			keys = append(keys, v.Key+"@"+v.VersionID)
+		}
+		for _, prefix := range result.CommonPrefixes {
+			keys = append(keys, prefix.Prefix)
+		}
+		if !result.IsTruncated {
+			break
+		}
+
+		query.Set("key-marker", result.NextKeyMarker)
+		query.Set("version-id-marker", result.NextVersionIDMarker)
+		if result.NextVersionIDMarker == "" {
+			query.Del("version-id-marker")
+		}
+		result = listVersionsWithQuery(t, hc, bktName, query)
+		require.Equal(t, query.Get("key-marker"), result.KeyMarker)
+	}
+
+	require.Equal(t, []string{
+		"a@" + all.Version[0].VersionID,
+		"a@" + all.Version[1].VersionID,
+		"b@" + all.Version[2].VersionID,
+		"dir/",
+	}, keys)
+
+	result = listVersionsWithQuery(t, hc, bktName, url.Values{"key-marker": []string{"a"}})
+	require.Len(t, result.Version, 3)
+	require.Equal(t, "b", result.Version[0].Key)
+
+	w, r := prepareTestFullRequest(hc, bktName, "", url.Values{"version-id-marker": []string{all.Version[0].VersionID}}, nil)
+	hc.Handler().ListBucketObjectVersionsHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w, r = prepareTestFullRequest(hc, bktName, "", url.Values{"encoding-type": []string{"base64"}}, nil)
+	hc.Handler().ListBucketObjectVersionsHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidEncodingMethod))
+}
+
+func listVersionsWithQuery(t *testing.T, tc *handlerContext, bktName string, query url.Values) *ListObjectsVersionsResponse {
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	tc.Handler().ListBucketObjectVersionsHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	res := &ListObjectsVersionsResponse{}
+	parseTestResponse(t, w, res)
+	return res
+}
+
+func TestListObjectsJSON(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-json-listing"
+	createBucketAndObject(tc, bktName, "dir/a")
+
+	query := url.Values{"list-type": []string{"2"}, "delimiter": []string{"/"}, "format": []string{"json"}}
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	tc.Handler().ListObjectsV2Handler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	res := &ListObjectsV2Response{}
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(res))
+	require.Equal(t, bktName, res.Name)
+	require.Len(t, res.CommonPrefixes, 1)
+	require.Equal(t, "dir/", res.CommonPrefixes[0].Prefix)
+
+	w, r = prepareTestFullRequest(tc, bktName, "", url.Values{"list-type": []string{"2"}, "max-keys": []string{"-1"}}, nil)
+	r.Header.Set(api.Accept, "application/json")
+	r = r.WithContext(api.SetReqInfo(r.Context(), api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName})))
+	tc.Handler().ListObjectsV2Handler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errResp struct{ Code string }
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&errResp))
+	require.Equal(t, "InvalidArgument", errResp.Code)
+}
+
 func listObjectsV2(t *testing.T, tc *handlerContext, bktName, prefix, delimiter, startAfter, continuationToken string, maxKeys int) *ListObjectsV2Response {
 	query := prepareCommonListObjectsQuery(prefix, delimiter, maxKeys)
 	if len(startAfter) != 0 {
@@ -195,3 +364,51 @@ func listObjectsV1(t *testing.T, tc *handlerContext, bktName, prefix, delimiter,
 	parseTestResponse(t, w, res)
 	return res
 }
+
+func TestListBucketsPagination(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	for _, name := range []string{"bucket-c", "bucket-a", "other", "bucket-b"} {
+		createTestBucket(hc, name)
+	}
+
+	listBuckets := func(query url.Values) *ListBucketsResponse {
+		w, r := prepareTestFullRequest(hc, "", "", query, nil)
+		hc.Handler().ListBucketsHandler(w, r)
+		res := &ListBucketsResponse{}
+		readResponse(t, w, http.StatusOK, res)
+		return res
+	}
+	names := func(res *ListBucketsResponse) []string {
+		var names []string
+		for _, bkt := range res.Buckets.Buckets {
+			names = append(names, bkt.Name)
+		}
+		return names
+	}
+
+	res := listBuckets(url.Values{})
+	require.Equal(t, []string{"bucket-a", "bucket-b", "bucket-c", "other"}, names(res))
+	require.Empty(t, res.ContinuationToken)
+
+	query := url.Values{"prefix": []string{"bucket-"}, "max-buckets": []string{"2"}}
+	res = listBuckets(query)
+	require.Equal(t, []string{"bucket-a", "bucket-b"}, names(res))
+	require.Equal(t, "bucket-", res.Prefix)
+	require.NotEmpty(t, res.ContinuationToken)
+
+	query.Set("continuation-token", res.ContinuationToken)
+	res = listBuckets(query)
+	require.Equal(t, []string{"bucket-c"}, names(res))
+	require.Empty(t, res.ContinuationToken)
+
+	for _, maxBuckets := range []string{"0", "10001", "-1", "many"} {
+		w, r := prepareTestFullRequest(hc, "", "", url.Values{"max-buckets": []string{maxBuckets}}, nil)
+		hc.Handler().ListBucketsHandler(w, r)
+		assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidMaxBuckets))
+	}
+
+	w, r := prepareTestFullRequest(hc, "", "", url.Values{"continuation-token": []string{"!"}}, nil)
+	hc.Handler().ListBucketsHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrIncorrectContinuationToken))
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+func (h *handler) GetBucketOwnershipControlsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	if settings.ObjectOwnership == "" {
+		h.logAndSendError(w, "ownership controls aren't set", reqInfo,
+			errors.GetAPIError(errors.ErrOwnershipControlsNotFoundError))
+		return
+	}
+
+	resp := &OwnershipControls{Rules: []OwnershipControlsRule{{ObjectOwnership: settings.ObjectOwnership}}}
+	if err = api.EncodeToResponse(w, resp); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func (h *handler) PutBucketOwnershipControlsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	controls := &OwnershipControls{}
+	if err = api.NewXMLDecoder(r.Body).Decode(controls); err != nil {
+		h.logAndSendError(w, "couldn't parse ownership controls", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if len(controls.Rules) != 1 || !isObjectOwnership(controls.Rules[0].ObjectOwnership) {
+		h.logAndSendError(w, "invalid ownership controls", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if err = h.setObjectOwnership(r.Context(), bktInfo, controls.Rules[0].ObjectOwnership); err != nil {
+		h.logAndSendError(w, "couldn't put ownership controls", reqInfo, err)
+	}
+}
+
+func (h *handler) DeleteBucketOwnershipControlsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.setObjectOwnership(r.Context(), bktInfo, ""); err != nil {
+		h.logAndSendError(w, "couldn't delete ownership controls", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) setObjectOwnership(ctx context.Context, bktInfo *data.BucketInfo, ownership string) error {
+	settings, err := h.obj.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+
+	// settings pointer is stored in the cache, so modify a copy of the settings
+	newSettings := *settings
+	newSettings.ObjectOwnership = ownership
+
+	return h.obj.PutBucketSettings(ctx, &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &newSettings,
+	})
+}
+
+func isObjectOwnership(ownership string) bool {
+	switch ownership {
+	case data.ObjectOwnershipObjectWriter, data.ObjectOwnershipBucketOwnerPreferred, data.ObjectOwnershipBucketOwnerEnforced:
+		return true
+	}
+	return false
+}
+
+// aclDisabled checks if ACLs of the bucket are disabled by BucketOwnerEnforced object ownership.
+func aclDisabled(settings *data.BucketSettings) bool {
+	return settings.ObjectOwnership == data.ObjectOwnershipBucketOwnerEnforced
+}
+
+// checkACLHeadersAllowed returns AccessControlListNotSupported error if ACLs of the bucket are disabled
+// and the request sets ACL other than bucket-owner-full-control.
+func checkACLHeadersAllowed(settings *data.BucketSettings, r *http.Request) error {
+	if !aclDisabled(settings) {
+		return nil
+	}
+
+	if acl := r.Header.Get(api.AmzACL); (acl != "" && acl != layer.CannedACLBucketOwnerFullControl) || containsGrantHeaders(r) {
+		return errors.GetAPIError(errors.ErrAccessControlListNotSupported)
+	}
+
+	return nil
+}
+
+// checkPutACLDisabled checks PutBucketAcl and PutObjectAcl requests. It returns true if ACLs of the bucket
+// are disabled and the request sets bucket-owner-full-control ACL which is accepted without changes.
+func (h *handler) checkPutACLDisabled(r *http.Request, bktInfo *data.BucketInfo) (bool, error) {
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		return false, err
+	}
+	if !aclDisabled(settings) {
+		return false, nil
+	}
+
+	if r.ContentLength != 0 || !isBucketOwnerFullControl(r) {
+		return false, errors.GetAPIError(errors.ErrAccessControlListNotSupported)
+	}
+	return true, checkACLHeadersAllowed(settings, r)
+}
+
+func isBucketOwnerFullControl(r *http.Request) bool {
+	return r.Header.Get(api.AmzACL) == layer.CannedACLBucketOwnerFullControl
+}
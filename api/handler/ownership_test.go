@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketOwnershipControls(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName := "bucket-for-ownership"
+
+	box, _ := createAccessBox(t)
+	bktInfo := createBucket(t, hc, bktName, box)
+
+	writerBox, _ := createAccessBox(t)
+	writer := bearer.ResolveIssuer(*writerBox.Gate.BearerToken)
+
+	getOwnershipControls(hc, bktName, http.StatusNotFound)
+
+	putOwnershipControls(hc, bktName, data.ObjectOwnershipBucketOwnerPreferred, http.StatusOK)
+	require.Equal(t, data.ObjectOwnershipBucketOwnerPreferred, getOwnershipControls(hc, bktName, http.StatusOK))
+	putOwnershipControls(hc, bktName, "BucketOwner", http.StatusBadRequest)
+
+	putObjectWithACL(hc, bktName, "writer-owned", writerBox, "", http.StatusOK)
+	require.Equal(t, writer, objectOwner(hc, bktInfo, writerBox, "writer-owned"))
+	putObjectWithACL(hc, bktName, "bucket-owned", writerBox, layer.CannedACLBucketOwnerFullControl, http.StatusOK)
+	require.Equal(t, bktInfo.Owner, objectOwner(hc, bktInfo, writerBox, "bucket-owned"))
+	var stored int
+	for _, obj := range hc.MockedPool().Objects() {
+		for _, attr := range obj.Attributes() {
+			if attr.Key() == layer.AttributeObjectOwner && attr.Value() == bktInfo.Owner.EncodeToString() {
+				stored++
+			}
+		}
+	}
+	require.Equal(t, 1, stored)
+
+	putOwnershipControls(hc, bktName, data.ObjectOwnershipBucketOwnerEnforced, http.StatusOK)
+	putObjectWithACL(hc, bktName, "public", writerBox, basicACLReadOnly, http.StatusBadRequest)
+	putObjectWithACL(hc, bktName, "enforced", writerBox, "", http.StatusOK)
+	require.Equal(t, bktInfo.Owner, objectOwner(hc, bktInfo, writerBox, "enforced"))
+
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	r.Header.Set(api.AmzACL, basicACLReadOnly)
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().PutBucketACLHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+	putBucketACL(t, hc, bktName, box, map[string]string{api.AmzACL: layer.CannedACLBucketOwnerFullControl})
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeleteBucketOwnershipControlsHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	getOwnershipControls(hc, bktName, http.StatusNotFound)
+
+	putObjectWithACL(hc, bktName, "writer-owned", writerBox, basicACLReadOnly, http.StatusOK)
+	require.Equal(t, writer, objectOwner(hc, bktInfo, writerBox, "writer-owned"))
+}
+
+func getOwnershipControls(hc *handlerContext, bktName string, status int) string {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketOwnershipControlsHandler(w, r)
+	assertStatus(hc.t, w, status)
+
+	if status != http.StatusOK {
+		return ""
+	}
+
+	controls := &OwnershipControls{}
+	readResponse(hc.t, w, status, controls)
+	require.Len(hc.t, controls.Rules, 1)
+	return controls.Rules[0].ObjectOwnership
+}
+
+func putOwnershipControls(hc *handlerContext, bktName, ownership string, status int) {
+	controls := &OwnershipControls{Rules: []OwnershipControlsRule{{ObjectOwnership: ownership}}}
+	w, r := prepareTestRequest(hc, bktName, "", controls)
+	hc.Handler().PutBucketOwnershipControlsHandler(w, r)
+	assertStatus(hc.t, w, status)
+}
+
+func putObjectWithACL(hc *handlerContext, bktName, objName string, box *accessbox.Box, acl string, status int) {
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader([]byte("content")))
+	if acl != "" {
+		r.Header.Set(api.AmzACL, acl)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(hc.t, w, status)
+}
+
+func objectOwner(hc *handlerContext, bktInfo *data.BucketInfo, box *accessbox.Box, objName string) user.ID {
+	ctx := context.WithValue(hc.Context(), api.BoxData, box)
+	objInfo, err := hc.Layer().GetObjectInfo(ctx, &layer.HeadObjectParams{BktInfo: bktInfo, Object: objName})
+	require.NoError(hc.t, err)
+	return objInfo.Owner
+}
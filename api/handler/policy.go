@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+)
+
+// routeActions maps API route names to S3 actions used in bucket policies.
+// Routes missing in the map use "s3:" + route name.
+var routeActions = map[string]string{
+	"HeadObject":                "s3:GetObject",
+	"Website":                   "s3:GetObject",
+	"SelectObjectContent":       "s3:GetObject",
+	"GetObjectAttributes":       "s3:GetObject",
+	"CreateDownloadToken":       "s3:GetObject",
+	"UploadPart":                "s3:PutObject",
+	"UploadPartCopy":            "s3:PutObject",
+	"CreateMultipartUpload":     "s3:PutObject",
+	"CompleteMultipartUpload":   "s3:PutObject",
+	"ConcatenateObjects":        "s3:PutObject",
+	"CopyObject":                "s3:PutObject",
+	"PostObject":                "s3:PutObject",
+	"RenameObject":              "s3:PutObject",
+	"UpdateObjectMetadata":      "s3:PutObject",
+	"AbortMultipartUpload":      "s3:AbortMultipartUpload",
+	"ListObjectParts":           "s3:ListMultipartUploadParts",
+	"ListMultipartUploads":      "s3:ListBucketMultipartUploads",
+	"DeleteMultipleObjects":     "s3:DeleteObject",
+	"GetObjectACL":              "s3:GetObjectAcl",
+	"PutObjectACL":              "s3:PutObjectAcl",
+	"GetBucketACL":              "s3:GetBucketAcl",
+	"PutBucketACL":              "s3:PutBucketAcl",
+	"HeadBucket":                "s3:ListBucket",
+	"ListObjectsV1":             "s3:ListBucket",
+	"ListObjectsV2":             "s3:ListBucket",
+	"ListObjectsV2M":            "s3:ListBucket",
+	"SearchObjects":             "s3:ListBucket",
+	"GetBucketCors":             "s3:GetBucketCORS",
+	"PutBucketCors":             "s3:PutBucketCORS",
+	"DeleteBucketCors":          "s3:PutBucketCORS",
+	"GetBucketLifecycle":        "s3:GetLifecycleConfiguration",
+	"PutBucketLifecycle":        "s3:PutLifecycleConfiguration",
+	"DeleteBucketLifecycle":     "s3:PutLifecycleConfiguration",
+	"GetBucketEncryption":       "s3:GetEncryptionConfiguration",
+	"PutBucketEncryption":       "s3:PutEncryptionConfiguration",
+	"DeleteBucketEncryption":    "s3:PutEncryptionConfiguration",
+	"GetBucketObjectLockConfig": "s3:GetBucketObjectLockConfiguration",
+	"PutBucketObjectLockConfig": "s3:PutBucketObjectLockConfiguration",
+	"DeleteBucketTagging":       "s3:PutBucketTagging",
+	"GetPublicAccessBlock":      "s3:GetBucketPublicAccessBlock",
+	"PutPublicAccessBlock":      "s3:PutBucketPublicAccessBlock",
+	"DeletePublicAccessBlock":   "s3:PutBucketPublicAccessBlock",
+
+	"DeleteBucketOwnershipControls":      "s3:PutBucketOwnershipControls",
+	"GetBucketInventoryConfiguration":    "s3:GetInventoryConfiguration",
+	"ListBucketInventoryConfigurations":  "s3:GetInventoryConfiguration",
+	"PutBucketInventoryConfiguration":    "s3:PutInventoryConfiguration",
+	"DeleteBucketInventoryConfiguration": "s3:PutInventoryConfiguration",
+	"GetBucketAnalyticsConfiguration":    "s3:GetAnalyticsConfiguration",
+	"ListBucketAnalyticsConfigurations":  "s3:GetAnalyticsConfiguration",
+	"PutBucketAnalyticsConfiguration":    "s3:PutAnalyticsConfiguration",
+	"DeleteBucketAnalyticsConfiguration": "s3:PutAnalyticsConfiguration",
+	"GetBucketMetricsConfiguration":      "s3:GetMetricsConfiguration",
+	"ListBucketMetricsConfigurations":    "s3:GetMetricsConfiguration",
+	"PutBucketMetricsConfiguration":      "s3:PutMetricsConfiguration",
+	"DeleteBucketMetricsConfiguration":   "s3:PutMetricsConfiguration",
+	"GetBucketReplication":               "s3:GetReplicationConfiguration",
+	"PutBucketReplication":               "s3:PutReplicationConfiguration",
+	"DeleteBucketReplication":            "s3:PutReplicationConfiguration",
+	"GetBucketAccelerate":                "s3:GetAccelerateConfiguration",
+	"PutBucketAccelerate":                "s3:PutAccelerateConfiguration",
+}
+
+// policyManagementRoutes are never denied for the bucket owner to prevent the owner lockout.
+var policyManagementRoutes = map[string]struct{}{
+	"GetBucketPolicy":    {},
+	"PutBucketPolicy":    {},
+	"DeleteBucketPolicy": {},
+
+	"GetPublicAccessBlock":    {},
+	"PutPublicAccessBlock":    {},
+	"DeletePublicAccessBlock": {},
+}
+
+// readPostRoutes are routes with POST method which don't modify the bucket, they are allowed in read-only buckets.
+var readPostRoutes = map[string]struct{}{
+	"CreateDownloadToken": {},
+	"SelectObjectContent": {},
+	"SearchObjects":       {},
+}
+
+// frozenRoutes are routes deleting or replacing objects of the bucket, they are rejected in frozen buckets.
+var frozenRoutes = map[string]struct{}{
+	"DeleteBucket":          {},
+	"DeleteObject":          {},
+	"DeleteMultipleObjects": {},
+	"RenameObject":          {},
+	"UpdateObjectMetadata":  {},
+	"PutBucketLifecycle":    {},
+}
+
+func routeToAction(route string) string {
+	if action, ok := routeActions[route]; ok {
+		return action
+	}
+	return "s3:" + route
+}
+
+// CheckBucketPolicy evaluates the bucket policy for the request. It sends
+// AccessDenied error and returns false if the policy explicitly denies the request
+// or the anonymous request is restricted by the public access block of the bucket.
+// Requests forbidden by the read-only and frozen flags of the bucket are rejected
+// before the policy evaluation.
+func (h *handler) CheckBucketPolicy(w http.ResponseWriter, r *http.Request) bool {
+	reqInfo := api.GetReqInfo(r.Context())
+	if reqInfo.BucketName == "" {
+		return true
+	}
+	switch reqInfo.API {
+	case "Options", "CreateBucket", "ListBuckets":
+		return true
+	}
+
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
+	if err != nil {
+		// the handler reports the error itself
+		return true
+	}
+
+	// the request isn't allowed if the settings can't be read, flags of the bucket could deny the request
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return false
+	}
+	if err = checkBucketFlags(r.Method, reqInfo.API, settings); err != nil {
+		h.logAndSendError(w, "denied by bucket flags", reqInfo, err)
+		return false
+	}
+
+	var (
+		principal string
+		anonymous = true
+	)
+	if box, err := layer.GetBoxData(r.Context()); err == nil && box.Gate.BearerToken != nil {
+		if _, ok := policyManagementRoutes[reqInfo.API]; ok && bktInfo.Owner.Equals(bearer.ResolveIssuer(*box.Gate.BearerToken)) {
+			return true
+		}
+
+		key, err := h.bearerTokenIssuerKey(r.Context())
+		if err != nil {
+			h.logAndSendError(w, "couldn't get requester key", reqInfo, err)
+			return false
+		}
+		principal = hex.EncodeToString(key.Bytes())
+		anonymous = false
+	}
+
+	decision := policy.NoDecision
+	bktPolicy, err := h.obj.GetBucketPolicy(r.Context(), bktInfo)
+	if err != nil {
+		// the request isn't allowed if the policy can't be read, it could deny the request
+		if !errors.IsS3Error(err, errors.ErrNoSuchBucketPolicy) {
+			h.logAndSendError(w, "couldn't get bucket policy", reqInfo, err)
+			return false
+		}
+		bktPolicy = nil
+	} else {
+		resource := policy.ArnPrefix + bktInfo.Name
+		if reqInfo.ObjectName != "" {
+			resource += "/" + reqInfo.ObjectName
+		}
+
+		decision = bktPolicy.Evaluate(policy.Request{
+			Principal: principal,
+			Action:    routeToAction(reqInfo.API),
+			Resource:  resource,
+		})
+	}
+
+	if decision == policy.Deny {
+		h.logAndSendError(w, "denied by bucket policy", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return false
+	}
+
+	if anonymous {
+		if err = h.checkPublicAccess(r.Context(), bktInfo, bktPolicy, decision); err != nil {
+			h.logAndSendError(w, "denied by public access block", reqInfo, err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkBucketFlags returns BucketReadOnly error if the read-only bucket is modified by the request
+// and BucketFrozen error if the request deletes or replaces objects of the frozen bucket.
+func checkBucketFlags(method, route string, settings *data.BucketSettings) error {
+	if settings.ReadOnly {
+		_, read := readPostRoutes[route]
+		if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions && !read {
+			return errors.GetAPIError(errors.ErrBucketReadOnly)
+		}
+	}
+
+	if _, ok := frozenRoutes[route]; ok && settings.Frozen {
+		return errors.GetAPIError(errors.ErrBucketFrozen)
+	}
+
+	return nil
+}
+
+// checkBypassGovernance checks that the requester is allowed to bypass governance retention of the object.
+// The bucket owner is allowed unless the bucket policy denies s3:BypassGovernanceRetention, other users
+// are allowed only if the bucket policy grants it.
+func (h *handler) checkBypassGovernance(r *http.Request, bktInfo *data.BucketInfo, object string) error {
+	var (
+		principal string
+		isOwner   bool
+	)
+	if box, err := layer.GetBoxData(r.Context()); err == nil && box.Gate.BearerToken != nil {
+		isOwner = bktInfo.Owner.Equals(bearer.ResolveIssuer(*box.Gate.BearerToken))
+
+		key, err := h.bearerTokenIssuerKey(r.Context())
+		if err != nil {
+			return err
+		}
+		principal = hex.EncodeToString(key.Bytes())
+	}
+
+	decision := policy.NoDecision
+	bktPolicy, err := h.obj.GetBucketPolicy(r.Context(), bktInfo)
+	if err != nil {
+		if !errors.IsS3Error(err, errors.ErrNoSuchBucketPolicy) {
+			return err
+		}
+	} else {
+		decision = bktPolicy.Evaluate(policy.Request{
+			Principal: principal,
+			Action:    "s3:BypassGovernanceRetention",
+			Resource:  policy.ArnPrefix + bktInfo.Name + "/" + object,
+		})
+	}
+
+	if decision == policy.Allow || decision == policy.NoDecision && isOwner {
+		return nil
+	}
+
+	return errors.GetAPIError(errors.ErrAccessDenied)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// PresignNonceResponse is a response of presigned URL nonce creation.
+type PresignNonceResponse struct {
+	XMLName    xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PresignNonceResult" json:"-"`
+	Nonce      string   `xml:"Nonce"`
+	Query      string   `xml:"Query"`
+	Expiration string   `xml:"Expiration"`
+}
+
+const (
+	defaultNonceLifetime = time.Hour
+	// maxNonceLifetime is the max expiration period of presigned URLs in AWS S3.
+	maxNonceLifetime = 7 * 24 * time.Hour
+)
+
+// CreatePresignNonceHandler issues the nonce to be signed in the presigned URL with credentials of the request.
+// The URL with the nonce is accepted once.
+func (h *handler) CreatePresignNonceHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if h.cfg.PresignNonces == nil {
+		h.logAndSendError(w, "presigned URL nonces are disabled", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	accessKeyID, ok := r.Context().Value(api.AccessKeyID).(string)
+	if !ok {
+		h.logAndSendError(w, "anonymous presigned URL nonce", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return
+	}
+
+	lifetime := defaultNonceLifetime
+	if expires := reqInfo.URL.Query().Get(tokenExpiresQuery); len(expires) > 0 {
+		seconds, err := strconv.Atoi(expires)
+		if err != nil || seconds <= 0 || time.Duration(seconds)*time.Second > maxNonceLifetime {
+			h.logAndSendError(w, "invalid nonce lifetime", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+			return
+		}
+		lifetime = time.Duration(seconds) * time.Second
+	}
+
+	nonce, expiration, err := h.cfg.PresignNonces.Issue(accessKeyID, lifetime, time.Now())
+	if err != nil {
+		h.logAndSendError(w, "could not issue nonce", reqInfo, err)
+		return
+	}
+
+	response := &PresignNonceResponse{
+		Nonce:      nonce,
+		Query:      auth.NonceQuery,
+		Expiration: expiration.UTC().Format(time.RFC3339),
+	}
+
+	if err = api.EncodeToResponse(w, response); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
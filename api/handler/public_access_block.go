@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
+)
+
+func (h *handler) GetPublicAccessBlockHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	if settings.PublicAccessBlock == nil {
+		h.logAndSendError(w, "public access block isn't set", reqInfo,
+			errors.GetAPIError(errors.ErrNoSuchPublicAccessBlockConfiguration))
+		return
+	}
+
+	if err = api.EncodeToResponse(w, settings.PublicAccessBlock); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func (h *handler) PutPublicAccessBlockHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := &data.PublicAccessBlockConfiguration{}
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "couldn't parse public access block configuration", reqInfo,
+			errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if err = h.setPublicAccessBlock(r.Context(), bktInfo, conf); err != nil {
+		h.logAndSendError(w, "couldn't put public access block", reqInfo, err)
+	}
+}
+
+func (h *handler) DeletePublicAccessBlockHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.setPublicAccessBlock(r.Context(), bktInfo, nil); err != nil {
+		h.logAndSendError(w, "couldn't delete public access block", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) setPublicAccessBlock(ctx context.Context, bktInfo *data.BucketInfo, conf *data.PublicAccessBlockConfiguration) error {
+	settings, err := h.obj.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+
+	// settings pointer is stored in the cache, so modify a copy of the settings
+	newSettings := *settings
+	newSettings.PublicAccessBlock = conf
+
+	return h.obj.PutBucketSettings(ctx, &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &newSettings,
+	})
+}
+
+func (h *handler) getPublicAccessBlock(ctx context.Context, bktInfo *data.BucketInfo) (*data.PublicAccessBlockConfiguration, error) {
+	settings, err := h.obj.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+	if settings.PublicAccessBlock == nil {
+		return &data.PublicAccessBlockConfiguration{}, nil
+	}
+	return settings.PublicAccessBlock, nil
+}
+
+// isPublicACL checks if the ACL grants any permission to all users.
+func isPublicACL(acl *AccessControlPolicy) bool {
+	for _, grant := range acl.AccessControlList {
+		if grant.Grantee.Type == granteeGroup && grant.Grantee.URI == allUsersGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPublicACL returns AccessDenied error if the ACL is public and BlockPublicAcls is set for the bucket.
+func (h *handler) checkPublicACL(ctx context.Context, bktInfo *data.BucketInfo, acl *AccessControlPolicy) error {
+	if !isPublicACL(acl) {
+		return nil
+	}
+
+	conf, err := h.getPublicAccessBlock(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+	if conf.BlockPublicAcls {
+		return errors.GetAPIError(errors.ErrAccessDenied)
+	}
+	return nil
+}
+
+// checkPublicACLHeaders checks ACL set by request headers the same way as checkPublicACL.
+func (h *handler) checkPublicACLHeaders(r *http.Request, bktInfo *data.BucketInfo) error {
+	if !containsACLHeaders(r) {
+		return nil
+	}
+
+	key, err := h.bearerTokenIssuerKey(r.Context())
+	if err != nil {
+		return err
+	}
+	acl, err := parseACLHeaders(r.Header, key)
+	if err != nil {
+		return err
+	}
+
+	return h.checkPublicACL(r.Context(), bktInfo, acl)
+}
+
+// checkPublicPolicy returns AccessDenied error if the policy is public and BlockPublicPolicy is set for the bucket.
+func (h *handler) checkPublicPolicy(ctx context.Context, bktInfo *data.BucketInfo, bktPolicy *policy.Policy) error {
+	if !bktPolicy.IsPublic() {
+		return nil
+	}
+
+	conf, err := h.getPublicAccessBlock(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+	if conf.BlockPublicPolicy {
+		return errors.GetAPIError(errors.ErrAccessDenied)
+	}
+	return nil
+}
+
+// checkPublicAccess checks anonymous request to the bucket. RestrictPublicBuckets denies anonymous access
+// if the bucket policy is public. IgnorePublicAcls denies anonymous access unless the bucket policy
+// allows it, because otherwise the access can be granted only by public ACL.
+func (h *handler) checkPublicAccess(ctx context.Context, bktInfo *data.BucketInfo, bktPolicy *policy.Policy, decision policy.Decision) error {
+	conf, err := h.getPublicAccessBlock(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+
+	if conf.RestrictPublicBuckets && bktPolicy != nil && bktPolicy.IsPublic() {
+		return errors.GetAPIError(errors.ErrAccessDenied)
+	}
+	if conf.IgnorePublicAcls && decision != policy.Allow {
+		return errors.GetAPIError(errors.ErrAccessDenied)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicAccessBlock(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName, objName := "bucket-for-public-access-block", "object"
+
+	box, _ := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+
+	// box without bearer token makes request anonymous
+	anonBox := &accessbox.Box{Gate: &accessbox.GateData{}}
+
+	publicPolicy := &bucketPolicy{
+		Statement: []statement{{
+			Effect:    "Allow",
+			Principal: principal{AWS: allUsersWildcard},
+			Action:    []string{s3GetObject},
+			Resource:  []string{arnAwsPrefix + bktName + "/*"},
+		}},
+	}
+
+	getPublicAccessBlock(hc, bktName, http.StatusNotFound)
+
+	putPublicAccessBlock(hc, bktName, &data.PublicAccessBlockConfiguration{BlockPublicAcls: true, BlockPublicPolicy: true})
+	stored := getPublicAccessBlock(hc, bktName, http.StatusOK)
+	require.True(t, stored.BlockPublicAcls)
+	require.False(t, stored.IgnorePublicAcls)
+	require.True(t, stored.BlockPublicPolicy)
+	require.False(t, stored.RestrictPublicBuckets)
+
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	r.Header.Set(api.AmzACL, basicACLReadOnly)
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().PutBucketACLHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+	putBucketACL(t, hc, bktName, box, map[string]string{api.AmzACL: basicACLPrivate})
+
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader([]byte("content")))
+	r.Header.Set(api.AmzACL, basicACLPublic)
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	putBucketPolicy(hc, bktName, publicPolicy, box, http.StatusForbidden)
+
+	putPublicAccessBlock(hc, bktName, &data.PublicAccessBlockConfiguration{RestrictPublicBuckets: true})
+	putBucketPolicy(hc, bktName, publicPolicy, box, http.StatusOK)
+	checkBucketPolicy(hc, bktName, objName, "GetObject", anonBox, http.StatusForbidden)
+	checkBucketPolicy(hc, bktName, objName, "GetObject", box, http.StatusOK)
+
+	putPublicAccessBlock(hc, bktName, &data.PublicAccessBlockConfiguration{IgnorePublicAcls: true})
+	checkBucketPolicy(hc, bktName, objName, "GetObject", anonBox, http.StatusOK)
+	checkBucketPolicy(hc, bktName, "", "ListObjectsV2", anonBox, http.StatusForbidden)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeletePublicAccessBlockHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	getPublicAccessBlock(hc, bktName, http.StatusNotFound)
+	checkBucketPolicy(hc, bktName, "", "ListObjectsV2", anonBox, http.StatusOK)
+}
+
+func getPublicAccessBlock(hc *handlerContext, bktName string, status int) *data.PublicAccessBlockConfiguration {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetPublicAccessBlockHandler(w, r)
+	assertStatus(hc.t, w, status)
+
+	if status != http.StatusOK {
+		return nil
+	}
+
+	conf := &data.PublicAccessBlockConfiguration{}
+	readResponse(hc.t, w, status, conf)
+	return conf
+}
+
+func putPublicAccessBlock(hc *handlerContext, bktName string, conf *data.PublicAccessBlockConfiguration) {
+	w, r := prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutPublicAccessBlockHandler(w, r)
+	assertStatus(hc.t, w, http.StatusOK)
+}
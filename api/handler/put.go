@@ -24,6 +24,7 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
 	"github.com/nspcc-dev/neofs-sdk-go/session"
 	"go.uber.org/zap"
 )
@@ -34,23 +35,19 @@ type postPolicy struct {
 	empty      bool
 }
 
-func (p *postPolicy) condition(key string) *policyCondition {
-	for _, condition := range p.Conditions {
-		if condition.Key == key {
-			return condition
-		}
-	}
-	return nil
-}
+// policyContentLengthRange is a condition of the payload size, its key and value are min and max sizes.
+const policyContentLengthRange = "content-length-range"
 
 func (p *postPolicy) CheckContentLength(size int64) bool {
 	if p.empty {
 		return true
 	}
 	for _, condition := range p.Conditions {
-		if condition.Matching == "content-length-range" {
-			length := strconv.FormatInt(size, 10)
-			return condition.Key <= length && length <= condition.Value
+		if condition.Matching == policyContentLengthRange {
+			min, errMin := strconv.ParseInt(condition.Key, 10, 64)
+			max, errMax := strconv.ParseInt(condition.Value, 10, 64)
+			condition.Matched = errMin == nil && errMax == nil && min <= size && size <= max
+			return condition.Matched
 		}
 	}
 	return true
@@ -75,16 +72,23 @@ func (p *policyCondition) match(value string) bool {
 	return p.Matched
 }
 
+// CheckField checks the form field by all conditions of the field, the field without conditions isn't allowed.
 func (p *postPolicy) CheckField(key string, value string) error {
 	if p.empty {
 		return nil
 	}
-	cond := p.condition(key)
-	if cond == nil {
-		return errors.GetAPIError(errors.ErrPostPolicyConditionInvalidFormat)
-	}
 
-	if !cond.match(value) {
+	var found bool
+	for _, cond := range p.Conditions {
+		if cond.Key != key || cond.Matching == policyContentLengthRange {
+			continue
+		}
+		found = true
+		if !cond.match(value) {
+			return errors.GetAPIError(errors.ErrPostPolicyConditionInvalidFormat)
+		}
+	}
+	if !found {
 		return errors.GetAPIError(errors.ErrPostPolicyConditionInvalidFormat)
 	}
 
@@ -128,14 +132,14 @@ func (p *policyCondition) UnmarshalJSON(data []byte) error {
 			return errInvalidCondition
 		}
 
-		if p.Matching == "content-length-range" {
+		if p.Matching == policyContentLengthRange {
 			min, ok := v[1].(float64)
 			max, ok2 := v[2].(float64)
 			if !ok || !ok2 {
 				return errInvalidCondition
 			}
-			p.Key = strconv.FormatFloat(min, 'f', 0, 32)
-			p.Value = strconv.FormatFloat(max, 'f', 0, 32)
+			p.Key = strconv.FormatFloat(min, 'f', 0, 64)
+			p.Value = strconv.FormatFloat(max, 'f', 0, 64)
 		} else {
 			key, ok2 := v[1].(string)
 			p.Value, ok = v[2].(string)
@@ -201,7 +205,12 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metadata := parseMetadata(r)
+	if err = h.checkPublicACLHeaders(r, bktInfo); err != nil {
+		h.logAndSendError(w, "public object acl is blocked", reqInfo, err)
+		return
+	}
+
+	metadata := h.parseMetadata(r)
 	if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
 		metadata[api.ContentType] = contentType
 	}
@@ -212,9 +221,9 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		metadata[api.Expires] = expires
 	}
 
-	copiesNumber, err := getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	copiesNumber, err := h.formStorageClass(r.Header, metadata)
 	if err != nil {
-		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		h.logAndSendError(w, "invalid storage class", reqInfo, err)
 		return
 	}
 
@@ -227,25 +236,52 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 	params := &layer.PutObjectParams{
 		BktInfo:      bktInfo,
 		Object:       reqInfo.ObjectName,
-		Reader:       r.Body,
-		Size:         r.ContentLength,
 		Header:       metadata,
 		Encryption:   encryptionParams,
 		CopiesNumber: copiesNumber,
 	}
 
+	payload, err := formPayload(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid payload headers", reqInfo, err)
+		return
+	}
+	params.Reader, params.Size = payload.reader, payload.size
+	params.ChecksumAlgorithm, params.Checksum, params.TrailingChecksum = payload.checksumAlgorithm, payload.checksum, payload.trailingChecksum
+	if params.ContentMD5, err = formContentMD5(r.Header); err != nil {
+		h.logAndSendError(w, "invalid content md5", reqInfo, err)
+		return
+	}
+
 	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
 	if err != nil {
 		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
 		return
 	}
 
+	if err = checkACLHeadersAllowed(settings, r); err != nil {
+		h.logAndSendError(w, "acl is disabled in the bucket", reqInfo, err)
+		return
+	}
+	containsACL = containsACL && !aclDisabled(settings)
+	params.BucketOwnerFullControl = isBucketOwnerFullControl(r)
+
 	params.Lock, err = formObjectLock(r.Context(), bktInfo, settings.LockConfiguration, r.Header)
 	if err != nil {
 		h.logAndSendError(w, "could not form object lock", reqInfo, err)
 		return
 	}
 
+	if err = checkUploadValidation(settings, reqInfo.ObjectName, metadata); err != nil {
+		h.logAndSendError(w, "object violates upload validation rules", reqInfo, err)
+		return
+	}
+
+	if err = h.checkWritePreconditions(r, bktInfo, reqInfo.ObjectName); err != nil {
+		h.logAndSendError(w, "precondition failed", reqInfo, err)
+		return
+	}
+
 	extendedObjInfo, err := h.obj.PutObject(r.Context(), params)
 	if err != nil {
 		_, err2 := io.Copy(io.Discard, r.Body)
@@ -304,14 +340,31 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 	if settings.VersioningEnabled() {
 		w.Header().Set(api.AmzVersionID, objInfo.VersionID())
 	}
-	if encryptionParams.Enabled() {
-		addSSECHeaders(w.Header(), r.Header)
+	addEncryptionHeaders(w.Header(), r.Header, encryptionParams)
+	if checksum := objInfo.Headers[layer.AttributeChecksum]; checksum != "" {
+		w.Header().Set(api.AmzChecksumPrefix+objInfo.Headers[layer.AttributeChecksumAlgorithm], checksum)
 	}
+	writeObjectLockHeaders(w.Header(), params.Lock)
+	h.setExpirationHeader(r.Context(), w.Header(), bktInfo, objInfo, tagSet)
 
 	w.Header().Set(api.ETag, objInfo.HashSum)
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
+// formContentMD5 returns the decoded Content-MD5 header, nil is returned if the header isn't set.
+func formContentMD5(header http.Header) ([]byte, error) {
+	value := header.Get(api.ContentMD5)
+	if value == "" {
+		return nil, nil
+	}
+
+	sum, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(sum) != md5.Size {
+		return nil, errors.GetAPIError(errors.ErrInvalidDigest)
+	}
+	return sum, nil
+}
+
 func getCopiesNumberOrDefault(metadata map[string]string, defaultCopiesNumber uint32) (uint32, error) {
 	copiesNumberStr, ok := metadata[layer.AttributeNeofsCopiesNumber]
 	if !ok {
@@ -326,10 +379,65 @@ func getCopiesNumberOrDefault(metadata map[string]string, defaultCopiesNumber ui
 	return uint32(copiesNumber), nil
 }
 
+// formStorageClass adds the storage class of x-amz-storage-class header to the metadata and returns
+// the number of object copies of the class. Copies number from the metadata overrides the class one.
+func (h *handler) formStorageClass(header http.Header, metadata map[string]string) (uint32, error) {
+	class := header.Get(api.AmzStorageClass)
+	if class == "" || class == data.StorageClassStandard {
+		return getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	}
+
+	copiesNumber, ok := h.cfg.StorageClasses[class]
+	if !ok {
+		return 0, errors.GetAPIError(errors.ErrInvalidStorageClass)
+	}
+	metadata[data.AttributeStorageClass] = class
+
+	return getCopiesNumberOrDefault(metadata, copiesNumber)
+}
+
+// formEncryptionParams returns encryption params of the request: the customer key (SSE-C)
+// or params to encrypt with the key managed by the gateway (SSE-S3).
 func formEncryptionParams(r *http.Request) (enc encryption.Params, err error) {
-	sseCustomerAlgorithm := r.Header.Get(api.AmzServerSideEncryptionCustomerAlgorithm)
-	sseCustomerKey := r.Header.Get(api.AmzServerSideEncryptionCustomerKey)
-	sseCustomerKeyMD5 := r.Header.Get(api.AmzServerSideEncryptionCustomerKeyMD5)
+	sse := r.Header.Get(api.AmzServerSideEncryption)
+	kmsKeyID := r.Header.Get(api.AmzServerSideEncryptionAwsKmsKeyID)
+	if len(kmsKeyID) > 0 && sse != layer.KMSEncryptionMethod {
+		return enc, errors.GetAPIError(errors.ErrInvalidEncryptionParameters)
+	}
+
+	if len(sse) > 0 {
+		if len(r.Header.Get(api.AmzServerSideEncryptionCustomerAlgorithm)) > 0 {
+			return enc, errors.GetAPIError(errors.ErrInvalidEncryptionParameters)
+		}
+
+		switch sse {
+		case layer.AESEncryptionAlgorithm:
+			return encryption.NewManagedParams(), nil
+		case layer.KMSEncryptionMethod:
+			return encryption.NewKMSParams(kmsKeyID), nil
+		default:
+			return enc, errors.GetAPIError(errors.ErrInvalidEncryptionMethod)
+		}
+	}
+
+	return formEncryptionParamsFromHeaders(r,
+		api.AmzServerSideEncryptionCustomerAlgorithm,
+		api.AmzServerSideEncryptionCustomerKey,
+		api.AmzServerSideEncryptionCustomerKeyMD5)
+}
+
+// formCopySourceEncryptionParams returns the customer key of the encrypted source object of copy requests.
+func formCopySourceEncryptionParams(r *http.Request) (enc encryption.Params, err error) {
+	return formEncryptionParamsFromHeaders(r,
+		api.AmzCopySourceServerSideEncryptionCustomerAlgorithm,
+		api.AmzCopySourceServerSideEncryptionCustomerKey,
+		api.AmzCopySourceServerSideEncryptionCustomerKeyMD5)
+}
+
+func formEncryptionParamsFromHeaders(r *http.Request, algorithmHeader, keyHeader, keyMD5Header string) (enc encryption.Params, err error) {
+	sseCustomerAlgorithm := r.Header.Get(algorithmHeader)
+	sseCustomerKey := r.Header.Get(keyHeader)
+	sseCustomerKeyMD5 := r.Header.Get(keyMD5Header)
 
 	if len(sseCustomerAlgorithm) == 0 && len(sseCustomerKey) == 0 && len(sseCustomerKeyMD5) == 0 {
 		return
@@ -380,7 +488,7 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		containsACL      = containsACLHeaders(r)
 	)
 
-	policy, err := checkPostPolicy(r, reqInfo, metadata)
+	policy, err := h.checkPostPolicy(r, reqInfo, metadata)
 	if err != nil {
 		h.logAndSendError(w, "failed check policy", reqInfo, err)
 		return
@@ -421,6 +529,14 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		h.logAndSendError(w, "invalid content-length", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
 		return
 	}
+	if !policy.AllConditionMatched() {
+		h.logAndSendError(w, "policy conditions aren't matched", reqInfo, errors.GetAPIError(errors.ErrPostPolicyConditionInvalidFormat))
+		return
+	}
+	if err = api.CheckObjectName(reqInfo.ObjectName); err != nil {
+		h.logAndSendError(w, "invalid object name", reqInfo, err)
+		return
+	}
 
 	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
 	if err != nil {
@@ -428,12 +544,36 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.checkPublicACLHeaders(r, bktInfo); err != nil {
+		h.logAndSendError(w, "public object acl is blocked", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
+	acl := auth.MultipartFormValue(r, "acl")
+	if aclDisabled(settings) && acl != "" && acl != layer.CannedACLBucketOwnerFullControl {
+		h.logAndSendError(w, "acl is disabled in the bucket", reqInfo, errors.GetAPIError(errors.ErrAccessControlListNotSupported))
+		return
+	}
+
+	if err = checkUploadValidation(settings, reqInfo.ObjectName, metadata); err != nil {
+		h.logAndSendError(w, "object violates upload validation rules", reqInfo, err)
+		return
+	}
+
 	params := &layer.PutObjectParams{
 		BktInfo: bktInfo,
 		Object:  reqInfo.ObjectName,
 		Reader:  contentReader,
 		Size:    size,
 		Header:  metadata,
+
+		BucketOwnerFullControl: acl == layer.CannedACLBucketOwnerFullControl,
 	}
 
 	extendedObjInfo, err := h.obj.PutObject(r.Context(), params)
@@ -453,7 +593,7 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		h.log.Error("couldn't send notification: %w", zap.Error(err))
 	}
 
-	if acl := auth.MultipartFormValue(r, "acl"); acl != "" {
+	if acl != "" && !aclDisabled(settings) {
 		r.Header.Set(api.AmzACL, acl)
 		r.Header.Set(api.AmzGrantFullControl, "")
 		r.Header.Set(api.AmzGrantWrite, "")
@@ -494,14 +634,17 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo); err != nil {
-		h.log.Warn("couldn't get bucket versioning", zap.String("bucket name", reqInfo.BucketName), zap.Error(err))
-	} else if settings.VersioningEnabled() {
+	if settings.VersioningEnabled() {
 		w.Header().Set(api.AmzVersionID, objInfo.VersionID())
 	}
 
-	if redirectURL := auth.MultipartFormValue(r, "success_action_redirect"); redirectURL != "" {
-		http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+	redirect := auth.MultipartFormValue(r, "success_action_redirect")
+	if redirect == "" {
+		// the deprecated form field is still sent by old browser forms
+		redirect = auth.MultipartFormValue(r, "redirect")
+	}
+	if location, ok := postRedirectLocation(redirect, objInfo); ok {
+		http.Redirect(w, r, location, http.StatusSeeOther)
 		return
 	}
 	status := http.StatusNoContent
@@ -512,9 +655,10 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		case "201":
 			status = http.StatusCreated
 			resp := &PostResponse{
-				Bucket: objInfo.Bucket,
-				Key:    objInfo.Name,
-				ETag:   objInfo.HashSum,
+				Location: postObjectLocation(r, objInfo.Name),
+				Bucket:   objInfo.Bucket,
+				Key:      objInfo.Name,
+				ETag:     objInfo.HashSum,
 			}
 			w.WriteHeader(status)
 			if _, err = w.Write(api.EncodeResponse(resp)); err != nil {
@@ -528,7 +672,7 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(status)
 }
 
-func checkPostPolicy(r *http.Request, reqInfo *api.ReqInfo, metadata map[string]string) (*postPolicy, error) {
+func (h *handler) checkPostPolicy(r *http.Request, reqInfo *api.ReqInfo, metadata map[string]string) (*postPolicy, error) {
 	policy := &postPolicy{empty: true}
 	if policyStr := auth.MultipartFormValue(r, "policy"); policyStr != "" {
 		policyData, err := base64.StdEncoding.DecodeString(policyStr)
@@ -539,7 +683,7 @@ func checkPostPolicy(r *http.Request, reqInfo *api.ReqInfo, metadata map[string]
 			return nil, fmt.Errorf("could not unmarshal policy: %w", err)
 		}
 		if policy.Expiration.Before(time.Now()) {
-			return nil, fmt.Errorf("policy is expired: %w", errors.GetAPIError(errors.ErrInvalidArgument))
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("policy is expired"))
 		}
 		policy.empty = false
 	}
@@ -550,37 +694,91 @@ func checkPostPolicy(r *http.Request, reqInfo *api.ReqInfo, metadata map[string]
 			continue
 		}
 		if err := policy.CheckField(key, value); err != nil {
-			return nil, fmt.Errorf("'%s' form field doesn't match the policy: %w", key, err)
+			return nil, errors.GetAPIErrorWithError(errors.ErrPostPolicyConditionInvalidFormat,
+				fmt.Errorf("'%s' form field doesn't match the policy", key))
 		}
 
 		prefix := strings.ToLower(api.MetadataPrefix)
 		if strings.HasPrefix(key, prefix) {
-			metadata[strings.TrimPrefix(key, prefix)] = value
+			metadata[h.metadataKey(strings.TrimPrefix(key, prefix))] = value
 		}
 
-		if key == "content-type" {
+		switch key {
+		case "content-type":
 			metadata[api.ContentType] = value
-		}
-
-		if key == "key" {
+		case "cache-control":
+			metadata[api.CacheControl] = value
+		case "expires":
+			metadata[api.Expires] = value
+		case "key":
 			reqInfo.ObjectName = value
 		}
 	}
 
 	for _, cond := range policy.Conditions {
-		if cond.Key == "bucket" {
+		switch {
+		case cond.Key == "bucket":
 			if !cond.match(reqInfo.BucketName) {
 				return nil, errors.GetAPIError(errors.ErrPostPolicyConditionInvalidFormat)
 			}
+		case cond.Matching != policyContentLengthRange && !cond.Matched:
+			// the field of the condition is absent in the form, so it's empty
+			if !cond.match("") {
+				return nil, errors.GetAPIErrorWithError(errors.ErrPostPolicyConditionInvalidFormat,
+					fmt.Errorf("'%s' form field required by the policy is missing", cond.Key))
+			}
 		}
 	}
 
 	return policy, nil
 }
 
+// postRedirectLocation returns the location of success_action_redirect with bucket, key and etag of the
+// uploaded object in the query. Invalid redirect URLs are ignored.
+func postRedirectLocation(redirect string, objInfo *data.ObjectInfo) (string, bool) {
+	if redirect == "" {
+		return "", false
+	}
+
+	u, err := url.Parse(redirect)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false
+	}
+
+	query := u.Query()
+	query.Set("bucket", objInfo.Bucket)
+	query.Set("key", objInfo.Name)
+	query.Set("etag", `"`+objInfo.HashSum+`"`)
+	u.RawQuery = query.Encode()
+
+	return u.String(), true
+}
+
+// postObjectLocation returns the URL of the object uploaded by the POST request.
+func postObjectLocation(r *http.Request, objName string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	// the request path is the bucket for path-style requests and the root for virtual-hosted-style ones
+	u := url.URL{
+		Scheme: scheme,
+		Host:   r.Host,
+		Path:   strings.TrimSuffix(r.URL.Path, "/") + "/" + objName,
+	}
+
+	return u.String()
+}
+
 func containsACLHeaders(r *http.Request) bool {
-	return r.Header.Get(api.AmzACL) != "" || r.Header.Get(api.AmzGrantRead) != "" ||
-		r.Header.Get(api.AmzGrantFullControl) != "" || r.Header.Get(api.AmzGrantWrite) != ""
+	return r.Header.Get(api.AmzACL) != "" || containsGrantHeaders(r)
+}
+
+func containsGrantHeaders(r *http.Request) bool {
+	return r.Header.Get(api.AmzGrantRead) != "" ||
+		r.Header.Get(api.AmzGrantFullControl) != "" || r.Header.Get(api.AmzGrantWrite) != "" ||
+		r.Header.Get(api.AmzGrantReadACP) != "" || r.Header.Get(api.AmzGrantWriteACP) != ""
 }
 
 func (h *handler) getNewEAclTable(r *http.Request, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) (*eacl.Table, error) {
@@ -609,6 +807,9 @@ func (h *handler) getNewEAclTable(r *http.Request, bktInfo *data.BucketInfo, obj
 	if err != nil {
 		return nil, fmt.Errorf("could not translate policy to ast: %w", err)
 	}
+	if acpResource := aclToACPResource(objectACL, resInfo); acpResource != nil {
+		astChild.Resources = append(astChild.Resources, acpResource)
+	}
 
 	bacl, err := h.obj.GetBucketACL(r.Context(), bktInfo)
 	if err != nil {
@@ -633,39 +834,97 @@ func (h *handler) getNewEAclTable(r *http.Request, bktInfo *data.BucketInfo, obj
 	return newEaclTable, nil
 }
 
+// parseTaggingHeader returns the tag set from URL-encoded x-amz-tagging header, nil is returned
+// if the header isn't set.
 func parseTaggingHeader(header http.Header) (map[string]string, error) {
-	var tagSet map[string]string
-	if tagging := header.Get(api.AmzTagging); len(tagging) > 0 {
-		queries, err := url.ParseQuery(tagging)
-		if err != nil {
-			return nil, errors.GetAPIError(errors.ErrInvalidArgument)
-		}
-		if len(queries) > maxTags {
-			return nil, errors.GetAPIError(errors.ErrInvalidTagsSizeExceed)
-		}
-		tagSet = make(map[string]string, len(queries))
-		for k, v := range queries {
-			tag := Tag{Key: k, Value: v[0]}
-			if err = checkTag(tag); err != nil {
-				return nil, err
-			}
-			tagSet[tag.Key] = tag.Value
+	tagging := header.Get(api.AmzTagging)
+	if len(tagging) == 0 {
+		return nil, nil
+	}
+
+	queries, err := url.ParseQuery(tagging)
+	if err != nil {
+		return nil, errors.GetAPIError(errors.ErrInvalidArgument)
+	}
+
+	tags := make([]Tag, 0, len(queries))
+	for k, values := range queries {
+		for _, v := range values {
+			tags = append(tags, Tag{Key: k, Value: v})
 		}
 	}
+	if err = checkTagSet(tags); err != nil {
+		return nil, err
+	}
+
+	tagSet := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tagSet[tag.Key] = tag.Value
+	}
 	return tagSet, nil
 }
 
-func parseMetadata(r *http.Request) map[string]string {
+// checkUploadValidation returns InvalidArgument error describing the violated rule if the new object
+// with the key and the metadata doesn't pass upload validation rules of the bucket.
+func checkUploadValidation(settings *data.BucketSettings, key string, metadata map[string]string) error {
+	if settings.UploadValidation == nil {
+		return nil
+	}
+
+	userMetadata := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		if k != api.ContentType && k != api.CacheControl && k != api.Expires {
+			userMetadata[k] = v
+		}
+	}
+
+	if err := settings.UploadValidation.Check(key, metadata[api.ContentType], userMetadata); err != nil {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, err)
+	}
+	return nil
+}
+
+func (h *handler) parseMetadata(r *http.Request) map[string]string {
 	res := make(map[string]string)
 	for k, v := range r.Header {
 		if strings.HasPrefix(k, api.MetadataPrefix) {
-			key := strings.ToLower(strings.TrimPrefix(k, api.MetadataPrefix))
-			res[key] = v[0]
+			res[h.metadataKey(strings.TrimPrefix(k, api.MetadataPrefix))] = v[0]
 		}
 	}
 	return res
 }
 
+// reservedMetadataKeys are attributes of the gateway and NeoFS which user metadata keys must not match.
+var reservedMetadataKeys = []string{
+	api.Date, api.CacheControl, api.ContentDisposition, api.ContentLength, api.ContentType,
+	api.LastModified, api.ETag, api.Expires, layer.AttributeNeofsCopiesNumber,
+	object.AttributeName, object.AttributeFilePath, object.AttributeFileName, object.AttributeTimestamp,
+}
+
+// reservedMetadataPrefixes are lowercased prefixes of system attributes of the gateway and NeoFS.
+var reservedMetadataPrefixes = []string{strings.ToLower(api.NeoFSSystemMetadataPrefix), "__neofs__"}
+
+// metadataKey returns the key of user metadata as it's stored in object attributes. Keys are lowercased
+// as AWS S3 does unless the case is preserved by the config. Keys matching reserved attributes in any case
+// are lowercased anyway, so they never override attributes of the gateway.
+func (h *handler) metadataKey(key string) string {
+	lower := strings.ToLower(key)
+	if !h.cfg.PreserveMetadataCase {
+		return lower
+	}
+	for _, prefix := range reservedMetadataPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return lower
+		}
+	}
+	for _, reserved := range reservedMetadataKeys {
+		if strings.EqualFold(key, reserved) {
+			return lower
+		}
+	}
+	return key
+}
+
 func (h *handler) CreateBucketHandler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
 	p := &layer.CreateBucketParams{
@@ -695,6 +954,11 @@ func (h *handler) CreateBucketHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = addACPRecords(p.EACL, bktACL, &resourceInfo{Bucket: reqInfo.BucketName}); err != nil {
+		h.logAndSendError(w, "could translate bucket acp to eacl", reqInfo, err)
+		return
+	}
+
 	createParams, err := parseLocationConstraint(r)
 	if err != nil {
 		h.logAndSendError(w, "could not parse body", reqInfo, err)
@@ -747,8 +1011,11 @@ func (h *handler) CreateBucketHandler(w http.ResponseWriter, r *http.Request) {
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
+// setPolicy chooses the placement policy of the bucket by its location constraint. The location constraint is
+// stored in the bucket even if there is no policy for it, so GetBucketLocation returns the value set by the client.
 func (h handler) setPolicy(prm *layer.CreateBucketParams, locationConstraint string, userPolicies []*accessbox.ContainerPolicy) {
 	prm.Policy = h.cfg.Policy.Default()
+	prm.LocationConstraint = locationConstraint
 
 	if locationConstraint == "" {
 		return
@@ -756,13 +1023,11 @@ func (h handler) setPolicy(prm *layer.CreateBucketParams, locationConstraint str
 
 	if policy, ok := h.cfg.Policy.Get(locationConstraint); ok {
 		prm.Policy = policy
-		prm.LocationConstraint = locationConstraint
 	}
 
 	for _, placementPolicy := range userPolicies {
 		if placementPolicy.LocationConstraint == locationConstraint {
 			prm.Policy = placementPolicy.Policy
-			prm.LocationConstraint = locationConstraint
 			return
 		}
 	}
@@ -814,8 +1079,33 @@ func parseLocationConstraint(r *http.Request) (*createBucketParams, error) {
 	}
 
 	params := new(createBucketParams)
-	if err := xml.NewDecoder(r.Body).Decode(params); err != nil {
+	if err := api.NewXMLDecoder(r.Body).Decode(params); err != nil {
 		return nil, errors.GetAPIError(errors.ErrMalformedXML)
 	}
 	return params, nil
 }
+
+// checkWritePreconditions checks conditional write headers against the current object. It returns
+// PreconditionFailed error if 'If-None-Match: *' header is set and the object already exists, so the object
+// is written only if it is absent, or if ETag of the object doesn't match 'If-Match' header. NoSuchKey error
+// is returned if 'If-Match' header is set and the object doesn't exist.
+// The check isn't atomic with the following write, so conditions of concurrent writes are best-effort.
+func (h *handler) checkWritePreconditions(r *http.Request, bktInfo *data.BucketInfo, object string) error {
+	ifMatch, ifNoneMatch := r.Header.Get(api.IfMatch), r.Header.Get(api.IfNoneMatch)
+	if len(ifMatch) == 0 && ifNoneMatch != "*" {
+		return nil
+	}
+
+	objInfo, err := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: object})
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchKey) && len(ifMatch) == 0 {
+			return nil
+		}
+		return err
+	}
+
+	if ifNoneMatch == "*" || len(ifMatch) > 0 && !etagMatches(ifMatch, objInfo.HashSum) {
+		return errors.GetAPIError(errors.ErrPreconditionFailed)
+	}
+	return nil
+}
@@ -1,14 +1,24 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/xml"
+	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/stretchr/testify/require"
 )
@@ -102,11 +112,100 @@ func TestEmptyPostPolicy(t *testing.T) {
 	}
 	reqInfo := &api.ReqInfo{}
 	metadata := make(map[string]string)
+	h := &handler{cfg: &Config{}}
 
-	_, err := checkPostPolicy(r, reqInfo, metadata)
+	_, err := h.checkPostPolicy(r, reqInfo, metadata)
 	require.NoError(t, err)
 }
 
+func TestPostObject(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-post"
+	createTestBucket(hc, bktName)
+
+	policy := map[string]interface{}{
+		"expiration": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		"conditions": []interface{}{
+			map[string]string{"bucket": bktName},
+			[]interface{}{"starts-with", "$key", "user/"},
+			[]interface{}{"content-length-range", 1, 10},
+			[]interface{}{"starts-with", "$success_action_redirect", ""},
+			[]interface{}{"eq", "$success_action_status", "201"},
+			map[string]string{"x-amz-meta-owner": "alice"},
+		},
+	}
+	fields := map[string]string{
+		"key":                   "user/${filename}",
+		"success_action_status": "201",
+		"x-amz-meta-owner":      "alice",
+	}
+
+	w, r := preparePostObjectRequest(hc, bktName, policy, fields, "content")
+	hc.Handler().PostObject(w, r)
+	resp := &PostResponse{}
+	readResponse(t, w, http.StatusCreated, resp)
+	require.Equal(t, "user/file.txt", resp.Key)
+	require.Equal(t, "http://localhost/"+bktName+"/user/file.txt", resp.Location)
+
+	w, r = prepareTestRequest(hc, bktName, "user/file.txt", nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, []string{"alice"}, w.Header()[api.MetadataPrefix+"owner"])
+
+	w, r = preparePostObjectRequest(hc, bktName, policy, fields, "too large content")
+	hc.Handler().PostObject(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	delete(fields, "success_action_status")
+	w, r = preparePostObjectRequest(hc, bktName, policy, fields, "content")
+	hc.Handler().PostObject(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+
+	fields["x-amz-meta-color"] = "red"
+	w, r = preparePostObjectRequest(hc, bktName, policy, fields, "content")
+	hc.Handler().PostObject(w, r)
+	assertStatus(t, w, http.StatusForbidden)
+	delete(fields, "x-amz-meta-color")
+
+	fields["success_action_status"] = "201"
+	fields["success_action_redirect"] = "https://example.com/uploaded?from=form"
+	w, r = preparePostObjectRequest(hc, bktName, policy, fields, "content")
+	hc.Handler().PostObject(w, r)
+	assertStatus(t, w, http.StatusSeeOther)
+	location := w.Header().Get("Location")
+	require.True(t, strings.HasPrefix(location, "https://example.com/uploaded?"))
+	require.Contains(t, location, "bucket="+bktName)
+	require.Contains(t, location, "key=user%2Ffile.txt")
+	require.Contains(t, location, "from=form")
+}
+
+func preparePostObjectRequest(hc *handlerContext, bktName string, policy interface{}, fields map[string]string, content string) (*httptest.ResponseRecorder, *http.Request) {
+	rawPolicy, err := json.Marshal(policy)
+	require.NoError(hc.t, err)
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	require.NoError(hc.t, writer.WriteField("policy", base64.StdEncoding.EncodeToString(rawPolicy)))
+	for key, val := range fields {
+		require.NoError(hc.t, writer.WriteField(key, val))
+	}
+	file, err := writer.CreateFormFile("file", "file.txt")
+	require.NoError(hc.t, err)
+	_, err = file.Write([]byte(content))
+	require.NoError(hc.t, err)
+	require.NoError(hc.t, writer.Close())
+
+	w, r := prepareTestPayloadRequest(hc, bktName, "", body)
+	r.Method = http.MethodPost
+	r.URL.Path = "/" + bktName
+	r.Header.Set(api.ContentType, writer.FormDataContentType())
+	// the form is parsed by the authentication
+	require.NoError(hc.t, r.ParseMultipartForm(1<<20))
+
+	return w, r
+}
+
 func TestPutObjectOverrideCopiesNumber(t *testing.T) {
 	tc := prepareHandlerContext(t)
 
@@ -126,3 +225,262 @@ func TestPutObjectOverrideCopiesNumber(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "1", objInfo.Headers[layer.AttributeNeofsCopiesNumber])
 }
+
+func TestPutObjectStorageClass(t *testing.T) {
+	tc := prepareHandlerContext(t)
+	tc.Handler().cfg.StorageClasses = map[string]uint32{"STANDARD_IA": 1}
+
+	bktName, objName := "bucket-for-storage-class", "object-for-storage-class"
+	createTestBucket(tc, bktName)
+
+	w, r := prepareTestRequest(tc, bktName, objName, nil)
+	r.Header.Set(api.AmzStorageClass, "GLACIER")
+	tc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidStorageClass))
+
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	r.Header.Set(api.AmzStorageClass, "STANDARD_IA")
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "STANDARD_IA", w.Header().Get(api.AmzStorageClass))
+	require.Empty(t, w.Header().Get(api.MetadataPrefix+data.AttributeStorageClass))
+
+	list := listObjectsV2(t, tc, bktName, "", "", "", "", -1)
+	require.Len(t, list.Contents, 1)
+	require.Equal(t, "STANDARD_IA", list.Contents[0].StorageClass)
+
+	// the copy gets STANDARD class unless the class is specified
+	copyObject(t, tc, bktName, objName, objName+"-copy", CopyMeta{}, http.StatusOK)
+	w, r = prepareTestRequest(tc, bktName, objName+"-copy", nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header().Get(api.AmzStorageClass))
+
+	// changing the class allows copying to itself
+	w, r = prepareTestRequest(tc, bktName, objName+"-copy", nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName+"-copy")
+	r.Header.Set(api.AmzStorageClass, "STANDARD_IA")
+	tc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	w, r = prepareTestRequest(tc, bktName, objName+"-copy", nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	require.Equal(t, "STANDARD_IA", w.Header().Get(api.AmzStorageClass))
+}
+
+func TestPutObjectIfNoneMatch(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-if-none-match", "object-for-if-none-match"
+	createTestBucket(tc, bktName)
+
+	w, r := prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.IfNoneMatch, "*")
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.IfNoneMatch, "*")
+	tc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrPreconditionFailed))
+}
+
+func TestPutObjectIfMatch(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-if-match", "object-for-if-match"
+	createTestBucket(tc, bktName)
+
+	w, r := prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.IfMatch, "\"etag\"")
+	tc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchKey))
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	etag := w.Header().Get(api.ETag)
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("updated"))
+	r.Header.Set(api.IfMatch, "\"etag\"")
+	tc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrPreconditionFailed))
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("updated"))
+	r.Header.Set(api.IfMatch, etag)
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.NotEqual(t, etag, w.Header().Get(api.ETag))
+
+	// the object is replaced, so the previous ETag doesn't match anymore
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.IfMatch, etag)
+	tc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrPreconditionFailed))
+}
+
+func TestPutObjectContentMD5(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-content-md5", "object-for-content-md5"
+	createTestBucket(hc, bktName)
+
+	content := "content"
+	sum := md5.Sum([]byte(content))
+	otherSum := md5.Sum([]byte("other"))
+
+	for _, tc := range []struct {
+		contentMD5 string
+		err        errors.ErrorCode
+	}{
+		{contentMD5: "invalid", err: errors.ErrInvalidDigest},
+		{contentMD5: base64.StdEncoding.EncodeToString(sum[:4]), err: errors.ErrInvalidDigest},
+		{contentMD5: base64.StdEncoding.EncodeToString(otherSum[:]), err: errors.ErrBadDigest},
+	} {
+		w, r := prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader(content))
+		r.Header.Set(api.ContentMD5, tc.contentMD5)
+		hc.Handler().PutObjectHandler(w, r)
+		assertS3Error(t, w, errors.GetAPIError(tc.err))
+	}
+	checkNotFound(t, hc, bktName, objName, emptyVersion)
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader(content))
+	r.Header.Set(api.ContentMD5, base64.StdEncoding.EncodeToString(sum[:]))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	checkFound(t, hc, bktName, objName, emptyVersion)
+}
+
+func TestPutObjectUploadValidation(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-upload-validation"
+	bktInfo := createTestBucket(hc, bktName)
+
+	rules := &data.UploadValidation{
+		ContentTypes:     []string{"image/*", "application/pdf"},
+		MaxKeyLength:     20,
+		FilenamePattern:  `^[a-z0-9.]+$`,
+		RequiredMetadata: []string{"Author"},
+	}
+	require.NoError(t, rules.Validate())
+
+	settings, err := hc.Layer().GetBucketSettings(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	newSettings := *settings
+	newSettings.UploadValidation = rules
+	err = hc.Layer().PutBucketSettings(hc.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		key     string
+		headers map[string]string
+		status  int
+	}{
+		{name: "valid", key: "dir/photo.png", headers: map[string]string{api.ContentType: "image/png; q=1", api.MetadataPrefix + "Author": "a"}, status: http.StatusOK},
+		{name: "pdf", key: "doc.pdf", headers: map[string]string{api.ContentType: "application/pdf", api.MetadataPrefix + "author": "a"}, status: http.StatusOK},
+		{name: "content type", key: "doc.txt", headers: map[string]string{api.ContentType: "text/plain", api.MetadataPrefix + "Author": "a"}, status: http.StatusBadRequest},
+		{name: "no content type", key: "doc.txt", headers: map[string]string{api.MetadataPrefix + "Author": "a"}, status: http.StatusBadRequest},
+		{name: "key length", key: "dir/long-long-photo.png", headers: map[string]string{api.ContentType: "image/png", api.MetadataPrefix + "Author": "a"}, status: http.StatusBadRequest},
+		{name: "filename", key: "Photo.png", headers: map[string]string{api.ContentType: "image/png", api.MetadataPrefix + "Author": "a"}, status: http.StatusBadRequest},
+		{name: "metadata", key: "photo.png", headers: map[string]string{api.ContentType: "image/png"}, status: http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, r := prepareTestPayloadRequest(hc, bktName, tc.key, strings.NewReader("content"))
+			setHeaders(r, tc.headers)
+			hc.Handler().PutObjectHandler(w, r)
+			assertStatus(t, w, tc.status)
+
+			w, r = prepareTestRequest(hc, bktName, tc.key, nil)
+			setHeaders(r, tc.headers)
+			hc.Handler().CreateMultipartUploadHandler(w, r)
+			assertStatus(t, w, tc.status)
+		})
+	}
+
+	require.Error(t, (&data.UploadValidation{FilenamePattern: "("}).Validate())
+	require.Error(t, (&data.UploadValidation{ContentTypes: []string{"image"}}).Validate())
+}
+
+func TestPutObjectTrailingChecksum(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-trailing-checksum", "object-for-trailing-checksum"
+	createTestBucket(hc, bktName)
+
+	content := "hello world"
+	checksum := "yZRlqg=="
+	chunked := func(checksum string) io.Reader {
+		return strings.NewReader("b\r\n" + content + "\r\n0\r\nx-amz-checksum-crc32c:" + checksum + "\r\n\r\n")
+	}
+	streaming := map[string]string{
+		api.AmzContentSha256:        "STREAMING-UNSIGNED-PAYLOAD-TRAILER",
+		api.AmzDecodedContentLength: strconv.Itoa(len(content)),
+		api.AmzTrailer:              "x-amz-checksum-crc32c",
+	}
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, chunked("DUoRhQ=="))
+	setHeaders(r, streaming)
+	hc.Handler().PutObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrBadDigest))
+	checkNotFound(t, hc, bktName, objName, emptyVersion)
+
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, chunked(checksum))
+	setHeaders(r, streaming)
+	r.Header.Set(api.AmzChecksumPrefix+layer.ChecksumCRC32, "DUoRhQ==")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, chunked(checksum))
+	setHeaders(r, streaming)
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, checksum, w.Header().Get(api.AmzChecksumPrefix+layer.ChecksumCRC32C))
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	r.Header.Set(api.AmzChecksumMode, checksumModeEnabled)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, content, w.Body.String())
+	require.Equal(t, checksum, w.Header().Get(api.AmzChecksumPrefix+layer.ChecksumCRC32C))
+}
+
+func TestCreateBucketLocationConstraint(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	box, _ := createAccessBox(t)
+
+	for _, tc := range []struct {
+		bucket   string
+		location string
+		expected string
+	}{
+		{bucket: "bucket-eu", location: "eu-west-1", expected: "eu-west-1"},
+		{bucket: "bucket-default", expected: api.DefaultLocationConstraint},
+	} {
+		var body interface{}
+		if tc.location != "" {
+			body = createBucketParams{LocationConstraint: tc.location}
+		}
+		w, r := prepareTestRequest(hc, tc.bucket, "", body)
+		r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+		hc.Handler().CreateBucketHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+
+		w, r = prepareTestRequest(hc, tc.bucket, "", nil)
+		hc.Handler().GetBucketLocationHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+
+		resp := &LocationResponse{}
+		require.NoError(t, xml.NewDecoder(w.Result().Body).Decode(resp))
+		require.Equal(t, tc.expected, resp.Location)
+
+		w, r = prepareTestRequest(hc, tc.bucket, "", nil)
+		hc.Handler().HeadBucketHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+		require.Equal(t, tc.expected, w.Header().Get(api.AmzBucketRegion))
+	}
+}
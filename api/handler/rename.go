@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+// RenameObjectHandler renames the object without copying of its payload.
+// Source object key is taken from URL encoded X-Amz-Rename-Source header.
+func (h *handler) RenameObjectHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	srcObject, err := url.PathUnescape(strings.TrimPrefix(r.Header.Get(api.AmzRenameSource), "/"))
+	if err != nil || len(srcObject) == 0 {
+		h.logAndSendError(w, "invalid rename source", reqInfo, errors.GetAPIError(errors.ErrInvalidRequest))
+		return
+	}
+	if err = api.CheckObjectName(srcObject); err != nil {
+		h.logAndSendError(w, "invalid rename source", reqInfo, err)
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.checkFeature(r.Context(), bktInfo, features.Rename); err != nil {
+		h.logAndSendError(w, "feature is disabled", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
+	srcObjInfo, err := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: srcObject})
+	if err != nil {
+		h.logAndSendError(w, "could not find source object", reqInfo, err)
+		return
+	}
+
+	if ifMatch := r.Header.Get(api.AmzRenameSourceIfMatch); len(ifMatch) > 0 && strings.Trim(ifMatch, "\"") != srcObjInfo.HashSum {
+		h.logAndSendError(w, "source object etag mismatch", reqInfo, errors.GetAPIError(errors.ErrPreconditionFailed))
+		return
+	}
+
+	if err = h.checkRenameDestination(r, bktInfo, reqInfo.ObjectName); err != nil {
+		h.logAndSendError(w, "destination precondition failed", reqInfo, err)
+		return
+	}
+
+	p := &layer.RenameObjectParams{
+		BktInfo:   bktInfo,
+		Settings:  settings,
+		SrcObject: srcObject,
+		DstObject: reqInfo.ObjectName,
+	}
+
+	extendedObjInfo, err := h.obj.RenameObject(r.Context(), p)
+	if err != nil {
+		h.logAndSendError(w, "couldn't rename object", reqInfo, err)
+		return
+	}
+
+	h.log.Info("object is renamed",
+		zap.String("bucket", bktInfo.Name),
+		zap.String("source", srcObject),
+		zap.String("object", reqInfo.ObjectName),
+		zap.Stringer("object_id", extendedObjInfo.ObjectInfo.ID))
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+// checkRenameDestination checks If-Match and If-None-Match headers against destination object.
+func (h *handler) checkRenameDestination(r *http.Request, bktInfo *data.BucketInfo, dstObject string) error {
+	ifMatch, ifNoneMatch := r.Header.Get(api.IfMatch), r.Header.Get(api.IfNoneMatch)
+	if len(ifMatch) == 0 && len(ifNoneMatch) == 0 {
+		return nil
+	}
+
+	dstObjInfo, err := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: dstObject})
+	if err != nil && !errors.IsS3Error(err, errors.ErrNoSuchKey) {
+		return err
+	}
+
+	exists := err == nil
+	if len(ifMatch) > 0 && (!exists || strings.Trim(ifMatch, "\"") != dstObjInfo.HashSum) {
+		return errors.GetAPIError(errors.ErrPreconditionFailed)
+	}
+	if len(ifNoneMatch) > 0 && exists && (ifNoneMatch == "*" || strings.Trim(ifNoneMatch, "\"") == dstObjInfo.HashSum) {
+		return errors.GetAPIError(errors.ErrPreconditionFailed)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameObject(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, srcName, dstName := "bucket-for-rename", "src/object", "dst/object with space"
+	bktInfo, objInfo := createBucketAndObject(hc, bktName, srcName)
+	putObjectTagging(t, hc, bktName, srcName, map[string]string{"key": "value"})
+
+	renameObject(hc, bktName, srcName, dstName, nil, http.StatusOK)
+	checkNotFound(t, hc, bktName, srcName, emptyVersion)
+	checkFound(t, hc, bktName, dstName, emptyVersion)
+	require.Len(t, listOIDsFromMockedNeoFS(t, hc, bktName), 1)
+	require.True(t, existInMockedNeoFS(hc, bktInfo, objInfo))
+
+	listing := listObjectsV1(t, hc, bktName, "", "", "", -1)
+	require.Len(t, listing.Contents, 1)
+	require.Equal(t, dstName, listing.Contents[0].Key)
+
+	tagging := getObjectTagging(t, hc, bktName, dstName, emptyVersion)
+	require.Len(t, tagging.TagSet, 1)
+	require.Equal(t, "value", tagging.TagSet[0].Value)
+
+	renameObject(hc, bktName, srcName, dstName, nil, http.StatusNotFound)
+}
+
+func TestRenameObjectOverwrite(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, srcName, dstName := "bucket-for-rename", "src", "dst"
+	bktInfo, srcObjInfo := createBucketAndObject(hc, bktName, srcName)
+	dstObjInfo := createTestObject(hc, bktInfo, dstName)
+
+	renameObject(hc, bktName, srcName, dstName, map[string]string{api.IfNoneMatch: "*"}, http.StatusPreconditionFailed)
+	renameObject(hc, bktName, srcName, dstName, map[string]string{api.AmzRenameSourceIfMatch: dstObjInfo.HashSum}, http.StatusPreconditionFailed)
+	renameObject(hc, bktName, srcName, dstName, map[string]string{api.IfMatch: srcObjInfo.HashSum}, http.StatusPreconditionFailed)
+
+	renameObject(hc, bktName, srcName, dstName, map[string]string{
+		api.AmzRenameSourceIfMatch: srcObjInfo.HashSum,
+		api.IfMatch:                dstObjInfo.HashSum,
+	}, http.StatusOK)
+
+	require.False(t, existInMockedNeoFS(hc, bktInfo, dstObjInfo))
+	require.True(t, existInMockedNeoFS(hc, bktInfo, srcObjInfo))
+	checkNotFound(t, hc, bktName, srcName, emptyVersion)
+	checkFound(t, hc, bktName, dstName, emptyVersion)
+}
+
+func TestRenameObjectOverwriteToTrash(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, srcName, dstName := "bucket-for-rename", "src", "dst"
+	bktInfo, srcObjInfo := createBucketAndObject(hc, bktName, srcName)
+	dstObjInfo := createTestObject(hc, bktInfo, dstName)
+	setTrashRetention(t, hc, bktInfo, time.Hour)
+
+	renameObject(hc, bktName, srcName, dstName, nil, http.StatusOK)
+	require.True(t, existInMockedNeoFS(hc, bktInfo, srcObjInfo))
+	require.True(t, existInMockedNeoFS(hc, bktInfo, dstObjInfo))
+	checkNotFound(t, hc, bktName, srcName, emptyVersion)
+
+	listing := listObjectsV1(t, hc, bktName, "", "", "", -1)
+	require.Len(t, listing.Contents, 1)
+	require.Equal(t, dstName, listing.Contents[0].Key)
+	require.Equal(t, srcObjInfo.HashSum, strings.Trim(listing.Contents[0].ETag, "\""))
+
+	trash, err := hc.Layer().ListTrash(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, dstName, trash[0].Key)
+}
+
+func TestRenameObjectVersioned(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, srcName := "bucket-for-rename", "src"
+	createVersionedBucketAndObject(t, hc, bktName, srcName)
+
+	w := renameObject(hc, bktName, srcName, "dst", nil, http.StatusNotImplemented)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNotSupported))
+}
+
+func renameObject(hc *handlerContext, bktName, srcName, dstName string, headers map[string]string, status int) *httptest.ResponseRecorder {
+	w, r := prepareTestRequest(hc, bktName, dstName, nil)
+	r.Header.Set(api.AmzRenameSource, "/"+url.PathEscape(srcName))
+	for key, val := range headers {
+		r.Header.Set(key, val)
+	}
+
+	hc.Handler().RenameObjectHandler(w, r)
+	assertStatus(hc.t, w, status)
+
+	return w
+}
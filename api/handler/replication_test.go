@@ -0,0 +1,59 @@
+package handler
+
+import (
+	errorsStd "errors"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketReplicationHandlers(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	replication := layer.NewTestReplicationService()
+	hc.h.replication = replication
+
+	bktName := "bucket-for-replication"
+	createTestBucket(hc, bktName)
+
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketReplicationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrReplicationConfigurationNotFoundError))
+
+	conf := &data.ReplicationConfiguration{
+		Role: "replication",
+		Rules: []data.ReplicationRule{{
+			ID:          "copy-logs",
+			Status:      data.ReplicationRuleEnabled,
+			Filter:      &data.ReplicationFilter{Prefix: "logs/"},
+			Destination: data.ReplicationDestination{Bucket: "arn:aws:s3:::destination"},
+		}},
+	}
+	w, r = prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutBucketReplicationHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketReplicationHandler(w, r)
+	actual := &data.ReplicationConfiguration{}
+	parseTestResponse(t, w, actual)
+	require.Equal(t, conf.Role, actual.Role)
+	require.Equal(t, conf.Rules, actual.Rules)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeleteBucketReplicationHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w, r = prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketReplicationHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrReplicationConfigurationNotFoundError))
+
+	replication.Err = errorsStd.New("replication is unavailable")
+
+	w, r = prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutBucketReplicationHandler(w, r)
+	assertStatus(t, w, http.StatusInternalServerError)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+)
+
+// requestPayerRequester is a value of x-amz-request-payer header acknowledging the charge of the request.
+const requestPayerRequester = "requester"
+
+func (h *handler) GetBucketRequestPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	resp := &RequestPaymentConfiguration{Payer: data.RequestPayerBucketOwner}
+	if settings.RequesterPays() {
+		resp.Payer = data.RequestPayerRequester
+	}
+
+	if err = api.EncodeToResponse(w, resp); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func (h *handler) PutBucketRequestPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := &RequestPaymentConfiguration{}
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "couldn't parse request payment configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if conf.Payer != data.RequestPayerBucketOwner && conf.Payer != data.RequestPayerRequester {
+		h.logAndSendError(w, "invalid payer", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return
+	}
+
+	// settings pointer is stored in the cache, so modify a copy of the settings
+	newSettings := *settings
+	newSettings.RequestPayer = conf.Payer
+
+	if err = h.obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{
+		BktInfo:  bktInfo,
+		Settings: &newSettings,
+	}); err != nil {
+		h.logAndSendError(w, "couldn't put request payment configuration", reqInfo, err)
+	}
+}
+
+// CheckRequestPayment checks the request to the requester pays bucket. Requests of users other than
+// the bucket owner must acknowledge the charge by x-amz-request-payer header, anonymous requests are
+// denied. It sends AccessDenied error and returns false if the request isn't allowed, charged is true
+// if the request is charged to the requester.
+func (h *handler) CheckRequestPayment(w http.ResponseWriter, r *http.Request) (charged bool, ok bool) {
+	reqInfo := api.GetReqInfo(r.Context())
+	if reqInfo.BucketName == "" {
+		return false, true
+	}
+	switch reqInfo.API {
+	case "Options", "CreateBucket", "ListBuckets":
+		return false, true
+	}
+
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
+	if err != nil {
+		// the handler reports the error itself
+		return false, true
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "couldn't get bucket settings", reqInfo, err)
+		return false, false
+	}
+	if !settings.RequesterPays() {
+		return false, true
+	}
+
+	box, err := layer.GetBoxData(r.Context())
+	if err != nil || box.Gate.BearerToken == nil {
+		h.logAndSendError(w, "anonymous request to requester pays bucket", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return false, false
+	}
+	if bktInfo.Owner.Equals(bearer.ResolveIssuer(*box.Gate.BearerToken)) {
+		return false, true
+	}
+
+	if !strings.EqualFold(r.Header.Get(api.AmzRequestPayer), requestPayerRequester) {
+		h.logAndSendError(w, "request to requester pays bucket isn't acknowledged", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return false, false
+	}
+
+	w.Header().Set(api.AmzRequestCharged, requestPayerRequester)
+	return true, true
+}
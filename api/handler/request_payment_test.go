@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequesterPays(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName := "bucket-for-request-payment"
+
+	box, _ := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+
+	require.Equal(t, data.RequestPayerBucketOwner, getRequestPayment(hc, bktName))
+	putRequestPayment(hc, bktName, "Anyone", http.StatusBadRequest)
+
+	requesterBox, _ := createAccessBox(t)
+	checkRequestPayment(hc, bktName, requesterBox, "", false, true)
+
+	putRequestPayment(hc, bktName, data.RequestPayerRequester, http.StatusOK)
+	require.Equal(t, data.RequestPayerRequester, getRequestPayment(hc, bktName))
+
+	checkRequestPayment(hc, bktName, box, "", false, true)
+	checkRequestPayment(hc, bktName, requesterBox, "", false, false)
+	checkRequestPayment(hc, bktName, requesterBox, "requester", true, true)
+	checkRequestPayment(hc, bktName, &accessbox.Box{Gate: &accessbox.GateData{}}, "requester", false, false)
+
+	putRequestPayment(hc, bktName, data.RequestPayerBucketOwner, http.StatusOK)
+	checkRequestPayment(hc, bktName, requesterBox, "", false, true)
+}
+
+func getRequestPayment(hc *handlerContext, bktName string) string {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketRequestPaymentHandler(w, r)
+
+	conf := &RequestPaymentConfiguration{}
+	readResponse(hc.t, w, http.StatusOK, conf)
+	return conf.Payer
+}
+
+func putRequestPayment(hc *handlerContext, bktName, payer string, status int) {
+	w, r := prepareTestRequest(hc, bktName, "", &RequestPaymentConfiguration{Payer: payer})
+	hc.Handler().PutBucketRequestPaymentHandler(w, r)
+	assertStatus(hc.t, w, status)
+}
+
+func checkRequestPayment(hc *handlerContext, bktName string, box *accessbox.Box, payer string, charged, ok bool) {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	if payer != "" {
+		r.Header.Set(api.AmzRequestPayer, payer)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+
+	actualCharged, actualOK := hc.Handler().CheckRequestPayment(w, r)
+	require.Equal(hc.t, charged, actualCharged)
+	require.Equal(hc.t, ok, actualOK)
+	if !ok {
+		assertStatus(hc.t, w, http.StatusForbidden)
+	}
+	if charged {
+		require.Equal(hc.t, "requester", w.Header().Get(api.AmzRequestCharged))
+	}
+}
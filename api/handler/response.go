@@ -15,6 +15,9 @@ type ListBucketsResponse struct {
 	Buckets struct {
 		Buckets []Bucket `xml:"Bucket"`
 	} // Buckets are nested
+
+	ContinuationToken string `xml:"ContinuationToken,omitempty"`
+	Prefix            string `xml:"Prefix,omitempty"`
 }
 
 // ListObjectsV1Response -- format for ListObjectsV1 response.
@@ -53,6 +56,7 @@ type ListObjectsV2Response struct {
 type Bucket struct {
 	Name         string
 	CreationDate string // time string of format "2006-01-02T15:04:05.000Z"
+	BucketRegion string `xml:",omitempty"`
 }
 
 // AccessControlPolicy contains ACL.
@@ -196,7 +200,7 @@ type ObjectVersionResponse struct {
 	LastModified string `xml:"LastModified"`
 	Owner        Owner  `xml:"Owner"`
 	Size         int64  `xml:"Size"`
-	StorageClass string `xml:"StorageClass,omitempty"` // is empty!!
+	StorageClass string `xml:"StorageClass,omitempty"`
 	VersionID    string `xml:"VersionId"`
 }
 
@@ -230,6 +234,9 @@ type ListObjectsVersionsResponse struct {
 	XMLName             xml.Name                `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult" json:"-"`
 	EncodingType        string                  `xml:"EncodingType,omitempty"`
 	Name                string                  `xml:"Name"`
+	Prefix              string                  `xml:"Prefix"`
+	Delimiter           string                  `xml:"Delimiter,omitempty"`
+	MaxKeys             int                     `xml:"MaxKeys"`
 	IsTruncated         bool                    `xml:"IsTruncated"`
 	KeyMarker           string                  `xml:"KeyMarker"`
 	NextKeyMarker       string                  `xml:"NextKeyMarker,omitempty"`
@@ -240,6 +247,29 @@ type ListObjectsVersionsResponse struct {
 	CommonPrefixes      []CommonPrefix          `xml:"CommonPrefixes"`
 }
 
+// OwnershipControls contains OwnershipControls XML representation.
+type OwnershipControls struct {
+	XMLName xml.Name                `xml:"http://s3.amazonaws.com/doc/2006-03-01/ OwnershipControls"`
+	Rules   []OwnershipControlsRule `xml:"Rule"`
+}
+
+// OwnershipControlsRule contains the object ownership of the bucket.
+type OwnershipControlsRule struct {
+	ObjectOwnership string `xml:"ObjectOwnership"`
+}
+
+// RequestPaymentConfiguration contains RequestPaymentConfiguration XML representation.
+type RequestPaymentConfiguration struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ RequestPaymentConfiguration"`
+	Payer   string   `xml:"Payer"`
+}
+
+// AccelerateConfiguration contains AccelerateConfiguration XML representation.
+type AccelerateConfiguration struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AccelerateConfiguration"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
 // VersioningConfiguration contains VersioningConfiguration XML representation.
 type VersioningConfiguration struct {
 	XMLName   xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration"`
@@ -255,9 +285,10 @@ type Tagging struct {
 
 // PostResponse contains result of posting object.
 type PostResponse struct {
-	Bucket string `xml:"Bucket"`
-	Key    string `xml:"Key"`
-	ETag   string `xml:"Etag"`
+	Location string `xml:"Location"`
+	Bucket   string `xml:"Bucket"`
+	Key      string `xml:"Key"`
+	ETag     string `xml:"ETag"`
 }
 
 // Tag is an AWS key-value tag.
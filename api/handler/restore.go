@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+type (
+	// RestoreRequest is a body of RestoreObject request.
+	RestoreRequest struct {
+		XMLName              xml.Name              `xml:"http://s3.amazonaws.com/doc/2006-03-01/ RestoreRequest" json:"-"`
+		Days                 int                   `xml:"Days,omitempty"`
+		GlacierJobParameters *GlacierJobParameters `xml:"GlacierJobParameters,omitempty"`
+		// Type is set for select restore requests, which aren't supported.
+		Type string `xml:"Type,omitempty"`
+	}
+
+	// GlacierJobParameters sets the retrieval tier of the restore, the tier doesn't affect the restore.
+	GlacierJobParameters struct {
+		Tier string `xml:"Tier"`
+	}
+)
+
+// RestoreObjectHandler requests the temporary copy of the archived object. The copy is made in background,
+// its state is reported by x-amz-restore header of HEAD and GET responses.
+func (h *handler) RestoreObjectHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	req := new(RestoreRequest)
+	if err = api.NewXMLDecoder(r.Body).Decode(req); err != nil {
+		h.logAndSendError(w, "could not parse restore request", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if len(req.Type) != 0 {
+		h.logAndSendError(w, "select restore request", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+	if req.Days < 1 {
+		h.logAndSendError(w, "invalid restore days", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	restored, err := h.lifecycle.RestoreObject(r.Context(), &layer.RestoreObjectParams{
+		BktInfo:   bktInfo,
+		Object:    reqInfo.ObjectName,
+		VersionID: reqInfo.URL.Query().Get(api.QueryVersionID),
+		Days:      req.Days,
+	})
+	if err != nil {
+		h.logAndSendError(w, "could not restore object", reqInfo, err)
+		return
+	}
+
+	if restored {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// restoreHeader returns a value of x-amz-restore header for the restore state of the archived object.
+func restoreHeader(restore *data.RestoreInfo) string {
+	if restore.Ongoing {
+		return `ongoing-request="true"`
+	}
+	return `ongoing-request="false", expiry-date="` + restore.Expiry.UTC().Format(http.TimeFormat) + `"`
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreArchivedObject(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-restore", "archive/obj"
+	bktInfo := createTestBucket(hc, bktName)
+	putObjectContent(hc, bktName, objName, "content")
+
+	conf := &data.LifecycleConfiguration{
+		Rules: []data.LifecycleRule{{
+			ID:          "archive",
+			Status:      data.LifecycleStatusEnabled,
+			Filter:      &data.LifecycleFilter{Prefix: "archive/"},
+			Transitions: []data.LifecycleTransition{{Days: 1, StorageClass: testArchiveStorageClass}},
+		}},
+	}
+	w, r := prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutBucketLifecycleHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	w = restoreObject(hc, bktName, objName, 1)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidObjectState))
+
+	later := context.WithValue(hc.Context(), api.ClientTime, time.Now().Add(3*24*time.Hour))
+	transitioned, err := hc.Layer().TransitionObjects(later, bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, 1, transitioned)
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidObjectState))
+
+	w = headArchivedObject(hc, bktName, objName)
+	require.Equal(t, testArchiveStorageClass, w.Header().Get(api.AmzStorageClass))
+	require.Empty(t, w.Header().Get(api.AmzRestore))
+
+	w = restoreObject(hc, bktName, objName, 0)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrMalformedXML))
+	w = restoreObject(hc, bktName, objName, 1)
+	assertStatus(t, w, http.StatusAccepted)
+	w = restoreObject(hc, bktName, objName, 1)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrRestoreAlreadyInProgress))
+
+	w = headArchivedObject(hc, bktName, objName)
+	require.Equal(t, `ongoing-request="true"`, w.Header().Get(api.AmzRestore))
+
+	restored, err := hc.Layer().ProcessRestores(hc.Context(), bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, 1, restored)
+
+	w = headArchivedObject(hc, bktName, objName)
+	require.True(t, strings.HasPrefix(w.Header().Get(api.AmzRestore), `ongoing-request="false", expiry-date=`))
+	require.Equal(t, "content", getObjectContent(t, hc, bktName, objName))
+
+	w = restoreObject(hc, bktName, objName, 2)
+	assertStatus(t, w, http.StatusOK)
+
+	restored, err = hc.Layer().ProcessRestores(later, bktInfo)
+	require.NoError(t, err)
+	require.Zero(t, restored)
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidObjectState))
+
+	w = headArchivedObject(hc, bktName, objName)
+	require.Empty(t, w.Header().Get(api.AmzRestore))
+}
+
+func restoreObject(hc *handlerContext, bktName, objName string, days int) *httptest.ResponseRecorder {
+	query := make(url.Values)
+	query.Add("restore", "")
+	w, r := prepareTestFullRequest(hc, bktName, objName, query, &RestoreRequest{Days: days})
+	hc.Handler().RestoreObjectHandler(w, r)
+	return w
+}
+
+func headArchivedObject(hc *handlerContext, bktName, objName string) *httptest.ResponseRecorder {
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(hc.t, w, http.StatusOK)
+	return w
+}
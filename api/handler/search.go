@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/xml"
+	errorsStd "errors"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+type (
+	// SearchObjects is a request body of objects search by metadata and tags.
+	SearchObjects struct {
+		XMLName xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ SearchObjects"`
+		Prefix  string         `xml:"Prefix,omitempty"`
+		Marker  string         `xml:"Marker,omitempty"`
+		MaxKeys *int           `xml:"MaxKeys,omitempty"`
+		Filters []SearchFilter `xml:"Filter"`
+	}
+
+	// SearchFilter is a predicate of objects search, Type is either Metadata or Tag.
+	SearchFilter struct {
+		Type   string   `xml:"Type"`
+		Key    string   `xml:"Key"`
+		Equals *string  `xml:"Equals,omitempty"`
+		Prefix *string  `xml:"Prefix,omitempty"`
+		Min    *float64 `xml:"Min,omitempty"`
+		Max    *float64 `xml:"Max,omitempty"`
+	}
+)
+
+// SearchObjectsHandler returns objects of the bucket matching metadata and tag filters in ListObjectsV1 format.
+func (h *handler) SearchObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	reqBody := new(SearchObjects)
+	if err := api.NewXMLDecoder(r.Body).Decode(reqBody); err != nil {
+		h.logAndSendError(w, "could not read search objects xml", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	p := &layer.SearchObjectsParams{
+		Prefix:  reqBody.Prefix,
+		Marker:  reqBody.Marker,
+		MaxKeys: maxObjectList,
+		Filters: make([]layer.SearchFilter, len(reqBody.Filters)),
+	}
+	if reqBody.MaxKeys != nil {
+		if *reqBody.MaxKeys < 0 {
+			h.logAndSendError(w, "invalid max keys", reqInfo, errors.GetAPIError(errors.ErrInvalidMaxKeys))
+			return
+		}
+		p.MaxKeys = *reqBody.MaxKeys
+	}
+
+	for i, filter := range reqBody.Filters {
+		p.Filters[i] = layer.SearchFilter{
+			Type:   layer.SearchFilterType(filter.Type),
+			Key:    filter.Key,
+			Equals: filter.Equals,
+			Prefix: filter.Prefix,
+			Min:    filter.Min,
+			Max:    filter.Max,
+		}
+		if err := p.Filters[i].Validate(); err != nil {
+			h.logAndSendError(w, "invalid search filter", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+			return
+		}
+	}
+
+	var err error
+	if p.BktInfo, err = h.getBucketAndCheckOwner(r, reqInfo.BucketName); err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.checkFeature(r.Context(), p.BktInfo, features.Search); err != nil {
+		h.logAndSendError(w, "feature is disabled", reqInfo, err)
+		return
+	}
+
+	list, err := h.obj.SearchObjects(r.Context(), p)
+	if err != nil {
+		if errorsStd.Is(err, layer.ErrInvalidSearchFilter) {
+			err = errors.GetAPIError(errors.ErrInvalidArgument)
+		}
+		h.logAndSendError(w, "could not search objects", reqInfo, err)
+		return
+	}
+
+	resp := &ListObjectsV1Response{
+		Name:        p.BktInfo.Name,
+		Marker:      p.Marker,
+		Prefix:      p.Prefix,
+		MaxKeys:     p.MaxKeys,
+		IsTruncated: list.IsTruncated,
+		NextMarker:  list.NextMarker,
+		Contents:    fillContentsWithOwner(list.Objects, ""),
+	}
+
+	if err = api.EncodeToResponse(w, resp); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchObjects(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-search"
+	createTestBucket(hc, bktName)
+
+	for _, obj := range []struct {
+		name, genre, year string
+	}{
+		{name: "media/a", genre: "jazz", year: "1995"},
+		{name: "media/b", genre: "jazz-fusion", year: "2005"},
+		{name: "media/c", genre: "rock", year: "1999"},
+		{name: "other/d", genre: "jazz", year: "1997"},
+	} {
+		w, r := prepareTestRequest(hc, bktName, obj.name, nil)
+		r.Header.Set(api.MetadataPrefix+"Genre", obj.genre)
+		hc.Handler().PutObjectHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+		putObjectTagging(t, hc, bktName, obj.name, map[string]string{"year": obj.year})
+	}
+
+	strPtr := func(s string) *string { return &s }
+	numPtr := func(f float64) *float64 { return &f }
+
+	for _, tc := range []struct {
+		name     string
+		req      *SearchObjects
+		expected []string
+	}{
+		{
+			name:     "metadata equality",
+			req:      &SearchObjects{Filters: []SearchFilter{{Type: "Metadata", Key: "genre", Equals: strPtr("jazz")}}},
+			expected: []string{"media/a", "other/d"},
+		},
+		{
+			name:     "metadata prefix with object prefix",
+			req:      &SearchObjects{Prefix: "media/", Filters: []SearchFilter{{Type: "Metadata", Key: "genre", Prefix: strPtr("jazz")}}},
+			expected: []string{"media/a", "media/b"},
+		},
+		{
+			name:     "tag range",
+			req:      &SearchObjects{Filters: []SearchFilter{{Type: "Tag", Key: "year", Min: numPtr(1996), Max: numPtr(2000)}}},
+			expected: []string{"media/c", "other/d"},
+		},
+		{
+			name: "several filters",
+			req: &SearchObjects{Filters: []SearchFilter{
+				{Type: "Metadata", Key: "genre", Equals: strPtr("jazz")},
+				{Type: "Tag", Key: "year", Max: numPtr(1996)},
+			}},
+			expected: []string{"media/a"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := searchObjects(hc, bktName, tc.req)
+			require.Equal(t, tc.expected, objectKeys(resp))
+			require.False(t, resp.IsTruncated)
+		})
+	}
+
+	maxKeys := 1
+	resp := searchObjects(hc, bktName, &SearchObjects{MaxKeys: &maxKeys, Filters: []SearchFilter{{Type: "Metadata", Key: "genre", Equals: strPtr("jazz")}}})
+	require.Equal(t, []string{"media/a"}, objectKeys(resp))
+	require.True(t, resp.IsTruncated)
+	require.Equal(t, "media/a", resp.NextMarker)
+
+	resp = searchObjects(hc, bktName, &SearchObjects{Marker: resp.NextMarker, Filters: []SearchFilter{{Type: "Metadata", Key: "genre", Equals: strPtr("jazz")}}})
+	require.Equal(t, []string{"other/d"}, objectKeys(resp))
+
+	w, r := prepareTestRequest(hc, bktName, "", &SearchObjects{Filters: []SearchFilter{{Type: "Metadata", Key: "genre"}}})
+	hc.Handler().SearchObjectsHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrInvalidArgument))
+}
+
+func searchObjects(hc *handlerContext, bktName string, req *SearchObjects) *ListObjectsV1Response {
+	w, r := prepareTestRequest(hc, bktName, "", req)
+	hc.Handler().SearchObjectsHandler(w, r)
+	assertStatus(hc.t, w, http.StatusOK)
+
+	resp := &ListObjectsV1Response{}
+	parseTestResponse(hc.t, w, resp)
+	return resp
+}
+
+func objectKeys(resp *ListObjectsV1Response) []string {
+	keys := make([]string, 0, len(resp.Contents))
+	for _, obj := range resp.Contents {
+		keys = append(keys, obj.Key)
+	}
+	return keys
+}
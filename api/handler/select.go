@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/xml"
+	errorsStd "errors"
+	"net/http"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/s3select"
+	"go.uber.org/zap"
+)
+
+type (
+	// SelectObjectContentRequest is a request body of SelectObjectContent.
+	SelectObjectContentRequest struct {
+		XMLName         xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ SelectObjectContentRequest"`
+		Expression      string   `xml:"Expression"`
+		ExpressionType  string   `xml:"ExpressionType"`
+		RequestProgress struct {
+			Enabled bool `xml:"Enabled"`
+		} `xml:"RequestProgress"`
+		InputSerialization  *s3select.InputSerialization  `xml:"InputSerialization"`
+		OutputSerialization *s3select.OutputSerialization `xml:"OutputSerialization"`
+		ScanRange           *struct {
+			Start *int64 `xml:"Start"`
+			End   *int64 `xml:"End"`
+		} `xml:"ScanRange"`
+	}
+)
+
+const selectExpressionTypeSQL = "SQL"
+
+// SelectObjectContentHandler filters the content of CSV or JSON object by SQL expression.
+// The result is sent in the event stream encoding.
+func (h *handler) SelectObjectContentHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	reqBody := new(SelectObjectContentRequest)
+	if err := api.NewXMLDecoder(r.Body).Decode(reqBody); err != nil {
+		h.logAndSendError(w, "could not read select object content xml", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	if !strings.EqualFold(reqBody.ExpressionType, selectExpressionTypeSQL) {
+		h.logAndSendError(w, "invalid expression type", reqInfo, errors.GetAPIError(errors.ErrInvalidExpressionType))
+		return
+	}
+	if reqBody.ScanRange != nil {
+		h.logAndSendError(w, "scan range isn't supported", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	query, err := s3select.NewQuery(reqBody.Expression, reqBody.InputSerialization, reqBody.OutputSerialization)
+	if err != nil {
+		h.logAndSendError(w, "invalid select query", reqInfo, err)
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.checkFeature(r.Context(), bktInfo, features.Select); err != nil {
+		h.logAndSendError(w, "feature is disabled", reqInfo, err)
+		return
+	}
+
+	p := &layer.HeadObjectParams{
+		BktInfo:   bktInfo,
+		Object:    reqInfo.ObjectName,
+		VersionID: reqInfo.URL.Query().Get(api.QueryVersionID),
+	}
+
+	info, err := h.obj.GetObjectInfo(r.Context(), p)
+	if err != nil {
+		h.logAndSendError(w, "could not find object", reqInfo, err)
+		return
+	}
+
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+
+	if err = encryptionParams.MatchObjectEncryption(layer.FormEncryptionInfo(info.Headers)); err != nil {
+		h.logAndSendError(w, "encryption doesn't match object", reqInfo, errors.GetAPIError(errors.ErrBadRequest), zap.Error(err))
+		return
+	}
+
+	if err = h.obj.CheckQuarantine(r.Context(), bktInfo, info); err != nil {
+		h.logAndSendError(w, "could not check object quarantine", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	events := s3select.NewEventWriter(w)
+	err = h.obj.SelectObjectContent(r.Context(), &layer.SelectObjectParams{
+		BktInfo:    bktInfo,
+		ObjectInfo: info,
+		Encryption: encryptionParams,
+		Query:      query,
+		Writer:     events,
+		Progress:   reqBody.RequestProgress.Enabled,
+	})
+	if err != nil {
+		h.log.Error("could not select object content", zap.String("request_id", reqInfo.RequestID),
+			zap.String("bucket", reqInfo.BucketName), zap.String("object", reqInfo.ObjectName), zap.Error(err))
+
+		// status is already sent, so the error is sent in the event stream
+		code, message := selectErrorCodeAndMessage(err)
+		if err = events.WriteError(code, message); err != nil {
+			h.log.Error("could not write select error", zap.String("request_id", reqInfo.RequestID), zap.Error(err))
+		}
+	}
+}
+
+func selectErrorCodeAndMessage(err error) (string, string) {
+	var parsingErr *s3select.ParsingError
+	if errorsStd.As(err, &parsingErr) {
+		return parsingErr.Code(), parsingErr.Error()
+	}
+
+	apiErr, ok := transformToS3Error(err).(errors.Error)
+	if !ok {
+		apiErr = errors.GetAPIError(errors.ErrInternalError)
+	}
+
+	return apiErr.Code, apiErr.Description
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/s3select"
+	"github.com/stretchr/testify/require"
+)
+
+type selectResult struct {
+	records   string
+	events    []string
+	errorCode string
+}
+
+func TestSelectObjectContent(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-select"
+	createTestBucket(hc, bktName)
+
+	putObjectContent(hc, bktName, "people.csv", "name,age,city\nalice,31,Paris\nbob,25,\"Berlin, DE\"\ncarol,42,Rome\n")
+	putObjectContent(hc, bktName, "people.json", "{\"name\":\"alice\",\"age\":31}\n{\"name\":\"bob\",\"age\":25,\"tags\":[\"a\"]}\n")
+
+	csvInput := &s3select.InputSerialization{CSV: &s3select.CSVInput{FileHeaderInfo: "USE"}}
+	jsonInput := &s3select.InputSerialization{JSON: &s3select.JSONInput{Type: "LINES"}}
+	csvOutput := &s3select.OutputSerialization{CSV: &s3select.CSVOutput{}}
+	jsonOutput := &s3select.OutputSerialization{JSON: &s3select.JSONOutput{}}
+
+	for _, tc := range []struct {
+		name       string
+		object     string
+		expression string
+		input      *s3select.InputSerialization
+		output     *s3select.OutputSerialization
+		expected   string
+	}{
+		{
+			name:       "csv where",
+			object:     "people.csv",
+			expression: "SELECT s.name, s.city FROM S3Object s WHERE CAST(s.age AS INT) > 30",
+			input:      csvInput,
+			output:     csvOutput,
+			expected:   "alice,Paris\ncarol,Rome\n",
+		},
+		{
+			name:       "csv quoting and limit",
+			object:     "people.csv",
+			expression: "SELECT * FROM S3Object WHERE name LIKE 'b%' LIMIT 1",
+			input:      csvInput,
+			output:     csvOutput,
+			expected:   "bob,25,\"Berlin, DE\"\n",
+		},
+		{
+			name:       "csv to json",
+			object:     "people.csv",
+			expression: "SELECT UPPER(name) AS n FROM S3Object WHERE city = 'Rome'",
+			input:      csvInput,
+			output:     jsonOutput,
+			expected:   "{\"n\":\"CAROL\"}\n",
+		},
+		{
+			name:       "csv aggregate",
+			object:     "people.csv",
+			expression: "SELECT COUNT(*), MAX(CAST(age AS INT)) FROM S3Object",
+			input:      csvInput,
+			output:     csvOutput,
+			expected:   "3,42\n",
+		},
+		{
+			name:       "json lines",
+			object:     "people.json",
+			expression: "SELECT s.name, s.tags[0] AS tag FROM S3Object s WHERE s.age < 30",
+			input:      jsonInput,
+			output:     jsonOutput,
+			expected:   "{\"name\":\"bob\",\"tag\":\"a\"}\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := selectObjectContent(hc, bktName, tc.object, &SelectObjectContentRequest{
+				Expression:          tc.expression,
+				ExpressionType:      "SQL",
+				InputSerialization:  tc.input,
+				OutputSerialization: tc.output,
+			})
+			require.Empty(t, res.errorCode)
+			require.Equal(t, tc.expected, res.records)
+			require.Equal(t, []string{"Stats", "End"}, res.events[len(res.events)-2:])
+		})
+	}
+
+	t.Run("parsing error", func(t *testing.T) {
+		putObjectContent(hc, bktName, "invalid.json", "{\"name\":")
+		res := selectObjectContent(hc, bktName, "invalid.json", &SelectObjectContentRequest{
+			Expression:          "SELECT * FROM S3Object",
+			ExpressionType:      "SQL",
+			InputSerialization:  jsonInput,
+			OutputSerialization: jsonOutput,
+		})
+		require.Equal(t, "JSONParsingError", res.errorCode)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		for _, tc := range []struct {
+			req *SelectObjectContentRequest
+			err errors.ErrorCode
+		}{
+			{
+				req: &SelectObjectContentRequest{Expression: "SELECT * FROM S3Object", ExpressionType: "XPath", InputSerialization: csvInput, OutputSerialization: csvOutput},
+				err: errors.ErrInvalidExpressionType,
+			},
+			{
+				req: &SelectObjectContentRequest{Expression: "SELECT * FROM S3Object", ExpressionType: "SQL", InputSerialization: &s3select.InputSerialization{CompressionType: "ZSTD", CSV: &s3select.CSVInput{}}, OutputSerialization: csvOutput},
+				err: errors.ErrInvalidCompressionFormat,
+			},
+			{
+				req: &SelectObjectContentRequest{Expression: "SELECT *", ExpressionType: "SQL", InputSerialization: csvInput, OutputSerialization: csvOutput},
+				err: errors.ErrParseSelectMissingFrom,
+			},
+		} {
+			w, r := prepareTestRequest(hc, bktName, "people.csv", tc.req)
+			hc.Handler().SelectObjectContentHandler(w, r)
+			assertS3Error(t, w, errors.GetAPIError(tc.err))
+		}
+	})
+}
+
+func selectObjectContent(hc *handlerContext, bktName, objName string, req *SelectObjectContentRequest) *selectResult {
+	w, r := prepareTestRequest(hc, bktName, objName, req)
+	hc.Handler().SelectObjectContentHandler(w, r)
+	assertStatus(hc.t, w, http.StatusOK)
+
+	return decodeEventStream(hc.t, w.Body)
+}
+
+// decodeEventStream decodes messages of the event stream and checks their checksums.
+func decodeEventStream(t *testing.T, r io.Reader) *selectResult {
+	res := new(selectResult)
+	var records bytes.Buffer
+
+	for {
+		prelude := make([]byte, 12)
+		if _, err := io.ReadFull(r, prelude); err == io.EOF {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		require.Equal(t, crc32.ChecksumIEEE(prelude[:8]), binary.BigEndian.Uint32(prelude[8:]))
+
+		totalLen := binary.BigEndian.Uint32(prelude[:4])
+		headersLen := binary.BigEndian.Uint32(prelude[4:8])
+		rest := make([]byte, totalLen-12)
+		_, err := io.ReadFull(r, rest)
+		require.NoError(t, err)
+
+		message := append(prelude, rest[:len(rest)-4]...)
+		require.Equal(t, crc32.ChecksumIEEE(message), binary.BigEndian.Uint32(rest[len(rest)-4:]))
+
+		headers := make(map[string]string)
+		for buf := rest[:headersLen]; len(buf) > 0; {
+			nameLen := int(buf[0])
+			name := string(buf[1 : 1+nameLen])
+			buf = buf[1+nameLen+1:] // skip value type
+			valueLen := int(binary.BigEndian.Uint16(buf))
+			headers[name] = string(buf[2 : 2+valueLen])
+			buf = buf[2+valueLen:]
+		}
+
+		if headers[":message-type"] == "error" {
+			res.errorCode = headers[":error-code"]
+			continue
+		}
+		res.events = append(res.events, headers[":event-type"])
+		if headers[":event-type"] == "Records" {
+			records.Write(rest[headersLen : len(rest)-4])
+		}
+	}
+
+	res.records = records.String()
+	return res
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+)
+
+// Veeam Smart Object Storage API (SOSAPI) discovery objects.
+const (
+	sosAPIPrefix         = ".system-d26a9498-cb7c-4a87-a44a-8ae204f5ba6c/"
+	sosAPISystemObject   = sosAPIPrefix + "system.xml"
+	sosAPICapacityObject = sosAPIPrefix + "capacity.xml"
+
+	sosAPIProtocolVersion = `"1.0"`
+	sosAPIModelName       = `"NeoFS S3 Gateway"`
+
+	sosAPIConcurrentTaskLimit = 64
+	sosAPIKbBlockSize         = 1024
+)
+
+type (
+	// SOSAPISystemInfo is a content of SOSAPI system.xml object.
+	SOSAPISystemInfo struct {
+		XMLName               xml.Name                    `xml:"SystemInfo"`
+		ProtocolVersion       string                      `xml:"ProtocolVersion"`
+		ModelName             string                      `xml:"ModelName"`
+		ProtocolCapabilities  SOSAPIProtocolCapabilities  `xml:"ProtocolCapabilities"`
+		SystemRecommendations SOSAPISystemRecommendations `xml:"SystemRecommendations"`
+	}
+
+	// SOSAPIProtocolCapabilities contains SOSAPI features supported by the gateway.
+	SOSAPIProtocolCapabilities struct {
+		CapacityInfo   bool `xml:"CapacityInfo"`
+		UploadSessions bool `xml:"UploadSessions"`
+		IAMSTS         bool `xml:"IAMSTS"`
+	}
+
+	// SOSAPISystemRecommendations contains recommended client settings.
+	SOSAPISystemRecommendations struct {
+		S3ConcurrentTaskLimit    int `xml:"S3ConcurrentTaskLimit"`
+		S3MultiObjectDeleteLimit int `xml:"S3MultiObjectDeleteLimit"`
+		KbBlockSize              int `xml:"KbBlockSize"`
+	}
+
+	// SOSAPICapacityInfo is a content of SOSAPI capacity.xml object.
+	SOSAPICapacityInfo struct {
+		XMLName   xml.Name `xml:"CapacityInfo"`
+		Capacity  uint64   `xml:"Capacity"`
+		Available uint64   `xml:"Available,omitempty"`
+		Used      uint64   `xml:"Used,omitempty"`
+	}
+)
+
+func isSOSAPIObject(name string) bool {
+	return name == sosAPISystemObject || name == sosAPICapacityObject
+}
+
+// serveSOSAPIObject writes SOSAPI discovery object to the response.
+// Payload is omitted for HEAD requests.
+func (h *handler) serveSOSAPIObject(w http.ResponseWriter, r *http.Request, reqInfo *api.ReqInfo) {
+	var response interface{}
+
+	switch reqInfo.ObjectName {
+	case sosAPISystemObject:
+		response = &SOSAPISystemInfo{
+			ProtocolVersion: sosAPIProtocolVersion,
+			ModelName:       sosAPIModelName,
+			ProtocolCapabilities: SOSAPIProtocolCapabilities{
+				CapacityInfo: true,
+			},
+			SystemRecommendations: SOSAPISystemRecommendations{
+				S3ConcurrentTaskLimit:    sosAPIConcurrentTaskLimit,
+				S3MultiObjectDeleteLimit: maxObjectList,
+				KbBlockSize:              sosAPIKbBlockSize,
+			},
+		}
+	case sosAPICapacityObject:
+		capacity, err := h.obj.NetworkCapacity(r.Context())
+		if err != nil {
+			h.logAndSendError(w, "could not get network capacity", reqInfo, err)
+			return
+		}
+		// NeoFS network map doesn't provide used space of the nodes,
+		// so only the total capacity is reported.
+		response = &SOSAPICapacityInfo{
+			Capacity: capacity,
+		}
+	}
+
+	body := api.EncodeResponse(response)
+
+	w.Header().Set(api.ContentType, string(api.MimeXML))
+	w.Header().Set(api.ContentLength, strconv.Itoa(len(body)))
+	w.Header().Set(api.LastModified, time.Now().UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSOSAPIObjects(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-sosapi"
+	createTestBucket(hc, bktName)
+
+	w, r := prepareTestRequest(hc, bktName, sosAPISystemObject, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertS3Error(t, w, errors.GetAPIError(errors.ErrNoSuchKey))
+
+	hc.Handler().cfg.SOSAPIEnabled = true
+
+	var node1, node2 netmap.NodeInfo
+	node1.SetCapacity(10)
+	node2.SetCapacity(20)
+
+	var nm netmap.NetMap
+	nm.SetNodes([]netmap.NodeInfo{node1, node2})
+	hc.MockedPool().SetNetmap(nm)
+
+	w, r = prepareTestRequest(hc, bktName, sosAPISystemObject, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	systemInfo := &SOSAPISystemInfo{}
+	parseTestResponse(t, w, systemInfo)
+	require.Equal(t, sosAPIProtocolVersion, systemInfo.ProtocolVersion)
+	require.True(t, systemInfo.ProtocolCapabilities.CapacityInfo)
+
+	w, r = prepareTestRequest(hc, bktName, sosAPICapacityObject, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	capacityInfo := &SOSAPICapacityInfo{}
+	parseTestResponse(t, w, capacityInfo)
+	require.Equal(t, uint64(30<<30), capacityInfo.Capacity)
+	require.NotContains(t, w.Body.String(), "<Available>")
+	require.NotContains(t, w.Body.String(), "<Used>")
+
+	w, r = prepareTestRequest(hc, bktName, sosAPICapacityObject, nil)
+	r.Method = http.MethodHead
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Body.Bytes())
+	require.NotEmpty(t, w.Header().Get(api.ContentLength))
+}
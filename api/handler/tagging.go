@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"encoding/xml"
+	stderrors "errors"
 	"io"
 	"net/http"
 	"sort"
@@ -23,6 +23,11 @@ const (
 	valueTagMaxLength = 256
 )
 
+// errQuarantineRelease is the reason of rejection of tagging changes dropping the quarantine tag.
+var errQuarantineRelease = stderrors.New("only operators can release objects from quarantine")
+
+var errDuplicateTagKey = stderrors.New("cannot provide multiple tags with the same key")
+
 func (h *handler) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
 
@@ -46,6 +51,11 @@ func (h *handler) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request
 		},
 		TagSet: tagSet,
 	}
+	if err = h.checkQuarantineRelease(r, *tagPrm.ObjectVersion, tagSet); err != nil {
+		h.logAndSendError(w, "could not change tags of quarantined object", reqInfo, err)
+		return
+	}
+
 	nodeVersion, err := h.obj.PutObjectTagging(r.Context(), tagPrm)
 	if err != nil {
 		h.logAndSendError(w, "could not put object tagging", reqInfo, err)
@@ -122,6 +132,11 @@ func (h *handler) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Requ
 		VersionID:  reqInfo.URL.Query().Get(api.QueryVersionID),
 	}
 
+	if err = h.checkQuarantineRelease(r, *p, nil); err != nil {
+		h.logAndSendError(w, "could not change tags of quarantined object", reqInfo, err)
+		return
+	}
+
 	nodeVersion, err := h.obj.DeleteObjectTagging(r.Context(), p)
 	if err != nil {
 		h.logAndSendError(w, "could not delete object tagging", reqInfo, err)
@@ -206,7 +221,7 @@ func (h *handler) DeleteBucketTaggingHandler(w http.ResponseWriter, r *http.Requ
 
 func readTagSet(reader io.Reader) (map[string]string, error) {
 	tagging := new(Tagging)
-	if err := xml.NewDecoder(reader).Decode(tagging); err != nil {
+	if err := api.NewXMLDecoder(reader).Decode(tagging); err != nil {
 		return nil, errors.GetAPIError(errors.ErrMalformedXML)
 	}
 
@@ -239,10 +254,15 @@ func checkTagSet(tagSet []Tag) error {
 		return errors.GetAPIError(errors.ErrInvalidTagsSizeExceed)
 	}
 
+	keys := make(map[string]struct{}, len(tagSet))
 	for _, tag := range tagSet {
 		if err := checkTag(tag); err != nil {
 			return err
 		}
+		if _, ok := keys[tag.Key]; ok {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidTagKey, errDuplicateTagKey)
+		}
+		keys[tag.Key] = struct{}{}
 	}
 
 	return nil
@@ -279,3 +299,21 @@ func isValidTag(str string) bool {
 	}
 	return true
 }
+
+// checkQuarantineRelease rejects replacing tags of the quarantined object version with the tags without
+// the quarantine tag unless the request is made by an operator.
+func (h *handler) checkQuarantineRelease(r *http.Request, version layer.ObjectVersion, tagSet map[string]string) error {
+	if h.obj.Quarantined(tagSet) || (h.cfg.Operators != nil && h.cfg.Operators.Allowed(r)) {
+		return nil
+	}
+
+	_, tags, err := h.obj.GetObjectTagging(r.Context(), &layer.GetObjectTaggingParams{ObjectVersion: &version})
+	if err != nil {
+		return err
+	}
+	if h.obj.Quarantined(tags) {
+		return errors.GetAPIErrorWithError(errors.ErrAccessDenied, errQuarantineRelease)
+	}
+
+	return nil
+}
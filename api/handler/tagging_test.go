@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,3 +46,87 @@ func TestTagsValidity(t *testing.T) {
 		}
 	}
 }
+
+func TestReadTagSetNamespace(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+	}{
+		{
+			name: "with namespace",
+			body: `<Tagging xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><TagSet><Tag><Key>key</Key><Value>val</Value></Tag></TagSet></Tagging>`,
+		},
+		{
+			name: "without namespace",
+			body: `<Tagging><TagSet><Tag><Key>key</Key><Value>val</Value></Tag></TagSet></Tagging>`,
+		},
+		{
+			name: "different order",
+			body: `<?xml version="1.0" encoding="UTF-8"?><Tagging><TagSet><Tag><Value>val</Value><Key>key</Key></Tag></TagSet></Tagging>`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tagSet, err := readTagSet(strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"key": "val"}, tagSet)
+		})
+	}
+
+	_, err := readTagSet(strings.NewReader(`<Tagging xmlns="http://example.com/"><TagSet></TagSet></Tagging>`))
+	require.Error(t, err)
+}
+
+func TestTaggingHeader(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-tagging-header", "object-for-tagging-header"
+	createTestBucket(hc, bktName)
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.AmzTagging, "key=val&key=other")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.AmzTagging, "key=val&k%20e%20y=v+a+l")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, []Tag{{Key: "k e y", Value: "v a l"}, {Key: "key", Value: "val"}},
+		getObjectTagging(t, hc, bktName, objName, emptyVersion).TagSet)
+
+	multipartInfo := createMultipartUpload(hc, bktName, objName, map[string]string{api.AmzTagging: "multipart=true"})
+	etag, _ := uploadPart(hc, bktName, objName, multipartInfo.UploadID, 1, 10)
+	w = completeMultipartUploadRequest(hc, bktName, objName, multipartInfo.UploadID, etag, "")
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, []Tag{{Key: "multipart", Value: "true"}}, getObjectTagging(t, hc, bktName, objName, emptyVersion).TagSet)
+}
+
+func TestTaggingCountHeader(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-tagging-count", "object-for-tagging-count"
+	createTestBucket(hc, bktName)
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader("content"))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	checkTaggingCount(hc, bktName, objName, "")
+
+	w, r = prepareTestPayloadRequest(hc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.AmzTagging, "key=val&other=val")
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	checkTaggingCount(hc, bktName, objName, "2")
+}
+
+func checkTaggingCount(hc *handlerContext, bktName, objName, count string) {
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().HeadObjectHandler(w, r)
+	assertStatus(hc.t, w, http.StatusOK)
+	require.Equal(hc.t, count, w.Header().Get(api.AmzTaggingCount))
+
+	w, r = prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().GetObjectHandler(w, r)
+	assertStatus(hc.t, w, http.StatusOK)
+	require.Equal(hc.t, count, w.Header().Get(api.AmzTaggingCount))
+}
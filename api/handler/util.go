@@ -16,6 +16,13 @@ import (
 )
 
 func (h *handler) logAndSendError(w http.ResponseWriter, logText string, reqInfo *api.ReqInfo, err error, additional ...zap.Field) {
+	var deleteMarkerErr *layer.DeleteMarkerError
+	if errorsStd.As(err, &deleteMarkerErr) {
+		w.Header().Set(api.AmzDeleteMarker, strconv.FormatBool(true))
+		w.Header().Set(api.AmzVersionID, deleteMarkerErr.VersionID)
+		w.Header().Set(api.LastModified, deleteMarkerErr.LastModified.UTC().Format(http.TimeFormat))
+	}
+
 	code := api.WriteErrorResponse(w, reqInfo, transformToS3Error(err))
 	fields := []zap.Field{
 		zap.Int("status", code),
@@ -34,8 +41,14 @@ func transformToS3Error(err error) error {
 		return err
 	}
 
+	var deleteMarkerErr *layer.DeleteMarkerError
+	if errorsStd.As(err, &deleteMarkerErr) {
+		return errors.GetAPIError(errors.ErrMethodNotAllowed)
+	}
+
 	if errorsStd.Is(err, layer.ErrAccessDenied) ||
-		errorsStd.Is(err, layer.ErrNodeAccessDenied) {
+		errorsStd.Is(err, layer.ErrNodeAccessDenied) ||
+		errorsStd.Is(err, layer.ErrObjectLocked) {
 		return errors.GetAPIError(errors.ErrAccessDenied)
 	}
 
@@ -1,8 +1,9 @@
 package handler
 
 import (
-	"encoding/xml"
+	stderrors "errors"
 	"net/http"
+	"strings"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
@@ -10,11 +11,17 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 )
 
+var (
+	errMFARequired       = stderrors.New("MFA authentication must be used for this request")
+	errMFAMalformed      = stderrors.New("x-amz-mfa header must contain the serial number and the code separated by space")
+	errMFANotImplemented = stderrors.New("MFA delete isn't configured on the gateway")
+)
+
 func (h *handler) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
 
 	configuration := new(VersioningConfiguration)
-	if err := xml.NewDecoder(r.Body).Decode(configuration); err != nil {
+	if err := api.NewXMLDecoder(r.Body).Decode(configuration); err != nil {
 		h.logAndSendError(w, "couldn't decode versioning configuration", reqInfo, errors.GetAPIError(errors.ErrIllegalVersioningConfigurationException))
 		return
 	}
@@ -36,9 +43,25 @@ func (h *handler) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if configuration.MfaDelete != "" && configuration.MfaDelete != data.MFADeleteEnabled && configuration.MfaDelete != data.MFADeleteDisabled {
+		h.logAndSendError(w, "invalid mfa delete state", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	// MFA is required to change MFA delete state and to change versioning of the bucket with MFA delete
+	if configuration.MfaDelete != "" || settings.MFADeleteEnabled() {
+		if err = h.checkMFA(r, bktInfo); err != nil {
+			h.logAndSendError(w, "mfa check failed", reqInfo, err)
+			return
+		}
+	}
+
 	// settings pointer is stored in the cache, so modify a copy of the settings
 	newSettings := *settings
 	newSettings.Versioning = configuration.Status
+	if configuration.MfaDelete != "" {
+		newSettings.MFADelete = configuration.MfaDelete
+	}
 
 	p := &layer.PutSettingsParams{
 		BktInfo:  bktInfo,
@@ -77,10 +100,35 @@ func (h *handler) GetBucketVersioningHandler(w http.ResponseWriter, r *http.Requ
 }
 
 func formVersioningConfiguration(settings *data.BucketSettings) *VersioningConfiguration {
-	res := &VersioningConfiguration{}
+	res := &VersioningConfiguration{
+		MfaDelete: settings.MFADelete,
+	}
 	if !settings.Unversioned() {
 		res.Status = settings.Versioning
 	}
 
 	return res
 }
+
+// checkMFA validates the code of MFA device from x-amz-mfa header, the device must belong to the bucket owner.
+func (h *handler) checkMFA(r *http.Request, bktInfo *data.BucketInfo) error {
+	if h.cfg.MFA == nil {
+		return errors.GetAPIErrorWithError(errors.ErrNotImplemented, errMFANotImplemented)
+	}
+
+	header := r.Header.Get(api.AmzMFA)
+	if header == "" {
+		return errors.GetAPIErrorWithError(errors.ErrAccessDenied, errMFARequired)
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) != 2 {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidRequest, errMFAMalformed)
+	}
+
+	if err := h.cfg.MFA.Validate(bktInfo.Owner, fields[0], fields[1]); err != nil {
+		return errors.GetAPIErrorWithError(errors.ErrAccessDenied, err)
+	}
+
+	return nil
+}
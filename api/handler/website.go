@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
+	"go.uber.org/zap"
+)
+
+func (h *handler) GetBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketWebsite(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get website configuration", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, conf); err != nil {
+		h.logAndSendError(w, "could not encode website configuration to response", reqInfo, err)
+		return
+	}
+}
+
+func (h *handler) PutBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	conf := new(data.WebsiteConfiguration)
+	if err = api.NewXMLDecoder(r.Body).Decode(conf); err != nil {
+		h.logAndSendError(w, "could not parse website configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+		return
+	}
+
+	p := &layer.PutBucketWebsiteParams{
+		BktInfo:       bktInfo,
+		Configuration: conf,
+		CopiesNumber:  h.cfg.CopiesNumber,
+	}
+
+	if err = h.obj.PutBucketWebsite(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not put website configuration", reqInfo, err)
+		return
+	}
+
+	api.WriteSuccessResponseHeadersOnly(w)
+}
+
+func (h *handler) DeleteBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.obj.DeleteBucketWebsite(r.Context(), bktInfo); err != nil {
+		h.logAndSendError(w, "could not delete website configuration", reqInfo, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WebsiteHandler serves the website of the bucket configured for website hosting. Requests are anonymous,
+// so only objects readable by everyone are served. Requests for directories get the index document,
+// 4XX errors get the error document, routing rules redirect requests before and after the object is read.
+func (h *handler) WebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.logAndSendError(w, "method isn't supported by website", reqInfo, errors.GetAPIError(errors.ErrMethodNotAllowed))
+		return
+	}
+
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	if err = h.checkFeature(r.Context(), bktInfo, features.Website); err != nil {
+		h.logAndSendError(w, "feature is disabled", reqInfo, err)
+		return
+	}
+
+	conf, err := h.obj.GetBucketWebsite(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get website configuration", reqInfo, err)
+		return
+	}
+
+	if redirect := conf.RedirectAllRequestsTo; redirect != nil {
+		websiteRedirect(w, r, &data.WebsiteRedirect{HostName: redirect.HostName, Protocol: redirect.Protocol}, nil, reqInfo.ObjectName)
+		return
+	}
+
+	if rule := matchRoutingRule(conf.RoutingRules, reqInfo.ObjectName, 0); rule != nil {
+		websiteRedirect(w, r, &rule.Redirect, rule.Condition, reqInfo.ObjectName)
+		return
+	}
+
+	key := reqInfo.ObjectName
+	if key == "" || strings.HasSuffix(key, "/") {
+		key += conf.IndexDocument.Suffix
+	}
+
+	objInfo, err := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: key})
+	if err != nil {
+		if errors.IsS3Error(transformToS3Error(err), errors.ErrNoSuchKey) && key == reqInfo.ObjectName {
+			// the directory requested without the trailing slash is redirected to its index document
+			indexKey := key + "/" + conf.IndexDocument.Suffix
+			if _, indexErr := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: indexKey}); indexErr == nil {
+				http.Redirect(w, r, "/"+key+"/", http.StatusFound)
+				return
+			}
+		}
+
+		if key != reqInfo.ObjectName && errors.IsS3Error(transformToS3Error(err), errors.ErrNoSuchKey) && h.htmlListingRequested(r) {
+			// the directory without the index document
+			h.writeHTMLListing(w, r, bktInfo, reqInfo.ObjectName)
+			return
+		}
+
+		h.websiteError(w, r, bktInfo, conf, err)
+		return
+	}
+
+	h.serveWebsiteObject(w, r, bktInfo, objInfo, http.StatusOK)
+}
+
+// websiteError redirects the request according to the routing rule matching the error or responds
+// with the error document of the website.
+func (h *handler) websiteError(w http.ResponseWriter, r *http.Request, bktInfo *data.BucketInfo, conf *data.WebsiteConfiguration, err error) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	status := http.StatusInternalServerError
+	if s3Err, ok := transformToS3Error(err).(errors.Error); ok {
+		status = s3Err.HTTPStatusCode
+	}
+
+	if rule := matchRoutingRule(conf.RoutingRules, reqInfo.ObjectName, status); rule != nil {
+		websiteRedirect(w, r, &rule.Redirect, rule.Condition, reqInfo.ObjectName)
+		return
+	}
+
+	if conf.ErrorDocument == nil || status < http.StatusBadRequest || status >= http.StatusInternalServerError {
+		h.logAndSendError(w, "could not get website object", reqInfo, err)
+		return
+	}
+
+	objInfo, docErr := h.obj.GetObjectInfo(r.Context(), &layer.HeadObjectParams{BktInfo: bktInfo, Object: conf.ErrorDocument.Key})
+	if docErr != nil {
+		h.logAndSendError(w, "could not get website object", reqInfo, err, zap.NamedError("error document", docErr))
+		return
+	}
+
+	h.serveWebsiteObject(w, r, bktInfo, objInfo, status)
+}
+
+// serveWebsiteObject writes the object with the status, the range is served for successful requests only.
+func (h *handler) serveWebsiteObject(w http.ResponseWriter, r *http.Request, bktInfo *data.BucketInfo, info *data.ObjectInfo, status int) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	var enc encryption.Params
+	encInfo := layer.FormEncryptionInfo(info.Headers)
+	if err := enc.MatchObjectEncryption(encInfo); err != nil {
+		h.logAndSendError(w, "object encrypted with customer key can't be served by website", reqInfo,
+			errors.GetAPIError(errors.ErrAccessDenied), zap.Error(err))
+		return
+	}
+
+	if err := h.obj.CheckQuarantine(r.Context(), bktInfo, info); err != nil {
+		h.logAndSendError(w, "could not check object quarantine", reqInfo, err)
+		return
+	}
+
+	fullSize := info.Size
+	if encInfo.Enabled {
+		var err error
+		if fullSize, err = strconv.ParseInt(info.Headers[layer.AttributeDecryptedSize], 10, 64); err != nil {
+			h.logAndSendError(w, "invalid decrypted size header", reqInfo, errors.GetAPIError(errors.ErrBadRequest))
+			return
+		}
+	}
+
+	var params *layer.RangeParams
+	if status == http.StatusOK {
+		var err error
+		if params, err = fetchRangeHeader(r.Header, uint64(fullSize)); err != nil {
+			h.logAndSendError(w, "could not parse range header", reqInfo, err)
+			return
+		}
+	}
+
+	if len(info.ContentType) > 0 {
+		w.Header().Set(api.ContentType, info.ContentType)
+	}
+	w.Header().Set(api.LastModified, info.Created.UTC().Format(http.TimeFormat))
+	w.Header().Set(api.ETag, info.HashSum)
+	w.Header().Set(api.ContentLength, strconv.FormatInt(fullSize, 10))
+	if cacheControl := info.Headers[api.CacheControl]; cacheControl != "" {
+		w.Header().Set(api.CacheControl, cacheControl)
+	}
+
+	if params != nil {
+		writeRangeHeaders(w, params, fullSize)
+	} else {
+		w.WriteHeader(status)
+	}
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	getParams := &layer.GetObjectParams{
+		ObjectInfo: info,
+		Writer:     w,
+		Range:      params,
+		BucketInfo: bktInfo,
+		Encryption: enc,
+	}
+	if err := h.obj.GetObject(r.Context(), getParams); err != nil {
+		h.log.Error("could not get website object", zap.String("request_id", reqInfo.RequestID),
+			zap.String("bucket", bktInfo.Name), zap.String("object", info.Name), zap.Error(err))
+	}
+}
+
+// matchRoutingRule returns the first routing rule matching the key and the error status. Zero status
+// matches rules without the error code condition, they are checked before the object is read.
+func matchRoutingRule(rules []data.WebsiteRoutingRule, key string, status int) *data.WebsiteRoutingRule {
+	for i := range rules {
+		cond := rules[i].Condition
+		if cond == nil {
+			if status == 0 {
+				return &rules[i]
+			}
+			continue
+		}
+
+		if len(cond.HTTPErrorCodeReturnedEquals) == 0 {
+			if status != 0 {
+				continue
+			}
+		} else if status == 0 || cond.HTTPErrorCodeReturnedEquals != strconv.Itoa(status) {
+			continue
+		}
+
+		if strings.HasPrefix(key, cond.KeyPrefixEquals) {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
+
+// websiteRedirect redirects the request for the key, empty parts of the redirect keep the values of the request.
+func websiteRedirect(w http.ResponseWriter, r *http.Request, redirect *data.WebsiteRedirect, cond *data.WebsiteCondition, key string) {
+	protocol := redirect.Protocol
+	if protocol == "" {
+		protocol = "http"
+		if r.TLS != nil {
+			protocol = "https"
+		}
+	}
+
+	host := redirect.HostName
+	if host == "" {
+		host = r.Host
+	}
+
+	if redirect.ReplaceKeyWith != "" {
+		key = redirect.ReplaceKeyWith
+	} else if redirect.ReplaceKeyPrefixWith != nil {
+		var prefix string
+		if cond != nil {
+			prefix = cond.KeyPrefixEquals
+		}
+		key = *redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	}
+
+	code := http.StatusMovedPermanently
+	if redirect.HTTPRedirectCode != "" {
+		code, _ = strconv.Atoi(redirect.HTTPRedirectCode)
+	}
+
+	w.Header().Set(api.Location, protocol+"://"+host+"/"+key)
+	w.WriteHeader(code)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketWebsite(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	bktName := "bucket-for-website"
+	createTestBucket(hc, bktName)
+
+	putObjectContent(hc, bktName, "index.html", "root index")
+	putObjectContent(hc, bktName, "docs/index.html", "docs index")
+	putObjectContent(hc, bktName, "error.html", "error page")
+
+	serveWebsite(hc, bktName, "", http.StatusNotFound)
+
+	putBucketWebsite(hc, bktName, &data.WebsiteConfiguration{IndexDocument: &data.WebsiteIndexDocument{Suffix: "docs/index.html"}}, http.StatusBadRequest)
+	putBucketWebsite(hc, bktName, &data.WebsiteConfiguration{IndexDocument: &data.WebsiteIndexDocument{Suffix: ".."}}, http.StatusBadRequest)
+	putBucketWebsite(hc, bktName, &data.WebsiteConfiguration{
+		RedirectAllRequestsTo: &data.WebsiteRedirectAllRequestsTo{HostName: "example.com"},
+		IndexDocument:         &data.WebsiteIndexDocument{Suffix: "index.html"},
+	}, http.StatusBadRequest)
+
+	newPrefix := "new/"
+	conf := &data.WebsiteConfiguration{
+		IndexDocument: &data.WebsiteIndexDocument{Suffix: "index.html"},
+		ErrorDocument: &data.WebsiteErrorDocument{Key: "error.html"},
+		RoutingRules: []data.WebsiteRoutingRule{{
+			Condition: &data.WebsiteCondition{KeyPrefixEquals: "old/"},
+			Redirect:  data.WebsiteRedirect{ReplaceKeyPrefixWith: &newPrefix},
+		}, {
+			Condition: &data.WebsiteCondition{KeyPrefixEquals: "moved/", HTTPErrorCodeReturnedEquals: "404"},
+			Redirect:  data.WebsiteRedirect{HostName: "example.com", Protocol: "https", HTTPRedirectCode: "302"},
+		}},
+	}
+	putBucketWebsite(hc, bktName, conf, http.StatusOK)
+	require.Equal(t, conf.RoutingRules, getBucketWebsite(hc, bktName).RoutingRules)
+
+	w := serveWebsite(hc, bktName, "", http.StatusOK)
+	require.Equal(t, "root index", w.Body.String())
+	w = serveWebsite(hc, bktName, "docs/", http.StatusOK)
+	require.Equal(t, "docs index", w.Body.String())
+	w = serveWebsite(hc, bktName, "docs", http.StatusFound)
+	require.Equal(t, "/docs/", w.Header().Get(api.Location))
+	w = serveWebsite(hc, bktName, "missing", http.StatusNotFound)
+	require.Equal(t, "error page", w.Body.String())
+
+	w = serveWebsite(hc, bktName, "old/page.html", http.StatusMovedPermanently)
+	require.Equal(t, "http://example.org/new/page.html", w.Header().Get(api.Location))
+	w = serveWebsite(hc, bktName, "moved/page.html", http.StatusFound)
+	require.Equal(t, "https://example.com/moved/page.html", w.Header().Get(api.Location))
+
+	putBucketWebsite(hc, bktName, &data.WebsiteConfiguration{
+		RedirectAllRequestsTo: &data.WebsiteRedirectAllRequestsTo{HostName: "example.com"},
+	}, http.StatusOK)
+	w = serveWebsite(hc, bktName, "docs/", http.StatusMovedPermanently)
+	require.Equal(t, "http://example.com/docs/", w.Header().Get(api.Location))
+
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().DeleteBucketWebsiteHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	serveWebsite(hc, bktName, "", http.StatusNotFound)
+}
+
+func putBucketWebsite(hc *handlerContext, bktName string, conf *data.WebsiteConfiguration, status int) {
+	w, r := prepareTestRequest(hc, bktName, "", conf)
+	hc.Handler().PutBucketWebsiteHandler(w, r)
+	assertStatus(hc.t, w, status)
+}
+
+func getBucketWebsite(hc *handlerContext, bktName string) *data.WebsiteConfiguration {
+	w, r := prepareTestRequest(hc, bktName, "", nil)
+	hc.Handler().GetBucketWebsiteHandler(w, r)
+
+	conf := &data.WebsiteConfiguration{}
+	readResponse(hc.t, w, http.StatusOK, conf)
+	return conf
+}
+
+func serveWebsite(hc *handlerContext, bktName, objName string, status int) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "http://example.org/"+objName, nil)
+
+	reqInfo := api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName, Object: objName})
+	r = r.WithContext(api.SetReqInfo(hc.Context(), reqInfo))
+
+	hc.Handler().WebsiteHandler(w, r)
+	assertStatus(hc.t, w, status)
+	return w
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// headResponseWriter sends headers and status of the response without its body.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Flush implements http.Flusher.
+func (w headResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// attachHeadSubresources adds HEAD routes for the bucket sub-resources registered as GET routes in the bucket router.
+// HEAD requests are served by the handlers of GET routes without the response body, route names are the same,
+// so bucket policies and disabled operations apply to them as to GET requests. Object routes and routes with
+// variable query values are skipped. It must be called before HeadBucket route is added.
+func attachHeadSubresources(bucket *mux.Router) {
+	var routes []*mux.Route
+	_ = bucket.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if methods, err := route.GetMethods(); err != nil || len(methods) != 1 || methods[0] != http.MethodGet {
+			return nil
+		}
+		if path, err := route.GetPathTemplate(); err == nil && strings.Contains(path, "{object") {
+			return nil
+		}
+		queries, err := route.GetQueriesTemplates()
+		if err != nil || len(queries) == 0 {
+			return nil
+		}
+		for _, query := range queries {
+			if strings.Contains(query, "{") {
+				return nil
+			}
+		}
+		routes = append(routes, route)
+		return nil
+	})
+
+	for _, route := range routes {
+		queries, _ := route.GetQueriesTemplates()
+		pairs := make([]string, 0, 2*len(queries))
+		for _, query := range queries {
+			key, value := query, ""
+			if i := strings.IndexByte(query, '='); i >= 0 {
+				key, value = query[:i], query[i+1:]
+			}
+			pairs = append(pairs, key, value)
+		}
+
+		handler := route.GetHandler()
+		bucket.Methods(http.MethodHead).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(headResponseWriter{ResponseWriter: w}, r)
+		}).Queries(pairs...).Name(route.GetName())
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachHeadSubresources(t *testing.T) {
+	r := mux.NewRouter()
+	bucket := r.PathPrefix("/{bucket}").Subrouter()
+
+	route := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Route", mux.CurrentRoute(r).GetName())
+			_, _ = w.Write([]byte(name))
+		}
+	}
+
+	bucket.Methods(http.MethodHead).Path("/{object:.+}").HandlerFunc(route("HeadObject")).Name("HeadObject")
+	bucket.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(route("GetObjectTagging")).Queries("tagging", "").Name("GetObjectTagging")
+	bucket.Methods(http.MethodGet).HandlerFunc(route("GetBucketVersioning")).Queries("versioning", "").Name("GetBucketVersioning")
+	bucket.Methods(http.MethodGet).HandlerFunc(route("ListObjectsV2")).Queries("list-type", "2").Name("ListObjectsV2")
+	bucket.Methods(http.MethodGet).HandlerFunc(route("ListenBucketNotification")).Queries("events", "{events:.*}").Name("ListenBucketNotification")
+	bucket.Methods(http.MethodGet).HandlerFunc(route("ListObjectsV1")).Name("ListObjectsV1")
+	attachHeadSubresources(bucket)
+	bucket.Methods(http.MethodHead).HandlerFunc(route("HeadBucket")).Name("HeadBucket")
+
+	for target, name := range map[string]string{
+		"/bucket?versioning":     "GetBucketVersioning",
+		"/bucket?list-type=2":    "ListObjectsV2",
+		"/bucket?events=put":     "HeadBucket",
+		"/bucket":                "HeadBucket",
+		"/bucket/object?tagging": "HeadObject",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, target, nil))
+		require.Equal(t, name, w.Header().Get("X-Route"), target)
+		if name == "HeadBucket" || name == "HeadObject" {
+			require.Equal(t, name, w.Body.String(), target)
+		} else {
+			require.Empty(t, w.Body.String(), target)
+		}
+	}
+}
@@ -13,6 +13,10 @@ const (
 	AmzCopySource             = "X-Amz-Copy-Source"
 	AmzCopySourceRange        = "X-Amz-Copy-Source-Range"
 	AmzDate                   = "X-Amz-Date"
+	AmzReplicationStatus      = "X-Amz-Replication-Status"
+	AmzStorageClass           = "X-Amz-Storage-Class"
+	AmzRestore                = "X-Amz-Restore"
+	AmzExpiration             = "X-Amz-Expiration"
 
 	LastModified       = "Last-Modified"
 	Date               = "Date"
@@ -26,6 +30,7 @@ const (
 	ContentRange       = "Content-Range"
 	Connection         = "Connection"
 	AcceptRanges       = "Accept-Ranges"
+	Accept             = "Accept"
 	AmzBucketRegion    = "X-Amz-Bucket-Region"
 	ServerInfo         = "Server"
 	RetryAfter         = "Retry-After"
@@ -39,6 +44,8 @@ const (
 	IfMatch            = "If-Match"
 	IfNoneMatch        = "If-None-Match"
 
+	AmzIfMatchLastModifiedTime   = "X-Amz-If-Match-Last-Modified-Time"
+	AmzIfMatchSize               = "X-Amz-If-Match-Size"
 	AmzCopyIfModifiedSince       = "X-Amz-Copy-Source-If-Modified-Since"
 	AmzCopyIfUnmodifiedSince     = "X-Amz-Copy-Source-If-Unmodified-Since"
 	AmzCopyIfMatch               = "X-Amz-Copy-Source-If-Match"
@@ -47,6 +54,8 @@ const (
 	AmzGrantFullControl          = "X-Amz-Grant-Full-Control"
 	AmzGrantRead                 = "X-Amz-Grant-Read"
 	AmzGrantWrite                = "X-Amz-Grant-Write"
+	AmzGrantReadACP              = "X-Amz-Grant-Read-Acp"
+	AmzGrantWriteACP             = "X-Amz-Grant-Write-Acp"
 	AmzExpectedBucketOwner       = "X-Amz-Expected-Bucket-Owner"
 	AmzSourceExpectedBucketOwner = "X-Amz-Source-Expected-Bucket-Owner"
 	AmzBucketObjectLockEnabled   = "X-Amz-Bucket-Object-Lock-Enabled"
@@ -54,14 +63,33 @@ const (
 	AmzObjectLockMode            = "X-Amz-Object-Lock-Mode"
 	AmzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
 	AmzBypassGovernanceRetention = "X-Amz-Bypass-Governance-Retention"
+	AmzMFA                       = "X-Amz-Mfa"
 	AmzObjectAttributes          = "X-Amz-Object-Attributes"
 	AmzMaxParts                  = "X-Amz-Max-Parts"
 	AmzPartNumberMarker          = "X-Amz-Part-Number-Marker"
+	AmzRenameSource              = "X-Amz-Rename-Source"
+	AmzRenameSourceIfMatch       = "X-Amz-Rename-Source-If-Match"
+	AmzChecksumAlgorithm         = "X-Amz-Checksum-Algorithm"
+	AmzSdkChecksumAlgorithm      = "X-Amz-Sdk-Checksum-Algorithm"
+	AmzChecksumPrefix            = "X-Amz-Checksum-"
+	AmzChecksumMode              = "X-Amz-Checksum-Mode"
+	AmzContentSha256             = "X-Amz-Content-Sha256"
+	AmzDecodedContentLength      = "X-Amz-Decoded-Content-Length"
+	AmzTrailer                   = "X-Amz-Trailer"
+	AmzRequestPayer              = "X-Amz-Request-Payer"
+	AmzRequestCharged            = "X-Amz-Request-Charged"
+
+	AmzServerSideEncryption            = "x-amz-server-side-encryption"
+	AmzServerSideEncryptionAwsKmsKeyID = "x-amz-server-side-encryption-aws-kms-key-id"
 
 	AmzServerSideEncryptionCustomerAlgorithm = "x-amz-server-side-encryption-customer-algorithm"
 	AmzServerSideEncryptionCustomerKey       = "x-amz-server-side-encryption-customer-key"
 	AmzServerSideEncryptionCustomerKeyMD5    = "x-amz-server-side-encryption-customer-key-MD5"
 
+	AmzCopySourceServerSideEncryptionCustomerAlgorithm = "x-amz-copy-source-server-side-encryption-customer-algorithm"
+	AmzCopySourceServerSideEncryptionCustomerKey       = "x-amz-copy-source-server-side-encryption-customer-key"
+	AmzCopySourceServerSideEncryptionCustomerKeyMD5    = "x-amz-copy-source-server-side-encryption-customer-key-MD5"
+
 	ContainerID = "X-Container-Id"
 
 	AccessControlAllowOrigin      = "Access-Control-Allow-Origin"
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeepAliveWriter starts the successful XML response of the long-running request if its result isn't ready
+// in the interval and writes whitespace to the body periodically, so clients don't close the idle connection.
+// Once the response is started, the status of the result is ignored and errors are written to the body
+// as the Error element, as AWS S3 does for CompleteMultipartUpload.
+type KeepAliveWriter struct {
+	http.ResponseWriter
+
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+
+	mu      sync.Mutex
+	started bool
+}
+
+// keepAliveWhitespace is written to the started response to keep the connection alive.
+var keepAliveWhitespace = []byte(" ")
+
+// NewKeepAliveWriter creates the writer starting the response after the interval, zero interval disables it.
+// Stop must be called before the result is written.
+func NewKeepAliveWriter(w http.ResponseWriter, interval time.Duration) *KeepAliveWriter {
+	k := &KeepAliveWriter{
+		ResponseWriter: w,
+		interval:       interval,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+
+	if interval <= 0 {
+		close(k.done)
+		return k
+	}
+
+	go k.run()
+	return k
+}
+
+func (k *KeepAliveWriter) run() {
+	defer close(k.done)
+
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k.stop:
+			return
+		case <-ticker.C:
+			k.mu.Lock()
+			if !k.started {
+				k.started = true
+				setCommonHeaders(k.ResponseWriter)
+				k.ResponseWriter.Header().Set(hdrContentType, string(MimeXML))
+				k.ResponseWriter.WriteHeader(http.StatusOK)
+				_, _ = k.ResponseWriter.Write(xmlHeader)
+			}
+			_, _ = k.ResponseWriter.Write(keepAliveWhitespace)
+			k.mu.Unlock()
+
+			if f, ok := k.ResponseWriter.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	}
+}
+
+// Stop stops writing of whitespace, the result can be written after it.
+func (k *KeepAliveWriter) Stop() {
+	select {
+	case <-k.stop:
+	default:
+		close(k.stop)
+	}
+	<-k.done
+}
+
+// Started checks whether the response is already started, so headers of the result aren't sent.
+func (k *KeepAliveWriter) Started() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.started
+}
+
+// WriteHeader implements http.ResponseWriter, the status is ignored if the response is started.
+func (k *KeepAliveWriter) WriteHeader(code int) {
+	if k.Started() {
+		return
+	}
+	k.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter, the XML declaration is skipped if the response is started.
+func (k *KeepAliveWriter) Write(p []byte) (int, error) {
+	if !k.Started() {
+		return k.ResponseWriter.Write(p)
+	}
+
+	body := bytes.TrimPrefix(p, xmlHeader)
+	if _, err := k.ResponseWriter.Write(body); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush implements http.Flusher.
+func (k *KeepAliveWriter) Flush() {
+	if f, ok := k.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
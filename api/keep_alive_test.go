@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepAliveWriter(t *testing.T) {
+	type result struct {
+		XMLName xml.Name `xml:"Result"`
+		Key     string   `xml:"Key"`
+	}
+
+	t.Run("result before interval", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		k := NewKeepAliveWriter(w, time.Hour)
+		k.Stop()
+		require.False(t, k.Started())
+
+		WriteErrorResponse(k, &ReqInfo{}, errors.GetAPIError(errors.ErrNoSuchUpload))
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.True(t, strings.HasPrefix(w.Body.String(), xml.Header+"<Error>"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		k := NewKeepAliveWriter(w, 0)
+		k.Stop()
+		require.False(t, k.Started())
+	})
+
+	t.Run("result after interval", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		k := NewKeepAliveWriter(w, 10*time.Millisecond)
+		require.Eventually(t, k.Started, time.Second, 5*time.Millisecond)
+		time.Sleep(30 * time.Millisecond)
+		k.Stop()
+
+		k.Header().Set(AmzVersionID, "version")
+		require.NoError(t, EncodeToResponse(k, &result{Key: "key"}))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Result().Header.Get(AmzVersionID))
+
+		body := w.Body.String()
+		require.True(t, strings.HasPrefix(body, xml.Header+" "))
+		require.Equal(t, 1, strings.Count(body, xml.Header))
+
+		var res result
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &res))
+		require.Equal(t, "key", res.Key)
+	})
+
+	t.Run("error after interval", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		k := NewKeepAliveWriter(w, 10*time.Millisecond)
+		require.Eventually(t, k.Started, time.Second, 5*time.Millisecond)
+		k.Stop()
+
+		WriteErrorResponse(k, &ReqInfo{}, errors.GetAPIError(errors.ErrInvalidPart))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res ErrorResponse
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &res))
+		require.Equal(t, "InvalidPart", res.Code)
+	})
+}
@@ -0,0 +1,382 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// PutBucketAnalyticsParams stores PutBucketAnalyticsConfiguration request parameters.
+type PutBucketAnalyticsParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.AnalyticsConfiguration
+	CopiesNumber  uint32
+}
+
+// analyticsAgeGroup is a group of objects by age in days the storage class analysis is exported for.
+type analyticsAgeGroup struct {
+	name string
+	// maxDays is the age of the oldest object in the group, the last group has no limit.
+	maxDays int
+}
+
+const (
+	// analyticsMaxConfigurations limits the number of analytics configurations of a bucket as AWS S3 does.
+	analyticsMaxConfigurations = 1000
+	// analyticsMaxIDLength limits the length of analytics configuration id.
+	analyticsMaxIDLength = 64
+
+	analyticsDateLayout   = "2006-01-02"
+	analyticsAllAgesGroup = "ALL"
+)
+
+var (
+	// analyticsAgeGroups are age groups of the storage class analysis as AWS S3 forms them.
+	analyticsAgeGroups = []analyticsAgeGroup{
+		{"000-014", 14}, {"015-029", 29}, {"030-044", 44}, {"045-059", 59}, {"060-074", 74}, {"075-089", 89},
+		{"090-119", 119}, {"120-149", 149}, {"150-179", 179}, {"180-364", 364}, {"365-729", 729}, {"730+", -1},
+	}
+
+	// analyticsExportHeader is the header of the storage class analysis export of V_1 schema.
+	analyticsExportHeader = []string{"Date", "ConfigId", "Filter", "StorageClass", "ObjectAge", "ObjectCount",
+		"DataUploaded_MB", "Storage_MB", "DataRetrieved_MB", "GetRequestCount", "CumulativeAccessRatio",
+		"ObjectAgeForSIATransition", "RecommendedObjectAgeForSIATransition"}
+)
+
+// PutBucketAnalyticsConfiguration adds the analytics configuration to the bucket or replaces
+// the configuration with the same id.
+func (n *layer) PutBucketAnalyticsConfiguration(ctx context.Context, p *PutBucketAnalyticsParams) error {
+	if err := checkAnalytics(p.Configuration); err != nil {
+		return err
+	}
+
+	configurations, err := n.getAnalyticsConfigurations(ctx, p.BktInfo)
+	if err != nil {
+		return err
+	}
+
+	newConfigurations := &data.AnalyticsConfigurations{
+		Configurations: make([]data.AnalyticsConfiguration, 0, len(configurations.Configurations)+1),
+	}
+	var replaced bool
+	for _, conf := range configurations.Configurations {
+		if conf.ID == p.Configuration.ID {
+			conf, replaced = *p.Configuration, true
+		}
+		newConfigurations.Configurations = append(newConfigurations.Configurations, conf)
+	}
+	if !replaced {
+		if len(newConfigurations.Configurations) >= analyticsMaxConfigurations {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+				fmt.Errorf("bucket can't have more than %d analytics configurations", analyticsMaxConfigurations))
+		}
+		newConfigurations.Configurations = append(newConfigurations.Configurations, *p.Configuration)
+	}
+
+	sort.Slice(newConfigurations.Configurations, func(i, j int) bool {
+		return newConfigurations.Configurations[i].ID < newConfigurations.Configurations[j].ID
+	})
+
+	return n.putAnalyticsConfigurations(ctx, p.BktInfo, configurations, newConfigurations, p.CopiesNumber)
+}
+
+// GetBucketAnalyticsConfiguration returns the analytics configuration of the bucket with the id.
+func (n *layer) GetBucketAnalyticsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) (*data.AnalyticsConfiguration, error) {
+	configurations, err := n.getAnalyticsConfigurations(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range configurations.Configurations {
+		if configurations.Configurations[i].ID == id {
+			return &configurations.Configurations[i], nil
+		}
+	}
+
+	return nil, errors.GetAPIError(errors.ErrNoSuchConfiguration)
+}
+
+// ListBucketAnalyticsConfigurations returns all analytics configurations of the bucket sorted by id.
+func (n *layer) ListBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) ([]data.AnalyticsConfiguration, error) {
+	configurations, err := n.getAnalyticsConfigurations(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return configurations.Configurations, nil
+}
+
+// DeleteBucketAnalyticsConfiguration removes the analytics configuration of the bucket with the id,
+// exports written by the configuration are kept.
+func (n *layer) DeleteBucketAnalyticsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) error {
+	configurations, err := n.getAnalyticsConfigurations(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+
+	newConfigurations := &data.AnalyticsConfigurations{}
+	for _, conf := range configurations.Configurations {
+		if conf.ID != id {
+			newConfigurations.Configurations = append(newConfigurations.Configurations, conf)
+		}
+	}
+	if len(newConfigurations.Configurations) == len(configurations.Configurations) {
+		return errors.GetAPIError(errors.ErrNoSuchConfiguration)
+	}
+
+	return n.putAnalyticsConfigurations(ctx, bktInfo, configurations, newConfigurations, 0)
+}
+
+// getAnalyticsConfigurations returns analytics configurations of the bucket, the bucket without
+// configurations gets the empty list.
+func (n *layer) getAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (*data.AnalyticsConfigurations, error) {
+	owner := n.Owner(ctx)
+	if configurations := n.cache.GetAnalyticsConfigurations(owner, bktInfo); configurations != nil {
+		return configurations, nil
+	}
+
+	configurations := &data.AnalyticsConfigurations{}
+	objID, err := n.treeService.GetBucketAnalyticsConfigurations(ctx, bktInfo)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		obj, err := n.objectGet(ctx, bktInfo, objID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = xml.Unmarshal(obj.Payload(), configurations); err != nil {
+			return nil, fmt.Errorf("unmarshal analytics configurations: %w", err)
+		}
+	}
+
+	n.cache.PutAnalyticsConfigurations(owner, bktInfo, configurations)
+
+	return configurations, nil
+}
+
+// putAnalyticsConfigurations saves analytics configurations of the bucket as a system object,
+// the object is removed if there are no configurations.
+func (n *layer) putAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo, prev, configurations *data.AnalyticsConfigurations, copiesNumber uint32) error {
+	var prevValue []byte
+	if len(prev.Configurations) != 0 {
+		var err error
+		if prevValue, err = xml.Marshal(prev); err != nil {
+			n.log.Warn("couldn't marshal previous bucket analytics configurations", zap.Error(err))
+		}
+	}
+
+	var (
+		objIDToDelete oid.ID
+		err           error
+	)
+	if len(configurations.Configurations) == 0 {
+		objIDToDelete, err = n.treeService.DeleteBucketAnalyticsConfigurations(ctx, bktInfo)
+	} else {
+		var confXML []byte
+		if confXML, err = xml.Marshal(configurations); err != nil {
+			return fmt.Errorf("marshal analytics configurations: %w", err)
+		}
+
+		prm := PrmObjectCreate{
+			Container:    bktInfo.CID,
+			Creator:      bktInfo.Owner,
+			Payload:      bytes.NewReader(confXML),
+			Filepath:     n.systemObjectName(bktInfo.AnalyticsConfigurationObjectName()),
+			CreationTime: TimeNow(ctx),
+			CopiesNumber: copiesNumber,
+		}
+
+		var objID oid.ID
+		if objID, _, err = n.objectPutAndHash(ctx, prm, bktInfo); err != nil {
+			return fmt.Errorf("put system object: %w", err)
+		}
+
+		objIDToDelete, err = n.treeService.PutBucketAnalyticsConfigurations(ctx, bktInfo, objID)
+	}
+
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete analytics configurations object", zap.Error(err),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	n.cache.PutAnalyticsConfigurations(n.Owner(ctx), bktInfo, configurations)
+	return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypeAnalytics, prevValue, copiesNumber)
+}
+
+func checkAnalytics(conf *data.AnalyticsConfiguration) error {
+	if len(conf.ID) == 0 || len(conf.ID) > analyticsMaxIDLength {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid analytics id '%s'", conf.ID))
+	}
+	for _, r := range conf.ID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid analytics id '%s'", conf.ID))
+		}
+	}
+
+	if conf.Filter != nil {
+		if conf.Filter.Tag != nil || conf.Filter.And != nil && len(conf.Filter.And.Tags) != 0 {
+			return errors.GetAPIErrorWithError(errors.ErrNotImplemented, errorsStd.New("analytics filter by tags isn't supported"))
+		}
+		if conf.Filter.And != nil && conf.Filter.Prefix != "" {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+	}
+
+	export := conf.StorageClassAnalysis.DataExport
+	if export == nil {
+		return nil
+	}
+	if export.OutputSchemaVersion != data.AnalyticsSchemaV1 || export.Destination.S3BucketDestination.Format != data.AnalyticsFormatCSV {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+	dst := export.Destination.S3BucketDestination.Bucket
+	if !strings.HasPrefix(dst, inventoryDestinationARNPrefix) || len(dst) == len(inventoryDestinationARNPrefix) {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid destination bucket '%s'", dst))
+	}
+
+	return nil
+}
+
+// WriteAnalyticsExports writes daily storage class analysis exports of analytics configurations of the bucket
+// and returns the number of written exports. The export of the day is written once: it's skipped if it
+// exists in the destination bucket.
+func (n *layer) WriteAnalyticsExports(ctx context.Context, bktInfo *data.BucketInfo) (int, error) {
+	configurations, err := n.getAnalyticsConfigurations(ctx, bktInfo)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get analytics configurations: %w", err)
+	}
+
+	var written int
+	now := TimeNow(ctx)
+	for i := range configurations.Configurations {
+		conf := &configurations.Configurations[i]
+		if conf.StorageClassAnalysis.DataExport == nil {
+			continue
+		}
+
+		ok, err := n.writeAnalyticsExport(ctx, bktInfo, conf, now)
+		if err != nil {
+			return written, fmt.Errorf("couldn't write analytics export '%s': %w", conf.ID, err)
+		}
+		if ok {
+			written++
+		}
+	}
+
+	return written, nil
+}
+
+// writeAnalyticsExport writes the number and the size of the latest object versions by age groups.
+// The gateway doesn't track reads of objects, so retrieval columns and recommendations are empty.
+func (n *layer) writeAnalyticsExport(ctx context.Context, bktInfo *data.BucketInfo, conf *data.AnalyticsConfiguration, now time.Time) (bool, error) {
+	dst := conf.StorageClassAnalysis.DataExport.Destination.S3BucketDestination
+	dstBktInfo, err := n.GetBucketInfo(ctx, strings.TrimPrefix(dst.Bucket, inventoryDestinationARNPrefix))
+	if err != nil {
+		return false, fmt.Errorf("get destination bucket: %w", err)
+	}
+
+	date := now.UTC().Format(analyticsDateLayout)
+	key := analyticsExportKey(bktInfo.Name, conf, date)
+	_, err = n.GetObjectInfo(ctx, &HeadObjectParams{BktInfo: dstBktInfo, Object: key})
+	if err == nil {
+		return false, nil
+	}
+	if !errors.IsS3Error(err, errors.ErrNoSuchKey) {
+		return false, fmt.Errorf("get export: %w", err)
+	}
+
+	counts := make([]int64, len(analyticsAgeGroups))
+	sizes := make([]int64, len(analyticsAgeGroups))
+	var uploaded int64
+	err = n.scanObjects(ctx, &scanObjectsParams{BktInfo: bktInfo, Prefix: conf.FilterPrefix()}, func(obj *data.ExtendedObjectInfo) error {
+		if obj.ObjectInfo.IsDeleteMarker {
+			return nil
+		}
+
+		age := now.Sub(obj.ObjectInfo.Created)
+		if age < 24*time.Hour {
+			uploaded += obj.ObjectInfo.Size
+		}
+
+		days := int(age / (24 * time.Hour))
+		for i, group := range analyticsAgeGroups {
+			if group.maxDays < 0 || days <= group.maxDays {
+				counts[i]++
+				sizes[i] += obj.ObjectInfo.Size
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("scan objects: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	row := func(group string, count, size, uploaded int64) []string {
+		return []string{date, conf.ID, conf.FilterPrefix(), inventoryStorageClass, group, strconv.FormatInt(count, 10),
+			analyticsMegabytes(uploaded), analyticsMegabytes(size), "", "", "", "", ""}
+	}
+
+	var totalCount, totalSize int64
+	records := [][]string{analyticsExportHeader}
+	for i, group := range analyticsAgeGroups {
+		var groupUploaded int64
+		if i == 0 {
+			groupUploaded = uploaded
+		}
+		records = append(records, row(group.name, counts[i], sizes[i], groupUploaded))
+		totalCount += counts[i]
+		totalSize += sizes[i]
+	}
+	records = append(records, row(analyticsAllAgesGroup, totalCount, totalSize, uploaded))
+
+	if err = w.WriteAll(records); err != nil {
+		return false, fmt.Errorf("encode export: %w", err)
+	}
+
+	if err = n.putInventoryObject(ctx, dstBktInfo, key, buf.Bytes()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// analyticsExportKey returns the key of the daily export in the destination bucket: the destination prefix,
+// the source bucket name, the configuration id and the date.
+func analyticsExportKey(bktName string, conf *data.AnalyticsConfiguration, date string) string {
+	key := bktName + "/" + conf.ID + "/" + date + ".csv"
+	if dstPrefix := conf.StorageClassAnalysis.DataExport.Destination.S3BucketDestination.Prefix; len(dstPrefix) != 0 {
+		key = strings.TrimSuffix(dstPrefix, "/") + "/" + key
+	}
+	return key
+}
+
+func analyticsMegabytes(size int64) string {
+	return strconv.FormatFloat(float64(size)/(1<<20), 'f', 6, 64)
+}
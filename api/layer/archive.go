@@ -0,0 +1,399 @@
+package layer
+
+import (
+	"context"
+	errorsStd "errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// RestoreObjectParams stores RestoreObject request parameters.
+type RestoreObjectParams struct {
+	BktInfo   *data.BucketInfo
+	Object    string
+	VersionID string
+	// Days is a number of days the restored copy is kept.
+	Days int
+}
+
+var errArchivedObjectChanged = errorsStd.New("archived object is changed")
+
+// TransitionObjects moves the latest versions of objects matched by transitions of the bucket lifecycle
+// configuration to archive containers and returns the number of transitioned objects. The archived
+// object is deleted from the bucket container, its payload can be read after restore only.
+// Packed objects aren't transitioned.
+func (n *layer) TransitionObjects(ctx context.Context, bktInfo *data.BucketInfo) (int, error) {
+	conf, err := n.GetBucketLifecycleConfiguration(ctx, bktInfo)
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchLifecycleConfiguration) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("couldn't get lifecycle configuration: %w", err)
+	}
+
+	var transitioned int
+	now := TimeNow(ctx)
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+		if rule.Status != data.LifecycleStatusEnabled || len(rule.Transitions) == 0 {
+			continue
+		}
+
+		prefix, tags := lifecycleRuleFilter(rule)
+		err = n.scanObjects(ctx, &scanObjectsParams{BktInfo: bktInfo, Prefix: prefix}, func(obj *data.ExtendedObjectInfo) error {
+			nodeVersion := obj.NodeVersion
+			if nodeVersion.IsDeleteMarker() || nodeVersion.Archive != nil || nodeVersion.Pack != nil ||
+				!strings.HasPrefix(nodeVersion.FilePath, prefix) {
+				return nil
+			}
+
+			storageClass, err := n.lifecycleStorageClass(ctx, bktInfo, rule.Transitions, tags, nodeVersion, now)
+			if err != nil {
+				n.log.Error("couldn't check object transition", zap.Error(err),
+					zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath))
+				return nil
+			}
+			if len(storageClass) == 0 {
+				return nil
+			}
+
+			err = n.transitionObject(ctx, bktInfo, nodeVersion, storageClass)
+			if errorsStd.Is(err, errArchivedObjectChanged) {
+				return nil
+			}
+			if err != nil {
+				n.log.Error("couldn't transition object", zap.Error(err),
+					zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath),
+					zap.String("storage class", storageClass))
+				return nil
+			}
+			transitioned++
+			return nil
+		})
+		if err != nil {
+			return transitioned, fmt.Errorf("couldn't scan objects of rule '%s': %w", rule.ID, err)
+		}
+	}
+
+	return transitioned, nil
+}
+
+// lifecycleStorageClass returns the storage class of the last due transition of the rule,
+// empty string is returned if no transition is due.
+func (n *layer) lifecycleStorageClass(ctx context.Context, bktInfo *data.BucketInfo, transitions []data.LifecycleTransition,
+	tags []data.LifecycleTag, nodeVersion *data.NodeVersion, now time.Time) (string, error) {
+	var storageClass string
+	for _, transition := range transitions {
+		ok, err := n.lifecycleDue(ctx, bktInfo, transition.Days, transition.Date, tags, nodeVersion, now)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			storageClass = transition.StorageClass
+		}
+	}
+
+	return storageClass, nil
+}
+
+// transitionObject copies the object to the archive container of the storage class, saves its location
+// to the version node and deletes the object from the bucket container.
+func (n *layer) transitionObject(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion, storageClass string) error {
+	cnrID, ok := n.archiveStorageClasses[storageClass]
+	if !ok {
+		return fmt.Errorf("unknown archive storage class '%s'", storageClass)
+	}
+
+	archivedID, err := n.copyNeoFSObject(ctx, bktInfo, bktInfo.CID, nodeVersion.OID, cnrID)
+	if err != nil {
+		return fmt.Errorf("copy object to archive container: %w", err)
+	}
+
+	archive := &data.ArchiveInfo{
+		StorageClass: storageClass,
+		Container:    cnrID,
+		OID:          archivedID,
+	}
+	if err = n.archiveVersion(ctx, bktInfo, nodeVersion, archive); err != nil {
+		n.deleteArchivedObject(ctx, bktInfo, archive)
+		return err
+	}
+
+	if err = n.objectDelete(ctx, bktInfo, nodeVersion.OID); err != nil {
+		n.log.Error("couldn't delete archived object from bucket container", zap.Error(err),
+			zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath),
+			zap.Stringer("oid", nodeVersion.OID))
+	}
+
+	n.log.Debug("object is archived", zap.String("bucket name", bktInfo.Name),
+		zap.String("object", nodeVersion.FilePath), zap.String("storage class", storageClass),
+		zap.Stringer("archive cid", cnrID), zap.Stringer("archive oid", archivedID))
+
+	return nil
+}
+
+// archiveVersion saves location of the archived object to the version node. The node is updated by
+// the tree move, so it's updated only if it's still the latest version of the object.
+func (n *layer) archiveVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion, archive *data.ArchiveInfo) error {
+	latest, err := n.treeService.GetLatestVersion(ctx, bktInfo, nodeVersion.FilePath)
+	if errorsStd.Is(err, ErrNodeNotFound) {
+		return errArchivedObjectChanged
+	}
+	if err != nil {
+		return err
+	}
+	if latest.ID != nodeVersion.ID || !latest.OID.Equals(nodeVersion.OID) {
+		return errArchivedObjectChanged
+	}
+
+	archived := *latest
+	archived.Archive = archive
+	if err = n.treeService.ArchiveVersion(ctx, bktInfo, &archived); err != nil {
+		return err
+	}
+
+	n.cleanArchivedObjectCache(bktInfo, nodeVersion)
+
+	return nil
+}
+
+// RestoreObject requests the restore of the archived object, the object is copied to the bucket container
+// by ProcessRestores. The expiry of the restored copy is updated if the object is already restored.
+func (n *layer) RestoreObject(ctx context.Context, p *RestoreObjectParams) (bool, error) {
+	nodeVersion, err := n.getNodeVersion(ctx, &ObjectVersion{
+		BktInfo:    p.BktInfo,
+		ObjectName: p.Object,
+		VersionID:  p.VersionID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if nodeVersion.Archive == nil {
+		return false, errors.GetAPIError(errors.ErrInvalidObjectState)
+	}
+
+	restore, err := n.treeService.GetRestoreState(ctx, p.BktInfo, nodeVersion)
+	if err != nil {
+		return false, fmt.Errorf("couldn't get restore state: %w", err)
+	}
+
+	var restored bool
+	switch {
+	case restore == nil:
+		restore = &data.RestoreInfo{Ongoing: true, Days: p.Days}
+	case restore.Ongoing:
+		return false, errors.GetAPIError(errors.ErrRestoreAlreadyInProgress)
+	default:
+		restored = true
+		restore.Days = p.Days
+		restore.Expiry = lifecycleDaysLater(TimeNow(ctx), p.Days)
+	}
+
+	if err = n.treeService.PutRestoreState(ctx, p.BktInfo, nodeVersion, restore); err != nil {
+		return false, fmt.Errorf("couldn't save restore state: %w", err)
+	}
+
+	n.cleanArchivedObjectCache(p.BktInfo, nodeVersion)
+
+	return restored, nil
+}
+
+// ProcessRestores copies archived objects with ongoing restores to the bucket container and deletes
+// restored copies after the expiry. It returns the number of restored objects.
+func (n *layer) ProcessRestores(ctx context.Context, bktInfo *data.BucketInfo) (int, error) {
+	nodeVersions, err := n.treeService.GetAllVersionsByPrefix(ctx, bktInfo, "")
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get versions: %w", err)
+	}
+
+	var restored int
+	now := TimeNow(ctx)
+	for _, nodeVersion := range nodeVersions {
+		if nodeVersion.Archive == nil {
+			continue
+		}
+
+		restore, err := n.treeService.GetRestoreState(ctx, bktInfo, nodeVersion)
+		if err != nil {
+			n.log.Error("couldn't get restore state", zap.Error(err),
+				zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath))
+			continue
+		}
+
+		switch {
+		case restore == nil:
+		case restore.Ongoing:
+			if err = n.restoreArchivedObject(ctx, bktInfo, nodeVersion, restore, now); err != nil {
+				n.log.Error("couldn't restore object", zap.Error(err),
+					zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath))
+				continue
+			}
+			restored++
+		case !now.Before(restore.Expiry):
+			if err = n.expireRestoredObject(ctx, bktInfo, nodeVersion, restore); err != nil {
+				n.log.Error("couldn't delete expired restored object", zap.Error(err),
+					zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath))
+			}
+		}
+	}
+
+	return restored, nil
+}
+
+// restoreArchivedObject copies the archived object to the bucket container and saves the restore state.
+func (n *layer) restoreArchivedObject(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion,
+	restore *data.RestoreInfo, now time.Time) error {
+	restoredID, err := n.copyNeoFSObject(ctx, bktInfo, nodeVersion.Archive.Container, nodeVersion.Archive.OID, bktInfo.CID)
+	if err != nil {
+		return fmt.Errorf("copy object from archive container: %w", err)
+	}
+
+	updated := *restore
+	updated.Ongoing = false
+	updated.OID = restoredID
+	updated.Expiry = lifecycleDaysLater(now, restore.Days)
+	if err = n.treeService.PutRestoreState(ctx, bktInfo, nodeVersion, &updated); err != nil {
+		if errDelete := n.objectDelete(ctx, bktInfo, restoredID); errDelete != nil {
+			n.log.Error("couldn't delete restored object", zap.Error(errDelete),
+				zap.String("bucket name", bktInfo.Name), zap.Stringer("oid", restoredID))
+		}
+		return fmt.Errorf("couldn't save restore state: %w", err)
+	}
+
+	n.cleanArchivedObjectCache(bktInfo, nodeVersion)
+
+	return nil
+}
+
+// expireRestoredObject deletes the restored copy of the archived object and its restore state.
+func (n *layer) expireRestoredObject(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion, restore *data.RestoreInfo) error {
+	if err := n.treeService.DeleteRestoreState(ctx, bktInfo, nodeVersion); err != nil {
+		return fmt.Errorf("couldn't delete restore state: %w", err)
+	}
+
+	n.cleanArchivedObjectCache(bktInfo, nodeVersion)
+
+	return n.objectDelete(ctx, bktInfo, restore.OID)
+}
+
+// archivedObjectInfo returns info of the archived object from the header of the object in the archive container.
+func (n *layer) archivedObjectInfo(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) (*data.ObjectInfo, error) {
+	prm := PrmObjectRead{
+		Container:  nodeVersion.Archive.Container,
+		Object:     nodeVersion.Archive.OID,
+		WithHeader: true,
+	}
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+
+	res, err := n.neoFS.ReadObject(ctx, prm)
+	if err != nil {
+		return nil, fmt.Errorf("read archived object header: %w", err)
+	}
+
+	restore, err := n.treeService.GetRestoreState(ctx, bktInfo, nodeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get restore state: %w", err)
+	}
+
+	objInfo := objectInfoFromMeta(bktInfo, res.Head)
+	// the archived object keeps the id of the deleted bucket container object as the version id
+	objInfo.ID = nodeVersion.OID
+	objInfo.Name = nodeVersion.FilePath
+	objInfo.Replica = nodeVersion.Replica
+	objInfo.ReplicationStatus = nodeVersion.ReplicationStatus
+	objInfo.Archive = nodeVersion.Archive
+	objInfo.Restore = restore
+	if len(nodeVersion.ETag) != 0 {
+		objInfo.HashSum = nodeVersion.ETag
+	}
+
+	return objInfo, applyObjectMetadata(objInfo, nodeVersion)
+}
+
+// deleteArchivedObject deletes the archived object and its restored copy.
+func (n *layer) deleteArchivedObject(ctx context.Context, bktInfo *data.BucketInfo, archive *data.ArchiveInfo) {
+	prm := PrmObjectDelete{
+		Container: archive.Container,
+		Object:    archive.OID,
+	}
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+
+	if err := n.neoFS.DeleteObject(ctx, prm); err != nil {
+		n.log.Error("couldn't delete archived object", zap.Error(err),
+			zap.Stringer("archive cid", archive.Container), zap.Stringer("archive oid", archive.OID))
+	}
+}
+
+// deleteArchivedVersion deletes the archived object of the version node and its restored copy.
+func (n *layer) deleteArchivedVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) error {
+	restore, err := n.treeService.GetRestoreState(ctx, bktInfo, nodeVersion)
+	if err != nil {
+		return fmt.Errorf("couldn't get restore state: %w", err)
+	}
+	if restore.Restored() {
+		if err = n.objectDelete(ctx, bktInfo, restore.OID); err != nil {
+			return err
+		}
+	}
+
+	n.deleteArchivedObject(ctx, bktInfo, nodeVersion.Archive)
+	n.cache.DeleteObject(newAddress(bktInfo.CID, nodeVersion.OID))
+
+	return nil
+}
+
+// copyNeoFSObject stores the copy of the NeoFS object with the same attributes in the destination container.
+func (n *layer) copyNeoFSObject(ctx context.Context, bktInfo *data.BucketInfo, srcCnrID cid.ID, srcID oid.ID, dstCnrID cid.ID) (oid.ID, error) {
+	prmRead := PrmObjectRead{
+		Container:   srcCnrID,
+		Object:      srcID,
+		WithHeader:  true,
+		WithPayload: true,
+	}
+	n.prepareAuthParameters(ctx, &prmRead.PrmAuth, bktInfo.Owner)
+
+	res, err := n.neoFS.ReadObject(ctx, prmRead)
+	if err != nil {
+		return oid.ID{}, fmt.Errorf("read object: %w", err)
+	}
+	defer func() { _ = res.Payload.Close() }()
+
+	attrs := res.Head.Attributes()
+	prm := PrmObjectCreate{
+		Container:   dstCnrID,
+		Creator:     *res.Head.OwnerID(),
+		Attributes:  make([][2]string, 0, len(attrs)),
+		PayloadSize: res.Head.PayloadSize(),
+		Payload:     res.Payload,
+	}
+	for _, attr := range attrs {
+		// the creation time attribute is set by NeoFS.CreateObject
+		if attr.Key() == object.AttributeTimestamp {
+			if unix, err := strconv.ParseInt(attr.Value(), 10, 64); err == nil {
+				prm.CreationTime = time.Unix(unix, 0)
+			}
+			continue
+		}
+		prm.Attributes = append(prm.Attributes, [2]string{attr.Key(), attr.Value()})
+	}
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+
+	return n.neoFS.CreateObject(ctx, prm)
+}
+
+// cleanArchivedObjectCache deletes the object from caches after the archive or restore state is changed.
+func (n *layer) cleanArchivedObjectCache(bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) {
+	n.cache.DeleteObject(newAddress(bktInfo.CID, nodeVersion.OID))
+	n.cache.DeleteObjectName(bktInfo.CID, bktInfo.Name, nodeVersion.FilePath)
+	n.cache.CleanListCacheEntriesContainingObject(nodeVersion.FilePath, bktInfo.CID)
+}
@@ -0,0 +1,487 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// PutBucketReplicationParams stores PutBucketReplication request parameters.
+type PutBucketReplicationParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.ReplicationConfiguration
+	CopiesNumber  uint32
+}
+
+type (
+	// replicator schedules copying of new object versions to the destination buckets of the bucket
+	// replication, versions are copied by the worker started with RunReplicator.
+	replicator struct {
+		tasks chan replicationTask
+	}
+
+	replicationTask struct {
+		bktInfo *data.BucketInfo
+		version *data.NodeVersion
+		rule    *data.ReplicationRule
+		// box contains credentials of the request which created the version.
+		box *accessbox.Box
+	}
+)
+
+const (
+	// replicationQueueSize is a number of versions waiting for the replication,
+	// versions created while the queue is full are marked as failed.
+	replicationQueueSize = 1024
+	// replicationMaxRules limits the number of rules in the replication configuration as AWS S3 does.
+	replicationMaxRules = 1000
+	// replicationMaxIDLength limits the length of replication rule id.
+	replicationMaxIDLength = 255
+)
+
+func newReplicator() *replicator {
+	return &replicator{
+		tasks: make(chan replicationTask, replicationQueueSize),
+	}
+}
+
+// schedule adds the version to the replication queue, false is returned if the queue is full.
+func (r *replicator) schedule(ctx context.Context, task replicationTask) bool {
+	task.box, _ = ctx.Value(api.BoxData).(*accessbox.Box)
+	select {
+	case r.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// PutBucketReplication sets the replication configuration of the bucket. Source and destination
+// buckets must have versioning enabled.
+func (n *layer) PutBucketReplication(ctx context.Context, p *PutBucketReplicationParams) error {
+	if err := checkReplication(p.Configuration); err != nil {
+		return err
+	}
+
+	settings, err := n.GetBucketSettings(ctx, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+	if !settings.VersioningEnabled() {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidRequest,
+			errorsStd.New("versioning must be enabled on the bucket to apply a replication configuration"))
+	}
+
+	for i := range p.Configuration.Rules {
+		if err = n.checkReplicationDestination(ctx, p.BktInfo, &p.Configuration.Rules[i]); err != nil {
+			return err
+		}
+	}
+
+	prev, err := n.getReplicationConfiguration(ctx, p.BktInfo)
+	if err != nil {
+		return err
+	}
+
+	return n.putReplicationConfiguration(ctx, p.BktInfo, prev, p.Configuration, p.CopiesNumber)
+}
+
+// GetBucketReplication returns the replication configuration of the bucket.
+func (n *layer) GetBucketReplication(ctx context.Context, bktInfo *data.BucketInfo) (*data.ReplicationConfiguration, error) {
+	conf, err := n.getReplicationConfiguration(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(conf.Rules) == 0 {
+		return nil, errors.GetAPIError(errors.ErrReplicationConfigurationNotFoundError)
+	}
+
+	return conf, nil
+}
+
+// DeleteBucketReplication removes the replication configuration of the bucket,
+// versions waiting for the replication are still copied.
+func (n *layer) DeleteBucketReplication(ctx context.Context, bktInfo *data.BucketInfo) error {
+	prev, err := n.getReplicationConfiguration(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+	if len(prev.Rules) == 0 {
+		return nil
+	}
+
+	return n.putReplicationConfiguration(ctx, bktInfo, prev, &data.ReplicationConfiguration{}, 0)
+}
+
+// getReplicationConfiguration returns the replication configuration of the bucket, the bucket without
+// replication gets the configuration without rules.
+func (n *layer) getReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.ReplicationConfiguration, error) {
+	owner := n.Owner(ctx)
+	if conf := n.cache.GetReplicationConfiguration(owner, bktInfo); conf != nil {
+		return conf, nil
+	}
+
+	conf := &data.ReplicationConfiguration{}
+	objID, err := n.treeService.GetBucketReplicationConfiguration(ctx, bktInfo)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		obj, err := n.objectGet(ctx, bktInfo, objID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = xml.Unmarshal(obj.Payload(), conf); err != nil {
+			return nil, fmt.Errorf("unmarshal replication configuration: %w", err)
+		}
+	}
+
+	n.cache.PutReplicationConfiguration(owner, bktInfo, conf)
+
+	return conf, nil
+}
+
+// putReplicationConfiguration saves the replication configuration of the bucket as a system object,
+// the object is removed if the configuration has no rules.
+func (n *layer) putReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo, prev, conf *data.ReplicationConfiguration, copiesNumber uint32) error {
+	var prevValue []byte
+	if len(prev.Rules) != 0 {
+		var err error
+		if prevValue, err = xml.Marshal(prev); err != nil {
+			n.log.Warn("couldn't marshal previous bucket replication configuration", zap.Error(err))
+		}
+	}
+
+	var (
+		objIDToDelete oid.ID
+		err           error
+	)
+	if len(conf.Rules) == 0 {
+		objIDToDelete, err = n.treeService.DeleteBucketReplicationConfiguration(ctx, bktInfo)
+	} else {
+		var confXML []byte
+		if confXML, err = xml.Marshal(conf); err != nil {
+			return fmt.Errorf("marshal replication configuration: %w", err)
+		}
+
+		prm := PrmObjectCreate{
+			Container:    bktInfo.CID,
+			Creator:      bktInfo.Owner,
+			Payload:      bytes.NewReader(confXML),
+			Filepath:     n.systemObjectName(bktInfo.ReplicationConfigurationObjectName()),
+			CreationTime: TimeNow(ctx),
+			CopiesNumber: copiesNumber,
+		}
+
+		var objID oid.ID
+		if objID, _, err = n.objectPutAndHash(ctx, prm, bktInfo); err != nil {
+			return fmt.Errorf("put system object: %w", err)
+		}
+
+		objIDToDelete, err = n.treeService.PutBucketReplicationConfiguration(ctx, bktInfo, objID)
+	}
+
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete replication configuration object", zap.Error(err),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	n.cache.PutReplicationConfiguration(n.Owner(ctx), bktInfo, conf)
+	return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypeReplication, prevValue, copiesNumber)
+}
+
+func checkReplication(conf *data.ReplicationConfiguration) error {
+	if len(conf.Rules) == 0 {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+	if len(conf.Rules) > replicationMaxRules {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+			fmt.Errorf("replication configuration can't have more than %d rules", replicationMaxRules))
+	}
+
+	ids := make(map[string]struct{}, len(conf.Rules))
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+		if len(rule.ID) > replicationMaxIDLength {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid replication rule id '%s'", rule.ID))
+		}
+		if len(rule.ID) != 0 {
+			if _, ok := ids[rule.ID]; ok {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("replication rule id '%s' isn't unique", rule.ID))
+			}
+			ids[rule.ID] = struct{}{}
+		}
+
+		if rule.Status != data.ReplicationRuleEnabled && rule.Status != data.ReplicationRuleDisabled {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+		if rule.DeleteMarkerReplication != nil && rule.DeleteMarkerReplication.Status != data.ReplicationRuleEnabled &&
+			rule.DeleteMarkerReplication.Status != data.ReplicationRuleDisabled {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+
+		if rule.Filter != nil {
+			if len(rule.Prefix) != 0 || rule.Filter.And != nil && len(rule.Filter.Prefix) != 0 {
+				return errors.GetAPIError(errors.ErrMalformedXML)
+			}
+			// objects are replicated right after the upload, tags aren't known at this moment
+			if rule.Filter.Tag != nil || rule.Filter.And != nil && len(rule.Filter.And.Tags) != 0 {
+				return errors.GetAPIErrorWithError(errors.ErrNotImplemented, errorsStd.New("replication filter by tags isn't supported"))
+			}
+		}
+
+		dst := rule.Destination.Bucket
+		if !strings.HasPrefix(dst, inventoryDestinationARNPrefix) || len(dst) == len(inventoryDestinationARNPrefix) {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid destination bucket '%s'", dst))
+		}
+	}
+
+	return nil
+}
+
+// checkReplicationDestination checks that the destination bucket of the rule exists, differs
+// from the source bucket and has versioning enabled.
+func (n *layer) checkReplicationDestination(ctx context.Context, bktInfo *data.BucketInfo, rule *data.ReplicationRule) error {
+	dstBktInfo, err := n.GetBucketInfo(ctx, strings.TrimPrefix(rule.Destination.Bucket, inventoryDestinationARNPrefix))
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchBucket) {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+				fmt.Errorf("destination bucket '%s' doesn't exist", rule.Destination.Bucket))
+		}
+		return err
+	}
+	if dstBktInfo.CID.Equals(bktInfo.CID) {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidRequest,
+			errorsStd.New("destination bucket must differ from the source bucket"))
+	}
+
+	settings, err := n.GetBucketSettings(ctx, dstBktInfo)
+	if err != nil {
+		return fmt.Errorf("couldn't get destination bucket settings: %w", err)
+	}
+	if !settings.VersioningEnabled() {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidRequest,
+			errorsStd.New("destination bucket must have versioning enabled"))
+	}
+
+	return nil
+}
+
+// matchReplicationRule returns the replication rule which copies the new version of the object,
+// nil is returned if the object isn't replicated.
+func (n *layer) matchReplicationRule(ctx context.Context, bktInfo *data.BucketInfo, key string) (*data.ReplicationRule, error) {
+	conf, err := n.getReplicationConfiguration(ctx, bktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get replication configuration: %w", err)
+	}
+
+	return conf.MatchRule(key), nil
+}
+
+// scheduleReplication adds the new version to the replication queue, the version is marked
+// as failed if the queue is full.
+func (n *layer) scheduleReplication(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, rule *data.ReplicationRule) {
+	if n.replicator.schedule(ctx, replicationTask{bktInfo: bktInfo, version: version, rule: rule}) {
+		return
+	}
+
+	n.log.Warn("replication queue is full", zap.String("bucket name", bktInfo.Name),
+		zap.String("object", version.FilePath))
+	if err := n.treeService.PutReplicationStatus(ctx, bktInfo, version, data.ReplicationStatusFailed); err != nil {
+		n.log.Error("couldn't save replication status", zap.Error(err),
+			zap.String("bucket name", bktInfo.Name), zap.String("object", version.FilePath))
+	}
+}
+
+// replicateDeleteMarker adds the new delete marker to the replication queue if the matching
+// replication rule copies delete markers.
+func (n *layer) replicateDeleteMarker(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) {
+	rule, err := n.matchReplicationRule(ctx, bktInfo, version.FilePath)
+	if err != nil {
+		n.log.Warn("couldn't replicate delete marker", zap.Error(err),
+			zap.String("bucket name", bktInfo.Name), zap.String("object", version.FilePath))
+		return
+	}
+	if rule == nil || !rule.DeleteMarkersReplicated() {
+		return
+	}
+
+	if !n.replicator.schedule(ctx, replicationTask{bktInfo: bktInfo, version: version, rule: rule}) {
+		n.log.Warn("replication queue is full, delete marker isn't replicated",
+			zap.String("bucket name", bktInfo.Name), zap.String("object", version.FilePath))
+	}
+}
+
+// withReplicationStatus returns the object info with the replication state updated after the version creation.
+func (n *layer) withReplicationStatus(ctx context.Context, bktInfo *data.BucketInfo, objInfo *data.ExtendedObjectInfo) (*data.ExtendedObjectInfo, error) {
+	if objInfo.ObjectInfo.ReplicationStatus != data.ReplicationStatusPending {
+		return objInfo, nil
+	}
+
+	status, err := n.treeService.GetReplicationStatus(ctx, bktInfo, objInfo.NodeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get replication status: %w", err)
+	}
+	if len(status) == 0 {
+		return objInfo, nil
+	}
+
+	// object info can be shared with the cache, so it isn't changed in place
+	info := *objInfo.ObjectInfo
+	info.ReplicationStatus = status
+
+	return &data.ExtendedObjectInfo{
+		ObjectInfo:  &info,
+		NodeVersion: objInfo.NodeVersion,
+		IsLatest:    objInfo.IsLatest,
+	}, nil
+}
+
+// RunReplicator copies new object versions and delete markers to the destination buckets
+// of the bucket replication until the context is done.
+func (n *layer) RunReplicator(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-n.replicator.tasks:
+			taskCtx := ctx
+			if task.box != nil {
+				taskCtx = context.WithValue(ctx, api.BoxData, task.box)
+			}
+
+			n.replicate(taskCtx, task)
+		}
+	}
+}
+
+// replicate copies the version of the task and saves the replication state of objects.
+func (n *layer) replicate(ctx context.Context, task replicationTask) {
+	if task.version.IsDeleteMarker() {
+		if err := n.copyDeleteMarker(ctx, task); err != nil {
+			n.log.Error("couldn't replicate delete marker", zap.Error(err),
+				zap.String("bucket name", task.bktInfo.Name), zap.String("object", task.version.FilePath))
+		}
+		return
+	}
+
+	status := data.ReplicationStatusCompleted
+	if err := n.copyVersion(ctx, task); err != nil {
+		n.log.Error("couldn't replicate object", zap.Error(err),
+			zap.String("bucket name", task.bktInfo.Name), zap.String("object", task.version.FilePath),
+			zap.Stringer("oid", task.version.OID))
+		status = data.ReplicationStatusFailed
+	}
+
+	if err := n.treeService.PutReplicationStatus(ctx, task.bktInfo, task.version, status); err != nil {
+		n.log.Error("couldn't save replication status", zap.Error(err),
+			zap.String("bucket name", task.bktInfo.Name), zap.String("object", task.version.FilePath))
+	}
+}
+
+// copyVersion writes the copy of the version with its metadata and tags to the destination bucket.
+// The copy keeps ETag of the source object.
+func (n *layer) copyVersion(ctx context.Context, task replicationTask) error {
+	dstBktInfo, err := n.GetBucketInfo(ctx, strings.TrimPrefix(task.rule.Destination.Bucket, inventoryDestinationARNPrefix))
+	if err != nil {
+		return fmt.Errorf("get destination bucket: %w", err)
+	}
+
+	objInfo, err := n.objectInfoFromNode(ctx, task.bktInfo, task.version)
+	if err != nil {
+		return fmt.Errorf("get object info: %w", err)
+	}
+
+	tags, err := n.treeService.GetObjectTagging(ctx, task.bktInfo, task.version)
+	if err != nil {
+		return fmt.Errorf("get object tagging: %w", err)
+	}
+
+	header := make(map[string]string, len(objInfo.Headers)+1)
+	for key, val := range objInfo.Headers {
+		// the copy is stored as a single object
+		if key != UploadCompletedParts && key != UploadCompletedID && key != AttributePartsChecksums {
+			header[key] = val
+		}
+	}
+	if len(objInfo.ContentType) != 0 {
+		header[api.ContentType] = objInfo.ContentType
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		err := n.GetObject(ctx, &GetObjectParams{
+			ObjectInfo: objInfo,
+			Writer:     pw,
+			BucketInfo: task.bktInfo,
+		})
+		_ = pw.CloseWithError(err)
+	}()
+
+	extObjInfo, err := n.PutObject(ctx, &PutObjectParams{
+		BktInfo: dstBktInfo,
+		Object:  objInfo.Name,
+		Size:    objInfo.Size,
+		Reader:  pr,
+		Header:  header,
+		ETag:    objInfo.HashSum,
+		Replica: true,
+	})
+	if err != nil {
+		_ = pr.CloseWithError(err)
+		return fmt.Errorf("put object copy: %w", err)
+	}
+
+	if len(tags) != 0 {
+		if err = n.treeService.PutObjectTagging(ctx, dstBktInfo, extObjInfo.NodeVersion, tags); err != nil {
+			return fmt.Errorf("put object copy tagging: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// copyDeleteMarker creates the delete marker of the object in the destination bucket.
+func (n *layer) copyDeleteMarker(ctx context.Context, task replicationTask) error {
+	dstBktInfo, err := n.GetBucketInfo(ctx, strings.TrimPrefix(task.rule.Destination.Bucket, inventoryDestinationARNPrefix))
+	if err != nil {
+		return fmt.Errorf("get destination bucket: %w", err)
+	}
+
+	settings, err := n.GetBucketSettings(ctx, dstBktInfo)
+	if err != nil {
+		return fmt.Errorf("get destination bucket settings: %w", err)
+	}
+
+	deleted := n.DeleteObjects(ctx, &DeleteObjectParams{
+		BktInfo:  dstBktInfo,
+		Objects:  []*VersionedObject{{Name: task.version.FilePath}},
+		Settings: settings,
+		Replica:  true,
+	})
+
+	return deleted[0].Error
+}
@@ -0,0 +1,92 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketReplication(t *testing.T) {
+	tc := prepareContext(t)
+	n := tc.layer.(*layer)
+
+	dstID, err := tc.testNeoFS.CreateContainer(tc.ctx, PrmContainerCreate{Name: "replica", Creator: tc.bktInfo.Owner})
+	require.NoError(t, err)
+	dstBktInfo, err := tc.layer.GetBucketInfo(tc.ctx, dstID.EncodeToString())
+	require.NoError(t, err)
+
+	conf := &data.ReplicationConfiguration{Rules: []data.ReplicationRule{{
+		ID:                      "logs",
+		Status:                  data.ReplicationRuleEnabled,
+		Filter:                  &data.ReplicationFilter{Prefix: "logs/"},
+		Destination:             data.ReplicationDestination{Bucket: "arn:aws:s3:::" + dstID.EncodeToString()},
+		DeleteMarkerReplication: &data.DeleteMarkerReplication{Status: data.ReplicationRuleEnabled},
+	}}}
+	p := &PutBucketReplicationParams{BktInfo: tc.bktInfo, Configuration: conf}
+
+	// both buckets must have versioning enabled
+	err = tc.layer.PutBucketReplication(tc.ctx, p)
+	require.True(t, errors.IsS3Error(err, errors.ErrInvalidRequest))
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	for _, bktInfo := range []*data.BucketInfo{tc.bktInfo, dstBktInfo} {
+		require.NoError(t, tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: bktInfo, Settings: settings}))
+	}
+	require.NoError(t, tc.layer.PutBucketReplication(tc.ctx, p))
+
+	actual, err := tc.layer.GetBucketReplication(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, conf.Rules, actual.Rules)
+
+	content := []byte("content")
+	put := func(key string) *data.ExtendedObjectInfo {
+		extObjInfo, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  key,
+			Size:    int64(len(content)),
+			Reader:  bytes.NewReader(content),
+			Header:  make(map[string]string),
+		})
+		require.NoError(t, err)
+		return extObjInfo
+	}
+	head := func(bktInfo *data.BucketInfo, key string) (*data.ObjectInfo, error) {
+		return tc.layer.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: bktInfo, Object: key})
+	}
+
+	require.Empty(t, put("other").ObjectInfo.ReplicationStatus)
+	require.Equal(t, data.ReplicationStatusPending, put("logs/a").ObjectInfo.ReplicationStatus)
+	require.Len(t, n.replicator.tasks, 1)
+
+	n.replicate(tc.ctx, <-n.replicator.tasks)
+
+	objInfo, err := head(tc.bktInfo, "logs/a")
+	require.NoError(t, err)
+	require.Equal(t, data.ReplicationStatusCompleted, objInfo.ReplicationStatus)
+
+	replica, err := head(dstBktInfo, "logs/a")
+	require.NoError(t, err)
+	require.Equal(t, data.ReplicationStatusReplica, replica.ReplicationStatus)
+	require.Equal(t, objInfo.HashSum, replica.HashSum)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, tc.layer.GetObject(tc.ctx, &GetObjectParams{ObjectInfo: replica, Writer: buf, BucketInfo: dstBktInfo}))
+	require.Equal(t, content, buf.Bytes())
+
+	// replicas aren't replicated further
+	require.Empty(t, n.replicator.tasks)
+
+	tc.deleteObject("logs/a", "", settings)
+	require.Len(t, n.replicator.tasks, 1)
+	n.replicate(tc.ctx, <-n.replicator.tasks)
+
+	_, err = head(dstBktInfo, "logs/a")
+	require.True(t, errors.IsS3Error(err, errors.ErrNoSuchKey))
+
+	require.NoError(t, tc.layer.DeleteBucketReplication(tc.ctx, tc.bktInfo))
+	_, err = tc.layer.GetBucketReplication(tc.ctx, tc.bktInfo)
+	require.True(t, errors.IsS3Error(err, errors.ErrReplicationConfigurationNotFoundError))
+	require.Empty(t, put("logs/b").ObjectInfo.ReplicationStatus)
+}
@@ -1,8 +1,11 @@
 package layer
 
 import (
+	"sync"
+
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
@@ -17,6 +20,11 @@ type Cache struct {
 	bucketCache *cache.BucketCache
 	systemCache *cache.SystemCache
 	accessCache *cache.AccessControlCache
+
+	// generationsMu serializes invalidation of objects with conditional puts of data read from the tree service.
+	generationsMu sync.RWMutex
+	// generations counts object changes in containers.
+	generations map[cid.ID]uint64
 }
 
 // CachesConfig contains params for caches.
@@ -52,6 +60,29 @@ func NewCache(cfg *CachesConfig) *Cache {
 		bucketCache: cache.NewBucketCache(cfg.Buckets),
 		systemCache: cache.NewSystemCache(cfg.System),
 		accessCache: cache.NewAccessControlCache(cfg.AccessControl),
+		generations: make(map[cid.ID]uint64),
+	}
+}
+
+// Purge deletes all entries from the caches, so the data is read from the storage again.
+func (c *Cache) Purge() {
+	c.listsCache.Purge()
+	c.objCache.Purge()
+	c.namesCache.Purge()
+	c.bucketCache.Purge()
+	c.systemCache.Purge()
+	c.accessCache.Purge()
+}
+
+// Stats returns statistics of the caches by their names.
+func (c *Cache) Stats() map[string]cache.Stats {
+	return map[string]cache.Stats{
+		"list":          c.listsCache.Stats(),
+		"objects":       c.objCache.Stats(),
+		"names":         c.namesCache.Stats(),
+		"buckets":       c.bucketCache.Stats(),
+		"system":        c.systemCache.Stats(),
+		"accesscontrol": c.accessCache.Stats(),
 	}
 }
 
@@ -72,13 +103,39 @@ func (c *Cache) DeleteBucket(name string) {
 	c.bucketCache.Delete(name)
 }
 
+// Generation returns the number of object changes in the container. It must be taken before reading
+// from the tree service and passed to the cache with the read data, so data read before a concurrent
+// change of objects isn't cached.
+func (c *Cache) Generation(cnrID cid.ID) uint64 {
+	c.generationsMu.RLock()
+	defer c.generationsMu.RUnlock()
+
+	return c.generations[cnrID]
+}
+
+// ObjectChanged removes cached listings and the latest version of the object after its change
+// and returns the new generation of the container to cache the changed object with.
+func (c *Cache) ObjectChanged(cnrID cid.ID, bktName, objName string) uint64 {
+	c.generationsMu.Lock()
+	defer c.generationsMu.Unlock()
+
+	c.namesCache.Delete(bktName + "/" + objName)
+	c.listsCache.CleanCacheEntriesContainingObject(objName, cnrID)
+	c.generations[cnrID]++
+
+	return c.generations[cnrID]
+}
+
 func (c *Cache) CleanListCacheEntriesContainingObject(objectName string, cnrID cid.ID) {
+	c.generationsMu.Lock()
+	defer c.generationsMu.Unlock()
+
 	c.listsCache.CleanCacheEntriesContainingObject(objectName, cnrID)
+	c.generations[cnrID]++
 }
 
 func (c *Cache) DeleteObjectName(cnrID cid.ID, bktName, objName string) {
-	c.namesCache.Delete(bktName + "/" + objName)
-	c.listsCache.CleanCacheEntriesContainingObject(objName, cnrID)
+	c.ObjectChanged(cnrID, bktName, objName)
 }
 
 func (c *Cache) DeleteObject(addr oid.Address) {
@@ -114,9 +171,18 @@ func (c *Cache) PutObject(owner user.ID, extObjInfo *data.ExtendedObjectInfo) {
 	}
 }
 
-func (c *Cache) PutObjectWithName(owner user.ID, extObjInfo *data.ExtendedObjectInfo) {
+// PutObjectWithName caches the object as the latest version of its name
+// unless objects of the container were changed since the generation.
+func (c *Cache) PutObjectWithName(owner user.ID, extObjInfo *data.ExtendedObjectInfo, generation uint64) {
 	c.PutObject(owner, extObjInfo)
 
+	c.generationsMu.RLock()
+	defer c.generationsMu.RUnlock()
+
+	if c.generations[extObjInfo.ObjectInfo.CID] != generation {
+		return
+	}
+
 	if err := c.namesCache.Put(extObjInfo.ObjectInfo.NiceName(), extObjInfo.ObjectInfo.Address()); err != nil {
 		c.logger.Warn("couldn't put obj address to name cache",
 			zap.String("obj nice name", extObjInfo.ObjectInfo.NiceName()),
@@ -124,6 +190,23 @@ func (c *Cache) PutObjectWithName(owner user.ID, extObjInfo *data.ExtendedObject
 	}
 }
 
+// GetIndexAccess checks if the owner has confirmed read access to objects of the container,
+// so their listings can be served from the object index.
+func (c *Cache) GetIndexAccess(owner user.ID, cnrID cid.ID) bool {
+	return c.accessCache.Get(owner, indexAccessKey(cnrID))
+}
+
+// PutIndexAccess saves confirmed read access of the owner to objects of the container.
+func (c *Cache) PutIndexAccess(owner user.ID, cnrID cid.ID) {
+	if err := c.accessCache.Put(owner, indexAccessKey(cnrID)); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func indexAccessKey(cnrID cid.ID) string {
+	return "index/" + cnrID.EncodeToString()
+}
+
 func (c *Cache) GetList(owner user.ID, key cache.ObjectsListKey) []*data.NodeVersion {
 	if !c.accessCache.Get(owner, key.String()) {
 		return nil
@@ -132,7 +215,15 @@ func (c *Cache) GetList(owner user.ID, key cache.ObjectsListKey) []*data.NodeVer
 	return c.listsCache.GetVersions(key)
 }
 
-func (c *Cache) PutList(owner user.ID, key cache.ObjectsListKey, list []*data.NodeVersion) {
+// PutList caches the list of object versions unless objects of the container were changed since the generation.
+func (c *Cache) PutList(owner user.ID, key cache.ObjectsListKey, list []*data.NodeVersion, generation uint64) {
+	c.generationsMu.RLock()
+	defer c.generationsMu.RUnlock()
+
+	if c.generations[key.CID()] != generation {
+		return
+	}
+
 	if err := c.listsCache.PutVersions(key, list); err != nil {
 		c.logger.Warn("couldn't cache list of objects", zap.Error(err))
 	}
@@ -249,3 +340,178 @@ func (c *Cache) PutNotificationConfiguration(owner user.ID, bktInfo *data.Bucket
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
+
+func (c *Cache) GetLifecycleConfiguration(owner user.ID, bktInfo *data.BucketInfo) *data.LifecycleConfiguration {
+	key := bktInfo.Name + bktInfo.LifecycleConfigurationObjectName()
+
+	if !c.accessCache.Get(owner, key) {
+		return nil
+	}
+
+	return c.systemCache.GetLifecycleConfiguration(key)
+}
+
+func (c *Cache) PutLifecycleConfiguration(owner user.ID, bktInfo *data.BucketInfo, configuration *data.LifecycleConfiguration) {
+	key := bktInfo.Name + bktInfo.LifecycleConfigurationObjectName()
+	if err := c.systemCache.PutLifecycleConfiguration(key, configuration); err != nil {
+		c.logger.Warn("couldn't cache lifecycle configuration", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func (c *Cache) DeleteLifecycleConfiguration(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.LifecycleConfigurationObjectName())
+}
+
+func (c *Cache) GetInventoryConfigurations(owner user.ID, bktInfo *data.BucketInfo) *data.InventoryConfigurations {
+	key := bktInfo.Name + bktInfo.InventoryConfigurationObjectName()
+
+	if !c.accessCache.Get(owner, key) {
+		return nil
+	}
+
+	return c.systemCache.GetInventoryConfigurations(key)
+}
+
+func (c *Cache) PutInventoryConfigurations(owner user.ID, bktInfo *data.BucketInfo, configurations *data.InventoryConfigurations) {
+	key := bktInfo.Name + bktInfo.InventoryConfigurationObjectName()
+	if err := c.systemCache.PutInventoryConfigurations(key, configurations); err != nil {
+		c.logger.Warn("couldn't cache inventory configurations", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func (c *Cache) DeleteInventoryConfigurations(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.InventoryConfigurationObjectName())
+}
+
+func (c *Cache) GetReplicationConfiguration(owner user.ID, bktInfo *data.BucketInfo) *data.ReplicationConfiguration {
+	key := bktInfo.Name + bktInfo.ReplicationConfigurationObjectName()
+
+	if !c.accessCache.Get(owner, key) {
+		return nil
+	}
+
+	return c.systemCache.GetReplicationConfiguration(key)
+}
+
+func (c *Cache) PutReplicationConfiguration(owner user.ID, bktInfo *data.BucketInfo, configuration *data.ReplicationConfiguration) {
+	key := bktInfo.Name + bktInfo.ReplicationConfigurationObjectName()
+	if err := c.systemCache.PutReplicationConfiguration(key, configuration); err != nil {
+		c.logger.Warn("couldn't cache replication configuration", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func (c *Cache) DeleteReplicationConfiguration(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.ReplicationConfigurationObjectName())
+}
+
+func (c *Cache) GetAnalyticsConfigurations(owner user.ID, bktInfo *data.BucketInfo) *data.AnalyticsConfigurations {
+	key := bktInfo.Name + bktInfo.AnalyticsConfigurationObjectName()
+
+	if !c.accessCache.Get(owner, key) {
+		return nil
+	}
+
+	return c.systemCache.GetAnalyticsConfigurations(key)
+}
+
+func (c *Cache) PutAnalyticsConfigurations(owner user.ID, bktInfo *data.BucketInfo, configurations *data.AnalyticsConfigurations) {
+	key := bktInfo.Name + bktInfo.AnalyticsConfigurationObjectName()
+	if err := c.systemCache.PutAnalyticsConfigurations(key, configurations); err != nil {
+		c.logger.Warn("couldn't cache analytics configurations", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func (c *Cache) DeleteAnalyticsConfigurations(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.AnalyticsConfigurationObjectName())
+}
+
+func (c *Cache) GetMetricsConfigurations(owner user.ID, bktInfo *data.BucketInfo) *data.MetricsConfigurations {
+	key := bktInfo.Name + bktInfo.MetricsConfigurationObjectName()
+
+	if !c.accessCache.Get(owner, key) {
+		return nil
+	}
+
+	return c.systemCache.GetMetricsConfigurations(key)
+}
+
+func (c *Cache) PutMetricsConfigurations(owner user.ID, bktInfo *data.BucketInfo, configurations *data.MetricsConfigurations) {
+	key := bktInfo.Name + bktInfo.MetricsConfigurationObjectName()
+	if err := c.systemCache.PutMetricsConfigurations(key, configurations); err != nil {
+		c.logger.Warn("couldn't cache metrics configurations", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func (c *Cache) DeleteMetricsConfigurations(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.MetricsConfigurationObjectName())
+}
+
+func (c *Cache) GetBucketPolicy(owner user.ID, bktInfo *data.BucketInfo) *policy.Policy {
+	key := bktInfo.Name + bktInfo.PolicyObjectName()
+
+	if !c.accessCache.Get(owner, key) {
+		return nil
+	}
+
+	return c.systemCache.GetBucketPolicy(key)
+}
+
+func (c *Cache) PutBucketPolicy(owner user.ID, bktInfo *data.BucketInfo, bktPolicy *policy.Policy) {
+	key := bktInfo.Name + bktInfo.PolicyObjectName()
+	if err := c.systemCache.PutBucketPolicy(key, bktPolicy); err != nil {
+		c.logger.Warn("couldn't cache bucket policy", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func (c *Cache) DeleteBucketPolicy(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.PolicyObjectName())
+}
+
+func (c *Cache) GetWebsiteConfiguration(owner user.ID, bktInfo *data.BucketInfo) *data.WebsiteConfiguration {
+	key := bktInfo.Name + bktInfo.WebsiteConfigurationObjectName()
+
+	if !c.accessCache.Get(owner, key) {
+		return nil
+	}
+
+	return c.systemCache.GetWebsiteConfiguration(key)
+}
+
+func (c *Cache) PutWebsiteConfiguration(owner user.ID, bktInfo *data.BucketInfo, configuration *data.WebsiteConfiguration) {
+	key := bktInfo.Name + bktInfo.WebsiteConfigurationObjectName()
+	if err := c.systemCache.PutWebsiteConfiguration(key, configuration); err != nil {
+		c.logger.Warn("couldn't cache website configuration", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
+func (c *Cache) DeleteWebsiteConfiguration(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.WebsiteConfigurationObjectName())
+}
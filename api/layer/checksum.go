@@ -0,0 +1,116 @@
+package layer
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"hash/crc32"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+)
+
+// Checksum algorithms supported for multipart upload parts.
+const (
+	ChecksumCRC32  = "CRC32"
+	ChecksumCRC32C = "CRC32C"
+	ChecksumSHA1   = "SHA1"
+	ChecksumSHA256 = "SHA256"
+
+	// UploadChecksumAlgorithm is a multipart upload meta key of the checksum algorithm
+	// applied to every part of the upload.
+	UploadChecksumAlgorithm = "S3-Checksum-Algorithm"
+
+	// AttributeChecksumAlgorithm is an algorithm of the object checksum.
+	AttributeChecksumAlgorithm = api.NeoFSSystemMetadataPrefix + "Checksum-Algorithm"
+	// AttributeChecksum is a base64-encoded checksum of the object payload. Checksum of the multipart
+	// object is a checksum of the part checksums with the number of parts suffix.
+	AttributeChecksum = api.NeoFSSystemMetadataPrefix + "Checksum"
+	// AttributePartsChecksums is a comma-separated list of the multipart object part checksums
+	// in the order of S3-Completed-Parts header.
+	AttributePartsChecksums = api.NeoFSSystemMetadataPrefix + "Parts-Checksums"
+)
+
+// ChecksumAlgorithms lists supported checksum algorithms.
+var ChecksumAlgorithms = []string{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA1, ChecksumSHA256}
+
+// IsChecksumAlgorithm checks if the checksum algorithm is supported.
+func IsChecksumAlgorithm(algorithm string) bool {
+	return newChecksumHash(algorithm) != nil
+}
+
+func newChecksumHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case ChecksumCRC32:
+		return crc32.NewIEEE()
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case ChecksumSHA1:
+		return sha1.New()
+	case ChecksumSHA256:
+		return sha256.New()
+	default:
+		return nil
+	}
+}
+
+func encodeChecksum(h hash.Hash) string {
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// applyNodeChecksum adds the checksum saved in the version node to headers of the object info.
+func applyNodeChecksum(objInfo *data.ObjectInfo, nodeVersion *data.NodeVersion) {
+	if nodeVersion.ChecksumAlgorithm == "" {
+		return
+	}
+
+	// headers map of the object info can be shared with caches, so a new map is created
+	headers := make(map[string]string, len(objInfo.Headers)+2)
+	for key, val := range objInfo.Headers {
+		headers[key] = val
+	}
+	headers[AttributeChecksumAlgorithm] = nodeVersion.ChecksumAlgorithm
+	headers[AttributeChecksum] = nodeVersion.Checksum
+	objInfo.Headers = headers
+}
+
+func (p *Part) setChecksum(algorithm, checksum string) {
+	switch algorithm {
+	case ChecksumCRC32:
+		p.ChecksumCRC32 = checksum
+	case ChecksumCRC32C:
+		p.ChecksumCRC32C = checksum
+	case ChecksumSHA1:
+		p.ChecksumSHA1 = checksum
+	case ChecksumSHA256:
+		p.ChecksumSHA256 = checksum
+	}
+}
+
+// multipartChecksum returns the checksum of the multipart object as AWS S3 forms it: checksum of concatenated
+// part checksums with the number of parts suffix, and the list of part checksums. Empty strings are returned
+// if some part has no checksum of the algorithm.
+func multipartChecksum(algorithm string, parts []*data.PartInfo) (string, string) {
+	h := newChecksumHash(algorithm)
+	if h == nil {
+		return "", ""
+	}
+
+	checksums := make([]string, len(parts))
+	for i, part := range parts {
+		if part.ChecksumAlgorithm != algorithm {
+			return "", ""
+		}
+		raw, err := base64.StdEncoding.DecodeString(part.Checksum)
+		if err != nil || len(raw) == 0 {
+			return "", ""
+		}
+		h.Write(raw)
+		checksums[i] = part.Checksum
+	}
+
+	return encodeChecksum(h) + "-" + strconv.Itoa(len(parts)), strings.Join(checksums, ",")
+}
@@ -33,6 +33,10 @@ func (n *layer) GetObjectTaggingAndLock(ctx context.Context, objVersion *ObjectV
 		}
 		return nil, nil, err
 	}
+	// lockInfo is nil if object has never been locked
+	if lockInfo == nil {
+		lockInfo = &data.LockInfo{}
+	}
 
 	n.cache.PutTagging(owner, objectTaggingCacheKey(objVersion), tags)
 	n.cache.PutLockInfo(owner, lockObjectKey(objVersion), lockInfo)
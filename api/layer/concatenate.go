@@ -0,0 +1,73 @@
+package layer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// ConcatenateObjects creates a new object with payload formed by payloads of source objects.
+// Unlike multipart upload sources have no minimum size limit. Encrypted sources are not supported.
+func (n *layer) ConcatenateObjects(ctx context.Context, p *ConcatenateObjectsParams) (*data.ExtendedObjectInfo, error) {
+	if len(p.Sources) == 0 || len(p.Sources) > UploadMaxPartNumber {
+		return nil, errors.GetAPIError(errors.ErrInvalidRequest)
+	}
+
+	var (
+		size                 int64
+		completedPartsHeader strings.Builder
+		parts                = make([]*data.PartInfo, 0, len(p.Sources))
+		packs                = make([]*data.PackInfo, 0, len(p.Sources))
+	)
+
+	for i, src := range p.Sources {
+		if FormEncryptionInfo(src.Headers).Enabled {
+			return nil, errors.GetAPIError(errors.ErrNotSupported)
+		}
+		if err := n.CheckQuarantine(ctx, p.BktInfo, src); err != nil {
+			return nil, err
+		}
+
+		part := &data.PartInfo{
+			Number: i + 1,
+			OID:    src.ID,
+			Size:   src.Size,
+			ETag:   src.HashSum,
+		}
+		parts = append(parts, part)
+		packs = append(packs, src.Pack)
+		size += src.Size
+
+		if i != 0 {
+			completedPartsHeader.WriteByte(',')
+		}
+		completedPartsHeader.WriteString(part.ToHeaderString())
+	}
+
+	header := make(map[string]string, len(p.Header)+1)
+	for key, val := range p.Header {
+		header[key] = val
+	}
+	header[UploadCompletedParts] = completedPartsHeader.String()
+
+	r := &multiObjectReader{
+		ctx:   ctx,
+		layer: n,
+		parts: parts,
+		packs: packs,
+	}
+
+	r.prm.bktInfo = p.BktInfo
+
+	return n.PutObject(ctx, &PutObjectParams{
+		BktInfo:      p.BktInfo,
+		Object:       p.Object,
+		Reader:       r,
+		Header:       header,
+		Size:         size,
+		Lock:         p.Lock,
+		CopiesNumber: p.CopiesNumber,
+	})
+}
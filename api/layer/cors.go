@@ -6,8 +6,9 @@ import (
 	"encoding/xml"
 	errorsStd "errors"
 	"fmt"
-	"io"
+	"strings"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"go.uber.org/zap"
@@ -18,13 +19,8 @@ const wildcard = "*"
 var supportedMethods = map[string]struct{}{"GET": {}, "HEAD": {}, "POST": {}, "PUT": {}, "DELETE": {}}
 
 func (n *layer) PutBucketCORS(ctx context.Context, p *PutCORSParams) error {
-	var (
-		buf  bytes.Buffer
-		tee  = io.TeeReader(p.Reader, &buf)
-		cors = &data.CORSConfiguration{}
-	)
-
-	if err := xml.NewDecoder(tee).Decode(cors); err != nil {
+	cors := &data.CORSConfiguration{}
+	if err := api.NewXMLDecoder(p.Reader).Decode(cors); err != nil {
 		return fmt.Errorf("xml decode cors: %w", err)
 	}
 
@@ -36,11 +32,18 @@ func (n *layer) PutBucketCORS(ctx context.Context, p *PutCORSParams) error {
 		return err
 	}
 
+	corsXML, err := xml.Marshal(cors)
+	if err != nil {
+		return fmt.Errorf("marshal cors: %w", err)
+	}
+
+	prevValue := n.marshaledCORS(ctx, p.BktInfo)
+
 	prm := PrmObjectCreate{
 		Container:    p.BktInfo.CID,
 		Creator:      p.BktInfo.Owner,
-		Payload:      p.Reader,
-		Filepath:     p.BktInfo.CORSObjectName(),
+		Payload:      bytes.NewReader(corsXML),
+		Filepath:     n.systemObjectName(p.BktInfo.CORSObjectName()),
 		CreationTime: TimeNow(ctx),
 		CopiesNumber: p.CopiesNumber,
 	}
@@ -66,8 +69,7 @@ func (n *layer) PutBucketCORS(ctx context.Context, p *PutCORSParams) error {
 	}
 
 	n.cache.PutCORS(n.Owner(ctx), p.BktInfo, cors)
-
-	return nil
+	return n.saveBucketConfigChange(ctx, p.BktInfo, ConfigTypeCORS, prevValue, p.CopiesNumber)
 }
 
 func (n *layer) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (*data.CORSConfiguration, error) {
@@ -83,6 +85,8 @@ func (n *layer) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (*d
 }
 
 func (n *layer) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) error {
+	prevValue := n.marshaledCORS(ctx, bktInfo)
+
 	objID, err := n.treeService.DeleteBucketCORS(ctx, bktInfo)
 	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
 	if err != nil && !objIDNotFound {
@@ -96,9 +100,32 @@ func (n *layer) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo)
 
 	n.cache.DeleteCORS(bktInfo)
 
+	if !objIDNotFound {
+		return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypeCORS, prevValue, 0)
+	}
+
 	return nil
 }
 
+// marshaledCORS returns current bucket CORS configuration in XML to save it in the bucket history.
+func (n *layer) marshaledCORS(ctx context.Context, bktInfo *data.BucketInfo) []byte {
+	cors, err := n.getCORS(ctx, bktInfo)
+	if err != nil {
+		if !errors.IsS3Error(err, errors.ErrNoSuchCORSConfiguration) {
+			n.log.Warn("couldn't get previous bucket cors", zap.Error(err))
+		}
+		return nil
+	}
+
+	corsXML, err := xml.Marshal(cors)
+	if err != nil {
+		n.log.Warn("couldn't marshal previous bucket cors", zap.Error(err))
+		return nil
+	}
+
+	return corsXML
+}
+
 func checkCORS(cors *data.CORSConfiguration) error {
 	for _, r := range cors.CORSRules {
 		for _, m := range r.AllowedMethods {
@@ -106,6 +133,11 @@ func checkCORS(cors *data.CORSConfiguration) error {
 				return errors.GetAPIErrorWithError(errors.ErrCORSUnsupportedMethod, fmt.Errorf("unsupported method is %s", m))
 			}
 		}
+		for _, o := range r.AllowedOrigins {
+			if strings.Count(o, wildcard) > 1 {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidRequest, fmt.Errorf("origin %s can't have more than one wildcard", o))
+			}
+		}
 		for _, h := range r.ExposeHeaders {
 			if h == wildcard {
 				return errors.GetAPIError(errors.ErrCORSWildcardExposeHeaders)
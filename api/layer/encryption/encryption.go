@@ -16,6 +16,12 @@ import (
 // Params contains encryption key info.
 type Params struct {
 	customerKey []byte
+	// managed is set if the key isn't provided by the customer (SSE-S3 or SSE-KMS).
+	managed    bool
+	wrappedKey []byte
+	// kms is set if the key is managed by the external KMS (SSE-KMS).
+	kms      bool
+	kmsKeyID string
 }
 
 // ObjectEncryption stores parsed object encryption headers.
@@ -24,6 +30,12 @@ type ObjectEncryption struct {
 	Algorithm string
 	HMACKey   string
 	HMACSalt  string
+	// WrappedKey is a data key wrapped with the master key of the gateway or with the KMS key,
+	// it's set only for objects encrypted with managed keys (SSE-S3 or SSE-KMS).
+	WrappedKey string
+	// KMSKeyID is an identifier of the KMS key which wraps the data key,
+	// it's set only for objects encrypted with keys managed by the external KMS (SSE-KMS).
+	KMSKeyID string
 }
 
 type encryptedPart struct {
@@ -75,14 +87,72 @@ func NewParams(key []byte) (*Params, error) {
 	return &p, nil
 }
 
+// NewManagedParams creates params to encrypt with the key managed by the gateway (SSE-S3).
+// The key is generated by the layer on write and is unwrapped from object headers on read.
+func NewManagedParams() Params {
+	return Params{managed: true}
+}
+
+// NewKMSParams creates params to encrypt with the key managed by the external KMS (SSE-KMS).
+// The data key is generated by the KMS key with the provided identifier, the default
+// KMS key of the gateway is used if the identifier is empty.
+func NewKMSParams(keyID string) Params {
+	return Params{managed: true, kms: true, kmsKeyID: keyID}
+}
+
+// NewKMSDataKeyParams creates params to encrypt with the data key generated by the external KMS.
+func NewKMSDataKeyParams(keyID string, key, wrappedKey []byte) (Params, error) {
+	if len(key) != aes256KeySize {
+		return Params{}, fmt.Errorf("invalid data key size: %d", len(key))
+	}
+
+	return Params{
+		customerKey: key,
+		managed:     true,
+		wrappedKey:  wrappedKey,
+		kms:         true,
+		kmsKeyID:    keyID,
+	}, nil
+}
+
 // Key returns encryption key.
 func (p Params) Key() []byte {
 	return p.customerKey
 }
 
-// Enabled returns true if key isn't empty.
+// Enabled returns true if key isn't empty or the key is managed by the gateway.
 func (p Params) Enabled() bool {
-	return len(p.customerKey) > 0
+	return len(p.customerKey) > 0 || p.managed
+}
+
+// Managed returns true if the key isn't provided by the customer (SSE-S3 or SSE-KMS).
+func (p Params) Managed() bool {
+	return p.managed
+}
+
+// WrappedKey returns the key wrapped with the master key of the gateway or with the KMS key.
+func (p Params) WrappedKey() []byte {
+	return p.wrappedKey
+}
+
+// KMS returns true if the key is managed by the external KMS (SSE-KMS).
+func (p Params) KMS() bool {
+	return p.kms
+}
+
+// KMSKeyID returns an identifier of the KMS key which wraps the data key.
+func (p Params) KMSKeyID() string {
+	return p.kmsKeyID
+}
+
+// Managed returns true if the object is encrypted with the managed key (SSE-S3 or SSE-KMS).
+func (e ObjectEncryption) Managed() bool {
+	return len(e.WrappedKey) > 0
+}
+
+// KMS returns true if the object is encrypted with the key managed by the external KMS (SSE-KMS).
+func (e ObjectEncryption) KMS() bool {
+	return len(e.KMSKeyID) > 0
 }
 
 // HMAC computes salted HMAC.
@@ -100,11 +170,19 @@ func (p Params) HMAC() ([]byte, []byte, error) {
 
 // MatchObjectEncryption checks if encryption params are valid for provided object.
 func (p Params) MatchObjectEncryption(encInfo ObjectEncryption) error {
-	if p.Enabled() != encInfo.Enabled {
+	if encInfo.Managed() && !p.managed {
+		// the key is unwrapped by the gateway, customer key isn't expected
+		if p.Enabled() {
+			return errorsStd.New("object is encrypted with the key managed by the gateway")
+		}
+		return nil
+	}
+
+	if p.Enabled() != encInfo.Enabled || p.managed != encInfo.Managed() || p.kms != encInfo.KMS() {
 		return errorsStd.New("invalid encryption view")
 	}
 
-	if !encInfo.Enabled {
+	if !encInfo.Enabled || len(p.customerKey) == 0 {
 		return nil
 	}
 
@@ -171,8 +249,8 @@ func NewDecrypter(p Params, encryptedObjectSize uint64, r *Range) (*Decrypter, e
 }
 
 func newDecrypter(p Params, parts []encryptedPart, r *Range) (*Decrypter, error) {
-	if !p.Enabled() {
-		return nil, errorsStd.New("couldn't create decrypter with disabled encryption")
+	if len(p.Key()) == 0 {
+		return nil, errorsStd.New("couldn't create decrypter without key")
 	}
 
 	if r != nil && r.Start > r.End {
@@ -36,6 +36,31 @@ func TestHMAC(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMasterKey(t *testing.T) {
+	masterKey, err := NewMasterKey(getAES256Key())
+	require.NoError(t, err)
+
+	associatedData := []byte("container/object")
+
+	encParam, err := masterKey.NewParams(associatedData)
+	require.NoError(t, err)
+	require.True(t, encParam.Managed())
+	require.Len(t, encParam.Key(), aes256KeySize)
+
+	unwrapped, err := masterKey.UnwrapParams(encParam.WrappedKey(), associatedData)
+	require.NoError(t, err)
+	require.Equal(t, encParam.Key(), unwrapped.Key())
+
+	// the key is bound to the associated data
+	_, err = masterKey.UnwrapParams(encParam.WrappedKey(), []byte("other-container/object"))
+	require.Error(t, err)
+
+	otherMasterKey, err := DeriveMasterKey([]byte("secret"))
+	require.NoError(t, err)
+	_, err = otherMasterKey.UnwrapParams(encParam.WrappedKey(), associatedData)
+	require.Error(t, err)
+}
+
 const (
 	objSize     = 30 * 1024 * 1024
 	partNum     = 6
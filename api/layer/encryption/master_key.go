@@ -0,0 +1,88 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	errorsStd "errors"
+	"fmt"
+)
+
+// MasterKey wraps data keys of objects encrypted with keys managed by the gateway (SSE-S3).
+// Every object is encrypted with its own random data key, the data key is stored
+// in the object headers encrypted with the master key.
+type MasterKey struct {
+	aead cipher.AEAD
+}
+
+// masterKeyLabel separates the derived master key from other usages of the secret.
+const masterKeyLabel = "neofs-s3-gw sse-s3 master key"
+
+// NewMasterKey creates master key from AES-256 key.
+func NewMasterKey(key []byte) (*MasterKey, error) {
+	if len(key) != aes256KeySize {
+		return nil, fmt.Errorf("invalid master key size: %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+
+	return &MasterKey{aead: aead}, nil
+}
+
+// DeriveMasterKey creates master key derived from the secret (e.g. the private key of the gateway wallet).
+func DeriveMasterKey(secret []byte) (*MasterKey, error) {
+	mac := hmac.New(sha256.New, []byte(masterKeyLabel))
+	mac.Write(secret)
+	return NewMasterKey(mac.Sum(nil))
+}
+
+// NewParams generates a new data key and returns params to encrypt an object with it.
+// The data key is wrapped with the associated data (e.g. the container and the name of the object),
+// it can be unwrapped only with the same associated data.
+func (m *MasterKey) NewParams(associatedData []byte) (Params, error) {
+	key := make([]byte, aes256KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return Params{}, fmt.Errorf("generate data key: %w", err)
+	}
+
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Params{}, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	return Params{
+		customerKey: key,
+		managed:     true,
+		wrappedKey:  m.aead.Seal(nonce, nonce, key, associatedData),
+	}, nil
+}
+
+// UnwrapParams returns params to decrypt an object with the data key wrapped by NewParams
+// with the same associated data.
+func (m *MasterKey) UnwrapParams(wrappedKey, associatedData []byte) (Params, error) {
+	nonceSize := m.aead.NonceSize()
+	if len(wrappedKey) < nonceSize {
+		return Params{}, errorsStd.New("wrapped key is too short")
+	}
+
+	key, err := m.aead.Open(nil, wrappedKey[:nonceSize], wrappedKey[nonceSize:], associatedData)
+	if err != nil {
+		return Params{}, fmt.Errorf("unwrap data key: %w", err)
+	}
+
+	return Params{
+		customerKey: key,
+		managed:     true,
+		wrappedKey:  wrappedKey,
+	}, nil
+}
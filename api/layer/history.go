@@ -0,0 +1,113 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"go.uber.org/zap"
+)
+
+// Types of bucket configuration saved in the bucket configuration history.
+const (
+	ConfigTypeACL          = "acl"
+	ConfigTypePolicy       = "policy"
+	ConfigTypeCORS         = "cors"
+	ConfigTypeNotification = "notification"
+	ConfigTypeLifecycle    = "lifecycle"
+	ConfigTypeWebsite      = "website"
+	ConfigTypeInventory    = "inventory"
+	ConfigTypeReplication  = "replication"
+	ConfigTypeAnalytics    = "analytics"
+	ConfigTypeMetrics      = "metrics"
+)
+
+// GetBucketConfigHistory returns the history of bucket configuration changes, the oldest change goes first.
+func (n *layer) GetBucketConfigHistory(ctx context.Context, bktInfo *data.BucketInfo) ([]data.ConfigChange, error) {
+	objIDs, err := n.treeService.GetBucketConfigChanges(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]data.ConfigChange, 0, len(objIDs))
+	for _, objID := range objIDs {
+		obj, err := n.objectGet(ctx, bktInfo, objID)
+		if err != nil {
+			return nil, err
+		}
+
+		var change data.ConfigChange
+		if err = json.Unmarshal(obj.Payload(), &change); err != nil {
+			return nil, fmt.Errorf("unmarshal config change: %w", err)
+		}
+		history = append(history, change)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Time.Before(history[j].Time)
+	})
+
+	return history, nil
+}
+
+// saveBucketConfigChange saves a change of the bucket configuration to the bucket history.
+// Configuration is already changed at this point, the error is returned to fail the request,
+// so the change isn't left unrecorded silently and the client retries it.
+func (n *layer) saveBucketConfigChange(ctx context.Context, bktInfo *data.BucketInfo, configType string, prevValue []byte, copiesNumber uint32) error {
+	return n.saveBucketConfigUpdate(ctx, bktInfo, configType, prevValue, nil, copiesNumber)
+}
+
+// saveBucketConfigUpdate saves a change of the bucket configuration with the new value to the bucket history.
+func (n *layer) saveBucketConfigUpdate(ctx context.Context, bktInfo *data.BucketInfo, configType string, prevValue, value []byte, copiesNumber uint32) error {
+	change := data.ConfigChange{
+		Type:          configType,
+		Owner:         n.Owner(ctx).EncodeToString(),
+		Time:          TimeNow(ctx),
+		PreviousValue: string(prevValue),
+		Value:         string(value),
+	}
+
+	if err := n.putBucketConfigChange(ctx, bktInfo, change, copiesNumber); err != nil {
+		return fmt.Errorf("couldn't save %s change to bucket config history: %w", configType, err)
+	}
+
+	return nil
+}
+
+// putBucketConfigChange stores every change in a separate object, so saving a change
+// doesn't depend on the size of the history.
+func (n *layer) putBucketConfigChange(ctx context.Context, bktInfo *data.BucketInfo, change data.ConfigChange, copiesNumber uint32) error {
+	changeJSON, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("marshal config change: %w", err)
+	}
+
+	prm := PrmObjectCreate{
+		Container:    bktInfo.CID,
+		Creator:      bktInfo.Owner,
+		Payload:      bytes.NewReader(changeJSON),
+		Filepath:     n.systemObjectName(bktInfo.ConfigHistoryObjectName()),
+		CreationTime: change.Time,
+		CopiesNumber: copiesNumber,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, bktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	if err = n.treeService.AddBucketConfigChange(ctx, bktInfo, objID); err != nil {
+		if errDelete := n.objectDelete(ctx, bktInfo, objID); errDelete != nil {
+			n.log.Error("couldn't delete config change object", zap.Error(errDelete),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", objID.EncodeToString()))
+		}
+		return err
+	}
+
+	return nil
+}
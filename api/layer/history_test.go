@@ -0,0 +1,42 @@
+package layer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBucketConfigChangeFailure(t *testing.T) {
+	tc := prepareContext(t)
+
+	faultyNeoFS := NewFaultyNeoFS(tc.testNeoFS)
+	tc.layer = NewLayer(zap.NewExample(), faultyNeoFS, &Config{
+		Caches:      DefaultCachesConfigs(zap.NewExample()),
+		AnonKey:     tc.layer.(*layer).anonKey,
+		TreeService: tc.layer.(*layer).treeService,
+	})
+
+	const cors = `<CORSConfiguration><CORSRule><AllowedMethod>GET</AllowedMethod><AllowedOrigin>*</AllowedOrigin></CORSRule></CORSConfiguration>`
+	putCORS := func() error {
+		return tc.layer.PutBucketCORS(tc.ctx, &PutCORSParams{BktInfo: tc.bktInfo, Reader: strings.NewReader(cors)})
+	}
+
+	// the configuration object is saved, but the history entry isn't
+	faultyNeoFS.Inject(Fault{Op: FaultCreateObject, Skip: 1, Count: 1, Err: errors.New("storage is unavailable")})
+	require.ErrorContains(t, putCORS(), "bucket config history")
+
+	history, err := tc.layer.GetBucketConfigHistory(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	// the retried request records the change
+	require.NoError(t, putCORS())
+
+	history, err = tc.layer.GetBucketConfigHistory(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, ConfigTypeCORS, history[0].Type)
+}
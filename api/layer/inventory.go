@@ -0,0 +1,649 @@
+package layer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/s3select"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// PutBucketInventoryParams stores PutBucketInventoryConfiguration request parameters.
+type PutBucketInventoryParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.InventoryConfiguration
+	CopiesNumber  uint32
+}
+
+type (
+	// inventoryField is a column of the inventory report.
+	inventoryField struct {
+		// name is a name of the field in the CSV schema.
+		name   string
+		column s3select.ParquetColumn
+		// urlEncoded values are URL-encoded in CSV reports.
+		urlEncoded bool
+		value      func(bktName string, obj *data.ExtendedObjectInfo) interface{}
+	}
+
+	// inventoryFile is a data file of the inventory report.
+	inventoryFile interface {
+		add(row []interface{}) error
+		rows() int
+		payload() ([]byte, error)
+	}
+
+	csvInventoryFile struct {
+		fields []inventoryField
+		buf    *bytes.Buffer
+		gz     *gzip.Writer
+		n      int
+	}
+
+	parquetInventoryFile struct {
+		*s3select.ParquetWriter
+	}
+
+	// inventoryManifest describes files of the inventory report in the AWS S3 format.
+	inventoryManifest struct {
+		SourceBucket      string                  `json:"sourceBucket"`
+		DestinationBucket string                  `json:"destinationBucket"`
+		Version           string                  `json:"version"`
+		CreationTimestamp string                  `json:"creationTimestamp"`
+		FileFormat        string                  `json:"fileFormat"`
+		FileSchema        string                  `json:"fileSchema"`
+		Files             []inventoryManifestFile `json:"files"`
+	}
+
+	inventoryManifestFile struct {
+		Key         string `json:"key"`
+		Size        int64  `json:"size"`
+		MD5Checksum string `json:"MD5checksum"`
+	}
+)
+
+const (
+	// inventoryMaxConfigurations limits the number of inventory configurations of a bucket as AWS S3 does.
+	inventoryMaxConfigurations = 1000
+	// inventoryMaxIDLength limits the length of inventory configuration id.
+	inventoryMaxIDLength = 64
+	// inventoryFileRows limits the number of objects in a data file of the inventory report,
+	// the file is kept in memory until it's stored.
+	inventoryFileRows = 100000
+
+	inventoryDestinationARNPrefix = "arn:aws:s3:::"
+	inventoryManifestVersion      = "2016-11-30"
+	inventoryParquetSchemaName    = "s3.inventory"
+	inventoryDateLayout           = "2006-01-02T15-04Z"
+	inventoryCSVTimeLayout        = "2006-01-02T15:04:05.000Z"
+	inventoryStorageClass         = "STANDARD"
+)
+
+var (
+	inventoryBaseFields = []inventoryField{
+		{name: "Bucket", column: s3select.ParquetColumn{Name: "bucket", Type: s3select.ParquetString},
+			value: func(bktName string, _ *data.ExtendedObjectInfo) interface{} { return bktName }},
+		{name: "Key", column: s3select.ParquetColumn{Name: "key", Type: s3select.ParquetString}, urlEncoded: true,
+			value: func(_ string, obj *data.ExtendedObjectInfo) interface{} { return obj.ObjectInfo.Name }},
+	}
+
+	inventoryVersionFields = []inventoryField{
+		{name: "VersionId", column: s3select.ParquetColumn{Name: "version_id", Type: s3select.ParquetString, Optional: true},
+			value: func(_ string, obj *data.ExtendedObjectInfo) interface{} { return obj.Version() }},
+		{name: "IsLatest", column: s3select.ParquetColumn{Name: "is_latest", Type: s3select.ParquetBoolean, Optional: true},
+			value: func(_ string, obj *data.ExtendedObjectInfo) interface{} { return obj.IsLatest }},
+		{name: "IsDeleteMarker", column: s3select.ParquetColumn{Name: "is_delete_marker", Type: s3select.ParquetBoolean, Optional: true},
+			value: func(_ string, obj *data.ExtendedObjectInfo) interface{} { return obj.ObjectInfo.IsDeleteMarker }},
+	}
+
+	inventoryOptionalFields = map[string]inventoryField{
+		data.InventoryFieldSize: {name: data.InventoryFieldSize,
+			column: s3select.ParquetColumn{Name: "size", Type: s3select.ParquetInt64, Optional: true},
+			value: func(_ string, obj *data.ExtendedObjectInfo) interface{} {
+				if obj.ObjectInfo.IsDeleteMarker {
+					return nil
+				}
+				return obj.ObjectInfo.Size
+			}},
+		data.InventoryFieldLastModifiedDate: {name: data.InventoryFieldLastModifiedDate,
+			column: s3select.ParquetColumn{Name: "last_modified_date", Type: s3select.ParquetTimestamp, Optional: true},
+			value:  func(_ string, obj *data.ExtendedObjectInfo) interface{} { return obj.ObjectInfo.Created }},
+		data.InventoryFieldETag: {name: data.InventoryFieldETag,
+			column: s3select.ParquetColumn{Name: "e_tag", Type: s3select.ParquetString, Optional: true},
+			value: func(_ string, obj *data.ExtendedObjectInfo) interface{} {
+				if obj.ObjectInfo.IsDeleteMarker {
+					return nil
+				}
+				return obj.ObjectInfo.HashSum
+			}},
+		data.InventoryFieldStorageClass: {name: data.InventoryFieldStorageClass,
+			column: s3select.ParquetColumn{Name: "storage_class", Type: s3select.ParquetString, Optional: true},
+			value: func(_ string, obj *data.ExtendedObjectInfo) interface{} {
+				if obj.ObjectInfo.IsDeleteMarker {
+					return nil
+				}
+				return inventoryStorageClass
+			}},
+	}
+)
+
+// PutBucketInventoryConfiguration adds the inventory configuration to the bucket or replaces
+// the configuration with the same id.
+func (n *layer) PutBucketInventoryConfiguration(ctx context.Context, p *PutBucketInventoryParams) error {
+	if err := checkInventory(p.Configuration); err != nil {
+		return err
+	}
+
+	configurations, err := n.getInventoryConfigurations(ctx, p.BktInfo)
+	if err != nil {
+		return err
+	}
+
+	newConfigurations := &data.InventoryConfigurations{
+		Configurations: make([]data.InventoryConfiguration, 0, len(configurations.Configurations)+1),
+	}
+	var replaced bool
+	for _, conf := range configurations.Configurations {
+		if conf.ID == p.Configuration.ID {
+			conf, replaced = *p.Configuration, true
+		}
+		newConfigurations.Configurations = append(newConfigurations.Configurations, conf)
+	}
+	if !replaced {
+		if len(newConfigurations.Configurations) >= inventoryMaxConfigurations {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+				fmt.Errorf("bucket can't have more than %d inventory configurations", inventoryMaxConfigurations))
+		}
+		newConfigurations.Configurations = append(newConfigurations.Configurations, *p.Configuration)
+	}
+
+	sort.Slice(newConfigurations.Configurations, func(i, j int) bool {
+		return newConfigurations.Configurations[i].ID < newConfigurations.Configurations[j].ID
+	})
+
+	return n.putInventoryConfigurations(ctx, p.BktInfo, configurations, newConfigurations, p.CopiesNumber)
+}
+
+// GetBucketInventoryConfiguration returns the inventory configuration of the bucket with the id.
+func (n *layer) GetBucketInventoryConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) (*data.InventoryConfiguration, error) {
+	configurations, err := n.getInventoryConfigurations(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range configurations.Configurations {
+		if configurations.Configurations[i].ID == id {
+			return &configurations.Configurations[i], nil
+		}
+	}
+
+	return nil, errors.GetAPIError(errors.ErrNoSuchConfiguration)
+}
+
+// ListBucketInventoryConfigurations returns all inventory configurations of the bucket sorted by id.
+func (n *layer) ListBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo) ([]data.InventoryConfiguration, error) {
+	configurations, err := n.getInventoryConfigurations(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return configurations.Configurations, nil
+}
+
+// DeleteBucketInventoryConfiguration removes the inventory configuration of the bucket with the id,
+// reports written by the configuration are kept.
+func (n *layer) DeleteBucketInventoryConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) error {
+	configurations, err := n.getInventoryConfigurations(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+
+	newConfigurations := &data.InventoryConfigurations{}
+	for _, conf := range configurations.Configurations {
+		if conf.ID != id {
+			newConfigurations.Configurations = append(newConfigurations.Configurations, conf)
+		}
+	}
+	if len(newConfigurations.Configurations) == len(configurations.Configurations) {
+		return errors.GetAPIError(errors.ErrNoSuchConfiguration)
+	}
+
+	return n.putInventoryConfigurations(ctx, bktInfo, configurations, newConfigurations, 0)
+}
+
+// getInventoryConfigurations returns inventory configurations of the bucket, the bucket without
+// configurations gets the empty list.
+func (n *layer) getInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (*data.InventoryConfigurations, error) {
+	owner := n.Owner(ctx)
+	if configurations := n.cache.GetInventoryConfigurations(owner, bktInfo); configurations != nil {
+		return configurations, nil
+	}
+
+	configurations := &data.InventoryConfigurations{}
+	objID, err := n.treeService.GetBucketInventoryConfigurations(ctx, bktInfo)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		obj, err := n.objectGet(ctx, bktInfo, objID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = xml.Unmarshal(obj.Payload(), configurations); err != nil {
+			return nil, fmt.Errorf("unmarshal inventory configurations: %w", err)
+		}
+	}
+
+	n.cache.PutInventoryConfigurations(owner, bktInfo, configurations)
+
+	return configurations, nil
+}
+
+// putInventoryConfigurations saves inventory configurations of the bucket as a system object,
+// the object is removed if there are no configurations.
+func (n *layer) putInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo, prev, configurations *data.InventoryConfigurations, copiesNumber uint32) error {
+	var prevValue []byte
+	if len(prev.Configurations) != 0 {
+		var err error
+		if prevValue, err = xml.Marshal(prev); err != nil {
+			n.log.Warn("couldn't marshal previous bucket inventory configurations", zap.Error(err))
+		}
+	}
+
+	var (
+		objIDToDelete oid.ID
+		err           error
+	)
+	if len(configurations.Configurations) == 0 {
+		objIDToDelete, err = n.treeService.DeleteBucketInventoryConfigurations(ctx, bktInfo)
+	} else {
+		var confXML []byte
+		if confXML, err = xml.Marshal(configurations); err != nil {
+			return fmt.Errorf("marshal inventory configurations: %w", err)
+		}
+
+		prm := PrmObjectCreate{
+			Container:    bktInfo.CID,
+			Creator:      bktInfo.Owner,
+			Payload:      bytes.NewReader(confXML),
+			Filepath:     n.systemObjectName(bktInfo.InventoryConfigurationObjectName()),
+			CreationTime: TimeNow(ctx),
+			CopiesNumber: copiesNumber,
+		}
+
+		var objID oid.ID
+		if objID, _, err = n.objectPutAndHash(ctx, prm, bktInfo); err != nil {
+			return fmt.Errorf("put system object: %w", err)
+		}
+
+		objIDToDelete, err = n.treeService.PutBucketInventoryConfigurations(ctx, bktInfo, objID)
+	}
+
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete inventory configurations object", zap.Error(err),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	n.cache.PutInventoryConfigurations(n.Owner(ctx), bktInfo, configurations)
+	return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypeInventory, prevValue, copiesNumber)
+}
+
+func checkInventory(conf *data.InventoryConfiguration) error {
+	if len(conf.ID) == 0 || len(conf.ID) > inventoryMaxIDLength {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid inventory id '%s'", conf.ID))
+	}
+	for _, r := range conf.ID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid inventory id '%s'", conf.ID))
+		}
+	}
+
+	dst := conf.Destination.S3BucketDestination
+	if !strings.HasPrefix(dst.Bucket, inventoryDestinationARNPrefix) || len(dst.Bucket) == len(inventoryDestinationARNPrefix) {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid destination bucket '%s'", dst.Bucket))
+	}
+
+	switch dst.Format {
+	case data.InventoryFormatCSV, data.InventoryFormatParquet:
+	case data.InventoryFormatORC:
+		return errors.GetAPIErrorWithError(errors.ErrNotImplemented, errorsStd.New("ORC inventory format isn't supported"))
+	default:
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	if conf.Schedule.Frequency != data.InventoryFrequencyDaily && conf.Schedule.Frequency != data.InventoryFrequencyWeekly {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	if conf.IncludedObjectVersions != data.InventoryVersionsAll && conf.IncludedObjectVersions != data.InventoryVersionsCurrent {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	if conf.OptionalFields != nil {
+		fields := make(map[string]struct{}, len(conf.OptionalFields.Fields))
+		for _, field := range conf.OptionalFields.Fields {
+			if _, ok := inventoryOptionalFields[field]; !ok {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("unsupported inventory field '%s'", field))
+			}
+			if _, ok := fields[field]; ok {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("duplicated inventory field '%s'", field))
+			}
+			fields[field] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// WriteInventoryReports writes reports of enabled inventory configurations of the bucket which are due
+// according to their schedules and returns the number of written reports. The report of the period is
+// written once: it's skipped if the manifest of the period exists in the destination bucket.
+func (n *layer) WriteInventoryReports(ctx context.Context, bktInfo *data.BucketInfo) (int, error) {
+	configurations, err := n.getInventoryConfigurations(ctx, bktInfo)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get inventory configurations: %w", err)
+	}
+
+	var written int
+	now := TimeNow(ctx)
+	for i := range configurations.Configurations {
+		conf := &configurations.Configurations[i]
+		if !conf.IsEnabled {
+			continue
+		}
+
+		ok, err := n.writeInventoryReport(ctx, bktInfo, conf, now)
+		if err != nil {
+			return written, fmt.Errorf("couldn't write inventory report '%s': %w", conf.ID, err)
+		}
+		if ok {
+			written++
+		}
+	}
+
+	return written, nil
+}
+
+// writeInventoryReport writes data files, the checksum and the manifest of the report if the manifest
+// of the current period doesn't exist. The manifest is written last, so the failed report is written again.
+func (n *layer) writeInventoryReport(ctx context.Context, bktInfo *data.BucketInfo, conf *data.InventoryConfiguration, now time.Time) (bool, error) {
+	dst := conf.Destination.S3BucketDestination
+	dstBktInfo, err := n.GetBucketInfo(ctx, strings.TrimPrefix(dst.Bucket, inventoryDestinationARNPrefix))
+	if err != nil {
+		return false, fmt.Errorf("get destination bucket: %w", err)
+	}
+
+	reportPrefix := inventoryReportPrefix(bktInfo.Name, conf)
+	manifestPrefix := reportPrefix + inventoryPeriodStart(now, conf.Schedule.Frequency).Format(inventoryDateLayout) + "/"
+	_, err = n.GetObjectInfo(ctx, &HeadObjectParams{BktInfo: dstBktInfo, Object: manifestPrefix + "manifest.json"})
+	if err == nil {
+		return false, nil
+	}
+	if !errors.IsS3Error(err, errors.ErrNoSuchKey) {
+		return false, fmt.Errorf("get manifest: %w", err)
+	}
+
+	fields := inventoryFields(conf)
+	file := newInventoryFile(dst.Format, fields)
+	manifest := &inventoryManifest{
+		SourceBucket:      bktInfo.Name,
+		DestinationBucket: dst.Bucket,
+		Version:           inventoryManifestVersion,
+		CreationTimestamp: strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10),
+		FileFormat:        dst.Format,
+		FileSchema:        inventorySchema(dst.Format, fields),
+		Files:             []inventoryManifestFile{},
+	}
+
+	flush := func() error {
+		payload, err := file.payload()
+		if err != nil {
+			return fmt.Errorf("encode data file: %w", err)
+		}
+
+		key := reportPrefix + "data/" + uuid.New().String() + inventoryFileExtension(dst.Format)
+		if err = n.putInventoryObject(ctx, dstBktInfo, key, payload); err != nil {
+			return err
+		}
+
+		checksum := md5.Sum(payload)
+		manifest.Files = append(manifest.Files, inventoryManifestFile{
+			Key:         key,
+			Size:        int64(len(payload)),
+			MD5Checksum: hex.EncodeToString(checksum[:]),
+		})
+		file = newInventoryFile(dst.Format, fields)
+		return nil
+	}
+
+	p := &scanObjectsParams{
+		BktInfo:     bktInfo,
+		AllVersions: conf.IncludedObjectVersions == data.InventoryVersionsAll,
+	}
+	if conf.Filter != nil {
+		p.Prefix = conf.Filter.Prefix
+	}
+
+	err = n.scanObjects(ctx, p, func(obj *data.ExtendedObjectInfo) error {
+		if !p.AllVersions && obj.ObjectInfo.IsDeleteMarker {
+			return nil
+		}
+
+		row := make([]interface{}, len(fields))
+		for i, field := range fields {
+			row[i] = field.value(bktInfo.Name, obj)
+		}
+		if err := file.add(row); err != nil {
+			return fmt.Errorf("add object '%s': %w", obj.ObjectInfo.Name, err)
+		}
+
+		if file.rows() >= inventoryFileRows {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("scan objects: %w", err)
+	}
+
+	if file.rows() != 0 {
+		if err = flush(); err != nil {
+			return false, err
+		}
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return false, fmt.Errorf("marshal manifest: %w", err)
+	}
+	checksum := md5.Sum(manifestJSON)
+
+	if err = n.putInventoryObject(ctx, dstBktInfo, manifestPrefix+"manifest.checksum", []byte(hex.EncodeToString(checksum[:]))); err != nil {
+		return false, err
+	}
+	if err = n.putInventoryObject(ctx, dstBktInfo, manifestPrefix+"manifest.json", manifestJSON); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (n *layer) putInventoryObject(ctx context.Context, bktInfo *data.BucketInfo, key string, payload []byte) error {
+	_, err := n.PutObject(ctx, &PutObjectParams{
+		BktInfo: bktInfo,
+		Object:  key,
+		Size:    int64(len(payload)),
+		Reader:  bytes.NewReader(payload),
+		Header:  map[string]string{api.ContentType: inventoryContentType(key)},
+	})
+	if err != nil {
+		return fmt.Errorf("put object '%s': %w", key, err)
+	}
+
+	return nil
+}
+
+// inventoryReportPrefix returns the prefix of the report files in the destination bucket as AWS S3 forms it:
+// the destination prefix, the source bucket name and the configuration id.
+func inventoryReportPrefix(bktName string, conf *data.InventoryConfiguration) string {
+	prefix := bktName + "/" + conf.ID + "/"
+	if dstPrefix := conf.Destination.S3BucketDestination.Prefix; len(dstPrefix) != 0 {
+		prefix = strings.TrimSuffix(dstPrefix, "/") + "/" + prefix
+	}
+	return prefix
+}
+
+// inventoryPeriodStart returns the start of the current period of the schedule: midnight UTC
+// of the current day for daily reports and of the last Sunday for weekly reports.
+func inventoryPeriodStart(now time.Time, frequency string) time.Time {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if frequency == data.InventoryFrequencyWeekly {
+		day = day.AddDate(0, 0, -int(day.Weekday()))
+	}
+	return day
+}
+
+// inventoryFields returns the fields of the report: the bucket and the key, fields of versions
+// if all versions are included and the optional fields in the configured order.
+func inventoryFields(conf *data.InventoryConfiguration) []inventoryField {
+	fields := append([]inventoryField{}, inventoryBaseFields...)
+	if conf.IncludedObjectVersions == data.InventoryVersionsAll {
+		fields = append(fields, inventoryVersionFields...)
+	}
+	if conf.OptionalFields != nil {
+		for _, name := range conf.OptionalFields.Fields {
+			fields = append(fields, inventoryOptionalFields[name])
+		}
+	}
+	return fields
+}
+
+func inventorySchema(format string, fields []inventoryField) string {
+	if format == data.InventoryFormatParquet {
+		return s3select.NewParquetWriter(inventoryParquetSchemaName, inventoryColumns(fields)).Schema()
+	}
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	return strings.Join(names, ", ")
+}
+
+func inventoryColumns(fields []inventoryField) []s3select.ParquetColumn {
+	columns := make([]s3select.ParquetColumn, len(fields))
+	for i, field := range fields {
+		columns[i] = field.column
+	}
+	return columns
+}
+
+func inventoryFileExtension(format string) string {
+	if format == data.InventoryFormatParquet {
+		return ".parquet"
+	}
+	return ".csv.gz"
+}
+
+func inventoryContentType(key string) string {
+	switch {
+	case strings.HasSuffix(key, ".json"):
+		return "application/json"
+	case strings.HasSuffix(key, ".checksum"):
+		return "text/plain"
+	case strings.HasSuffix(key, ".csv"):
+		return "text/csv"
+	case strings.HasSuffix(key, ".csv.gz"):
+		return "application/gzip"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func newInventoryFile(format string, fields []inventoryField) inventoryFile {
+	if format == data.InventoryFormatParquet {
+		return parquetInventoryFile{s3select.NewParquetWriter(inventoryParquetSchemaName, inventoryColumns(fields))}
+	}
+
+	buf := new(bytes.Buffer)
+	return &csvInventoryFile{fields: fields, buf: buf, gz: gzip.NewWriter(buf)}
+}
+
+// add writes the row of quoted values as AWS S3 does.
+func (f *csvInventoryFile) add(row []interface{}) error {
+	values := make([]string, len(row))
+	for i, value := range row {
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+			if f.fields[i].urlEncoded {
+				s = url.QueryEscape(v)
+			}
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		case bool:
+			s = strconv.FormatBool(v)
+		case time.Time:
+			s = v.UTC().Format(inventoryCSVTimeLayout)
+		}
+		values[i] = `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+
+	if _, err := f.gz.Write([]byte(strings.Join(values, ",") + "\n")); err != nil {
+		return err
+	}
+	f.n++
+	return nil
+}
+
+func (f *csvInventoryFile) rows() int {
+	return f.n
+}
+
+func (f *csvInventoryFile) payload() ([]byte, error) {
+	if err := f.gz.Close(); err != nil {
+		return nil, err
+	}
+	return f.buf.Bytes(), nil
+}
+
+func (f parquetInventoryFile) add(row []interface{}) error {
+	return f.Write(row)
+}
+
+func (f parquetInventoryFile) rows() int {
+	return f.Rows()
+}
+
+func (f parquetInventoryFile) payload() ([]byte, error) {
+	return f.Bytes(), nil
+}
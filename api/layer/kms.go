@@ -0,0 +1,14 @@
+package layer
+
+import (
+	"context"
+)
+
+// KeyManagementService is an external KMS which generates and unwraps data keys
+// of objects encrypted with SSE-KMS. Only wrapped data keys are stored in object headers.
+type KeyManagementService interface {
+	// GenerateDataKey returns a new AES-256 data key and the data key wrapped with the KMS key.
+	GenerateDataKey(ctx context.Context, keyID string) (key []byte, wrappedKey []byte, err error)
+	// Decrypt unwraps the data key wrapped with the KMS key.
+	Decrypt(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
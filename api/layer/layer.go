@@ -4,19 +4,28 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	errorsStd "errors"
 	"fmt"
 	"io"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/s3select"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
@@ -45,13 +54,33 @@ type (
 	}
 
 	layer struct {
-		neoFS       NeoFS
-		log         *zap.Logger
-		anonKey     AnonymousKey
-		resolver    BucketResolver
-		ncontroller EventListener
-		cache       *Cache
-		treeService TreeService
+		neoFS        NeoFS
+		log          *zap.Logger
+		anonKey      AnonymousKey
+		resolver     BucketResolver
+		ncontroller  EventListener
+		cache        *Cache
+		treeService  TreeService
+		objectIndex  ObjectIndex
+		trashPurger  *trashPurger
+		replicator   *replicator
+		versionLocks *versionLocks
+		masterKey    *encryption.MasterKey
+		kms          KeyManagementService
+		kmsKeyID     string
+
+		consistentListing   bool
+		partRetries         int
+		partRetryBufferSize int64
+		deleteConcurrency   int
+		replicaNetworks     map[string]NeoFS
+		// archiveStorageClasses are archive containers by storage class names.
+		archiveStorageClasses map[string]cid.ID
+		// systemObjectPrefix is the prefix of names of bucket system objects.
+		systemObjectPrefix string
+		// quarantineTagKey and quarantineTagValue are the tag of quarantined objects.
+		quarantineTagKey   string
+		quarantineTagValue string
 	}
 
 	Config struct {
@@ -60,6 +89,39 @@ type (
 		AnonKey      AnonymousKey
 		Resolver     BucketResolver
 		TreeService  TreeService
+		// ConsistentListing disables cache of object listings, so objects
+		// uploaded through other gateway instances are listed immediately.
+		ConsistentListing bool
+		// ObjectIndex is an optional persistent index of object headers.
+		ObjectIndex ObjectIndex
+		// MasterKey wraps data keys of objects encrypted with keys managed by the gateway (SSE-S3).
+		MasterKey *encryption.MasterKey
+		// KMS generates and unwraps data keys of objects encrypted with SSE-KMS.
+		KMS KeyManagementService
+		// KMSKeyID is the KMS key used if the request doesn't specify one.
+		KMSKeyID string
+		// PartRetries is a number of extra attempts to store a multipart upload part
+		// if NeoFS write fails.
+		PartRetries int
+		// PartRetryBufferSize is the max size of the part buffered in memory to be retried.
+		// Larger parts are stored with a single attempt.
+		PartRetryBufferSize int64
+		// DeleteObjectsConcurrency is a number of objects of DeleteObjects deleted concurrently,
+		// zero means DefaultDeleteObjectsConcurrency.
+		DeleteObjectsConcurrency int
+		// ReplicaNetworks are NeoFS networks of secondary containers of synchronous replication by name.
+		// Secondary containers in the bucket network don't need to be listed.
+		ReplicaNetworks map[string]NeoFS
+		// ArchiveStorageClasses are containers with the cold placement policy objects are transitioned
+		// to by the bucket lifecycle, by storage class names.
+		ArchiveStorageClasses map[string]cid.ID
+		// SystemObjectPrefix is the prefix of names of bucket system objects replacing
+		// data.DefaultSystemObjectPrefix, empty value means the default prefix.
+		SystemObjectPrefix string
+		// QuarantineTagKey and QuarantineTagValue are the tag of objects quarantined by malware scanning,
+		// payloads of such objects can't be read. Empty key disables the quarantine.
+		QuarantineTagKey   string
+		QuarantineTagValue string
 	}
 
 	// AnonymousKey contains data for anonymous requests.
@@ -107,12 +169,31 @@ type (
 		Lock         *data.ObjectLock
 		Encryption   encryption.Params
 		CopiesNumber uint32
+		// ETag of the object, it's formed from the payload with the bucket ETag algorithm if empty.
+		ETag string
+		// BucketOwnerFullControl is set if the object is written with bucket-owner-full-control canned ACL.
+		BucketOwnerFullControl bool
+		// Replica is set if the object is written by the bucket replication, it isn't replicated further.
+		Replica bool
+		// ChecksumAlgorithm and Checksum (base64-encoded) are set if the client sent the payload checksum,
+		// the checksum is verified and stored with the object.
+		ChecksumAlgorithm string
+		Checksum          string
+		// TrailingChecksum returns the checksum of ChecksumAlgorithm sent after the payload, it's called
+		// once the payload is stored. The checksum is verified and saved in the version node.
+		TrailingChecksum func() string
+		// ContentMD5 is set if the client sent Content-MD5 header, the MD5 of the payload is verified.
+		ContentMD5 []byte
 	}
 
 	DeleteObjectParams struct {
 		BktInfo  *data.BucketInfo
 		Objects  []*VersionedObject
 		Settings *data.BucketSettings
+		// Replica is set if delete markers are created by the bucket replication, they aren't replicated further.
+		Replica bool
+		// BypassGovernance allows deletion of versions with governance retention.
+		BypassGovernance bool
 	}
 
 	// PutSettingsParams stores object copy request parameters.
@@ -130,17 +211,74 @@ type (
 
 	// CopyObjectParams stores object copy request parameters.
 	CopyObjectParams struct {
-		SrcObject   *data.ObjectInfo
-		ScrBktInfo  *data.BucketInfo
-		DstBktInfo  *data.BucketInfo
-		DstObject   string
-		SrcSize     int64
-		Header      map[string]string
-		Range       *RangeParams
-		Lock        *data.ObjectLock
+		SrcObject  *data.ObjectInfo
+		ScrBktInfo *data.BucketInfo
+		DstBktInfo *data.BucketInfo
+		DstObject  string
+		SrcSize    int64
+		Header     map[string]string
+		Range      *RangeParams
+		Lock       *data.ObjectLock
+		// SrcEncryption contains the customer key of the encrypted source object.
+		SrcEncryption encryption.Params
+		// Encryption contains encryption params of the new object.
 		Encryption  encryption.Params
 		CopiesNuber uint32
+		// BucketOwnerFullControl is set if the object is written with bucket-owner-full-control canned ACL.
+		BucketOwnerFullControl bool
+	}
+
+	// ConcatenateObjectsParams stores objects concatenation request parameters.
+	ConcatenateObjectsParams struct {
+		BktInfo      *data.BucketInfo
+		Object       string
+		Sources      []*data.ObjectInfo
+		Header       map[string]string
+		Lock         *data.ObjectLock
+		CopiesNumber uint32
 	}
+
+	// RenameObjectParams stores object rename request parameters.
+	RenameObjectParams struct {
+		BktInfo   *data.BucketInfo
+		Settings  *data.BucketSettings
+		SrcObject string
+		DstObject string
+	}
+
+	// SearchObjectsParams stores parameters of objects search by metadata and tags.
+	SearchObjectsParams struct {
+		BktInfo *data.BucketInfo
+		Prefix  string
+		Marker  string
+		MaxKeys int
+		// Filters must all match the object.
+		Filters []SearchFilter
+	}
+
+	// SelectObjectParams stores SelectObjectContent request parameters.
+	SelectObjectParams struct {
+		BktInfo    *data.BucketInfo
+		ObjectInfo *data.ObjectInfo
+		Encryption encryption.Params
+		Query      *s3select.Query
+		Writer     *s3select.EventWriter
+		// Progress enables Progress events.
+		Progress bool
+	}
+
+	// PackObjectsParams stores small objects packing parameters.
+	PackObjectsParams struct {
+		BktInfo *data.BucketInfo
+		// MaxObjectSize is the max payload size of the object to be packed.
+		MaxObjectSize int64
+		// MaxPackSize is the max payload size of the pack object.
+		MaxPackSize int64
+		// MinObjects is the min number of not packed objects to create a new pack.
+		MinObjects   int
+		CopiesNumber uint32
+	}
+
 	// CreateBucketParams stores bucket create request parameters.
 	CreateBucketParams struct {
 		Name                     string
@@ -151,11 +289,23 @@ type (
 		LocationConstraint       string
 		ObjectLockEnabled        bool
 	}
+	// ListBucketsParams stores ListBuckets request parameters.
+	ListBucketsParams struct {
+		Prefix            string
+		ContinuationToken string
+		// MaxBuckets limits the number of listed buckets, 0 means no limit.
+		MaxBuckets int
+	}
 	// PutBucketACLParams stores put bucket acl request parameters.
 	PutBucketACLParams struct {
 		BktInfo      *data.BucketInfo
 		EACL         *eacl.Table
 		SessionToken *session.Container
+		// ConfigType is a type of the change saved in the bucket configuration history.
+		// Empty type means the change isn't saved, it's used for object ACL which is stored
+		// in the bucket eACL too and for bucket policy which is saved as the policy document.
+		ConfigType   string
+		CopiesNumber uint32
 	}
 	// DeleteBucketParams stores delete bucket request parameters.
 	DeleteBucketParams struct {
@@ -183,11 +333,8 @@ type (
 		Error             error
 	}
 
-	// Client provides S3 API client interface.
-	Client interface {
-		Initialize(ctx context.Context, c EventListener) error
-		EphemeralKey() *keys.PublicKey
-
+	// BucketService manages buckets and their basic settings.
+	BucketService interface {
 		GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error)
 		PutBucketSettings(ctx context.Context, p *PutSettingsParams) error
 
@@ -195,38 +342,47 @@ type (
 		GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (*data.CORSConfiguration, error)
 		DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) error
 
-		ListBuckets(ctx context.Context) ([]*data.BucketInfo, error)
+		ListBuckets(ctx context.Context, p *ListBucketsParams) (*ListBucketsInfo, error)
 		GetBucketInfo(ctx context.Context, name string) (*data.BucketInfo, error)
 		GetBucketACL(ctx context.Context, bktInfo *data.BucketInfo) (*BucketACL, error)
 		PutBucketACL(ctx context.Context, p *PutBucketACLParams) error
 		CreateBucket(ctx context.Context, p *CreateBucketParams) (*data.BucketInfo, error)
 		DeleteBucket(ctx context.Context, p *DeleteBucketParams) error
 
+		PutBucketPolicy(ctx context.Context, p *PutBucketPolicyParams) error
+		GetBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) (*policy.Policy, error)
+		DeleteBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) error
+	}
+
+	// ObjectService reads, writes, lists and deletes objects.
+	ObjectService interface {
 		GetObject(ctx context.Context, p *GetObjectParams) error
 		GetObjectInfo(ctx context.Context, p *HeadObjectParams) (*data.ObjectInfo, error)
 		GetExtendedObjectInfo(ctx context.Context, p *HeadObjectParams) (*data.ExtendedObjectInfo, error)
 
-		GetLockInfo(ctx context.Context, obj *ObjectVersion) (*data.LockInfo, error)
-		PutLockInfo(ctx context.Context, p *PutLockInfoParams) error
-
-		GetBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) (map[string]string, error)
-		PutBucketTagging(ctx context.Context, bktInfo *data.BucketInfo, tagSet map[string]string) error
-		DeleteBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) error
-
-		GetObjectTagging(ctx context.Context, p *GetObjectTaggingParams) (string, map[string]string, error)
-		PutObjectTagging(ctx context.Context, p *PutObjectTaggingParams) (*data.NodeVersion, error)
-		DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (*data.NodeVersion, error)
-
 		PutObject(ctx context.Context, p *PutObjectParams) (*data.ExtendedObjectInfo, error)
 
 		CopyObject(ctx context.Context, p *CopyObjectParams) (*data.ExtendedObjectInfo, error)
+		RenameObject(ctx context.Context, p *RenameObjectParams) (*data.ExtendedObjectInfo, error)
+		UpdateObjectMetadata(ctx context.Context, p *UpdateObjectMetadataParams) (*data.ExtendedObjectInfo, error)
+		ConcatenateObjects(ctx context.Context, p *ConcatenateObjectsParams) (*data.ExtendedObjectInfo, error)
 
 		ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (*ListObjectsInfoV1, error)
 		ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (*ListObjectsInfoV2, error)
 		ListObjectVersions(ctx context.Context, p *ListObjectVersionsParams) (*ListObjectVersionsInfo, error)
 
+		// SearchObjects returns the latest versions of objects matching all filters sorted by name.
+		SearchObjects(ctx context.Context, p *SearchObjectsParams) (*ListObjectsInfoV1, error)
+		// SelectObjectContent runs the SQL query over the object payload and streams the result.
+		SelectObjectContent(ctx context.Context, p *SelectObjectParams) error
+		// ReconcileObjectIndex synchronizes the object index with objects of the bucket.
+		ReconcileObjectIndex(ctx context.Context, bktInfo *data.BucketInfo) error
+
 		DeleteObjects(ctx context.Context, p *DeleteObjectParams) []*VersionedObject
+	}
 
+	// MultipartService manages multipart uploads.
+	MultipartService interface {
 		CreateMultipartUpload(ctx context.Context, p *CreateMultipartParams) error
 		CompleteMultipartUpload(ctx context.Context, p *CompleteMultipartParams) (*UploadData, *data.ExtendedObjectInfo, error)
 		UploadPart(ctx context.Context, p *UploadPartParams) (string, error)
@@ -234,10 +390,124 @@ type (
 		ListMultipartUploads(ctx context.Context, p *ListMultipartUploadsParams) (*ListMultipartUploadsInfo, error)
 		AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) error
 		ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsInfo, error)
+	}
+
+	// TaggingService manages tag sets of buckets and objects.
+	TaggingService interface {
+		GetBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) (map[string]string, error)
+		PutBucketTagging(ctx context.Context, bktInfo *data.BucketInfo, tagSet map[string]string) error
+		DeleteBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) error
+
+		GetObjectTagging(ctx context.Context, p *GetObjectTaggingParams) (string, map[string]string, error)
+		PutObjectTagging(ctx context.Context, p *PutObjectTaggingParams) (*data.NodeVersion, error)
+		DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (*data.NodeVersion, error)
+
+		// Quarantined checks whether the object with the tags is quarantined by malware scanning.
+		Quarantined(tags map[string]string) bool
+		// CheckQuarantine returns AccessDenied error if the object version is quarantined, so its payload can't be read.
+		CheckQuarantine(ctx context.Context, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) error
+	}
+
+	// LockService manages retention and legal hold of objects.
+	LockService interface {
+		GetLockInfo(ctx context.Context, obj *ObjectVersion) (*data.LockInfo, error)
+		PutLockInfo(ctx context.Context, p *PutLockInfoParams) error
+	}
+
+	// LifecycleService manages bucket lifecycle configurations and applies them to objects.
+	LifecycleService interface {
+		PutBucketLifecycleConfiguration(ctx context.Context, p *PutBucketLifecycleParams) error
+		GetBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.LifecycleConfiguration, error)
+		DeleteBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error
+
+		// ExpireObjects deletes objects expired according to the bucket lifecycle configuration.
+		ExpireObjects(ctx context.Context, bktInfo *data.BucketInfo) (int, error)
+		// TransitionObjects moves objects to archive storage classes according to the bucket lifecycle configuration.
+		TransitionObjects(ctx context.Context, bktInfo *data.BucketInfo) (int, error)
+		// RestoreObject requests the temporary copy of the archived object. It returns true if the object
+		// is already restored, then only the expiry of the copy is updated.
+		RestoreObject(ctx context.Context, p *RestoreObjectParams) (bool, error)
+		// ProcessRestores copies requested archived objects of the bucket to the bucket container and
+		// deletes expired copies. It returns the number of restored objects.
+		ProcessRestores(ctx context.Context, bktInfo *data.BucketInfo) (int, error)
+	}
+
+	// ReplicationService manages bucket replication configurations.
+	ReplicationService interface {
+		PutBucketReplication(ctx context.Context, p *PutBucketReplicationParams) error
+		GetBucketReplication(ctx context.Context, bktInfo *data.BucketInfo) (*data.ReplicationConfiguration, error)
+		DeleteBucketReplication(ctx context.Context, bktInfo *data.BucketInfo) error
+	}
+
+	// Client provides S3 API client interface. Features depending on a part of the layer
+	// should use the narrow service interfaces instead.
+	Client interface {
+		BucketService
+		ObjectService
+		MultipartService
+		TaggingService
+		LockService
+		LifecycleService
+		ReplicationService
+
+		Initialize(ctx context.Context, c EventListener) error
+		EphemeralKey() *keys.PublicKey
 
 		PutBucketNotificationConfiguration(ctx context.Context, p *PutBucketNotificationConfigurationParams) error
 		GetBucketNotificationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.NotificationConfiguration, error)
 
+		PutBucketWebsite(ctx context.Context, p *PutBucketWebsiteParams) error
+		GetBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) (*data.WebsiteConfiguration, error)
+		DeleteBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) error
+
+		PutBucketInventoryConfiguration(ctx context.Context, p *PutBucketInventoryParams) error
+		GetBucketInventoryConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) (*data.InventoryConfiguration, error)
+		ListBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo) ([]data.InventoryConfiguration, error)
+		DeleteBucketInventoryConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) error
+
+		PutBucketAnalyticsConfiguration(ctx context.Context, p *PutBucketAnalyticsParams) error
+		GetBucketAnalyticsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) (*data.AnalyticsConfiguration, error)
+		ListBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) ([]data.AnalyticsConfiguration, error)
+		DeleteBucketAnalyticsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) error
+
+		PutBucketMetricsConfiguration(ctx context.Context, p *PutBucketMetricsParams) error
+		GetBucketMetricsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) (*data.MetricsConfiguration, error)
+		ListBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) ([]data.MetricsConfiguration, error)
+		DeleteBucketMetricsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) error
+
+		// WriteInventoryReports writes due inventory reports of the bucket to the destination buckets.
+		WriteInventoryReports(ctx context.Context, bktInfo *data.BucketInfo) (int, error)
+		// WriteAnalyticsExports writes daily storage class analysis exports of the bucket to the destination buckets.
+		WriteAnalyticsExports(ctx context.Context, bktInfo *data.BucketInfo) (int, error)
+
+		// GetBucketConfigHistory returns the history of bucket policy, ACL, CORS, notification, lifecycle, website, inventory, metrics, analytics and replication configuration changes.
+		GetBucketConfigHistory(ctx context.Context, bktInfo *data.BucketInfo) ([]data.ConfigChange, error)
+
+		// ListTrash returns objects moved to the trash of unversioned bucket instead of deletion.
+		ListTrash(ctx context.Context, bktInfo *data.BucketInfo) ([]*TrashObject, error)
+		// RestoreTrashObject moves the object from the bucket trash back to its original key.
+		RestoreTrashObject(ctx context.Context, bktInfo *data.BucketInfo, name string) (*TrashObject, error)
+		// PurgeExpiredTrash deletes objects stored in the bucket trash longer than retention period.
+		PurgeExpiredTrash(ctx context.Context, bktInfo *data.BucketInfo) (int, error)
+		// RunTrashPurger purges the trash of buckets where objects were deleted until the context is done.
+		RunTrashPurger(ctx context.Context)
+
+		// RunReplicator copies new object versions to the destination buckets of the bucket replication
+		// until the context is done.
+		RunReplicator(ctx context.Context)
+
+		// PackObjects aggregates small objects of the bucket into bigger pack objects and compacts sparse packs.
+		PackObjects(ctx context.Context, p *PackObjectsParams) (*PackObjectsResult, error)
+
+		// FlushCache deletes all entries from the gateway caches.
+		FlushCache()
+
+		// CacheStats returns statistics of the gateway caches by their names.
+		CacheStats() map[string]cache.Stats
+
+		// NetworkCapacity returns total storage capacity (in bytes) announced by the nodes of NeoFS network map.
+		NetworkCapacity(ctx context.Context) (uint64, error)
+
 		// Compound methods for optimizations
 
 		// GetObjectTaggingAndLock unifies GetObjectTagging and GetLock methods in single tree service invocation.
@@ -245,6 +515,9 @@ type (
 	}
 )
 
+// DefaultDeleteObjectsConcurrency is a default number of objects of DeleteObjects deleted concurrently.
+const DefaultDeleteObjectsConcurrency = 16
+
 const (
 	tagPrefix = "S3-Tag-"
 
@@ -254,8 +527,23 @@ const (
 	AttributeDecryptedSize       = api.NeoFSSystemMetadataPrefix + "Decrypted-Size"
 	AttributeHMACSalt            = api.NeoFSSystemMetadataPrefix + "HMAC-Salt"
 	AttributeHMACKey             = api.NeoFSSystemMetadataPrefix + "HMAC-Key"
+	// AttributeWrappedKey is a data key of the object encrypted with the key managed by the gateway.
+	AttributeWrappedKey = api.NeoFSSystemMetadataPrefix + "Wrapped-Key"
+	// AttributeKMSKeyID is an identifier of the KMS key which wraps the data key of the object encrypted with SSE-KMS.
+	AttributeKMSKeyID = api.NeoFSSystemMetadataPrefix + "KMS-Key-Id"
+	// AttributeObjectOwner is an owner of the S3 object if it differs from the NeoFS object owner.
+	AttributeObjectOwner = api.NeoFSSystemMetadataPrefix + "Object-Owner"
+
+	// CannedACLBucketOwnerFullControl is a canned ACL which grants full control over the object to the bucket owner.
+	CannedACLBucketOwnerFullControl = "bucket-owner-full-control"
+
+	// KMSEncryptionMethod is a value of the server-side encryption header for SSE-KMS.
+	KMSEncryptionMethod = "aws:kms"
 
 	AttributeNeofsCopiesNumber = "neofs-copies-number" // such formate to match X-Amz-Meta-Neofs-Copies-Number header
+
+	// nodeAttributeCapacity is a netmap node attribute that contains storage capacity of the node in GB.
+	nodeAttributeCapacity = "Capacity"
 )
 
 func (t *VersionedObject) String() string {
@@ -269,13 +557,41 @@ func (f MsgHandlerFunc) HandleMessage(ctx context.Context, msg *nats.Msg) error
 // NewLayer creates an instance of a layer. It checks credentials
 // and establishes gRPC connection with the node.
 func NewLayer(log *zap.Logger, neoFS NeoFS, config *Config) Client {
+	deleteConcurrency := config.DeleteObjectsConcurrency
+	if deleteConcurrency <= 0 {
+		deleteConcurrency = DefaultDeleteObjectsConcurrency
+	}
+
+	systemObjectPrefix := config.SystemObjectPrefix
+	if systemObjectPrefix == "" {
+		systemObjectPrefix = data.DefaultSystemObjectPrefix
+	}
+
 	return &layer{
-		neoFS:       neoFS,
-		log:         log,
-		anonKey:     config.AnonKey,
-		resolver:    config.Resolver,
-		cache:       NewCache(config.Caches),
-		treeService: config.TreeService,
+		neoFS:        neoFS,
+		log:          log,
+		anonKey:      config.AnonKey,
+		resolver:     config.Resolver,
+		cache:        NewCache(config.Caches),
+		treeService:  config.TreeService,
+		objectIndex:  config.ObjectIndex,
+		masterKey:    config.MasterKey,
+		kms:          config.KMS,
+		kmsKeyID:     config.KMSKeyID,
+		trashPurger:  newTrashPurger(),
+		replicator:   newReplicator(),
+		versionLocks: newVersionLocks(),
+
+		consistentListing:   config.ConsistentListing,
+		partRetries:         config.PartRetries,
+		partRetryBufferSize: config.PartRetryBufferSize,
+		deleteConcurrency:   deleteConcurrency,
+		replicaNetworks:     config.ReplicaNetworks,
+
+		archiveStorageClasses: config.ArchiveStorageClasses,
+		systemObjectPrefix:    systemObjectPrefix,
+		quarantineTagKey:      config.QuarantineTagKey,
+		quarantineTagValue:    config.QuarantineTagValue,
 	}
 }
 
@@ -300,6 +616,39 @@ func (n *layer) IsNotificationEnabled() bool {
 	return n.ncontroller != nil
 }
 
+func (n *layer) FlushCache() {
+	n.cache.Purge()
+	n.log.Info("caches are flushed")
+}
+
+func (n *layer) CacheStats() map[string]cache.Stats {
+	return n.cache.Stats()
+}
+
+func (n *layer) NetworkCapacity(ctx context.Context) (uint64, error) {
+	nm, err := n.neoFS.NetmapSnapshot(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get netmap snapshot: %w", err)
+	}
+
+	var capacity uint64
+	for _, node := range nm.Nodes() {
+		val := node.Attribute(nodeAttributeCapacity)
+		if val == "" {
+			continue
+		}
+
+		nodeCapacity, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			n.log.Warn("invalid node capacity", zap.String("value", val), zap.Error(err))
+			continue
+		}
+		capacity += nodeCapacity << 30
+	}
+
+	return capacity, nil
+}
+
 // IsAuthenticatedRequest checks if access box exists in the current request.
 func IsAuthenticatedRequest(ctx context.Context) bool {
 	_, ok := ctx.Value(api.BoxData).(*accessbox.Box)
@@ -373,13 +722,62 @@ func (n *layer) GetBucketACL(ctx context.Context, bktInfo *data.BucketInfo) (*Bu
 
 // PutBucketACL puts bucket acl by name.
 func (n *layer) PutBucketACL(ctx context.Context, param *PutBucketACLParams) error {
-	return n.setContainerEACLTable(ctx, param.BktInfo.CID, param.EACL, param.SessionToken)
+	if param.ConfigType == "" {
+		return n.setContainerEACLTable(ctx, param.BktInfo.CID, param.EACL, param.SessionToken)
+	}
+
+	var prevValue []byte
+	if prevTable, err := n.GetContainerEACL(ctx, param.BktInfo.CID); err != nil {
+		n.log.Warn("couldn't get previous bucket eacl", zap.Error(err))
+	} else if prevValue, err = prevTable.MarshalJSON(); err != nil {
+		n.log.Warn("couldn't marshal previous bucket eacl", zap.Error(err))
+	}
+
+	if err := n.setContainerEACLTable(ctx, param.BktInfo.CID, param.EACL, param.SessionToken); err != nil {
+		return err
+	}
+
+	return n.saveBucketConfigChange(ctx, param.BktInfo, param.ConfigType, prevValue, param.CopiesNumber)
 }
 
-// ListBuckets returns all user containers. The name of the bucket is a container
-// id. Timestamp is omitted since it is not saved in neofs container.
-func (n *layer) ListBuckets(ctx context.Context) ([]*data.BucketInfo, error) {
-	return n.containerList(ctx)
+// ListBuckets returns user containers sorted by bucket name. The name of the
+// bucket is a container id if the container has no domain.
+func (n *layer) ListBuckets(ctx context.Context, p *ListBucketsParams) (*ListBucketsInfo, error) {
+	var startFrom string
+	if p.ContinuationToken != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(p.ContinuationToken)
+		if err != nil || len(decoded) == 0 {
+			return nil, errors.GetAPIError(errors.ErrIncorrectContinuationToken)
+		}
+		startFrom = string(decoded)
+	}
+
+	list, err := n.containerList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	var result ListBucketsInfo
+	for _, info := range list {
+		if !strings.HasPrefix(info.Name, p.Prefix) || info.Name < startFrom {
+			continue
+		}
+
+		if p.MaxBuckets > 0 && len(result.Buckets) == p.MaxBuckets {
+			// token is the name of the next bucket, so listing continues
+			// even if this bucket is removed
+			result.ContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(info.Name))
+			break
+		}
+
+		result.Buckets = append(result.Buckets, info)
+	}
+
+	return &result, nil
 }
 
 // GetObject from storage.
@@ -389,9 +787,17 @@ func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
 	params.oid = p.ObjectInfo.ID
 	params.bktInfo = p.BucketInfo
 
+	err := n.CheckQuarantine(ctx, p.BucketInfo, p.ObjectInfo)
+	if err != nil {
+		return err
+	}
+
+	if p.Encryption, err = n.objectEncryption(ctx, p.Encryption, p.ObjectInfo.Headers, p.BucketInfo, p.ObjectInfo.FilePath); err != nil {
+		return err
+	}
+
 	var decReader *encryption.Decrypter
 	if p.Encryption.Enabled() {
-		var err error
 		decReader, err = getDecrypter(p)
 		if err != nil {
 			return fmt.Errorf("creating decrypter: %w", err)
@@ -408,7 +814,27 @@ func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
 		}
 	}
 
+	if p.ObjectInfo.Archive != nil {
+		if !p.ObjectInfo.Restore.Restored() {
+			return errors.GetAPIError(errors.ErrInvalidObjectState)
+		}
+		// payload of the archived object is read from the restored copy
+		params.oid = p.ObjectInfo.Restore.OID
+	}
+
+	if p.ObjectInfo.Pack != nil {
+		// payload of the packed object is a range of the pack object payload
+		params.oid = p.ObjectInfo.Pack.OID
+		if params.ln == 0 {
+			params.ln = uint64(p.ObjectInfo.Size)
+		}
+		params.off += p.ObjectInfo.Pack.Offset
+	}
+
 	payload, err := n.initObjectPayloadReader(ctx, params)
+	if err != nil && p.ObjectInfo.Replica != nil {
+		payload, err = n.replicaPayloadReader(ctx, params, p.ObjectInfo, err)
+	}
 	if err != nil {
 		return fmt.Errorf("init object payload reader: %w", err)
 	}
@@ -470,6 +896,88 @@ func getDecrypter(p *GetObjectParams) (*encryption.Decrypter, error) {
 	return encryption.NewMultipartDecrypter(p.Encryption, decryptedObjectSize, sizes, encRange)
 }
 
+// newObjectEncryption returns params to encrypt a new object with the name in the bucket. A new data key is
+// generated if the key is managed by the gateway or by the external KMS.
+func (n *layer) newObjectEncryption(ctx context.Context, p encryption.Params, bktInfo *data.BucketInfo, name string) (encryption.Params, error) {
+	if !p.Managed() || len(p.Key()) != 0 {
+		return p, nil
+	}
+
+	if p.KMS() {
+		return n.newKMSEncryption(ctx, p.KMSKeyID())
+	}
+
+	if n.masterKey == nil {
+		return p, errors.GetAPIError(errors.ErrInvalidEncryptionMethod)
+	}
+
+	return n.masterKey.NewParams(wrappedKeyBinding(bktInfo, name))
+}
+
+// wrappedKeyBinding returns associated data binding the data key wrapped with the master key to the bucket
+// container and the name the object is created with, so the gateway doesn't unwrap the key of the object
+// copied to other bucket with its headers.
+func wrappedKeyBinding(bktInfo *data.BucketInfo, name string) []byte {
+	binding := make([]byte, sha256.Size, sha256.Size+len(name))
+	bktInfo.CID.Encode(binding)
+	return append(binding, name...)
+}
+
+// newKMSEncryption returns params with a new data key generated by the external KMS.
+func (n *layer) newKMSEncryption(ctx context.Context, keyID string) (encryption.Params, error) {
+	if n.kms == nil {
+		return encryption.Params{}, errors.GetAPIError(errors.ErrInvalidEncryptionMethod)
+	}
+
+	if len(keyID) == 0 {
+		keyID = n.kmsKeyID
+	}
+	if len(keyID) == 0 {
+		return encryption.Params{}, errors.GetAPIError(errors.ErrInvalidEncryptionParameters)
+	}
+
+	key, wrappedKey, err := n.kms.GenerateDataKey(ctx, keyID)
+	if err != nil {
+		return encryption.Params{}, fmt.Errorf("generate data key: %w", err)
+	}
+
+	return encryption.NewKMSDataKeyParams(keyID, key, wrappedKey)
+}
+
+// objectEncryption returns params to decrypt the object with the provided headers created with the name
+// in the bucket. The data key of objects encrypted with managed keys is unwrapped with the master
+// key or by the external KMS, the provided params are returned otherwise.
+func (n *layer) objectEncryption(ctx context.Context, p encryption.Params, headers map[string]string, bktInfo *data.BucketInfo, name string) (encryption.Params, error) {
+	encInfo := FormEncryptionInfo(headers)
+	if !encInfo.Managed() {
+		return p, nil
+	}
+
+	wrappedKey, err := hex.DecodeString(encInfo.WrappedKey)
+	if err != nil {
+		return p, fmt.Errorf("invalid wrapped key '%s': %w", encInfo.WrappedKey, err)
+	}
+
+	if encInfo.KMS() {
+		if n.kms == nil {
+			return p, errorsStd.New("kms isn't configured to decrypt object")
+		}
+
+		key, err := n.kms.Decrypt(ctx, encInfo.KMSKeyID, wrappedKey)
+		if err != nil {
+			return p, fmt.Errorf("decrypt data key: %w", err)
+		}
+
+		return encryption.NewKMSDataKeyParams(encInfo.KMSKeyID, key, wrappedKey)
+	}
+
+	if n.masterKey == nil {
+		return p, errorsStd.New("master key isn't configured to decrypt object")
+	}
+
+	return n.masterKey.UnwrapParams(wrappedKey, wrappedKeyBinding(bktInfo, name))
+}
+
 // GetObjectInfo returns meta information about the object.
 func (n *layer) GetObjectInfo(ctx context.Context, p *HeadObjectParams) (*data.ObjectInfo, error) {
 	extendedObjectInfo, err := n.GetExtendedObjectInfo(ctx, p)
@@ -494,6 +1002,10 @@ func (n *layer) GetExtendedObjectInfo(ctx context.Context, p *HeadObjectParams)
 		return nil, err
 	}
 
+	if objInfo, err = n.withReplicationStatus(ctx, p.BktInfo, objInfo); err != nil {
+		return nil, err
+	}
+
 	reqInfo := api.GetReqInfo(ctx)
 	n.log.Debug("get object",
 		zap.String("reqId", reqInfo.RequestID),
@@ -507,6 +1019,19 @@ func (n *layer) GetExtendedObjectInfo(ctx context.Context, p *HeadObjectParams)
 
 // CopyObject from one bucket into another bucket.
 func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.ExtendedObjectInfo, error) {
+	if err := n.CheckQuarantine(ctx, p.ScrBktInfo, p.SrcObject); err != nil {
+		return nil, err
+	}
+
+	size := p.SrcSize
+	if FormEncryptionInfo(p.SrcObject.Headers).Enabled {
+		decryptedSize, err := decryptedObjectSize(p.SrcObject)
+		if err != nil {
+			return nil, err
+		}
+		size = decryptedSize
+	}
+
 	pr, pw := io.Pipe()
 
 	go func() {
@@ -515,7 +1040,7 @@ func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.Exte
 			Writer:     pw,
 			Range:      p.Range,
 			BucketInfo: p.ScrBktInfo,
-			Encryption: p.Encryption,
+			Encryption: p.SrcEncryption,
 		})
 
 		if err = pw.CloseWithError(err); err != nil {
@@ -526,14 +1051,113 @@ func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.Exte
 	return n.PutObject(ctx, &PutObjectParams{
 		BktInfo:      p.DstBktInfo,
 		Object:       p.DstObject,
-		Size:         p.SrcSize,
+		Size:         size,
 		Reader:       pr,
 		Header:       p.Header,
 		Encryption:   p.Encryption,
 		CopiesNumber: p.CopiesNuber,
+
+		BucketOwnerFullControl: p.BucketOwnerFullControl,
 	})
 }
 
+// RenameObject makes the object available by a new key without copying of its payload.
+// Object with the new key is overwritten. Only unversioned buckets are supported.
+func (n *layer) RenameObject(ctx context.Context, p *RenameObjectParams) (*data.ExtendedObjectInfo, error) {
+	if !p.Settings.Unversioned() {
+		return nil, errors.GetAPIError(errors.ErrNotSupported)
+	}
+	if err := n.checkSystemObjectName(p.DstObject); err != nil {
+		return nil, err
+	}
+
+	unlock := n.versionLocks.lockPair(p.BktInfo.CID, p.SrcObject, p.DstObject)
+	defer unlock()
+
+	srcVersion, err := n.treeService.GetLatestVersion(ctx, p.BktInfo, p.SrcObject)
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+		}
+		return nil, err
+	}
+	if srcVersion.IsDeleteMarker() {
+		return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+	}
+
+	if p.SrcObject != p.DstObject {
+		if err = n.renameObjectVersion(ctx, p.BktInfo, p.Settings, srcVersion, p.DstObject); err != nil {
+			return nil, err
+		}
+	}
+
+	return n.headLastVersionIfNotDeleted(ctx, p.BktInfo, p.DstObject)
+}
+
+// renameObjectVersion moves the version node to the new name by a single tree operation.
+// Overwritten object is moved to the bucket trash if it's enabled, otherwise it's deleted after the move.
+func (n *layer) renameObjectVersion(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, nodeVersion *data.NodeVersion, newName string) error {
+	dstVersion, err := n.treeService.GetUnversioned(ctx, bktInfo, newName)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return err
+	}
+
+	var dstTagSet map[string]string
+	if dstVersion != nil {
+		if settings.TrashEnabled() && !dstVersion.IsDeleteMarker() {
+			err = n.moveToTrash(ctx, bktInfo, dstVersion)
+		} else {
+			if dstTagSet, err = n.treeService.GetObjectTagging(ctx, bktInfo, dstVersion); err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+				return fmt.Errorf("couldn't get object tagging: %w", err)
+			}
+			err = n.treeService.RemoveVersion(ctx, bktInfo, dstVersion.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't remove overwritten version: %w", err)
+		}
+	}
+
+	oldName := nodeVersion.FilePath
+	if err = n.treeService.MoveVersion(ctx, bktInfo, nodeVersion, newName); err != nil {
+		if dstVersion != nil && (!settings.TrashEnabled() || dstVersion.IsDeleteMarker()) {
+			n.restoreOverwrittenVersion(ctx, bktInfo, dstVersion, dstTagSet)
+		}
+		return fmt.Errorf("couldn't move version in tree service: %w", err)
+	}
+
+	n.cleanObjectNameCache(bktInfo, oldName, nodeVersion)
+	n.cleanObjectNameCache(bktInfo, newName, nodeVersion)
+
+	if dstVersion != nil && !settings.TrashEnabled() && !dstVersion.IsDeleteMarker() {
+		if err = n.deleteNodeObject(ctx, bktInfo, dstVersion); err != nil {
+			n.log.Error("couldn't delete overwritten object", zap.Error(err),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("object", newName),
+				zap.String("objID", dstVersion.OID.EncodeToString()))
+		}
+	}
+
+	return nil
+}
+
+// restoreOverwrittenVersion adds the removed version of the rename destination back, if the rename failed.
+// Overwritten object moved to the trash can be restored from there.
+func (n *layer) restoreOverwrittenVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion, tagSet map[string]string) {
+	restored := *nodeVersion
+	restored.IsUnversioned = true
+
+	var err error
+	if restored.ID, err = n.treeService.AddVersion(ctx, bktInfo, &restored); err == nil && len(tagSet) != 0 {
+		err = n.treeService.PutObjectTagging(ctx, bktInfo, &restored, tagSet)
+	}
+	if err != nil {
+		n.log.Error("couldn't restore overwritten version", zap.Error(err),
+			zap.String("cnrID", bktInfo.CID.EncodeToString()),
+			zap.String("object", nodeVersion.FilePath),
+			zap.String("objID", nodeVersion.OID.EncodeToString()))
+	}
+}
+
 func getRandomOID() (oid.ID, error) {
 	b := [32]byte{}
 	if _, err := rand.Read(b[:]); err != nil {
@@ -545,19 +1169,37 @@ func getRandomOID() (oid.ID, error) {
 	return objID, nil
 }
 
-func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings *data.BucketSettings, obj *VersionedObject) *VersionedObject {
+func (n *layer) deleteObject(ctx context.Context, p *DeleteObjectParams, obj *VersionedObject) *VersionedObject {
+	bkt, settings := p.BktInfo, p.Settings
+
+	unlock := n.versionLocks.lock(bkt.CID, obj.Name)
+	defer unlock()
+
 	if len(obj.VersionID) != 0 || settings.Unversioned() {
 		var nodeVersion *data.NodeVersion
 		if nodeVersion, obj.Error = n.getNodeVersionToDelete(ctx, bkt, obj); obj.Error != nil {
 			return dismissNotFoundError(obj)
 		}
 
-		if obj.DeleteMarkVersion, obj.Error = n.removeOldVersion(ctx, bkt, nodeVersion, obj); obj.Error != nil {
+		if len(obj.VersionID) == 0 && settings.TrashEnabled() && !nodeVersion.IsDeleteMarker() {
+			obj.Error = n.moveToTrash(ctx, bkt, nodeVersion)
+			return obj
+		}
+
+		var bypassed bool
+		if bypassed, obj.Error = n.checkLockToDelete(ctx, bkt, nodeVersion, p.BypassGovernance); obj.Error != nil {
+			return obj
+		}
+
+		if bypassed {
+			n.log.Info("governance retention is bypassed, object is kept until the retention date",
+				zap.String("bucket", bkt.Name), zap.String("object", obj.Name), zap.Stringer("oid", nodeVersion.OID))
+		} else if obj.DeleteMarkVersion, obj.Error = n.removeOldVersion(ctx, bkt, nodeVersion, obj); obj.Error != nil {
 			return obj
 		}
 
 		obj.Error = n.treeService.RemoveVersion(ctx, bkt, nodeVersion.ID)
-		n.cache.CleanListCacheEntriesContainingObject(obj.Name, bkt.CID)
+		n.cleanObjectNameCache(bkt, obj.Name, nodeVersion)
 		return obj
 	}
 
@@ -571,9 +1213,16 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 			return dismissNotFoundError(obj)
 		}
 
-		if obj.DeleteMarkVersion, obj.Error = n.removeOldVersion(ctx, bkt, nodeVersion, obj); obj.Error != nil {
+		var bypassed bool
+		if bypassed, obj.Error = n.checkLockToDelete(ctx, bkt, nodeVersion, p.BypassGovernance); obj.Error != nil {
 			return obj
 		}
+
+		if !bypassed {
+			if obj.DeleteMarkVersion, obj.Error = n.removeOldVersion(ctx, bkt, nodeVersion, obj); obj.Error != nil {
+				return obj
+			}
+		}
 	}
 
 	randOID, err := getRandomOID()
@@ -596,12 +1245,16 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 		IsUnversioned: settings.VersioningSuspended(),
 	}
 
-	if _, obj.Error = n.treeService.AddVersion(ctx, bkt, newVersion); obj.Error != nil {
+	if newVersion.ID, obj.Error = n.treeService.AddVersion(ctx, bkt, newVersion); obj.Error != nil {
 		return obj
 	}
 
 	n.cache.DeleteObjectName(bkt.CID, bkt.Name, obj.Name)
 
+	if !p.Replica {
+		n.replicateDeleteMarker(ctx, bkt, newVersion)
+	}
+
 	return obj
 }
 
@@ -630,13 +1283,49 @@ func (n *layer) removeOldVersion(ctx context.Context, bkt *data.BucketInfo, node
 		return obj.VersionID, nil
 	}
 
-	return "", n.objectDelete(ctx, bkt, nodeVersion.OID)
+	return "", n.deleteNodeObject(ctx, bkt, nodeVersion)
 }
 
-// DeleteObjects from the storage.
+// DeleteObjects from the storage. Objects are deleted concurrently, the error of every object is set
+// in its VersionedObject.
 func (n *layer) DeleteObjects(ctx context.Context, p *DeleteObjectParams) []*VersionedObject {
+	// versions of the same object are deleted sequentially in the requested order,
+	// so delete markers and removed versions of the object don't race
+	indexes := make(map[string][]int, len(p.Objects))
+	names := make([]string, 0, len(p.Objects))
 	for i, obj := range p.Objects {
-		p.Objects[i] = n.deleteObject(ctx, p.BktInfo, p.Settings, obj)
+		if _, ok := indexes[obj.Name]; !ok {
+			names = append(names, obj.Name)
+		}
+		indexes[obj.Name] = append(indexes[obj.Name], i)
+	}
+
+	workers := n.deleteConcurrency
+	if workers > len(names) {
+		workers = len(names)
+	}
+
+	nameCh := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range nameCh {
+				for _, i := range indexes[name] {
+					p.Objects[i] = n.deleteObject(ctx, p, p.Objects[i])
+				}
+			}
+		}()
+	}
+	for _, name := range names {
+		nameCh <- name
+	}
+	close(nameCh)
+	wg.Wait()
+
+	if p.Settings.TrashEnabled() {
+		n.scheduleTrashPurge(ctx, p.BktInfo)
 	}
 
 	return p.Objects
@@ -0,0 +1,400 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"go.uber.org/zap"
+)
+
+// PutBucketLifecycleParams stores PutBucketLifecycleConfiguration request parameters.
+type PutBucketLifecycleParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.LifecycleConfiguration
+	CopiesNumber  uint32
+}
+
+// lifecycleDay is a period of expiration days, objects expire at midnight UTC.
+const lifecycleDay = 24 * time.Hour
+
+func (n *layer) PutBucketLifecycleConfiguration(ctx context.Context, p *PutBucketLifecycleParams) error {
+	if err := checkLifecycle(p.Configuration, n.archiveStorageClasses); err != nil {
+		return err
+	}
+
+	confXML, err := xml.Marshal(p.Configuration)
+	if err != nil {
+		return fmt.Errorf("marshal lifecycle configuration: %w", err)
+	}
+
+	prevValue := n.marshaledLifecycle(ctx, p.BktInfo)
+
+	prm := PrmObjectCreate{
+		Container:    p.BktInfo.CID,
+		Creator:      p.BktInfo.Owner,
+		Payload:      bytes.NewReader(confXML),
+		Filepath:     n.systemObjectName(p.BktInfo.LifecycleConfigurationObjectName()),
+		CreationTime: TimeNow(ctx),
+		CopiesNumber: p.CopiesNumber,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketLifecycleConfiguration(ctx, p.BktInfo, objID)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, p.BktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete lifecycle configuration object", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	n.cache.PutLifecycleConfiguration(n.Owner(ctx), p.BktInfo, p.Configuration)
+	return n.saveBucketConfigChange(ctx, p.BktInfo, ConfigTypeLifecycle, prevValue, p.CopiesNumber)
+}
+
+func (n *layer) GetBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.LifecycleConfiguration, error) {
+	owner := n.Owner(ctx)
+	if conf := n.cache.GetLifecycleConfiguration(owner, bktInfo); conf != nil {
+		return conf, nil
+	}
+
+	objID, err := n.treeService.GetBucketLifecycleConfiguration(ctx, bktInfo)
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, errors.GetAPIError(errors.ErrNoSuchLifecycleConfiguration)
+		}
+		return nil, err
+	}
+
+	obj, err := n.objectGet(ctx, bktInfo, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &data.LifecycleConfiguration{}
+	if err = xml.Unmarshal(obj.Payload(), conf); err != nil {
+		return nil, fmt.Errorf("unmarshal lifecycle configuration: %w", err)
+	}
+
+	n.cache.PutLifecycleConfiguration(owner, bktInfo, conf)
+
+	return conf, nil
+}
+
+func (n *layer) DeleteBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) error {
+	prevValue := n.marshaledLifecycle(ctx, bktInfo)
+
+	objID, err := n.treeService.DeleteBucketLifecycleConfiguration(ctx, bktInfo)
+	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDNotFound {
+		return err
+	}
+	if !objIDNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objID); err != nil {
+			return err
+		}
+	}
+
+	n.cache.DeleteLifecycleConfiguration(bktInfo)
+
+	if !objIDNotFound {
+		return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypeLifecycle, prevValue, 0)
+	}
+
+	return nil
+}
+
+// ExpireObjects deletes the latest versions of objects which are expired according to the bucket
+// lifecycle configuration and returns the number of expired objects. Objects of versioned buckets
+// are deleted with delete markers as DeleteObject requests do. Objects of read-only and frozen buckets
+// don't expire.
+func (n *layer) ExpireObjects(ctx context.Context, bktInfo *data.BucketInfo) (int, error) {
+	conf, err := n.GetBucketLifecycleConfiguration(ctx, bktInfo)
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchLifecycleConfiguration) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("couldn't get lifecycle configuration: %w", err)
+	}
+
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+	if settings.ReadOnly || settings.Frozen {
+		return 0, nil
+	}
+
+	var expired int
+	now := TimeNow(ctx)
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+		if rule.Status != data.LifecycleStatusEnabled || rule.Expiration == nil {
+			continue
+		}
+
+		prefix, tags := lifecycleRuleFilter(rule)
+		err = n.scanObjects(ctx, &scanObjectsParams{BktInfo: bktInfo, Prefix: prefix}, func(obj *data.ExtendedObjectInfo) error {
+			nodeVersion := obj.NodeVersion
+			if nodeVersion.IsDeleteMarker() || !strings.HasPrefix(nodeVersion.FilePath, prefix) {
+				return nil
+			}
+
+			ok, err := n.lifecycleDue(ctx, bktInfo, rule.Expiration.Days, rule.Expiration.Date, tags, nodeVersion, now)
+			if err != nil {
+				n.log.Error("couldn't check object expiration", zap.Error(err),
+					zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath))
+				return nil
+			}
+			if !ok {
+				return nil
+			}
+
+			res := n.DeleteObjects(ctx, &DeleteObjectParams{
+				BktInfo:  bktInfo,
+				Objects:  []*VersionedObject{{Name: nodeVersion.FilePath}},
+				Settings: settings,
+			})
+			if err = res[0].Error; err != nil {
+				n.log.Error("couldn't delete expired object", zap.Error(err),
+					zap.String("bucket name", bktInfo.Name), zap.String("object", nodeVersion.FilePath))
+				return nil
+			}
+			expired++
+			return nil
+		})
+		if err != nil {
+			return expired, fmt.Errorf("couldn't scan objects of rule '%s': %w", rule.ID, err)
+		}
+	}
+
+	return expired, nil
+}
+
+// lifecycleDue checks if the object version matches tags of the rule and the action of the rule
+// is due at the moment: at the date or in the number of days since the object creation.
+func (n *layer) lifecycleDue(ctx context.Context, bktInfo *data.BucketInfo, days int, date string,
+	tags []data.LifecycleTag, nodeVersion *data.NodeVersion, now time.Time) (bool, error) {
+	var due time.Time
+	if len(date) != 0 {
+		parsed, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return false, fmt.Errorf("invalid lifecycle date '%s': %w", date, err)
+		}
+		due = parsed
+	} else {
+		objInfo, err := n.objectInfoFromNode(ctx, bktInfo, nodeVersion)
+		if err != nil {
+			return false, err
+		}
+		due = lifecycleDaysLater(objInfo.Created, days)
+	}
+
+	if now.Before(due) {
+		return false, nil
+	}
+
+	if len(tags) == 0 {
+		return true, nil
+	}
+
+	tagSet, err := n.treeService.GetObjectTagging(ctx, bktInfo, nodeVersion)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return false, fmt.Errorf("couldn't get object tagging: %w", err)
+	}
+
+	return lifecycleTagsMatch(tags, tagSet), nil
+}
+
+// lifecycleTagsMatch checks if the tag set contains all the tags of the rule filter.
+func lifecycleTagsMatch(tags []data.LifecycleTag, tagSet map[string]string) bool {
+	for _, tag := range tags {
+		if value, ok := tagSet[tag.Key]; !ok || value != tag.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// LifecycleExpiration returns the date the object with the name, creation time and tags expires at according
+// to the lifecycle configuration and the ID of the rule expiring it. The earliest date is returned
+// if several rules apply, zero time means the object doesn't expire.
+func LifecycleExpiration(conf *data.LifecycleConfiguration, name string, created time.Time, tagSet map[string]string) (time.Time, string) {
+	var (
+		expiry time.Time
+		ruleID string
+	)
+
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+		if rule.Status != data.LifecycleStatusEnabled || rule.Expiration == nil {
+			continue
+		}
+
+		prefix, tags := lifecycleRuleFilter(rule)
+		if !strings.HasPrefix(name, prefix) || !lifecycleTagsMatch(tags, tagSet) {
+			continue
+		}
+
+		due := lifecycleDaysLater(created, rule.Expiration.Days)
+		if len(rule.Expiration.Date) != 0 {
+			parsed, err := time.Parse(time.RFC3339, rule.Expiration.Date)
+			if err != nil {
+				continue
+			}
+			due = parsed.UTC()
+		}
+
+		if expiry.IsZero() || due.Before(expiry) {
+			expiry, ruleID = due, rule.ID
+		}
+	}
+
+	return expiry, ruleID
+}
+
+// lifecycleDaysLater returns the time in the number of days since t rounded to the next midnight UTC as AWS S3 does.
+func lifecycleDaysLater(t time.Time, days int) time.Time {
+	return t.UTC().Add(time.Duration(days) * lifecycleDay).Truncate(lifecycleDay).Add(lifecycleDay)
+}
+
+// lifecycleRuleFilter returns the key prefix and tags of objects the rule applies to.
+func lifecycleRuleFilter(rule *data.LifecycleRule) (string, []data.LifecycleTag) {
+	if rule.Filter == nil {
+		return rule.Prefix, nil
+	}
+
+	switch {
+	case rule.Filter.And != nil:
+		return rule.Filter.And.Prefix, rule.Filter.And.Tags
+	case rule.Filter.Tag != nil:
+		return "", []data.LifecycleTag{*rule.Filter.Tag}
+	default:
+		return rule.Filter.Prefix, nil
+	}
+}
+
+// marshaledLifecycle returns current bucket lifecycle configuration in XML to save it in the bucket history.
+func (n *layer) marshaledLifecycle(ctx context.Context, bktInfo *data.BucketInfo) []byte {
+	conf, err := n.GetBucketLifecycleConfiguration(ctx, bktInfo)
+	if err != nil {
+		if !errors.IsS3Error(err, errors.ErrNoSuchLifecycleConfiguration) {
+			n.log.Warn("couldn't get previous bucket lifecycle configuration", zap.Error(err))
+		}
+		return nil
+	}
+
+	confXML, err := xml.Marshal(conf)
+	if err != nil {
+		n.log.Warn("couldn't marshal previous bucket lifecycle configuration", zap.Error(err))
+		return nil
+	}
+
+	return confXML
+}
+
+func checkLifecycle(conf *data.LifecycleConfiguration, storageClasses map[string]cid.ID) error {
+	if len(conf.Rules) == 0 {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	ids := make(map[string]struct{}, len(conf.Rules))
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+
+		if len(rule.ID) != 0 {
+			if _, ok := ids[rule.ID]; ok {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("duplicated rule id '%s'", rule.ID))
+			}
+			ids[rule.ID] = struct{}{}
+		}
+
+		if rule.Status != data.LifecycleStatusEnabled && rule.Status != data.LifecycleStatusDisabled {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+
+		if rule.NoncurrentVersionExpiration != nil || len(rule.NoncurrentVersionTransitions) != 0 ||
+			rule.AbortIncompleteMultipartUpload != nil {
+			return errors.GetAPIErrorWithError(errors.ErrNotImplemented, errorsStd.New("only expiration and transition of current versions are supported"))
+		}
+
+		if rule.Expiration == nil && len(rule.Transitions) == 0 {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+
+		if rule.Expiration != nil {
+			if err := checkLifecycleDate(rule.Expiration.Days, rule.Expiration.Date); err != nil {
+				return err
+			}
+		}
+
+		for _, transition := range rule.Transitions {
+			if _, ok := storageClasses[transition.StorageClass]; !ok {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidStorageClass,
+					fmt.Errorf("unknown archive storage class '%s'", transition.StorageClass))
+			}
+			if err := checkLifecycleDate(transition.Days, transition.Date); err != nil {
+				return err
+			}
+		}
+
+		if rule.Filter != nil {
+			filters := 0
+			if len(rule.Filter.Prefix) != 0 {
+				filters++
+			}
+			if rule.Filter.Tag != nil {
+				filters++
+			}
+			if rule.Filter.And != nil {
+				filters++
+			}
+			if filters > 1 || len(rule.Prefix) != 0 {
+				return errors.GetAPIError(errors.ErrMalformedXML)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkLifecycleDate checks days or date of the expiration or transition.
+func checkLifecycleDate(days int, date string) error {
+	if (days != 0) == (len(date) != 0) {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("exactly one of days or date must be set"))
+	}
+
+	if days < 0 {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("days must be positive"))
+	}
+
+	if len(date) != 0 {
+		parsed, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid date: %w", err))
+		}
+		if !parsed.Equal(parsed.UTC().Truncate(lifecycleDay)) {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("date must be at midnight UTC"))
+		}
+	}
+
+	return nil
+}
@@ -50,3 +50,25 @@ func TestObjectLockAttributes(t *testing.T) {
 
 	require.Truef(t, expEpoch, "system header __NEOFS__EXPIRATION_EPOCH presence")
 }
+
+func TestObjectTaggingAndLockWithoutLock(t *testing.T) {
+	tc := prepareContext(t)
+	obj := tc.putObject([]byte("content"))
+
+	objVersion := &ObjectVersion{
+		BktInfo:    tc.bktInfo,
+		ObjectName: obj.Name,
+		VersionID:  obj.VersionID(),
+	}
+
+	_, lockInfo, err := tc.layer.GetObjectTaggingAndLock(tc.ctx, objVersion, nil)
+	require.NoError(t, err)
+	require.NotNil(t, lockInfo)
+	require.False(t, lockInfo.IsLegalHoldSet())
+	require.False(t, lockInfo.IsRetentionSet())
+
+	// empty lock is cached and returned on the next call
+	_, lockInfo, err = tc.layer.GetObjectTaggingAndLock(tc.ctx, objVersion, nil)
+	require.NoError(t, err)
+	require.NotNil(t, lockInfo)
+}
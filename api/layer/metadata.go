@@ -0,0 +1,112 @@
+package layer
+
+import (
+	"context"
+	"encoding/json"
+	errorsStd "errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// UpdateObjectMetadataParams stores parameters of the object metadata update.
+	UpdateObjectMetadataParams struct {
+		BktInfo  *data.BucketInfo
+		Settings *data.BucketSettings
+		Object   string
+		// Header is a new user metadata of the object, content type is set by Content-Type key.
+		Header map[string]string
+	}
+
+	// objectMetadata is saved in the tree node of the object whose metadata is updated.
+	objectMetadata struct {
+		ContentType string            `json:"contentType,omitempty"`
+		Headers     map[string]string `json:"headers,omitempty"`
+	}
+)
+
+// UpdateObjectMetadata replaces user metadata and content type of the object without re-storing
+// of its payload. New metadata is saved in the tree node of the object and overrides headers
+// of the NeoFS object. Only unversioned buckets are supported.
+func (n *layer) UpdateObjectMetadata(ctx context.Context, p *UpdateObjectMetadataParams) (*data.ExtendedObjectInfo, error) {
+	if !p.Settings.Unversioned() {
+		return nil, errors.GetAPIError(errors.ErrNotSupported)
+	}
+
+	unlock := n.versionLocks.lock(p.BktInfo.CID, p.Object)
+	defer unlock()
+
+	nodeVersion, err := n.treeService.GetLatestVersion(ctx, p.BktInfo, p.Object)
+	if err != nil {
+		if errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+		}
+		return nil, err
+	}
+	if nodeVersion.IsDeleteMarker() {
+		return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+	}
+
+	// access to the object is checked by the NeoFS request of its headers
+	if _, err = n.objectInfoFromNode(ctx, p.BktInfo, nodeVersion); err != nil {
+		return nil, err
+	}
+
+	meta := objectMetadata{Headers: make(map[string]string, len(p.Header))}
+	for key, val := range p.Header {
+		if key == api.ContentType {
+			meta.ContentType = val
+			continue
+		}
+		meta.Headers[key] = val
+	}
+
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("marshal object metadata: %w", err)
+	}
+
+	updated := *nodeVersion
+	updated.Metadata = string(encoded)
+	if err = n.treeService.UpdateVersionMetadata(ctx, p.BktInfo, &updated); err != nil {
+		return nil, fmt.Errorf("couldn't update version metadata: %w", err)
+	}
+
+	n.cleanObjectNameCache(p.BktInfo, p.Object, nodeVersion)
+
+	return n.headLastVersionIfNotDeleted(ctx, p.BktInfo, p.Object)
+}
+
+// applyObjectMetadata replaces user metadata and content type of the object info with
+// the metadata updated after the object creation. The trailing checksum saved in the node is added too.
+func applyObjectMetadata(objInfo *data.ObjectInfo, nodeVersion *data.NodeVersion) error {
+	applyNodeChecksum(objInfo, nodeVersion)
+
+	if len(nodeVersion.Metadata) == 0 {
+		return nil
+	}
+
+	var meta objectMetadata
+	if err := json.Unmarshal([]byte(nodeVersion.Metadata), &meta); err != nil {
+		return fmt.Errorf("unmarshal object metadata: %w", err)
+	}
+
+	// headers map of the object info can be shared with caches, so a new map is created
+	headers := make(map[string]string, len(objInfo.Headers)+len(meta.Headers))
+	for key, val := range objInfo.Headers {
+		if IsSystemHeader(key) && key != api.CacheControl {
+			headers[key] = val
+		}
+	}
+	for key, val := range meta.Headers {
+		headers[key] = val
+	}
+
+	objInfo.Headers = headers
+	objInfo.ContentType = meta.ContentType
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+	"sort"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// PutBucketMetricsParams stores PutBucketMetricsConfiguration request parameters.
+type PutBucketMetricsParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.MetricsConfiguration
+	CopiesNumber  uint32
+}
+
+const (
+	// metricsMaxConfigurations limits the number of metrics configurations of a bucket as AWS S3 does.
+	metricsMaxConfigurations = 1000
+	// metricsMaxIDLength limits the length of metrics configuration id.
+	metricsMaxIDLength = 64
+)
+
+// PutBucketMetricsConfiguration adds the metrics configuration to the bucket or replaces
+// the configuration with the same id.
+func (n *layer) PutBucketMetricsConfiguration(ctx context.Context, p *PutBucketMetricsParams) error {
+	if err := checkMetrics(p.Configuration); err != nil {
+		return err
+	}
+
+	configurations, err := n.getMetricsConfigurations(ctx, p.BktInfo)
+	if err != nil {
+		return err
+	}
+
+	newConfigurations := &data.MetricsConfigurations{
+		Configurations: make([]data.MetricsConfiguration, 0, len(configurations.Configurations)+1),
+	}
+	var replaced bool
+	for _, conf := range configurations.Configurations {
+		if conf.ID == p.Configuration.ID {
+			conf, replaced = *p.Configuration, true
+		}
+		newConfigurations.Configurations = append(newConfigurations.Configurations, conf)
+	}
+	if !replaced {
+		if len(newConfigurations.Configurations) >= metricsMaxConfigurations {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+				fmt.Errorf("bucket can't have more than %d metrics configurations", metricsMaxConfigurations))
+		}
+		newConfigurations.Configurations = append(newConfigurations.Configurations, *p.Configuration)
+	}
+
+	sort.Slice(newConfigurations.Configurations, func(i, j int) bool {
+		return newConfigurations.Configurations[i].ID < newConfigurations.Configurations[j].ID
+	})
+
+	return n.putMetricsConfigurations(ctx, p.BktInfo, configurations, newConfigurations, p.CopiesNumber)
+}
+
+// GetBucketMetricsConfiguration returns the metrics configuration of the bucket with the id.
+func (n *layer) GetBucketMetricsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) (*data.MetricsConfiguration, error) {
+	configurations, err := n.getMetricsConfigurations(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range configurations.Configurations {
+		if configurations.Configurations[i].ID == id {
+			return &configurations.Configurations[i], nil
+		}
+	}
+
+	return nil, errors.GetAPIError(errors.ErrNoSuchConfiguration)
+}
+
+// ListBucketMetricsConfigurations returns all metrics configurations of the bucket sorted by id.
+func (n *layer) ListBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) ([]data.MetricsConfiguration, error) {
+	configurations, err := n.getMetricsConfigurations(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return configurations.Configurations, nil
+}
+
+// DeleteBucketMetricsConfiguration removes the metrics configuration of the bucket with the id.
+func (n *layer) DeleteBucketMetricsConfiguration(ctx context.Context, bktInfo *data.BucketInfo, id string) error {
+	configurations, err := n.getMetricsConfigurations(ctx, bktInfo)
+	if err != nil {
+		return err
+	}
+
+	newConfigurations := &data.MetricsConfigurations{}
+	for _, conf := range configurations.Configurations {
+		if conf.ID != id {
+			newConfigurations.Configurations = append(newConfigurations.Configurations, conf)
+		}
+	}
+	if len(newConfigurations.Configurations) == len(configurations.Configurations) {
+		return errors.GetAPIError(errors.ErrNoSuchConfiguration)
+	}
+
+	return n.putMetricsConfigurations(ctx, bktInfo, configurations, newConfigurations, 0)
+}
+
+// getMetricsConfigurations returns metrics configurations of the bucket, the bucket without
+// configurations gets the empty list.
+func (n *layer) getMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (*data.MetricsConfigurations, error) {
+	owner := n.Owner(ctx)
+	if configurations := n.cache.GetMetricsConfigurations(owner, bktInfo); configurations != nil {
+		return configurations, nil
+	}
+
+	configurations := &data.MetricsConfigurations{}
+	objID, err := n.treeService.GetBucketMetricsConfigurations(ctx, bktInfo)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		obj, err := n.objectGet(ctx, bktInfo, objID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = xml.Unmarshal(obj.Payload(), configurations); err != nil {
+			return nil, fmt.Errorf("unmarshal metrics configurations: %w", err)
+		}
+	}
+
+	n.cache.PutMetricsConfigurations(owner, bktInfo, configurations)
+
+	return configurations, nil
+}
+
+// putMetricsConfigurations saves metrics configurations of the bucket as a system object,
+// the object is removed if there are no configurations.
+func (n *layer) putMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo, prev, configurations *data.MetricsConfigurations, copiesNumber uint32) error {
+	var prevValue []byte
+	if len(prev.Configurations) != 0 {
+		var err error
+		if prevValue, err = xml.Marshal(prev); err != nil {
+			n.log.Warn("couldn't marshal previous bucket metrics configurations", zap.Error(err))
+		}
+	}
+
+	var (
+		objIDToDelete oid.ID
+		err           error
+	)
+	if len(configurations.Configurations) == 0 {
+		objIDToDelete, err = n.treeService.DeleteBucketMetricsConfigurations(ctx, bktInfo)
+	} else {
+		var confXML []byte
+		if confXML, err = xml.Marshal(configurations); err != nil {
+			return fmt.Errorf("marshal metrics configurations: %w", err)
+		}
+
+		prm := PrmObjectCreate{
+			Container:    bktInfo.CID,
+			Creator:      bktInfo.Owner,
+			Payload:      bytes.NewReader(confXML),
+			Filepath:     n.systemObjectName(bktInfo.MetricsConfigurationObjectName()),
+			CreationTime: TimeNow(ctx),
+			CopiesNumber: copiesNumber,
+		}
+
+		var objID oid.ID
+		if objID, _, err = n.objectPutAndHash(ctx, prm, bktInfo); err != nil {
+			return fmt.Errorf("put system object: %w", err)
+		}
+
+		objIDToDelete, err = n.treeService.PutBucketMetricsConfigurations(ctx, bktInfo, objID)
+	}
+
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete metrics configurations object", zap.Error(err),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	n.cache.PutMetricsConfigurations(n.Owner(ctx), bktInfo, configurations)
+	return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypeMetrics, prevValue, copiesNumber)
+}
+
+func checkMetrics(conf *data.MetricsConfiguration) error {
+	if len(conf.ID) == 0 || len(conf.ID) > metricsMaxIDLength {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid metrics id '%s'", conf.ID))
+	}
+	for _, r := range conf.ID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid metrics id '%s'", conf.ID))
+		}
+	}
+
+	if conf.Filter == nil {
+		return nil
+	}
+
+	// requests are matched by the object key only, the gateway has no access points
+	// and doesn't read object tags on every request
+	if conf.Filter.Tag != nil || conf.Filter.And != nil && len(conf.Filter.And.Tags) != 0 {
+		return errors.GetAPIErrorWithError(errors.ErrNotImplemented, errorsStd.New("metrics filter by tags isn't supported"))
+	}
+	if conf.Filter.AccessPointArn != "" || conf.Filter.And != nil && conf.Filter.And.AccessPointArn != "" {
+		return errors.GetAPIErrorWithError(errors.ErrNotImplemented, errorsStd.New("metrics filter by access point isn't supported"))
+	}
+	if conf.Filter.And != nil && conf.Filter.Prefix != "" {
+		return errors.GetAPIError(errors.ErrMalformedXML)
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package layer
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
 	"encoding/hex"
 	stderrors "errors"
 	"fmt"
@@ -25,10 +27,15 @@ const (
 	UploadIDAttributeName         = "S3-Upload-Id"
 	UploadPartNumberAttributeName = "S3-Upload-Part-Number"
 	UploadCompletedParts          = "S3-Completed-Parts"
+	UploadCompletedID             = "S3-Completed-Upload-Id"
 
 	metaPrefix = "meta-"
 	aclPrefix  = "acl-"
 
+	lockRetainUntil = "lock-retain-until"
+	lockCompliance  = "lock-compliance"
+	lockLegalHold   = "lock-legal-hold"
+
 	MaxSizeUploadsList  = 1000
 	MaxSizePartsList    = 1000
 	UploadMinPartNumber = 1
@@ -50,11 +57,15 @@ type (
 		Header       map[string]string
 		Data         *UploadData
 		CopiesNumber uint32
+		// ChecksumAlgorithm is applied to every part of the upload if set.
+		ChecksumAlgorithm string
 	}
 
 	UploadData struct {
 		TagSet     map[string]string
 		ACLHeaders map[string]string
+		// Lock is applied to the object on completion of the upload.
+		Lock *data.ObjectLock
 	}
 
 	UploadPartParams struct {
@@ -62,6 +73,13 @@ type (
 		PartNumber int
 		Size       int64
 		Reader     io.Reader
+		// ChecksumAlgorithm overrides the checksum algorithm of the upload.
+		ChecksumAlgorithm string
+		// Checksum is an expected base64-encoded part checksum, it's verified if set.
+		Checksum string
+		// TrailingChecksum returns the expected part checksum sent after the payload, it's called
+		// once the part is stored.
+		TrailingChecksum func() string
 	}
 
 	UploadCopyParams struct {
@@ -70,6 +88,8 @@ type (
 		SrcBktInfo *data.BucketInfo
 		PartNumber int
 		Range      *RangeParams
+		// SrcEncryption contains the customer key of the encrypted source object.
+		SrcEncryption encryption.Params
 	}
 
 	CompleteMultipartParams struct {
@@ -88,10 +108,14 @@ type (
 	}
 
 	Part struct {
-		ETag         string
-		LastModified string
-		PartNumber   int
-		Size         int64
+		ETag           string
+		LastModified   string
+		PartNumber     int
+		Size           int64
+		ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+		ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+		ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+		ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
 	}
 
 	ListMultipartUploadsParams struct {
@@ -115,6 +139,7 @@ type (
 		Owner                user.ID
 		NextPartNumberMarker int
 		IsTruncated          bool
+		ChecksumAlgorithm    string
 	}
 
 	ListMultipartUploadsInfo struct {
@@ -134,6 +159,10 @@ type (
 )
 
 func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartParams) error {
+	if err := n.checkSystemObjectName(p.Info.Key); err != nil {
+		return err
+	}
+
 	metaSize := len(p.Header)
 	if p.Data != nil {
 		metaSize += len(p.Data.ACLHeaders)
@@ -161,10 +190,21 @@ func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartPar
 		for key, val := range p.Data.TagSet {
 			info.Meta[tagPrefix+key] = val
 		}
+
+		addLockMeta(info.Meta, p.Data.Lock)
+	}
+
+	if p.ChecksumAlgorithm != "" {
+		info.Meta[UploadChecksumAlgorithm] = p.ChecksumAlgorithm
+	}
+
+	encryptionParams, err := n.newObjectEncryption(ctx, p.Info.Encryption, p.Info.Bkt, p.Info.Key)
+	if err != nil {
+		return err
 	}
 
-	if p.Info.Encryption.Enabled() {
-		if err := addEncryptionHeaders(info.Meta, p.Info.Encryption); err != nil {
+	if encryptionParams.Enabled() {
+		if err := addEncryptionHeaders(info.Meta, encryptionParams); err != nil {
 			return fmt.Errorf("add encryption header: %w", err)
 		}
 	}
@@ -200,35 +240,78 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 		return nil, errors.GetAPIError(errors.ErrInvalidEncryptionParameters)
 	}
 
+	encryptionParams, err := n.objectEncryption(ctx, p.Info.Encryption, multipartInfo.Meta, p.Info.Bkt, p.Info.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	checksumAlgorithm := multipartInfo.Meta[UploadChecksumAlgorithm]
+	if p.ChecksumAlgorithm != "" {
+		if checksumAlgorithm != "" && checksumAlgorithm != p.ChecksumAlgorithm {
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidRequest,
+				fmt.Errorf("checksum algorithm '%s' differs from upload one '%s'", p.ChecksumAlgorithm, checksumAlgorithm))
+		}
+		checksumAlgorithm = p.ChecksumAlgorithm
+	}
+
 	bktInfo := p.Info.Bkt
 	prm := PrmObjectCreate{
 		Container:    bktInfo.CID,
 		Creator:      bktInfo.Owner,
 		Attributes:   make([][2]string, 2),
-		Payload:      p.Reader,
 		CreationTime: TimeNow(ctx),
 		CopiesNumber: multipartInfo.CopiesNumber,
 	}
 
-	decSize := p.Size
-	if p.Info.Encryption.Enabled() {
-		r, encSize, err := encryptionReader(p.Reader, uint64(p.Size), p.Info.Encryption.Key())
-		if err != nil {
-			return nil, fmt.Errorf("failed to create ecnrypted reader: %w", err)
-		}
-		prm.Attributes = append(prm.Attributes, [2]string{AttributeDecryptedSize, strconv.FormatInt(p.Size, 10)})
-		prm.Payload = r
-		p.Size = int64(encSize)
-	}
-
 	prm.Attributes[0][0], prm.Attributes[0][1] = UploadIDAttributeName, p.Info.UploadID
 	prm.Attributes[1][0], prm.Attributes[1][1] = UploadPartNumberAttributeName, strconv.Itoa(p.PartNumber)
 
-	id, hash, err := n.objectPutAndHash(ctx, prm, bktInfo)
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+
+	partData, payload, err := n.bufferPart(p.Reader, p.Size)
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		id             oid.ID
+		etag, checksum string
+	)
+	for attempt := 0; ; attempt++ {
+		if partData != nil {
+			payload = bytes.NewReader(partData)
+		}
+
+		id, etag, checksum, err = n.putPart(ctx, bktInfo, prm, payload, p.Size, encryptionParams, checksumAlgorithm, settings)
+		if err == nil || partData == nil || attempt == n.partRetries || ctx.Err() != nil {
+			break
+		}
+
+		n.log.Warn("couldn't store part, retry", zap.Error(err),
+			zap.String("bucket", bktInfo.Name), zap.Stringer("cid", bktInfo.CID),
+			zap.String("multipart upload", p.Info.UploadID),
+			zap.Int("part number", p.PartNumber), zap.Int("attempt", attempt+1))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.TrailingChecksum != nil {
+		p.Checksum = p.TrailingChecksum()
+	}
+	if checksum != "" && (p.Checksum != "" || p.TrailingChecksum != nil) && p.Checksum != checksum {
+		if err = n.objectDelete(ctx, bktInfo, id); err != nil {
+			n.log.Error("couldn't delete part object with invalid checksum", zap.Error(err),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", id.EncodeToString()))
+		}
+		return nil, errors.GetAPIError(errors.ErrBadDigest)
+	}
+
 	reqInfo := api.GetReqInfo(ctx)
 	n.log.Debug("upload part",
 		zap.String("reqId", reqInfo.RequestID),
@@ -241,9 +324,12 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 		UploadID: p.Info.UploadID,
 		Number:   p.PartNumber,
 		OID:      id,
-		Size:     decSize,
-		ETag:     hex.EncodeToString(hash),
+		Size:     p.Size,
+		ETag:     etag,
 		Created:  prm.CreationTime,
+
+		ChecksumAlgorithm: checksumAlgorithm,
+		Checksum:          checksum,
 	}
 
 	oldPartID, err := n.treeService.AddPart(ctx, bktInfo, multipartInfo.ID, partInfo)
@@ -274,6 +360,89 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 	return objInfo, nil
 }
 
+// bufferPart reads the part payload to memory to be able to store the part again
+// if NeoFS write fails. If the part can't be retried, it returns nil data and
+// the reader of the part payload.
+func (n *layer) bufferPart(r io.Reader, size int64) ([]byte, io.Reader, error) {
+	if n.partRetries == 0 || size > n.partRetryBufferSize {
+		return nil, r, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, n.partRetryBufferSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read part payload: %w", err)
+	}
+	if int64(len(data)) > n.partRetryBufferSize {
+		return nil, io.MultiReader(bytes.NewReader(data), r), nil
+	}
+
+	return data, nil, nil
+}
+
+// putPart stores the part object and validates the payload checksum of the stored object.
+// It returns the part object ID, ETag and the checksum of the plain payload if checksum
+// algorithm is set.
+func (n *layer) putPart(ctx context.Context, bktInfo *data.BucketInfo, prm PrmObjectCreate, payload io.Reader, size int64,
+	encryptionParams encryption.Params, checksumAlgorithm string, settings *data.BucketSettings) (oid.ID, string, string, error) {
+	checksumHash := newChecksumHash(checksumAlgorithm)
+	if checksumHash != nil {
+		payload = wrapReader(payload, 64*1024, func(buf []byte) {
+			checksumHash.Write(buf)
+		})
+	}
+
+	prm.Payload = payload
+	if encryptionParams.Enabled() {
+		r, _, err := encryptionReader(payload, uint64(size), encryptionParams.Key())
+		if err != nil {
+			return oid.ID{}, "", "", fmt.Errorf("failed to create ecnrypted reader: %w", err)
+		}
+		prm.Attributes = append(prm.Attributes, [2]string{AttributeDecryptedSize, strconv.FormatInt(size, 10)})
+		prm.Payload = r
+	}
+
+	id, etag, hash, err := n.objectPutAndETag(ctx, prm, bktInfo, settings)
+	if err != nil {
+		return oid.ID{}, "", "", err
+	}
+
+	if err = n.checkPayloadHash(ctx, bktInfo, id, hash); err != nil {
+		if errDel := n.objectDelete(ctx, bktInfo, id); errDel != nil {
+			n.log.Error("couldn't delete part object with invalid payload hash", zap.Error(errDel),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", id.EncodeToString()))
+		}
+		return oid.ID{}, "", "", err
+	}
+
+	var checksum string
+	if checksumHash != nil {
+		checksum = encodeChecksum(checksumHash)
+	}
+
+	return id, etag, checksum, nil
+}
+
+// checkPayloadHash compares the payload checksum from the stored object header with the hash
+// of the sent payload.
+func (n *layer) checkPayloadHash(ctx context.Context, bktInfo *data.BucketInfo, id oid.ID, hash []byte) error {
+	head, err := n.objectHead(ctx, bktInfo, id)
+	if err != nil {
+		return fmt.Errorf("couldn't head stored object: %w", err)
+	}
+
+	cs, ok := head.PayloadChecksum()
+	if !ok {
+		return fmt.Errorf("stored object '%s' has no payload checksum", id)
+	}
+	if !bytes.Equal(cs.Value(), hash) {
+		return fmt.Errorf("payload checksum of stored object '%s' mismatched", id)
+	}
+
+	return nil
+}
+
 func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.ObjectInfo, error) {
 	multipartInfo, err := n.treeService.GetMultipartUpload(ctx, p.Info.Bkt, p.Info.Key, p.Info.UploadID)
 	if err != nil {
@@ -283,10 +452,19 @@ func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.
 		return nil, err
 	}
 
-	size := p.SrcObjInfo.Size
+	if err = n.CheckQuarantine(ctx, p.SrcBktInfo, p.SrcObjInfo); err != nil {
+		return nil, err
+	}
+
+	srcSize, err := decryptedObjectSize(p.SrcObjInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	size := srcSize
 	if p.Range != nil {
 		size = int64(p.Range.End - p.Range.Start + 1)
-		if p.Range.End > uint64(p.SrcObjInfo.Size) {
+		if p.Range.End >= uint64(srcSize) {
 			return nil, errors.GetAPIError(errors.ErrInvalidCopyPartRangeSource)
 		}
 	}
@@ -302,6 +480,7 @@ func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.
 			Writer:     pw,
 			Range:      p.Range,
 			BucketInfo: p.SrcBktInfo,
+			Encryption: p.SrcEncryption,
 		})
 
 		if err = pw.CloseWithError(err); err != nil {
@@ -330,6 +509,9 @@ type multiObjectReader struct {
 	curReader io.Reader
 
 	parts []*data.PartInfo
+
+	// packs are locations of the packed parts, aligned with parts (optional).
+	packs []*data.PackInfo
 }
 
 func (x *multiObjectReader) Read(p []byte) (n int, err error) {
@@ -344,7 +526,13 @@ func (x *multiObjectReader) Read(p []byte) (n int, err error) {
 		return n, io.EOF
 	}
 
-	x.prm.oid = x.parts[0].OID
+	x.prm.oid, x.prm.off, x.prm.ln = x.parts[0].OID, 0, 0
+	if len(x.packs) != 0 {
+		if pack := x.packs[0]; pack != nil {
+			x.prm.oid, x.prm.off, x.prm.ln = pack.OID, pack.Offset, uint64(x.parts[0].Size)
+		}
+		x.packs = x.packs[1:]
+	}
 
 	x.curReader, err = x.layer.initObjectPayloadReader(x.ctx, x.prm)
 	if err != nil {
@@ -367,6 +555,15 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 
 	multipartInfo, partsInfo, err := n.getUploadParts(ctx, p.Info)
 	if err != nil {
+		// client retries completion if the response to the successful request is lost
+		if errors.IsS3Error(err, errors.ErrNoSuchUpload) {
+			if extObjInfo := n.completedMultipartObject(ctx, p); extObjInfo != nil {
+				return &UploadData{
+					TagSet:     make(map[string]string),
+					ACLHeaders: make(map[string]string),
+				}, extObjInfo, nil
+			}
+		}
 		return nil, nil, err
 	}
 	encInfo := FormEncryptionInfo(multipartInfo.Meta)
@@ -382,7 +579,7 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 	var completedPartsHeader strings.Builder
 	for i, part := range p.Parts {
 		partInfo := partsInfo[part.PartNumber]
-		if partInfo == nil || part.ETag != partInfo.ETag {
+		if partInfo == nil || strings.Trim(part.ETag, "\"") != partInfo.ETag {
 			return nil, nil, errors.GetAPIError(errors.ErrInvalidPart)
 		}
 		// for the last part we have no minimum size limit
@@ -411,6 +608,15 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 
 	initMetadata := make(map[string]string, len(multipartInfo.Meta)+1)
 	initMetadata[UploadCompletedParts] = completedPartsHeader.String()
+	initMetadata[UploadCompletedID] = p.Info.UploadID
+
+	if algorithm := multipartInfo.Meta[UploadChecksumAlgorithm]; algorithm != "" {
+		if checksum, partsChecksums := multipartChecksum(algorithm, parts); checksum != "" {
+			initMetadata[AttributeChecksumAlgorithm] = algorithm
+			initMetadata[AttributeChecksum] = checksum
+			initMetadata[AttributePartsChecksums] = partsChecksums
+		}
+	}
 
 	uploadData := &UploadData{
 		TagSet:     make(map[string]string),
@@ -425,15 +631,34 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 			uploadData.ACLHeaders[strings.TrimPrefix(key, aclPrefix)] = val
 		}
 	}
+	if uploadData.Lock, err = lockFromMeta(multipartInfo.Meta); err != nil {
+		return nil, nil, fmt.Errorf("invalid object lock of upload: %w", err)
+	}
 
 	if encInfo.Enabled {
 		initMetadata[AttributeEncryptionAlgorithm] = encInfo.Algorithm
 		initMetadata[AttributeHMACKey] = encInfo.HMACKey
 		initMetadata[AttributeHMACSalt] = encInfo.HMACSalt
+		if encInfo.Managed() {
+			initMetadata[AttributeWrappedKey] = encInfo.WrappedKey
+		}
+		if encInfo.KMS() {
+			initMetadata[AttributeKMSKeyID] = encInfo.KMSKeyID
+		}
 		initMetadata[AttributeDecryptedSize] = strconv.FormatInt(multipartObjetSize, 10)
 		multipartObjetSize = int64(encMultipartObjectSize)
 	}
 
+	settings, err := n.GetBucketSettings(ctx, p.Info.Bkt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+
+	var etag string
+	if settings.ETagAlgorithm == data.ETagAlgorithmMD5 {
+		etag = multipartMD5ETag(parts)
+	}
+
 	r := &multiObjectReader{
 		ctx:   ctx,
 		layer: n,
@@ -450,6 +675,10 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		Size:         multipartObjetSize,
 		Encryption:   p.Info.Encryption,
 		CopiesNumber: multipartInfo.CopiesNumber,
+		ETag:         etag,
+		Lock:         uploadData.Lock,
+
+		BucketOwnerFullControl: uploadData.ACLHeaders[api.AmzACL] == CannedACLBucketOwnerFullControl,
 	})
 	if err != nil {
 		n.log.Error("could not put a completed object (multipart upload)",
@@ -476,6 +705,34 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 	return uploadData, extObjInfo, n.treeService.DeleteMultipartUpload(ctx, p.Info.Bkt, multipartInfo.ID)
 }
 
+// completedMultipartObject returns the latest version of the object if it was completed
+// by the same upload from the requested parts, so repeated completion of the upload succeeds.
+func (n *layer) completedMultipartObject(ctx context.Context, p *CompleteMultipartParams) *data.ExtendedObjectInfo {
+	extObjInfo, err := n.GetExtendedObjectInfo(ctx, &HeadObjectParams{BktInfo: p.Info.Bkt, Object: p.Info.Key})
+	if err != nil {
+		return nil
+	}
+
+	if extObjInfo.ObjectInfo.Headers[UploadCompletedID] != p.Info.UploadID {
+		return nil
+	}
+
+	completedParts := strings.Split(extObjInfo.ObjectInfo.Headers[UploadCompletedParts], ",")
+	if len(completedParts) != len(p.Parts) {
+		return nil
+	}
+
+	// see data.PartInfo.ToHeaderString for the format of completed part
+	for i, part := range p.Parts {
+		if !strings.HasPrefix(completedParts[i], strconv.Itoa(part.PartNumber)+"-") ||
+			!strings.HasSuffix(completedParts[i], "-"+strings.Trim(part.ETag, "\"")) {
+			return nil
+		}
+	}
+
+	return extObjInfo
+}
+
 func (n *layer) ListMultipartUploads(ctx context.Context, p *ListMultipartUploadsParams) (*ListMultipartUploadsInfo, error) {
 	var result ListMultipartUploadsInfo
 	if p.MaxUploads == 0 {
@@ -566,16 +823,19 @@ func (n *layer) ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsIn
 	}
 
 	res.Owner = multipartInfo.Owner
+	res.ChecksumAlgorithm = multipartInfo.Meta[UploadChecksumAlgorithm]
 
 	parts := make([]*Part, 0, len(partsInfo))
 
 	for _, partInfo := range partsInfo {
-		parts = append(parts, &Part{
+		part := &Part{
 			ETag:         partInfo.ETag,
 			LastModified: partInfo.Created.UTC().Format(time.RFC3339),
 			PartNumber:   partInfo.Number,
 			Size:         partInfo.Size,
-		})
+		}
+		part.setChecksum(partInfo.ChecksumAlgorithm, partInfo.Checksum)
+		parts = append(parts, part)
 	}
 
 	sort.Slice(parts, func(i, j int) bool {
@@ -583,20 +843,21 @@ func (n *layer) ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsIn
 	})
 
 	if p.PartNumberMarker != 0 {
-		for i, part := range parts {
-			if part.PartNumber > p.PartNumberMarker {
-				parts = parts[i:]
-				break
-			}
-		}
+		parts = parts[sort.Search(len(parts), func(i int) bool {
+			return parts[i].PartNumber > p.PartNumberMarker
+		}):]
 	}
 
 	if len(parts) > p.MaxParts {
 		res.IsTruncated = true
-		res.NextPartNumberMarker = parts[p.MaxParts-1].PartNumber
 		parts = parts[:p.MaxParts]
 	}
 
+	res.NextPartNumberMarker = p.PartNumberMarker
+	if len(parts) != 0 {
+		res.NextPartNumberMarker = parts[len(parts)-1].PartNumber
+	}
+
 	res.Parts = parts
 
 	return &res, nil
@@ -638,19 +899,11 @@ func (n *layer) getUploadParts(ctx context.Context, p *UploadInfoParams) (*data.
 	return multipartInfo, res, nil
 }
 
+// trimAfterUploadIDAndKey returns sorted uploads following the upload with the key and id.
 func trimAfterUploadIDAndKey(key, id string, uploads []*UploadInfo) []*UploadInfo {
-	var res []*UploadInfo
-	if len(uploads) != 0 && uploads[len(uploads)-1].Key < key {
-		return res
-	}
-
-	for _, obj := range uploads {
-		if obj.Key >= key && obj.UploadID > id {
-			res = append(res, obj)
-		}
-	}
-
-	return res
+	return uploads[sort.Search(len(uploads), func(i int) bool {
+		return uploads[i].Key > key || uploads[i].Key == key && uploads[i].UploadID > id
+	}):]
 }
 
 func trimAfterUploadKey(key string, objects []*UploadInfo) []*UploadInfo {
@@ -681,10 +934,15 @@ func uploadInfoFromMultipartInfo(uploadInfo *data.MultipartInfo, prefix, delimit
 		index := strings.Index(tail, delimiter)
 		if index >= 0 {
 			isDir = true
-			key = prefix + tail[:index+1]
+			key = prefix + tail[:index+len(delimiter)]
 		}
 	}
 
+	if isDir {
+		// common prefix groups uploads, so it has no upload ID to be used as the marker
+		return &UploadInfo{IsDir: true, Key: key}
+	}
+
 	return &UploadInfo{
 		IsDir:    isDir,
 		Key:      key,
@@ -693,3 +951,58 @@ func uploadInfoFromMultipartInfo(uploadInfo *data.MultipartInfo, prefix, delimit
 		Created:  uploadInfo.Created,
 	}
 }
+
+// multipartMD5ETag returns ETag of the completed multipart object as AWS S3 forms it: MD5 checksum of
+// concatenated MD5 checksums of parts with the number of parts suffix. Empty string is returned if ETag
+// of some part isn't MD5 checksum, e.g. the part was uploaded before the bucket ETag algorithm change.
+func multipartMD5ETag(parts []*data.PartInfo) string {
+	md5Hash := md5.New()
+	for _, part := range parts {
+		sum, err := hex.DecodeString(part.ETag)
+		if err != nil || len(sum) != md5.Size {
+			return ""
+		}
+		md5Hash.Write(sum)
+	}
+
+	return hex.EncodeToString(md5Hash.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+}
+
+// addLockMeta stores the object lock in the metadata of the multipart upload.
+func addLockMeta(meta map[string]string, lock *data.ObjectLock) {
+	if lock == nil {
+		return
+	}
+
+	if lock.Retention != nil {
+		meta[lockRetainUntil] = lock.Retention.Until.UTC().Format(time.RFC3339)
+		meta[lockCompliance] = strconv.FormatBool(lock.Retention.IsCompliance)
+	}
+	if lock.LegalHold != nil && lock.LegalHold.Enabled {
+		meta[lockLegalHold] = strconv.FormatBool(true)
+	}
+}
+
+// lockFromMeta returns the object lock stored by addLockMeta, nil if the upload has no lock.
+func lockFromMeta(meta map[string]string) (*data.ObjectLock, error) {
+	var lock data.ObjectLock
+
+	if until, ok := meta[lockRetainUntil]; ok {
+		untilTime, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("parse retain until date: %w", err)
+		}
+		lock.Retention = &data.RetentionLock{
+			Until:        untilTime,
+			IsCompliance: meta[lockCompliance] == strconv.FormatBool(true),
+		}
+	}
+	if _, ok := meta[lockLegalHold]; ok {
+		lock.LegalHold = &data.LegalHoldLock{Enabled: true}
+	}
+
+	if lock.Retention == nil && lock.LegalHold == nil {
+		return nil, nil
+	}
+	return &lock, nil
+}
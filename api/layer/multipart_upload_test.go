@@ -1,10 +1,16 @@
 package layer
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	stderrors "errors"
 	"sort"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestTrimAfterUploadIDAndKey(t *testing.T) {
@@ -18,11 +24,11 @@ func TestTrimAfterUploadIDAndKey(t *testing.T) {
 		{Key: "q", UploadID: "r"}, // key > id >
 	}
 	expectedUploadsListsIndexes := [][]int{
-		{1, 2, 3, 4, 6},
-		{4, 6},
-		{3, 4, 6},
-		{4, 6},
-		{6},
+		{1, 2, 3, 4, 5, 6},
+		{2, 3, 4, 5, 6},
+		{3, 4, 5, 6},
+		{4, 5, 6},
+		{5, 6},
 		{6},
 		{},
 	}
@@ -106,3 +112,49 @@ func TestTrimAfterUploadKey(t *testing.T) {
 		require.Empty(t, keys)
 	})
 }
+
+func TestUploadPartRetry(t *testing.T) {
+	tc := prepareContext(t)
+	content := []byte("content of the part")
+
+	uploadPart := func(retries int) error {
+		// connection is broken in the middle of the payload stream
+		neoFS := NewFaultyNeoFS(tc.testNeoFS)
+		neoFS.Inject(Fault{Op: FaultCreateObject, Count: 1, FailAfter: 4, Err: stderrors.New("stream is broken")})
+		n := NewLayer(zap.NewExample(), neoFS, &Config{
+			Caches:              DefaultCachesConfigs(zap.NewExample()),
+			AnonKey:             tc.layer.(*layer).anonKey,
+			TreeService:         tc.layer.(*layer).treeService,
+			PartRetries:         retries,
+			PartRetryBufferSize: int64(len(content)),
+		})
+
+		info := &UploadInfoParams{UploadID: uuid.New().String(), Bkt: tc.bktInfo, Key: "object"}
+		err := n.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: info, ChecksumAlgorithm: ChecksumSHA256})
+		require.NoError(t, err)
+
+		h := sha256.Sum256(content)
+		_, err = n.UploadPart(tc.ctx, &UploadPartParams{
+			Info:       info,
+			PartNumber: 1,
+			Size:       int64(len(content)),
+			Reader:     bytes.NewReader(content),
+			Checksum:   base64.StdEncoding.EncodeToString(h[:]),
+		})
+		return err
+	}
+
+	require.Error(t, uploadPart(0))
+
+	require.NoError(t, uploadPart(1))
+	var found bool
+	for _, obj := range tc.testNeoFS.Objects() {
+		for _, attr := range obj.Attributes() {
+			if attr.Key() == UploadPartNumberAttributeName {
+				require.Equal(t, content, obj.Payload())
+				found = true
+			}
+		}
+	}
+	require.True(t, found)
+}
@@ -128,8 +128,56 @@ type PrmObjectDelete struct {
 	Object oid.ID
 }
 
-// ErrAccessDenied is returned from NeoFS in case of access violation.
-var ErrAccessDenied = errors.New("access denied")
+// PrmObjectSearch groups parameters of NeoFS.SearchObjects operation.
+type PrmObjectSearch struct {
+	// Authentication parameters.
+	PrmAuth
+
+	// Container to select the objects from.
+	Container cid.ID
+
+	// Key-value object attributes which values should be equal to the given ones.
+	ExactAttributes [][2]string
+
+	// Key-value object attributes which values should start with the given prefixes.
+	PrefixAttributes [][2]string
+}
+
+var (
+	// ErrAccessDenied is returned from NeoFS in case of access violation.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrObjectNotFound is returned from NeoFS if the object doesn't exist or is already removed.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrQuotaExceeded is returned from NeoFS if the storage has no space for the object.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// NeoFSError is an error status returned from NeoFS. It matches one of ErrAccessDenied, ErrObjectNotFound
+// and ErrQuotaExceeded with errors.Is and unwraps to the original error of the NeoFS client.
+type NeoFSError struct {
+	// Kind is ErrAccessDenied, ErrObjectNotFound or ErrQuotaExceeded.
+	Kind error
+	// Reason is a message of the NeoFS status.
+	Reason string
+	// Cause is an error returned from the NeoFS client.
+	Cause error
+}
+
+func (e *NeoFSError) Error() string {
+	if e.Reason == "" {
+		return e.Kind.Error()
+	}
+	return e.Kind.Error() + ": " + e.Reason
+}
+
+// Is implements errors.Is interface, the error matches its kind.
+func (e *NeoFSError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *NeoFSError) Unwrap() error {
+	return e.Cause
+}
 
 // NeoFS represents virtual connection to NeoFS network.
 type NeoFS interface {
@@ -184,7 +232,8 @@ type NeoFS interface {
 	//
 	// Payload reader should be closed if it is no longer needed.
 	//
-	// It returns ErrAccessDenied on read access violation.
+	// It returns ErrAccessDenied on read access violation and ErrObjectNotFound if the object
+	// doesn't exist.
 	//
 	// It returns exactly one non-nil value. It returns any error encountered which
 	// prevented the object header from being read.
@@ -196,7 +245,8 @@ type NeoFS interface {
 	//
 	// Creation time should be written into the object (UTC).
 	//
-	// It returns ErrAccessDenied on write access violation.
+	// It returns ErrAccessDenied on write access violation and ErrQuotaExceeded if the storage
+	// has no space for the object.
 	//
 	// It returns exactly one non-zero value. It returns any error encountered which
 	// prevented the container from being created.
@@ -210,6 +260,14 @@ type NeoFS interface {
 	// It returns any error encountered which prevented the removal request from being sent.
 	DeleteObject(context.Context, PrmObjectDelete) error
 
+	// SearchObjects selects identifiers of the root objects from the NeoFS container
+	// matching all the attribute filters.
+	//
+	// It returns ErrAccessDenied on selection access violation.
+	//
+	// It returns any error encountered which prevented the objects from being selected.
+	SearchObjects(context.Context, PrmObjectSearch) ([]oid.ID, error)
+
 	// TimeToEpoch computes current epoch and the epoch that corresponds to the provided now and future time.
 	// Note:
 	// * future time must be after the now
@@ -217,4 +275,10 @@ type NeoFS interface {
 	//
 	// It returns any error encountered which prevented computing epochs.
 	TimeToEpoch(ctx context.Context, now time.Time, future time.Time) (uint64, uint64, error)
+
+	// NetmapSnapshot reads the current network map of NeoFS.
+	//
+	// It returns exactly one non-nil value. It returns any error encountered which
+	// prevented the network map from being read.
+	NetmapSnapshot(ctx context.Context) (*netmap.NetMap, error)
 }
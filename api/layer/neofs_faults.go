@@ -0,0 +1,319 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-sdk-go/container"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+)
+
+// FaultOp is a NeoFS operation the fault is injected into.
+type FaultOp string
+
+// Operations of NeoFS interface.
+const (
+	FaultCreateContainer  FaultOp = "CreateContainer"
+	FaultContainer        FaultOp = "Container"
+	FaultUserContainers   FaultOp = "UserContainers"
+	FaultSetContainerEACL FaultOp = "SetContainerEACL"
+	FaultContainerEACL    FaultOp = "ContainerEACL"
+	FaultDeleteContainer  FaultOp = "DeleteContainer"
+	FaultReadObject       FaultOp = "ReadObject"
+	FaultCreateObject     FaultOp = "CreateObject"
+	FaultDeleteObject     FaultOp = "DeleteObject"
+	FaultSearchObjects    FaultOp = "SearchObjects"
+	FaultTimeToEpoch      FaultOp = "TimeToEpoch"
+	FaultNetmapSnapshot   FaultOp = "NetmapSnapshot"
+)
+
+type (
+	// Fault is injected into matching NeoFS operations. Operations are matched in the order of calls,
+	// so faults are deterministic for sequential requests.
+	Fault struct {
+		// Op is the faulty operation, empty value matches all operations.
+		Op FaultOp
+		// Skip is a number of matching operations passed before the fault is injected.
+		Skip int
+		// Count is a number of operations the fault is injected into, zero means all next operations.
+		Count int
+		// Latency delays the operation, the delay is interrupted by the context.
+		Latency time.Duration
+		// Err is returned instead of the result of the operation. If FailAfter is positive,
+		// it's returned by the payload stream instead, io.ErrUnexpectedEOF is used if it's nil.
+		Err error
+		// FailAfter is a number of payload bytes of ReadObject and CreateObject streamed before Err.
+		FailAfter int64
+		// ShortReads limits the number of bytes returned by every Read call of ReadObject payload.
+		ShortReads int
+	}
+
+	// FaultyNeoFS wraps NeoFS and injects faults into its operations to test the resilience
+	// of the gateway, e.g. retries, garbage collection and the degraded mode.
+	FaultyNeoFS struct {
+		NeoFS
+
+		mu         sync.Mutex
+		faults     []*faultState
+		epochShift uint64
+	}
+
+	faultState struct {
+		Fault
+		matched int
+	}
+
+	// faultyReader streams the payload with injected faults.
+	faultyReader struct {
+		io.Reader
+		closer     io.Closer
+		left       int64
+		err        error
+		shortReads int
+	}
+)
+
+// NewFaultyNeoFS creates NeoFS wrapper without faults.
+func NewFaultyNeoFS(neoFS NeoFS) *FaultyNeoFS {
+	return &FaultyNeoFS{NeoFS: neoFS}
+}
+
+// Inject adds the fault, the first matching fault is injected into the operation.
+func (f *FaultyNeoFS) Inject(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &faultState{Fault: fault})
+}
+
+// Reset removes all faults and the epoch shift.
+func (f *FaultyNeoFS) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = nil
+	f.epochShift = 0
+}
+
+// FlipEpoch moves the current epoch of TimeToEpoch and NetmapSnapshot forward by the number of epochs
+// as if the network ticked them at once.
+func (f *FaultyNeoFS) FlipEpoch(epochs uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.epochShift += epochs
+}
+
+// fault returns the fault injected into the operation, it's nil if the operation is passed.
+func (f *FaultyNeoFS) fault(op FaultOp) *Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, state := range f.faults {
+		if state.Op != "" && state.Op != op {
+			continue
+		}
+		state.matched++
+		if state.matched <= state.Skip || state.Count > 0 && state.matched > state.Skip+state.Count {
+			continue
+		}
+		fault := state.Fault
+		return &fault
+	}
+	return nil
+}
+
+// inject delays the operation and returns the error of the fault. Stream faults aren't injected here.
+func (f *FaultyNeoFS) inject(ctx context.Context, op FaultOp) (*Fault, error) {
+	fault := f.fault(op)
+	if fault == nil {
+		return nil, nil
+	}
+
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if fault.Err != nil && !fault.streamed() {
+		return nil, fault.Err
+	}
+	return fault, nil
+}
+
+func (f *Fault) streamed() bool {
+	return f.FailAfter > 0 || f.ShortReads > 0
+}
+
+func (f *Fault) reader(r io.Reader, closer io.Closer) *faultyReader {
+	res := &faultyReader{Reader: r, closer: closer, left: -1, shortReads: f.ShortReads}
+	if f.FailAfter > 0 {
+		res.left = f.FailAfter
+		if res.err = f.Err; res.err == nil {
+			res.err = io.ErrUnexpectedEOF
+		}
+	}
+	return res
+}
+
+func (f *FaultyNeoFS) CreateContainer(ctx context.Context, prm PrmContainerCreate) (cid.ID, error) {
+	if _, err := f.inject(ctx, FaultCreateContainer); err != nil {
+		return cid.ID{}, err
+	}
+	return f.NeoFS.CreateContainer(ctx, prm)
+}
+
+func (f *FaultyNeoFS) Container(ctx context.Context, id cid.ID) (*container.Container, error) {
+	if _, err := f.inject(ctx, FaultContainer); err != nil {
+		return nil, err
+	}
+	return f.NeoFS.Container(ctx, id)
+}
+
+func (f *FaultyNeoFS) UserContainers(ctx context.Context, id user.ID) ([]cid.ID, error) {
+	if _, err := f.inject(ctx, FaultUserContainers); err != nil {
+		return nil, err
+	}
+	return f.NeoFS.UserContainers(ctx, id)
+}
+
+func (f *FaultyNeoFS) SetContainerEACL(ctx context.Context, table eacl.Table, sessionToken *session.Container) error {
+	if _, err := f.inject(ctx, FaultSetContainerEACL); err != nil {
+		return err
+	}
+	return f.NeoFS.SetContainerEACL(ctx, table, sessionToken)
+}
+
+func (f *FaultyNeoFS) ContainerEACL(ctx context.Context, id cid.ID) (*eacl.Table, error) {
+	if _, err := f.inject(ctx, FaultContainerEACL); err != nil {
+		return nil, err
+	}
+	return f.NeoFS.ContainerEACL(ctx, id)
+}
+
+func (f *FaultyNeoFS) DeleteContainer(ctx context.Context, id cid.ID, sessionToken *session.Container) error {
+	if _, err := f.inject(ctx, FaultDeleteContainer); err != nil {
+		return err
+	}
+	return f.NeoFS.DeleteContainer(ctx, id, sessionToken)
+}
+
+func (f *FaultyNeoFS) ReadObject(ctx context.Context, prm PrmObjectRead) (*ObjectPart, error) {
+	fault, err := f.inject(ctx, FaultReadObject)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := f.NeoFS.ReadObject(ctx, prm)
+	if err != nil || fault == nil || res.Payload == nil {
+		return res, err
+	}
+
+	return &ObjectPart{
+		Head:    res.Head,
+		Payload: fault.reader(res.Payload, res.Payload),
+	}, nil
+}
+
+func (f *FaultyNeoFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.ID, error) {
+	fault, err := f.inject(ctx, FaultCreateObject)
+	if err != nil {
+		return oid.ID{}, err
+	}
+	if fault != nil && fault.streamed() && prm.Payload != nil {
+		prm.Payload = fault.reader(prm.Payload, nil)
+	}
+	return f.NeoFS.CreateObject(ctx, prm)
+}
+
+func (f *FaultyNeoFS) DeleteObject(ctx context.Context, prm PrmObjectDelete) error {
+	if _, err := f.inject(ctx, FaultDeleteObject); err != nil {
+		return err
+	}
+	return f.NeoFS.DeleteObject(ctx, prm)
+}
+
+func (f *FaultyNeoFS) SearchObjects(ctx context.Context, prm PrmObjectSearch) ([]oid.ID, error) {
+	if _, err := f.inject(ctx, FaultSearchObjects); err != nil {
+		return nil, err
+	}
+	return f.NeoFS.SearchObjects(ctx, prm)
+}
+
+func (f *FaultyNeoFS) TimeToEpoch(ctx context.Context, now, future time.Time) (uint64, uint64, error) {
+	if _, err := f.inject(ctx, FaultTimeToEpoch); err != nil {
+		return 0, 0, err
+	}
+
+	curr, epoch, err := f.NeoFS.TimeToEpoch(ctx, now, future)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	f.mu.Lock()
+	shift := f.epochShift
+	f.mu.Unlock()
+	return curr + shift, epoch + shift, nil
+}
+
+func (f *FaultyNeoFS) NetmapSnapshot(ctx context.Context) (*netmap.NetMap, error) {
+	if _, err := f.inject(ctx, FaultNetmapSnapshot); err != nil {
+		return nil, err
+	}
+
+	nm, err := f.NeoFS.NetmapSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	shift := f.epochShift
+	f.mu.Unlock()
+	if shift != 0 {
+		res := *nm
+		res.SetEpoch(nm.Epoch() + shift)
+		nm = &res
+	}
+	return nm, nil
+}
+
+// Read implements io.Reader.
+func (r *faultyReader) Read(p []byte) (int, error) {
+	if r.left == 0 {
+		return 0, r.err
+	}
+	if r.shortReads > 0 && len(p) > r.shortReads {
+		p = p[:r.shortReads]
+	}
+	if r.left > 0 && int64(len(p)) > r.left {
+		p = p[:r.left]
+	}
+
+	n, err := r.Reader.Read(p)
+	if r.left > 0 {
+		r.left -= int64(n)
+		if r.left == 0 && errors.Is(err, io.EOF) {
+			// the payload is shorter than FailAfter bytes
+			r.left = -1
+		}
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (r *faultyReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
@@ -0,0 +1,114 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"io"
+	"testing"
+	"time"
+
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultyNeoFS(t *testing.T) {
+	tc := prepareContext(t)
+	neoFS := NewFaultyNeoFS(tc.testNeoFS)
+	errInjected := stderrors.New("injected")
+	content := []byte("content of the object")
+
+	createObject := func() (oid.ID, error) {
+		return neoFS.CreateObject(tc.ctx, PrmObjectCreate{
+			Container: tc.bktInfo.CID,
+			Creator:   tc.bktInfo.Owner,
+			Payload:   bytes.NewReader(content),
+		})
+	}
+	readObject := func(objID oid.ID) ([]byte, error) {
+		res, err := neoFS.ReadObject(tc.ctx, PrmObjectRead{Container: tc.bktInfo.CID, Object: objID, WithPayload: true})
+		if err != nil {
+			return nil, err
+		}
+		defer res.Payload.Close()
+		return io.ReadAll(res.Payload)
+	}
+
+	t.Run("skip and count", func(t *testing.T) {
+		neoFS.Inject(Fault{Op: FaultCreateObject, Skip: 1, Count: 2, Err: errInjected})
+		defer neoFS.Reset()
+
+		for _, expected := range []error{nil, errInjected, errInjected, nil} {
+			_, err := createObject()
+			require.ErrorIs(t, err, expected)
+		}
+	})
+
+	t.Run("mid-stream errors", func(t *testing.T) {
+		objID, err := createObject()
+		require.NoError(t, err)
+
+		neoFS.Inject(Fault{Op: FaultReadObject, Count: 1, FailAfter: 4})
+		neoFS.Inject(Fault{Op: FaultCreateObject, Count: 1, FailAfter: 4, Err: errInjected})
+		defer neoFS.Reset()
+
+		payload, err := readObject(objID)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.Equal(t, content[:4], payload)
+
+		_, err = createObject()
+		require.ErrorIs(t, err, errInjected)
+
+		payload, err = readObject(objID)
+		require.NoError(t, err)
+		require.Equal(t, content, payload)
+	})
+
+	t.Run("partial reads", func(t *testing.T) {
+		objID, err := createObject()
+		require.NoError(t, err)
+
+		neoFS.Inject(Fault{Op: FaultReadObject, ShortReads: 3})
+		defer neoFS.Reset()
+
+		res, err := neoFS.ReadObject(tc.ctx, PrmObjectRead{Container: tc.bktInfo.CID, Object: objID, WithPayload: true})
+		require.NoError(t, err)
+		n, err := res.Payload.Read(make([]byte, len(content)))
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+
+		payload, err := readObject(objID)
+		require.NoError(t, err)
+		require.Equal(t, content, payload)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		neoFS.Inject(Fault{Latency: time.Hour})
+		defer neoFS.Reset()
+
+		ctx, cancel := context.WithTimeout(tc.ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := neoFS.SearchObjects(ctx, PrmObjectSearch{Container: tc.bktInfo.CID})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("epoch flip", func(t *testing.T) {
+		defer neoFS.Reset()
+
+		now := time.Now()
+		curr, epoch, err := neoFS.TimeToEpoch(tc.ctx, now, now.Add(time.Hour))
+		require.NoError(t, err)
+		nm, err := neoFS.NetmapSnapshot(tc.ctx)
+		require.NoError(t, err)
+
+		neoFS.FlipEpoch(10)
+		flippedCurr, flippedEpoch, err := neoFS.TimeToEpoch(tc.ctx, now, now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, curr+10, flippedCurr)
+		require.Equal(t, epoch+10, flippedEpoch)
+
+		flippedNM, err := neoFS.NetmapSnapshot(tc.ctx)
+		require.NoError(t, err)
+		require.Equal(t, nm.Epoch()+10, flippedNM.Epoch())
+	})
+}
@@ -8,6 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	objectv2 "github.com/nspcc-dev/neofs-api-go/v2/object"
@@ -18,6 +21,7 @@ import (
 	"github.com/nspcc-dev/neofs-sdk-go/container"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/session"
@@ -27,9 +31,12 @@ import (
 type TestNeoFS struct {
 	NeoFS
 
+	// mu guards objects since replicas are written and objects are deleted concurrently.
+	mu           sync.Mutex
 	objects      map[string]*object.Object
 	containers   map[string]*container.Container
 	eaclTables   map[string]*eacl.Table
+	netMap       netmap.NetMap
 	currentEpoch uint64
 }
 
@@ -142,7 +149,11 @@ func (t *TestNeoFS) ReadObject(ctx context.Context, prm PrmObjectRead) (*ObjectP
 
 	sAddr := addr.EncodeToString()
 
-	if obj, ok := t.objects[sAddr]; ok {
+	t.mu.Lock()
+	obj, ok := t.objects[sAddr]
+	t.mu.Unlock()
+
+	if ok {
 		owner := getOwner(ctx)
 		if !obj.OwnerID().Equals(owner) {
 			return nil, ErrAccessDenied
@@ -161,10 +172,21 @@ func (t *TestNeoFS) ReadObject(ctx context.Context, prm PrmObjectRead) (*ObjectP
 		}, nil
 	}
 
-	return nil, fmt.Errorf("object not found %s", addr)
+	return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, addr)
 }
 
 func (t *TestNeoFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.ID, error) {
+	var payload []byte
+	if prm.Payload != nil {
+		var err error
+		if payload, err = io.ReadAll(prm.Payload); err != nil {
+			return oid.ID{}, err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	b := make([]byte, 32)
 	if _, err := io.ReadFull(rand.Reader, b); err != nil {
 		return oid.ID{}, err
@@ -174,6 +196,13 @@ func (t *TestNeoFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.
 
 	attrs := make([]object.Attribute, 0)
 
+	if !prm.CreationTime.IsZero() {
+		a := object.NewAttribute()
+		a.SetKey(object.AttributeTimestamp)
+		a.SetValue(strconv.FormatInt(prm.CreationTime.Unix(), 10))
+		attrs = append(attrs, *a)
+	}
+
 	if prm.Filepath != "" {
 		a := object.NewAttribute()
 		a.SetKey(object.AttributeFilePath)
@@ -204,14 +233,10 @@ func (t *TestNeoFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.
 	}
 
 	if prm.Payload != nil {
-		all, err := io.ReadAll(prm.Payload)
-		if err != nil {
-			return oid.ID{}, err
-		}
-		obj.SetPayload(all)
-		obj.SetPayloadSize(uint64(len(all)))
+		obj.SetPayload(payload)
+		obj.SetPayloadSize(uint64(len(payload)))
 		var hash checksum.Checksum
-		checksum.Calculate(&hash, checksum.SHA256, all)
+		checksum.Calculate(&hash, checksum.SHA256, payload)
 		obj.SetPayloadChecksum(hash)
 	}
 
@@ -223,11 +248,56 @@ func (t *TestNeoFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.
 	return objID, nil
 }
 
+func (t *TestNeoFS) SearchObjects(ctx context.Context, prm PrmObjectSearch) ([]oid.ID, error) {
+	owner := getOwner(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var res []oid.ID
+	for _, obj := range t.objects {
+		cnrID, _ := obj.ContainerID()
+		if !cnrID.Equals(prm.Container) || !obj.OwnerID().Equals(owner) {
+			continue
+		}
+
+		attributes := make(map[string]string, len(obj.Attributes()))
+		for _, attr := range obj.Attributes() {
+			attributes[attr.Key()] = attr.Value()
+		}
+
+		if matchAttributes(attributes, prm) {
+			objID, _ := obj.ID()
+			res = append(res, objID)
+		}
+	}
+
+	return res, nil
+}
+
+func matchAttributes(attributes map[string]string, prm PrmObjectSearch) bool {
+	for _, attr := range prm.ExactAttributes {
+		if value, ok := attributes[attr[0]]; !ok || value != attr[1] {
+			return false
+		}
+	}
+	for _, attr := range prm.PrefixAttributes {
+		if value, ok := attributes[attr[0]]; !ok || !strings.HasPrefix(value, attr[1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (t *TestNeoFS) DeleteObject(ctx context.Context, prm PrmObjectDelete) error {
 	var addr oid.Address
 	addr.SetContainer(prm.Container)
 	addr.SetObject(prm.Object)
 
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	if obj, ok := t.objects[addr.EncodeToString()]; ok {
 		owner := getOwner(ctx)
 		if !obj.OwnerID().Equals(owner) {
@@ -244,6 +314,15 @@ func (t *TestNeoFS) TimeToEpoch(_ context.Context, now, futureTime time.Time) (u
 	return t.currentEpoch, t.currentEpoch + uint64(futureTime.Sub(now).Seconds()), nil
 }
 
+func (t *TestNeoFS) NetmapSnapshot(_ context.Context) (*netmap.NetMap, error) {
+	nm := t.netMap
+	return &nm, nil
+}
+
+func (t *TestNeoFS) SetNetmap(nm netmap.NetMap) {
+	t.netMap = nm
+}
+
 func (t *TestNeoFS) AllObjects(cnrID cid.ID) []oid.ID {
 	result := make([]oid.ID, 0, len(t.objects))
 
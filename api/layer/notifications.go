@@ -25,11 +25,18 @@ func (n *layer) PutBucketNotificationConfiguration(ctx context.Context, p *PutBu
 		return fmt.Errorf("marshal notify configuration: %w", err)
 	}
 
+	var prevValue []byte
+	if prevConf, err := n.GetBucketNotificationConfiguration(ctx, p.BktInfo); err != nil {
+		n.log.Warn("couldn't get previous notification configuration", zap.Error(err))
+	} else if prevValue, err = xml.Marshal(prevConf); err != nil {
+		n.log.Warn("couldn't marshal previous notification configuration", zap.Error(err))
+	}
+
 	prm := PrmObjectCreate{
 		Container:    p.BktInfo.CID,
 		Creator:      p.BktInfo.Owner,
 		Payload:      bytes.NewReader(confXML),
-		Filepath:     p.BktInfo.NotificationConfigurationObjectName(),
+		Filepath:     n.systemObjectName(p.BktInfo.NotificationConfigurationObjectName()),
 		CreationTime: TimeNow(ctx),
 		CopiesNumber: p.CopiesNumber,
 	}
@@ -55,8 +62,7 @@ func (n *layer) PutBucketNotificationConfiguration(ctx context.Context, p *PutBu
 	}
 
 	n.cache.PutNotificationConfiguration(n.Owner(ctx), p.BktInfo, p.Configuration)
-
-	return nil
+	return n.saveBucketConfigChange(ctx, p.BktInfo, ConfigTypeNotification, prevValue, p.CopiesNumber)
 }
 
 func (n *layer) GetBucketNotificationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.NotificationConfiguration, error) {
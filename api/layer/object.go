@@ -1,11 +1,15 @@
 package layer
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"mime"
 	"path/filepath"
@@ -13,16 +17,17 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/minio/sio"
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
-	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"github.com/panjf2000/ants/v2"
 	"go.uber.org/zap"
 )
@@ -60,19 +65,16 @@ type (
 	}
 
 	allObjectParams struct {
-		Bucket            *data.BucketInfo
-		Delimiter         string
-		Prefix            string
-		MaxKeys           int
-		Marker            string
-		ContinuationToken string
+		Bucket    *data.BucketInfo
+		Delimiter string
+		Prefix    string
+		MaxKeys   int
+		Marker    string
+		// StartFrom is the name of the first object to list.
+		StartFrom string
 	}
 )
 
-const (
-	continuationToken = "<continuation-token>"
-)
-
 func newAddress(cnr cid.ID, obj oid.ID) oid.Address {
 	var addr oid.Address
 	addr.SetContainer(cnr)
@@ -184,6 +186,10 @@ func ParseCompletedPartHeader(hdr string) (*Part, error) {
 
 // PutObject stores object into NeoFS, took payload from io.Reader.
 func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.ExtendedObjectInfo, error) {
+	if err := n.checkSystemObjectName(p.Object); err != nil {
+		return nil, err
+	}
+
 	owner := n.Owner(ctx)
 
 	bktSettings, err := n.GetBucketSettings(ctx, p.BktInfo)
@@ -199,6 +205,50 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		IsUnversioned: !bktSettings.VersioningEnabled(),
 	}
 
+	if newVersion.IsUnversioned {
+		// the new version replaces the unversioned one, so it mustn't be protected
+		if err = n.checkUnversionedOverwrite(ctx, p.BktInfo, bktSettings, p.Object); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Encryption, err = n.newObjectEncryption(ctx, p.Encryption, p.BktInfo, p.Object); err != nil {
+		return nil, err
+	}
+
+	// encrypted objects can't be read by the replication without the encryption key of the request
+	var replicationRule *data.ReplicationRule
+	if p.Replica {
+		newVersion.ReplicationStatus = data.ReplicationStatusReplica
+	} else if !p.Encryption.Enabled() {
+		if replicationRule, err = n.matchReplicationRule(ctx, p.BktInfo, p.Object); err != nil {
+			return nil, err
+		}
+		if replicationRule != nil {
+			newVersion.ReplicationStatus = data.ReplicationStatusPending
+		}
+	}
+
+	// checksum is calculated over the plain payload
+	checksumHash := newChecksumHash(p.ChecksumAlgorithm)
+	if checksumHash != nil && (p.Checksum != "" || p.TrailingChecksum != nil) && p.Reader != nil {
+		if p.TrailingChecksum == nil {
+			p.Header[AttributeChecksumAlgorithm] = p.ChecksumAlgorithm
+			p.Header[AttributeChecksum] = p.Checksum
+		}
+		p.Reader = wrapReader(p.Reader, 64*1024, func(buf []byte) {
+			checksumHash.Write(buf)
+		})
+	} else {
+		checksumHash = nil
+	}
+
+	var md5Hash hash.Hash
+	if len(p.ContentMD5) != 0 && p.Reader != nil {
+		md5Hash = md5.New()
+		p.Reader = io.TeeReader(p.Reader, md5Hash)
+	}
+
 	r := p.Reader
 	if p.Encryption.Enabled() {
 		p.Header[AttributeDecryptedSize] = strconv.FormatInt(p.Size, 10)
@@ -227,6 +277,13 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		}
 	}
 
+	// the header can be copied from the source object
+	delete(p.Header, AttributeObjectOwner)
+	objOwner := objectOwner(owner, p.BktInfo, bktSettings, p.BucketOwnerFullControl)
+	if !objOwner.Equals(owner) {
+		p.Header[AttributeObjectOwner] = objOwner.EncodeToString()
+	}
+
 	prm := PrmObjectCreate{
 		Container:    p.BktInfo.CID,
 		Creator:      owner,
@@ -243,11 +300,44 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		prm.Attributes = append(prm.Attributes, [2]string{k, v})
 	}
 
-	id, hash, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	replica, err := n.startReplication(ctx, &prm, p.BktInfo, bktSettings.SyncReplication)
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		id   oid.ID
+		etag = p.ETag
+	)
+	if len(etag) != 0 {
+		id, _, err = n.objectPutAndHash(ctx, prm, p.BktInfo)
+	} else {
+		id, etag, _, err = n.objectPutAndETag(ctx, prm, p.BktInfo, bktSettings)
+	}
+	if replica != nil {
+		newVersion.Replica, err = n.finishReplication(ctx, replica, p.BktInfo, id, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the trailing checksum is known after the object header is sent, so it's saved in the version node
+	if checksumHash != nil && p.TrailingChecksum != nil {
+		p.Checksum = p.TrailingChecksum()
+		newVersion.ChecksumAlgorithm, newVersion.Checksum = p.ChecksumAlgorithm, p.Checksum
+	}
+
+	if checksumHash != nil && encodeChecksum(checksumHash) != p.Checksum ||
+		md5Hash != nil && !bytes.Equal(md5Hash.Sum(nil), p.ContentMD5) {
+		if err = n.objectDelete(ctx, p.BktInfo, id); err != nil {
+			n.log.Error("couldn't delete object with invalid checksum", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", id.EncodeToString()))
+		}
+		return nil, apiErrors.GetAPIError(apiErrors.ErrBadDigest)
+	}
+
 	reqInfo := api.GetReqInfo(ctx)
 	n.log.Debug("put object",
 		zap.String("reqId", reqInfo.RequestID),
@@ -255,11 +345,17 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		zap.String("object", p.Object), zap.Stringer("oid", id))
 
 	newVersion.OID = id
-	newVersion.ETag = hex.EncodeToString(hash)
-	if newVersion.ID, err = n.treeService.AddVersion(ctx, p.BktInfo, newVersion); err != nil {
+	newVersion.ETag = etag
+	unlock := n.versionLocks.lock(p.BktInfo.CID, p.Object)
+	newVersion.ID, err = n.treeService.AddVersion(ctx, p.BktInfo, newVersion)
+	unlock()
+	if err != nil {
 		return nil, fmt.Errorf("couldn't add new verion to tree service: %w", err)
 	}
 
+	// cached data is invalidated right after the tree service change, so the next read reflects it
+	generation := n.cache.ObjectChanged(p.BktInfo.CID, p.BktInfo.Name, p.Object)
+
 	if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
 		putLockInfoPrms := &PutLockInfoParams{
 			ObjVersion: &ObjectVersion{
@@ -277,38 +373,96 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		}
 	}
 
-	n.cache.CleanListCacheEntriesContainingObject(p.Object, p.BktInfo.CID)
+	// keep headers the same as objectInfoFromMeta produces, content type is stored separately
+	headers := make(map[string]string, len(p.Header))
+	for key, val := range p.Header {
+		if key != api.ContentType {
+			headers[key] = val
+		}
+	}
 
 	objInfo := &data.ObjectInfo{
 		ID:  id,
 		CID: p.BktInfo.CID,
 
-		Owner:       owner,
+		Owner:       objOwner,
 		Bucket:      p.BktInfo.Name,
 		Name:        p.Object,
+		FilePath:    p.Object,
 		Size:        p.Size,
 		Created:     prm.CreationTime,
-		Headers:     p.Header,
+		Headers:     headers,
 		ContentType: p.Header[api.ContentType],
 		HashSum:     newVersion.ETag,
+
+		ReplicationStatus: newVersion.ReplicationStatus,
 	}
+	applyNodeChecksum(objInfo, newVersion)
 
 	extendedObjInfo := &data.ExtendedObjectInfo{
 		ObjectInfo:  objInfo,
 		NodeVersion: newVersion,
 	}
 
-	n.cache.PutObjectWithName(owner, extendedObjInfo)
+	n.cache.PutObjectWithName(owner, extendedObjInfo, generation)
+	n.indexObject(ctx, p.BktInfo, objInfo)
+
+	if replicationRule != nil {
+		n.scheduleReplication(ctx, p.BktInfo, newVersion, replicationRule)
+	}
 
 	return extendedObjInfo, nil
 }
 
+// checkUnversionedOverwrite returns ObjectLocked error if the unversioned version of the object
+// is protected by the legal hold or the active retention, so it can't be replaced. BucketFrozen
+// error is returned if the object exists in the frozen bucket.
+func (n *layer) checkUnversionedOverwrite(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, objectName string) error {
+	if !bktInfo.ObjectLockEnabled && !settings.Frozen {
+		return nil
+	}
+
+	nodeVersion, err := n.treeService.GetUnversioned(ctx, bktInfo, objectName)
+	if err != nil {
+		if errors.Is(err, ErrNodeNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if settings.Frozen && !nodeVersion.IsDeleteMarker() {
+		return apiErrors.GetAPIError(apiErrors.ErrBucketFrozen)
+	}
+	if !bktInfo.ObjectLockEnabled {
+		return nil
+	}
+
+	if _, err = n.checkLockToDelete(ctx, bktInfo, nodeVersion, false); errors.Is(err, ErrObjectLocked) {
+		return apiErrors.GetAPIError(apiErrors.ErrObjectLocked)
+	}
+	return err
+}
+
+// objectOwner returns the owner of the new object according to the object ownership of the bucket.
+func objectOwner(writer user.ID, bktInfo *data.BucketInfo, settings *data.BucketSettings, bucketOwnerFullControl bool) user.ID {
+	switch settings.ObjectOwnership {
+	case data.ObjectOwnershipBucketOwnerEnforced:
+		return bktInfo.Owner
+	case data.ObjectOwnershipBucketOwnerPreferred:
+		if bucketOwnerFullControl {
+			return bktInfo.Owner
+		}
+	}
+	return writer
+}
+
 func (n *layer) headLastVersionIfNotDeleted(ctx context.Context, bkt *data.BucketInfo, objectName string) (*data.ExtendedObjectInfo, error) {
 	owner := n.Owner(ctx)
 	if extObjInfo := n.cache.GetLastObject(owner, bkt.Name, objectName); extObjInfo != nil {
 		return extObjInfo, nil
 	}
 
+	generation := n.cache.Generation(bkt.CID)
 	node, err := n.treeService.GetLatestVersion(ctx, bkt, objectName)
 	if err != nil {
 		if errors.Is(err, ErrNodeNotFound) {
@@ -321,22 +475,31 @@ func (n *layer) headLastVersionIfNotDeleted(ctx context.Context, bkt *data.Bucke
 		return nil, apiErrors.GetAPIError(apiErrors.ErrNoSuchKey)
 	}
 
-	meta, err := n.objectHead(ctx, bkt, node.OID)
+	objInfo, err := n.objectInfoFromNode(ctx, bkt, node)
 	if err != nil {
 		return nil, err
 	}
-	objInfo := objectInfoFromMeta(bkt, meta)
 
 	extObjInfo := &data.ExtendedObjectInfo{
 		ObjectInfo:  objInfo,
 		NodeVersion: node,
 	}
 
-	n.cache.PutObjectWithName(owner, extObjInfo)
+	n.cache.PutObjectWithName(owner, extObjInfo, generation)
 
 	return extObjInfo, nil
 }
 
+// DeleteMarkerError is returned when the requested version of the object is a delete marker.
+type DeleteMarkerError struct {
+	VersionID    string
+	LastModified time.Time
+}
+
+func (e *DeleteMarkerError) Error() string {
+	return fmt.Sprintf("version %s is a delete marker", e.VersionID)
+}
+
 func (n *layer) headVersion(ctx context.Context, bkt *data.BucketInfo, p *HeadObjectParams) (*data.ExtendedObjectInfo, error) {
 	var err error
 	var foundVersion *data.NodeVersion
@@ -365,19 +528,22 @@ func (n *layer) headVersion(ctx context.Context, bkt *data.BucketInfo, p *HeadOb
 		}
 	}
 
+	if foundVersion.IsDeleteMarker() {
+		return nil, &DeleteMarkerError{VersionID: p.VersionID, LastModified: foundVersion.DeleteMarker.Created}
+	}
+
 	owner := n.Owner(ctx)
 	if extObjInfo := n.cache.GetObject(owner, newAddress(bkt.CID, foundVersion.OID)); extObjInfo != nil {
 		return extObjInfo, nil
 	}
 
-	meta, err := n.objectHead(ctx, bkt, foundVersion.OID)
+	objInfo, err := n.objectInfoFromNode(ctx, bkt, foundVersion)
 	if err != nil {
-		if client.IsErrObjectNotFound(err) {
+		if errors.Is(err, ErrObjectNotFound) {
 			return nil, apiErrors.GetAPIError(apiErrors.ErrNoSuchVersion)
 		}
 		return nil, err
 	}
-	objInfo := objectInfoFromMeta(bkt, meta)
 
 	extObjInfo := &data.ExtendedObjectInfo{
 		ObjectInfo:  objInfo,
@@ -399,6 +565,7 @@ func (n *layer) objectDelete(ctx context.Context, bktInfo *data.BucketInfo, idOb
 	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
 
 	n.cache.DeleteObject(newAddress(bktInfo.CID, idObj))
+	n.unindexObject(ctx, bktInfo, idObj)
 
 	return n.neoFS.DeleteObject(ctx, prm)
 }
@@ -408,9 +575,7 @@ func (n *layer) objectDelete(ctx context.Context, bktInfo *data.BucketInfo, idOb
 func (n *layer) objectPutAndHash(ctx context.Context, prm PrmObjectCreate, bktInfo *data.BucketInfo) (oid.ID, []byte, error) {
 	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
 	hash := sha256.New()
-	prm.Payload = wrapReader(prm.Payload, 64*1024, func(buf []byte) {
-		hash.Write(buf)
-	})
+	prm.Payload = hashPayload(prm.Payload, hash)
 	id, err := n.neoFS.CreateObject(ctx, prm)
 	if err != nil {
 		return oid.ID{}, nil, err
@@ -418,6 +583,30 @@ func (n *layer) objectPutAndHash(ctx context.Context, prm PrmObjectCreate, bktIn
 	return id, hash.Sum(nil), nil
 }
 
+// objectPutAndETag creates the object and returns its ETag formed with the algorithm from the bucket settings
+// and SHA256 hash of the payload.
+func (n *layer) objectPutAndETag(ctx context.Context, prm PrmObjectCreate, bktInfo *data.BucketInfo, settings *data.BucketSettings) (oid.ID, string, []byte, error) {
+	var md5Hash hash.Hash
+	if settings.ETagAlgorithm == data.ETagAlgorithmMD5 {
+		md5Hash = md5.New()
+		prm.Payload = hashPayload(prm.Payload, md5Hash)
+	}
+
+	id, hash, err := n.objectPutAndHash(ctx, prm, bktInfo)
+	if err != nil {
+		return oid.ID{}, "", nil, err
+	}
+
+	switch settings.ETagAlgorithm {
+	case data.ETagAlgorithmMD5:
+		return id, hex.EncodeToString(md5Hash.Sum(nil)), hash, nil
+	case data.ETagAlgorithmCID:
+		return id, id.EncodeToString(), hash, nil
+	default:
+		return id, hex.EncodeToString(hash), hash, nil
+	}
+}
+
 // ListObjectsV1 returns objects in a bucket for requests of Version 1.
 func (n *layer) ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (*ListObjectsInfoV1, error) {
 	var result ListObjectsInfoV1
@@ -450,12 +639,19 @@ func (n *layer) ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (*Lis
 	var result ListObjectsInfoV2
 
 	prm := allObjectParams{
-		Bucket:            p.BktInfo,
-		Delimiter:         p.Delimiter,
-		Prefix:            p.Prefix,
-		MaxKeys:           p.MaxKeys,
-		Marker:            p.StartAfter,
-		ContinuationToken: p.ContinuationToken,
+		Bucket:    p.BktInfo,
+		Delimiter: p.Delimiter,
+		Prefix:    p.Prefix,
+		MaxKeys:   p.MaxKeys,
+		Marker:    p.StartAfter,
+	}
+
+	if p.ContinuationToken != "" {
+		startFrom, err := base64.RawURLEncoding.DecodeString(p.ContinuationToken)
+		if err != nil || len(startFrom) == 0 {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrIncorrectContinuationToken)
+		}
+		prm.StartFrom = string(startFrom)
 	}
 
 	objects, next, err := n.getLatestObjectsVersions(ctx, prm)
@@ -465,7 +661,9 @@ func (n *layer) ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (*Lis
 
 	if next != nil {
 		result.IsTruncated = true
-		result.NextContinuationToken = next.ID.EncodeToString()
+		// token is the name of the next object, so listing continues
+		// even if this object is removed or replaced
+		result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(next.Name))
 	}
 
 	result.Prefixes, result.Objects = triageObjects(objects)
@@ -488,14 +686,15 @@ func (n *layer) getLatestObjectsVersions(ctx context.Context, p allObjectParams)
 
 	owner := n.Owner(ctx)
 	cacheKey := cache.CreateObjectsListCacheKey(p.Bucket.CID, p.Prefix, true)
-	nodeVersions := n.cache.GetList(owner, cacheKey)
+	nodeVersions := n.getCachedList(owner, cacheKey)
 
 	if nodeVersions == nil {
+		generation := n.cache.Generation(p.Bucket.CID)
 		nodeVersions, err = n.treeService.GetLatestVersionsByPrefix(ctx, p.Bucket, p.Prefix)
 		if err != nil {
 			return nil, nil, err
 		}
-		n.cache.PutList(owner, cacheKey, nodeVersions)
+		n.cache.PutList(owner, cacheKey, nodeVersions, generation)
 	}
 
 	if len(nodeVersions) == 0 {
@@ -506,9 +705,12 @@ func (n *layer) getLatestObjectsVersions(ctx context.Context, p allObjectParams)
 		return nodeVersions[i].FilePath < nodeVersions[j].FilePath
 	})
 
+	page := listingPage(p, nodeVersions)
+	indexed := n.indexedObjects(ctx, p.Bucket, page)
+
 	poolCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	objOutCh, err := n.initWorkerPool(poolCtx, 2, p, nodesGenerator(poolCtx, p, nodeVersions))
+	objOutCh, err := n.initWorkerPool(poolCtx, 2, p, indexed, nodesGenerator(poolCtx, p, page))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to init worker pool: %w", err)
 	}
@@ -531,6 +733,23 @@ func (n *layer) getLatestObjectsVersions(ctx context.Context, p allObjectParams)
 	return
 }
 
+// listingPage returns nodes of the listing page and one more node to know the next marker.
+func listingPage(p allObjectParams, nodeVersions []*data.NodeVersion) []*data.NodeVersion {
+	existed := make(map[string]struct{}, p.MaxKeys+1)
+	page := make([]*data.NodeVersion, 0, p.MaxKeys+1)
+	for _, node := range nodeVersions {
+		if shouldSkip(node, p, existed) {
+			continue
+		}
+		page = append(page, node)
+		if len(page) == p.MaxKeys+1 {
+			break
+		}
+	}
+
+	return page
+}
+
 func nodesGenerator(ctx context.Context, p allObjectParams, nodeVersions []*data.NodeVersion) <-chan *data.NodeVersion {
 	nodeCh := make(chan *data.NodeVersion)
 	existed := make(map[string]struct{}, len(nodeVersions)) // to squash the same directories
@@ -559,7 +778,7 @@ func nodesGenerator(ctx context.Context, p allObjectParams, nodeVersions []*data
 	return nodeCh
 }
 
-func (n *layer) initWorkerPool(ctx context.Context, size int, p allObjectParams, input <-chan *data.NodeVersion) (<-chan *data.ObjectInfo, error) {
+func (n *layer) initWorkerPool(ctx context.Context, size int, p allObjectParams, indexed map[oid.ID]*data.ObjectInfo, input <-chan *data.NodeVersion) (<-chan *data.ObjectInfo, error) {
 	pool, err := ants.NewPool(size, ants.WithLogger(&logWrapper{n.log}))
 	if err != nil {
 		return nil, fmt.Errorf("coudln't init go pool for listing: %w", err)
@@ -583,7 +802,10 @@ func (n *layer) initWorkerPool(ctx context.Context, size int, p allObjectParams,
 				wg.Add(1)
 				err = pool.Submit(func() {
 					defer wg.Done()
-					oi := n.objectInfoFromObjectsCacheOrNeoFS(ctx, p.Bucket, node, p.Prefix, p.Delimiter)
+					oi := indexedListingObject(indexed, node, p.Prefix, p.Delimiter)
+					if oi == nil {
+						oi = n.objectInfoFromObjectsCacheOrNeoFS(ctx, p.Bucket, node, p.Prefix, p.Delimiter)
+					}
 					if oi == nil {
 						// try to get object again
 						if oi = n.objectInfoFromObjectsCacheOrNeoFS(ctx, p.Bucket, node, p.Prefix, p.Delimiter); oi == nil {
@@ -627,20 +849,31 @@ func (n *layer) bucketNodeVersions(ctx context.Context, bkt *data.BucketInfo, pr
 
 	owner := n.Owner(ctx)
 	cacheKey := cache.CreateObjectsListCacheKey(bkt.CID, prefix, false)
-	nodeVersions := n.cache.GetList(owner, cacheKey)
+	nodeVersions := n.getCachedList(owner, cacheKey)
 
 	if nodeVersions == nil {
+		generation := n.cache.Generation(bkt.CID)
 		nodeVersions, err = n.treeService.GetAllVersionsByPrefix(ctx, bkt, prefix)
 		if err != nil {
 			return nil, fmt.Errorf("get all versions from tree service: %w", err)
 		}
 
-		n.cache.PutList(owner, cacheKey, nodeVersions)
+		n.cache.PutList(owner, cacheKey, nodeVersions, generation)
 	}
 
 	return nodeVersions, nil
 }
 
+// getCachedList returns cached list of object versions
+// unless listing consistency is required.
+func (n *layer) getCachedList(owner user.ID, key cache.ObjectsListKey) []*data.NodeVersion {
+	if n.consistentListing {
+		return nil
+	}
+
+	return n.cache.GetList(owner, key)
+}
+
 func (n *layer) getAllObjectsVersions(ctx context.Context, bkt *data.BucketInfo, prefix, delimiter string) (map[string][]*data.ExtendedObjectInfo, error) {
 	nodeVersions, err := n.bucketNodeVersions(ctx, bkt, prefix)
 	if err != nil {
@@ -648,6 +881,7 @@ func (n *layer) getAllObjectsVersions(ctx context.Context, bkt *data.BucketInfo,
 	}
 
 	versions := make(map[string][]*data.ExtendedObjectInfo, len(nodeVersions))
+	indexed := n.indexedObjects(ctx, bkt, nodeVersions)
 
 	for _, nodeVersion := range nodeVersions {
 		oi := &data.ObjectInfo{}
@@ -658,7 +892,7 @@ func (n *layer) getAllObjectsVersions(ctx context.Context, bkt *data.BucketInfo,
 			oi.Owner = nodeVersion.DeleteMarker.Owner
 			oi.Created = nodeVersion.DeleteMarker.Created
 			oi.IsDeleteMarker = true
-		} else {
+		} else if oi = indexedListingObject(indexed, nodeVersion, prefix, delimiter); oi == nil {
 			if oi = n.objectInfoFromObjectsCacheOrNeoFS(ctx, bkt, nodeVersion, prefix, delimiter); oi == nil {
 				continue
 			}
@@ -703,13 +937,8 @@ func shouldSkip(node *data.NodeVersion, p allObjectParams, existed map[string]st
 		return true
 	}
 
-	if p.ContinuationToken != "" {
-		if _, ok := existed[continuationToken]; !ok {
-			if p.ContinuationToken != node.OID.EncodeToString() {
-				return true
-			}
-			existed[continuationToken] = struct{}{}
-		}
+	if filePath < p.StartFrom {
+		return true
 	}
 
 	existed[filePath] = struct{}{}
@@ -750,13 +979,12 @@ func (n *layer) objectInfoFromObjectsCacheOrNeoFS(ctx context.Context, bktInfo *
 		return extInfo.ObjectInfo
 	}
 
-	meta, err := n.objectHead(ctx, bktInfo, node.OID)
+	oi, err := n.objectInfoFromNode(ctx, bktInfo, node)
 	if err != nil {
 		n.log.Warn("could not fetch object meta", zap.Error(err))
 		return nil
 	}
 
-	oi = objectInfoFromMeta(bktInfo, meta)
 	n.cache.PutObject(owner, &data.ExtendedObjectInfo{ObjectInfo: oi, NodeVersion: node})
 
 	return oi
@@ -769,7 +997,7 @@ func tryDirectory(bktInfo *data.BucketInfo, node *data.NodeVersion, prefix, deli
 	}
 
 	return &data.ObjectInfo{
-		ID:             node.OID, // to use it as continuation token
+		ID:             node.OID,
 		CID:            bktInfo.CID,
 		IsDir:          true,
 		IsDeleteMarker: node.IsDeleteMarker(),
@@ -795,6 +1023,45 @@ func tryDirectoryName(node *data.NodeVersion, prefix, delimiter string) string {
 	return ""
 }
 
+// hashPayload writes the payload to the hash while it's read. Seekable payloads stay seekable, so they
+// can be re-sent if NeoFS rejects the write, e.g. with the session expired at the epoch change.
+func hashPayload(input io.Reader, h hash.Hash) io.Reader {
+	if seeker, ok := input.(io.ReadSeeker); ok {
+		return &hashReader{ReadSeeker: seeker, hash: h}
+	}
+
+	return wrapReader(input, 64*1024, func(buf []byte) {
+		h.Write(buf)
+	})
+}
+
+// hashReader writes the seekable payload to the hash while it's read.
+type hashReader struct {
+	io.ReadSeeker
+	hash hash.Hash
+}
+
+func (r *hashReader) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+// Seek supports rewinding to the start only, the hash is reset then.
+func (r *hashReader) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, errors.New("hashed payload can be rewound to the start only")
+	}
+
+	pos, err := r.ReadSeeker.Seek(0, io.SeekStart)
+	if err != nil {
+		return pos, err
+	}
+	r.hash.Reset()
+
+	return pos, nil
+}
+
 func wrapReader(input io.Reader, bufSize int, f func(buf []byte)) io.Reader {
 	if input == nil {
 		return nil
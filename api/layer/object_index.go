@@ -0,0 +1,179 @@
+package layer
+
+import (
+	"context"
+	errorsStd "errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// ObjectIndex is a persistent index of object headers. It allows to serve listings
+// without requests of every object to NeoFS storage nodes. The index is updated on every object mutation and
+// reconciled with the tree service in background, so it's allowed to lose some updates.
+type ObjectIndex interface {
+	// GetObject returns info of the indexed object, object name is taken from the tree service.
+	//
+	// If object isn't indexed returns ErrNotIndexed error.
+	GetObject(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (*data.ObjectInfo, error)
+	// GetObjects returns infos of the indexed objects from the list, objects which aren't indexed are skipped.
+	GetObjects(ctx context.Context, bktInfo *data.BucketInfo, objIDs []oid.ID) (map[oid.ID]*data.ObjectInfo, error)
+	// PutObject adds object info to the index.
+	PutObject(ctx context.Context, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) error
+	// DeleteObject removes object info from the index.
+	DeleteObject(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) error
+	// IterateObjects calls f for every indexed object of the bucket, iteration stops on the first f error.
+	IterateObjects(ctx context.Context, bktInfo *data.BucketInfo, f func(oid.ID) error) error
+}
+
+// ErrNotIndexed is returned from ObjectIndex if object isn't found in the index.
+var ErrNotIndexed = errorsStd.New("object is not indexed")
+
+// ReconcileObjectIndex adds missing objects of the bucket to the object index and removes stale ones.
+func (n *layer) ReconcileObjectIndex(ctx context.Context, bktInfo *data.BucketInfo) error {
+	if n.objectIndex == nil {
+		return nil
+	}
+
+	live := make(map[oid.ID]struct{})
+	// object info of the scanned object is taken from the index or is added to it
+	err := n.scanObjects(ctx, &scanObjectsParams{BktInfo: bktInfo, AllVersions: true}, func(extObjInfo *data.ExtendedObjectInfo) error {
+		live[extObjInfo.NodeVersion.OID] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("scan objects: %w", err)
+	}
+
+	var stale []oid.ID
+	err = n.objectIndex.IterateObjects(ctx, bktInfo, func(objID oid.ID) error {
+		if _, ok := live[objID]; !ok {
+			stale = append(stale, objID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("iterate index: %w", err)
+	}
+
+	for _, objID := range stale {
+		if err = n.objectIndex.DeleteObject(ctx, bktInfo, objID); err != nil {
+			return fmt.Errorf("delete stale object from index: %w", err)
+		}
+	}
+
+	n.log.Debug("object index reconciled", zap.String("bucket", bktInfo.Name),
+		zap.Int("objects", len(live)), zap.Int("stale", len(stale)))
+
+	return nil
+}
+
+// indexObject adds object info to the object index if it's enabled.
+// The index is reconciled in background, so failures are only logged.
+func (n *layer) indexObject(ctx context.Context, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) {
+	if n.objectIndex == nil {
+		return
+	}
+
+	if err := n.objectIndex.PutObject(ctx, bktInfo, objInfo); err != nil {
+		n.log.Warn("couldn't add object to index", zap.Error(err),
+			zap.String("bucket", bktInfo.Name), zap.Stringer("oid", objInfo.ID))
+	}
+}
+
+// unindexObject removes object from the object index if it's enabled.
+func (n *layer) unindexObject(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) {
+	if n.objectIndex == nil {
+		return
+	}
+
+	if err := n.objectIndex.DeleteObject(ctx, bktInfo, objID); err != nil {
+		n.log.Warn("couldn't delete object from index", zap.Error(err),
+			zap.String("bucket", bktInfo.Name), zap.Stringer("oid", objID))
+	}
+}
+
+// indexedObjects returns infos of listed objects from the object index if it's enabled. Listing is
+// served from the index only if the owner has read access to the bucket, which is confirmed by
+// the NeoFS request of one of the indexed objects and is cached.
+func (n *layer) indexedObjects(ctx context.Context, bktInfo *data.BucketInfo, nodeVersions []*data.NodeVersion) map[oid.ID]*data.ObjectInfo {
+	if n.objectIndex == nil {
+		return nil
+	}
+
+	objIDs := make([]oid.ID, 0, len(nodeVersions))
+	for _, nodeVersion := range nodeVersions {
+		// headers of packed objects are stored in the tree node, archived objects have the restore state
+		if !nodeVersion.IsDeleteMarker() && nodeVersion.Pack == nil && nodeVersion.Archive == nil {
+			objIDs = append(objIDs, nodeVersion.OID)
+		}
+	}
+	if len(objIDs) == 0 {
+		return nil
+	}
+
+	objInfos, err := n.objectIndex.GetObjects(ctx, bktInfo, objIDs)
+	if err != nil {
+		n.log.Warn("couldn't get objects from index", zap.Error(err), zap.String("bucket", bktInfo.Name))
+		return nil
+	}
+	if len(objInfos) == 0 || !n.checkIndexAccess(ctx, bktInfo, objIDs, objInfos) {
+		return nil
+	}
+
+	for _, nodeVersion := range nodeVersions {
+		objInfo, ok := objInfos[nodeVersion.OID]
+		if !ok {
+			continue
+		}
+		objInfo.Name = nodeVersion.FilePath
+		// ETag of the object depends on the bucket settings and may differ from the NeoFS payload checksum
+		if len(nodeVersion.ETag) != 0 {
+			objInfo.HashSum = nodeVersion.ETag
+		}
+		if err = applyObjectMetadata(objInfo, nodeVersion); err != nil {
+			n.log.Warn("couldn't apply object metadata", zap.Error(err), zap.String("bucket", bktInfo.Name))
+			delete(objInfos, nodeVersion.OID)
+		}
+	}
+
+	return objInfos
+}
+
+// checkIndexAccess confirms read access of the request owner to the bucket by the NeoFS request
+// of the first indexed object.
+func (n *layer) checkIndexAccess(ctx context.Context, bktInfo *data.BucketInfo, objIDs []oid.ID, objInfos map[oid.ID]*data.ObjectInfo) bool {
+	owner := n.Owner(ctx)
+	if n.cache.GetIndexAccess(owner, bktInfo.CID) {
+		return true
+	}
+
+	for _, objID := range objIDs {
+		if _, ok := objInfos[objID]; !ok {
+			continue
+		}
+
+		if _, err := n.objectHead(ctx, bktInfo, objID); err != nil {
+			n.log.Debug("object index isn't used for listing", zap.Error(err),
+				zap.String("bucket", bktInfo.Name), zap.Stringer("oid", objID))
+			return false
+		}
+
+		n.cache.PutIndexAccess(owner, bktInfo.CID)
+		return true
+	}
+
+	return false
+}
+
+// indexedListingObject returns info of the listed object from the indexed infos,
+// nil is returned if the node is a directory of the listing or it isn't indexed.
+func indexedListingObject(indexed map[oid.ID]*data.ObjectInfo, node *data.NodeVersion, prefix, delimiter string) *data.ObjectInfo {
+	if len(indexed) == 0 || node.IsDeleteMarker() || len(tryDirectoryName(node, prefix, delimiter)) != 0 {
+		return nil
+	}
+
+	return indexed[node.OID]
+}
@@ -0,0 +1,100 @@
+package layer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	bearertest "github.com/nspcc-dev/neofs-sdk-go/bearer/test"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/stretchr/testify/require"
+)
+
+type testObjectIndex struct {
+	objects map[oid.ID]data.ObjectInfo
+}
+
+func (t *testObjectIndex) GetObject(_ context.Context, _ *data.BucketInfo, objID oid.ID) (*data.ObjectInfo, error) {
+	objInfo, ok := t.objects[objID]
+	if !ok {
+		return nil, ErrNotIndexed
+	}
+	return &objInfo, nil
+}
+
+func (t *testObjectIndex) GetObjects(_ context.Context, _ *data.BucketInfo, objIDs []oid.ID) (map[oid.ID]*data.ObjectInfo, error) {
+	objInfos := make(map[oid.ID]*data.ObjectInfo, len(objIDs))
+	for _, objID := range objIDs {
+		if objInfo, ok := t.objects[objID]; ok {
+			objInfos[objID] = &objInfo
+		}
+	}
+	return objInfos, nil
+}
+
+func (t *testObjectIndex) PutObject(_ context.Context, _ *data.BucketInfo, objInfo *data.ObjectInfo) error {
+	t.objects[objInfo.ID] = *objInfo
+	return nil
+}
+
+func (t *testObjectIndex) DeleteObject(_ context.Context, _ *data.BucketInfo, objID oid.ID) error {
+	delete(t.objects, objID)
+	return nil
+}
+
+func (t *testObjectIndex) IterateObjects(_ context.Context, _ *data.BucketInfo, f func(oid.ID) error) error {
+	for objID := range t.objects {
+		if err := f(objID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestObjectIndex(t *testing.T) {
+	tc := prepareContext(t)
+	index := &testObjectIndex{objects: make(map[oid.ID]data.ObjectInfo)}
+	tc.layer.(*layer).objectIndex = index
+
+	objInfo := tc.putObject([]byte("content"))
+	require.Contains(t, index.objects, objInfo.ID)
+
+	indexed := index.objects[objInfo.ID]
+	indexed.ContentType = "application/indexed"
+	index.objects[objInfo.ID] = indexed
+	tc.layer.(*layer).cache.DeleteObject(newAddress(tc.bktInfo.CID, objInfo.ID))
+
+	// listing is served from the index after access to the bucket is confirmed by NeoFS
+	objs := tc.listObjectsV1()
+	require.Len(t, objs, 1)
+	require.Equal(t, tc.obj, objs[0].Name)
+	require.Equal(t, indexed.ContentType, objs[0].ContentType)
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	bearerToken := bearertest.Token()
+	require.NoError(t, bearerToken.Sign(key.PrivateKey))
+	otherCtx := context.WithValue(context.Background(), api.BoxData, &accessbox.Box{
+		Gate: &accessbox.GateData{BearerToken: &bearerToken, GateKey: key.PublicKey()},
+	})
+
+	// user without access to objects doesn't get them from the index
+	res, err := tc.layer.ListObjectsV2(otherCtx, &ListObjectsParamsV2{
+		ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, MaxKeys: 1000},
+	})
+	require.NoError(t, err)
+	for _, obj := range res.Objects {
+		require.NotEqual(t, indexed.ContentType, obj.ContentType)
+	}
+
+	staleID := oidtest.ID()
+	index.objects[staleID] = data.ObjectInfo{ID: staleID}
+	delete(index.objects, objInfo.ID)
+
+	require.NoError(t, tc.layer.ReconcileObjectIndex(tc.ctx, tc.bktInfo))
+	require.NotContains(t, index.objects, staleID)
+}
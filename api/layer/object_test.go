@@ -2,12 +2,22 @@ package layer
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
 	"io"
+	"strings"
 	"testing"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestWrapReader(t *testing.T) {
@@ -27,3 +37,315 @@ func TestWrapReader(t *testing.T) {
 	require.Equal(t, src, dst)
 	require.Equal(t, h[:], streamHash.Sum(nil))
 }
+
+func TestHashPayload(t *testing.T) {
+	src := []byte("payload re-sent after the failed write")
+	h := sha256.Sum256(src)
+
+	streamHash := sha256.New()
+	payload := hashPayload(bytes.NewReader(src), streamHash)
+
+	seeker, ok := payload.(io.Seeker)
+	require.True(t, ok)
+
+	_, err := payload.Read(make([]byte, 10))
+	require.NoError(t, err)
+
+	_, err = seeker.Seek(5, io.SeekStart)
+	require.Error(t, err)
+
+	_, err = seeker.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	dst, err := io.ReadAll(payload)
+	require.NoError(t, err)
+	require.Equal(t, src, dst)
+	require.Equal(t, h[:], streamHash.Sum(nil))
+
+	_, ok = hashPayload(io.MultiReader(bytes.NewReader(src)), sha256.New()).(io.Seeker)
+	require.False(t, ok)
+}
+
+func TestConsistentListing(t *testing.T) {
+	tc := prepareContext(t)
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningUnversioned},
+	})
+	require.NoError(t, err)
+
+	tc.putObject([]byte("content"))
+	require.Len(t, tc.listObjectsV2(), 1)
+
+	// other gateway instances share NeoFS and tree service but have their own caches
+	newLayer := func(consistentListing bool) Client {
+		return NewLayer(zap.NewExample(), tc.testNeoFS, &Config{
+			Caches:            DefaultCachesConfigs(zap.NewExample()),
+			TreeService:       tc.layer.(*layer).treeService,
+			ConsistentListing: consistentListing,
+		})
+	}
+	otherLayer := newLayer(false)
+	consistentLayer := newLayer(true)
+	// listing result would be cached here if listing consistency weren't required
+	_, err = consistentLayer.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+		ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, MaxKeys: 10},
+	})
+	require.NoError(t, err)
+
+	content := []byte("content")
+	_, err = otherLayer.PutObject(tc.ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  "obj2",
+		Size:    int64(len(content)),
+		Reader:  bytes.NewReader(content),
+		Header:  make(map[string]string),
+	})
+	require.NoError(t, err)
+	require.Len(t, tc.listObjectsV2(), 1)
+
+	res, err := consistentLayer.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+		ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, MaxKeys: 10},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Objects, 2)
+}
+
+// writingTreeService changes objects concurrently with the listing: right after the listing is read from the tree.
+type writingTreeService struct {
+	TreeService
+	write func()
+}
+
+func (w *writingTreeService) GetLatestVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error) {
+	versions, err := w.TreeService.GetLatestVersionsByPrefix(ctx, bktInfo, prefix)
+	if w.write != nil {
+		write := w.write
+		w.write = nil
+		write()
+	}
+	return versions, err
+}
+
+func TestReadBeforeWriteNotCached(t *testing.T) {
+	tc := prepareContext(t)
+	tc.putObject([]byte("content"))
+
+	treeService := &writingTreeService{TreeService: tc.layer.(*layer).treeService}
+	tc.layer.(*layer).treeService = treeService
+	treeService.write = func() {
+		tc.putObject([]byte("new content"))
+		tc.obj = "obj2"
+		tc.putObject([]byte("content"))
+	}
+
+	// listing read before the concurrent write can't be served by the next requests
+	require.Len(t, tc.listObjectsV2(), 1)
+	require.Len(t, tc.listObjectsV2(), 2)
+
+	objInfo, _ := tc.getObject("obj1", "", false)
+	require.Equal(t, int64(len("new content")), objInfo.Size)
+}
+
+func TestETagAlgorithm(t *testing.T) {
+	tc := prepareContext(t)
+	content := []byte("content")
+	sha256Sum, md5Sum := sha256.Sum256(content), md5.Sum(content)
+
+	// another gateway instance without cached objects to check ETags of headed objects
+	otherLayer := NewLayer(zap.NewExample(), tc.testNeoFS, &Config{
+		Caches:      DefaultCachesConfigs(zap.NewExample()),
+		TreeService: tc.layer.(*layer).treeService,
+	})
+
+	for _, algorithm := range []string{"", data.ETagAlgorithmSHA256, data.ETagAlgorithmMD5, data.ETagAlgorithmCID} {
+		t.Run(algorithm, func(t *testing.T) {
+			err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+				BktInfo:  tc.bktInfo,
+				Settings: &data.BucketSettings{Versioning: data.VersioningEnabled, ETagAlgorithm: algorithm},
+			})
+			require.NoError(t, err)
+
+			objInfo := tc.putObject(content)
+
+			expected := hex.EncodeToString(sha256Sum[:])
+			switch algorithm {
+			case data.ETagAlgorithmMD5:
+				expected = hex.EncodeToString(md5Sum[:])
+			case data.ETagAlgorithmCID:
+				expected = objInfo.ID.EncodeToString()
+			}
+			require.Equal(t, expected, objInfo.HashSum)
+
+			headInfo, err := otherLayer.GetObjectInfo(tc.ctx, &HeadObjectParams{
+				BktInfo:   tc.bktInfo,
+				Object:    tc.obj,
+				VersionID: objInfo.VersionID(),
+			})
+			require.NoError(t, err)
+			require.Equal(t, expected, headInfo.HashSum)
+		})
+	}
+}
+
+func TestSyncReplication(t *testing.T) {
+	tc := prepareContext(t)
+
+	replicaCnrID, err := tc.testNeoFS.CreateContainer(tc.ctx, PrmContainerCreate{Name: "replica"})
+	require.NoError(t, err)
+
+	backupNeoFS := NewTestNeoFS()
+	backupCnrID, err := backupNeoFS.CreateContainer(tc.ctx, PrmContainerCreate{Name: "backup"})
+	require.NoError(t, err)
+
+	flakyBackup := NewFaultyNeoFS(NewTestNeoFS())
+	flakyBackup.Inject(Fault{Op: FaultCreateObject, Count: 1, FailAfter: 4, Err: stderrors.New("stream is broken")})
+
+	tc.layer = NewLayer(zap.NewExample(), tc.testNeoFS, &Config{
+		Caches:      DefaultCachesConfigs(zap.NewExample()),
+		AnonKey:     tc.layer.(*layer).anonKey,
+		TreeService: tc.layer.(*layer).treeService,
+		ReplicaNetworks: map[string]NeoFS{
+			"backup": backupNeoFS,
+			"flaky":  flakyBackup,
+		},
+	})
+
+	containerObjects := func(neoFS *TestNeoFS, cnrID cid.ID) [][]byte {
+		var payloads [][]byte
+		for _, obj := range neoFS.Objects() {
+			if objCnrID, _ := obj.ContainerID(); objCnrID.Equals(cnrID) {
+				payloads = append(payloads, obj.Payload())
+			}
+		}
+		return payloads
+	}
+
+	setReplication := func(replication *data.SyncReplication) {
+		err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+			BktInfo:  tc.bktInfo,
+			Settings: &data.BucketSettings{Versioning: data.VersioningUnversioned, SyncReplication: replication},
+		})
+		require.NoError(t, err)
+	}
+
+	content := []byte("content")
+
+	setReplication(&data.SyncReplication{Container: replicaCnrID})
+	tc.putObject(content)
+	require.Equal(t, [][]byte{content}, containerObjects(tc.testNeoFS, replicaCnrID))
+
+	setReplication(&data.SyncReplication{Network: "backup", Container: backupCnrID})
+	tc.putObject(content)
+	require.Equal(t, [][]byte{content}, containerObjects(backupNeoFS, backupCnrID))
+
+	setReplication(&data.SyncReplication{Network: "flaky", Container: backupCnrID})
+	_, err = tc.layer.PutObject(tc.ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  "obj2",
+		Size:    int64(len(content)),
+		Reader:  bytes.NewReader(content),
+		Header:  make(map[string]string),
+	})
+	require.Error(t, err)
+	// object is removed from the bucket container if the replica isn't stored
+	require.Len(t, containerObjects(tc.testNeoFS, tc.bktInfo.CID), 2)
+}
+
+func TestReplicaReadFallback(t *testing.T) {
+	tc := prepareContext(t)
+
+	backupNeoFS := NewTestNeoFS()
+	backupCnrID, err := backupNeoFS.CreateContainer(tc.ctx, PrmContainerCreate{Name: "backup"})
+	require.NoError(t, err)
+
+	// every layer has its own cache, so the object is read from NeoFS
+	newLayer := func() Client {
+		return NewLayer(zap.NewExample(), tc.testNeoFS, &Config{
+			Caches:          DefaultCachesConfigs(zap.NewExample()),
+			AnonKey:         tc.layer.(*layer).anonKey,
+			TreeService:     tc.layer.(*layer).treeService,
+			ReplicaNetworks: map[string]NeoFS{"backup": backupNeoFS},
+		})
+	}
+
+	tc.layer = newLayer()
+	err = tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo: tc.bktInfo,
+		Settings: &data.BucketSettings{
+			Versioning:      data.VersioningUnversioned,
+			SyncReplication: &data.SyncReplication{Network: "backup", Container: backupCnrID},
+		},
+	})
+	require.NoError(t, err)
+
+	content := []byte("content")
+	objInfo := tc.putObject(content)
+
+	// bucket container object is unavailable
+	err = tc.testNeoFS.DeleteObject(tc.ctx, PrmObjectDelete{Container: tc.bktInfo.CID, Object: objInfo.ID})
+	require.NoError(t, err)
+
+	tc.layer = newLayer()
+	readInfo, payload := tc.getObject(tc.obj, "", false)
+	require.Equal(t, content, payload)
+	require.Equal(t, objInfo.ID, readInfo.ID)
+	require.NotNil(t, readInfo.Replica)
+
+	err = backupNeoFS.DeleteObject(tc.ctx, PrmObjectDelete{Container: backupCnrID, Object: readInfo.Replica.OID})
+	require.NoError(t, err)
+
+	tc.layer = newLayer()
+	tc.getObject(tc.obj, "", true)
+}
+
+func TestSystemObjectPrefix(t *testing.T) {
+	tc := prepareContext(t)
+
+	putObject := func(client Client, name string) error {
+		_, err := client.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  name,
+			Reader:  bytes.NewReader(nil),
+			Header:  make(map[string]string),
+		})
+		return err
+	}
+	filePaths := func() map[string]struct{} {
+		res := make(map[string]struct{})
+		for _, obj := range tc.testNeoFS.Objects() {
+			for _, attr := range obj.Attributes() {
+				if attr.Key() == object.AttributeFilePath {
+					res[attr.Value()] = struct{}{}
+				}
+			}
+		}
+		return res
+	}
+
+	corsXML := `<CORSConfiguration><CORSRule><AllowedMethod>GET</AllowedMethod><AllowedOrigin>*</AllowedOrigin></CORSRule></CORSConfiguration>`
+	err := tc.layer.PutBucketCORS(tc.ctx, &PutCORSParams{BktInfo: tc.bktInfo, Reader: strings.NewReader(corsXML)})
+	require.NoError(t, err)
+	require.Contains(t, filePaths(), ".s3-cors")
+
+	require.True(t, errors.IsS3Error(putObject(tc.layer, ".s3-cors"), errors.ErrInvalidObjectName))
+	require.NoError(t, putObject(tc.layer, ".s3-cors-backup"))
+
+	prefixedLayer := NewLayer(zap.NewExample(), tc.testNeoFS, &Config{
+		Caches:             DefaultCachesConfigs(zap.NewExample()),
+		TreeService:        tc.layer.(*layer).treeService,
+		SystemObjectPrefix: ".gw/",
+	})
+
+	// system objects with the previous prefix are found by the tree service
+	cors, err := prefixedLayer.GetBucketCORS(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Len(t, cors.CORSRules, 1)
+
+	require.NoError(t, putObject(prefixedLayer, ".s3-cors"))
+	require.True(t, errors.IsS3Error(putObject(prefixedLayer, ".gw/cors"), errors.ErrInvalidObjectName))
+
+	err = prefixedLayer.PutBucketCORS(tc.ctx, &PutCORSParams{BktInfo: tc.bktInfo, Reader: strings.NewReader(corsXML)})
+	require.NoError(t, err)
+	require.Contains(t, filePaths(), ".gw/cors")
+}
@@ -0,0 +1,561 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	errorsStd "errors"
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"go.uber.org/zap"
+)
+
+type (
+	// PackObjectsResult contains statistics of the bucket packing.
+	PackObjectsResult struct {
+		// Packed is a number of objects moved to new packs.
+		Packed int `json:"packed"`
+		// Created is a number of new pack objects.
+		Created int `json:"created"`
+		// Removed is a number of deleted empty or sparse pack objects.
+		Removed int `json:"removed"`
+	}
+
+	// packIndexEntry describes a pack object in the bucket pack index.
+	packIndexEntry struct {
+		OID     string    `json:"oid"`
+		Size    uint64    `json:"size"`
+		Created time.Time `json:"created"`
+	}
+
+	// packedObjectMeta is saved in the tree node of the packed object instead of its NeoFS object headers.
+	packedObjectMeta struct {
+		Owner       string            `json:"owner"`
+		Created     time.Time         `json:"created"`
+		ContentType string            `json:"contentType,omitempty"`
+		HashSum     string            `json:"hashSum"`
+		Headers     map[string]string `json:"headers,omitempty"`
+	}
+
+	// packEntry is an object added to the pack.
+	packEntry struct {
+		node   *data.NodeVersion
+		meta   string
+		offset uint64
+	}
+
+	// packWriter accumulates payloads of small objects and writes them to NeoFS as a single pack object.
+	packWriter struct {
+		layer   *layer
+		params  *PackObjectsParams
+		result  *PackObjectsResult
+		payload bytes.Buffer
+		entries []*packEntry
+		// written are new pack objects.
+		written []packIndexEntry
+		// failed are old packs with objects which weren't moved to new packs.
+		failed map[oid.ID]struct{}
+	}
+)
+
+// packCompactionRatio is the min ratio of live payload in the pack object, sparser packs are repacked.
+const packCompactionRatio = 0.5
+
+// errPackedObjectChanged is returned if the object was overwritten or removed during packing.
+var errPackedObjectChanged = errorsStd.New("object was changed during packing")
+
+// PackObjects aggregates small objects of the bucket into bigger pack objects.
+// Node of the packed object in the tree service keeps its version and headers, so the packing is transparent
+// for clients. Packs with a low ratio of live payload are repacked, and packs without live objects are deleted.
+func (n *layer) PackObjects(ctx context.Context, p *PackObjectsParams) (*PackObjectsResult, error) {
+	result := &PackObjectsResult{}
+
+	if p.BktInfo.ObjectLockEnabled {
+		// objects can be locked, so they must not be deleted after packing
+		return result, nil
+	}
+
+	nodeVersions, err := n.treeService.GetAllVersionsByPrefix(ctx, p.BktInfo, "")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get versions: %w", err)
+	}
+
+	latestVersions, err := n.treeService.GetLatestVersionsByPrefix(ctx, p.BktInfo, "")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get latest versions: %w", err)
+	}
+
+	// Node is updated by the tree move, which sets a new timestamp of the node, and the latest version
+	// is the one with the max timestamp. So only latest unversioned versions are packed, otherwise
+	// an old version would become the latest one.
+	movable := make(map[uint64]struct{}, len(latestVersions))
+	for _, nodeVersion := range latestVersions {
+		if nodeVersion.IsUnversioned {
+			movable[nodeVersion.ID] = struct{}{}
+		}
+	}
+
+	index, err := n.getPackIndex(ctx, p.BktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		candidates []*data.NodeVersion
+		live       = make(map[oid.ID][]*data.NodeVersion)
+		pinned     = make(map[oid.ID]struct{})
+	)
+	for _, nodeVersion := range nodeVersions {
+		_, isMovable := movable[nodeVersion.ID]
+		switch {
+		case nodeVersion.IsDeleteMarker():
+		case nodeVersion.Pack != nil:
+			live[nodeVersion.Pack.OID] = append(live[nodeVersion.Pack.OID], nodeVersion)
+			if !isMovable {
+				pinned[nodeVersion.Pack.OID] = struct{}{}
+			}
+		case nodeVersion.Archive != nil:
+		case isMovable && nodeVersion.Size > 0 && nodeVersion.Size <= p.MaxObjectSize:
+			candidates = append(candidates, nodeVersion)
+		}
+	}
+
+	w := &packWriter{
+		layer:  n,
+		params: p,
+		result: result,
+		failed: make(map[oid.ID]struct{}),
+	}
+
+	var (
+		newIndex = make([]packIndexEntry, 0, len(index))
+		toRemove = make(map[oid.ID]packIndexEntry)
+	)
+	for _, entry := range index {
+		var packID oid.ID
+		if err = packID.DecodeString(entry.OID); err != nil {
+			n.log.Warn("invalid pack index entry", zap.String("oid", entry.OID), zap.Error(err))
+			continue
+		}
+
+		var liveSize uint64
+		for _, nodeVersion := range live[packID] {
+			liveSize += uint64(nodeVersion.Size)
+		}
+
+		// pack with objects which can't be moved is kept until they are deleted
+		if _, ok := pinned[packID]; ok || liveSize >= uint64(float64(entry.Size)*packCompactionRatio) {
+			newIndex = append(newIndex, entry)
+			continue
+		}
+
+		if liveSize != 0 {
+			if err = w.addPack(ctx, packID, live[packID]); err != nil {
+				n.log.Error("couldn't repack sparse pack", zap.Error(err),
+					zap.String("bucket name", p.BktInfo.Name),
+					zap.String("pack", entry.OID))
+				newIndex = append(newIndex, entry)
+				continue
+			}
+		}
+		toRemove[packID] = entry
+	}
+
+	if len(candidates) >= p.MinObjects {
+		for _, nodeVersion := range candidates {
+			if err = w.addObject(ctx, nodeVersion); err != nil {
+				n.log.Warn("couldn't add object to pack", zap.Error(err),
+					zap.String("bucket name", p.BktInfo.Name),
+					zap.String("object", nodeVersion.FilePath))
+			}
+		}
+	}
+	w.flush(ctx)
+
+	newIndex = append(newIndex, w.written...)
+	for packID, entry := range toRemove {
+		if _, ok := w.failed[packID]; ok {
+			newIndex = append(newIndex, entry)
+			delete(toRemove, packID)
+		}
+	}
+
+	if len(w.written) == 0 && len(toRemove) == 0 {
+		return result, nil
+	}
+
+	if err = n.putPackIndex(ctx, p.BktInfo, newIndex, p.CopiesNumber); err != nil {
+		return nil, err
+	}
+
+	for packID := range toRemove {
+		if err = n.objectDelete(ctx, p.BktInfo, packID); err != nil {
+			n.log.Error("couldn't delete pack object", zap.Error(err),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("pack", packID.EncodeToString()))
+			continue
+		}
+		result.Removed++
+	}
+
+	return result, nil
+}
+
+// addObject adds not packed object to the pack.
+func (w *packWriter) addObject(ctx context.Context, nodeVersion *data.NodeVersion) error {
+	obj, err := w.layer.objectGet(ctx, w.params.BktInfo, nodeVersion.OID)
+	if err != nil {
+		return err
+	}
+
+	objInfo := objectInfoFromMeta(w.params.BktInfo, obj)
+	if FormEncryptionInfo(objInfo.Headers).Enabled || int64(len(obj.Payload())) != nodeVersion.Size {
+		return nil
+	}
+
+	meta, err := json.Marshal(packedObjectMeta{
+		Owner:       objInfo.Owner.EncodeToString(),
+		Created:     objInfo.Created,
+		ContentType: objInfo.ContentType,
+		HashSum:     objInfo.HashSum,
+		Headers:     objInfo.Headers,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal packed object meta: %w", err)
+	}
+
+	w.add(ctx, nodeVersion, string(meta), obj.Payload())
+	return nil
+}
+
+// addPack adds live objects of the sparse pack to the new pack.
+func (w *packWriter) addPack(ctx context.Context, packID oid.ID, nodeVersions []*data.NodeVersion) error {
+	obj, err := w.layer.objectGet(ctx, w.params.BktInfo, packID)
+	if err != nil {
+		return err
+	}
+
+	payload := obj.Payload()
+	for _, nodeVersion := range nodeVersions {
+		end := nodeVersion.Pack.Offset + uint64(nodeVersion.Size)
+		if end > uint64(len(payload)) {
+			return fmt.Errorf("object '%s' is out of pack payload", nodeVersion.FilePath)
+		}
+	}
+
+	for _, nodeVersion := range nodeVersions {
+		w.add(ctx, nodeVersion, nodeVersion.Pack.Meta, payload[nodeVersion.Pack.Offset:nodeVersion.Pack.Offset+uint64(nodeVersion.Size)])
+	}
+
+	return nil
+}
+
+func (w *packWriter) add(ctx context.Context, nodeVersion *data.NodeVersion, meta string, payload []byte) {
+	if w.payload.Len() > 0 && int64(w.payload.Len()+len(payload)) > w.params.MaxPackSize {
+		w.flush(ctx)
+	}
+
+	w.entries = append(w.entries, &packEntry{
+		node:   nodeVersion,
+		meta:   meta,
+		offset: uint64(w.payload.Len()),
+	})
+	w.payload.Write(payload)
+}
+
+// flush writes accumulated payloads as a new pack object and updates nodes of packed objects.
+func (w *packWriter) flush(ctx context.Context) {
+	if len(w.entries) == 0 {
+		return
+	}
+
+	defer func() {
+		w.entries = nil
+		w.payload.Reset()
+	}()
+
+	bktInfo := w.params.BktInfo
+	prm := PrmObjectCreate{
+		Container:    bktInfo.CID,
+		Creator:      bktInfo.Owner,
+		PayloadSize:  uint64(w.payload.Len()),
+		Payload:      bytes.NewReader(w.payload.Bytes()),
+		CreationTime: TimeNow(ctx),
+		CopiesNumber: w.params.CopiesNumber,
+	}
+
+	packID, _, err := w.layer.objectPutAndHash(ctx, prm, bktInfo)
+	if err != nil {
+		w.layer.log.Error("couldn't put pack object", zap.Error(err),
+			zap.String("bucket name", bktInfo.Name))
+		for _, entry := range w.entries {
+			if entry.node.Pack != nil {
+				w.failed[entry.node.Pack.OID] = struct{}{}
+			}
+		}
+		return
+	}
+
+	w.written = append(w.written, packIndexEntry{
+		OID:     packID.EncodeToString(),
+		Size:    uint64(w.payload.Len()),
+		Created: prm.CreationTime,
+	})
+	w.result.Created++
+
+	for _, entry := range w.entries {
+		pack := &data.PackInfo{
+			OID:    packID,
+			Offset: entry.offset,
+			Meta:   entry.meta,
+		}
+
+		oldPack := entry.node.Pack
+		err = w.layer.packVersion(ctx, bktInfo, entry.node, pack)
+		if errorsStd.Is(err, errPackedObjectChanged) {
+			continue
+		}
+		if err != nil {
+			w.layer.log.Error("couldn't update packed object node", zap.Error(err),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("object", entry.node.FilePath))
+			if oldPack != nil {
+				w.failed[oldPack.OID] = struct{}{}
+			}
+			continue
+		}
+		w.result.Packed++
+
+		if oldPack == nil {
+			if err = w.layer.objectDelete(ctx, bktInfo, entry.node.OID); err != nil {
+				w.layer.log.Error("couldn't delete packed object", zap.Error(err),
+					zap.String("bucket name", bktInfo.Name),
+					zap.String("object", entry.node.FilePath),
+					zap.String("objID", entry.node.OID.EncodeToString()))
+			}
+		}
+	}
+}
+
+// packVersion saves location of the object in the pack to the version node.
+// The node is re-read under the version lock, so changes made by the gateway since
+// the object was read for packing are neither lost nor overwritten.
+func (n *layer) packVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion, pack *data.PackInfo) error {
+	unlock := n.versionLocks.lock(bktInfo.CID, nodeVersion.FilePath)
+	defer unlock()
+
+	current, err := n.currentPackedVersion(ctx, bktInfo, nodeVersion)
+	if err != nil {
+		return err
+	}
+
+	packed := *current
+	packed.Pack = pack
+
+	if err = n.treeService.PackVersion(ctx, bktInfo, &packed); err != nil {
+		return err
+	}
+
+	n.cache.DeleteObject(newAddress(bktInfo.CID, nodeVersion.OID))
+	n.cache.DeleteObjectName(bktInfo.CID, bktInfo.Name, nodeVersion.FilePath)
+	n.cache.CleanListCacheEntriesContainingObject(nodeVersion.FilePath, bktInfo.CID)
+
+	return nil
+}
+
+// currentPackedVersion returns the current state of the version node being packed
+// or errPackedObjectChanged if the object was overwritten or removed since it was read.
+func (n *layer) currentPackedVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) (*data.NodeVersion, error) {
+	if !nodeVersion.IsUnversioned {
+		versions, err := n.treeService.GetVersions(ctx, bktInfo, nodeVersion.FilePath)
+		if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, err
+		}
+		for _, version := range versions {
+			if version.ID == nodeVersion.ID && version.OID.Equals(nodeVersion.OID) {
+				return version, nil
+			}
+		}
+		return nil, errPackedObjectChanged
+	}
+
+	// unversioned object can be overwritten since it was read
+	current, err := n.treeService.GetUnversioned(ctx, bktInfo, nodeVersion.FilePath)
+	if errorsStd.Is(err, ErrNodeNotFound) {
+		return nil, errPackedObjectChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !current.OID.Equals(nodeVersion.OID) {
+		return nil, errPackedObjectChanged
+	}
+
+	// new version could be added to the bucket with enabled versioning since it was read
+	latest, err := n.treeService.GetLatestVersion(ctx, bktInfo, nodeVersion.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if latest.ID != current.ID {
+		return nil, errPackedObjectChanged
+	}
+
+	return current, nil
+}
+
+func (n *layer) getPackIndex(ctx context.Context, bktInfo *data.BucketInfo) ([]packIndexEntry, error) {
+	objID, err := n.treeService.GetBucketPackIndexNode(ctx, bktInfo)
+	if errorsStd.Is(err, ErrNodeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := n.objectGet(ctx, bktInfo, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	var index []packIndexEntry
+	if err = json.Unmarshal(obj.Payload(), &index); err != nil {
+		return nil, fmt.Errorf("unmarshal pack index: %w", err)
+	}
+
+	return index, nil
+}
+
+func (n *layer) putPackIndex(ctx context.Context, bktInfo *data.BucketInfo, index []packIndexEntry, copiesNumber uint32) error {
+	indexJSON, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("marshal pack index: %w", err)
+	}
+
+	prm := PrmObjectCreate{
+		Container:    bktInfo.CID,
+		Creator:      bktInfo.Owner,
+		Payload:      bytes.NewReader(indexJSON),
+		Filepath:     n.systemObjectName(bktInfo.PackIndexObjectName()),
+		CreationTime: TimeNow(ctx),
+		CopiesNumber: copiesNumber,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, bktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketPackIndexNode(ctx, bktInfo, objID)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete pack index object", zap.Error(err),
+				zap.String("cnrID", bktInfo.CID.EncodeToString()),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	return nil
+}
+
+// objectInfoFromNode returns info of the object from the version node.
+// Headers of the packed object are stored in the node, headers of the archived object are requested from
+// the archive container, headers of others are requested from NeoFS, which checks access to the object,
+// and are added to the object index.
+// Updated metadata of the object is applied to the headers in both cases.
+func (n *layer) objectInfoFromNode(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) (*data.ObjectInfo, error) {
+	if nodeVersion.Pack != nil {
+		objInfo, err := packedObjectInfo(bktInfo, nodeVersion)
+		if err != nil {
+			return nil, err
+		}
+		objInfo.Replica = nodeVersion.Replica
+		objInfo.ReplicationStatus = nodeVersion.ReplicationStatus
+		return objInfo, applyObjectMetadata(objInfo, nodeVersion)
+	}
+
+	if nodeVersion.Archive != nil {
+		return n.archivedObjectInfo(ctx, bktInfo, nodeVersion)
+	}
+
+	meta, err := n.objectHead(ctx, bktInfo, nodeVersion.OID)
+	if err != nil && nodeVersion.Replica != nil {
+		meta, err = n.replicaHead(ctx, bktInfo, nodeVersion.OID, nodeVersion.Replica, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	objInfo := objectInfoFromMeta(bktInfo, meta)
+	objInfo.ID = nodeVersion.OID
+	objInfo.Name = nodeVersion.FilePath
+	objInfo.Replica = nodeVersion.Replica
+	objInfo.ReplicationStatus = nodeVersion.ReplicationStatus
+	// ETag of the object depends on the bucket settings and may differ from the NeoFS payload checksum
+	if len(nodeVersion.ETag) != 0 {
+		objInfo.HashSum = nodeVersion.ETag
+	}
+	n.indexObject(ctx, bktInfo, objInfo)
+
+	return objInfo, applyObjectMetadata(objInfo, nodeVersion)
+}
+
+func packedObjectInfo(bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) (*data.ObjectInfo, error) {
+	var meta packedObjectMeta
+	if err := json.Unmarshal([]byte(nodeVersion.Pack.Meta), &meta); err != nil {
+		return nil, fmt.Errorf("unmarshal packed object meta: %w", err)
+	}
+
+	var owner user.ID
+	if err := owner.DecodeString(meta.Owner); err != nil {
+		return nil, fmt.Errorf("invalid packed object owner '%s': %w", meta.Owner, err)
+	}
+
+	if meta.Headers == nil {
+		meta.Headers = make(map[string]string)
+	}
+	if len(nodeVersion.ETag) != 0 {
+		meta.HashSum = nodeVersion.ETag
+	}
+
+	// the original object is deleted after packing, so the pack object is addressed instead
+	pack := *nodeVersion.Pack
+	pack.VersionOID = nodeVersion.OID
+
+	return &data.ObjectInfo{
+		ID:          pack.OID,
+		CID:         bktInfo.CID,
+		Bucket:      bktInfo.Name,
+		Name:        nodeVersion.FilePath,
+		Size:        nodeVersion.Size,
+		ContentType: meta.ContentType,
+		Created:     meta.Created,
+		HashSum:     meta.HashSum,
+		Owner:       owner,
+		Headers:     meta.Headers,
+		Pack:        &pack,
+	}, nil
+}
+
+// deleteNodeObject deletes the object of the version node from NeoFS.
+// Payload of the packed object is a part of the pack object, so it's reclaimed by pack compaction.
+// The archived object is deleted from the archive container with its restored copy.
+func (n *layer) deleteNodeObject(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) error {
+	if nodeVersion.Pack != nil {
+		return nil
+	}
+	if nodeVersion.Archive != nil {
+		return n.deleteArchivedVersion(ctx, bktInfo, nodeVersion)
+	}
+
+	return n.objectDelete(ctx, bktInfo, nodeVersion.OID)
+}
@@ -0,0 +1,193 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackObjects(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: settings,
+	})
+	require.NoError(t, err)
+
+	names := []string{"obj1", "obj2", "dir/obj3"}
+	contents := make(map[string][]byte, len(names))
+	ids := make(map[string]oid.ID, len(names))
+	for _, name := range names {
+		tc.obj = name
+		contents[name] = []byte("content of " + name)
+		ids[name] = tc.putObject(contents[name]).ID
+	}
+
+	tc.obj = "big"
+	bigObjInfo := tc.putObject(bytes.Repeat([]byte("a"), 200))
+
+	prm := &PackObjectsParams{
+		BktInfo:       tc.bktInfo,
+		MaxObjectSize: 100,
+		MaxPackSize:   1 << 20,
+		MinObjects:    2,
+	}
+
+	result, err := tc.layer.PackObjects(tc.ctx, prm)
+	require.NoError(t, err)
+	require.Equal(t, &PackObjectsResult{Packed: 3, Created: 1}, result)
+
+	var packID oid.ID
+	for _, name := range names {
+		objInfo, payload := tc.getObject(name, "", false)
+		require.Equal(t, contents[name], payload)
+		require.Equal(t, ids[name], objInfo.VersionOID())
+		require.Equal(t, objInfo.Pack.OID, objInfo.ID)
+		require.Equal(t, int64(len(contents[name])), objInfo.Size)
+		require.NotNil(t, objInfo.Pack)
+		require.Nil(t, tc.getObjectByID(ids[name]))
+		packID = objInfo.Pack.OID
+	}
+	require.NotNil(t, tc.getObjectByID(packID))
+	require.NotNil(t, tc.getObjectByID(bigObjInfo.ID))
+	tc.checkListObjects(ids["obj1"], ids["obj2"], ids["dir/obj3"], bigObjInfo.ID)
+
+	objInfo, _ := tc.getObject("obj2", "", false)
+	rangePayload := bytes.NewBuffer(nil)
+	err = tc.layer.GetObject(tc.ctx, &GetObjectParams{
+		ObjectInfo: objInfo,
+		Writer:     rangePayload,
+		BucketInfo: tc.bktInfo,
+		Range:      &RangeParams{Start: 2, End: 5},
+	})
+	require.NoError(t, err)
+	require.Equal(t, contents["obj2"][2:6], rangePayload.Bytes())
+
+	// sparse pack is repacked
+	tc.deleteObject("obj1", "", settings)
+	tc.deleteObject("dir/obj3", "", settings)
+
+	result, err = tc.layer.PackObjects(tc.ctx, prm)
+	require.NoError(t, err)
+	require.Equal(t, &PackObjectsResult{Packed: 1, Created: 1, Removed: 1}, result)
+	require.Nil(t, tc.getObjectByID(packID))
+
+	objInfo, payload := tc.getObject("obj2", "", false)
+	require.Equal(t, contents["obj2"], payload)
+	require.NotEqual(t, packID, objInfo.Pack.OID)
+	packID = objInfo.Pack.OID
+
+	// empty pack is removed
+	tc.deleteObject("obj2", "", settings)
+
+	result, err = tc.layer.PackObjects(tc.ctx, prm)
+	require.NoError(t, err)
+	require.Equal(t, &PackObjectsResult{Removed: 1}, result)
+	require.Nil(t, tc.getObjectByID(packID))
+	tc.checkListObjects(bigObjInfo.ID)
+}
+
+func TestPackObjectsNotEnoughObjects(t *testing.T) {
+	tc := prepareContext(t)
+
+	objInfo := tc.putObject([]byte("content"))
+
+	result, err := tc.layer.PackObjects(tc.ctx, &PackObjectsParams{
+		BktInfo:       tc.bktInfo,
+		MaxObjectSize: 100,
+		MaxPackSize:   1 << 20,
+		MinObjects:    2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &PackObjectsResult{}, result)
+
+	resInfo, _ := tc.getObject(tc.obj, "", false)
+	require.Nil(t, resInfo.Pack)
+	require.NotNil(t, tc.getObjectByID(objInfo.ID))
+}
+
+func TestPackObjectsVersionedBucket(t *testing.T) {
+	tc := prepareContext(t)
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningUnversioned},
+	})
+	require.NoError(t, err)
+
+	tc.obj = "obj1"
+	unversionedInfo := tc.putObject([]byte("unversioned content"))
+	tc.obj = "obj2"
+	tc.putObject([]byte("content of obj2"))
+
+	err = tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	tc.obj = "obj1"
+	latestContent := []byte("latest content")
+	latestInfo := tc.putObject(latestContent)
+
+	result, err := tc.layer.PackObjects(tc.ctx, &PackObjectsParams{
+		BktInfo:       tc.bktInfo,
+		MaxObjectSize: 100,
+		MaxPackSize:   1 << 20,
+		MinObjects:    1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &PackObjectsResult{Packed: 1, Created: 1}, result)
+
+	// the old unversioned version isn't packed, so it doesn't become the latest one
+	objInfo, payload := tc.getObject("obj1", "", false)
+	require.Equal(t, latestContent, payload)
+	require.Equal(t, latestInfo.ID, objInfo.ID)
+	require.NotNil(t, tc.getObjectByID(unversionedInfo.ID))
+
+	objInfo, payload = tc.getObject("obj2", "", false)
+	require.Equal(t, []byte("content of obj2"), payload)
+	require.NotNil(t, objInfo.Pack)
+}
+
+func TestPackVersionChangedObject(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: settings,
+	})
+	require.NoError(t, err)
+	n := tc.layer.(*layer)
+
+	objInfo := tc.putObject([]byte("content"))
+	nodeVersion, err := n.treeService.GetUnversioned(tc.ctx, tc.bktInfo, tc.obj)
+	require.NoError(t, err)
+
+	// metadata updated after the object was read for packing is kept
+	_, err = tc.layer.UpdateObjectMetadata(tc.ctx, &UpdateObjectMetadataParams{
+		BktInfo:  tc.bktInfo,
+		Settings: settings,
+		Object:   tc.obj,
+		Header:   map[string]string{"key": "value"},
+	})
+	require.NoError(t, err)
+
+	pack := &data.PackInfo{OID: oidtest.ID()}
+	require.NoError(t, n.packVersion(tc.ctx, tc.bktInfo, nodeVersion, pack))
+
+	current, err := n.treeService.GetUnversioned(tc.ctx, tc.bktInfo, tc.obj)
+	require.NoError(t, err)
+	require.Equal(t, objInfo.ID, current.OID)
+	require.Equal(t, pack, current.Pack)
+	require.Contains(t, current.Metadata, "value")
+
+	// removed object isn't restored by packing
+	tc.deleteObject(tc.obj, "", settings)
+	err = n.packVersion(tc.ctx, tc.bktInfo, nodeVersion, pack)
+	require.ErrorIs(t, err, errPackedObjectChanged)
+}
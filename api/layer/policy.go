@@ -0,0 +1,130 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	errorsStd "errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/policy"
+	"go.uber.org/zap"
+)
+
+// PutBucketPolicyParams stores PutBucketPolicy request parameters.
+type PutBucketPolicyParams struct {
+	BktInfo      *data.BucketInfo
+	Policy       *policy.Policy
+	CopiesNumber uint32
+}
+
+// PutBucketPolicy saves the policy document as a bucket system object.
+func (n *layer) PutBucketPolicy(ctx context.Context, p *PutBucketPolicyParams) error {
+	policyJSON, err := json.Marshal(p.Policy)
+	if err != nil {
+		return fmt.Errorf("marshal bucket policy: %w", err)
+	}
+
+	prevValue := n.storedBucketPolicy(ctx, p.BktInfo)
+
+	prm := PrmObjectCreate{
+		Container:    p.BktInfo.CID,
+		Creator:      p.BktInfo.Owner,
+		Payload:      bytes.NewReader(policyJSON),
+		Filepath:     n.systemObjectName(p.BktInfo.PolicyObjectName()),
+		CreationTime: TimeNow(ctx),
+		CopiesNumber: p.CopiesNumber,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketPolicy(ctx, p.BktInfo, objID, policyJSON)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, p.BktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete bucket policy object", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	n.cache.PutBucketPolicy(n.Owner(ctx), p.BktInfo, p.Policy)
+	return n.saveBucketConfigUpdate(ctx, p.BktInfo, ConfigTypePolicy, prevValue, policyJSON, p.CopiesNumber)
+}
+
+// GetBucketPolicy returns the policy document of the bucket. The document is read
+// from the tree copy, so it's available for any requester. Absence of the policy
+// is cached too, because the policy is checked for every request to the bucket.
+func (n *layer) GetBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) (*policy.Policy, error) {
+	owner := n.Owner(ctx)
+	bktPolicy := n.cache.GetBucketPolicy(owner, bktInfo)
+	if bktPolicy == nil {
+		document, err := n.treeService.GetBucketPolicy(ctx, bktInfo)
+		if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, err
+		}
+
+		// empty policy means that the bucket has no policy
+		bktPolicy = &policy.Policy{}
+		if err == nil {
+			if err = json.Unmarshal(document, bktPolicy); err != nil {
+				return nil, fmt.Errorf("unmarshal bucket policy: %w", err)
+			}
+		}
+
+		n.cache.PutBucketPolicy(owner, bktInfo, bktPolicy)
+	}
+
+	if len(bktPolicy.Statement) == 0 {
+		return nil, errors.GetAPIError(errors.ErrNoSuchBucketPolicy)
+	}
+
+	return bktPolicy, nil
+}
+
+// DeleteBucketPolicy removes the policy document of the bucket.
+func (n *layer) DeleteBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) error {
+	prevValue := n.storedBucketPolicy(ctx, bktInfo)
+
+	objID, err := n.treeService.DeleteBucketPolicy(ctx, bktInfo)
+	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDNotFound {
+		return err
+	}
+	if !objIDNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objID); err != nil {
+			return err
+		}
+	}
+
+	n.cache.DeleteBucketPolicy(bktInfo)
+
+	if !objIDNotFound {
+		return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypePolicy, prevValue, 0)
+	}
+
+	return nil
+}
+
+// storedBucketPolicy returns current bucket policy document to save it in the bucket history.
+func (n *layer) storedBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) []byte {
+	document, err := n.treeService.GetBucketPolicy(ctx, bktInfo)
+	if err != nil {
+		if !errorsStd.Is(err, ErrNodeNotFound) {
+			n.log.Warn("couldn't get previous bucket policy", zap.Error(err))
+		}
+		return nil
+	}
+
+	return document
+}
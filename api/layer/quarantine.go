@@ -0,0 +1,48 @@
+package layer
+
+import (
+	"context"
+	errorsStd "errors"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// ErrQuarantined is the reason of rejection of reading payloads of objects quarantined by malware scanning.
+var ErrQuarantined = errorsStd.New("object is quarantined by malware scanning")
+
+// Quarantined checks whether the object with the tags is quarantined by malware scanning.
+func (n *layer) Quarantined(tags map[string]string) bool {
+	if n.quarantineTagKey == "" {
+		return false
+	}
+	value, ok := tags[n.quarantineTagKey]
+	return ok && value == n.quarantineTagValue
+}
+
+// CheckQuarantine returns AccessDenied error if the object version is quarantined, so its payload can't be read.
+func (n *layer) CheckQuarantine(ctx context.Context, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) error {
+	if n.quarantineTagKey == "" {
+		return nil
+	}
+
+	_, tags, err := n.GetObjectTagging(ctx, &GetObjectTaggingParams{
+		ObjectVersion: &ObjectVersion{
+			BktInfo:    bktInfo,
+			ObjectName: objInfo.Name,
+			VersionID:  objInfo.VersionID(),
+		},
+	})
+	if err != nil {
+		if errors.IsS3Error(err, errors.ErrNoSuchKey) || errors.IsS3Error(err, errors.ErrNoSuchVersion) || errorsStd.Is(err, ErrNodeNotFound) {
+			// objects without tree nodes, e.g. objects in the trash, can't be tagged
+			return nil
+		}
+		return err
+	}
+
+	if n.Quarantined(tags) {
+		return errors.GetAPIErrorWithError(errors.ErrAccessDenied, ErrQuarantined)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+type (
+	// replicaWriter stores the copy of the object payload to the secondary container
+	// while the payload is read to be stored in the bucket container.
+	replicaWriter struct {
+		cfg  *data.SyncReplication
+		pw   *io.PipeWriter
+		done chan replicaResult
+	}
+
+	replicaResult struct {
+		id  oid.ID
+		err error
+	}
+)
+
+func (n *layer) replicaNeoFS(network string) (NeoFS, bool) {
+	if network == "" {
+		return n.neoFS, true
+	}
+	neoFS, ok := n.replicaNetworks[network]
+	return neoFS, ok
+}
+
+// startReplication makes payload of the object be copied to the secondary container while
+// it's stored in the bucket container. It returns nil if replication is disabled for the bucket.
+func (n *layer) startReplication(ctx context.Context, prm *PrmObjectCreate, bktInfo *data.BucketInfo, cfg *data.SyncReplication) (*replicaWriter, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	neoFS, ok := n.replicaNeoFS(cfg.Network)
+	if !ok {
+		return nil, fmt.Errorf("unknown replication network '%s'", cfg.Network)
+	}
+
+	if prm.Payload == nil {
+		prm.Payload = bytes.NewReader(nil)
+	}
+
+	pr, pw := io.Pipe()
+
+	replicaPrm := *prm
+	replicaPrm.Container = cfg.Container
+	replicaPrm.Payload = pr
+	n.prepareAuthParameters(ctx, &replicaPrm.PrmAuth, bktInfo.Owner)
+
+	prm.Payload = io.TeeReader(prm.Payload, pw)
+
+	w := &replicaWriter{
+		cfg:  cfg,
+		pw:   pw,
+		done: make(chan replicaResult, 1),
+	}
+
+	go func() {
+		id, err := neoFS.CreateObject(ctx, replicaPrm)
+		if err != nil {
+			// unblock the writer if the replica fails before the payload is read
+			_ = pr.CloseWithError(err)
+		} else {
+			_ = pr.Close()
+		}
+		w.done <- replicaResult{id: id, err: err}
+	}()
+
+	return w, nil
+}
+
+// finishReplication waits for the replica to be stored and returns its location. If the object
+// isn't stored in the bucket container, the replica is aborted. If the replica isn't stored,
+// the bucket container object is deleted, so the write fails completely.
+func (n *layer) finishReplication(ctx context.Context, w *replicaWriter, bktInfo *data.BucketInfo, id oid.ID, putErr error) (*data.ReplicaInfo, error) {
+	if putErr != nil {
+		_ = w.pw.CloseWithError(putErr)
+	} else {
+		_ = w.pw.Close()
+	}
+
+	res := <-w.done
+	if putErr != nil {
+		if res.err == nil {
+			n.deleteReplica(ctx, w.cfg, bktInfo, res.id)
+		}
+		return nil, putErr
+	}
+
+	if res.err != nil {
+		if err := n.objectDelete(ctx, bktInfo, id); err != nil {
+			n.log.Error("couldn't delete object which failed to be replicated", zap.Error(err),
+				zap.String("bucket", bktInfo.Name), zap.Stringer("cid", bktInfo.CID), zap.Stringer("oid", id))
+		}
+		return nil, fmt.Errorf("couldn't store replica in container '%s': %w", w.cfg.Container, res.err)
+	}
+
+	n.log.Debug("object is replicated",
+		zap.String("bucket", bktInfo.Name), zap.Stringer("oid", id),
+		zap.String("network", w.cfg.Network), zap.Stringer("replica cid", w.cfg.Container), zap.Stringer("replica oid", res.id))
+
+	return &data.ReplicaInfo{
+		Network:   w.cfg.Network,
+		Container: w.cfg.Container,
+		OID:       res.id,
+	}, nil
+}
+
+func (n *layer) deleteReplica(ctx context.Context, cfg *data.SyncReplication, bktInfo *data.BucketInfo, id oid.ID) {
+	neoFS, _ := n.replicaNeoFS(cfg.Network)
+
+	prm := PrmObjectDelete{
+		Container: cfg.Container,
+		Object:    id,
+	}
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+
+	if err := neoFS.DeleteObject(ctx, prm); err != nil {
+		n.log.Error("couldn't delete replica", zap.Error(err),
+			zap.String("network", cfg.Network), zap.Stringer("cid", cfg.Container), zap.Stringer("oid", id))
+	}
+}
+
+// readReplica reads the replica of the object which can't be read from the bucket container.
+// The error of the bucket container read is returned if the object isn't replicated or the replica
+// can't be read too. Access violation isn't a storage failure, so the replica isn't read in this case.
+func (n *layer) readReplica(ctx context.Context, bktInfo *data.BucketInfo, replica *data.ReplicaInfo, prm PrmObjectRead, readErr error) (*ObjectPart, error) {
+	if replica == nil || errors.Is(readErr, ErrAccessDenied) {
+		return nil, readErr
+	}
+
+	neoFS, ok := n.replicaNeoFS(replica.Network)
+	if !ok {
+		n.log.Warn("couldn't read replica of unknown network", zap.Error(readErr),
+			zap.String("bucket", bktInfo.Name), zap.Stringer("oid", prm.Object), zap.String("network", replica.Network))
+		return nil, readErr
+	}
+
+	prm.Container = replica.Container
+	prm.Object = replica.OID
+
+	res, err := neoFS.ReadObject(ctx, prm)
+	metrics.ReplicaReadFallback(replica.Network, err == nil)
+	if err != nil {
+		n.log.Error("couldn't read replica", zap.Error(err), zap.NamedError("primary error", readErr),
+			zap.String("bucket", bktInfo.Name), zap.String("network", replica.Network),
+			zap.Stringer("replica cid", replica.Container), zap.Stringer("replica oid", replica.OID))
+		return nil, readErr
+	}
+
+	n.log.Warn("object is read from replica", zap.Error(readErr),
+		zap.String("bucket", bktInfo.Name), zap.Stringer("cid", bktInfo.CID), zap.String("network", replica.Network),
+		zap.Stringer("replica cid", replica.Container), zap.Stringer("replica oid", replica.OID))
+
+	return res, nil
+}
+
+// replicaHead returns the header of the object replica. The header keeps the id of the replica,
+// so the id of the bucket container object must be used as the object version id.
+func (n *layer) replicaHead(ctx context.Context, bktInfo *data.BucketInfo, idObj oid.ID, replica *data.ReplicaInfo, readErr error) (*object.Object, error) {
+	prm := PrmObjectRead{
+		Container:  bktInfo.CID,
+		Object:     idObj,
+		WithHeader: true,
+	}
+
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+
+	res, err := n.readReplica(ctx, bktInfo, replica, prm, readErr)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Head, nil
+}
+
+// replicaPayloadReader initializes payload reader of the object replica. The replica is a copy
+// of the original object, so the offset in the pack object isn't applied to packed objects.
+func (n *layer) replicaPayloadReader(ctx context.Context, p getParams, objInfo *data.ObjectInfo, readErr error) (io.Reader, error) {
+	prm := PrmObjectRead{
+		Container:    p.bktInfo.CID,
+		Object:       objInfo.VersionOID(),
+		WithPayload:  true,
+		PayloadRange: [2]uint64{p.off, p.ln},
+	}
+	if objInfo.Pack != nil {
+		prm.PayloadRange[0] -= objInfo.Pack.Offset
+	}
+
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, p.bktInfo.Owner)
+
+	res, err := n.readReplica(ctx, p.bktInfo, objInfo.Replica, prm, readErr)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Payload, nil
+}
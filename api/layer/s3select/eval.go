@@ -0,0 +1,531 @@
+package s3select
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// Values of expressions are nil (NULL or MISSING), bool, float64, string,
+// *jsonObject and []interface{} for nested JSON values.
+type (
+	expr interface {
+		eval(rec record) (interface{}, error)
+	}
+
+	literalExpr struct {
+		value interface{}
+	}
+
+	pathElem struct {
+		name   string
+		quoted bool
+		// index is an index of array element, it's negative for object keys.
+		index int
+	}
+
+	columnExpr struct {
+		path []pathElem
+	}
+
+	logicalExpr struct {
+		op          string
+		left, right expr
+	}
+
+	notExpr struct {
+		operand expr
+	}
+
+	compareExpr struct {
+		op          string
+		left, right expr
+	}
+
+	arithmeticExpr struct {
+		op          string
+		left, right expr
+	}
+
+	isNullExpr struct {
+		operand expr
+		negate  bool
+	}
+
+	likeExpr struct {
+		operand, pattern expr
+	}
+
+	inExpr struct {
+		operand expr
+		list    []expr
+	}
+
+	castExpr struct {
+		operand  expr
+		typeName string
+	}
+
+	funcExpr struct {
+		name string
+		f    func(args []interface{}) (interface{}, error)
+		args []expr
+	}
+
+	function struct {
+		minArgs, maxArgs int
+		call             func(args []interface{}) (interface{}, error)
+	}
+
+	aggregateFunc struct {
+		name  string
+		arg   expr
+		count int64
+		sum   float64
+		value interface{}
+	}
+)
+
+var castTypes = map[string]struct{}{
+	"INT": {}, "INTEGER": {}, "FLOAT": {}, "DECIMAL": {}, "NUMERIC": {}, "STRING": {}, "BOOL": {}, "BOOLEAN": {},
+}
+
+var functions = map[string]function{
+	"LOWER":            {1, 1, stringFunc(strings.ToLower)},
+	"UPPER":            {1, 1, stringFunc(strings.ToUpper)},
+	"TRIM":             {1, 1, stringFunc(strings.TrimSpace)},
+	"CHAR_LENGTH":      {1, 1, charLength},
+	"CHARACTER_LENGTH": {1, 1, charLength},
+	"SUBSTRING":        {2, 3, substring},
+	"COALESCE": {1, -1, func(args []interface{}) (interface{}, error) {
+		for _, arg := range args {
+			if arg != nil {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	}},
+	"NULLIF": {2, 2, func(args []interface{}) (interface{}, error) {
+		if equal, ok := compareValues(args[0], args[1]); ok && equal == 0 {
+			return nil, nil
+		}
+		return args[0], nil
+	}},
+}
+
+func stringFunc(f func(string) string) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		return f(toString(args[0])), nil
+	}
+}
+
+func charLength(args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	return float64(len([]rune(toString(args[0])))), nil
+}
+
+// substring implements SUBSTRING(string, start[, length]), start is 1-based.
+func substring(args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	runes := []rune(toString(args[0]))
+
+	start, ok := toNumber(args[1])
+	if !ok {
+		return nil, errors.GetAPIError(errors.ErrIncorrectSQLFunctionArgumentType)
+	}
+	end := float64(len(runes)) + 1
+	if len(args) == 3 {
+		length, ok := toNumber(args[2])
+		if !ok || length < 0 {
+			return nil, errors.GetAPIError(errors.ErrIncorrectSQLFunctionArgumentType)
+		}
+		end = math.Min(end, start+length)
+	}
+	start = math.Max(start, 1)
+	if start >= end {
+		return "", nil
+	}
+
+	return string(runes[int(start)-1 : int(end)-1]), nil
+}
+
+func isAggregate(name string) bool {
+	switch name {
+	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+		return true
+	}
+	return false
+}
+
+func (e literalExpr) eval(record) (interface{}, error) {
+	return e.value, nil
+}
+
+func (e *columnExpr) eval(rec record) (interface{}, error) {
+	return rec.get(e.path), nil
+}
+
+// name returns the name of the column in the output record, it's empty for array elements.
+func (e *columnExpr) name() string {
+	if last := e.path[len(e.path)-1]; last.index < 0 {
+		return last.name
+	}
+	return ""
+}
+
+func (e *logicalExpr) eval(rec record) (interface{}, error) {
+	left, err := evalBool(e.left, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	// three-valued logic: false AND x is false, true OR x is true
+	if e.op == "AND" && left != nil && !*left || e.op == "OR" && left != nil && *left {
+		return *left, nil
+	}
+
+	right, err := evalBool(e.right, rec)
+	if err != nil {
+		return nil, err
+	}
+	if right != nil && (e.op == "AND" && !*right || e.op == "OR" && *right) {
+		return *right, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	return *right, nil
+}
+
+func (e *notExpr) eval(rec record) (interface{}, error) {
+	operand, err := evalBool(e.operand, rec)
+	if err != nil || operand == nil {
+		return nil, err
+	}
+	return !*operand, nil
+}
+
+func evalBool(e expr, rec record) (*bool, error) {
+	value, err := e.eval(rec)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return &v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return &b, nil
+		}
+	}
+
+	return nil, errors.GetAPIErrorWithError(errors.ErrIncorrectSQLFunctionArgumentType, fmt.Errorf("'%v' is not a boolean", value))
+}
+
+func (e *compareExpr) eval(rec record) (interface{}, error) {
+	left, err := e.left.eval(rec)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	cmp, ok := compareValues(left, right)
+	if !ok {
+		return nil, nil
+	}
+
+	switch e.op {
+	case "=":
+		return cmp == 0, nil
+	case "!=", "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case ">":
+		return cmp > 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// compareValues compares values as numbers if one of them is a number and another one is convertible
+// to the number, otherwise values are compared as strings. Comparison with NULL is unknown.
+func compareValues(left, right interface{}) (int, bool) {
+	if left == nil || right == nil {
+		return 0, false
+	}
+
+	_, leftNumber := left.(float64)
+	_, rightNumber := right.(float64)
+	if leftNumber || rightNumber {
+		l, lok := toNumber(left)
+		r, rok := toNumber(right)
+		if lok && rok {
+			switch {
+			case l < r:
+				return -1, true
+			case l > r:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	return strings.Compare(toString(left), toString(right)), true
+}
+
+func (e *arithmeticExpr) eval(rec record) (interface{}, error) {
+	left, err := e.left.eval(rec)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(rec)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		return nil, errors.GetAPIErrorWithError(errors.ErrEvaluatorInvalidArguments, fmt.Errorf("non-numeric operands of '%s'", e.op))
+	}
+
+	switch e.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, errors.GetAPIErrorWithError(errors.ErrEvaluatorInvalidArguments, fmt.Errorf("division by zero"))
+		}
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, errors.GetAPIErrorWithError(errors.ErrEvaluatorInvalidArguments, fmt.Errorf("division by zero"))
+		}
+		return math.Mod(l, r), nil
+	}
+}
+
+func (e *isNullExpr) eval(rec record) (interface{}, error) {
+	value, err := e.operand.eval(rec)
+	if err != nil {
+		return nil, err
+	}
+	return (value == nil) != e.negate, nil
+}
+
+func (e *likeExpr) eval(rec record) (interface{}, error) {
+	value, err := e.operand.eval(rec)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := e.pattern.eval(rec)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil || pattern == nil {
+		return nil, nil
+	}
+
+	return matchLike([]rune(toString(value)), []rune(toString(pattern))), nil
+}
+
+// matchLike matches the value against LIKE pattern, '%' matches any sequence of characters
+// and '_' matches any single character.
+func matchLike(value, pattern []rune) bool {
+	var (
+		vi, pi       int
+		starP, starV = -1, 0
+	)
+
+	for vi < len(value) {
+		switch {
+		case pi < len(pattern) && (pattern[pi] == '_' || pattern[pi] == value[vi]):
+			vi++
+			pi++
+		case pi < len(pattern) && pattern[pi] == '%':
+			starP, starV = pi, vi
+			pi++
+		case starP >= 0:
+			starV++
+			vi, pi = starV, starP+1
+		default:
+			return false
+		}
+	}
+
+	for pi < len(pattern) && pattern[pi] == '%' {
+		pi++
+	}
+
+	return pi == len(pattern)
+}
+
+func (e *inExpr) eval(rec record) (interface{}, error) {
+	value, err := e.operand.eval(rec)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	for _, item := range e.list {
+		itemValue, err := item.eval(rec)
+		if err != nil {
+			return nil, err
+		}
+		if cmp, ok := compareValues(value, itemValue); ok && cmp == 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (e *castExpr) eval(rec record) (interface{}, error) {
+	value, err := e.operand.eval(rec)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	switch e.typeName {
+	case "STRING":
+		return toString(value), nil
+	case "BOOL", "BOOLEAN":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(toString(value)))
+		if err != nil {
+			return nil, errors.GetAPIErrorWithError(errors.ErrCastFailed, err)
+		}
+		return b, nil
+	default:
+		number, ok := toNumber(value)
+		if !ok {
+			return nil, errors.GetAPIErrorWithError(errors.ErrCastFailed, fmt.Errorf("'%v' is not a number", value))
+		}
+		if e.typeName == "INT" || e.typeName == "INTEGER" {
+			number = math.Trunc(number)
+		}
+		return number, nil
+	}
+}
+
+func (e *funcExpr) eval(rec record) (interface{}, error) {
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		value, err := arg.eval(rec)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	return e.f(args)
+}
+
+// update accumulates the value of the record.
+func (a *aggregateFunc) update(rec record) error {
+	if a.arg == nil { // COUNT(*)
+		a.count++
+		return nil
+	}
+
+	value, err := a.arg.eval(rec)
+	if err != nil || value == nil {
+		return err
+	}
+
+	switch a.name {
+	case "COUNT":
+		a.count++
+	case "SUM", "AVG":
+		number, ok := toNumber(value)
+		if !ok {
+			return errors.GetAPIErrorWithError(errors.ErrIncorrectSQLFunctionArgumentType, fmt.Errorf("'%v' is not a number", value))
+		}
+		a.count++
+		a.sum += number
+	case "MIN", "MAX":
+		if number, ok := toNumber(value); ok {
+			value = number
+		}
+		if a.value == nil {
+			a.value = value
+		} else if cmp, ok := compareValues(value, a.value); ok && (a.name == "MIN" && cmp < 0 || a.name == "MAX" && cmp > 0) {
+			a.value = value
+		}
+	}
+
+	return nil
+}
+
+func (a *aggregateFunc) result() interface{} {
+	switch a.name {
+	case "COUNT":
+		return float64(a.count)
+	case "SUM":
+		if a.count == 0 {
+			return nil
+		}
+		return a.sum
+	case "AVG":
+		if a.count == 0 {
+			return nil
+		}
+		return a.sum / float64(a.count)
+	default:
+		return a.value
+	}
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return string(encodeJSONValue(nil, v))
+	}
+}
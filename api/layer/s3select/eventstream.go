@@ -0,0 +1,147 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"net/http"
+)
+
+const (
+	headerEventType    = ":event-type"
+	headerContentType  = ":content-type"
+	headerMessageType  = ":message-type"
+	headerErrorCode    = ":error-code"
+	headerErrorMessage = ":error-message"
+
+	// headerValueString is a type of string header value in the event stream encoding.
+	headerValueString = 7
+
+	messageTypeEvent = "event"
+	messageTypeError = "error"
+)
+
+type (
+	// Stats is a payload of Stats and Progress events.
+	Stats struct {
+		BytesScanned   int64 `xml:"BytesScanned"`
+		BytesProcessed int64 `xml:"BytesProcessed"`
+		BytesReturned  int64 `xml:"BytesReturned"`
+	}
+
+	statsMessage struct {
+		XMLName xml.Name `xml:"Stats"`
+		Stats
+	}
+
+	progressMessage struct {
+		XMLName xml.Name `xml:"Progress"`
+		Stats
+	}
+
+	// EventWriter writes messages of SelectObjectContent response in the event stream encoding.
+	EventWriter struct {
+		w   io.Writer
+		buf bytes.Buffer
+	}
+
+	header struct {
+		name, value string
+	}
+)
+
+// NewEventWriter creates a new event stream writer. Every message is flushed
+// if w implements http.Flusher.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{w: w}
+}
+
+// WriteRecords writes Records event with the chunk of selected records.
+func (e *EventWriter) WriteRecords(payload []byte) error {
+	return e.writeMessage([]header{
+		{headerEventType, "Records"},
+		{headerContentType, "application/octet-stream"},
+		{headerMessageType, messageTypeEvent},
+	}, payload)
+}
+
+// WriteContinuation writes Cont event to keep the connection alive.
+func (e *EventWriter) WriteContinuation() error {
+	return e.writeMessage([]header{
+		{headerEventType, "Cont"},
+		{headerMessageType, messageTypeEvent},
+	}, nil)
+}
+
+// WriteProgress writes Progress event.
+func (e *EventWriter) WriteProgress(stats Stats) error {
+	return e.writeXMLEvent("Progress", progressMessage{Stats: stats})
+}
+
+// WriteStats writes Stats event.
+func (e *EventWriter) WriteStats(stats Stats) error {
+	return e.writeXMLEvent("Stats", statsMessage{Stats: stats})
+}
+
+// WriteEnd writes End event which means that the request is completed successfully.
+func (e *EventWriter) WriteEnd() error {
+	return e.writeMessage([]header{
+		{headerEventType, "End"},
+		{headerMessageType, messageTypeEvent},
+	}, nil)
+}
+
+// WriteError writes error message, no more messages are expected after it.
+func (e *EventWriter) WriteError(code, message string) error {
+	return e.writeMessage([]header{
+		{headerErrorCode, code},
+		{headerErrorMessage, message},
+		{headerMessageType, messageTypeError},
+	}, nil)
+}
+
+func (e *EventWriter) writeXMLEvent(eventType string, v interface{}) error {
+	payload, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return e.writeMessage([]header{
+		{headerEventType, eventType},
+		{headerContentType, "text/xml"},
+		{headerMessageType, messageTypeEvent},
+	}, payload)
+}
+
+// writeMessage encodes the message: prelude with total and headers length and its CRC,
+// headers, payload and CRC of the whole message.
+func (e *EventWriter) writeMessage(headers []header, payload []byte) error {
+	var headersBuf bytes.Buffer
+	for _, h := range headers {
+		headersBuf.WriteByte(byte(len(h.name)))
+		headersBuf.WriteString(h.name)
+		headersBuf.WriteByte(headerValueString)
+		_ = binary.Write(&headersBuf, binary.BigEndian, uint16(len(h.value)))
+		headersBuf.WriteString(h.value)
+	}
+
+	e.buf.Reset()
+	totalLen := uint32(4 + 4 + 4 + headersBuf.Len() + len(payload) + 4)
+	_ = binary.Write(&e.buf, binary.BigEndian, totalLen)
+	_ = binary.Write(&e.buf, binary.BigEndian, uint32(headersBuf.Len()))
+	_ = binary.Write(&e.buf, binary.BigEndian, crc32.ChecksumIEEE(e.buf.Bytes()))
+	e.buf.Write(headersBuf.Bytes())
+	e.buf.Write(payload)
+	_ = binary.Write(&e.buf, binary.BigEndian, crc32.ChecksumIEEE(e.buf.Bytes()))
+
+	if _, err := e.w.Write(e.buf.Bytes()); err != nil {
+		return err
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
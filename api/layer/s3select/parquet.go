@@ -0,0 +1,637 @@
+package s3select
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Parquet format constants, see https://github.com/apache/parquet-format.
+const (
+	parquetMagic = "PAR1"
+
+	parquetBoolean           = 0
+	parquetInt32             = 1
+	parquetInt64             = 2
+	parquetInt96             = 3
+	parquetFloat             = 4
+	parquetDouble            = 5
+	parquetByteArray         = 6
+	parquetFixedLenByteArray = 7
+
+	parquetOptional = 1
+	parquetRepeated = 2
+
+	parquetConvertedDecimal         = 5
+	parquetConvertedDate            = 6
+	parquetConvertedTimestampMillis = 9
+	parquetConvertedTimestampMicros = 10
+
+	parquetCodecUncompressed = 0
+	parquetCodecSnappy       = 1
+	parquetCodecGzip         = 2
+
+	parquetPageData       = 0
+	parquetPageDictionary = 2
+	parquetPageDataV2     = 3
+
+	parquetEncodingPlain           = 0
+	parquetEncodingPlainDictionary = 2
+	parquetEncodingRLE             = 3
+	parquetEncodingRLEDictionary   = 8
+
+	// julianDayOfUnixEpoch is used to convert INT96 timestamps.
+	julianDayOfUnixEpoch = 2440588
+)
+
+type (
+	parquetColumn struct {
+		name          string
+		physicalType  int64
+		typeLength    int64
+		optional      bool
+		convertedType int64
+		scale         int64
+	}
+
+	// parquetRecordReader reads Parquet file row group by row group, so only
+	// column chunks of the current row group are kept in memory.
+	parquetRecordReader struct {
+		r         io.ReaderAt
+		size      int64
+		columns   []parquetColumn
+		rowGroups []thriftStruct
+		group     int
+		values    [][]interface{}
+		row, rows int
+		// processed is a size of decompressed pages.
+		processed int64
+	}
+)
+
+var errParquetNestedSchema = errors.New("nested schemas are not supported")
+
+func newParquetRecordReader(r io.ReaderAt, size int64) (*parquetRecordReader, error) {
+	footer := make([]byte, 8)
+	if size < int64(len(parquetMagic)+len(footer)) {
+		return nil, parquetError(errors.New("file is too small"))
+	}
+	if _, err := r.ReadAt(footer, size-int64(len(footer))); err != nil {
+		return nil, err
+	}
+	if string(footer[4:]) != parquetMagic {
+		return nil, parquetError(errors.New("invalid magic"))
+	}
+
+	metaLen := int64(binary.LittleEndian.Uint32(footer))
+	if metaLen > size-int64(len(parquetMagic)+len(footer)) {
+		return nil, parquetError(errors.New("invalid metadata length"))
+	}
+	metaBytes := make([]byte, metaLen)
+	if _, err := r.ReadAt(metaBytes, size-int64(len(footer))-metaLen); err != nil {
+		return nil, err
+	}
+
+	meta, err := (&thriftReader{buf: metaBytes}).readStruct()
+	if err != nil {
+		return nil, parquetError(fmt.Errorf("file metadata: %w", err))
+	}
+
+	reader := &parquetRecordReader{r: r, size: size}
+	if reader.columns, err = parseParquetSchema(meta.list(2)); err != nil {
+		return nil, parquetError(err)
+	}
+	for _, rowGroup := range meta.list(4) {
+		rg, ok := rowGroup.(thriftStruct)
+		if !ok {
+			return nil, parquetError(errors.New("invalid row group"))
+		}
+		reader.rowGroups = append(reader.rowGroups, rg)
+	}
+
+	return reader, nil
+}
+
+func parseParquetSchema(elements []interface{}) ([]parquetColumn, error) {
+	if len(elements) == 0 {
+		return nil, errors.New("empty schema")
+	}
+
+	columns := make([]parquetColumn, 0, len(elements)-1)
+	for _, element := range elements[1:] {
+		el, ok := element.(thriftStruct)
+		if !ok {
+			return nil, errors.New("invalid schema element")
+		}
+		if el.int(5) > 0 || el.int(3) == parquetRepeated {
+			return nil, errParquetNestedSchema
+		}
+
+		col := parquetColumn{
+			name:          el.str(4),
+			physicalType:  el.int(1),
+			typeLength:    el.int(2),
+			optional:      el.int(3) == parquetOptional,
+			convertedType: -1,
+			scale:         el.int(7),
+		}
+		if _, ok = el[6]; ok {
+			col.convertedType = el.int(6)
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
+
+func (p *parquetRecordReader) read() (record, error) {
+	for p.row >= p.rows {
+		if p.group >= len(p.rowGroups) {
+			return nil, io.EOF
+		}
+		if err := p.loadRowGroup(p.rowGroups[p.group]); err != nil {
+			return nil, err
+		}
+		p.group++
+	}
+
+	obj := &jsonObject{
+		keys:   make([]string, len(p.columns)),
+		values: make(map[string]interface{}, len(p.columns)),
+	}
+	for i, col := range p.columns {
+		obj.keys[i] = col.name
+		obj.values[col.name] = p.values[i][p.row]
+	}
+	p.row++
+
+	return &jsonRecord{value: obj}, nil
+}
+
+func (p *parquetRecordReader) loadRowGroup(rowGroup thriftStruct) error {
+	chunks := rowGroup.list(1)
+	if len(chunks) != len(p.columns) {
+		return parquetError(errors.New("number of column chunks doesn't match schema"))
+	}
+
+	var (
+		err    error
+		rows   = rowGroup.int(3)
+		values = make([][]interface{}, len(p.columns))
+	)
+	for i, chunk := range chunks {
+		cc, ok := chunk.(thriftStruct)
+		if !ok {
+			return parquetError(errors.New("invalid column chunk"))
+		}
+		meta := cc.strct(3)
+
+		start := meta.int(9)
+		if dictOffset := meta.int(11); dictOffset > 0 && dictOffset < start {
+			start = dictOffset
+		}
+		length := meta.int(7)
+		if start < 0 || length < 0 || start+length > p.size {
+			return parquetError(errors.New("column chunk is out of file"))
+		}
+
+		data := make([]byte, length)
+		if _, err = p.r.ReadAt(data, start); err != nil {
+			return err
+		}
+
+		if values[i], err = p.decodeColumnChunk(data, p.columns[i], meta.int(4), rows); err != nil {
+			return parquetError(fmt.Errorf("column '%s': %w", p.columns[i].name, err))
+		}
+	}
+
+	p.values, p.row, p.rows = values, 0, int(rows)
+	return nil
+}
+
+func (p *parquetRecordReader) decodeColumnChunk(data []byte, col parquetColumn, codec, rows int64) ([]interface{}, error) {
+	var (
+		dict   []interface{}
+		values []interface{}
+		t      = &thriftReader{buf: data}
+	)
+
+	for int64(len(values)) < rows {
+		header, err := t.readStruct()
+		if err != nil {
+			return nil, fmt.Errorf("page header: %w", err)
+		}
+
+		compressedSize := header.int(3)
+		if compressedSize < 0 || int64(t.pos)+compressedSize > int64(len(data)) {
+			return nil, errors.New("page is out of column chunk")
+		}
+		page := data[t.pos : int64(t.pos)+compressedSize]
+		t.pos += int(compressedSize)
+		uncompressedSize := header.int(2)
+
+		switch header.int(1) {
+		case parquetPageDictionary:
+			if page, err = p.decompress(codec, page, uncompressedSize); err != nil {
+				return nil, err
+			}
+			if dict, _, err = decodeParquetPlain(page, col, int(header.strct(7).int(1))); err != nil {
+				return nil, err
+			}
+		case parquetPageData:
+			if page, err = p.decompress(codec, page, uncompressedSize); err != nil {
+				return nil, err
+			}
+			dh := header.strct(5)
+			numValues := int(dh.int(1))
+			if numValues < 0 || int64(len(values)+numValues) > rows {
+				return nil, errors.New("invalid number of page values")
+			}
+
+			var defLevels []uint32
+			if col.optional {
+				if len(page) < 4 {
+					return nil, errors.New("invalid definition levels")
+				}
+				levelsLen := int(binary.LittleEndian.Uint32(page))
+				if levelsLen > len(page)-4 {
+					return nil, errors.New("invalid definition levels")
+				}
+				if defLevels, err = decodeParquetHybrid(page[4:4+levelsLen], 1, numValues); err != nil {
+					return nil, err
+				}
+				page = page[4+levelsLen:]
+			}
+
+			if values, err = appendParquetValues(values, page, dh.int(2), col, numValues, defLevels, dict); err != nil {
+				return nil, err
+			}
+		case parquetPageDataV2:
+			dh := header.strct(8)
+			numValues := int(dh.int(1))
+			if numValues < 0 || int64(len(values)+numValues) > rows {
+				return nil, errors.New("invalid number of page values")
+			}
+			defLen, repLen := dh.int(5), dh.int(6)
+			if defLen < 0 || repLen < 0 || defLen+repLen > int64(len(page)) {
+				return nil, errors.New("invalid levels length")
+			}
+
+			var defLevels []uint32
+			if col.optional {
+				if defLevels, err = decodeParquetHybrid(page[repLen:repLen+defLen], 1, numValues); err != nil {
+					return nil, err
+				}
+			}
+
+			page = page[repLen+defLen:]
+			if compressed, ok := dh[7].(bool); !ok || compressed {
+				if page, err = p.decompress(codec, page, uncompressedSize-repLen-defLen); err != nil {
+					return nil, err
+				}
+			}
+
+			if values, err = appendParquetValues(values, page, dh.int(4), col, numValues, defLevels, dict); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return values, nil
+}
+
+func (p *parquetRecordReader) decompress(codec int64, data []byte, uncompressedSize int64) ([]byte, error) {
+	var (
+		res []byte
+		err error
+	)
+
+	switch codec {
+	case parquetCodecUncompressed:
+		res = data
+	case parquetCodecSnappy:
+		res, err = decodeSnappy(data, uncompressedSize)
+	case parquetCodecGzip:
+		var gzipReader *gzip.Reader
+		if gzipReader, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			res, err = io.ReadAll(io.LimitReader(gzipReader, uncompressedSize))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %d", codec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.processed += int64(len(res))
+	return res, nil
+}
+
+// appendParquetValues decodes values of the data page, definition levels are nil for required columns.
+func appendParquetValues(values []interface{}, data []byte, encoding int64, col parquetColumn, numValues int, defLevels []uint32, dict []interface{}) ([]interface{}, error) {
+	notNull := numValues
+	if defLevels != nil {
+		notNull = 0
+		for _, level := range defLevels {
+			if level != 0 {
+				notNull++
+			}
+		}
+	}
+
+	var (
+		decoded []interface{}
+		err     error
+	)
+
+	switch encoding {
+	case parquetEncodingPlain:
+		decoded, _, err = decodeParquetPlain(data, col, notNull)
+	case parquetEncodingPlainDictionary, parquetEncodingRLEDictionary:
+		if len(data) == 0 {
+			if notNull != 0 {
+				return nil, errors.New("empty dictionary indices")
+			}
+			break
+		}
+		var indices []uint32
+		if indices, err = decodeParquetHybrid(data[1:], int(data[0]), notNull); err != nil {
+			return nil, err
+		}
+		decoded = make([]interface{}, len(indices))
+		for i, index := range indices {
+			if int(index) >= len(dict) {
+				return nil, errors.New("dictionary index is out of range")
+			}
+			decoded[i] = dict[index]
+		}
+	case parquetEncodingRLE:
+		if col.physicalType != parquetBoolean || len(data) < 4 {
+			return nil, errors.New("unsupported RLE encoded values")
+		}
+		var bits []uint32
+		if bits, err = decodeParquetHybrid(data[4:], 1, notNull); err != nil {
+			return nil, err
+		}
+		decoded = make([]interface{}, len(bits))
+		for i, bit := range bits {
+			decoded[i] = bit == 1
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding %d", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if defLevels == nil {
+		return append(values, decoded...), nil
+	}
+
+	var next int
+	for _, level := range defLevels {
+		if level == 0 {
+			values = append(values, nil)
+			continue
+		}
+		values = append(values, decoded[next])
+		next++
+	}
+
+	return values, nil
+}
+
+// decodeParquetPlain decodes n PLAIN encoded values and returns them with the number of consumed bytes.
+func decodeParquetPlain(data []byte, col parquetColumn, n int) ([]interface{}, int, error) {
+	errTruncated := errors.New("truncated values")
+	// every value takes at least one bit
+	if n < 0 || n > len(data)*8 {
+		return nil, 0, errTruncated
+	}
+
+	values := make([]interface{}, n)
+	pos := 0
+
+	for i := 0; i < n; i++ {
+		switch col.physicalType {
+		case parquetBoolean:
+			if i/8 >= len(data) {
+				return nil, 0, errTruncated
+			}
+			values[i] = data[i/8]>>(i%8)&1 == 1
+			pos = i/8 + 1
+		case parquetInt32:
+			if pos+4 > len(data) {
+				return nil, 0, errTruncated
+			}
+			values[i] = convertParquetInt(col, int64(int32(binary.LittleEndian.Uint32(data[pos:]))))
+			pos += 4
+		case parquetInt64:
+			if pos+8 > len(data) {
+				return nil, 0, errTruncated
+			}
+			values[i] = convertParquetInt(col, int64(binary.LittleEndian.Uint64(data[pos:])))
+			pos += 8
+		case parquetInt96:
+			if pos+12 > len(data) {
+				return nil, 0, errTruncated
+			}
+			nanos := int64(binary.LittleEndian.Uint64(data[pos:]))
+			days := int64(binary.LittleEndian.Uint32(data[pos+8:])) - julianDayOfUnixEpoch
+			values[i] = time.Unix(days*24*60*60, nanos).UTC().Format(time.RFC3339Nano)
+			pos += 12
+		case parquetFloat:
+			if pos+4 > len(data) {
+				return nil, 0, errTruncated
+			}
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[pos:])))
+			pos += 4
+		case parquetDouble:
+			if pos+8 > len(data) {
+				return nil, 0, errTruncated
+			}
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[pos:]))
+			pos += 8
+		case parquetByteArray, parquetFixedLenByteArray:
+			length := int(col.typeLength)
+			if col.physicalType == parquetByteArray {
+				if pos+4 > len(data) {
+					return nil, 0, errTruncated
+				}
+				length = int(binary.LittleEndian.Uint32(data[pos:]))
+				pos += 4
+			}
+			if length < 0 || pos+length > len(data) {
+				return nil, 0, errTruncated
+			}
+			values[i] = convertParquetBytes(col, data[pos:pos+length])
+			pos += length
+		default:
+			return nil, 0, fmt.Errorf("unsupported type %d", col.physicalType)
+		}
+	}
+
+	return values, pos, nil
+}
+
+func convertParquetInt(col parquetColumn, v int64) interface{} {
+	switch col.convertedType {
+	case parquetConvertedDecimal:
+		return float64(v) / math.Pow10(int(col.scale))
+	case parquetConvertedDate:
+		return time.Unix(v*24*60*60, 0).UTC().Format("2006-01-02")
+	case parquetConvertedTimestampMillis:
+		return time.Unix(0, v*int64(time.Millisecond)).UTC().Format(time.RFC3339Nano)
+	case parquetConvertedTimestampMicros:
+		return time.Unix(0, v*int64(time.Microsecond)).UTC().Format(time.RFC3339Nano)
+	default:
+		return float64(v)
+	}
+}
+
+func convertParquetBytes(col parquetColumn, data []byte) interface{} {
+	if col.convertedType != parquetConvertedDecimal {
+		return string(data)
+	}
+
+	// big-endian two's complement unscaled value
+	unscaled := new(big.Int).SetBytes(data)
+	if len(data) > 0 && data[0]&0x80 != 0 {
+		unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(data)*8)))
+	}
+	value, _ := new(big.Float).SetInt(unscaled).Float64()
+	return value / math.Pow10(int(col.scale))
+}
+
+// decodeParquetHybrid decodes n values of RLE/bit-packing hybrid encoding.
+func decodeParquetHybrid(data []byte, bitWidth, n int) ([]uint32, error) {
+	if bitWidth > 32 {
+		return nil, errors.New("invalid bit width")
+	}
+
+	var res []uint32
+	for pos := 0; len(res) < n; {
+		header, k := binary.Uvarint(data[pos:])
+		if k <= 0 {
+			return nil, errors.New("truncated hybrid encoded values")
+		}
+		pos += k
+
+		if header&1 == 1 {
+			groups := int(header >> 1)
+			byteLen := groups * bitWidth
+			if groups < 0 || pos+byteLen > len(data) {
+				return nil, errors.New("truncated bit-packed run")
+			}
+			for i := 0; i < groups*8 && len(res) < n; i++ {
+				var v uint32
+				for b := 0; b < bitWidth; b++ {
+					bit := i*bitWidth + b
+					v |= uint32(data[pos+bit/8]>>(bit%8)&1) << b
+				}
+				res = append(res, v)
+			}
+			pos += byteLen
+			continue
+		}
+
+		width := (bitWidth + 7) / 8
+		if pos+width > len(data) {
+			return nil, errors.New("truncated RLE run")
+		}
+		var v uint32
+		for b := 0; b < width; b++ {
+			v |= uint32(data[pos+b]) << (8 * b)
+		}
+		pos += width
+		for count := header >> 1; count > 0 && len(res) < n; count-- {
+			res = append(res, v)
+		}
+	}
+
+	return res, nil
+}
+
+// decodeSnappy decodes snappy block format, see https://github.com/google/snappy/blob/main/format_description.txt.
+func decodeSnappy(src []byte, expectedSize int64) ([]byte, error) {
+	size, k := binary.Uvarint(src)
+	if k <= 0 || int64(size) != expectedSize {
+		return nil, errors.New("invalid snappy block length")
+	}
+	src = src[k:]
+
+	errCorrupted := errors.New("corrupted snappy block")
+	dst := make([]byte, 0, size)
+	for len(src) > 0 {
+		tag := src[0]
+		var length, offset int
+
+		switch tag & 3 {
+		case 0:
+			length = int(tag >> 2)
+			src = src[1:]
+			if length >= 60 {
+				extra := length - 59
+				if len(src) < extra {
+					return nil, errCorrupted
+				}
+				length = 0
+				for i := 0; i < extra; i++ {
+					length |= int(src[i]) << (8 * i)
+				}
+				src = src[extra:]
+			}
+			length++
+			if length <= 0 || len(src) < length || uint64(len(dst)+length) > size {
+				return nil, errCorrupted
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case 1:
+			if len(src) < 2 {
+				return nil, errCorrupted
+			}
+			length = 4 + int(tag>>2&7)
+			offset = int(tag&0xe0)<<3 | int(src[1])
+			src = src[2:]
+		case 2:
+			if len(src) < 3 {
+				return nil, errCorrupted
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case 3:
+			if len(src) < 5 {
+				return nil, errCorrupted
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+
+		if offset <= 0 || offset > len(dst) || uint64(len(dst)+length) > size {
+			return nil, errCorrupted
+		}
+		for i := 0; i < length; i++ {
+			dst = append(dst, dst[len(dst)-offset])
+		}
+	}
+
+	if uint64(len(dst)) != size {
+		return nil, errCorrupted
+	}
+
+	return dst, nil
+}
+
+func parquetError(err error) error {
+	return &ParsingError{Format: "Parquet", Err: err}
+}
@@ -0,0 +1,210 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testColumnChunk struct {
+	name  string
+	codec int64
+	pages [][]byte
+	// first page is a dictionary page
+	dictionary bool
+}
+
+func TestParquet(t *testing.T) {
+	// id INT64 REQUIRED, name BYTE_ARRAY OPTIONAL with dictionary and snappy, score DOUBLE OPTIONAL in v2 page
+	var ids []byte
+	for _, id := range []uint64{1, 2, 3} {
+		ids = appendUint64(ids, id)
+	}
+	idPage := dataPageV1(3, parquetEncodingPlain, ids, ids)
+
+	dict := []byte("\x05\x00\x00\x00alice\x05\x00\x00\x00carol")
+	dictPage := pageHeader(parquetPageDictionary, len(dict), snappyLiteral(dict), func(w *thriftWriter) {
+		w.strct(7, func() {
+			w.int(1, 2)
+			w.int(2, parquetEncodingPlain)
+		})
+	})
+	// definition levels [1, 0, 1] and dictionary indices [0, 1]
+	names := []byte{2, 0, 0, 0, 3, 5, 1, 3, 2}
+	namePage := dataPageV1(3, parquetEncodingRLEDictionary, names, snappyLiteral(names))
+
+	scores := []byte{3, 5}
+	scores = appendUint64(scores, math.Float64bits(7.5))
+	scores = appendUint64(scores, math.Float64bits(9))
+	scorePage := pageHeader(parquetPageDataV2, len(scores), scores, func(w *thriftWriter) {
+		w.strct(8, func() {
+			w.int(1, 3)
+			w.int(2, 1)
+			w.int(3, 3)
+			w.int(4, parquetEncodingPlain)
+			w.int(5, 2)
+			w.int(6, 0)
+			w.bool(7, false)
+		})
+	})
+
+	file := parquetFile([]testColumnChunk{
+		{name: "id", pages: [][]byte{idPage}},
+		{name: "name", codec: parquetCodecSnappy, pages: [][]byte{dictPage, namePage}, dictionary: true},
+		{name: "score", pages: [][]byte{scorePage}},
+	}, func(w *thriftWriter) {
+		w.strct(0, func() {
+			w.str(4, "schema")
+			w.int(5, 3)
+		})
+		w.strct(0, func() {
+			w.int(1, parquetInt64)
+			w.int(3, 0)
+			w.str(4, "id")
+		})
+		w.strct(0, func() {
+			w.int(1, parquetByteArray)
+			w.int(3, parquetOptional)
+			w.str(4, "name")
+			w.int(6, 0)
+		})
+		w.strct(0, func() {
+			w.int(1, parquetDouble)
+			w.int(3, parquetOptional)
+			w.str(4, "score")
+		})
+	})
+
+	input := &InputSerialization{Parquet: &struct{}{}}
+	jsonOutput := &OutputSerialization{JSON: &JSONOutput{}}
+	csvOutput := &OutputSerialization{CSV: &CSVOutput{}}
+
+	for _, tc := range []struct {
+		expression string
+		output     *OutputSerialization
+		expected   string
+	}{
+		{"SELECT * FROM S3Object", jsonOutput, "{\"id\":1,\"name\":\"alice\",\"score\":7.5}\n{\"id\":2}\n{\"id\":3,\"name\":\"carol\",\"score\":9}\n"},
+		{"SELECT s.name FROM S3Object s WHERE s.score > 8", csvOutput, "carol\n"},
+		{"SELECT COUNT(*), SUM(id) FROM S3Object WHERE name IS NOT NULL", csvOutput, "2,4\n"},
+	} {
+		t.Run(tc.expression, func(t *testing.T) {
+			q, err := NewQuery(tc.expression, input, tc.output)
+			require.NoError(t, err)
+			require.True(t, q.IsParquet())
+
+			var out bytes.Buffer
+			require.NoError(t, q.RunParquet(bytes.NewReader(file), int64(len(file)), NewEventWriter(&out), false))
+			require.Equal(t, tc.expected, recordsPayload(out.Bytes()))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		q, err := NewQuery("SELECT * FROM S3Object", input, csvOutput)
+		require.NoError(t, err)
+
+		var parsingErr *ParsingError
+		err = q.RunParquet(bytes.NewReader(file[:len(file)-1]), int64(len(file)-1), NewEventWriter(new(bytes.Buffer)), false)
+		require.ErrorAs(t, err, &parsingErr)
+		require.Equal(t, "ParquetParsingError", parsingErr.Code())
+
+		_, err = NewQuery("SELECT * FROM S3Object", &InputSerialization{CompressionType: "GZIP", Parquet: &struct{}{}}, csvOutput)
+		require.Error(t, err)
+	})
+}
+
+func TestDecodeSnappy(t *testing.T) {
+	// literal "abc" and copy of 9 bytes with offset 3
+	res, err := decodeSnappy([]byte{12, 0x08, 'a', 'b', 'c', 0x15, 0x03}, 12)
+	require.NoError(t, err)
+	require.Equal(t, "abcabcabcabc", string(res))
+
+	_, err = decodeSnappy([]byte{12, 0x08, 'a', 'b', 'c', 0x15, 0x04}, 12)
+	require.Error(t, err)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return append(buf, b...)
+}
+
+func snappyLiteral(data []byte) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	res := append([]byte{}, buf[:binary.PutUvarint(buf, uint64(len(data)))]...)
+	res = append(res, byte(len(data)-1)<<2)
+	return append(res, data...)
+}
+
+func dataPageV1(numValues, encoding int64, uncompressed, payload []byte) []byte {
+	return pageHeader(parquetPageData, len(uncompressed), payload, func(w *thriftWriter) {
+		w.strct(5, func() {
+			w.int(1, numValues)
+			w.int(2, encoding)
+			w.int(3, parquetEncodingRLE)
+			w.int(4, parquetEncodingRLE)
+		})
+	})
+}
+
+func pageHeader(typ int64, uncompressedSize int, payload []byte, header func(w *thriftWriter)) []byte {
+	w := &thriftWriter{}
+	w.strct(0, func() {
+		w.int(1, typ)
+		w.int(2, int64(uncompressedSize))
+		w.int(3, int64(len(payload)))
+		header(w)
+	})
+	return append(w.Bytes(), payload...)
+}
+
+func parquetFile(chunks []testColumnChunk, schema func(w *thriftWriter)) []byte {
+	file := []byte(parquetMagic)
+	offsets := make([]int64, len(chunks))
+	sizes := make([]int64, len(chunks))
+	for i, chunk := range chunks {
+		offsets[i] = int64(len(file))
+		for _, page := range chunk.pages {
+			file = append(file, page...)
+		}
+		sizes[i] = int64(len(file)) - offsets[i]
+	}
+
+	w := &thriftWriter{}
+	w.strct(0, func() {
+		w.int(1, 1)
+		w.list(2, thriftStructure, 4, func() { schema(w) })
+		w.int(3, 3)
+		w.list(4, thriftStructure, 1, func() {
+			w.strct(0, func() {
+				w.list(1, thriftStructure, len(chunks), func() {
+					for i, chunk := range chunks {
+						w.strct(0, func() {
+							w.int(2, offsets[i])
+							w.strct(3, func() {
+								w.list(3, thriftBinary, 1, func() { w.binary(chunk.name) })
+								w.int(4, chunk.codec)
+								w.int(5, 3)
+								w.int(7, sizes[i])
+								if chunk.dictionary {
+									w.int(9, offsets[i]+int64(len(chunk.pages[0])))
+									w.int(11, offsets[i])
+								} else {
+									w.int(9, offsets[i])
+								}
+							})
+						})
+					}
+				})
+				w.int(3, 3)
+			})
+		})
+	})
+
+	file = append(file, w.Bytes()...)
+	file = append(file, byte(w.Len()), byte(w.Len()>>8), byte(w.Len()>>16), byte(w.Len()>>24))
+	return append(file, parquetMagic...)
+}
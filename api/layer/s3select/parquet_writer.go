@@ -0,0 +1,303 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParquetType is a type of column written by ParquetWriter.
+type ParquetType int
+
+// Types of columns written by ParquetWriter.
+const (
+	// ParquetString is a UTF8 BYTE_ARRAY column of string values.
+	ParquetString ParquetType = iota
+	// ParquetInt64 is an INT64 column of int64 values.
+	ParquetInt64
+	// ParquetBoolean is a BOOLEAN column of bool values.
+	ParquetBoolean
+	// ParquetTimestamp is a TIMESTAMP_MILLIS INT64 column of time.Time values.
+	ParquetTimestamp
+)
+
+const (
+	parquetConvertedUTF8 = 0
+	parquetRequired      = 0
+	parquetCreatedBy     = "neofs-s3-gw"
+)
+
+type (
+	// ParquetColumn describes a column written by ParquetWriter.
+	ParquetColumn struct {
+		Name string
+		Type ParquetType
+		// Optional column accepts nil values.
+		Optional bool
+	}
+
+	// ParquetWriter writes rows to a Parquet file with a single row group. Columns are
+	// PLAIN encoded and uncompressed, the file is kept in memory until it's encoded.
+	ParquetWriter struct {
+		name    string
+		columns []ParquetColumn
+		chunks  []parquetChunkWriter
+		rows    int
+	}
+
+	parquetChunkWriter struct {
+		values bytes.Buffer
+		// defLevels are definition levels of the optional column, 0 is for nil values.
+		defLevels []byte
+		// bits are packed values of the boolean column.
+		bits  []byte
+		nBits int
+	}
+)
+
+// NewParquetWriter creates a writer of the file with the schema of the name and the columns.
+func NewParquetWriter(name string, columns []ParquetColumn) *ParquetWriter {
+	return &ParquetWriter{
+		name:    name,
+		columns: columns,
+		chunks:  make([]parquetChunkWriter, len(columns)),
+	}
+}
+
+// Write adds a row with values of the columns, nil values are allowed for optional columns only.
+func (p *ParquetWriter) Write(row []interface{}) error {
+	if len(row) != len(p.columns) {
+		return fmt.Errorf("row has %d values, but there are %d columns", len(row), len(p.columns))
+	}
+
+	for i, col := range p.columns {
+		if row[i] == nil {
+			if !col.Optional {
+				return fmt.Errorf("nil value of required column '%s'", col.Name)
+			}
+			continue
+		}
+		if !col.Type.check(row[i]) {
+			return fmt.Errorf("invalid value type %T of column '%s'", row[i], col.Name)
+		}
+	}
+
+	for i, col := range p.columns {
+		chunk := &p.chunks[i]
+		if col.Optional {
+			if row[i] == nil {
+				chunk.defLevels = append(chunk.defLevels, 0)
+				continue
+			}
+			chunk.defLevels = append(chunk.defLevels, 1)
+		}
+
+		switch v := row[i].(type) {
+		case string:
+			_ = binary.Write(&chunk.values, binary.LittleEndian, uint32(len(v)))
+			chunk.values.WriteString(v)
+		case int64:
+			_ = binary.Write(&chunk.values, binary.LittleEndian, v)
+		case time.Time:
+			_ = binary.Write(&chunk.values, binary.LittleEndian, v.UnixNano()/int64(time.Millisecond))
+		case bool:
+			if chunk.nBits%8 == 0 {
+				chunk.bits = append(chunk.bits, 0)
+			}
+			if v {
+				chunk.bits[len(chunk.bits)-1] |= 1 << (chunk.nBits % 8)
+			}
+			chunk.nBits++
+		}
+	}
+	p.rows++
+
+	return nil
+}
+
+// Rows returns the number of written rows.
+func (p *ParquetWriter) Rows() int {
+	return p.rows
+}
+
+// Schema returns the schema of the file in the Parquet message format.
+func (p *ParquetWriter) Schema() string {
+	fields := make([]string, len(p.columns))
+	for i, col := range p.columns {
+		repetition := "required"
+		if col.Optional {
+			repetition = "optional"
+		}
+
+		var typ string
+		switch col.Type {
+		case ParquetString:
+			typ = "binary " + col.Name + " (UTF8)"
+		case ParquetInt64:
+			typ = "int64 " + col.Name
+		case ParquetBoolean:
+			typ = "boolean " + col.Name
+		case ParquetTimestamp:
+			typ = "int64 " + col.Name + " (TIMESTAMP_MILLIS)"
+		}
+		fields[i] = repetition + " " + typ + ";"
+	}
+
+	return "message " + p.name + " { " + strings.Join(fields, " ") + " }"
+}
+
+// Bytes encodes the written rows to the Parquet file.
+func (p *ParquetWriter) Bytes() []byte {
+	file := bytes.NewBufferString(parquetMagic)
+
+	offsets := make([]int64, len(p.columns))
+	sizes := make([]int64, len(p.columns))
+	for i := range p.columns {
+		page := p.chunks[i].page(p.columns[i].Optional)
+
+		header := &thriftWriter{}
+		header.strct(0, func() {
+			header.int32(1, parquetPageData)
+			header.int32(2, int32(len(page)))
+			header.int32(3, int32(len(page)))
+			header.strct(5, func() {
+				header.int32(1, int32(p.rows))
+				header.int32(2, parquetEncodingPlain)
+				header.int32(3, parquetEncodingRLE)
+				header.int32(4, parquetEncodingRLE)
+			})
+		})
+
+		offsets[i] = int64(file.Len())
+		sizes[i] = int64(header.Len() + len(page))
+		file.Write(header.Bytes())
+		file.Write(page)
+	}
+
+	meta := &thriftWriter{}
+	meta.strct(0, func() {
+		meta.int32(1, 1)
+		meta.list(2, thriftStructure, len(p.columns)+1, func() {
+			meta.strct(0, func() {
+				meta.str(4, p.name)
+				meta.int32(5, int32(len(p.columns)))
+			})
+			for _, col := range p.columns {
+				col := col
+				meta.strct(0, func() {
+					meta.int32(1, int32(col.Type.physicalType()))
+					repetition := parquetRequired
+					if col.Optional {
+						repetition = parquetOptional
+					}
+					meta.int32(3, int32(repetition))
+					meta.str(4, col.Name)
+					switch col.Type {
+					case ParquetString:
+						meta.int32(6, parquetConvertedUTF8)
+					case ParquetTimestamp:
+						meta.int32(6, parquetConvertedTimestampMillis)
+					}
+				})
+			}
+		})
+		meta.int(3, int64(p.rows))
+		meta.list(4, thriftStructure, 1, func() {
+			meta.strct(0, func() {
+				var total int64
+				meta.list(1, thriftStructure, len(p.columns), func() {
+					for i, col := range p.columns {
+						i, col := i, col
+						total += sizes[i]
+						meta.strct(0, func() {
+							meta.int(2, offsets[i])
+							meta.strct(3, func() {
+								meta.int32(1, int32(col.Type.physicalType()))
+								meta.list(2, thriftI32, 2, func() {
+									meta.varint(parquetEncodingPlain)
+									meta.varint(parquetEncodingRLE)
+								})
+								meta.list(3, thriftBinary, 1, func() { meta.binary(col.Name) })
+								meta.int32(4, parquetCodecUncompressed)
+								meta.int(5, int64(p.rows))
+								meta.int(6, sizes[i])
+								meta.int(7, sizes[i])
+								meta.int(9, offsets[i])
+							})
+						})
+					}
+				})
+				meta.int(2, total)
+				meta.int(3, int64(p.rows))
+			})
+		})
+		meta.str(6, parquetCreatedBy)
+	})
+
+	file.Write(meta.Bytes())
+	_ = binary.Write(file, binary.LittleEndian, uint32(meta.Len()))
+	file.WriteString(parquetMagic)
+
+	return file.Bytes()
+}
+
+// page returns the payload of the data page: definition levels of the optional column and values.
+func (c *parquetChunkWriter) page(optional bool) []byte {
+	var page bytes.Buffer
+	if optional {
+		levels := encodeParquetRLE(c.defLevels)
+		_ = binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+	page.Write(c.values.Bytes())
+	page.Write(c.bits)
+
+	return page.Bytes()
+}
+
+// encodeParquetRLE encodes values of bit width 1 by RLE runs of RLE/bit-packing hybrid encoding.
+func encodeParquetRLE(values []byte) []byte {
+	var (
+		res []byte
+		buf = make([]byte, binary.MaxVarintLen64)
+	)
+	for start := 0; start < len(values); {
+		end := start + 1
+		for end < len(values) && values[end] == values[start] {
+			end++
+		}
+		res = append(res, buf[:binary.PutUvarint(buf, uint64(end-start)<<1)]...)
+		res = append(res, values[start])
+		start = end
+	}
+
+	return res
+}
+
+func (t ParquetType) check(v interface{}) bool {
+	var ok bool
+	switch t {
+	case ParquetString:
+		_, ok = v.(string)
+	case ParquetInt64:
+		_, ok = v.(int64)
+	case ParquetBoolean:
+		_, ok = v.(bool)
+	case ParquetTimestamp:
+		_, ok = v.(time.Time)
+	}
+	return ok
+}
+
+func (t ParquetType) physicalType() int {
+	switch t {
+	case ParquetString:
+		return parquetByteArray
+	case ParquetBoolean:
+		return parquetBoolean
+	default:
+		return parquetInt64
+	}
+}
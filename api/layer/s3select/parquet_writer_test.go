@@ -0,0 +1,39 @@
+package s3select
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetWriter(t *testing.T) {
+	w := NewParquetWriter("test", []ParquetColumn{
+		{Name: "key", Type: ParquetString},
+		{Name: "size", Type: ParquetInt64, Optional: true},
+		{Name: "latest", Type: ParquetBoolean, Optional: true},
+		{Name: "modified", Type: ParquetTimestamp},
+	})
+	require.Equal(t, "message test { required binary key (UTF8); optional int64 size; optional boolean latest; "+
+		"required int64 modified (TIMESTAMP_MILLIS); }", w.Schema())
+
+	modified := time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, w.Write([]interface{}{"a", int64(1), true, modified}))
+	require.NoError(t, w.Write([]interface{}{"b", nil, false, modified}))
+	require.NoError(t, w.Write([]interface{}{"c", int64(3), nil, modified}))
+	require.Error(t, w.Write([]interface{}{nil, int64(1), true, modified}))
+	require.Error(t, w.Write([]interface{}{"d", 1, true, modified}))
+	require.Error(t, w.Write([]interface{}{"d"}))
+	require.Equal(t, 3, w.Rows())
+
+	file := w.Bytes()
+	q, err := NewQuery("SELECT * FROM S3Object", &InputSerialization{Parquet: &struct{}{}}, &OutputSerialization{JSON: &JSONOutput{}})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, q.RunParquet(bytes.NewReader(file), int64(len(file)), NewEventWriter(&out), false))
+	require.Equal(t, `{"key":"a","size":1,"latest":true,"modified":"2022-10-16T12:00:00Z"}`+"\n"+
+		`{"key":"b","latest":false,"modified":"2022-10-16T12:00:00Z"}`+"\n"+
+		`{"key":"c","size":3,"modified":"2022-10-16T12:00:00Z"}`+"\n", recordsPayload(out.Bytes()))
+}
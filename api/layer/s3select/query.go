@@ -0,0 +1,521 @@
+package s3select
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type (
+	// InputSerialization describes the format of the object payload.
+	InputSerialization struct {
+		CompressionType string     `xml:"CompressionType,omitempty"`
+		CSV             *CSVInput  `xml:"CSV"`
+		JSON            *JSONInput `xml:"JSON"`
+		Parquet         *struct{}  `xml:"Parquet"`
+	}
+
+	// CSVInput describes CSV payload.
+	CSVInput struct {
+		FileHeaderInfo             string `xml:"FileHeaderInfo,omitempty"`
+		Comments                   string `xml:"Comments,omitempty"`
+		QuoteEscapeCharacter       string `xml:"QuoteEscapeCharacter,omitempty"`
+		RecordDelimiter            string `xml:"RecordDelimiter,omitempty"`
+		FieldDelimiter             string `xml:"FieldDelimiter,omitempty"`
+		QuoteCharacter             string `xml:"QuoteCharacter,omitempty"`
+		AllowQuotedRecordDelimiter bool   `xml:"AllowQuotedRecordDelimiter,omitempty"`
+	}
+
+	// JSONInput describes JSON payload.
+	JSONInput struct {
+		Type string `xml:"Type"`
+	}
+
+	// OutputSerialization describes the format of the selected records.
+	OutputSerialization struct {
+		CSV  *CSVOutput  `xml:"CSV"`
+		JSON *JSONOutput `xml:"JSON"`
+	}
+
+	// CSVOutput describes CSV format of the selected records.
+	CSVOutput struct {
+		QuoteFields          string `xml:"QuoteFields,omitempty"`
+		QuoteEscapeCharacter string `xml:"QuoteEscapeCharacter,omitempty"`
+		RecordDelimiter      string `xml:"RecordDelimiter,omitempty"`
+		FieldDelimiter       string `xml:"FieldDelimiter,omitempty"`
+		QuoteCharacter       string `xml:"QuoteCharacter,omitempty"`
+	}
+
+	// JSONOutput describes JSON format of the selected records.
+	JSONOutput struct {
+		RecordDelimiter string `xml:"RecordDelimiter,omitempty"`
+	}
+
+	// Query is a validated SelectObjectContent request.
+	Query struct {
+		stmt   *selectStatement
+		input  InputSerialization
+		output OutputSerialization
+	}
+
+	// countingReader counts read bytes and keeps the error of the underlying reader.
+	countingReader struct {
+		r   io.Reader
+		n   int64
+		err error
+	}
+
+	countingReaderAt struct {
+		r io.ReaderAt
+		n int64
+	}
+
+	// resultWriter serializes selected records and sends them in Records events.
+	resultWriter struct {
+		events   *EventWriter
+		output   *OutputSerialization
+		buf      bytes.Buffer
+		returned int64
+	}
+)
+
+const (
+	compressionNone  = "NONE"
+	compressionGzip  = "GZIP"
+	compressionBzip2 = "BZIP2"
+
+	fileHeaderNone   = "NONE"
+	fileHeaderUse    = "USE"
+	fileHeaderIgnore = "IGNORE"
+
+	jsonTypeDocument = "DOCUMENT"
+	jsonTypeLines    = "LINES"
+
+	quoteFieldsAlways   = "ALWAYS"
+	quoteFieldsAsNeeded = "ASNEEDED"
+
+	// maxExpressionLength is the max length of SQL expression in bytes.
+	maxExpressionLength = 256 * 1024
+
+	// recordsChunkSize is the max size of payload of a single Records event.
+	recordsChunkSize = 128 * 1024
+)
+
+// NewQuery parses the SQL expression and validates serialization parameters.
+func NewQuery(expression string, input *InputSerialization, output *OutputSerialization) (*Query, error) {
+	if len(expression) > maxExpressionLength {
+		return nil, errors.GetAPIError(errors.ErrExpressionTooLong)
+	}
+	if input == nil || output == nil {
+		return nil, errors.GetAPIError(errors.ErrMissingRequiredParameter)
+	}
+
+	q := &Query{input: *input, output: *output}
+	if err := q.validateInput(); err != nil {
+		return nil, err
+	}
+	if err := q.validateOutput(); err != nil {
+		return nil, err
+	}
+
+	stmt, err := parseSelect(expression)
+	if err != nil {
+		return nil, err
+	}
+	q.stmt = stmt
+
+	return q, nil
+}
+
+func (q *Query) validateInput() error {
+	in := &q.input
+
+	in.CompressionType = strings.ToUpper(in.CompressionType)
+	switch in.CompressionType {
+	case "":
+		in.CompressionType = compressionNone
+	case compressionNone, compressionGzip, compressionBzip2:
+	default:
+		return errors.GetAPIError(errors.ErrInvalidCompressionFormat)
+	}
+
+	formats := 0
+	for _, set := range []bool{in.CSV != nil, in.JSON != nil, in.Parquet != nil} {
+		if set {
+			formats++
+		}
+	}
+	if formats > 1 {
+		return errors.GetAPIError(errors.ErrObjectSerializationConflict)
+	}
+
+	switch {
+	case in.Parquet != nil:
+		// Parquet pages are compressed by the file itself
+		if in.CompressionType != compressionNone {
+			return errors.GetAPIError(errors.ErrInvalidCompressionFormat)
+		}
+	case in.CSV != nil:
+		csvIn := *in.CSV
+		in.CSV = &csvIn
+
+		csvIn.FileHeaderInfo = strings.ToUpper(csvIn.FileHeaderInfo)
+		switch csvIn.FileHeaderInfo {
+		case "":
+			csvIn.FileHeaderInfo = fileHeaderNone
+		case fileHeaderNone, fileHeaderUse, fileHeaderIgnore:
+		default:
+			return errors.GetAPIError(errors.ErrInvalidFileHeaderInfo)
+		}
+		if csvIn.FieldDelimiter == "" {
+			csvIn.FieldDelimiter = ","
+		}
+		// payload is parsed by encoding/csv, so only default quotation and line endings are supported
+		if !isValidDelimiter(csvIn.FieldDelimiter) || !isOptionalRune(csvIn.Comments) ||
+			csvIn.QuoteCharacter != "" && csvIn.QuoteCharacter != `"` ||
+			csvIn.QuoteEscapeCharacter != "" && csvIn.QuoteEscapeCharacter != `"` ||
+			!isValidRecordDelimiter(csvIn.RecordDelimiter) {
+			return errors.GetAPIError(errors.ErrInvalidRequestParameter)
+		}
+	case in.JSON != nil:
+		jsonIn := *in.JSON
+		in.JSON = &jsonIn
+
+		jsonIn.Type = strings.ToUpper(jsonIn.Type)
+		if jsonIn.Type != jsonTypeDocument && jsonIn.Type != jsonTypeLines {
+			return errors.GetAPIError(errors.ErrInvalidJSONType)
+		}
+	default:
+		return errors.GetAPIError(errors.ErrMissingRequiredParameter)
+	}
+
+	return nil
+}
+
+func (q *Query) validateOutput() error {
+	out := &q.output
+	switch {
+	case out.CSV != nil && out.JSON != nil:
+		return errors.GetAPIError(errors.ErrObjectSerializationConflict)
+	case out.CSV != nil:
+		csvOut := *out.CSV
+		out.CSV = &csvOut
+
+		csvOut.QuoteFields = strings.ToUpper(csvOut.QuoteFields)
+		switch csvOut.QuoteFields {
+		case "":
+			csvOut.QuoteFields = quoteFieldsAsNeeded
+		case quoteFieldsAlways, quoteFieldsAsNeeded:
+		default:
+			return errors.GetAPIError(errors.ErrInvalidQuoteFields)
+		}
+		if csvOut.FieldDelimiter == "" {
+			csvOut.FieldDelimiter = ","
+		}
+		if csvOut.QuoteCharacter == "" {
+			csvOut.QuoteCharacter = `"`
+		}
+		if csvOut.QuoteEscapeCharacter == "" {
+			csvOut.QuoteEscapeCharacter = csvOut.QuoteCharacter
+		}
+		if csvOut.RecordDelimiter == "" {
+			csvOut.RecordDelimiter = "\n"
+		}
+		if !isValidDelimiter(csvOut.FieldDelimiter) || !isValidDelimiter(csvOut.QuoteCharacter) ||
+			!isValidDelimiter(csvOut.QuoteEscapeCharacter) || !isValidRecordDelimiter(csvOut.RecordDelimiter) {
+			return errors.GetAPIError(errors.ErrInvalidRequestParameter)
+		}
+	case out.JSON != nil:
+		jsonOut := *out.JSON
+		out.JSON = &jsonOut
+
+		if jsonOut.RecordDelimiter == "" {
+			jsonOut.RecordDelimiter = "\n"
+		}
+		if !isValidRecordDelimiter(jsonOut.RecordDelimiter) {
+			return errors.GetAPIError(errors.ErrInvalidRequestParameter)
+		}
+	default:
+		return errors.GetAPIError(errors.ErrMissingRequiredParameter)
+	}
+
+	return nil
+}
+
+func isValidDelimiter(s string) bool {
+	return len([]rune(s)) == 1 && s != "\n" && s != "\r"
+}
+
+func isOptionalRune(s string) bool {
+	return s == "" || isValidDelimiter(s)
+}
+
+func isValidRecordDelimiter(s string) bool {
+	return s == "" || s == "\n" || s == "\r\n"
+}
+
+// IsParquet checks if the object is Parquet file, it must be processed by RunParquet.
+func (q *Query) IsParquet() bool {
+	return q.input.Parquet != nil
+}
+
+// Run reads CSV or JSON records from r, selects them and writes the result to w. Payload is processed
+// as a stream, so the whole object is never kept in memory. Progress events are sent after
+// every Records event if progress is enabled. Returned error must be sent to the client in the
+// error message, because the response status is already written.
+func (q *Query) Run(r io.Reader, w *EventWriter, progress bool) error {
+	scanned := &countingReader{r: r}
+	processed, err := q.decompress(scanned)
+	if err != nil {
+		return err
+	}
+
+	reader, err := q.newRecordReader(processed)
+	if err != nil {
+		return scanned.errOr(err)
+	}
+
+	err = q.run(reader, w, progress, func() (int64, int64) {
+		return scanned.n, processed.n
+	})
+	return scanned.errOr(err)
+}
+
+// RunParquet is the same as Run, but for Parquet file of the size, which requires random access
+// to read metadata from the end of the file. Row groups are read one by one.
+func (q *Query) RunParquet(r io.ReaderAt, size int64, w *EventWriter, progress bool) error {
+	scanned := &countingReaderAt{r: r}
+	reader, err := newParquetRecordReader(scanned, size)
+	if err != nil {
+		return err
+	}
+
+	return q.run(reader, w, progress, func() (int64, int64) {
+		return scanned.n, reader.processed
+	})
+}
+
+// run selects records of the reader, counters return the number of scanned and processed bytes.
+func (q *Query) run(reader recordReader, w *EventWriter, progress bool, counters func() (int64, int64)) error {
+	res := &resultWriter{events: w, output: &q.output}
+	stats := func() Stats {
+		scanned, processed := counters()
+		return Stats{
+			BytesScanned:   scanned,
+			BytesProcessed: processed,
+			BytesReturned:  res.returned,
+		}
+	}
+
+	var selected int64
+	for q.stmt.aggregate || q.stmt.limit < 0 || selected < q.stmt.limit {
+		rec, err := reader.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if q.stmt.where != nil {
+			match, err := evalBool(q.stmt.where, rec)
+			if err != nil {
+				return err
+			}
+			if match == nil || !*match {
+				continue
+			}
+		}
+
+		if q.stmt.aggregate {
+			for _, item := range q.stmt.items {
+				if err = item.agg.update(rec); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		names, values, err := q.project(rec)
+		if err != nil {
+			return err
+		}
+		res.add(names, values)
+		selected++
+
+		if res.buf.Len() >= recordsChunkSize {
+			if err = res.flush(); err != nil {
+				return err
+			}
+			if progress {
+				if err = w.WriteProgress(stats()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if q.stmt.aggregate {
+		names := make([]string, len(q.stmt.items))
+		values := make([]interface{}, len(q.stmt.items))
+		for i, item := range q.stmt.items {
+			names[i] = itemName(item, i)
+			values[i] = item.agg.result()
+		}
+		res.add(names, values)
+	}
+
+	if err := res.flush(); err != nil {
+		return err
+	}
+	if err := w.WriteStats(stats()); err != nil {
+		return err
+	}
+
+	return w.WriteEnd()
+}
+
+func (q *Query) decompress(r io.Reader) (*countingReader, error) {
+	switch q.input.CompressionType {
+	case compressionGzip:
+		gzipReader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidCompressionFormat, err)
+		}
+		return &countingReader{r: gzipReader}, nil
+	case compressionBzip2:
+		return &countingReader{r: bzip2.NewReader(r)}, nil
+	default:
+		return &countingReader{r: r}, nil
+	}
+}
+
+func (q *Query) newRecordReader(r io.Reader) (recordReader, error) {
+	if q.input.CSV != nil {
+		return newCSVRecordReader(r, q.input.CSV)
+	}
+
+	// JSON lines are processed by the same decoder, because it reads a sequence of values
+	return newJSONRecordReader(r, q.stmt.expandArray), nil
+}
+
+// project returns names and values of the output record.
+func (q *Query) project(rec record) ([]string, []interface{}, error) {
+	if len(q.stmt.items) == 0 {
+		names, values := rec.columns()
+		return names, values, nil
+	}
+
+	names := make([]string, len(q.stmt.items))
+	values := make([]interface{}, len(q.stmt.items))
+	for i, item := range q.stmt.items {
+		value, err := item.expr.eval(rec)
+		if err != nil {
+			return nil, nil, err
+		}
+		names[i] = itemName(item, i)
+		values[i] = value
+	}
+
+	return names, values, nil
+}
+
+// itemName returns the name of the select list item: its alias, the name of the column or
+// a positional name like _1.
+func itemName(item *selectItem, i int) string {
+	if item.alias != "" {
+		return item.alias
+	}
+	if column, ok := item.expr.(*columnExpr); ok {
+		if name := column.name(); name != "" {
+			return name
+		}
+	}
+	return "_" + strconv.Itoa(i+1)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if err != nil && err != io.EOF {
+		c.err = err
+	}
+	return n, err
+}
+
+// errOr returns the error of the underlying reader if any, because parsers
+// can't distinguish it from malformed payload.
+func (c *countingReader) errOr(err error) error {
+	if err != nil && c.err != nil {
+		return c.err
+	}
+	return err
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.n += int64(n)
+	return n, err
+}
+
+func (r *resultWriter) add(names []string, values []interface{}) {
+	if r.output.CSV != nil {
+		r.addCSV(values)
+		return
+	}
+
+	obj := &jsonObject{values: make(map[string]interface{}, len(names))}
+	for i, name := range names {
+		// missing values are omitted
+		if values[i] == nil {
+			continue
+		}
+		if _, ok := obj.values[name]; !ok {
+			obj.keys = append(obj.keys, name)
+		}
+		obj.values[name] = values[i]
+	}
+	r.buf.Write(encodeJSONValue(nil, obj))
+	r.buf.WriteString(r.output.JSON.RecordDelimiter)
+}
+
+func (r *resultWriter) addCSV(values []interface{}) {
+	cfg := r.output.CSV
+	for i, value := range values {
+		if i != 0 {
+			r.buf.WriteString(cfg.FieldDelimiter)
+		}
+
+		field := toString(value)
+		if cfg.QuoteFields != quoteFieldsAlways && !strings.ContainsAny(field, cfg.FieldDelimiter+cfg.QuoteCharacter+"\r\n") {
+			r.buf.WriteString(field)
+			continue
+		}
+
+		r.buf.WriteString(cfg.QuoteCharacter)
+		r.buf.WriteString(strings.ReplaceAll(field, cfg.QuoteCharacter, cfg.QuoteEscapeCharacter+cfg.QuoteCharacter))
+		r.buf.WriteString(cfg.QuoteCharacter)
+	}
+	r.buf.WriteString(cfg.RecordDelimiter)
+}
+
+func (r *resultWriter) flush() error {
+	if r.buf.Len() == 0 {
+		return nil
+	}
+
+	r.returned += int64(r.buf.Len())
+	err := r.events.WriteRecords(r.buf.Bytes())
+	r.buf.Reset()
+	if err != nil {
+		return fmt.Errorf("write records: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package s3select
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	csvPayload := "id,name,score\n1,alice,7.5\n2,bob,\n3,\"o'neil, pat\",9\n"
+	jsonPayload := `[{"id":1,"user":{"name":"alice","langs":["go","c"]}},{"id":2,"user":{"name":"bob"}}]`
+
+	csvInput := &InputSerialization{CSV: &CSVInput{FileHeaderInfo: "USE"}}
+	jsonInput := &InputSerialization{JSON: &JSONInput{Type: "DOCUMENT"}}
+	csvOutput := &OutputSerialization{CSV: &CSVOutput{}}
+	jsonOutput := &OutputSerialization{JSON: &JSONOutput{}}
+
+	for _, tc := range []struct {
+		expression string
+		payload    string
+		input      *InputSerialization
+		output     *OutputSerialization
+		expected   string
+	}{
+		{"SELECT * FROM S3Object", csvPayload, csvInput, csvOutput, "1,alice,7.5\n2,bob,\n3,\"o'neil, pat\",9\n"},
+		{"SELECT _2 FROM S3Object LIMIT 2", csvPayload, &InputSerialization{CSV: &CSVInput{FileHeaderInfo: "IGNORE"}}, csvOutput, "alice\nbob\n"},
+		{"SELECT name FROM S3Object WHERE score IS NOT NULL AND score <> ''", csvPayload, csvInput, csvOutput, "alice\n\"o'neil, pat\"\n"},
+		{"SELECT id FROM S3Object WHERE CAST(id AS INT) BETWEEN 2 AND 3", csvPayload, csvInput, jsonOutput, "{\"id\":\"2\"}\n{\"id\":\"3\"}\n"},
+		{"SELECT id FROM S3Object WHERE name IN ('bob', 'carol')", csvPayload, csvInput, csvOutput, "2\n"},
+		{"SELECT SUM(CAST(score AS FLOAT)) AS total, AVG(CAST(id AS INT)) FROM S3Object WHERE score <> ''", csvPayload, csvInput, jsonOutput, "{\"total\":16.5,\"_2\":2}\n"},
+		{"SELECT s.user.name, s.user.langs[1] FROM S3Object[*] s", jsonPayload, jsonInput, jsonOutput, "{\"name\":\"alice\",\"_2\":\"c\"}\n{\"name\":\"bob\"}\n"},
+		{"SELECT * FROM S3Object[*] s WHERE s.id * 2 = 4", jsonPayload, jsonInput, jsonOutput, "{\"id\":2,\"user\":{\"name\":\"bob\"}}\n"},
+		{"SELECT COUNT(*) FROM S3Object[*] WHERE user.langs IS MISSING", jsonPayload, jsonInput, csvOutput, "1\n"},
+	} {
+		t.Run(tc.expression, func(t *testing.T) {
+			require.Equal(t, tc.expected, runQuery(t, tc.expression, tc.input, tc.output, tc.payload))
+		})
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(csvPayload))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		input := &InputSerialization{CompressionType: "GZIP", CSV: &CSVInput{FileHeaderInfo: "USE"}}
+		require.Equal(t, "3\n", runQuery(t, "SELECT COUNT(*) FROM S3Object", input, csvOutput, buf.String()))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, expression := range []string{
+			"SELECT FROM S3Object",
+			"SELECT * FROM S3Object WHERE",
+			"SELECT COUNT(*), name FROM S3Object",
+			"SELECT * FROM S3Object LIMIT x",
+			"SELECT 'unterminated FROM S3Object",
+		} {
+			_, err := NewQuery(expression, csvInput, csvOutput)
+			require.Error(t, err, expression)
+		}
+
+		_, err := NewQuery("SELECT * FROM S3Object", &InputSerialization{CSV: &CSVInput{}, JSON: &JSONInput{Type: "LINES"}}, csvOutput)
+		require.Error(t, err)
+		_, err = NewQuery("SELECT * FROM S3Object", &InputSerialization{CompressionType: "ZSTD", CSV: &CSVInput{}}, csvOutput)
+		require.Error(t, err)
+	})
+}
+
+func runQuery(t *testing.T, expression string, input *InputSerialization, output *OutputSerialization, payload string) string {
+	q, err := NewQuery(expression, input, output)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, q.Run(strings.NewReader(payload), NewEventWriter(&out), false))
+
+	return recordsPayload(out.Bytes())
+}
+
+// recordsPayload returns concatenated payloads of Records events.
+func recordsPayload(data []byte) string {
+	var res bytes.Buffer
+	for len(data) > 0 {
+		totalLen := int(binary.BigEndian.Uint32(data))
+		headersLen := int(binary.BigEndian.Uint32(data[4:]))
+		if bytes.Contains(data[12:12+headersLen], []byte("Records")) {
+			res.Write(data[12+headersLen : totalLen-4])
+		}
+		data = data[totalLen:]
+	}
+
+	return res.String()
+}
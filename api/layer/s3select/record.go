@@ -0,0 +1,355 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type (
+	// record is a row of the input object.
+	record interface {
+		// get returns the value by the path, nil is returned for missing values.
+		get(path []pathElem) interface{}
+		// columns returns names and values of all top-level columns for SELECT *.
+		columns() ([]string, []interface{})
+	}
+
+	recordReader interface {
+		// read returns io.EOF if there are no more records.
+		read() (record, error)
+	}
+
+	csvHeader struct {
+		names []string
+		index map[string]int
+		// foldIndex is used for case-insensitive lookup of not quoted column names.
+		foldIndex map[string]int
+	}
+
+	csvRecord struct {
+		fields []string
+		header *csvHeader
+	}
+
+	csvRecordReader struct {
+		r      *csv.Reader
+		header *csvHeader
+	}
+
+	// jsonObject is a JSON object with the order of keys preserved.
+	jsonObject struct {
+		keys   []string
+		values map[string]interface{}
+	}
+
+	jsonRecord struct {
+		value interface{}
+	}
+
+	jsonRecordReader struct {
+		dec         *json.Decoder
+		expandArray bool
+		pending     []interface{}
+	}
+
+	// ParsingError is an error of the object payload parsing.
+	ParsingError struct {
+		Format string
+		Err    error
+	}
+)
+
+func (e *ParsingError) Error() string {
+	return fmt.Sprintf("%s parsing error: %s", e.Format, e.Err)
+}
+
+// Code returns S3 error code of the parsing error.
+func (e *ParsingError) Code() string {
+	return e.Format + "ParsingError"
+}
+
+func newCSVRecordReader(r io.Reader, cfg *CSVInput) (*csvRecordReader, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	csvReader.ReuseRecord = true
+	csvReader.Comma = []rune(cfg.FieldDelimiter)[0]
+	if len(cfg.Comments) != 0 {
+		csvReader.Comment = []rune(cfg.Comments)[0]
+	}
+
+	reader := &csvRecordReader{r: csvReader}
+
+	switch strings.ToUpper(cfg.FileHeaderInfo) {
+	case fileHeaderUse, fileHeaderIgnore:
+		fields, err := csvReader.Read()
+		if err == io.EOF {
+			return reader, nil
+		}
+		if err != nil {
+			return nil, &ParsingError{Format: "CSV", Err: err}
+		}
+		if strings.EqualFold(cfg.FileHeaderInfo, fileHeaderUse) {
+			reader.header = newCSVHeader(fields)
+		}
+	}
+
+	return reader, nil
+}
+
+func newCSVHeader(fields []string) *csvHeader {
+	header := &csvHeader{
+		names:     make([]string, len(fields)),
+		index:     make(map[string]int, len(fields)),
+		foldIndex: make(map[string]int, len(fields)),
+	}
+	for i, name := range fields {
+		header.names[i] = name
+		if _, ok := header.index[name]; !ok {
+			header.index[name] = i
+		}
+		if _, ok := header.foldIndex[strings.ToLower(name)]; !ok {
+			header.foldIndex[strings.ToLower(name)] = i
+		}
+	}
+	return header
+}
+
+func (c *csvRecordReader) read() (record, error) {
+	fields, err := c.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, &ParsingError{Format: "CSV", Err: err}
+	}
+
+	return &csvRecord{fields: fields, header: c.header}, nil
+}
+
+func (c *csvRecord) get(path []pathElem) interface{} {
+	if len(path) != 1 || path[0].index >= 0 {
+		return nil
+	}
+	name := path[0].name
+
+	index := -1
+	if c.header != nil {
+		if i, ok := c.header.index[name]; ok {
+			index = i
+		} else if i, ok = c.header.foldIndex[strings.ToLower(name)]; ok && !path[0].quoted {
+			index = i
+		}
+	}
+	if index < 0 && strings.HasPrefix(name, "_") {
+		if i, err := strconv.Atoi(name[1:]); err == nil {
+			index = i - 1
+		}
+	}
+
+	if index < 0 || index >= len(c.fields) {
+		return nil
+	}
+	return c.fields[index]
+}
+
+func (c *csvRecord) columns() ([]string, []interface{}) {
+	names := make([]string, len(c.fields))
+	values := make([]interface{}, len(c.fields))
+	for i, field := range c.fields {
+		if c.header != nil && i < len(c.header.names) {
+			names[i] = c.header.names[i]
+		} else {
+			names[i] = "_" + strconv.Itoa(i+1)
+		}
+		values[i] = field
+	}
+	return names, values
+}
+
+func newJSONRecordReader(r io.Reader, expandArray bool) *jsonRecordReader {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &jsonRecordReader{dec: dec, expandArray: expandArray}
+}
+
+func (j *jsonRecordReader) read() (record, error) {
+	for len(j.pending) == 0 {
+		value, err := decodeJSONValue(j.dec)
+		if err != nil {
+			if err == io.EOF {
+				return nil, err
+			}
+			return nil, &ParsingError{Format: "JSON", Err: err}
+		}
+
+		if arr, ok := value.([]interface{}); ok && j.expandArray {
+			j.pending = arr
+			continue
+		}
+		return &jsonRecord{value: value}, nil
+	}
+
+	value := j.pending[0]
+	j.pending = j.pending[1:]
+	return &jsonRecord{value: value}, nil
+}
+
+func (j *jsonRecord) get(path []pathElem) interface{} {
+	value := j.value
+	for _, elem := range path {
+		if elem.index >= 0 {
+			arr, ok := value.([]interface{})
+			if !ok || elem.index >= len(arr) {
+				return nil
+			}
+			value = arr[elem.index]
+			continue
+		}
+
+		obj, ok := value.(*jsonObject)
+		if !ok {
+			return nil
+		}
+		if value, ok = obj.values[elem.name]; !ok {
+			if elem.quoted {
+				return nil
+			}
+			value = obj.getFold(elem.name)
+		}
+	}
+
+	return value
+}
+
+func (j *jsonRecord) columns() ([]string, []interface{}) {
+	if obj, ok := j.value.(*jsonObject); ok {
+		values := make([]interface{}, len(obj.keys))
+		for i, key := range obj.keys {
+			values[i] = obj.values[key]
+		}
+		return obj.keys, values
+	}
+	return []string{"_1"}, []interface{}{j.value}
+}
+
+func (o *jsonObject) getFold(name string) interface{} {
+	for _, key := range o.keys {
+		if strings.EqualFold(key, name) {
+			return o.values[key]
+		}
+	}
+	return nil
+}
+
+// decodeJSONValue decodes the next JSON value, objects are decoded with the order of keys preserved
+// and numbers are decoded as float64.
+func decodeJSONValue(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := t.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			obj := &jsonObject{values: make(map[string]interface{})}
+			for dec.More() {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+				key, _ := keyToken.(string)
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+				if _, ok := obj.values[key]; !ok {
+					obj.keys = append(obj.keys, key)
+				}
+				obj.values[key] = value
+			}
+			if _, err = dec.Token(); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			return obj, nil
+		case '[':
+			arr := make([]interface{}, 0)
+			for dec.More() {
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+				arr = append(arr, value)
+			}
+			if _, err = dec.Token(); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			return arr, nil
+		default:
+			return nil, fmt.Errorf("unexpected delimiter '%s'", v)
+		}
+	case json.Number:
+		return v.Float64()
+	default:
+		return v, nil
+	}
+}
+
+// unexpectedEOF makes EOF inside the JSON value an error.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// encodeJSONValue appends JSON encoding of the value to the buffer.
+func encodeJSONValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case bool:
+		return strconv.AppendBool(buf, v)
+	case float64:
+		return strconv.AppendFloat(buf, v, 'f', -1, 64)
+	case string:
+		return appendJSONString(buf, v)
+	case *jsonObject:
+		buf = append(buf, '{')
+		for i, key := range v.keys {
+			if i != 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, key)
+			buf = append(buf, ':')
+			buf = encodeJSONValue(buf, v.values[key])
+		}
+		return append(buf, '}')
+	case []interface{}:
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i != 0 {
+				buf = append(buf, ',')
+			}
+			buf = encodeJSONValue(buf, item)
+		}
+		return append(buf, ']')
+	default:
+		return appendJSONString(buf, fmt.Sprint(v))
+	}
+}
+
+func appendJSONString(buf []byte, s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return append(buf, bytes.TrimSuffix(b.Bytes(), []byte("\n"))...)
+}
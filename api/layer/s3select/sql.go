@@ -0,0 +1,680 @@
+package s3select
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	typ   tokenType
+	value string
+}
+
+// is checks if the token is the keyword or the operator, keywords are case-insensitive.
+func (t token) is(value string) bool {
+	switch t.typ {
+	case tokenIdent:
+		return strings.EqualFold(t.value, value)
+	case tokenOperator:
+		return t.value == value
+	default:
+		return false
+	}
+}
+
+var reservedWords = map[string]struct{}{
+	"SELECT": {}, "FROM": {}, "WHERE": {}, "LIMIT": {}, "AS": {}, "AND": {}, "OR": {}, "NOT": {},
+	"LIKE": {}, "IN": {}, "BETWEEN": {}, "IS": {}, "NULL": {}, "MISSING": {}, "TRUE": {}, "FALSE": {},
+}
+
+func isReserved(t token) bool {
+	if t.typ != tokenIdent {
+		return false
+	}
+	_, ok := reservedWords[strings.ToUpper(t.value)]
+	return ok
+}
+
+func lex(expression string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(expression)
+	)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenIdent, value: string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenNumber, value: string(runes[start:i])})
+		case r == '\'' || r == '"':
+			value, next, err := lexQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			typ := tokenString
+			if r == '"' {
+				typ = tokenQuotedIdent
+			}
+			tokens = append(tokens, token{typ: typ, value: value})
+			i = next
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				if two := string(runes[i : i+2]); two == "<=" || two == ">=" || two == "<>" || two == "!=" {
+					op = two
+				}
+			}
+			if !strings.Contains("=<>!+-*/%(),.[]", op[:1]) || op == "!" {
+				return nil, errors.GetAPIErrorWithError(errors.ErrLexerInvalidChar, fmt.Errorf("unexpected character '%c'", r))
+			}
+			tokens = append(tokens, token{typ: tokenOperator, value: op})
+			i += len([]rune(op))
+		}
+	}
+
+	return append(tokens, token{typ: tokenEOF}), nil
+}
+
+// lexQuoted reads the quoted literal, the quote inside literal is escaped by doubling.
+func lexQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			sb.WriteRune(quote)
+			i++
+			continue
+		}
+		return sb.String(), i + 1, nil
+	}
+
+	return "", 0, errors.GetAPIErrorWithError(errors.ErrLexerInvalidLiteral, fmt.Errorf("unterminated literal"))
+}
+
+type (
+	// selectStatement is a parsed SQL expression of SelectObjectContent request.
+	selectStatement struct {
+		// items is empty for SELECT *.
+		items []*selectItem
+		// aggregate is set if all items are aggregate functions.
+		aggregate bool
+		// expandArray makes top-level JSON arrays be processed as sequences of records (FROM S3Object[*]).
+		expandArray bool
+		where       expr
+		limit       int64
+	}
+
+	selectItem struct {
+		expr  expr
+		alias string
+		agg   *aggregateFunc
+	}
+
+	parser struct {
+		tokens []token
+		pos    int
+		// alias is a name of the table in the FROM clause.
+		alias string
+	}
+)
+
+func parseSelect(expression string) (*selectStatement, error) {
+	tokens, err := lex(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	// the table alias is needed to parse column references of the select list, so FROM clause is parsed first
+	p := &parser{tokens: tokens}
+	fromPos := -1
+	for i, t := range tokens {
+		if t.is("FROM") {
+			fromPos = i
+			break
+		}
+	}
+	if fromPos < 0 {
+		return nil, errors.GetAPIError(errors.ErrParseSelectMissingFrom)
+	}
+
+	stmt := &selectStatement{limit: -1}
+
+	p.pos = fromPos + 1
+	if err = p.parseFrom(stmt); err != nil {
+		return nil, err
+	}
+	if p.peek().is("WHERE") {
+		p.next()
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek().is("LIMIT") {
+		p.next()
+		t := p.next()
+		if t.typ != tokenNumber {
+			return nil, errors.GetAPIError(errors.ErrParseExpectedNumber)
+		}
+		if stmt.limit, err = strconv.ParseInt(t.value, 10, 64); err != nil || stmt.limit < 0 {
+			return nil, errors.GetAPIError(errors.ErrParseExpectedNumber)
+		}
+	}
+	if t := p.next(); t.typ != tokenEOF {
+		return nil, errors.GetAPIErrorWithError(errors.ErrParseUnexpectedToken, fmt.Errorf("unexpected '%s'", t.value))
+	}
+
+	p.tokens, p.pos = append(tokens[:fromPos:fromPos], token{typ: tokenEOF}), 0
+	if err = p.parseSelectList(stmt); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) expect(value string) error {
+	if t := p.next(); !t.is(value) {
+		return errors.GetAPIErrorWithError(errors.ErrParseExpectedTokenType, fmt.Errorf("expected '%s', got '%s'", value, t.value))
+	}
+	return nil
+}
+
+func (p *parser) parseFrom(stmt *selectStatement) error {
+	if t := p.next(); !t.is("S3Object") {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidDataSource, fmt.Errorf("unexpected table '%s'", t.value))
+	}
+
+	if p.peek().is("[") {
+		p.next()
+		if err := p.expect("*"); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+		stmt.expandArray = true
+	}
+	if p.peek().is(".") {
+		return errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure, fmt.Errorf("paths in FROM clause are not supported"))
+	}
+
+	if p.peek().is("AS") {
+		p.next()
+	}
+	if t := p.peek(); (t.typ == tokenIdent || t.typ == tokenQuotedIdent) && !isReserved(t) {
+		p.alias = p.next().value
+	}
+
+	return nil
+}
+
+func (p *parser) parseSelectList(stmt *selectStatement) error {
+	if err := p.expect("SELECT"); err != nil {
+		return err
+	}
+
+	if p.peek().is("*") {
+		p.next()
+		if t := p.next(); t.typ != tokenEOF {
+			return errors.GetAPIError(errors.ErrParseAsteriskIsNotAloneInSelectList)
+		}
+		return nil
+	}
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return err
+		}
+		stmt.items = append(stmt.items, item)
+
+		t := p.next()
+		if t.typ == tokenEOF {
+			break
+		}
+		if !t.is(",") {
+			return errors.GetAPIErrorWithError(errors.ErrParseUnexpectedToken, fmt.Errorf("unexpected '%s'", t.value))
+		}
+	}
+
+	if len(stmt.items) == 0 {
+		return errors.GetAPIError(errors.ErrParseEmptySelect)
+	}
+
+	aggregates := 0
+	for _, item := range stmt.items {
+		if item.agg != nil {
+			aggregates++
+		}
+	}
+	if aggregates != 0 && aggregates != len(stmt.items) {
+		return errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure, fmt.Errorf("aggregate functions can't be mixed with other expressions"))
+	}
+	stmt.aggregate = aggregates != 0
+
+	return nil
+}
+
+func (p *parser) parseSelectItem() (*selectItem, error) {
+	item := new(selectItem)
+
+	if t := p.peek(); t.typ == tokenIdent && p.tokens[p.pos+1].is("(") {
+		if name := strings.ToUpper(t.value); isAggregate(name) {
+			p.pos += 2
+			item.agg = &aggregateFunc{name: name}
+			if p.peek().is("*") {
+				if name != "COUNT" {
+					return nil, errors.GetAPIError(errors.ErrParseUnsupportedCallWithStar)
+				}
+				p.next()
+			} else {
+				arg, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				item.agg.arg = arg
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if item.agg == nil {
+		var err error
+		if item.expr, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek().is("AS") {
+		p.next()
+		t := p.next()
+		if t.typ != tokenIdent && t.typ != tokenQuotedIdent {
+			return nil, errors.GetAPIError(errors.ErrParseExpectedIdentForAlias)
+		}
+		item.alias = t.value
+	} else if t := p.peek(); (t.typ == tokenIdent || t.typ == tokenQuotedIdent) && !isReserved(t) {
+		item.alias = p.next().value
+	}
+
+	return item, nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.peek().is("NOT") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.is("=") || t.is("!=") || t.is("<>") || t.is("<") || t.is(">") || t.is("<=") || t.is(">="):
+		p.next()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &compareExpr{op: t.value, left: left, right: right}, nil
+	case t.is("IS"):
+		p.next()
+		negate := false
+		if p.peek().is("NOT") {
+			p.next()
+			negate = true
+		}
+		if t := p.next(); !t.is("NULL") && !t.is("MISSING") {
+			return nil, errors.GetAPIErrorWithError(errors.ErrParseExpectedKeyword, fmt.Errorf("expected NULL or MISSING"))
+		}
+		return &isNullExpr{operand: left, negate: negate}, nil
+	}
+
+	negate := false
+	if t.is("NOT") {
+		if next := p.tokens[p.pos+1]; next.is("LIKE") || next.is("IN") || next.is("BETWEEN") {
+			p.next()
+			negate = true
+		}
+	}
+
+	var result expr
+	switch t := p.peek(); {
+	case t.is("LIKE"):
+		p.next()
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		result = &likeExpr{operand: left, pattern: pattern}
+	case t.is("BETWEEN"):
+		p.next()
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		result = &logicalExpr{op: "AND",
+			left:  &compareExpr{op: ">=", left: left, right: low},
+			right: &compareExpr{op: "<=", left: left, right: high},
+		}
+	case t.is("IN"):
+		p.next()
+		list, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		result = &inExpr{operand: left, list: list}
+	default:
+		return left, nil
+	}
+
+	if negate {
+		result = &notExpr{operand: result}
+	}
+	return result, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.is("+") || t.is("-"); t = p.peek() {
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpr{op: t.value, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.is("*") || t.is("/") || t.is("%"); t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpr{op: t.value, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.peek().is("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &arithmeticExpr{op: "-", left: literalExpr{value: float64(0)}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.typ {
+	case tokenNumber:
+		number, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, errors.GetAPIErrorWithError(errors.ErrLexerInvalidLiteral, fmt.Errorf("invalid number '%s'", t.value))
+		}
+		return literalExpr{value: number}, nil
+	case tokenString:
+		return literalExpr{value: t.value}, nil
+	case tokenOperator:
+		if t.is("(") {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	case tokenIdent, tokenQuotedIdent:
+		if t.typ == tokenIdent {
+			switch strings.ToUpper(t.value) {
+			case "NULL", "MISSING":
+				return literalExpr{}, nil
+			case "TRUE":
+				return literalExpr{value: true}, nil
+			case "FALSE":
+				return literalExpr{value: false}, nil
+			case "CAST":
+				return p.parseCast()
+			}
+			if p.peek().is("(") {
+				return p.parseFunction(t.value)
+			}
+			if isReserved(t) {
+				break
+			}
+		}
+		p.pos--
+		return p.parseColumn()
+	case tokenEOF:
+		return nil, errors.GetAPIError(errors.ErrParseExpectedExpression)
+	}
+
+	return nil, errors.GetAPIErrorWithError(errors.ErrParseUnexpectedTerm, fmt.Errorf("unexpected '%s'", t.value))
+}
+
+func (p *parser) parseColumn() (expr, error) {
+	col := &columnExpr{}
+
+	first := true
+	for {
+		t := p.next()
+		if t.typ != tokenIdent && t.typ != tokenQuotedIdent {
+			return nil, errors.GetAPIErrorWithError(errors.ErrInvalidKeyPath, fmt.Errorf("unexpected '%s'", t.value))
+		}
+		// table alias is optional in column references
+		if !(first && p.peek().is(".") && (strings.EqualFold(t.value, p.alias) || strings.EqualFold(t.value, "S3Object"))) {
+			col.path = append(col.path, pathElem{name: t.value, quoted: t.typ == tokenQuotedIdent, index: -1})
+		}
+		first = false
+
+		for p.peek().is("[") {
+			p.next()
+			t = p.next()
+			index, err := strconv.Atoi(t.value)
+			if t.typ != tokenNumber || err != nil || index < 0 {
+				return nil, errors.GetAPIErrorWithError(errors.ErrInvalidKeyPath, fmt.Errorf("invalid index '%s'", t.value))
+			}
+			if err = p.expect("]"); err != nil {
+				return nil, err
+			}
+			col.path = append(col.path, pathElem{index: index})
+		}
+
+		if !p.peek().is(".") {
+			break
+		}
+		p.next()
+	}
+
+	if len(col.path) == 0 {
+		return nil, errors.GetAPIError(errors.ErrInvalidKeyPath)
+	}
+
+	return col, nil
+}
+
+func (p *parser) parseCast() (expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, errors.GetAPIError(errors.ErrParseExpectedLeftParenAfterCast)
+	}
+	operand, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect("AS"); err != nil {
+		return nil, err
+	}
+	t := p.next()
+	typeName := strings.ToUpper(t.value)
+	if _, ok := castTypes[typeName]; !ok || t.typ != tokenIdent {
+		return nil, errors.GetAPIErrorWithError(errors.ErrParseExpectedTypeName, fmt.Errorf("unsupported type '%s'", t.value))
+	}
+	if err = p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return &castExpr{operand: operand, typeName: typeName}, nil
+}
+
+func (p *parser) parseFunction(name string) (expr, error) {
+	name = strings.ToUpper(name)
+	if isAggregate(name) {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedSQLStructure, fmt.Errorf("aggregate function %s must be a select list item", name))
+	}
+	f, ok := functions[name]
+	if !ok {
+		return nil, errors.GetAPIErrorWithError(errors.ErrUnsupportedFunction, fmt.Errorf("unknown function '%s'", name))
+	}
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < f.minArgs || (f.maxArgs >= 0 && len(args) > f.maxArgs) {
+		return nil, errors.GetAPIErrorWithError(errors.ErrEvaluatorInvalidArguments, fmt.Errorf("invalid number of arguments of %s", name))
+	}
+
+	return &funcExpr{name: name, f: f.call, args: args}, nil
+}
+
+// parseArgs parses parenthesized comma-separated list of expressions.
+func (p *parser) parseArgs() ([]expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []expr
+	if p.peek().is(")") {
+		p.next()
+		return args, nil
+	}
+
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		t := p.next()
+		if t.is(")") {
+			return args, nil
+		}
+		if !t.is(",") {
+			return nil, errors.GetAPIError(errors.ErrParseExpectedArgumentDelimiter)
+		}
+	}
+}
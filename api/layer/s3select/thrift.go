@@ -0,0 +1,294 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Thrift compact protocol types, see https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md.
+const (
+	thriftStop         = 0
+	thriftBooleanTrue  = 1
+	thriftBooleanFalse = 2
+	thriftByte         = 3
+	thriftI16          = 4
+	thriftI32          = 5
+	thriftI64          = 6
+	thriftDouble       = 7
+	thriftBinary       = 8
+	thriftList         = 9
+	thriftSet          = 10
+	thriftMap          = 11
+	thriftStructure    = 12
+
+	// thriftMaxDepth limits nesting of decoded structures.
+	thriftMaxDepth = 64
+)
+
+type (
+	// thriftStruct is a decoded struct, values are bool, int64, float64, []byte, []interface{}
+	// and thriftStruct, maps are skipped.
+	thriftStruct map[int16]interface{}
+
+	// thriftReader decodes Parquet metadata in the thrift compact protocol without generated code.
+	thriftReader struct {
+		buf   []byte
+		pos   int
+		depth int
+	}
+
+	// thriftWriter encodes Parquet metadata in the thrift compact protocol without generated code.
+	thriftWriter struct {
+		bytes.Buffer
+		ids []int16
+	}
+)
+
+var errThriftTruncated = errors.New("truncated thrift data")
+
+func (s thriftStruct) int(id int16) int64 {
+	v, _ := s[id].(int64)
+	return v
+}
+
+func (s thriftStruct) str(id int16) string {
+	v, _ := s[id].([]byte)
+	return string(v)
+}
+
+func (s thriftStruct) list(id int16) []interface{} {
+	v, _ := s[id].([]interface{})
+	return v
+}
+
+func (s thriftStruct) strct(id int16) thriftStruct {
+	v, _ := s[id].(thriftStruct)
+	return v
+}
+
+func (t *thriftReader) readByte() (byte, error) {
+	if t.pos >= len(t.buf) {
+		return 0, errThriftTruncated
+	}
+	b := t.buf[t.pos]
+	t.pos++
+	return b, nil
+}
+
+func (t *thriftReader) readUvarint() (uint64, error) {
+	v, k := binary.Uvarint(t.buf[t.pos:])
+	if k <= 0 {
+		return 0, errThriftTruncated
+	}
+	t.pos += k
+	return v, nil
+}
+
+func (t *thriftReader) readZigzag() (int64, error) {
+	v, err := t.readUvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (t *thriftReader) readStruct() (thriftStruct, error) {
+	if t.depth++; t.depth > thriftMaxDepth {
+		return nil, errors.New("too deep thrift structure")
+	}
+	defer func() { t.depth-- }()
+
+	s := make(thriftStruct)
+	var id int16
+	for {
+		b, err := t.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		typ := b & 0x0f
+		if typ == thriftStop {
+			return s, nil
+		}
+		if delta := int16(b >> 4); delta != 0 {
+			id += delta
+		} else {
+			fieldID, err := t.readZigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(fieldID)
+		}
+
+		switch typ {
+		case thriftBooleanTrue:
+			s[id] = true
+		case thriftBooleanFalse:
+			s[id] = false
+		default:
+			if s[id], err = t.readValue(typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// readValue reads the value of the type, booleans are encoded as a byte in collections.
+func (t *thriftReader) readValue(typ byte) (interface{}, error) {
+	switch typ {
+	case thriftBooleanTrue, thriftBooleanFalse:
+		b, err := t.readByte()
+		return b == thriftBooleanTrue, err
+	case thriftByte:
+		b, err := t.readByte()
+		return int64(int8(b)), err
+	case thriftI16, thriftI32, thriftI64:
+		return t.readZigzag()
+	case thriftDouble:
+		if t.pos+8 > len(t.buf) {
+			return nil, errThriftTruncated
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(t.buf[t.pos:]))
+		t.pos += 8
+		return v, nil
+	case thriftBinary:
+		size, err := t.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if size > uint64(len(t.buf)-t.pos) {
+			return nil, errThriftTruncated
+		}
+		v := t.buf[t.pos : t.pos+int(size)]
+		t.pos += int(size)
+		return v, nil
+	case thriftList, thriftSet:
+		return t.readList()
+	case thriftMap:
+		return nil, t.skipMap()
+	case thriftStructure:
+		return t.readStruct()
+	default:
+		return nil, errors.New("unknown thrift type")
+	}
+}
+
+func (t *thriftReader) readList() ([]interface{}, error) {
+	b, err := t.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	size := uint64(b >> 4)
+	if size == 15 {
+		if size, err = t.readUvarint(); err != nil {
+			return nil, err
+		}
+	}
+	// every element takes at least one byte
+	if size > uint64(len(t.buf)-t.pos) {
+		return nil, errThriftTruncated
+	}
+
+	list := make([]interface{}, size)
+	for i := range list {
+		if list[i], err = t.readValue(b & 0x0f); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (t *thriftReader) skipMap() error {
+	size, err := t.readUvarint()
+	if err != nil || size == 0 {
+		return err
+	}
+	if size > uint64(len(t.buf)-t.pos) {
+		return errThriftTruncated
+	}
+
+	types, err := t.readByte()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < size; i++ {
+		if _, err = t.readValue(types >> 4); err != nil {
+			return err
+		}
+		if _, err = t.readValue(types & 0x0f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	last := &w.ids[len(w.ids)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+// varint writes the zigzag encoded value, it's also used for i32 and i64 list elements.
+func (w *thriftWriter) varint(v int64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	w.Write(buf[:binary.PutUvarint(buf, uint64(v<<1^v>>63))])
+}
+
+func (w *thriftWriter) int(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) int32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) bool(id int16, v bool) {
+	if v {
+		w.field(id, thriftBooleanTrue)
+	} else {
+		w.field(id, thriftBooleanFalse)
+	}
+}
+
+func (w *thriftWriter) binary(s string) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	w.Write(buf[:binary.PutUvarint(buf, uint64(len(s)))])
+	w.WriteString(s)
+}
+
+func (w *thriftWriter) str(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.binary(s)
+}
+
+// strct writes the struct field, id is ignored for top-level structs and list elements.
+func (w *thriftWriter) strct(id int16, fields func()) {
+	if id != 0 {
+		w.field(id, thriftStructure)
+	}
+	w.ids = append(w.ids, 0)
+	fields()
+	w.WriteByte(thriftStop)
+	w.ids = w.ids[:len(w.ids)-1]
+}
+
+func (w *thriftWriter) list(id int16, typ byte, size int, elements func()) {
+	w.field(id, thriftList)
+	if size < 15 {
+		w.WriteByte(byte(size)<<4 | typ)
+	} else {
+		w.WriteByte(0xf0 | typ)
+		buf := make([]byte, binary.MaxVarintLen64)
+		w.Write(buf[:binary.PutUvarint(buf, uint64(size))])
+	}
+	elements()
+}
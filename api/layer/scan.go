@@ -0,0 +1,143 @@
+package layer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+)
+
+// defaultScanWorkers is the number of concurrently scanned shards if it's not set in scanObjectsParams.
+const defaultScanWorkers = 16
+
+// scanObjectsParams stores parameters of the full bucket scan.
+type scanObjectsParams struct {
+	BktInfo *data.BucketInfo
+	Prefix  string
+	// AllVersions makes the scan return all object versions and delete markers, not only the latest versions.
+	AllVersions bool
+	// Workers is the number of concurrently scanned shards.
+	Workers int
+}
+
+// scanObjects scans all objects of the bucket with the prefix in arbitrary order and passes them to handler.
+// Keyspace is split into shards by ranges of the first-level names after the prefix and shards are scanned
+// concurrently, all versions of an object belong to the same shard. Handler is never called concurrently, the scan stops
+// on the first handler error.
+func (n *layer) scanObjects(ctx context.Context, p *scanObjectsParams, handler func(*data.ExtendedObjectInfo) error) error {
+	latestOnly := !p.AllVersions
+
+	shards, err := n.treeService.GetVersionsShards(ctx, p.BktInfo, p.Prefix, latestOnly)
+	if err != nil {
+		return fmt.Errorf("get versions shards: %w", err)
+	}
+
+	workers := p.Workers
+	if workers <= 0 {
+		workers = defaultScanWorkers
+	}
+
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		scanErr error
+		shardCh = make(chan *data.VersionsShard)
+	)
+
+	syncHandler := func(extObjInfo *data.ExtendedObjectInfo) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if scanErr != nil {
+			return scanErr
+		}
+		if err := handler(extObjInfo); err != nil {
+			scanErr = err
+			return err
+		}
+		return nil
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range shardCh {
+				if err := n.scanShard(scanCtx, p, shard, syncHandler); err != nil {
+					mu.Lock()
+					if scanErr == nil {
+						scanErr = err
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+
+LOOP:
+	for _, shard := range shards {
+		select {
+		case <-scanCtx.Done():
+			break LOOP
+		case shardCh <- shard:
+		}
+	}
+	close(shardCh)
+	wg.Wait()
+
+	if scanErr != nil {
+		return scanErr
+	}
+
+	return ctx.Err()
+}
+
+func (n *layer) scanShard(ctx context.Context, p *scanObjectsParams, shard *data.VersionsShard, handler func(*data.ExtendedObjectInfo) error) error {
+	nodeVersions, err := n.treeService.GetShardVersions(ctx, p.BktInfo, shard, !p.AllVersions)
+	if err != nil {
+		return fmt.Errorf("get versions of shard '%s'-'%s': %w", shard.Prefix+shard.First, shard.Prefix+shard.Last, err)
+	}
+
+	latest := make(map[string]*data.NodeVersion, len(nodeVersions))
+	for _, nodeVersion := range nodeVersions {
+		if prev, ok := latest[nodeVersion.FilePath]; !ok || prev.Timestamp <= nodeVersion.Timestamp {
+			latest[nodeVersion.FilePath] = nodeVersion
+		}
+	}
+
+	for _, nodeVersion := range nodeVersions {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		extObjInfo := &data.ExtendedObjectInfo{
+			NodeVersion: nodeVersion,
+			IsLatest:    latest[nodeVersion.FilePath] == nodeVersion,
+		}
+
+		if nodeVersion.IsDeleteMarker() { // delete marker does not match any object in NeoFS
+			extObjInfo.ObjectInfo = &data.ObjectInfo{
+				ID:             nodeVersion.OID,
+				CID:            p.BktInfo.CID,
+				Bucket:         p.BktInfo.Name,
+				Name:           nodeVersion.FilePath,
+				Owner:          nodeVersion.DeleteMarker.Owner,
+				Created:        nodeVersion.DeleteMarker.Created,
+				IsDeleteMarker: true,
+			}
+		} else if extObjInfo.ObjectInfo = n.objectInfoFromObjectsCacheOrNeoFS(ctx, p.BktInfo, nodeVersion, "", ""); extObjInfo.ObjectInfo == nil {
+			// form object info with data that the tree node contains
+			extObjInfo.ObjectInfo = getPartialObjectInfo(p.BktInfo, nodeVersion)
+		}
+
+		if err = handler(extObjInfo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
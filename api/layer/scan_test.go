@@ -0,0 +1,119 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanObjects(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: settings,
+	})
+	require.NoError(t, err)
+
+	names := []string{"a", "ab", "a/b", "a/c/d", "b/c", "c"}
+	for _, name := range names {
+		tc.obj = name
+		tc.putObject([]byte("content of " + name))
+	}
+	tc.obj = "a/b"
+	tc.putObject([]byte("new content of a/b"))
+	tc.deleteObject("c", "", settings)
+
+	scan := func(p *scanObjectsParams) []string {
+		var result []string
+		err := tc.layer.(*layer).scanObjects(tc.ctx, p, func(extObjInfo *data.ExtendedObjectInfo) error {
+			name := extObjInfo.ObjectInfo.Name
+			if extObjInfo.ObjectInfo.IsDeleteMarker {
+				name += " (delete marker)"
+			}
+			if extObjInfo.IsLatest {
+				name += " (latest)"
+			}
+			result = append(result, name)
+			return nil
+		})
+		require.NoError(t, err)
+		sort.Strings(result)
+		return result
+	}
+
+	require.Equal(t, []string{"a (latest)", "a/b (latest)", "a/c/d (latest)", "ab (latest)", "b/c (latest)"},
+		scan(&scanObjectsParams{BktInfo: tc.bktInfo, Workers: 4}))
+
+	require.Equal(t, []string{"a (latest)", "a/b", "a/b (latest)", "a/c/d (latest)", "ab (latest)", "b/c (latest)",
+		"c", "c (delete marker) (latest)"},
+		scan(&scanObjectsParams{BktInfo: tc.bktInfo, AllVersions: true, Workers: 4}))
+
+	require.Equal(t, []string{"a/b (latest)", "a/c/d (latest)"},
+		scan(&scanObjectsParams{BktInfo: tc.bktInfo, Prefix: "a/", Workers: 1}))
+
+	// the latest version is the one with the max timestamp, which is changed by the node move
+	treeService := tc.layer.(*layer).treeService
+	versions, err := treeService.GetVersions(tc.ctx, tc.bktInfo, "a/b")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	oldest := versions[0]
+	if versions[1].ID < oldest.ID {
+		oldest = versions[1]
+	}
+	require.NoError(t, treeService.MoveVersion(tc.ctx, tc.bktInfo, oldest, "a/b"))
+
+	latest, err := treeService.GetLatestVersion(tc.ctx, tc.bktInfo, "a/b")
+	require.NoError(t, err)
+	require.Equal(t, oldest.OID, latest.OID)
+
+	var scannedOID oid.ID
+	err = tc.layer.(*layer).scanObjects(tc.ctx, &scanObjectsParams{BktInfo: tc.bktInfo, Prefix: "a/b"}, func(extObjInfo *data.ExtendedObjectInfo) error {
+		scannedOID = extObjInfo.NodeVersion.OID
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, latest.OID, scannedOID)
+
+	errStop := errors.New("stop")
+	var scanned int
+	err = tc.layer.(*layer).scanObjects(tc.ctx, &scanObjectsParams{BktInfo: tc.bktInfo, Workers: 2}, func(*data.ExtendedObjectInfo) error {
+		scanned++
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, scanned)
+}
+
+func TestSplitVersionsShards(t *testing.T) {
+	shards, indexes := data.SplitVersionsShards("dir/", []string{"a", "a", "b"})
+	require.Equal(t, []*data.VersionsShard{
+		{Prefix: "dir/", First: "a", Last: "a"},
+		{Prefix: "dir/", First: "b", Last: "b"},
+	}, shards)
+	require.Equal(t, []int{0, 0, 1}, indexes)
+
+	shards, indexes = data.SplitVersionsShards("", nil)
+	require.Empty(t, shards)
+	require.Empty(t, indexes)
+
+	// flat bucket keys are split into ranges, not into a shard per object
+	var names []string
+	for i := 0; i < 1000; i++ {
+		name := fmt.Sprintf("object-%04d", i)
+		names = append(names, name, name)
+	}
+	shards, indexes = data.SplitVersionsShards("", names)
+	require.Len(t, shards, 250)
+	for i := 1; i < len(shards); i++ {
+		require.Less(t, shards[i-1].Last, shards[i].First)
+	}
+	for i, name := range names {
+		require.True(t, shards[indexes[i]].Contains(name))
+	}
+}
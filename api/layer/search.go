@@ -0,0 +1,254 @@
+package layer
+
+import (
+	"context"
+	errorsStd "errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+)
+
+// SearchFilterType is a source of the value checked by the search filter.
+type SearchFilterType string
+
+const (
+	// SearchFilterMetadata checks user metadata of the object.
+	SearchFilterMetadata SearchFilterType = "Metadata"
+	// SearchFilterTag checks tags of the object.
+	SearchFilterTag SearchFilterType = "Tag"
+)
+
+// SearchFilter is a predicate over object metadata or tags. Exactly one of Equals, Prefix
+// or the Min/Max range must be set, range bounds are inclusive and compared as numbers.
+type SearchFilter struct {
+	Type   SearchFilterType
+	Key    string
+	Equals *string
+	Prefix *string
+	Min    *float64
+	Max    *float64
+}
+
+// ErrInvalidSearchFilter is returned if the search filter has an unsupported type or predicate.
+var ErrInvalidSearchFilter = errorsStd.New("invalid search filter")
+
+// Validate checks that the filter contains exactly one predicate.
+func (f *SearchFilter) Validate() error {
+	if f.Type != SearchFilterMetadata && f.Type != SearchFilterTag {
+		return fmt.Errorf("%w: unknown type '%s'", ErrInvalidSearchFilter, f.Type)
+	}
+	if len(f.Key) == 0 {
+		return fmt.Errorf("%w: empty key", ErrInvalidSearchFilter)
+	}
+
+	predicates := 0
+	if f.Equals != nil {
+		predicates++
+	}
+	if f.Prefix != nil {
+		predicates++
+	}
+	if f.Min != nil || f.Max != nil {
+		predicates++
+	}
+	if predicates != 1 {
+		return fmt.Errorf("%w: exactly one of equality, prefix or range must be set for '%s'", ErrInvalidSearchFilter, f.Key)
+	}
+
+	return nil
+}
+
+func (f *SearchFilter) match(value string, ok bool) bool {
+	if !ok {
+		return false
+	}
+
+	switch {
+	case f.Equals != nil:
+		return value == *f.Equals
+	case f.Prefix != nil:
+		return strings.HasPrefix(value, *f.Prefix)
+	default:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		return (f.Min == nil || number >= *f.Min) && (f.Max == nil || number <= *f.Max)
+	}
+}
+
+// searchBatchSize is the number of candidates whose headers are requested at once if MaxKeys isn't limited.
+const searchBatchSize = 1000
+
+// pushable reports whether the filter can be checked by NeoFS object search.
+func (f *SearchFilter) pushable() bool {
+	return f.Type == SearchFilterMetadata && (f.Equals != nil || f.Prefix != nil)
+}
+
+// SearchObjects returns the latest versions of objects with the prefix matching all filters in the name order.
+// Metadata equality and prefix filters are checked by NeoFS object search, so headers and tags are requested
+// only for matched objects until MaxKeys+1 of them pass all filters. Headers are taken from the object index
+// if it's enabled.
+func (n *layer) SearchObjects(ctx context.Context, p *SearchObjectsParams) (*ListObjectsInfoV1, error) {
+	for i := range p.Filters {
+		if err := p.Filters[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ListObjectsInfoV1{}
+	if p.MaxKeys == 0 {
+		return result, nil
+	}
+
+	nodeVersions, err := n.treeService.GetLatestVersionsByPrefix(ctx, p.BktInfo, p.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := n.searchByAttributes(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*data.NodeVersion, 0, len(nodeVersions))
+	for _, nodeVersion := range nodeVersions {
+		if nodeVersion.IsDeleteMarker() || nodeVersion.FilePath <= p.Marker {
+			continue
+		}
+		// attributes of packed objects are stored in the tree node, so they aren't found by NeoFS
+		if found != nil && nodeVersion.Pack == nil {
+			if _, ok := found[nodeVersion.OID]; !ok {
+				continue
+			}
+		}
+		candidates = append(candidates, nodeVersion)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].FilePath < candidates[j].FilePath
+	})
+
+	batchSize := searchBatchSize
+	if p.MaxKeys > 0 {
+		batchSize = p.MaxKeys + 1
+	}
+
+	var objects []*data.ObjectInfo
+	for start := 0; start < len(candidates) && (p.MaxKeys < 0 || len(objects) <= p.MaxKeys); start += batchSize {
+		end := start + batchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+
+		batch := candidates[start:end]
+		indexed := n.indexedObjects(ctx, p.BktInfo, batch)
+		for _, nodeVersion := range batch {
+			objInfo := indexedListingObject(indexed, nodeVersion, "", "")
+			if objInfo == nil {
+				if objInfo = n.objectInfoFromObjectsCacheOrNeoFS(ctx, p.BktInfo, nodeVersion, "", ""); objInfo == nil {
+					continue
+				}
+			}
+
+			matched, err := n.matchSearchFilters(ctx, p, objInfo, nodeVersion)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+
+			objects = append(objects, objInfo)
+			if p.MaxKeys > 0 && len(objects) > p.MaxKeys {
+				break
+			}
+		}
+	}
+
+	if p.MaxKeys >= 0 && len(objects) > p.MaxKeys {
+		objects = objects[:p.MaxKeys]
+		result.IsTruncated = true
+		if len(objects) != 0 {
+			result.NextMarker = objects[len(objects)-1].Name
+		}
+	}
+	result.Objects = objects
+
+	return result, nil
+}
+
+// searchByAttributes selects objects matching metadata equality and prefix filters via NeoFS object search.
+// Nil is returned if there are no such filters.
+func (n *layer) searchByAttributes(ctx context.Context, p *SearchObjectsParams) (map[oid.ID]struct{}, error) {
+	prm := PrmObjectSearch{Container: p.BktInfo.CID}
+	for i := range p.Filters {
+		filter := &p.Filters[i]
+		if !filter.pushable() {
+			continue
+		}
+
+		// user metadata is stored in object attributes with lower case keys
+		key := strings.ToLower(filter.Key)
+		if filter.Equals != nil {
+			prm.ExactAttributes = append(prm.ExactAttributes, [2]string{key, *filter.Equals})
+		} else {
+			prm.PrefixAttributes = append(prm.PrefixAttributes, [2]string{key, *filter.Prefix})
+		}
+	}
+	if len(prm.ExactAttributes) == 0 && len(prm.PrefixAttributes) == 0 {
+		return nil, nil
+	}
+
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, p.BktInfo.Owner)
+
+	ids, err := n.neoFS.SearchObjects(ctx, prm)
+	if err != nil {
+		return nil, fmt.Errorf("search objects: %w", err)
+	}
+
+	found := make(map[oid.ID]struct{}, len(ids))
+	for _, id := range ids {
+		found[id] = struct{}{}
+	}
+
+	return found, nil
+}
+
+func (n *layer) matchSearchFilters(ctx context.Context, p *SearchObjectsParams, objInfo *data.ObjectInfo, nodeVersion *data.NodeVersion) (bool, error) {
+	var tags map[string]string
+
+	for i := range p.Filters {
+		filter := &p.Filters[i]
+
+		var (
+			value string
+			ok    bool
+		)
+		switch filter.Type {
+		case SearchFilterMetadata:
+			value, ok = objInfo.Headers[strings.ToLower(filter.Key)]
+		case SearchFilterTag:
+			if tags == nil {
+				var err error
+				if tags, err = n.treeService.GetObjectTagging(ctx, p.BktInfo, nodeVersion); err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+					return false, fmt.Errorf("couldn't get object tagging: %w", err)
+				}
+				if tags == nil {
+					tags = make(map[string]string)
+				}
+			}
+			value, ok = tags[filter.Key]
+		}
+
+		if !filter.match(value, ok) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
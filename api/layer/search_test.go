@@ -0,0 +1,59 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type readCountingNeoFS struct {
+	*TestNeoFS
+	reads int
+}
+
+func (c *readCountingNeoFS) ReadObject(ctx context.Context, prm PrmObjectRead) (*ObjectPart, error) {
+	c.reads++
+	return c.TestNeoFS.ReadObject(ctx, prm)
+}
+
+func TestSearchObjectsHeadsOnlyFoundObjects(t *testing.T) {
+	tc := prepareContext(t)
+
+	for _, obj := range []struct {
+		name, genre string
+	}{
+		{name: "a", genre: "jazz"},
+		{name: "b", genre: "rock"},
+		{name: "c", genre: "jazz"},
+		{name: "d", genre: "rock"},
+		{name: "e", genre: "jazz"},
+	} {
+		objInfo, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  obj.name,
+			Reader:  bytes.NewReader(nil),
+			Header:  map[string]string{"genre": obj.genre},
+		})
+		require.NoError(t, err)
+		tc.layer.(*layer).cache.DeleteObject(newAddress(tc.bktInfo.CID, objInfo.ObjectInfo.ID))
+	}
+
+	neoFS := &readCountingNeoFS{TestNeoFS: tc.testNeoFS}
+	tc.layer.(*layer).neoFS = neoFS
+
+	genre := "jazz"
+	res, err := tc.layer.SearchObjects(tc.ctx, &SearchObjectsParams{
+		BktInfo: tc.bktInfo,
+		MaxKeys: 1,
+		Filters: []SearchFilter{{Type: SearchFilterMetadata, Key: "Genre", Equals: &genre}},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Objects, 1)
+	require.Equal(t, "a", res.Objects[0].Name)
+	require.True(t, res.IsTruncated)
+
+	// objects not found by NeoFS aren't headed, search stops after MaxKeys+1 matches
+	require.Equal(t, 2, neoFS.reads)
+}
@@ -0,0 +1,80 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// objectReaderAt provides random access to the object payload by range requests.
+type objectReaderAt struct {
+	ctx    context.Context
+	layer  *layer
+	params *SelectObjectParams
+	size   int64
+}
+
+// SelectObjectContent runs the SQL query over the object payload. CSV and JSON payload is read from NeoFS
+// and processed concurrently through a pipe, so the object isn't kept in memory. Parquet files are read
+// by ranges: the metadata at the end of the file first and then row groups one by one.
+func (n *layer) SelectObjectContent(ctx context.Context, p *SelectObjectParams) error {
+	if err := n.CheckQuarantine(ctx, p.BktInfo, p.ObjectInfo); err != nil {
+		return err
+	}
+
+	if p.Query.IsParquet() {
+		size, err := decryptedObjectSize(p.ObjectInfo)
+		if err != nil {
+			return err
+		}
+
+		return p.Query.RunParquet(&objectReaderAt{ctx: ctx, layer: n, params: p, size: size}, size, p.Writer, p.Progress)
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	go func() {
+		err := n.GetObject(ctx, &GetObjectParams{
+			ObjectInfo: p.ObjectInfo,
+			BucketInfo: p.BktInfo,
+			Writer:     pw,
+			Encryption: p.Encryption,
+		})
+		pw.CloseWithError(err)
+	}()
+
+	return p.Query.Run(pr, p.Writer, p.Progress)
+}
+
+func (o *objectReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 || off >= o.size {
+		return 0, io.EOF
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	end := off + int64(len(b)) - 1
+	if end >= o.size {
+		end = o.size - 1
+	}
+
+	buf := bytes.NewBuffer(b[:0])
+	err := o.layer.GetObject(o.ctx, &GetObjectParams{
+		Range:      &RangeParams{Start: uint64(off), End: uint64(end)},
+		ObjectInfo: o.params.ObjectInfo,
+		BucketInfo: o.params.BktInfo,
+		Writer:     buf,
+		Encryption: o.params.Encryption,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	n := copy(b, buf.Bytes())
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
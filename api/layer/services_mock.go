@@ -0,0 +1,131 @@
+package layer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+)
+
+// TestLifecycleService keeps lifecycle configurations of buckets in memory, it doesn't expire,
+// transition or restore objects.
+type TestLifecycleService struct {
+	// Err is returned by all methods if set.
+	Err error
+
+	mu             sync.Mutex
+	configurations map[cid.ID]*data.LifecycleConfiguration
+}
+
+// TestReplicationService keeps replication configurations of buckets in memory without checks
+// of versioning and destination buckets.
+type TestReplicationService struct {
+	// Err is returned by all methods if set.
+	Err error
+
+	mu             sync.Mutex
+	configurations map[cid.ID]*data.ReplicationConfiguration
+}
+
+var (
+	_ LifecycleService   = (*TestLifecycleService)(nil)
+	_ ReplicationService = (*TestReplicationService)(nil)
+)
+
+func NewTestLifecycleService() *TestLifecycleService {
+	return &TestLifecycleService{configurations: make(map[cid.ID]*data.LifecycleConfiguration)}
+}
+
+func (t *TestLifecycleService) PutBucketLifecycleConfiguration(_ context.Context, p *PutBucketLifecycleParams) error {
+	if t.Err != nil {
+		return t.Err
+	}
+
+	t.mu.Lock()
+	t.configurations[p.BktInfo.CID] = p.Configuration
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *TestLifecycleService) GetBucketLifecycleConfiguration(_ context.Context, bktInfo *data.BucketInfo) (*data.LifecycleConfiguration, error) {
+	if t.Err != nil {
+		return nil, t.Err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conf, ok := t.configurations[bktInfo.CID]
+	if !ok {
+		return nil, errors.GetAPIError(errors.ErrNoSuchLifecycleConfiguration)
+	}
+	return conf, nil
+}
+
+func (t *TestLifecycleService) DeleteBucketLifecycleConfiguration(_ context.Context, bktInfo *data.BucketInfo) error {
+	if t.Err != nil {
+		return t.Err
+	}
+
+	t.mu.Lock()
+	delete(t.configurations, bktInfo.CID)
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *TestLifecycleService) ExpireObjects(context.Context, *data.BucketInfo) (int, error) {
+	return 0, t.Err
+}
+
+func (t *TestLifecycleService) TransitionObjects(context.Context, *data.BucketInfo) (int, error) {
+	return 0, t.Err
+}
+
+func (t *TestLifecycleService) RestoreObject(context.Context, *RestoreObjectParams) (bool, error) {
+	return false, t.Err
+}
+
+func (t *TestLifecycleService) ProcessRestores(context.Context, *data.BucketInfo) (int, error) {
+	return 0, t.Err
+}
+
+func NewTestReplicationService() *TestReplicationService {
+	return &TestReplicationService{configurations: make(map[cid.ID]*data.ReplicationConfiguration)}
+}
+
+func (t *TestReplicationService) PutBucketReplication(_ context.Context, p *PutBucketReplicationParams) error {
+	if t.Err != nil {
+		return t.Err
+	}
+
+	t.mu.Lock()
+	t.configurations[p.BktInfo.CID] = p.Configuration
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *TestReplicationService) GetBucketReplication(_ context.Context, bktInfo *data.BucketInfo) (*data.ReplicationConfiguration, error) {
+	if t.Err != nil {
+		return nil, t.Err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conf, ok := t.configurations[bktInfo.CID]
+	if !ok {
+		return nil, errors.GetAPIError(errors.ErrReplicationConfigurationNotFoundError)
+	}
+	return conf, nil
+}
+
+func (t *TestReplicationService) DeleteBucketReplication(_ context.Context, bktInfo *data.BucketInfo) error {
+	if t.Err != nil {
+		return t.Err
+	}
+
+	t.mu.Lock()
+	delete(t.configurations, bktInfo.CID)
+	t.mu.Unlock()
+	return nil
+}
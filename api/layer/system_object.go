@@ -1,14 +1,16 @@
 package layer
 
 import (
+	"bytes"
 	"context"
-	"encoding/xml"
 	errorsStd "errors"
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
@@ -19,6 +21,12 @@ const (
 	AttributeExpirationEpoch = "__NEOFS__EXPIRATION_EPOCH"
 )
 
+// ErrObjectLocked is returned if the retention of the object version doesn't allow its deletion.
+var ErrObjectLocked = errorsStd.New("object is locked")
+
+// errSystemObjectName is the reason of rejection of user objects with names of bucket system objects.
+var errSystemObjectName = errorsStd.New("object name is reserved for bucket system objects")
+
 type PutLockInfoParams struct {
 	ObjVersion   *ObjectVersion
 	NewLock      *data.ObjectLock
@@ -50,10 +58,10 @@ func (n *layer) PutLockInfo(ctx context.Context, p *PutLockInfoParams) (err erro
 	if newLock.Retention != nil {
 		if lockInfo.IsRetentionSet() {
 			if lockInfo.IsCompliance() {
-				return fmt.Errorf("you cannot change compliance mode")
+				return fmt.Errorf("%w: you cannot change compliance mode", ErrObjectLocked)
 			}
 			if !newLock.Retention.ByPassedGovernance {
-				return fmt.Errorf("you cannot bypass governence mode")
+				return fmt.Errorf("%w: you cannot bypass governence mode", ErrObjectLocked)
 			}
 
 			untilDate := lockInfo.UntilDate()
@@ -100,6 +108,44 @@ func (n *layer) PutLockInfo(ctx context.Context, p *PutLockInfoParams) (err erro
 	return nil
 }
 
+// checkLockToDelete checks that the legal hold and the active retention of the version allow its deletion.
+// Governance retention is bypassed if bypassGovernance is set, true is returned in this case: the NeoFS object
+// of the version is protected by the lock object until the retention date, so only the version is removed from
+// the bucket. Legal hold and compliance retention can't be bypassed.
+func (n *layer) checkLockToDelete(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion, bypassGovernance bool) (bool, error) {
+	if !bktInfo.ObjectLockEnabled || nodeVersion.IsDeleteMarker() {
+		return false, nil
+	}
+
+	lockInfo, err := n.treeService.GetLock(ctx, bktInfo, nodeVersion.ID)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return false, err
+	}
+	if lockInfo == nil {
+		return false, nil
+	}
+	if lockInfo.IsLegalHoldSet() {
+		return false, fmt.Errorf("%w: legal hold is set", ErrObjectLocked)
+	}
+	if !lockInfo.IsRetentionSet() {
+		return false, nil
+	}
+
+	untilDate, err := time.Parse(time.RFC3339, lockInfo.UntilDate())
+	if err != nil {
+		return false, fmt.Errorf("couldn't parse time '%s': %w", lockInfo.UntilDate(), err)
+	}
+	if !TimeNow(ctx).Before(untilDate) {
+		return false, nil
+	}
+
+	if lockInfo.IsCompliance() || !bypassGovernance {
+		return false, ErrObjectLocked
+	}
+
+	return true, nil
+}
+
 func (n *layer) getNodeVersionFromCacheOrNeofs(ctx context.Context, objVersion *ObjectVersion) (nodeVersion *data.NodeVersion, err error) {
 	// check cache if node version is stored inside extendedObjectVersion
 	nodeVersion = n.getNodeVersionFromCache(n.Owner(ctx), objVersion)
@@ -177,7 +223,7 @@ func (n *layer) getCORS(ctx context.Context, bkt *data.BucketInfo) (*data.CORSCo
 
 	cors := &data.CORSConfiguration{}
 
-	if err = xml.Unmarshal(obj.Payload(), &cors); err != nil {
+	if err = api.NewXMLDecoder(bytes.NewReader(obj.Payload())).Decode(cors); err != nil {
 		return nil, fmt.Errorf("unmarshal cors: %w", err)
 	}
 
@@ -252,3 +298,18 @@ func (n *layer) attributesFromLock(ctx context.Context, lock *data.ObjectLock) (
 
 	return result, nil
 }
+
+// systemObjectName returns the name of the bucket system object with the configured prefix
+// instead of the default one.
+func (n *layer) systemObjectName(name string) string {
+	return n.systemObjectPrefix + strings.TrimPrefix(name, data.DefaultSystemObjectPrefix)
+}
+
+// checkSystemObjectName rejects names of user objects matching names of bucket system objects,
+// so they can't be confused by NeoFS attributes.
+func (n *layer) checkSystemObjectName(name string) error {
+	if data.IsSystemObjectName(name, n.systemObjectPrefix) {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidObjectName, errSystemObjectName)
+	}
+	return nil
+}
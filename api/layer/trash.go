@@ -0,0 +1,300 @@
+package layer
+
+import (
+	"context"
+	errorsStd "errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"go.uber.org/zap"
+)
+
+// TrashObject is an object moved to the bucket trash instead of deletion.
+type TrashObject struct {
+	// Name is an identifier of object in the bucket trash.
+	Name string `json:"name"`
+	// Key is an original key of object.
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	Deleted time.Time `json:"deleted"`
+	Expires time.Time `json:"expires"`
+}
+
+type (
+	// trashPurger schedules removal of expired objects from the bucket trash,
+	// objects are removed by the worker started with RunTrashPurger.
+	trashPurger struct {
+		tasks chan trashPurgeTask
+
+		mu      sync.Mutex
+		pending map[cid.ID]struct{}
+	}
+
+	trashPurgeTask struct {
+		bktInfo *data.BucketInfo
+		// box contains credentials of the request which scheduled the task.
+		box *accessbox.Box
+	}
+)
+
+// trashPurgeQueueSize is a number of buckets waiting for trash purge,
+// deletions in other buckets don't schedule purge until the queue is freed.
+const trashPurgeQueueSize = 64
+
+// ErrObjectExists is returned on restoring object from the trash if object with original key exists.
+var ErrObjectExists = errorsStd.New("object already exists")
+
+func newTrashPurger() *trashPurger {
+	return &trashPurger{
+		tasks:   make(chan trashPurgeTask, trashPurgeQueueSize),
+		pending: make(map[cid.ID]struct{}),
+	}
+}
+
+// schedule adds the bucket to the purge queue if it's not there yet.
+func (p *trashPurger) schedule(ctx context.Context, bktInfo *data.BucketInfo) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pending[bktInfo.CID]; ok {
+		return true
+	}
+
+	box, _ := ctx.Value(api.BoxData).(*accessbox.Box)
+	select {
+	case p.tasks <- trashPurgeTask{bktInfo: bktInfo, box: box}:
+		p.pending[bktInfo.CID] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *trashPurger) done(bktInfo *data.BucketInfo) {
+	p.mu.Lock()
+	delete(p.pending, bktInfo.CID)
+	p.mu.Unlock()
+}
+
+func newTrashObject(trashVersion *data.TrashVersion, retention time.Duration) *TrashObject {
+	return &TrashObject{
+		Name:    trashVersion.Name(),
+		Key:     trashVersion.Version.FilePath,
+		Size:    trashVersion.Version.Size,
+		Deleted: trashVersion.Deleted,
+		Expires: trashVersion.Deleted.Add(retention),
+	}
+}
+
+// ListTrash returns objects from the bucket trash, the oldest deleted object goes first.
+func (n *layer) ListTrash(ctx context.Context, bktInfo *data.BucketInfo) ([]*TrashObject, error) {
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+
+	trashVersions, err := n.treeService.GetTrashVersions(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*TrashObject, 0, len(trashVersions))
+	for _, trashVersion := range trashVersions {
+		result = append(result, newTrashObject(trashVersion, settings.TrashRetention))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Deleted.Before(result[j].Deleted)
+	})
+
+	return result, nil
+}
+
+// RestoreTrashObject moves the object from the bucket trash back to its original key.
+func (n *layer) RestoreTrashObject(ctx context.Context, bktInfo *data.BucketInfo, name string) (*TrashObject, error) {
+	trashVersions, err := n.treeService.GetTrashVersions(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var trashVersion *data.TrashVersion
+	for _, version := range trashVersions {
+		if version.Name() == name {
+			trashVersion = version
+			break
+		}
+	}
+	if trashVersion == nil {
+		return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+	}
+
+	key := trashVersion.Version.FilePath
+	unlock := n.versionLocks.lock(bktInfo.CID, key)
+	defer unlock()
+
+	existed, err := n.treeService.GetLatestVersion(ctx, bktInfo, key)
+	if err == nil && !existed.IsDeleteMarker() {
+		return nil, fmt.Errorf("%w: %s", ErrObjectExists, key)
+	}
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return nil, err
+	}
+
+	newVersion := &data.NodeVersion{
+		BaseNodeVersion: data.BaseNodeVersion{
+			OID:      trashVersion.Version.OID,
+			Size:     trashVersion.Version.Size,
+			ETag:     trashVersion.Version.ETag,
+			FilePath: key,
+		},
+		IsUnversioned: true,
+		Pack:          trashVersion.Version.Pack,
+		Replica:       trashVersion.Version.Replica,
+
+		ChecksumAlgorithm: trashVersion.Version.ChecksumAlgorithm,
+		Checksum:          trashVersion.Version.Checksum,
+	}
+
+	// version is added before removal from the trash,
+	// so object isn't lost in case of failure
+	if newVersion.ID, err = n.treeService.AddVersion(ctx, bktInfo, newVersion); err != nil {
+		return nil, fmt.Errorf("couldn't add version to tree service: %w", err)
+	}
+
+	if len(trashVersion.Tags) != 0 {
+		if err = n.treeService.PutObjectTagging(ctx, bktInfo, newVersion, trashVersion.Tags); err != nil {
+			return nil, fmt.Errorf("couldn't put object tagging: %w", err)
+		}
+	}
+
+	if err = n.treeService.RemoveTrashVersion(ctx, bktInfo, trashVersion.ID); err != nil {
+		return nil, fmt.Errorf("couldn't remove version from trash: %w", err)
+	}
+
+	n.cleanObjectNameCache(bktInfo, key, trashVersion.Version)
+
+	return newTrashObject(trashVersion, 0), nil
+}
+
+// moveToTrash moves the unversioned object to the bucket trash instead of its deletion.
+func (n *layer) moveToTrash(ctx context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) error {
+	tagSet, err := n.treeService.GetObjectTagging(ctx, bktInfo, nodeVersion)
+	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+		return fmt.Errorf("couldn't get object tagging: %w", err)
+	}
+
+	trashVersion := &data.TrashVersion{
+		Version: nodeVersion,
+		Deleted: TimeNow(ctx).UTC(),
+		Tags:    tagSet,
+	}
+
+	// object is added to the trash before removal of its version,
+	// so object is still available in case of failure
+	if _, err = n.treeService.AddTrashVersion(ctx, bktInfo, trashVersion); err != nil {
+		return fmt.Errorf("couldn't add version to trash: %w", err)
+	}
+
+	if err = n.treeService.RemoveVersion(ctx, bktInfo, nodeVersion.ID); err != nil {
+		return fmt.Errorf("couldn't remove version from tree service: %w", err)
+	}
+
+	n.cleanObjectNameCache(bktInfo, nodeVersion.FilePath, nodeVersion)
+
+	return nil
+}
+
+// cleanObjectNameCache removes cached object info, listings and tagging of the key.
+func (n *layer) cleanObjectNameCache(bktInfo *data.BucketInfo, name string, nodeVersion *data.NodeVersion) {
+	n.cache.DeleteObject(newAddress(bktInfo.CID, nodeVersion.OID))
+	n.cache.DeleteObjectName(bktInfo.CID, bktInfo.Name, name)
+	for _, versionID := range []string{"", data.UnversionedObjectVersionID, nodeVersion.OID.EncodeToString()} {
+		n.cache.DeleteTagging(objectTaggingCacheKey(&ObjectVersion{BktInfo: bktInfo, ObjectName: name, VersionID: versionID}))
+	}
+}
+
+// scheduleTrashPurge schedules removal of expired objects from the bucket trash with credentials of the request.
+func (n *layer) scheduleTrashPurge(ctx context.Context, bktInfo *data.BucketInfo) {
+	if !n.trashPurger.schedule(ctx, bktInfo) {
+		n.log.Warn("trash purge queue is full, purge is left to the periodic sweep",
+			zap.String("bucket name", bktInfo.Name))
+	}
+}
+
+// RunTrashPurger removes expired objects from the trash of buckets where objects were deleted
+// until the context is done.
+func (n *layer) RunTrashPurger(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-n.trashPurger.tasks:
+			n.trashPurger.done(task.bktInfo)
+
+			taskCtx := ctx
+			if task.box != nil {
+				taskCtx = context.WithValue(ctx, api.BoxData, task.box)
+			}
+
+			purged, err := n.PurgeExpiredTrash(taskCtx, task.bktInfo)
+			if err != nil {
+				n.log.Error("couldn't purge expired trash", zap.Error(err),
+					zap.String("bucket name", task.bktInfo.Name))
+				continue
+			}
+			if purged != 0 {
+				n.log.Info("expired trash purged", zap.String("bucket name", task.bktInfo.Name),
+					zap.Int("objects", purged))
+			}
+		}
+	}
+}
+
+// PurgeExpiredTrash deletes objects which are stored in the bucket trash longer than retention period
+// and returns the number of deleted objects. Trash of read-only and frozen buckets isn't purged.
+func (n *layer) PurgeExpiredTrash(ctx context.Context, bktInfo *data.BucketInfo) (int, error) {
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+	if !settings.TrashEnabled() || settings.ReadOnly || settings.Frozen {
+		return 0, nil
+	}
+
+	trashVersions, err := n.treeService.GetTrashVersions(ctx, bktInfo)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't list trash objects: %w", err)
+	}
+
+	var purged int
+	now := TimeNow(ctx)
+	for _, trashVersion := range trashVersions {
+		if trashVersion.Deleted.Add(settings.TrashRetention).After(now) {
+			continue
+		}
+
+		if err = n.deleteNodeObject(ctx, bktInfo, trashVersion.Version); err != nil {
+			n.log.Error("couldn't delete expired trash object", zap.Error(err),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("object", trashVersion.Name()))
+			continue
+		}
+
+		if err = n.treeService.RemoveTrashVersion(ctx, bktInfo, trashVersion.ID); err != nil {
+			n.log.Error("couldn't remove expired trash object from tree", zap.Error(err),
+				zap.String("bucket name", bktInfo.Name),
+				zap.String("object", trashVersion.Name()))
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
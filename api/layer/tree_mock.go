@@ -5,28 +5,60 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 )
 
 type TreeServiceMock struct {
-	settings   map[string]*data.BucketSettings
-	versions   map[string]map[string][]*data.NodeVersion
-	system     map[string]map[string]*data.BaseNodeVersion
-	locks      map[string]map[uint64]*data.LockInfo
-	tags       map[string]map[uint64]map[string]string
-	multiparts map[string]map[string][]*data.MultipartInfo
-	parts      map[string]map[int]*data.PartInfo
+	settings    map[string]*data.BucketSettings
+	versions    map[string]map[string][]*data.NodeVersion
+	system      map[string]map[string]*data.BaseNodeVersion
+	locks       map[string]map[uint64]*data.LockInfo
+	tags        map[string]map[uint64]map[string]string
+	statuses    map[string]map[uint64]string
+	restores    map[string]map[uint64]*data.RestoreInfo
+	multiparts  map[string]map[string][]*data.MultipartInfo
+	parts       map[string]map[int]*data.PartInfo
+	cors        map[string]oid.ID
+	lifecycle   map[string]oid.ID
+	inventory   map[string]oid.ID
+	replication map[string]oid.ID
+	analytics   map[string]oid.ID
+	metrics     map[string]oid.ID
+	policies    map[string]bucketPolicyMock
+	websites    map[string]bucketPolicyMock
+	history     map[string][]oid.ID
+	trash       map[string][]*data.TrashVersion
+	packs       map[string]oid.ID
+
+	// lastVersionID is used to make version node IDs unique within the tree like the real tree service does.
+	lastVersionID uint64
+
+	// mu guards the tree since objects are deleted concurrently.
+	mu sync.Mutex
+}
+
+// bucketPolicyMock keeps a system object id with the copy of the document, it's used for policies and websites.
+type bucketPolicyMock struct {
+	objID    oid.ID
+	document []byte
 }
 
 func (t *TreeServiceMock) GetObjectTaggingAndLock(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, *data.LockInfo, error) {
-	// TODO implement object tagging
+	tags, err := t.GetObjectTagging(ctx, bktInfo, objVersion)
+	if err != nil {
+		return nil, nil, err
+	}
 	lock, err := t.GetLock(ctx, bktInfo, objVersion.ID)
-	return nil, lock, err
+	return tags, lock, err
 }
 
 func (t *TreeServiceMock) GetObjectTagging(_ context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) (map[string]string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrTagsMap, ok := t.tags[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, nil
@@ -36,6 +68,9 @@ func (t *TreeServiceMock) GetObjectTagging(_ context.Context, bktInfo *data.Buck
 }
 
 func (t *TreeServiceMock) PutObjectTagging(_ context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion, tagSet map[string]string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrTagsMap, ok := t.tags[bktInfo.CID.EncodeToString()]
 	if !ok {
 		t.tags[bktInfo.CID.EncodeToString()] = map[uint64]map[string]string{
@@ -50,6 +85,9 @@ func (t *TreeServiceMock) PutObjectTagging(_ context.Context, bktInfo *data.Buck
 }
 
 func (t *TreeServiceMock) DeleteObjectTagging(_ context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrTagsMap, ok := t.tags[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil
@@ -76,22 +114,41 @@ func (t *TreeServiceMock) DeleteBucketTagging(ctx context.Context, bktInfo *data
 
 func NewTreeService() *TreeServiceMock {
 	return &TreeServiceMock{
-		settings:   make(map[string]*data.BucketSettings),
-		versions:   make(map[string]map[string][]*data.NodeVersion),
-		system:     make(map[string]map[string]*data.BaseNodeVersion),
-		locks:      make(map[string]map[uint64]*data.LockInfo),
-		tags:       make(map[string]map[uint64]map[string]string),
-		multiparts: make(map[string]map[string][]*data.MultipartInfo),
-		parts:      make(map[string]map[int]*data.PartInfo),
+		settings:    make(map[string]*data.BucketSettings),
+		versions:    make(map[string]map[string][]*data.NodeVersion),
+		system:      make(map[string]map[string]*data.BaseNodeVersion),
+		locks:       make(map[string]map[uint64]*data.LockInfo),
+		tags:        make(map[string]map[uint64]map[string]string),
+		statuses:    make(map[string]map[uint64]string),
+		restores:    make(map[string]map[uint64]*data.RestoreInfo),
+		multiparts:  make(map[string]map[string][]*data.MultipartInfo),
+		parts:       make(map[string]map[int]*data.PartInfo),
+		cors:        make(map[string]oid.ID),
+		lifecycle:   make(map[string]oid.ID),
+		inventory:   make(map[string]oid.ID),
+		replication: make(map[string]oid.ID),
+		analytics:   make(map[string]oid.ID),
+		metrics:     make(map[string]oid.ID),
+		policies:    make(map[string]bucketPolicyMock),
+		websites:    make(map[string]bucketPolicyMock),
+		history:     make(map[string][]oid.ID),
+		trash:       make(map[string][]*data.TrashVersion),
+		packs:       make(map[string]oid.ID),
 	}
 }
 
 func (t *TreeServiceMock) PutSettingsNode(_ context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.settings[bktInfo.CID.EncodeToString()] = settings
 	return nil
 }
 
 func (t *TreeServiceMock) GetSettingsNode(_ context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	settings, ok := t.settings[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, ErrNodeNotFound
@@ -101,26 +158,401 @@ func (t *TreeServiceMock) GetSettingsNode(_ context.Context, bktInfo *data.Bucke
 }
 
 func (t *TreeServiceMock) GetNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	panic("implement me")
 }
 
 func (t *TreeServiceMock) PutNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	panic("implement me")
 }
 
-func (t *TreeServiceMock) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetBucketCORS(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.cors[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
 }
 
-func (t *TreeServiceMock) PutBucketCORS(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) PutBucketCORS(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objIDToDelete, ok := t.cors[bktInfo.CID.EncodeToString()]
+	t.cors[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return objIDToDelete, nil
 }
 
-func (t *TreeServiceMock) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) DeleteBucketCORS(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.cors[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.cors, bktInfo.CID.EncodeToString())
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) GetBucketLifecycleConfiguration(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.lifecycle[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) PutBucketLifecycleConfiguration(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objIDToDelete, ok := t.lifecycle[bktInfo.CID.EncodeToString()]
+	t.lifecycle[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return objIDToDelete, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketLifecycleConfiguration(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.lifecycle[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.lifecycle, bktInfo.CID.EncodeToString())
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) GetBucketInventoryConfigurations(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.inventory[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) PutBucketInventoryConfigurations(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objIDToDelete, ok := t.inventory[bktInfo.CID.EncodeToString()]
+	t.inventory[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return objIDToDelete, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketInventoryConfigurations(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.inventory[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.inventory, bktInfo.CID.EncodeToString())
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) GetBucketReplicationConfiguration(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.replication[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) PutBucketReplicationConfiguration(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objIDToDelete, ok := t.replication[bktInfo.CID.EncodeToString()]
+	t.replication[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return objIDToDelete, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketReplicationConfiguration(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.replication[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.replication, bktInfo.CID.EncodeToString())
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) GetBucketAnalyticsConfigurations(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.analytics[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) PutBucketAnalyticsConfigurations(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objIDToDelete, ok := t.analytics[bktInfo.CID.EncodeToString()]
+	t.analytics[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return objIDToDelete, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketAnalyticsConfigurations(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.analytics[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.analytics, bktInfo.CID.EncodeToString())
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) GetBucketMetricsConfigurations(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.metrics[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) PutBucketMetricsConfigurations(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objIDToDelete, ok := t.metrics[bktInfo.CID.EncodeToString()]
+	t.metrics[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return objIDToDelete, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketMetricsConfigurations(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.metrics[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.metrics, bktInfo.CID.EncodeToString())
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) GetBucketPolicy(_ context.Context, bktInfo *data.BucketInfo) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bktPolicy, ok := t.policies[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	return bktPolicy.document, nil
+}
+
+func (t *TreeServiceMock) PutBucketPolicy(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID, document []byte) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.policies[bktInfo.CID.EncodeToString()]
+	t.policies[bktInfo.CID.EncodeToString()] = bucketPolicyMock{objID: objID, document: document}
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return prev.objID, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketPolicy(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bktPolicy, ok := t.policies[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.policies, bktInfo.CID.EncodeToString())
+
+	return bktPolicy.objID, nil
+}
+
+func (t *TreeServiceMock) GetBucketWebsite(_ context.Context, bktInfo *data.BucketInfo) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	website, ok := t.websites[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	return website.document, nil
+}
+
+func (t *TreeServiceMock) PutBucketWebsite(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID, configuration []byte) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.websites[bktInfo.CID.EncodeToString()]
+	t.websites[bktInfo.CID.EncodeToString()] = bucketPolicyMock{objID: objID, document: configuration}
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return prev.objID, nil
+}
+
+func (t *TreeServiceMock) DeleteBucketWebsite(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	website, ok := t.websites[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+	delete(t.websites, bktInfo.CID.EncodeToString())
+
+	return website.objID, nil
+}
+
+func (t *TreeServiceMock) AddBucketConfigChange(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.history[bktInfo.CID.EncodeToString()] = append(t.history[bktInfo.CID.EncodeToString()], objID)
+	return nil
+}
+
+func (t *TreeServiceMock) GetBucketConfigChanges(_ context.Context, bktInfo *data.BucketInfo) ([]oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.history[bktInfo.CID.EncodeToString()], nil
+}
+
+func (t *TreeServiceMock) AddTrashVersion(_ context.Context, bktInfo *data.BucketInfo, trashVersion *data.TrashVersion) (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastVersionID++
+	newVersion := *trashVersion
+	newVersion.ID = t.lastVersionID
+	t.trash[bktInfo.CID.EncodeToString()] = append(t.trash[bktInfo.CID.EncodeToString()], &newVersion)
+
+	return newVersion.ID, nil
+}
+
+func (t *TreeServiceMock) GetTrashVersions(_ context.Context, bktInfo *data.BucketInfo) ([]*data.TrashVersion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.trash[bktInfo.CID.EncodeToString()], nil
+}
+
+func (t *TreeServiceMock) RemoveTrashVersion(_ context.Context, bktInfo *data.BucketInfo, id uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	trash := t.trash[bktInfo.CID.EncodeToString()]
+	for i, trashVersion := range trash {
+		if trashVersion.ID == id {
+			t.trash[bktInfo.CID.EncodeToString()] = append(trash[:i:i], trash[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrNodeNotFound
+}
+
+func (t *TreeServiceMock) GetBucketPackIndexNode(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objID, ok := t.packs[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
+}
+
+func (t *TreeServiceMock) PutBucketPackIndexNode(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	objIDToDelete, ok := t.packs[bktInfo.CID.EncodeToString()]
+	t.packs[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return objIDToDelete, nil
 }
 
 func (t *TreeServiceMock) GetVersions(_ context.Context, bktInfo *data.BucketInfo, objectName string) ([]*data.NodeVersion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, ErrNodeNotFound
@@ -135,6 +567,9 @@ func (t *TreeServiceMock) GetVersions(_ context.Context, bktInfo *data.BucketInf
 }
 
 func (t *TreeServiceMock) GetLatestVersion(_ context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, ErrNodeNotFound
@@ -145,21 +580,37 @@ func (t *TreeServiceMock) GetLatestVersion(_ context.Context, bktInfo *data.Buck
 		return nil, ErrNodeNotFound
 	}
 
+	if latest := latestNodeVersion(versions); latest != nil {
+		return latest, nil
+	}
+
+	return nil, ErrNodeNotFound
+}
+
+// latestNodeVersion returns the version with the max timestamp like the real tree service does,
+// the version added later wins if timestamps are equal.
+func latestNodeVersion(versions []*data.NodeVersion) *data.NodeVersion {
 	sort.Slice(versions, func(i, j int) bool {
 		return versions[i].ID < versions[j].ID
 	})
 
-	if len(versions) != 0 {
-		return versions[len(versions)-1], nil
+	var latest *data.NodeVersion
+	for _, version := range versions {
+		if latest == nil || latest.Timestamp <= version.Timestamp {
+			latest = version
+		}
 	}
 
-	return nil, ErrNodeNotFound
+	return latest
 }
 
 func (t *TreeServiceMock) GetLatestVersionsByPrefix(_ context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
-		return nil, ErrNodeNotFound
+		return nil, nil
 	}
 
 	var result []*data.NodeVersion
@@ -169,12 +620,8 @@ func (t *TreeServiceMock) GetLatestVersionsByPrefix(_ context.Context, bktInfo *
 			continue
 		}
 
-		sort.Slice(versions, func(i, j int) bool {
-			return versions[i].ID < versions[j].ID
-		})
-
-		if len(versions) != 0 {
-			result = append(result, versions[len(versions)-1])
+		if latest := latestNodeVersion(versions); latest != nil {
+			result = append(result, latest)
 		}
 	}
 
@@ -182,6 +629,9 @@ func (t *TreeServiceMock) GetLatestVersionsByPrefix(_ context.Context, bktInfo *
 }
 
 func (t *TreeServiceMock) GetUnversioned(_ context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, ErrNodeNotFound
@@ -202,6 +652,12 @@ func (t *TreeServiceMock) GetUnversioned(_ context.Context, bktInfo *data.Bucket
 }
 
 func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion) (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastVersionID++
+	newVersion.ID = t.lastVersionID
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		t.versions[bktInfo.CID.EncodeToString()] = map[string][]*data.NodeVersion{
@@ -216,13 +672,8 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo
 		return newVersion.ID, nil
 	}
 
-	sort.Slice(versions, func(i, j int) bool {
-		return versions[i].ID < versions[j].ID
-	})
-
-	if len(versions) != 0 {
-		newVersion.ID = versions[len(versions)-1].ID + 1
-		newVersion.Timestamp = versions[len(versions)-1].Timestamp + 1
+	if latest := latestNodeVersion(versions); latest != nil {
+		newVersion.Timestamp = latest.Timestamp + 1
 	}
 
 	result := versions
@@ -241,7 +692,87 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo
 	return newVersion.ID, nil
 }
 
+func (t *TreeServiceMock) PackVersion(_ context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	versions := cnrVersionsMap[version.FilePath]
+	for _, node := range versions {
+		if node.ID == version.ID {
+			node.Pack = version.Pack
+			// node is updated by move, which sets a new timestamp like in the real tree service
+			node.Timestamp = latestNodeVersion(versions).Timestamp + 1
+			return nil
+		}
+	}
+
+	return ErrNodeNotFound
+}
+
+func (t *TreeServiceMock) UpdateVersionMetadata(_ context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	versions := cnrVersionsMap[version.FilePath]
+	for _, node := range versions {
+		if node.ID == version.ID {
+			node.Metadata = version.Metadata
+			node.Timestamp = latestNodeVersion(versions).Timestamp + 1
+			return nil
+		}
+	}
+
+	return ErrNodeNotFound
+}
+
+func (t *TreeServiceMock) MoveVersion(_ context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, newName string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	versions := cnrVersionsMap[version.FilePath]
+	for i, node := range versions {
+		if node.ID != version.ID {
+			continue
+		}
+
+		cnrVersionsMap[version.FilePath] = append(versions[:i:i], versions[i+1:]...)
+		if len(cnrVersionsMap[version.FilePath]) == 0 {
+			delete(cnrVersionsMap, version.FilePath)
+		}
+
+		// moved node gets the new timestamp like any other updated node of the tree
+		moved := *node
+		moved.FilePath = newName
+		moved.Timestamp = 0
+		if latest := latestNodeVersion(cnrVersionsMap[newName]); latest != nil {
+			moved.Timestamp = latest.Timestamp + 1
+		}
+		cnrVersionsMap[newName] = append(cnrVersionsMap[newName], &moved)
+		return nil
+	}
+
+	return ErrNodeNotFound
+}
+
 func (t *TreeServiceMock) RemoveVersion(_ context.Context, bktInfo *data.BucketInfo, nodeID uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return ErrNodeNotFound
@@ -260,6 +791,9 @@ func (t *TreeServiceMock) RemoveVersion(_ context.Context, bktInfo *data.BucketI
 }
 
 func (t *TreeServiceMock) GetAllVersionsByPrefix(_ context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, nil
@@ -275,7 +809,66 @@ func (t *TreeServiceMock) GetAllVersionsByPrefix(_ context.Context, bktInfo *dat
 	return result, nil
 }
 
+func (t *TreeServiceMock) GetVersionsShards(_ context.Context, bktInfo *data.BucketInfo, prefix string, _ bool) ([]*data.VersionsShard, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return nil, nil
+	}
+
+	headPrefix := prefix[:strings.LastIndex(prefix, "/")+1]
+
+	var names []string
+	for objName := range cnrVersionsMap {
+		if strings.HasPrefix(objName, prefix) {
+			names = append(names, shardName(headPrefix, objName))
+		}
+	}
+	sort.Strings(names)
+
+	shards, _ := data.SplitVersionsShards(headPrefix, names)
+	return shards, nil
+}
+
+// shardName returns the first-level name of the object after the prefix.
+func shardName(prefix, objName string) string {
+	return strings.SplitN(strings.TrimPrefix(objName, prefix), "/", 2)[0]
+}
+
+func (t *TreeServiceMock) GetShardVersions(_ context.Context, bktInfo *data.BucketInfo, shard *data.VersionsShard, latestOnly bool) ([]*data.NodeVersion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return nil, nil
+	}
+
+	var result []*data.NodeVersion
+	for objName, versions := range cnrVersionsMap {
+		if !strings.HasPrefix(objName, shard.Prefix) || !shard.Contains(shardName(shard.Prefix, objName)) || len(versions) == 0 {
+			continue
+		}
+
+		if !latestOnly {
+			result = append(result, versions...)
+			continue
+		}
+
+		if latest := latestNodeVersion(versions); !latest.IsDeleteMarker() {
+			result = append(result, latest)
+		}
+	}
+
+	return result, nil
+}
+
 func (t *TreeServiceMock) CreateMultipartUpload(_ context.Context, bktInfo *data.BucketInfo, info *data.MultipartInfo) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrMultipartsMap, ok := t.multiparts[bktInfo.CID.EncodeToString()]
 	if !ok {
 		t.multiparts[bktInfo.CID.EncodeToString()] = map[string][]*data.MultipartInfo{
@@ -293,11 +886,24 @@ func (t *TreeServiceMock) CreateMultipartUpload(_ context.Context, bktInfo *data
 	return nil
 }
 
-func (t *TreeServiceMock) GetMultipartUploadsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.MultipartInfo, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetMultipartUploadsByPrefix(_ context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.MultipartInfo, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result []*data.MultipartInfo
+	for key, multiparts := range t.multiparts[bktInfo.CID.EncodeToString()] {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, multiparts...)
+		}
+	}
+
+	return result, nil
 }
 
 func (t *TreeServiceMock) GetMultipartUpload(_ context.Context, bktInfo *data.BucketInfo, objectName, uploadID string) (*data.MultipartInfo, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrMultipartsMap, ok := t.multiparts[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, ErrNodeNotFound
@@ -319,6 +925,9 @@ func (t *TreeServiceMock) AddPart(ctx context.Context, bktInfo *data.BucketInfo,
 		return oid.ID{}, err
 	}
 
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	if multipartInfo.ID != multipartNodeID {
 		return oid.ID{}, fmt.Errorf("invalid multipart info id")
 	}
@@ -335,6 +944,9 @@ func (t *TreeServiceMock) AddPart(ctx context.Context, bktInfo *data.BucketInfo,
 }
 
 func (t *TreeServiceMock) GetParts(_ context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) ([]*data.PartInfo, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrMultipartsMap := t.multiparts[bktInfo.CID.EncodeToString()]
 
 	var foundMultipart *data.MultipartInfo
@@ -363,6 +975,9 @@ LOOP:
 }
 
 func (t *TreeServiceMock) DeleteMultipartUpload(_ context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrMultipartsMap := t.multiparts[bktInfo.CID.EncodeToString()]
 
 	var uploadID string
@@ -387,6 +1002,9 @@ LOOP:
 }
 
 func (t *TreeServiceMock) PutLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, lock *data.LockInfo) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrLockMap, ok := t.locks[bktInfo.CID.EncodeToString()]
 	if !ok {
 		t.locks[bktInfo.CID.EncodeToString()] = map[uint64]*data.LockInfo{
@@ -401,6 +1019,9 @@ func (t *TreeServiceMock) PutLock(ctx context.Context, bktInfo *data.BucketInfo,
 }
 
 func (t *TreeServiceMock) GetLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) (*data.LockInfo, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	cnrLockMap, ok := t.locks[bktInfo.CID.EncodeToString()]
 	if !ok {
 		return nil, nil
@@ -408,3 +1029,81 @@ func (t *TreeServiceMock) GetLock(ctx context.Context, bktInfo *data.BucketInfo,
 
 	return cnrLockMap[nodeID], nil
 }
+
+func (t *TreeServiceMock) GetReplicationStatus(_ context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.statuses[bktInfo.CID.EncodeToString()][objVersion.ID], nil
+}
+
+func (t *TreeServiceMock) PutReplicationStatus(_ context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, status string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrStatuses, ok := t.statuses[bktInfo.CID.EncodeToString()]
+	if !ok {
+		cnrStatuses = make(map[uint64]string)
+		t.statuses[bktInfo.CID.EncodeToString()] = cnrStatuses
+	}
+
+	cnrStatuses[objVersion.ID] = status
+	return nil
+}
+
+func (t *TreeServiceMock) ArchiveVersion(_ context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	versions := cnrVersionsMap[version.FilePath]
+	for _, node := range versions {
+		if node.ID == version.ID {
+			node.Archive = version.Archive
+			node.Timestamp = latestNodeVersion(versions).Timestamp + 1
+			return nil
+		}
+	}
+
+	return ErrNodeNotFound
+}
+
+func (t *TreeServiceMock) GetRestoreState(_ context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (*data.RestoreInfo, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	restore, ok := t.restores[bktInfo.CID.EncodeToString()][objVersion.ID]
+	if !ok {
+		return nil, nil
+	}
+
+	res := *restore
+	return &res, nil
+}
+
+func (t *TreeServiceMock) PutRestoreState(_ context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, restore *data.RestoreInfo) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cnrRestores, ok := t.restores[bktInfo.CID.EncodeToString()]
+	if !ok {
+		cnrRestores = make(map[uint64]*data.RestoreInfo)
+		t.restores[bktInfo.CID.EncodeToString()] = cnrRestores
+	}
+
+	res := *restore
+	cnrRestores[objVersion.ID] = &res
+	return nil
+}
+
+func (t *TreeServiceMock) DeleteRestoreState(_ context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.restores[bktInfo.CID.EncodeToString()], objVersion.ID)
+	return nil
+}
@@ -44,6 +44,140 @@ type TreeService interface {
 	// If object id to remove is not found returns ErrNoNodeToRemove error.
 	DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
 
+	// GetBucketLifecycleConfiguration gets an object id that corresponds to object with bucket lifecycle configuration.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// PutBucketLifecycleConfiguration puts a node to a system tree and returns objectID of a previous
+	// lifecycle configuration which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error)
+
+	// GetBucketPolicy gets a copy of the bucket policy document kept in a system tree.
+	// The copy lets the gateway evaluate the policy for requesters who can't read bucket system objects.
+	// If the policy is not found returns ErrNodeNotFound.
+	GetBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) ([]byte, error)
+
+	// PutBucketPolicy puts a node with the policy object id and the document copy to a system tree
+	// and returns objectID of a previous policy which must be deleted in NeoFS.
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID, document []byte) (oid.ID, error)
+
+	// DeleteBucketPolicy removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// GetBucketWebsite gets a copy of the bucket website configuration kept in a system tree.
+	// The copy lets the gateway serve the website for anonymous requesters who can't read bucket system objects.
+	// If the configuration is not found returns ErrNodeNotFound.
+	GetBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) ([]byte, error)
+
+	// PutBucketWebsite puts a node with the website configuration object id and the configuration copy
+	// to a system tree and returns objectID of a previous configuration which must be deleted in NeoFS.
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID, configuration []byte) (oid.ID, error)
+
+	// DeleteBucketWebsite removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// DeleteBucketLifecycleConfiguration removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// GetBucketInventoryConfigurations gets an object id that corresponds to object with bucket inventory configurations.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// PutBucketInventoryConfigurations puts a node to a system tree and returns objectID of previous
+	// inventory configurations which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketInventoryConfigurations removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// GetBucketReplicationConfiguration gets an object id that corresponds to object with bucket replication configuration.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// PutBucketReplicationConfiguration puts a node to a system tree and returns objectID of previous
+	// replication configuration which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketReplicationConfiguration removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// GetBucketAnalyticsConfigurations gets an object id that corresponds to object with bucket analytics configurations.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// PutBucketAnalyticsConfigurations puts a node to a system tree and returns objectID of previous
+	// analytics configurations which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketAnalyticsConfigurations removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// GetBucketMetricsConfigurations gets an object id that corresponds to object with bucket metrics configurations.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// PutBucketMetricsConfigurations puts a node to a system tree and returns objectID of previous
+	// metrics configurations which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error)
+
+	// DeleteBucketMetricsConfigurations removes a node from a system tree and returns objID which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	DeleteBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// AddBucketConfigChange adds a node with an object id of the bucket configuration change to a system tree.
+	AddBucketConfigChange(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) error
+
+	// GetBucketConfigChanges gets object ids of all bucket configuration changes.
+	GetBucketConfigChanges(ctx context.Context, bktInfo *data.BucketInfo) ([]oid.ID, error)
+
+	// AddTrashVersion adds a node of the object version moved to the bucket trash to a system tree.
+	AddTrashVersion(ctx context.Context, bktInfo *data.BucketInfo, trashVersion *data.TrashVersion) (uint64, error)
+
+	// GetTrashVersions gets all object versions from the bucket trash.
+	GetTrashVersions(ctx context.Context, bktInfo *data.BucketInfo) ([]*data.TrashVersion, error)
+
+	// RemoveTrashVersion removes a node of the object version from the bucket trash.
+	RemoveTrashVersion(ctx context.Context, bktInfo *data.BucketInfo, id uint64) error
+
+	// GetBucketPackIndexNode gets an object id that corresponds to object with bucket pack index.
+	//
+	// If object id is not found returns ErrNodeNotFound error.
+	GetBucketPackIndexNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
+
+	// PutBucketPackIndexNode puts a node to a system tree
+	// and returns objectID of a previous pack index object which must be deleted in NeoFS.
+	//
+	// If object id to remove is not found returns ErrNoNodeToRemove error.
+	PutBucketPackIndexNode(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error)
+
 	GetObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, error)
 	PutObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, tagSet map[string]string) error
 	DeleteObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) error
@@ -57,12 +191,41 @@ type TreeService interface {
 	GetLatestVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error)
 	GetAllVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error)
 	GetUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error)
+
+	// GetVersionsShards splits version nodes with the prefix into shards which can be fetched concurrently.
+	GetVersionsShards(ctx context.Context, bktInfo *data.BucketInfo, prefix string, latestOnly bool) ([]*data.VersionsShard, error)
+	// GetShardVersions returns version nodes of the shard got from GetVersionsShards.
+	GetShardVersions(ctx context.Context, bktInfo *data.BucketInfo, shard *data.VersionsShard, latestOnly bool) ([]*data.NodeVersion, error)
 	AddVersion(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion) (uint64, error)
 	RemoveVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) error
 
+	// PackVersion updates the existing version node with the location of the object in the pack object.
+	PackVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error
+	// UpdateVersionMetadata updates user metadata and content type of the existing version node.
+	UpdateVersionMetadata(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error
+	// MoveVersion moves the existing version node with its child nodes to the new object name by a single
+	// tree operation. Versions of the new name are not changed.
+	MoveVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, newName string) error
+
 	PutLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, lock *data.LockInfo) error
 	GetLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) (*data.LockInfo, error)
 
+	// GetReplicationStatus returns the replication state of the version saved by PutReplicationStatus,
+	// the empty state is returned if it isn't saved.
+	GetReplicationStatus(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (string, error)
+	// PutReplicationStatus saves the replication state of the existing version without changing the version node.
+	PutReplicationStatus(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, status string) error
+
+	// ArchiveVersion updates the existing version node with the location of the archived object.
+	ArchiveVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error
+	// GetRestoreState returns the restore state of the archived version saved by PutRestoreState,
+	// nil is returned if it isn't saved.
+	GetRestoreState(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (*data.RestoreInfo, error)
+	// PutRestoreState saves the restore state of the archived version without changing the version node.
+	PutRestoreState(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, restore *data.RestoreInfo) error
+	// DeleteRestoreState removes the restore state of the archived version.
+	DeleteRestoreState(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) error
+
 	CreateMultipartUpload(ctx context.Context, bktInfo *data.BucketInfo, info *data.MultipartInfo) error
 	DeleteMultipartUpload(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) error
 	GetMultipartUploadsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.MultipartInfo, error)
@@ -36,6 +36,12 @@ type (
 		NextContinuationToken string
 	}
 
+	// ListBucketsInfo holds data which ListBuckets returns.
+	ListBucketsInfo struct {
+		Buckets           []*data.BucketInfo
+		ContinuationToken string
+	}
+
 	// ListObjectVersionsInfo stores info and list of objects versions.
 	ListObjectVersionsInfo struct {
 		CommonPrefixes      []string
@@ -81,6 +87,11 @@ func objectInfoFromMeta(bkt *data.BucketInfo, meta *object.Object) *data.ObjectI
 		delete(headers, object.AttributeTimestamp)
 	}
 
+	owner := *meta.OwnerID()
+	if val, ok := headers[AttributeObjectOwner]; ok {
+		_ = owner.DecodeString(val)
+	}
+
 	objID, _ := meta.ID()
 	payloadChecksum, _ := meta.PayloadChecksum()
 	return &data.ObjectInfo{
@@ -90,10 +101,11 @@ func objectInfoFromMeta(bkt *data.BucketInfo, meta *object.Object) *data.ObjectI
 
 		Bucket:      bkt.Name,
 		Name:        filepathFromObject(meta),
+		FilePath:    filepathFromObject(meta),
 		Created:     creation,
 		ContentType: mimeType,
 		Headers:     headers,
-		Owner:       *meta.OwnerID(),
+		Owner:       owner,
 		Size:        int64(meta.PayloadSize()),
 		HashSum:     hex.EncodeToString(payloadChecksum.Value()),
 	}
@@ -102,13 +114,29 @@ func objectInfoFromMeta(bkt *data.BucketInfo, meta *object.Object) *data.ObjectI
 func FormEncryptionInfo(headers map[string]string) encryption.ObjectEncryption {
 	algorithm := headers[AttributeEncryptionAlgorithm]
 	return encryption.ObjectEncryption{
-		Enabled:   len(algorithm) > 0,
-		Algorithm: algorithm,
-		HMACKey:   headers[AttributeHMACKey],
-		HMACSalt:  headers[AttributeHMACSalt],
+		Enabled:    len(algorithm) > 0,
+		Algorithm:  algorithm,
+		HMACKey:    headers[AttributeHMACKey],
+		HMACSalt:   headers[AttributeHMACSalt],
+		WrappedKey: headers[AttributeWrappedKey],
+		KMSKeyID:   headers[AttributeKMSKeyID],
 	}
 }
 
+// decryptedObjectSize returns the size of the object payload before encryption.
+func decryptedObjectSize(objInfo *data.ObjectInfo) (int64, error) {
+	if !FormEncryptionInfo(objInfo.Headers).Enabled {
+		return objInfo.Size, nil
+	}
+
+	size, err := strconv.ParseInt(objInfo.Headers[AttributeDecryptedSize], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse decrypted size: %w", err)
+	}
+
+	return size, nil
+}
+
 func addEncryptionHeaders(meta map[string]string, enc encryption.Params) error {
 	meta[AttributeEncryptionAlgorithm] = AESEncryptionAlgorithm
 	hmacKey, hmacSalt, err := enc.HMAC()
@@ -117,6 +145,12 @@ func addEncryptionHeaders(meta map[string]string, enc encryption.Params) error {
 	}
 	meta[AttributeHMACKey] = hex.EncodeToString(hmacKey)
 	meta[AttributeHMACSalt] = hex.EncodeToString(hmacSalt)
+	if enc.Managed() {
+		meta[AttributeWrappedKey] = hex.EncodeToString(enc.WrappedKey())
+	}
+	if enc.KMS() {
+		meta[AttributeKMSKeyID] = enc.KMSKeyID()
+	}
 
 	return nil
 }
@@ -0,0 +1,69 @@
+package layer
+
+import (
+	"sync"
+
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+)
+
+type (
+	// versionLocks serializes changes of version nodes of the same object made by the gateway,
+	// so packing can't overwrite the node changed since it was read.
+	versionLocks struct {
+		mu    sync.Mutex
+		locks map[string]*versionLock
+	}
+
+	versionLock struct {
+		sync.Mutex
+		refs int
+	}
+)
+
+func newVersionLocks() *versionLocks {
+	return &versionLocks{locks: make(map[string]*versionLock)}
+}
+
+// lock locks version nodes of the object and returns the function to unlock them.
+func (l *versionLocks) lock(cnrID cid.ID, object string) func() {
+	key := cnrID.EncodeToString() + "/" + object
+
+	l.mu.Lock()
+	vl, ok := l.locks[key]
+	if !ok {
+		vl = new(versionLock)
+		l.locks[key] = vl
+	}
+	vl.refs++
+	l.mu.Unlock()
+
+	vl.Lock()
+
+	return func() {
+		vl.Unlock()
+
+		l.mu.Lock()
+		if vl.refs--; vl.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// lockPair locks version nodes of both objects in the same order for all callers.
+func (l *versionLocks) lockPair(cnrID cid.ID, first, second string) func() {
+	if first == second {
+		return l.lock(cnrID, first)
+	}
+	if second < first {
+		first, second = second, first
+	}
+
+	unlockFirst := l.lock(cnrID, first)
+	unlockSecond := l.lock(cnrID, second)
+
+	return func() {
+		unlockSecond()
+		unlockFirst()
+	}
+}
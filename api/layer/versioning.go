@@ -7,12 +7,23 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 )
 
+// ListObjectVersions lists versions of objects sorted by name and from the latest to the oldest version.
+// Common prefixes are listed along with versions and count towards MaxKeys. The listing continues after
+// the version VersionIDMarker of the object KeyMarker or after all versions of KeyMarker if the version
+// marker isn't set.
 func (n *layer) ListObjectVersions(ctx context.Context, p *ListObjectVersionsParams) (*ListObjectVersionsInfo, error) {
 	var (
 		allObjects = make([]*data.ExtendedObjectInfo, 0, p.MaxKeys)
-		res        = &ListObjectVersionsInfo{}
+		res        = &ListObjectVersionsInfo{
+			KeyMarker:       p.KeyMarker,
+			VersionIDMarker: p.VersionIDMarker,
+		}
 	)
 
+	if p.MaxKeys == 0 {
+		return res, nil
+	}
+
 	versions, err := n.getAllObjectsVersions(ctx, p.BktInfo, p.Prefix, p.Delimiter)
 	if err != nil {
 		return nil, err
@@ -36,29 +47,43 @@ func (n *layer) ListObjectVersions(ctx context.Context, p *ListObjectVersionsPar
 		}
 	}
 
-	for i, obj := range allObjects {
-		if obj.ObjectInfo.Name >= p.KeyMarker && obj.ObjectInfo.VersionID() >= p.VersionIDMarker {
-			allObjects = allObjects[i:]
-			break
-		}
-	}
-
-	res.CommonPrefixes, allObjects = triageExtendedObjects(allObjects)
+	allObjects = skipVersionsToMarker(allObjects, p.KeyMarker, p.VersionIDMarker)
 
 	if len(allObjects) > p.MaxKeys {
 		res.IsTruncated = true
-		res.NextKeyMarker = allObjects[p.MaxKeys].ObjectInfo.Name
-		res.NextVersionIDMarker = allObjects[p.MaxKeys].ObjectInfo.VersionID()
-
 		allObjects = allObjects[:p.MaxKeys]
-		res.KeyMarker = allObjects[p.MaxKeys-1].ObjectInfo.Name
-		res.VersionIDMarker = allObjects[p.MaxKeys-1].ObjectInfo.VersionID()
+
+		// the next page starts after the last listed version or common prefix
+		last := allObjects[p.MaxKeys-1]
+		res.NextKeyMarker = last.ObjectInfo.Name
+		if !last.ObjectInfo.IsDir {
+			res.NextVersionIDMarker = last.Version()
+		}
 	}
 
+	res.CommonPrefixes, allObjects = triageExtendedObjects(allObjects)
 	res.Version, res.DeleteMarker = triageVersions(allObjects)
 	return res, nil
 }
 
+// skipVersionsToMarker returns sorted versions after the version marker of the key marker. All versions of the key
+// marker are skipped if the version marker is empty or the version doesn't exist anymore.
+func skipVersionsToMarker(versions []*data.ExtendedObjectInfo, keyMarker, versionIDMarker string) []*data.ExtendedObjectInfo {
+	if keyMarker == "" {
+		return versions
+	}
+
+	for i, version := range versions {
+		if version.ObjectInfo.Name > keyMarker {
+			return versions[i:]
+		}
+		if versionIDMarker != "" && version.ObjectInfo.Name == keyMarker && version.Version() == versionIDMarker {
+			return versions[i+1:]
+		}
+	}
+	return nil
+}
+
 func triageVersions(objVersions []*data.ExtendedObjectInfo) ([]*data.ExtendedObjectInfo, []*data.ExtendedObjectInfo) {
 	if len(objVersions) == 0 {
 		return nil, nil
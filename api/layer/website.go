@@ -0,0 +1,212 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	errorsStd "errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"go.uber.org/zap"
+)
+
+// PutBucketWebsiteParams stores PutBucketWebsite request parameters.
+type PutBucketWebsiteParams struct {
+	BktInfo       *data.BucketInfo
+	Configuration *data.WebsiteConfiguration
+	CopiesNumber  uint32
+}
+
+// PutBucketWebsite saves the website configuration as a bucket system object.
+func (n *layer) PutBucketWebsite(ctx context.Context, p *PutBucketWebsiteParams) error {
+	if err := checkWebsite(p.Configuration); err != nil {
+		return err
+	}
+
+	confXML, err := xml.Marshal(p.Configuration)
+	if err != nil {
+		return fmt.Errorf("marshal website configuration: %w", err)
+	}
+
+	prevValue := n.marshaledWebsite(ctx, p.BktInfo)
+
+	prm := PrmObjectCreate{
+		Container:    p.BktInfo.CID,
+		Creator:      p.BktInfo.Owner,
+		Payload:      bytes.NewReader(confXML),
+		Filepath:     n.systemObjectName(p.BktInfo.WebsiteConfigurationObjectName()),
+		CreationTime: TimeNow(ctx),
+		CopiesNumber: p.CopiesNumber,
+	}
+
+	objID, _, err := n.objectPutAndHash(ctx, prm, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("put system object: %w", err)
+	}
+
+	objIDToDelete, err := n.treeService.PutBucketWebsite(ctx, p.BktInfo, objID, confXML)
+	objIDToDeleteNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDToDeleteNotFound {
+		return err
+	}
+
+	if !objIDToDeleteNotFound {
+		if err = n.objectDelete(ctx, p.BktInfo, objIDToDelete); err != nil {
+			n.log.Error("couldn't delete website configuration object", zap.Error(err),
+				zap.String("cnrID", p.BktInfo.CID.EncodeToString()),
+				zap.String("bucket name", p.BktInfo.Name),
+				zap.String("objID", objIDToDelete.EncodeToString()))
+		}
+	}
+
+	n.cache.PutWebsiteConfiguration(n.Owner(ctx), p.BktInfo, p.Configuration)
+	return n.saveBucketConfigChange(ctx, p.BktInfo, ConfigTypeWebsite, prevValue, p.CopiesNumber)
+}
+
+// GetBucketWebsite returns the website configuration of the bucket. The configuration is read
+// from the tree copy, so it's available for anonymous requests to the website. Absence of the
+// configuration is cached too, because it's checked for every request to the website.
+func (n *layer) GetBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) (*data.WebsiteConfiguration, error) {
+	owner := n.Owner(ctx)
+	conf := n.cache.GetWebsiteConfiguration(owner, bktInfo)
+	if conf == nil {
+		confXML, err := n.treeService.GetBucketWebsite(ctx, bktInfo)
+		if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+			return nil, err
+		}
+
+		// empty configuration means that the bucket isn't configured for website hosting
+		conf = &data.WebsiteConfiguration{}
+		if err == nil {
+			if err = xml.Unmarshal(confXML, conf); err != nil {
+				return nil, fmt.Errorf("unmarshal website configuration: %w", err)
+			}
+		}
+
+		n.cache.PutWebsiteConfiguration(owner, bktInfo, conf)
+	}
+
+	if conf.RedirectAllRequestsTo == nil && conf.IndexDocument == nil {
+		return nil, errors.GetAPIError(errors.ErrNoSuchWebsiteConfiguration)
+	}
+
+	return conf, nil
+}
+
+// DeleteBucketWebsite removes the website configuration of the bucket.
+func (n *layer) DeleteBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) error {
+	prevValue := n.marshaledWebsite(ctx, bktInfo)
+
+	objID, err := n.treeService.DeleteBucketWebsite(ctx, bktInfo)
+	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
+	if err != nil && !objIDNotFound {
+		return err
+	}
+	if !objIDNotFound {
+		if err = n.objectDelete(ctx, bktInfo, objID); err != nil {
+			return err
+		}
+	}
+
+	n.cache.DeleteWebsiteConfiguration(bktInfo)
+
+	if !objIDNotFound {
+		return n.saveBucketConfigChange(ctx, bktInfo, ConfigTypeWebsite, prevValue, 0)
+	}
+
+	return nil
+}
+
+// marshaledWebsite returns current bucket website configuration in XML to save it in the bucket history.
+func (n *layer) marshaledWebsite(ctx context.Context, bktInfo *data.BucketInfo) []byte {
+	conf, err := n.GetBucketWebsite(ctx, bktInfo)
+	if err != nil {
+		if !errors.IsS3Error(err, errors.ErrNoSuchWebsiteConfiguration) {
+			n.log.Warn("couldn't get previous bucket website configuration", zap.Error(err))
+		}
+		return nil
+	}
+
+	confXML, err := xml.Marshal(conf)
+	if err != nil {
+		n.log.Warn("couldn't marshal previous bucket website configuration", zap.Error(err))
+		return nil
+	}
+
+	return confXML
+}
+
+func checkWebsite(conf *data.WebsiteConfiguration) error {
+	if redirect := conf.RedirectAllRequestsTo; redirect != nil {
+		if conf.IndexDocument != nil || conf.ErrorDocument != nil || len(conf.RoutingRules) != 0 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+				errorsStd.New("redirect of all requests can't be combined with other website configuration"))
+		}
+		if len(redirect.HostName) == 0 {
+			return errors.GetAPIError(errors.ErrMalformedXML)
+		}
+		return checkWebsiteProtocol(redirect.Protocol)
+	}
+
+	if conf.IndexDocument == nil {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("index document must be specified"))
+	}
+	if suffix := conf.IndexDocument.Suffix; len(suffix) == 0 || strings.Contains(suffix, "/") || api.CheckObjectName(suffix) != nil {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("index document suffix must be a non-empty valid object name without slash"))
+	}
+	if conf.ErrorDocument != nil && len(conf.ErrorDocument.Key) == 0 {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("error document key must be non-empty"))
+	}
+
+	for i := range conf.RoutingRules {
+		if err := checkWebsiteRoutingRule(&conf.RoutingRules[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkWebsiteRoutingRule(rule *data.WebsiteRoutingRule) error {
+	if cond := rule.Condition; cond != nil {
+		if len(cond.HTTPErrorCodeReturnedEquals) == 0 && len(cond.KeyPrefixEquals) == 0 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, errorsStd.New("routing rule condition must be non-empty"))
+		}
+		if len(cond.HTTPErrorCodeReturnedEquals) != 0 {
+			code, err := strconv.Atoi(cond.HTTPErrorCodeReturnedEquals)
+			if err != nil || code < 400 || code > 599 {
+				return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+					fmt.Errorf("invalid routing rule error code '%s'", cond.HTTPErrorCodeReturnedEquals))
+			}
+		}
+	}
+
+	redirect := rule.Redirect
+	if len(redirect.ReplaceKeyWith) != 0 && redirect.ReplaceKeyPrefixWith != nil {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+			errorsStd.New("routing rule can't replace both the key and the key prefix"))
+	}
+	if len(redirect.HTTPRedirectCode) != 0 {
+		code, err := strconv.Atoi(redirect.HTTPRedirectCode)
+		if err != nil || code < 300 || code > 399 {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidArgument,
+				fmt.Errorf("invalid routing rule redirect code '%s'", redirect.HTTPRedirectCode))
+		}
+	}
+
+	return checkWebsiteProtocol(redirect.Protocol)
+}
+
+func checkWebsiteProtocol(protocol string) error {
+	switch protocol {
+	case "", "http", "https":
+		return nil
+	default:
+		return errors.GetAPIErrorWithError(errors.ErrInvalidArgument, fmt.Errorf("invalid redirect protocol '%s'", protocol))
+	}
+}
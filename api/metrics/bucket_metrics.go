@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	bucketMetricsRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Name:      "bucket_requests_total",
+			Help:      "Number of requests matched by bucket metrics configurations",
+		},
+		[]string{"bucket", "id", "method"},
+	)
+
+	bucketMetricsErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Name:      "bucket_errors_total",
+			Help:      "Number of failed requests matched by bucket metrics configurations",
+		},
+		[]string{
+			"bucket",
+			"id",
+			// "4xx" for client errors, "5xx" for server errors
+			"class",
+		},
+	)
+
+	bucketMetricsBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Name:      "bucket_bytes_total",
+			Help:      "Traffic of requests matched by bucket metrics configurations",
+		},
+		[]string{
+			"bucket",
+			"id",
+			// "uploaded" for request payload, "downloaded" for response payload
+			"direction",
+		},
+	)
+)
+
+// BucketMetrics serves the request and counts it, its errors and traffic for every matched
+// metrics configuration of the bucket.
+func BucketMetrics(bucket string, ids []string, h http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := &readCounter{ReadCloser: r.Body}
+		out := &writeCounter{ResponseWriter: w}
+		statsWriter := &responseWrapper{ResponseWriter: out}
+
+		r.Body = in
+
+		h.ServeHTTP(statsWriter, r)
+
+		var class string
+		switch {
+		case statsWriter.statusCode >= http.StatusInternalServerError:
+			class = "5xx"
+		case statsWriter.statusCode >= http.StatusBadRequest:
+			class = "4xx"
+		}
+
+		for _, id := range ids {
+			bucketMetricsRequests.WithLabelValues(bucket, id, r.Method).Inc()
+			if class != "" {
+				bucketMetricsErrors.WithLabelValues(bucket, id, class).Inc()
+			}
+			bucketMetricsBytes.WithLabelValues(bucket, id, "uploaded").Add(float64(in.countBytes))
+			bucketMetricsBytes.WithLabelValues(bucket, id, "downloaded").Add(float64(out.countBytes))
+		}
+	}
+}
@@ -22,6 +22,28 @@ var (
 		},
 	)
 
+	replicaReadFallbacks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Name:      "replica_read_fallbacks_total",
+			Help:      "Number of object reads from the replica container after failed read from the bucket container",
+		},
+		[]string{
+			// replica network name, empty for the bucket network
+			"network",
+			// "success" or "failure" of the replica read
+			"result",
+		},
+	)
+
+	expiredCredentials = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Name:      "expired_credentials_total",
+			Help:      "Number of requests rejected because the bearer token of the credentials is expired",
+		},
+	)
+
 	statsMetrics = &stats{
 		desc: prometheus.NewDesc("neofs_s3_stats", "Statistics exposed by NeoFS S3 Gate instance", nil, nil),
 	}
@@ -31,6 +53,28 @@ func init() {
 	prometheus.MustRegister(versionInfo)
 	prometheus.MustRegister(statsMetrics)
 	prometheus.MustRegister(httpRequestsDuration)
+	prometheus.MustRegister(replicaReadFallbacks)
+	prometheus.MustRegister(expiredCredentials)
+	prometheus.MustRegister(requesterPaysRequests)
+	prometheus.MustRegister(requesterPaysBytes)
+	prometheus.MustRegister(bucketMetricsRequests)
+	prometheus.MustRegister(bucketMetricsErrors)
+	prometheus.MustRegister(bucketMetricsBytes)
+}
+
+// ReplicaReadFallback counts the read of the object replica made because the object
+// couldn't be read from the bucket container.
+func ReplicaReadFallback(network string, success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	replicaReadFallbacks.WithLabelValues(network, result).Inc()
+}
+
+// ExpiredCredentials counts the request rejected because of the expired bearer token.
+func ExpiredCredentials() {
+	expiredCredentials.Inc()
 }
 
 func collectNetworkMetrics(ch chan<- prometheus.Metric) {
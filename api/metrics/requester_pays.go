@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	requesterPaysRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Name:      "requester_pays_requests_total",
+			Help:      "Number of requests to requester pays buckets charged to the requester",
+		},
+		[]string{"bucket", "access_key_id"},
+	)
+
+	requesterPaysBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Name:      "requester_pays_bytes_total",
+			Help:      "Traffic of requests to requester pays buckets charged to the requester",
+		},
+		[]string{
+			"bucket",
+			"access_key_id",
+			// "rx" for request payload, "tx" for response payload
+			"direction",
+		},
+	)
+)
+
+// RequesterPays serves the request charged to the requester and attributes the request
+// and its traffic to the access key of the requester.
+func RequesterPays(bucket, accessKeyID string, h http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := &readCounter{ReadCloser: r.Body}
+		out := &writeCounter{ResponseWriter: w}
+
+		r.Body = in
+
+		h.ServeHTTP(out, r)
+
+		requesterPaysRequests.WithLabelValues(bucket, accessKeyID).Inc()
+		requesterPaysBytes.WithLabelValues(bucket, accessKeyID, "rx").Add(float64(in.countBytes))
+		requesterPaysBytes.WithLabelValues(bucket, accessKeyID, "tx").Add(float64(out.countBytes))
+	}
+}
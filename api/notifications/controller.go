@@ -10,6 +10,7 @@ import (
 	"github.com/nats-io/nats.go"
 	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +32,11 @@ type (
 		TLSAuthPrivateKeyFilePath string
 		Timeout                   time.Duration
 		RootCAFiles               []string
+		// QueueSize is a max number of events waiting for publishing to a single target.
+		QueueSize int
+		// ParkTimeout is a max time the request waits for space in the full queue
+		// if the bucket parks notification events.
+		ParkTimeout time.Duration
 	}
 
 	Controller struct {
@@ -39,6 +45,7 @@ type (
 		jsClient            nats.JetStreamContext
 		handlers            map[string]Stream
 		mu                  sync.RWMutex
+		queues              *targetQueues
 	}
 
 	Stream struct {
@@ -123,12 +130,15 @@ func NewController(p *Options, l *zap.Logger) (*Controller, error) {
 		return nil, fmt.Errorf("get jet stream: %w", err)
 	}
 
-	return &Controller{
+	c := &Controller{
 		logger:              l,
 		taskQueueConnection: nc,
 		jsClient:            js,
 		handlers:            make(map[string]Stream),
-	}, nil
+	}
+	c.queues = newTargetQueues(l, p.QueueSize, p.ParkTimeout, c.publish)
+
+	return c, nil
 }
 
 func (c *Controller) Subscribe(ctx context.Context, topic string, handler layer.MsgHandler) error {
@@ -188,15 +198,27 @@ func (c *Controller) SendNotifications(topics map[string]string, p *handler.Send
 		msg, err := json.Marshal(event)
 		if err != nil {
 			c.logger.Error("couldn't marshal an event", zap.String("subject", topic), zap.Error(err))
+			continue
 		}
-		if err = c.publish(topic, msg); err != nil {
-			c.logger.Error("couldn't send an event to topic", zap.String("subject", topic), zap.Error(err))
+		if !c.queues.enqueue(topic, msg, p.QueuePolicy) {
+			c.logger.Warn("notification queue is full, event is dropped", zap.String("subject", topic),
+				zap.String("bucket", p.BktInfo.Name), zap.String("object", p.NotificationInfo.Name))
 		}
 	}
 
 	return nil
 }
 
+// Describe implements prometheus.Collector.
+func (c *Controller) Describe(ch chan<- *prometheus.Desc) {
+	c.queues.describe(ch)
+}
+
+// Collect implements prometheus.Collector, it exposes queue depth and dropped events of notification targets.
+func (c *Controller) Collect(ch chan<- prometheus.Metric) {
+	c.queues.collect(ch)
+}
+
 func (c *Controller) SendTestNotification(topic, bucketName, requestID, HostID string, now time.Time) error {
 	event := &TestEvent{
 		Service:   "NeoFS S3",
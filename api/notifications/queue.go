@@ -0,0 +1,132 @@
+package notifications
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultQueueSize is a default number of events waiting for publishing to a single target.
+	DefaultQueueSize = 1000
+	// DefaultParkTimeout is a default time the request waits for space in the full queue of the target
+	// if events of the bucket are parked.
+	DefaultParkTimeout = time.Second
+)
+
+type (
+	// targetQueues publishes events to notification targets in background. Every target has its own
+	// bounded queue and worker, so a slow or unavailable target doesn't delay requests and events of other targets.
+	targetQueues struct {
+		log         *zap.Logger
+		publish     func(topic string, msg []byte) error
+		size        int
+		parkTimeout time.Duration
+
+		mu      sync.Mutex
+		targets map[string]*targetQueue
+	}
+
+	targetQueue struct {
+		events chan []byte
+		// dropped is a number of events dropped because the queue was full.
+		dropped uint64
+	}
+)
+
+var (
+	queueDepthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("neofs_s3_gw", "notifications", "queue_depth"),
+		"Number of events waiting for publishing to the notification target",
+		[]string{"topic"}, nil)
+
+	droppedEventsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("neofs_s3_gw", "notifications", "dropped_events_total"),
+		"Number of events dropped because the queue of the notification target was full",
+		[]string{"topic"}, nil)
+)
+
+func newTargetQueues(log *zap.Logger, size int, parkTimeout time.Duration, publish func(topic string, msg []byte) error) *targetQueues {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+
+	return &targetQueues{
+		log:         log,
+		publish:     publish,
+		size:        size,
+		parkTimeout: parkTimeout,
+		targets:     make(map[string]*targetQueue),
+	}
+}
+
+// target returns the queue of the topic, the queue and its worker are created on the first event.
+func (q *targetQueues) target(topic string) *targetQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	target, ok := q.targets[topic]
+	if !ok {
+		target = &targetQueue{events: make(chan []byte, q.size)}
+		q.targets[topic] = target
+		go q.run(topic, target)
+	}
+
+	return target
+}
+
+func (q *targetQueues) run(topic string, target *targetQueue) {
+	for msg := range target.events {
+		if err := q.publish(topic, msg); err != nil {
+			q.log.Error("couldn't send an event to topic", zap.String("subject", topic), zap.Error(err))
+		}
+	}
+}
+
+// enqueue adds the event to the queue of the topic. If the queue is full, the event is dropped
+// or, with the park policy, waits for free space up to the park timeout.
+// It returns false if the event is dropped.
+func (q *targetQueues) enqueue(topic string, msg []byte, policy string) bool {
+	target := q.target(topic)
+
+	select {
+	case target.events <- msg:
+		return true
+	default:
+	}
+
+	if policy == data.NotificationQueuePolicyPark && q.parkTimeout > 0 {
+		timer := time.NewTimer(q.parkTimeout)
+		defer timer.Stop()
+
+		select {
+		case target.events <- msg:
+			return true
+		case <-timer.C:
+		}
+	}
+
+	atomic.AddUint64(&target.dropped, 1)
+	return false
+}
+
+func (q *targetQueues) describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- droppedEventsDesc
+}
+
+func (q *targetQueues) collect(ch chan<- prometheus.Metric) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for topic, target := range q.targets {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue,
+			float64(len(target.events)), topic)
+		ch <- prometheus.MustNewConstMetric(droppedEventsDesc, prometheus.CounterValue,
+			float64(atomic.LoadUint64(&target.dropped)), topic)
+	}
+}
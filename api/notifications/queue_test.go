@@ -0,0 +1,52 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTargetQueuesBackpressure(t *testing.T) {
+	// publishing to the dead target is blocked, published events of the live target are collected
+	unblock := make(chan struct{})
+	published := make(chan string, 10)
+	queues := newTargetQueues(zap.NewNop(), 2, 50*time.Millisecond, func(topic string, msg []byte) error {
+		if topic == "dead" {
+			<-unblock
+		}
+		published <- topic + ":" + string(msg)
+		return nil
+	})
+	defer close(unblock)
+
+	// the first event is taken by the worker, the next two fill the queue
+	require.True(t, queues.enqueue("dead", []byte("1"), data.NotificationQueuePolicyDrop))
+	require.Eventually(t, func() bool { return len(queues.target("dead").events) == 0 }, time.Second, time.Millisecond)
+	require.True(t, queues.enqueue("dead", []byte("2"), data.NotificationQueuePolicyDrop))
+	require.True(t, queues.enqueue("dead", []byte("3"), data.NotificationQueuePolicyDrop))
+
+	start := time.Now()
+	require.False(t, queues.enqueue("dead", []byte("4"), data.NotificationQueuePolicyDrop))
+	require.Less(t, int64(time.Since(start)), int64(50*time.Millisecond))
+
+	start = time.Now()
+	require.False(t, queues.enqueue("dead", []byte("5"), data.NotificationQueuePolicyPark))
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+
+	// other targets aren't affected
+	require.True(t, queues.enqueue("live", []byte("1"), data.NotificationQueuePolicyDrop))
+	require.Equal(t, "live:1", <-published)
+
+	require.Len(t, queues.target("dead").events, 2)
+	require.EqualValues(t, 2, queues.target("dead").dropped)
+	require.EqualValues(t, 0, queues.target("live").dropped)
+
+	// depth and dropped events are exposed for every target
+	ch := make(chan prometheus.Metric, 10)
+	queues.collect(ch)
+	require.Len(t, ch, 4)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// CheckObjectName returns InvalidObjectName error if the object name contains NUL bytes or
+// "." and ".." path segments. Clients and proxies normalize such names differently, while
+// NeoFS attributes are matched exactly, so the same request could address different objects.
+func CheckObjectName(name string) error {
+	if strings.IndexByte(name, 0) >= 0 {
+		return errors.GetAPIErrorWithError(errors.ErrInvalidObjectName, fmt.Errorf("NUL byte in object name"))
+	}
+	for _, segment := range strings.Split(name, SlashSeparator) {
+		if segment == "." || segment == ".." {
+			return errors.GetAPIErrorWithError(errors.ErrInvalidObjectName, fmt.Errorf("'%s' segment in object name", segment))
+		}
+	}
+	return nil
+}
+
+// checkObjectName rejects requests to objects with names not passing CheckObjectName.
+func checkObjectName(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqInfo := GetReqInfo(r.Context())
+		if err := CheckObjectName(reqInfo.ObjectName); err != nil {
+			WriteErrorResponse(w, reqInfo, err)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckObjectName(t *testing.T) {
+	for _, name := range []string{"", "obj", "dir/obj", "dir/", "dir//obj", ".obj", "..obj", "dir/.../obj", "dir/obj."} {
+		require.NoError(t, CheckObjectName(name), name)
+	}
+
+	for _, name := range []string{".", "..", "./obj", "../obj", "dir/./obj", "dir/../obj", "dir/..", "dir/.", "obj\x00", "dir/\x00obj"} {
+		err := CheckObjectName(name)
+		require.Error(t, err, name)
+		require.True(t, errors.IsS3Error(err, errors.ErrInvalidObjectName), name)
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+)
+
+// Responses to requests of disabled operations.
+const (
+	// OperationsResponseAccessDenied rejects requests of disabled operations with AccessDenied error.
+	OperationsResponseAccessDenied = "AccessDenied"
+	// OperationsResponseNotImplemented rejects requests of disabled operations with NotImplemented error.
+	OperationsResponseNotImplemented = "NotImplemented"
+)
+
+// Operations keeps S3 operations disabled in the deployment. Requests of disabled
+// operations are rejected before they reach the handlers.
+type Operations struct {
+	mu       sync.RWMutex
+	disabled map[string]struct{}
+	errCode  errors.ErrorCode
+}
+
+// knownOperations are names of all routes of the S3 API.
+var knownOperations = []string{
+	"AbortMultipartUpload", "CompleteMultipartUpload", "ConcatenateObjects", "CopyObject", "CreateBucket",
+	"CreateDownloadToken", "CreateMultipartUpload", "CreatePresignNonce", "DeleteBucket",
+	"DeleteBucketAnalyticsConfiguration", "DeleteBucketCors", "DeleteBucketEncryption",
+	"DeleteBucketInventoryConfiguration", "DeleteBucketLifecycle", "DeleteBucketMetricsConfiguration",
+	"DeleteBucketOwnershipControls", "DeleteBucketPolicy", "DeleteBucketReplication", "DeleteBucketTagging",
+	"DeleteBucketWebsite", "DeleteMultipleObjects", "DeleteObject", "DeleteObjectTagging", "DeletePublicAccessBlock",
+	"GetBucketACL", "GetBucketAccelerate", "GetBucketAnalyticsConfiguration", "GetBucketCors", "GetBucketEncryption",
+	"GetBucketInventoryConfiguration", "GetBucketLifecycle", "GetBucketLocation", "GetBucketLogging",
+	"GetBucketMetricsConfiguration", "GetBucketNotification", "GetBucketObjectLockConfig",
+	"GetBucketOwnershipControls", "GetBucketPolicy", "GetBucketReplication", "GetBucketRequestPayment",
+	"GetBucketTagging", "GetBucketVersioning", "GetBucketWebsite", "GetObject", "GetObjectACL", "GetObjectAttributes",
+	"GetObjectLegalHold", "GetObjectRetention", "GetObjectTagging", "GetPublicAccessBlock", "HeadBucket", "HeadObject",
+	"ListBucketAnalyticsConfigurations", "ListBucketInventoryConfigurations", "ListBucketMetricsConfigurations",
+	"ListBucketVersions", "ListBuckets", "ListMultipartUploads", "ListObjectParts", "ListObjectsV1", "ListObjectsV2",
+	"ListObjectsV2M", "ListenBucketNotification", "PostObject", "PutBucketACL", "PutBucketAccelerate",
+	"PutBucketAnalyticsConfiguration", "PutBucketCors", "PutBucketEncryption", "PutBucketInventoryConfiguration",
+	"PutBucketLifecycle", "PutBucketMetricsConfiguration", "PutBucketNotification", "PutBucketObjectLockConfig",
+	"PutBucketOwnershipControls", "PutBucketPolicy", "PutBucketReplication", "PutBucketRequestPayment",
+	"PutBucketTagging", "PutBucketVersioning", "PutBucketWebsite", "PutObject", "PutObjectACL", "PutObjectLegalHold",
+	"PutObjectRetention", "PutObjectTagging", "PutPublicAccessBlock", "RenameObject", "RestoreObject", "SearchObjects",
+	"SelectObjectContent", "UpdateObjectMetadata", "UploadPart", "UploadPartCopy",
+}
+
+// operationGroups are groups of operations which can be disabled by the group name.
+var operationGroups = map[string][]string{
+	"acl":                 {"GetBucketACL", "PutBucketACL", "GetObjectACL", "PutObjectACL"},
+	"policy":              {"GetBucketPolicy", "PutBucketPolicy", "DeleteBucketPolicy"},
+	"public_access_block": {"GetPublicAccessBlock", "PutPublicAccessBlock", "DeletePublicAccessBlock"},
+	"ownership":           {"GetBucketOwnershipControls", "PutBucketOwnershipControls", "DeleteBucketOwnershipControls"},
+	"cors":                {"GetBucketCors", "PutBucketCors", "DeleteBucketCors"},
+	"website":             {"GetBucketWebsite", "PutBucketWebsite", "DeleteBucketWebsite"},
+	"lifecycle":           {"GetBucketLifecycle", "PutBucketLifecycle", "DeleteBucketLifecycle"},
+	"encryption":          {"GetBucketEncryption", "PutBucketEncryption", "DeleteBucketEncryption"},
+	"tagging": {"GetBucketTagging", "PutBucketTagging", "DeleteBucketTagging",
+		"GetObjectTagging", "PutObjectTagging", "DeleteObjectTagging"},
+	"object_lock": {"GetBucketObjectLockConfig", "PutBucketObjectLockConfig",
+		"GetObjectLegalHold", "PutObjectLegalHold", "GetObjectRetention", "PutObjectRetention"},
+	"versioning":    {"GetBucketVersioning", "PutBucketVersioning", "ListBucketVersions"},
+	"notifications": {"GetBucketNotification", "PutBucketNotification", "ListenBucketNotification"},
+	"inventory": {"GetBucketInventoryConfiguration", "PutBucketInventoryConfiguration",
+		"DeleteBucketInventoryConfiguration", "ListBucketInventoryConfigurations"},
+	"analytics": {"GetBucketAnalyticsConfiguration", "PutBucketAnalyticsConfiguration",
+		"DeleteBucketAnalyticsConfiguration", "ListBucketAnalyticsConfigurations"},
+	"metrics": {"GetBucketMetricsConfiguration", "PutBucketMetricsConfiguration",
+		"DeleteBucketMetricsConfiguration", "ListBucketMetricsConfigurations"},
+	"replication":     {"GetBucketReplication", "PutBucketReplication", "DeleteBucketReplication"},
+	"request_payment": {"GetBucketRequestPayment", "PutBucketRequestPayment"},
+	"accelerate":      {"GetBucketAccelerate", "PutBucketAccelerate"},
+	"multipart": {"CreateMultipartUpload", "UploadPart", "UploadPartCopy", "CompleteMultipartUpload",
+		"AbortMultipartUpload", "ListMultipartUploads", "ListObjectParts"},
+}
+
+// NewOperations creates the set of disabled operations, see Update.
+func NewOperations(disabled []string, response string) (*Operations, error) {
+	o := new(Operations)
+	return o, o.Update(disabled, response)
+}
+
+// Update replaces disabled operations and the response to their requests. Disabled items are
+// operation names (e.g. DeleteBucket) or lowercase group names (e.g. acl). Empty response means
+// AccessDenied.
+func (o *Operations) Update(disabled []string, response string) error {
+	var errCode errors.ErrorCode
+	switch response {
+	case "", OperationsResponseAccessDenied:
+		errCode = errors.ErrAccessDenied
+	case OperationsResponseNotImplemented:
+		errCode = errors.ErrNotImplemented
+	default:
+		return fmt.Errorf("unknown response to disabled operations '%s'", response)
+	}
+
+	known := make(map[string]struct{}, len(knownOperations))
+	for _, name := range knownOperations {
+		known[name] = struct{}{}
+	}
+
+	set := make(map[string]struct{}, len(disabled))
+	for _, item := range disabled {
+		item = strings.TrimSpace(item)
+		if group, ok := operationGroups[item]; ok {
+			for _, name := range group {
+				set[name] = struct{}{}
+			}
+			continue
+		}
+		if _, ok := known[item]; !ok {
+			return fmt.Errorf("unknown operation or group of operations '%s'", item)
+		}
+		set[item] = struct{}{}
+	}
+
+	o.mu.Lock()
+	o.disabled = set
+	o.errCode = errCode
+	o.mu.Unlock()
+
+	return nil
+}
+
+// Disabled returns sorted names of disabled operations.
+func (o *Operations) Disabled() []string {
+	o.mu.RLock()
+	res := make([]string, 0, len(o.disabled))
+	for name := range o.disabled {
+		res = append(res, name)
+	}
+	o.mu.RUnlock()
+
+	sort.Strings(res)
+	return res
+}
+
+// check returns an error if the operation is disabled.
+func (o *Operations) check(name string) error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if _, ok := o.disabled[name]; ok {
+		return errors.GetAPIError(o.errCode)
+	}
+	return nil
+}
+
+// Middleware rejects requests of disabled operations.
+func (o *Operations) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		if route := mux.CurrentRoute(r); route != nil {
+			name = route.GetName()
+		}
+		if err := o.check(name); err != nil {
+			WriteErrorResponse(w, GetReqInfo(r.Context()), err)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func checkOperations(operations *Operations) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if operations == nil {
+			return h
+		}
+		return operations.Middleware(h)
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationGroups(t *testing.T) {
+	known := make(map[string]struct{}, len(knownOperations))
+	for _, name := range knownOperations {
+		known[name] = struct{}{}
+	}
+
+	for group, names := range operationGroups {
+		for _, name := range names {
+			_, ok := known[name]
+			require.True(t, ok, "unknown operation %s in group %s", name, group)
+		}
+	}
+}
+
+func TestOperations(t *testing.T) {
+	ops, err := NewOperations([]string{"acl", "DeleteBucket"}, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"DeleteBucket", "GetBucketACL", "GetObjectACL", "PutBucketACL", "PutObjectACL"}, ops.Disabled())
+
+	r := mux.NewRouter()
+	r.Use(checkOperations(ops))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.Methods(http.MethodDelete).Path("/{bucket}").HandlerFunc(ok).Name("DeleteBucket")
+	r.Methods(http.MethodGet).Path("/{bucket}").HandlerFunc(ok).Name("ListObjectsV1")
+
+	serve := func(method string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/bucket", nil))
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusForbidden, serve(http.MethodDelete))
+	require.Equal(t, http.StatusOK, serve(http.MethodGet))
+
+	require.NoError(t, ops.Update([]string{"DeleteBucket"}, OperationsResponseNotImplemented))
+	require.Equal(t, http.StatusNotImplemented, serve(http.MethodDelete))
+
+	require.NoError(t, ops.Update(nil, ""))
+	require.Equal(t, http.StatusOK, serve(http.MethodDelete))
+
+	require.Error(t, ops.Update([]string{"DeleteBuckets"}, ""))
+	require.Error(t, ops.Update(nil, "Forbidden"))
+}
@@ -0,0 +1,263 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	errorsStd "errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// Policy is a bucket policy document.
+	Policy struct {
+		Version   string      `json:"Version,omitempty"`
+		ID        string      `json:"Id,omitempty"`
+		Statement []Statement `json:"Statement"`
+	}
+
+	// Statement is a single rule of the bucket policy.
+	Statement struct {
+		Sid       string     `json:"Sid,omitempty"`
+		Effect    string     `json:"Effect"`
+		Principal Principal  `json:"Principal"`
+		Action    StringList `json:"Action"`
+		Resource  StringList `json:"Resource"`
+	}
+
+	// Principal is a set of users the statement is applied to.
+	// Wildcard "*" principal (or "AWS": "*") matches all users including anonymous ones.
+	Principal struct {
+		AWS           StringList `json:"AWS,omitempty"`
+		CanonicalUser StringList `json:"CanonicalUser,omitempty"`
+	}
+
+	// StringList is a list of strings which is a single string or an array in JSON.
+	StringList []string
+
+	// Request describes the request checked against the policy.
+	Request struct {
+		// Principal is a canonical user ID (hex-encoded public key) of the requester,
+		// empty for anonymous requests.
+		Principal string
+		// Action is an S3 action, e.g. s3:GetObject.
+		Action string
+		// Resource is an ARN of the bucket or the object.
+		Resource string
+	}
+
+	// Decision is a result of the policy evaluation.
+	Decision int
+)
+
+const (
+	// NoDecision means that no statement matches the request.
+	NoDecision Decision = iota
+	// Allow means that an Allow statement matches the request and no Deny statement does.
+	Allow
+	// Deny means that a Deny statement matches the request.
+	Deny
+)
+
+const (
+	// EffectAllow is an effect of statements allowing requests.
+	EffectAllow = "Allow"
+	// EffectDeny is an effect of statements denying requests.
+	EffectDeny = "Deny"
+
+	// ArnPrefix is a prefix of bucket and object resources.
+	ArnPrefix = "arn:aws:s3:::"
+
+	wildcard     = "*"
+	actionPrefix = "s3:"
+)
+
+// Parse decodes the policy document. Unknown elements (e.g. Condition or NotAction)
+// are not supported and result in an error.
+func Parse(r io.Reader) (*Policy, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("decode policy: %w", err)
+	}
+
+	return &p, nil
+}
+
+// UnmarshalJSON decodes the principal from "*" string or from the object with principal types.
+func (p *Principal) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		if str != wildcard {
+			return fmt.Errorf("invalid principal '%s'", str)
+		}
+		p.AWS = StringList{wildcard}
+		return nil
+	}
+
+	type principal Principal
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*principal)(p))
+}
+
+// UnmarshalJSON decodes the list from a single string or from an array of strings.
+func (s *StringList) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = StringList{str}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// Validate checks that the policy is applicable to the bucket.
+func (p *Policy) Validate(bucket string) error {
+	if len(p.Statement) == 0 {
+		return errorsStd.New("policy has no statements")
+	}
+
+	for i, st := range p.Statement {
+		if st.Effect != EffectAllow && st.Effect != EffectDeny {
+			return fmt.Errorf("statement %d: invalid effect '%s'", i, st.Effect)
+		}
+
+		if len(st.Principal.AWS) == 0 && len(st.Principal.CanonicalUser) == 0 {
+			return fmt.Errorf("statement %d: empty principal", i)
+		}
+		for _, aws := range st.Principal.AWS {
+			if aws != wildcard {
+				return fmt.Errorf("statement %d: unsupported principal '%s', use CanonicalUser", i, aws)
+			}
+		}
+
+		if len(st.Action) == 0 {
+			return fmt.Errorf("statement %d: empty action", i)
+		}
+		for _, action := range st.Action {
+			if action != wildcard && !strings.HasPrefix(strings.ToLower(action), actionPrefix) {
+				return fmt.Errorf("statement %d: invalid action '%s'", i, action)
+			}
+		}
+
+		if len(st.Resource) == 0 {
+			return fmt.Errorf("statement %d: empty resource", i)
+		}
+		for _, resource := range st.Resource {
+			name := strings.TrimPrefix(resource, ArnPrefix)
+			if name == resource || (name != bucket && !strings.HasPrefix(name, bucket+"/")) {
+				return fmt.Errorf("statement %d: resource '%s' must be in the bucket '%s'", i, resource, bucket)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Evaluate checks the request against statements of the policy. Deny statements take precedence.
+func (p *Policy) Evaluate(r Request) Decision {
+	decision := NoDecision
+	for _, st := range p.Statement {
+		if !st.matches(r) {
+			continue
+		}
+		if st.Effect == EffectDeny {
+			return Deny
+		}
+		decision = Allow
+	}
+
+	return decision
+}
+
+// IsPublic checks if the policy allows access to any user.
+func (p *Policy) IsPublic() bool {
+	for _, st := range p.Statement {
+		if st.Effect != EffectAllow {
+			continue
+		}
+		for _, principal := range append(st.Principal.AWS, st.Principal.CanonicalUser...) {
+			if principal == wildcard {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s Statement) matches(r Request) bool {
+	return s.Principal.matches(r.Principal) &&
+		matchAny(s.Action, strings.ToLower(r.Action), strings.ToLower) &&
+		matchAny(s.Resource, r.Resource, nil)
+}
+
+func (p Principal) matches(principal string) bool {
+	for _, aws := range p.AWS {
+		if aws == wildcard {
+			return true
+		}
+	}
+
+	if len(principal) == 0 {
+		return false
+	}
+
+	for _, user := range p.CanonicalUser {
+		if user == wildcard || strings.EqualFold(user, principal) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchAny(patterns []string, value string, normalize func(string) string) bool {
+	for _, pattern := range patterns {
+		if normalize != nil {
+			pattern = normalize(pattern)
+		}
+		if matchWildcard(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard matches the value with the pattern where '*' matches any sequence
+// of characters and '?' matches any single character.
+func matchWildcard(pattern, value string) bool {
+	var p, v int
+	star, match := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			star, match = p, v
+			p++
+		case star != -1:
+			p = star + 1
+			match++
+			v = match
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
@@ -0,0 +1,122 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse(strings.NewReader(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": "s3:GetObject",
+			"Resource": ["arn:aws:s3:::bucket/*"]
+		}, {
+			"Effect": "Deny",
+			"Principal": {"CanonicalUser": ["user1", "user2"]},
+			"Action": ["s3:PutObject", "s3:DeleteObject"],
+			"Resource": "arn:aws:s3:::bucket"
+		}]
+	}`))
+	require.NoError(t, err)
+	require.NoError(t, p.Validate("bucket"))
+
+	require.Equal(t, StringList{wildcard}, p.Statement[0].Principal.AWS)
+	require.Equal(t, StringList{"s3:GetObject"}, p.Statement[0].Action)
+	require.Equal(t, StringList{"user1", "user2"}, p.Statement[1].Principal.CanonicalUser)
+	require.Equal(t, StringList{ArnPrefix + "bucket"}, p.Statement[1].Resource)
+
+	_, err = Parse(strings.NewReader(`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject",
+		"Resource": "arn:aws:s3:::bucket/*", "Condition": {}}]}`))
+	require.Error(t, err)
+
+	_, err = Parse(strings.NewReader(`{"Statement": [{"Effect": "Allow", "Principal": "user", "Action": "s3:GetObject",
+		"Resource": "arn:aws:s3:::bucket/*"}]}`))
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Policy {
+		return &Policy{Statement: []Statement{{
+			Effect:    EffectAllow,
+			Principal: Principal{CanonicalUser: StringList{"user"}},
+			Action:    StringList{"s3:GetObject"},
+			Resource:  StringList{ArnPrefix + "bucket/*"},
+		}}}
+	}
+	require.NoError(t, valid().Validate("bucket"))
+
+	for _, tc := range []struct {
+		name   string
+		modify func(p *Policy)
+	}{
+		{name: "no statements", modify: func(p *Policy) { p.Statement = nil }},
+		{name: "invalid effect", modify: func(p *Policy) { p.Statement[0].Effect = "allow" }},
+		{name: "empty principal", modify: func(p *Policy) { p.Statement[0].Principal = Principal{} }},
+		{name: "aws account principal", modify: func(p *Policy) { p.Statement[0].Principal.AWS = StringList{"123456789012"} }},
+		{name: "invalid action", modify: func(p *Policy) { p.Statement[0].Action = StringList{"GetObject"} }},
+		{name: "other bucket", modify: func(p *Policy) { p.Statement[0].Resource = StringList{ArnPrefix + "bucket2/*"} }},
+		{name: "no arn prefix", modify: func(p *Policy) { p.Statement[0].Resource = StringList{"bucket/*"} }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := valid()
+			tc.modify(p)
+			require.Error(t, p.Validate("bucket"))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p := &Policy{Statement: []Statement{{
+		Effect:    EffectAllow,
+		Principal: Principal{AWS: StringList{wildcard}},
+		Action:    StringList{"s3:Get*"},
+		Resource:  StringList{ArnPrefix + "bucket/public/*"},
+	}, {
+		Effect:    EffectDeny,
+		Principal: Principal{CanonicalUser: StringList{"user"}},
+		Action:    StringList{"*"},
+		Resource:  StringList{ArnPrefix + "bucket/public/secret?.txt"},
+	}}}
+
+	for _, tc := range []struct {
+		request  Request
+		expected Decision
+	}{
+		{request: Request{Action: "s3:GetObject", Resource: ArnPrefix + "bucket/public/file"}, expected: Allow},
+		{request: Request{Action: "s3:getobject", Resource: ArnPrefix + "bucket/public/file"}, expected: Allow},
+		{request: Request{Action: "s3:PutObject", Resource: ArnPrefix + "bucket/public/file"}, expected: NoDecision},
+		{request: Request{Action: "s3:GetObject", Resource: ArnPrefix + "bucket/private/file"}, expected: NoDecision},
+		{request: Request{Action: "s3:GetObject", Resource: ArnPrefix + "bucket/public/secret1.txt"}, expected: Allow},
+		{request: Request{Principal: "USER", Action: "s3:GetObject", Resource: ArnPrefix + "bucket/public/secret1.txt"}, expected: Deny},
+		{request: Request{Principal: "user", Action: "s3:GetObject", Resource: ArnPrefix + "bucket/public/secret10.txt"}, expected: Allow},
+	} {
+		require.Equal(t, tc.expected, p.Evaluate(tc.request), tc.request)
+	}
+
+	require.True(t, p.IsPublic())
+	p.Statement[0].Principal = Principal{CanonicalUser: StringList{"user"}}
+	require.False(t, p.IsPublic())
+}
+
+func TestMatchWildcard(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, value string
+		expected       bool
+	}{
+		{pattern: "*", value: "", expected: true},
+		{pattern: "*", value: "abc", expected: true},
+		{pattern: "a*c", value: "abbbc", expected: true},
+		{pattern: "a*c", value: "abbbd", expected: false},
+		{pattern: "a?c", value: "abc", expected: true},
+		{pattern: "a?c", value: "ac", expected: false},
+		{pattern: "a*b*c", value: "aXbYbZc", expected: true},
+		{pattern: "abc", value: "abcd", expected: false},
+	} {
+		require.Equal(t, tc.expected, matchWildcard(tc.pattern, tc.value), tc.pattern+" "+tc.value)
+	}
+}
@@ -31,6 +31,7 @@ type (
 		BucketName   string   // Bucket name
 		ObjectName   string   // Object name
 		URL          *url.URL // Request url
+		JSONResponse bool     // Listing and error responses are encoded in JSON instead of XML
 		tags         []KeyVal // Any additional info not accommodated by above fields
 	}
 
@@ -104,7 +105,7 @@ func GetSourceIP(r *http.Request) string {
 	return addr
 }
 
-func prepareContext(w http.ResponseWriter, r *http.Request) context.Context {
+func prepareContext(ctx context.Context, w http.ResponseWriter, r *http.Request) context.Context {
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 	object, err := url.PathUnescape(vars["object"])
@@ -118,7 +119,7 @@ func prepareContext(w http.ResponseWriter, r *http.Request) context.Context {
 	if prefix != "" {
 		object = prefix
 	}
-	return SetReqInfo(r.Context(),
+	return SetReqInfo(ctx,
 		// prepare request info
 		NewReqInfo(w, r, ObjectRequest{
 			Bucket: bucket,
@@ -136,8 +137,9 @@ func NewReqInfo(w http.ResponseWriter, r *http.Request, req ObjectRequest) *ReqI
 		UserAgent:    r.UserAgent(),
 		RemoteHost:   GetSourceIP(r),
 		RequestID:    GetRequestID(w),
-		DeploymentID: deploymentID.String(),
+		DeploymentID: deploymentID,
 		URL:          r.URL,
+		JSONResponse: jsonResponseRequested(r),
 	}
 }
 
@@ -2,10 +2,12 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
@@ -45,6 +47,10 @@ const (
 
 	hdrAmzCopySource = "X-Amz-Copy-Source"
 
+	// formatQuery is a query parameter of the extension to get listing and error responses in JSON.
+	formatQuery = "format"
+	formatJSON  = "json"
+
 	// Response request id.
 	hdrAmzRequestID = "x-amz-request-id"
 
@@ -61,7 +67,7 @@ const (
 )
 
 var (
-	deploymentID, _ = uuid.NewRandom()
+	deploymentID = uuid.New().String()
 
 	xmlHeader = []byte(xml.Header)
 )
@@ -128,11 +134,38 @@ func WriteErrorResponse(w http.ResponseWriter, reqInfo *ReqInfo, err error) int
 
 	// Generates error response.
 	errorResponse := getAPIErrorResponse(reqInfo, err)
+	if reqInfo != nil && reqInfo.JSONResponse {
+		encodedErrorResponse, _ := json.Marshal(errorResponse)
+		WriteResponse(w, code, encodedErrorResponse, MimeJSON)
+		return code
+	}
+
 	encodedErrorResponse := EncodeResponse(errorResponse)
 	WriteResponse(w, code, encodedErrorResponse, MimeXML)
 	return code
 }
 
+// jsonResponseRequested returns true if the client asks for listing and error responses in JSON
+// by `format=json` query parameter or by the Accept header listing JSON before XML. XML stays
+// the default for S3 clients.
+func jsonResponseRequested(r *http.Request) bool {
+	if format := r.URL.Query().Get(formatQuery); format != "" {
+		return strings.EqualFold(format, formatJSON)
+	}
+
+	for _, accept := range strings.Split(r.Header.Get(Accept), ",") {
+		mediaType := strings.TrimSpace(strings.Split(accept, ";")[0])
+		switch {
+		case strings.EqualFold(mediaType, string(MimeJSON)):
+			return true
+		case strings.EqualFold(mediaType, string(MimeXML)):
+			return false
+		}
+	}
+
+	return false
+}
+
 // If none of the http routes match respond with appropriate errors.
 func errorResponseHandler(w http.ResponseWriter, r *http.Request) {
 	desc := fmt.Sprintf("Unknown API request at %s", r.URL.Path)
@@ -201,6 +234,23 @@ func EncodeToResponse(w http.ResponseWriter, response interface{}) error {
 	return nil
 }
 
+// EncodeListingToResponse encodes the listing response into ResponseWriter in XML
+// or in JSON if the client asked for it.
+func EncodeListingToResponse(w http.ResponseWriter, reqInfo *ReqInfo, response interface{}) error {
+	if !reqInfo.JSONResponse {
+		return EncodeToResponse(w, response)
+	}
+
+	w.Header().Set(hdrContentType, string(MimeJSON))
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		return fmt.Errorf("encode json response: %w", err)
+	}
+
+	return nil
+}
+
 // // WriteSuccessResponseXML writes success headers and response if any,
 // // with content-type set to `application/xml`.
 // func WriteSuccessResponseXML(w http.ResponseWriter, response []byte) {
@@ -23,17 +23,24 @@ type (
 		PutObjectTaggingHandler(http.ResponseWriter, *http.Request)
 		DeleteObjectTaggingHandler(http.ResponseWriter, *http.Request)
 		SelectObjectContentHandler(http.ResponseWriter, *http.Request)
+		RestoreObjectHandler(http.ResponseWriter, *http.Request)
 		GetObjectRetentionHandler(http.ResponseWriter, *http.Request)
 		GetObjectLegalHoldHandler(http.ResponseWriter, *http.Request)
 		GetObjectHandler(http.ResponseWriter, *http.Request)
 		GetObjectAttributesHandler(http.ResponseWriter, *http.Request)
 		CopyObjectHandler(http.ResponseWriter, *http.Request)
+		RenameObjectHandler(http.ResponseWriter, *http.Request)
+		UpdateObjectMetadataHandler(http.ResponseWriter, *http.Request)
+		ConcatenateObjectsHandler(http.ResponseWriter, *http.Request)
+		CreateDownloadTokenHandler(http.ResponseWriter, *http.Request)
 		PutObjectRetentionHandler(http.ResponseWriter, *http.Request)
 		PutObjectLegalHoldHandler(http.ResponseWriter, *http.Request)
 		PutObjectHandler(http.ResponseWriter, *http.Request)
 		DeleteObjectHandler(http.ResponseWriter, *http.Request)
 		GetBucketLocationHandler(http.ResponseWriter, *http.Request)
 		GetBucketPolicyHandler(http.ResponseWriter, *http.Request)
+		GetPublicAccessBlockHandler(http.ResponseWriter, *http.Request)
+		GetBucketOwnershipControlsHandler(http.ResponseWriter, *http.Request)
 		GetBucketLifecycleHandler(http.ResponseWriter, *http.Request)
 		GetBucketEncryptionHandler(http.ResponseWriter, *http.Request)
 		GetBucketACLHandler(http.ResponseWriter, *http.Request)
@@ -43,11 +50,28 @@ type (
 		DeleteBucketCorsHandler(http.ResponseWriter, *http.Request)
 		GetBucketWebsiteHandler(http.ResponseWriter, *http.Request)
 		GetBucketAccelerateHandler(http.ResponseWriter, *http.Request)
+		PutBucketAccelerateHandler(http.ResponseWriter, *http.Request)
 		GetBucketRequestPaymentHandler(http.ResponseWriter, *http.Request)
 		GetBucketLoggingHandler(http.ResponseWriter, *http.Request)
 		GetBucketReplicationHandler(http.ResponseWriter, *http.Request)
+		PutBucketReplicationHandler(http.ResponseWriter, *http.Request)
+		DeleteBucketReplicationHandler(http.ResponseWriter, *http.Request)
 		GetBucketTaggingHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketWebsiteHandler(http.ResponseWriter, *http.Request)
+		PutBucketWebsiteHandler(http.ResponseWriter, *http.Request)
+		WebsiteHandler(http.ResponseWriter, *http.Request)
+		GetBucketInventoryConfigurationHandler(http.ResponseWriter, *http.Request)
+		ListBucketInventoryConfigurationsHandler(http.ResponseWriter, *http.Request)
+		PutBucketInventoryConfigurationHandler(http.ResponseWriter, *http.Request)
+		DeleteBucketInventoryConfigurationHandler(http.ResponseWriter, *http.Request)
+		GetBucketAnalyticsConfigurationHandler(http.ResponseWriter, *http.Request)
+		ListBucketAnalyticsConfigurationsHandler(http.ResponseWriter, *http.Request)
+		PutBucketAnalyticsConfigurationHandler(http.ResponseWriter, *http.Request)
+		DeleteBucketAnalyticsConfigurationHandler(http.ResponseWriter, *http.Request)
+		GetBucketMetricsConfigurationHandler(http.ResponseWriter, *http.Request)
+		ListBucketMetricsConfigurationsHandler(http.ResponseWriter, *http.Request)
+		PutBucketMetricsConfigurationHandler(http.ResponseWriter, *http.Request)
+		DeleteBucketMetricsConfigurationHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketTaggingHandler(http.ResponseWriter, *http.Request)
 		GetBucketObjectLockConfigHandler(http.ResponseWriter, *http.Request)
 		GetBucketVersioningHandler(http.ResponseWriter, *http.Request)
@@ -60,6 +84,9 @@ type (
 		PutBucketLifecycleHandler(http.ResponseWriter, *http.Request)
 		PutBucketEncryptionHandler(http.ResponseWriter, *http.Request)
 		PutBucketPolicyHandler(http.ResponseWriter, *http.Request)
+		PutPublicAccessBlockHandler(http.ResponseWriter, *http.Request)
+		PutBucketOwnershipControlsHandler(http.ResponseWriter, *http.Request)
+		PutBucketRequestPaymentHandler(http.ResponseWriter, *http.Request)
 		PutBucketObjectLockConfigHandler(http.ResponseWriter, *http.Request)
 		PutBucketTaggingHandler(http.ResponseWriter, *http.Request)
 		PutBucketVersioningHandler(http.ResponseWriter, *http.Request)
@@ -68,13 +95,21 @@ type (
 		HeadBucketHandler(http.ResponseWriter, *http.Request)
 		PostObject(http.ResponseWriter, *http.Request)
 		DeleteMultipleObjectsHandler(http.ResponseWriter, *http.Request)
+		SearchObjectsHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketPolicyHandler(http.ResponseWriter, *http.Request)
+		DeletePublicAccessBlockHandler(http.ResponseWriter, *http.Request)
+		DeleteBucketOwnershipControlsHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketLifecycleHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketEncryptionHandler(http.ResponseWriter, *http.Request)
 		DeleteBucketHandler(http.ResponseWriter, *http.Request)
 		ListBucketsHandler(http.ResponseWriter, *http.Request)
+		CreatePresignNonceHandler(http.ResponseWriter, *http.Request)
 		Preflight(w http.ResponseWriter, r *http.Request)
 		AppendCORSHeaders(w http.ResponseWriter, r *http.Request)
+		CheckBucketPolicy(w http.ResponseWriter, r *http.Request) bool
+		CheckRequestPayment(w http.ResponseWriter, r *http.Request) (charged bool, ok bool)
+		CheckAccelerate(w http.ResponseWriter, r *http.Request) bool
+		MatchMetricsConfigurations(r *http.Request) []string
 		CreateMultipartUploadHandler(http.ResponseWriter, *http.Request)
 		UploadPartHandler(http.ResponseWriter, *http.Request)
 		UploadPartCopy(w http.ResponseWriter, r *http.Request)
@@ -104,6 +139,9 @@ const (
 
 	// MimeXML means response type is XML.
 	MimeXML mimeType = "application/xml"
+
+	// MimeJSON means response type is JSON.
+	MimeJSON mimeType = "application/json"
 )
 
 var _ = logErrorResponse
@@ -120,16 +158,16 @@ func setRequestID(h http.Handler) http.Handler {
 		// generate random UUIDv4
 		id, _ := uuid.NewRandom()
 
+		requestID := id.String()
+
 		// set request id into response header
-		w.Header().Set(hdrAmzRequestID, id.String())
+		w.Header().Set(hdrAmzRequestID, requestID)
 
 		// set request id into gRPC meta header
-		r = r.WithContext(metadata.AppendToOutgoingContext(
-			r.Context(), hdrAmzRequestID, id.String(),
-		))
+		ctx := metadata.AppendToOutgoingContext(r.Context(), hdrAmzRequestID, requestID)
 
 		// set request info into context
-		r = r.WithContext(prepareContext(w, r))
+		r = r.WithContext(prepareContext(ctx, w, r))
 
 		// continue execution
 		h.ServeHTTP(w, r)
@@ -145,6 +183,58 @@ func appendCORS(handler Handler) mux.MiddlewareFunc {
 	}
 }
 
+func countBucketMetrics(handler Handler) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ids := handler.MatchMetricsConfigurations(r)
+			if len(ids) == 0 {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			metrics.BucketMetrics(GetReqInfo(r.Context()).BucketName, ids, h).ServeHTTP(w, r)
+		})
+	}
+}
+
+func checkBucketPolicy(handler Handler) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if handler.CheckBucketPolicy(w, r) {
+				h.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+func checkAccelerate(handler Handler) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if handler.CheckAccelerate(w, r) {
+				h.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+func checkRequestPayment(handler Handler) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			charged, ok := handler.CheckRequestPayment(w, r)
+			if !ok {
+				return
+			}
+			if !charged {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			accessKeyID, _ := r.Context().Value(AccessKeyID).(string)
+			metrics.RequesterPays(GetReqInfo(r.Context()).BucketName, accessKeyID, h).ServeHTTP(w, r)
+		})
+	}
+}
+
 func logErrorResponse(l *zap.Logger) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -183,35 +273,82 @@ func GetRequestID(v interface{}) string {
 	}
 }
 
+// bucketRouters returns routers of bucket requests to api. Virtual-hosted-style routers of accelerated domains
+// go first, since accelerated domains can be subdomains of domains, then virtual-hosted-style routers of domains,
+// so "GET /key" to the bucket host isn't matched as the path-style request to "key" bucket. The path-style router
+// is the last one, so root paths of both styles are served by the same bucket routes with the same queries.
+func bucketRouters(api *mux.Router, domains, accelerateDomains []string, h Handler) []*mux.Router {
+	buckets := make([]*mux.Router, 0, len(domains)+len(accelerateDomains)+1)
+
+	for _, domain := range accelerateDomains {
+		accelerated := api.Host("{bucket:.+}." + domain).Subrouter()
+		accelerated.Use(
+			// -- deny requests to buckets without transfer acceleration
+			checkAccelerate(h),
+		)
+		buckets = append(buckets, accelerated)
+	}
+
+	for _, domain := range domains {
+		buckets = append(buckets, api.Host("{bucket:.+}."+domain).Subrouter())
+	}
+
+	return append(buckets, api.PathPrefix("/{bucket}").Subrouter())
+}
+
 // Attach adds S3 API handlers from h to r for domains with m client limit using
-// center authentication and log logger.
-func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center auth.Center, log *zap.Logger) {
+// center authentication and log logger. Requests are served in degraded mode while
+// the storage is unavailable, nil storage state disables degraded mode. Requests of
+// disabled operations are rejected, nil operations allow all of them. Sampled requests
+// are written to the wire log, nil wire log disables it. Buckets are accessed through
+// accelerated domains only if transfer acceleration of the bucket is enabled. Uses of access keys
+// are recorded to usage, nil usage disables it.
+func Attach(r *mux.Router, domains, accelerateDomains []string, m MaxClients, storage *StorageState, control *ControlState, operations *Operations, wireLog *WireLog, h Handler, center auth.Center, usage *auth.KeyUsage, log *zap.Logger) {
 	api := r.PathPrefix(SlashSeparator).Subrouter()
 
 	api.Use(
 		// -- prepare request
 		setRequestID,
 
+		// -- capture requests and responses sampled by the rate
+		logWire(wireLog),
+
 		// -- logging error requests
 		logErrorResponse(log),
+
+		// -- fail fast if the storage is unavailable
+		degradation(storage),
+
+		// -- reject names of objects with NUL bytes and relative path segments
+		checkObjectName,
 	)
 
 	// Attach user authentication for all S3 routes.
-	AttachUserAuth(api, center, log)
+	AttachUserAuth(api, center, usage, log)
 
-	buckets := make([]*mux.Router, 0, len(domains)+1)
-	buckets = append(buckets, api.PathPrefix("/{bucket}").Subrouter())
+	api.Use(
+		// -- capture requests and responses of selected access keys
+		logWireKeys(wireLog),
 
-	for _, domain := range domains {
-		buckets = append(buckets, api.Host("{bucket:.+}."+domain).Subrouter())
-	}
+		// -- reject requests in maintenance mode, with revoked credentials or exceeding quotas
+		checkControlState(control),
 
-	for _, bucket := range buckets {
+		// -- reject requests of operations disabled in the deployment
+		checkOperations(operations),
+	)
+
+	for _, bucket := range bucketRouters(api, domains, accelerateDomains, h) {
 		// Object operations
 		// HeadObject
 		bucket.Use(
 			// -- append CORS headers to a response for
 			appendCORS(h),
+			// -- count requests matched by bucket metrics configurations
+			countBucketMetrics(h),
+			// -- deny requests according to the bucket policy
+			checkBucketPolicy(h),
+			// -- deny requests to requester pays buckets without the charge acknowledgement
+			checkRequestPayment(h),
 		)
 		bucket.Methods(http.MethodOptions).HandlerFunc(m.Handle(metrics.APIStats("preflight", h.Preflight))).Name("Options")
 		bucket.Methods(http.MethodHead).Path("/{object:.+}").HandlerFunc(
@@ -235,6 +372,14 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(
 			m.Handle(metrics.APIStats("createmultipartupload", h.CreateMultipartUploadHandler))).Queries("uploads", "").
 			Name("CreateMultipartUpload")
+		// ConcatenateObjects
+		bucket.Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(
+			m.Handle(metrics.APIStats("concatenateobjects", h.ConcatenateObjectsHandler))).Queries("concatenate", "").
+			Name("ConcatenateObjects")
+		// CreateDownloadToken
+		bucket.Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(
+			m.Handle(metrics.APIStats("createdownloadtoken", h.CreateDownloadTokenHandler))).Queries("downloadToken", "").
+			Name("CreateDownloadToken")
 		// AbortMultipartUpload
 		bucket.Methods(http.MethodDelete).Path("/{object:.+}").HandlerFunc(
 			m.Handle(metrics.APIStats("abortmultipartupload", h.AbortMultipartUploadHandler))).Queries("uploadId", "{uploadId:.*}").
@@ -267,6 +412,10 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(
 			m.Handle(metrics.APIStats("selectobjectcontent", h.SelectObjectContentHandler))).Queries("select", "").Queries("select-type", "2").
 			Name("SelectObjectContent")
+		// RestoreObject
+		bucket.Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(
+			m.Handle(metrics.APIStats("restoreobject", h.RestoreObjectHandler))).Queries("restore", "").
+			Name("RestoreObject")
 		// GetObjectRetention
 		bucket.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(
 			m.Handle(metrics.APIStats("getobjectretention", h.GetObjectRetentionHandler))).Queries("retention", "").
@@ -294,6 +443,14 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(
 			m.Handle(metrics.APIStats("putobjectlegalhold", h.PutObjectLegalHoldHandler))).Queries("legal-hold", "").
 			Name("PutObjectLegalHold")
+		// RenameObject
+		bucket.Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(
+			m.Handle(metrics.APIStats("renameobject", h.RenameObjectHandler))).Queries("renameObject", "").
+			Name("RenameObject")
+		// UpdateObjectMetadata
+		bucket.Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(
+			m.Handle(metrics.APIStats("updateobjectmetadata", h.UpdateObjectMetadataHandler))).Queries("metadata", "").
+			Name("UpdateObjectMetadata")
 
 		// PutObject
 		bucket.Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(
@@ -313,6 +470,14 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketpolicy", h.GetBucketPolicyHandler))).Queries("policy", "").
 			Name("GetBucketPolicy")
+		// GetPublicAccessBlock
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("getpublicaccessblock", h.GetPublicAccessBlockHandler))).Queries("publicAccessBlock", "").
+			Name("GetPublicAccessBlock")
+		// GetBucketOwnershipControls
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("getbucketownershipcontrols", h.GetBucketOwnershipControlsHandler))).Queries("ownershipControls", "").
+			Name("GetBucketOwnershipControls")
 		// GetBucketLifecycle
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketlifecycle", h.GetBucketLifecycleHandler))).Queries("lifecycle", "").
@@ -339,15 +504,39 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("putbucketacl", h.PutBucketACLHandler))).Queries("acl", "").
 			Name("PutBucketACL")
-		// GetBucketWebsiteHandler -- this is a dummy call.
+		// GetBucketWebsite
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketwebsite", h.GetBucketWebsiteHandler))).Queries("website", "").
 			Name("GetBucketWebsite")
-		// GetBucketAccelerateHandler -- this is a dummy call.
+		// GetBucketInventoryConfiguration
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("getbucketinventoryconfiguration", h.GetBucketInventoryConfigurationHandler))).Queries("inventory", "", "id", "{id}").
+			Name("GetBucketInventoryConfiguration")
+		// ListBucketInventoryConfigurations
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("listbucketinventoryconfigurations", h.ListBucketInventoryConfigurationsHandler))).Queries("inventory", "").
+			Name("ListBucketInventoryConfigurations")
+		// GetBucketAnalyticsConfiguration
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("getbucketanalyticsconfiguration", h.GetBucketAnalyticsConfigurationHandler))).Queries("analytics", "", "id", "{id}").
+			Name("GetBucketAnalyticsConfiguration")
+		// ListBucketAnalyticsConfigurations
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("listbucketanalyticsconfigurations", h.ListBucketAnalyticsConfigurationsHandler))).Queries("analytics", "").
+			Name("ListBucketAnalyticsConfigurations")
+		// GetBucketMetricsConfiguration
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("getbucketmetricsconfiguration", h.GetBucketMetricsConfigurationHandler))).Queries("metrics", "", "id", "{id}").
+			Name("GetBucketMetricsConfiguration")
+		// ListBucketMetricsConfigurations
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(metrics.APIStats("listbucketmetricsconfigurations", h.ListBucketMetricsConfigurationsHandler))).Queries("metrics", "").
+			Name("ListBucketMetricsConfigurations")
+		// GetBucketAccelerate
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketaccelerate", h.GetBucketAccelerateHandler))).Queries("accelerate", "").
 			Name("GetBucketAccelerate")
-		// GetBucketRequestPaymentHandler -- this is a dummy call.
+		// GetBucketRequestPayment
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketrequestpayment", h.GetBucketRequestPaymentHandler))).Queries("requestPayment", "").
 			Name("GetBucketRequestPayment")
@@ -359,7 +548,7 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketlifecycle", h.GetBucketLifecycleHandler))).Queries("lifecycle", "").
 			Name("GetBucketLifecycle")
-		// GetBucketReplicationHandler -- this is a dummy call.
+		// GetBucketReplicationHandler
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(metrics.APIStats("getbucketreplication", h.GetBucketReplicationHandler))).Queries("replication", "").
 			Name("GetBucketReplication")
@@ -371,6 +560,22 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebucketwebsite", h.DeleteBucketWebsiteHandler))).Queries("website", "").
 			Name("DeleteBucketWebsite")
+		// DeleteBucketInventoryConfiguration
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletebucketinventoryconfiguration", h.DeleteBucketInventoryConfigurationHandler))).Queries("inventory", "").
+			Name("DeleteBucketInventoryConfiguration")
+		// DeleteBucketAnalyticsConfiguration
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletebucketanalyticsconfiguration", h.DeleteBucketAnalyticsConfigurationHandler))).Queries("analytics", "").
+			Name("DeleteBucketAnalyticsConfiguration")
+		// DeleteBucketMetricsConfiguration
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletebucketmetricsconfiguration", h.DeleteBucketMetricsConfigurationHandler))).Queries("metrics", "").
+			Name("DeleteBucketMetricsConfiguration")
+		// DeleteBucketReplication
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletebucketreplication", h.DeleteBucketReplicationHandler))).Queries("replication", "").
+			Name("DeleteBucketReplication")
 		// DeleteBucketTaggingHandler
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebuckettagging", h.DeleteBucketTaggingHandler))).Queries("tagging", "").
@@ -411,6 +616,26 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("putbucketlifecycle", h.PutBucketLifecycleHandler))).Queries("lifecycle", "").
 			Name("PutBucketLifecycle")
+		// PutBucketWebsite
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketwebsite", h.PutBucketWebsiteHandler))).Queries("website", "").
+			Name("PutBucketWebsite")
+		// PutBucketInventoryConfiguration
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketinventoryconfiguration", h.PutBucketInventoryConfigurationHandler))).Queries("inventory", "").
+			Name("PutBucketInventoryConfiguration")
+		// PutBucketAnalyticsConfiguration
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketanalyticsconfiguration", h.PutBucketAnalyticsConfigurationHandler))).Queries("analytics", "").
+			Name("PutBucketAnalyticsConfiguration")
+		// PutBucketMetricsConfiguration
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketmetricsconfiguration", h.PutBucketMetricsConfigurationHandler))).Queries("metrics", "").
+			Name("PutBucketMetricsConfiguration")
+		// PutBucketReplication
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketreplication", h.PutBucketReplicationHandler))).Queries("replication", "").
+			Name("PutBucketReplication")
 		// PutBucketEncryption
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("putbucketencryption", h.PutBucketEncryptionHandler))).Queries("encryption", "").
@@ -420,6 +645,22 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("putbucketpolicy", h.PutBucketPolicyHandler))).Queries("policy", "").
 			Name("PutBucketPolicy")
+		// PutPublicAccessBlock
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putpublicaccessblock", h.PutPublicAccessBlockHandler))).Queries("publicAccessBlock", "").
+			Name("PutPublicAccessBlock")
+		// PutBucketOwnershipControls
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketownershipcontrols", h.PutBucketOwnershipControlsHandler))).Queries("ownershipControls", "").
+			Name("PutBucketOwnershipControls")
+		// PutBucketRequestPayment
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketrequestpayment", h.PutBucketRequestPaymentHandler))).Queries("requestPayment", "").
+			Name("PutBucketRequestPayment")
+		// PutBucketAccelerate
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			m.Handle(metrics.APIStats("putbucketaccelerate", h.PutBucketAccelerateHandler))).Queries("accelerate", "").
+			Name("PutBucketAccelerate")
 
 		// PutBucketObjectLockConfig
 		bucket.Methods(http.MethodPut).HandlerFunc(
@@ -441,6 +682,8 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			m.Handle(metrics.APIStats("createbucket", h.CreateBucketHandler))).
 			Name("CreateBucket")
+		// HEAD requests to bucket sub-resources, e.g. ?versioning
+		attachHeadSubresources(bucket)
 		// HeadBucket
 		bucket.Methods(http.MethodHead).HandlerFunc(
 			m.Handle(metrics.APIStats("headbucket", h.HeadBucketHandler))).
@@ -453,10 +696,22 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodPost).HandlerFunc(
 			m.Handle(metrics.APIStats("deletemultipleobjects", h.DeleteMultipleObjectsHandler))).Queries("delete", "").
 			Name("DeleteMultipleObjects")
+		// SearchObjects
+		bucket.Methods(http.MethodPost).HandlerFunc(
+			m.Handle(metrics.APIStats("searchobjects", h.SearchObjectsHandler))).Queries("search", "").
+			Name("SearchObjects")
 		// DeleteBucketPolicy
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebucketpolicy", h.DeleteBucketPolicyHandler))).Queries("policy", "").
 			Name("DeleteBucketPolicy")
+		// DeletePublicAccessBlock
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletepublicaccessblock", h.DeletePublicAccessBlockHandler))).Queries("publicAccessBlock", "").
+			Name("DeletePublicAccessBlock")
+		// DeleteBucketOwnershipControls
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			m.Handle(metrics.APIStats("deletebucketownershipcontrols", h.DeleteBucketOwnershipControlsHandler))).Queries("ownershipControls", "").
+			Name("DeleteBucketOwnershipControls")
 		// DeleteBucketLifecycle
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			m.Handle(metrics.APIStats("deletebucketlifecycle", h.DeleteBucketLifecycleHandler))).Queries("lifecycle", "").
@@ -472,6 +727,11 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 	}
 	// Root operation
 
+	// CreatePresignNonce
+	api.Methods(http.MethodPost).Path(SlashSeparator).HandlerFunc(
+		m.Handle(metrics.APIStats("createpresignnonce", h.CreatePresignNonceHandler))).Queries("presignNonce", "").
+		Name("CreatePresignNonce")
+
 	// ListBuckets
 	api.Methods(http.MethodGet).Path(SlashSeparator).HandlerFunc(
 		m.Handle(metrics.APIStats("listbuckets", h.ListBucketsHandler))).
@@ -487,3 +747,43 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 	api.NotFoundHandler = metrics.APIStats("notfound", errorResponseHandler)
 	api.MethodNotAllowedHandler = metrics.APIStats("methodnotallowed", errorResponseHandler)
 }
+
+// AttachWebsite adds the website endpoint of buckets configured for website hosting from h to r with m client limit.
+// The bucket is a subdomain of one of the domains or the whole host if it doesn't match the domains.
+// Requests to the website are anonymous, so they aren't authenticated. CORS configuration of the bucket
+// is applied to the website, so pages of other websites can load its content.
+func AttachWebsite(r *mux.Router, domains []string, m MaxClients, h Handler, log *zap.Logger) {
+	website := r.PathPrefix(SlashSeparator).Subrouter()
+
+	website.Use(
+		// -- prepare request
+		setRequestID,
+
+		// -- logging error requests
+		logErrorResponse(log),
+
+		// -- reject names of objects with NUL bytes and relative path segments
+		checkObjectName,
+	)
+
+	buckets := make([]*mux.Router, 0, len(domains)+1)
+	for _, domain := range domains {
+		buckets = append(buckets, website.Host("{bucket:.+}."+domain).Subrouter())
+	}
+	buckets = append(buckets, website.Host("{bucket:.+}").Subrouter())
+
+	for _, bucket := range buckets {
+		bucket.Use(
+			// -- append CORS headers to a response for
+			appendCORS(h),
+			// -- deny requests according to the bucket policy
+			checkBucketPolicy(h),
+		)
+		bucket.Methods(http.MethodOptions).HandlerFunc(m.Handle(metrics.APIStats("preflight", h.Preflight))).Name("Options")
+		bucket.Path("/{object:.*}").HandlerFunc(
+			m.Handle(metrics.APIStats("website", h.WebsiteHandler))).
+			Name("Website")
+	}
+
+	website.NotFoundHandler = metrics.APIStats("notfound", errorResponseHandler)
+}
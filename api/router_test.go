@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketRouters(t *testing.T) {
+	r := mux.NewRouter().SkipClean(true).UseEncodedPath()
+	api := r.PathPrefix(SlashSeparator).Subrouter()
+
+	route := func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		w.Header().Set("X-Route", mux.CurrentRoute(r).GetName()+" "+vars["bucket"]+" "+vars["object"])
+	}
+
+	for _, bucket := range bucketRouters(api, []string{"s3.test"}, nil, nil) {
+		bucket.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(route).Name("GetObject")
+		bucket.Methods(http.MethodGet).HandlerFunc(route).Queries("list-type", "2").Name("ListObjectsV2")
+		bucket.Methods(http.MethodGet).HandlerFunc(route).Name("ListObjectsV1")
+		bucket.Methods(http.MethodHead).HandlerFunc(route).Name("HeadBucket")
+	}
+	api.Methods(http.MethodGet).Path(SlashSeparator).HandlerFunc(route).Name("ListBuckets")
+
+	for _, tc := range []struct {
+		method, host, target string
+		expected             string
+	}{
+		{http.MethodGet, "s3.test", "/", "ListBuckets  "},
+		{http.MethodGet, "s3.test", "/bucket", "ListObjectsV1 bucket "},
+		{http.MethodGet, "s3.test", "/bucket/", "ListObjectsV1 bucket "},
+		{http.MethodGet, "s3.test", "/bucket?list-type=2&prefix=dir", "ListObjectsV2 bucket "},
+		{http.MethodGet, "s3.test", "/bucket/dir/key", "GetObject bucket dir/key"},
+		{http.MethodHead, "s3.test", "/bucket", "HeadBucket bucket "},
+		{http.MethodGet, "bucket.s3.test", "/", "ListObjectsV1 bucket "},
+		{http.MethodGet, "bucket.s3.test:8080", "/", "ListObjectsV1 bucket "},
+		{http.MethodGet, "bucket.s3.test", "/?list-type=2&prefix=dir", "ListObjectsV2 bucket "},
+		{http.MethodGet, "bucket.s3.test", "/key", "GetObject bucket key"},
+		{http.MethodGet, "bucket.s3.test", "/dir/key", "GetObject bucket dir/key"},
+		{http.MethodHead, "bucket.s3.test", "/", "HeadBucket bucket "},
+		// bucket names matching paths of the admin API are regular buckets
+		{http.MethodGet, "s3.test", "/api/v1/buckets/bucket/flags", "GetObject api v1/buckets/bucket/flags"},
+		{http.MethodGet, "api.s3.test", "/v1/buckets/bucket/flags", "GetObject api v1/buckets/bucket/flags"},
+		{http.MethodGet, "api.s3.test", "/", "ListObjectsV1 api "},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, "http://"+tc.host+tc.target, nil))
+		require.Equal(t, tc.expected, w.Header().Get("X-Route"), tc.host+tc.target)
+	}
+}
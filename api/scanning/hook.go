@@ -0,0 +1,89 @@
+package scanning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type (
+	// Hook scans payloads of objects for malware.
+	Hook interface {
+		Scan(ctx context.Context, obj *Object, payload io.Reader) (*Result, error)
+	}
+
+	// Object describes the scanned object.
+	Object struct {
+		Bucket    string
+		Key       string
+		VersionID string
+		Size      int64
+		ETag      string
+	}
+
+	// Result is a verdict of the hook.
+	Result struct {
+		Infected bool `json:"infected"`
+		// Signature is a name of the detected malware.
+		Signature string `json:"signature,omitempty"`
+	}
+
+	// HTTPHook sends payloads to the HTTP callback of the scanning service. The payload is a body of
+	// the POST request, object is described by X-Scan-* headers. The service responds with 200 status
+	// and Result JSON object.
+	HTTPHook struct {
+		url    string
+		client *http.Client
+	}
+)
+
+// Headers of requests of HTTPHook.
+const (
+	HeaderScanBucket    = "X-Scan-Bucket"
+	HeaderScanKey       = "X-Scan-Key"
+	HeaderScanVersionID = "X-Scan-Version-Id"
+	HeaderScanETag      = "X-Scan-Etag"
+)
+
+// NewHTTPHook creates a hook sending payloads to the url, timeout limits the whole request.
+func NewHTTPHook(url string, timeout time.Duration) *HTTPHook {
+	return &HTTPHook{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Scan implements Hook.
+func (h *HTTPHook) Scan(ctx context.Context, obj *Object, payload io.Reader) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, payload)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.ContentLength = obj.Size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Length", strconv.FormatInt(obj.Size, 10))
+	req.Header.Set(HeaderScanBucket, obj.Bucket)
+	req.Header.Set(HeaderScanKey, obj.Key)
+	req.Header.Set(HeaderScanVersionID, obj.VersionID)
+	req.Header.Set(HeaderScanETag, obj.ETag)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	res := new(Result)
+	if err = json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return res, nil
+}
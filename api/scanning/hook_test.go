@@ -0,0 +1,52 @@
+package scanning
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(HeaderScanKey) == "broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		payload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "bucket", r.Header.Get(HeaderScanBucket))
+		require.Equal(t, "version", r.Header.Get(HeaderScanVersionID))
+		require.EqualValues(t, len(payload), r.ContentLength)
+
+		if strings.Contains(string(payload), "EICAR") {
+			_, _ = w.Write([]byte(`{"infected": true, "signature": "EICAR-Test-File"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"infected": false}`))
+	}))
+	defer srv.Close()
+
+	hook := NewHTTPHook(srv.URL, time.Second)
+	scan := func(key, payload string) (*Result, error) {
+		obj := &Object{Bucket: "bucket", Key: key, VersionID: "version", Size: int64(len(payload))}
+		return hook.Scan(context.Background(), obj, strings.NewReader(payload))
+	}
+
+	res, err := scan("clean", "content")
+	require.NoError(t, err)
+	require.False(t, res.Infected)
+
+	res, err = scan("infected", "EICAR content")
+	require.NoError(t, err)
+	require.Equal(t, &Result{Infected: true, Signature: "EICAR-Test-File"}, res)
+
+	_, err = scan("broken", "content")
+	require.Error(t, err)
+}
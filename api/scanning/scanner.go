@@ -0,0 +1,231 @@
+package scanning
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"go.uber.org/zap"
+)
+
+type (
+	// Config is a configuration of the scanner.
+	Config struct {
+		// Workers is a number of objects scanned concurrently.
+		Workers int
+		// QueueSize is a max number of objects waiting for scanning, new objects are skipped if the queue is full.
+		QueueSize int
+		// Timeout limits scanning of a single object.
+		Timeout time.Duration
+		// MaxSize is a max size of scanned objects, larger objects are skipped. Zero means no limit.
+		MaxSize int64
+		// QuarantineTagKey and QuarantineTagValue are a tag set to infected objects.
+		QuarantineTagKey   string
+		QuarantineTagValue string
+	}
+
+	// ObjectLayer is a part of the layer used by the scanner to read objects and tag infected ones.
+	ObjectLayer interface {
+		layer.ObjectService
+		layer.TaggingService
+	}
+
+	// Scanner scans payloads of new objects in background and quarantines infected ones:
+	// they get the quarantine tag and can't be read.
+	Scanner struct {
+		log   *zap.Logger
+		obj   ObjectLayer
+		hook  Hook
+		cfg   Config
+		tasks chan *task
+		wg    sync.WaitGroup
+
+		mu      sync.RWMutex
+		stopped bool
+	}
+
+	task struct {
+		bktInfo *data.BucketInfo
+		info    data.NotificationInfo
+		// box are credentials of the request that created the object.
+		box *accessbox.Box
+	}
+)
+
+// Default values of Config.
+const (
+	DefaultWorkers            = 4
+	DefaultQueueSize          = 1000
+	DefaultTimeout            = time.Minute
+	DefaultQuarantineTagKey   = "quarantine"
+	DefaultQuarantineTagValue = "infected"
+)
+
+// NewScanner creates a scanner, zero values of the config are replaced by the defaults.
+// Objects are scanned after Start.
+func NewScanner(log *zap.Logger, obj ObjectLayer, hook Hook, cfg Config) *Scanner {
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.QuarantineTagKey == "" {
+		cfg.QuarantineTagKey = DefaultQuarantineTagKey
+	}
+	if cfg.QuarantineTagValue == "" {
+		cfg.QuarantineTagValue = DefaultQuarantineTagValue
+	}
+
+	return &Scanner{
+		log:   log,
+		obj:   obj,
+		hook:  hook,
+		cfg:   cfg,
+		tasks: make(chan *task, cfg.QueueSize),
+	}
+}
+
+// Start runs workers scanning queued objects until Stop, the context limits scanning.
+func (s *Scanner) Start(ctx context.Context) {
+	s.wg.Add(s.cfg.Workers)
+	for i := 0; i < s.cfg.Workers; i++ {
+		go func() {
+			defer s.wg.Done()
+			for t := range s.tasks {
+				if ctx.Err() != nil {
+					// drain the queue without scanning after the shutdown
+					continue
+				}
+				s.scan(ctx, t)
+			}
+		}()
+	}
+}
+
+// Stop makes the scanner skip new objects and waits for queued objects to be processed.
+func (s *Scanner) Stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.tasks)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Enqueue schedules scanning of the created object with credentials of the request from the context.
+func (s *Scanner) Enqueue(ctx context.Context, bktInfo *data.BucketInfo, info *data.NotificationInfo) {
+	if s.cfg.MaxSize > 0 && info.Size > s.cfg.MaxSize {
+		s.log.Debug("object is too large to be scanned", zap.String("bucket", bktInfo.Name),
+			zap.String("object", info.Name), zap.Int64("size", info.Size))
+		return
+	}
+
+	t := &task{bktInfo: bktInfo, info: *info}
+	t.box, _ = layer.GetBoxData(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return
+	}
+
+	select {
+	case s.tasks <- t:
+	default:
+		s.log.Warn("scanning queue is full, object isn't scanned", zap.String("bucket", bktInfo.Name),
+			zap.String("object", info.Name), zap.String("version", info.Version))
+	}
+}
+
+func (s *Scanner) scan(ctx context.Context, t *task) {
+	log := s.log.With(zap.String("bucket", t.bktInfo.Name), zap.String("object", t.info.Name),
+		zap.String("version", t.info.Version))
+
+	if t.box != nil {
+		ctx = context.WithValue(ctx, api.BoxData, t.box)
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	res, err := s.scanPayload(ctx, t)
+	if err != nil {
+		log.Error("couldn't scan object", zap.Error(err))
+		return
+	}
+	if !res.Infected {
+		log.Debug("object is clean")
+		return
+	}
+
+	if err = s.quarantine(ctx, t); err != nil {
+		log.Error("couldn't quarantine infected object", zap.String("signature", res.Signature), zap.Error(err))
+		return
+	}
+	log.Warn("infected object is quarantined", zap.String("signature", res.Signature))
+}
+
+func (s *Scanner) scanPayload(ctx context.Context, t *task) (*Result, error) {
+	objInfo, err := s.obj.GetObjectInfo(ctx, &layer.HeadObjectParams{
+		BktInfo:   t.bktInfo,
+		Object:    t.info.Name,
+		VersionID: t.info.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get object info: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(s.obj.GetObject(ctx, &layer.GetObjectParams{
+			ObjectInfo: objInfo,
+			BucketInfo: t.bktInfo,
+			Writer:     pw,
+		}))
+	}()
+	// the payload writer is unblocked if the hook doesn't read the whole payload
+	defer pr.Close()
+
+	return s.hook.Scan(ctx, &Object{
+		Bucket:    t.bktInfo.Name,
+		Key:       objInfo.Name,
+		VersionID: objInfo.VersionID(),
+		Size:      objInfo.Size,
+		ETag:      objInfo.HashSum,
+	}, pr)
+}
+
+func (s *Scanner) quarantine(ctx context.Context, t *task) error {
+	objVersion := &layer.ObjectVersion{
+		BktInfo:    t.bktInfo,
+		ObjectName: t.info.Name,
+		VersionID:  t.info.Version,
+	}
+
+	_, tags, err := s.obj.GetObjectTagging(ctx, &layer.GetObjectTaggingParams{ObjectVersion: objVersion})
+	if err != nil {
+		return fmt.Errorf("get object tagging: %w", err)
+	}
+
+	newTags := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		newTags[k] = v
+	}
+	newTags[s.cfg.QuarantineTagKey] = s.cfg.QuarantineTagValue
+
+	if _, err = s.obj.PutObjectTagging(ctx, &layer.PutObjectTaggingParams{ObjectVersion: objVersion, TagSet: newTags}); err != nil {
+		return fmt.Errorf("put object tagging: %w", err)
+	}
+	return nil
+}
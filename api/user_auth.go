@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
@@ -19,8 +20,12 @@ var BoxData = KeyWrapper("__context_box_key")
 // ClientTime is an ID used to store client time.Time in a context.
 var ClientTime = KeyWrapper("__context_client_time")
 
+// AccessKeyID is an ID used to store access key id of the request credentials in a context.
+var AccessKeyID = KeyWrapper("__context_access_key_id")
+
 // AttachUserAuth adds user authentication via center to router using log for logging.
-func AttachUserAuth(router *mux.Router, center auth.Center, log *zap.Logger) {
+// Uses of access keys are recorded to usage, nil usage disables it.
+func AttachUserAuth(router *mux.Router, center auth.Center, usage *auth.KeyUsage, log *zap.Logger) {
 	router.Use(func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			var ctx context.Context
@@ -39,9 +44,13 @@ func AttachUserAuth(router *mux.Router, center auth.Center, log *zap.Logger) {
 				}
 			} else {
 				ctx = context.WithValue(r.Context(), BoxData, box.AccessBox)
+				ctx = context.WithValue(ctx, AccessKeyID, box.AccessKeyID)
 				if !box.ClientTime.IsZero() {
 					ctx = context.WithValue(ctx, ClientTime, box.ClientTime)
 				}
+				if usage != nil {
+					usage.UseBox(box, GetSourceIP(r), time.Now())
+				}
 			}
 
 			h.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// WireLogConfig selects requests captured by the wire log.
+type WireLogConfig struct {
+	// SampleRate is a fraction of captured requests from 0 to 1.
+	SampleRate float64
+	// AccessKeys are access key IDs whose authenticated requests are always captured.
+	AccessKeys []string
+	// MaxBodySize is a number of captured bytes of request and response bodies, zero disables capture of bodies.
+	MaxBodySize int64
+}
+
+// WireLog captures full headers and optionally bodies of sampled requests and their responses
+// to debug incompatibilities of S3 clients. Secrets in headers and query parameters are redacted,
+// bodies of requests with secrets are never captured.
+type WireLog struct {
+	log *zap.Logger
+
+	mu          sync.RWMutex
+	sampleRate  float64
+	accessKeys  map[string]struct{}
+	maxBodySize int64
+}
+
+type wireResponseWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+	body       limitedBuffer
+}
+
+// limitedBuffer keeps first max bytes written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int64
+	truncated bool
+}
+
+// wireRedacted replaces values of secrets in the wire log.
+const wireRedacted = "REDACTED"
+
+// wireCaptured is an ID used to mark requests captured by the sample rate in a context.
+var wireCaptured = KeyWrapper("__context_wire_captured")
+
+// wireSecretHeaders are headers whose values aren't written to the wire log.
+var wireSecretHeaders = []string{
+	AmzServerSideEncryptionCustomerKey,
+	AmzCopySourceServerSideEncryptionCustomerKey,
+	"X-Amz-Security-Token",
+}
+
+// wireSecretQueryParams are query parameters whose values aren't written to the wire log:
+// signatures and bearer credentials of presigned URLs and download links.
+var wireSecretQueryParams = []string{
+	"X-Amz-Signature",
+	"X-Amz-Security-Token",
+	// single-use nonce of presigned URLs, see auth.NonceQuery
+	"X-Neofs-Nonce",
+	// download token of GetObject, see handler.CreateDownloadTokenHandler
+	"downloadToken",
+}
+
+// wireSecretBodyRoutes are routes whose request or response bodies contain secrets:
+// tokens, nonces and signed policies, their bodies aren't written to the wire log.
+var wireSecretBodyRoutes = map[string]struct{}{
+	"CreateDownloadToken": {},
+	"CreatePresignNonce":  {},
+	"PostObject":          {},
+}
+
+// NewWireLog creates the wire log writing captured requests to log, see WireLogConfig.
+func NewWireLog(log *zap.Logger, cfg WireLogConfig) (*WireLog, error) {
+	w := &WireLog{log: log}
+	return w, w.Update(cfg)
+}
+
+// Update replaces the selection of captured requests.
+func (w *WireLog) Update(cfg WireLogConfig) error {
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return fmt.Errorf("sample rate %v is out of range [0, 1]", cfg.SampleRate)
+	}
+	if cfg.MaxBodySize < 0 {
+		return fmt.Errorf("negative max body size %d", cfg.MaxBodySize)
+	}
+
+	accessKeys := make(map[string]struct{}, len(cfg.AccessKeys))
+	for _, key := range cfg.AccessKeys {
+		accessKeys[key] = struct{}{}
+	}
+
+	w.mu.Lock()
+	w.sampleRate = cfg.SampleRate
+	w.accessKeys = accessKeys
+	w.maxBodySize = cfg.MaxBodySize
+	w.mu.Unlock()
+
+	return nil
+}
+
+// sampled returns the max captured body size and true if the request is captured by the sample rate.
+func (w *WireLog) sampled() (int64, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.maxBodySize, w.sampleRate > 0 && rand.Float64() < w.sampleRate
+}
+
+// selected returns the max captured body size and true if requests with the access key id are captured.
+func (w *WireLog) selected(accessKeyID string) (int64, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	_, ok := w.accessKeys[accessKeyID]
+	return w.maxBodySize, ok && accessKeyID != ""
+}
+
+// Middleware writes requests sampled by the rate and their responses to the wire log.
+func (w *WireLog) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		maxBodySize, ok := w.sampled()
+		if !ok {
+			h.ServeHTTP(rw, r)
+			return
+		}
+
+		w.capture(h, rw, r.WithContext(context.WithValue(r.Context(), wireCaptured, true)), maxBodySize)
+	})
+}
+
+// KeysMiddleware writes requests of the selected access keys and their responses to the wire log.
+// Access keys are taken from the request context, so the middleware must follow the authentication.
+func (w *WireLog) KeysMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if captured, _ := r.Context().Value(wireCaptured).(bool); captured {
+			h.ServeHTTP(rw, r)
+			return
+		}
+
+		accessKeyID, _ := r.Context().Value(AccessKeyID).(string)
+		maxBodySize, ok := w.selected(accessKeyID)
+		if !ok {
+			h.ServeHTTP(rw, r)
+			return
+		}
+
+		w.capture(h, rw, r, maxBodySize)
+	})
+}
+
+// capture serves the request and writes it with the response to the wire log.
+func (w *WireLog) capture(h http.Handler, rw http.ResponseWriter, r *http.Request, maxBodySize int64) {
+	if route := mux.CurrentRoute(r); route != nil {
+		if _, ok := wireSecretBodyRoutes[route.GetName()]; ok {
+			maxBodySize = 0
+		}
+	}
+
+	reqBody := &limitedBuffer{max: maxBodySize}
+	if maxBodySize > 0 && r.Body != nil {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(r.Body, reqBody), r.Body}
+	}
+	// headers are copied before the handler since handlers can change them
+	reqHeaders := redactHeaders(r.Header)
+	wrw := &wireResponseWriter{ResponseWriter: rw, body: limitedBuffer{max: maxBodySize}}
+
+	start := time.Now()
+	h.ServeHTTP(wrw, r)
+
+	if wrw.statusCode == 0 {
+		wrw.statusCode = http.StatusOK
+	}
+
+	fields := []zap.Field{
+		zap.String("request_id", GetRequestID(r.Context())),
+		zap.String("remote_addr", r.RemoteAddr),
+		zap.String("method", r.Method),
+		zap.String("host", r.Host),
+		zap.String("uri", redactURI(r.URL.RequestURI())),
+		zap.Any("request_headers", reqHeaders),
+		zap.Int("status", wrw.statusCode),
+		zap.Any("response_headers", redactHeaders(rw.Header())),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if maxBodySize > 0 {
+		fields = append(fields,
+			zap.ByteString("request_body", reqBody.Bytes()),
+			zap.Bool("request_body_truncated", reqBody.truncated),
+			zap.ByteString("response_body", wrw.body.Bytes()),
+			zap.Bool("response_body_truncated", wrw.body.truncated),
+		)
+	}
+
+	w.log.Info("wire", fields...)
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if rest := b.max - int64(b.Len()); rest < int64(len(p)) {
+		b.truncated = true
+		if rest > 0 {
+			b.Buffer.Write(p[:rest])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+func (w *wireResponseWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *wireResponseWriter) Write(p []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	if w.body.max > 0 {
+		_, _ = w.body.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *wireResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// redactHeaders returns the copy of headers with signatures and secret values replaced.
+func redactHeaders(h http.Header) http.Header {
+	res := h.Clone()
+	for _, name := range wireSecretHeaders {
+		if len(res.Values(name)) != 0 {
+			res.Set(name, wireRedacted)
+		}
+	}
+	if auth := res.Get(Authorization); len(auth) != 0 {
+		if i := strings.Index(auth, "Signature="); i >= 0 {
+			res.Set(Authorization, auth[:i+len("Signature=")]+wireRedacted)
+		}
+	}
+	return res
+}
+
+// redactURI returns the request URI with values of wireSecretQueryParams replaced.
+func redactURI(uri string) string {
+	i := strings.IndexByte(uri, '?')
+	if i < 0 {
+		return uri
+	}
+
+	params := strings.Split(uri[i+1:], "&")
+	for j, param := range params {
+		for _, name := range wireSecretQueryParams {
+			if strings.HasPrefix(param, name+"=") {
+				params[j] = name + "=" + wireRedacted
+			}
+		}
+	}
+	return uri[:i+1] + strings.Join(params, "&")
+}
+
+// logWire returns middleware of the wire log, nil log disables it.
+func logWire(w *WireLog) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if w == nil {
+			return h
+		}
+		return w.Middleware(h)
+	}
+}
+
+// logWireKeys returns middleware of the wire log for selected access keys, nil log disables it.
+func logWireKeys(w *WireLog) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if w == nil {
+			return h
+		}
+		return w.KeysMiddleware(h)
+	}
+}
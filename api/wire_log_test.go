@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWireLog(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	wireLog, err := NewWireLog(zap.New(core), WireLogConfig{AccessKeys: []string{"key"}, MaxBodySize: 4})
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("X-Response", "value")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("response"))
+	})
+	// authenticate puts the access key id of the test header to the context like the user authentication
+	authenticate := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if accessKeyID := r.Header.Get("X-Test-Access-Key"); accessKeyID != "" {
+				r = r.WithContext(context.WithValue(r.Context(), AccessKeyID, accessKeyID))
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+
+	router := mux.NewRouter()
+	router.Use(logWire(wireLog), authenticate, logWireKeys(wireLog))
+	router.Methods(http.MethodPut).Path("/bucket/object").Handler(handler).Name("PutObject")
+	router.Methods(http.MethodPost).Path("/").Handler(handler).Name("CreatePresignNonce")
+
+	serve := func(method, target, accessKeyID, authorization string) {
+		r := httptest.NewRequest(method, target, strings.NewReader("request"))
+		r.Header.Set(Authorization, authorization)
+		r.Header.Set(AmzServerSideEncryptionCustomerKey, "secret")
+		r.Header.Set("X-Test-Access-Key", accessKeyID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, "response", w.Body.String())
+	}
+
+	const objectTarget = "/bucket/object?X-Amz-Signature=secret&x=y&X-Amz-Security-Token=token"
+
+	serve(http.MethodPut, objectTarget, "other", "AWS4-HMAC-SHA256 Credential=other/20220101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=secret")
+	require.Zero(t, logs.Len())
+
+	// access key from the unauthenticated credential doesn't select the request
+	serve(http.MethodPut, objectTarget, "", "AWS4-HMAC-SHA256 Credential=key/20220101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=secret")
+	require.Zero(t, logs.Len())
+
+	serve(http.MethodPut, objectTarget, "key", "AWS4-HMAC-SHA256 Credential=key/20220101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=secret")
+	require.Equal(t, 1, logs.Len())
+
+	fields := logs.TakeAll()[0].ContextMap()
+	require.Equal(t, "/bucket/object?X-Amz-Signature=REDACTED&x=y&X-Amz-Security-Token=REDACTED", fields["uri"])
+	require.EqualValues(t, http.StatusCreated, fields["status"])
+	require.Equal(t, "requ", fields["request_body"])
+	require.Equal(t, true, fields["request_body_truncated"])
+	require.Equal(t, "resp", fields["response_body"])
+
+	reqHeaders := fields["request_headers"].(http.Header)
+	require.Equal(t, "REDACTED", reqHeaders.Get(AmzServerSideEncryptionCustomerKey))
+	require.True(t, strings.HasSuffix(reqHeaders.Get(Authorization), "Signature=REDACTED"))
+	require.Equal(t, "value", fields["response_headers"].(http.Header).Get("X-Response"))
+
+	// bodies with secrets aren't captured
+	serve(http.MethodPost, "/?presignNonce", "key", "")
+	require.Equal(t, 1, logs.Len())
+	require.NotContains(t, logs.TakeAll()[0].ContextMap(), "response_body")
+
+	require.NoError(t, wireLog.Update(WireLogConfig{SampleRate: 1}))
+	serve(http.MethodPut, objectTarget, "", "")
+	require.Equal(t, 1, logs.Len())
+	require.NotContains(t, logs.TakeAll()[0].ContextMap(), "request_body")
+
+	// sampled request of the selected access key is captured once
+	require.NoError(t, wireLog.Update(WireLogConfig{SampleRate: 1, AccessKeys: []string{"key"}}))
+	serve(http.MethodPut, objectTarget, "key", "")
+	require.Equal(t, 1, logs.Len())
+	logs.TakeAll()
+
+	require.Error(t, wireLog.Update(WireLogConfig{SampleRate: 2}))
+	require.Error(t, wireLog.Update(WireLogConfig{MaxBodySize: -1}))
+}
+
+func TestRedactURI(t *testing.T) {
+	for _, tc := range []struct {
+		name, uri, expected string
+	}{
+		{
+			name:     "no query",
+			uri:      "/bucket/object",
+			expected: "/bucket/object",
+		},
+		{
+			name:     "signature and session token",
+			uri:      "/bucket/object?X-Amz-Signature=secret&x=y&X-Amz-Security-Token=token",
+			expected: "/bucket/object?X-Amz-Signature=REDACTED&x=y&X-Amz-Security-Token=REDACTED",
+		},
+		{
+			name:     "presign nonce",
+			uri:      "/bucket/object?X-Amz-Credential=key&X-Neofs-Nonce=nonce&X-Amz-Signature=secret",
+			expected: "/bucket/object?X-Amz-Credential=key&X-Neofs-Nonce=REDACTED&X-Amz-Signature=REDACTED",
+		},
+		{
+			name:     "download token",
+			uri:      "/bucket/object?downloadToken=token&versionId=v",
+			expected: "/bucket/object?downloadToken=REDACTED&versionId=v",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, redactURI(tc.uri))
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// S3Namespace is a namespace of AWS S3 XML documents.
+const S3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// NewXMLDecoder creates xml.Decoder for S3 request documents.
+// SDKs serialize documents with or without namespace, so elements without
+// namespace are decoded as elements of S3Namespace.
+func NewXMLDecoder(r io.Reader) *xml.Decoder {
+	dec := xml.NewDecoder(r)
+	dec.DefaultSpace = S3Namespace
+	return dec
+}
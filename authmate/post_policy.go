@@ -0,0 +1,103 @@
+package authmate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+)
+
+// PostPolicyParams groups parameters of the presigned POST form.
+type PostPolicyParams struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+
+	Bucket string
+	// Key is a key of the uploaded object. If it ends with ${filename}, any key with the preceding
+	// prefix is allowed and the gateway replaces ${filename} with the name of the uploaded file.
+	Key string
+	// Lifetime is a period the form can be used in.
+	Lifetime time.Duration
+	// MaxSize limits the size of the uploaded file, zero means no limit.
+	MaxSize int64
+	// Fields are additional form fields with exact values, e.g. content-type or x-amz-meta-*.
+	Fields map[string]string
+
+	// Time is a signing time, the current time is used if it's zero.
+	Time time.Time
+}
+
+// postFilenameVariable is replaced with the name of the uploaded file in the key field.
+const postFilenameVariable = "${filename}"
+
+// PresignPostPolicy returns fields of the form to upload an object to the bucket via POST request
+// without credentials. The form fields must be sent before the file field.
+func PresignPostPolicy(prm PostPolicyParams) (map[string]string, error) {
+	if prm.Bucket == "" || prm.Key == "" {
+		return nil, errors.New("bucket and key must be specified")
+	}
+	if prm.AccessKeyID == "" || prm.SecretAccessKey == "" {
+		return nil, errors.New("credentials must be specified")
+	}
+	if prm.Lifetime <= 0 {
+		return nil, fmt.Errorf("invalid lifetime %s", prm.Lifetime)
+	}
+	if prm.MaxSize < 0 {
+		return nil, fmt.Errorf("negative max size %d", prm.MaxSize)
+	}
+
+	now := prm.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+
+	fields := make(map[string]string, len(prm.Fields)+6)
+	for key, value := range prm.Fields {
+		fields[strings.ToLower(key)] = value
+	}
+	fields["key"] = prm.Key
+	fields["x-amz-algorithm"] = "AWS4-HMAC-SHA256"
+	fields["x-amz-credential"] = fmt.Sprintf("%s/%s/%s/s3/aws4_request", prm.AccessKeyID, now.Format("20060102"), prm.Region)
+	fields["x-amz-date"] = now.Format("20060102T150405Z")
+
+	conditions := []interface{}{map[string]string{"bucket": prm.Bucket}}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "key" && strings.HasSuffix(prm.Key, postFilenameVariable) {
+			conditions = append(conditions, []string{"starts-with", "$key", strings.TrimSuffix(prm.Key, postFilenameVariable)})
+			continue
+		}
+		conditions = append(conditions, map[string]string{name: fields[name]})
+	}
+	if prm.MaxSize > 0 {
+		conditions = append(conditions, []interface{}{"content-length-range", 0, prm.MaxSize})
+	}
+
+	policy, err := json.Marshal(struct {
+		Expiration string        `json:"expiration"`
+		Conditions []interface{} `json:"conditions"`
+	}{
+		Expiration: now.Add(prm.Lifetime).Format(time.RFC3339),
+		Conditions: conditions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal policy: %w", err)
+	}
+
+	fields["policy"] = base64.StdEncoding.EncodeToString(policy)
+	fields["x-amz-signature"] = auth.SignPostPolicy(prm.SecretAccessKey, prm.Region, now, fields["policy"])
+
+	return fields, nil
+}
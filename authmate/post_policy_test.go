@@ -0,0 +1,58 @@
+package authmate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresignPostPolicy(t *testing.T) {
+	signTime := time.Date(2022, 6, 15, 8, 42, 3, 0, time.UTC)
+	prm := PostPolicyParams{
+		AccessKeyID:     "accessKey",
+		SecretAccessKey: "secret",
+		Region:          "us-east-1",
+		Bucket:          "bucket",
+		Key:             "uploads/${filename}",
+		Lifetime:        time.Hour,
+		MaxSize:         1024,
+		Fields:          map[string]string{"Content-Type": "image/png"},
+		Time:            signTime,
+	}
+
+	fields, err := PresignPostPolicy(prm)
+	require.NoError(t, err)
+
+	require.Equal(t, "uploads/${filename}", fields["key"])
+	require.Equal(t, "image/png", fields["content-type"])
+	require.Equal(t, "AWS4-HMAC-SHA256", fields["x-amz-algorithm"])
+	require.Equal(t, "accessKey/20220615/us-east-1/s3/aws4_request", fields["x-amz-credential"])
+	require.Equal(t, "20220615T084203Z", fields["x-amz-date"])
+	require.Equal(t, auth.SignPostPolicy("secret", "us-east-1", signTime, fields["policy"]), fields["x-amz-signature"])
+
+	rawPolicy, err := base64.StdEncoding.DecodeString(fields["policy"])
+	require.NoError(t, err)
+	var policy struct {
+		Expiration time.Time     `json:"expiration"`
+		Conditions []interface{} `json:"conditions"`
+	}
+	require.NoError(t, json.Unmarshal(rawPolicy, &policy))
+	require.True(t, signTime.Add(time.Hour).Equal(policy.Expiration))
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"bucket": "bucket"},
+		map[string]interface{}{"content-type": "image/png"},
+		[]interface{}{"starts-with", "$key", "uploads/"},
+		map[string]interface{}{"x-amz-algorithm": "AWS4-HMAC-SHA256"},
+		map[string]interface{}{"x-amz-credential": "accessKey/20220615/us-east-1/s3/aws4_request"},
+		map[string]interface{}{"x-amz-date": "20220615T084203Z"},
+		[]interface{}{"content-length-range", float64(0), float64(1024)},
+	}, policy.Conditions)
+
+	prm.Lifetime = 0
+	_, err = PresignPostPolicy(prm)
+	require.Error(t, err)
+}
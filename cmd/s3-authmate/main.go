@@ -77,6 +77,8 @@ var (
 	containerPolicies        string
 	awcCliCredFile           string
 	timeoutFlag              time.Duration
+	maxSizeFlag              int64
+	formFieldsFlag           cli.StringSlice
 
 	// pool timeouts flag.
 	poolDialTimeoutFlag        time.Duration
@@ -176,6 +178,7 @@ func appCommands() []*cli.Command {
 		issueSecret(),
 		obtainSecret(),
 		generatePresignedURL(),
+		generatePresignedPost(),
 	}
 }
 
@@ -450,24 +453,9 @@ It will be ceil rounded to the nearest amount of epoch.`,
 			},
 		},
 		Action: func(c *cli.Context) error {
-			var cfg aws.Config
-			if regionFlag != "" {
-				cfg.Region = &regionFlag
-			}
-			if accessKeyIDFlag != "" && secretAccessKeyFlag != "" {
-				cfg.Credentials = credentials.NewStaticCredentialsFromCreds(credentials.Value{
-					AccessKeyID:     accessKeyIDFlag,
-					SecretAccessKey: secretAccessKeyFlag,
-				})
-			}
-
-			sess, err := session.NewSessionWithOptions(session.Options{
-				Config:            cfg,
-				Profile:           profileFlag,
-				SharedConfigState: session.SharedConfigEnable,
-			})
+			sess, err := awsSession()
 			if err != nil {
-				return fmt.Errorf("couldn't get credentials: %w", err)
+				return err
 			}
 
 			signer := v4.NewSigner(sess.Config.Credentials)
@@ -495,6 +483,151 @@ It will be ceil rounded to the nearest amount of epoch.`,
 	}
 }
 
+func generatePresignedPost() *cli.Command {
+	return &cli.Command{
+		Name: "generate-presigned-post",
+		Description: `Generate fields of the HTML form to upload an object via POST request using AWS credentials.
+Credentials are loaded the same way as in generate-presigned-url command.
+Object name ending with ${filename} allows any name with the preceding prefix, the gateway replaces
+${filename} with the name of the uploaded file.`,
+		Usage: "generate-presigned-post --endpoint http://s3.neofs.devenv:8080 --bucket bucket-name --object 'uploads/${filename}' --max-size 1048576 --profile aws-profile",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:        "lifetime",
+				Usage:       `Lifetime of the form. For example 50h30m (note: max time unit is an hour so to set a day you should use 24h).`,
+				Required:    false,
+				Destination: &lifetimeFlag,
+				Value:       defaultPresignedLifetime,
+			},
+			&cli.StringFlag{
+				Name:        "endpoint",
+				Usage:       `Endpoint of s3-gw`,
+				Required:    true,
+				Destination: &endpointFlag,
+			},
+			&cli.StringFlag{
+				Name:        "bucket",
+				Usage:       `Bucket name to upload the object to`,
+				Required:    true,
+				Destination: &bucketFlag,
+			},
+			&cli.StringFlag{
+				Name:        "object",
+				Usage:       `Object name to upload, may end with ${filename}`,
+				Required:    true,
+				Destination: &objectFlag,
+			},
+			&cli.Int64Flag{
+				Name:        "max-size",
+				Usage:       `Max size of the uploaded file in bytes, 0 means no limit`,
+				Required:    false,
+				Destination: &maxSizeFlag,
+			},
+			&cli.StringSliceFlag{
+				Name:        "field",
+				Usage:       `Additional form field with the exact value in 'name=value' format, e.g. 'Content-Type=image/png'`,
+				Required:    false,
+				Destination: &formFieldsFlag,
+			},
+			&cli.StringFlag{
+				Name:        "profile",
+				Usage:       `AWS profile to load`,
+				Required:    false,
+				Destination: &profileFlag,
+			},
+			&cli.StringFlag{
+				Name:        "region",
+				Usage:       `AWS region to use in signature (default is taken from ~/.aws/config)`,
+				Required:    false,
+				Destination: &regionFlag,
+			},
+			&cli.StringFlag{
+				Name:        "aws-access-key-id",
+				Usage:       `AWS access key id to sign the policy (default is taken from ~/.aws/credentials)`,
+				Required:    false,
+				Destination: &accessKeyIDFlag,
+			},
+			&cli.StringFlag{
+				Name:        "aws-secret-access-key",
+				Usage:       `AWS access secret access key to sign the policy (default is taken from ~/.aws/credentials)`,
+				Required:    false,
+				Destination: &secretAccessKeyFlag,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			sess, err := awsSession()
+			if err != nil {
+				return err
+			}
+
+			creds, err := sess.Config.Credentials.Get()
+			if err != nil {
+				return fmt.Errorf("couldn't get credentials: %w", err)
+			}
+
+			extraFields := make(map[string]string, len(formFieldsFlag.Value()))
+			for _, field := range formFieldsFlag.Value() {
+				i := strings.IndexByte(field, '=')
+				if i <= 0 {
+					return fmt.Errorf("invalid form field '%s', expected 'name=value'", field)
+				}
+				extraFields[field[:i]] = field[i+1:]
+			}
+
+			fields, err := authmate.PresignPostPolicy(authmate.PostPolicyParams{
+				AccessKeyID:     creds.AccessKeyID,
+				SecretAccessKey: creds.SecretAccessKey,
+				Region:          *sess.Config.Region,
+				Bucket:          bucketFlag,
+				Key:             objectFlag,
+				Lifetime:        lifetimeFlag,
+				MaxSize:         maxSizeFlag,
+				Fields:          extraFields,
+			})
+			if err != nil {
+				return fmt.Errorf("presign post policy: %w", err)
+			}
+
+			res := &struct {
+				URL    string
+				Fields map[string]string
+			}{
+				URL:    fmt.Sprintf("%s/%s", endpointFlag, bucketFlag),
+				Fields: fields,
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			enc.SetEscapeHTML(false)
+			return enc.Encode(res)
+		},
+	}
+}
+
+// awsSession loads AWS credentials and region from the profile, explicit flags override them.
+func awsSession() (*session.Session, error) {
+	var cfg aws.Config
+	if regionFlag != "" {
+		cfg.Region = &regionFlag
+	}
+	if accessKeyIDFlag != "" && secretAccessKeyFlag != "" {
+		cfg.Credentials = credentials.NewStaticCredentialsFromCreds(credentials.Value{
+			AccessKeyID:     accessKeyIDFlag,
+			SecretAccessKey: secretAccessKeyFlag,
+		})
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            cfg,
+		Profile:           profileFlag,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get credentials: %w", err)
+	}
+	return sess, nil
+}
+
 func parsePolicies(val string) (authmate.ContainerPolicies, error) {
 	if val == "" {
 		return nil, nil
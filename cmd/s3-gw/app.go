@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -18,15 +19,25 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
 	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
 	"github.com/nspcc-dev/neofs-s3-gw/api/notifications"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/api/scanning"
+	"github.com/nspcc-dev/neofs-s3-gw/control"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/kms"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/objectindex"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/wallet"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/netmap"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
@@ -43,6 +54,13 @@ type (
 		obj  layer.Client
 		api  api.Handler
 
+		objectIndex *objectindex.BoltIndex
+		peers       []peerInfo
+		// boxes resolves credentials of background jobs.
+		boxes tokens.Credentials
+		// keyUsage records uses of access keys by S3 and admin requests.
+		keyUsage *auth.KeyUsage
+
 		servers []Server
 
 		metrics        *appMetrics
@@ -50,6 +68,14 @@ type (
 		services       []*Service
 		settings       *appSettings
 		maxClients     api.MaxClients
+		// storage is nil if degraded mode is disabled.
+		storage *api.StorageState
+		// control is managed by the control service.
+		control *api.ControlState
+		// scanner is nil if scanning of new objects is disabled.
+		scanner *scanning.Scanner
+		// neoFS is the storage network the object layer works with.
+		neoFS *neofs.NeoFS
 
 		webDone chan struct{}
 		wrkDone chan struct{}
@@ -58,6 +84,15 @@ type (
 	appSettings struct {
 		logLevel zap.AtomicLevel
 		policies *placementPolicy
+		features *features.Registry
+		// operations are disabled S3 operations.
+		operations *api.Operations
+		// wireLog is nil if the wire log is disabled.
+		wireLog *api.WireLog
+		// presignNonces are shared by the auth center and the handler.
+		presignNonces *auth.PresignNonces
+		// operators release quarantined objects through the S3 API.
+		operators *adminOperators
 	}
 
 	Logger struct {
@@ -85,26 +120,47 @@ type (
 )
 
 func newApp(ctx context.Context, log *Logger, v *viper.Viper) *App {
-	conns, key := getPool(ctx, log.logger, v)
+	runPreflightChecks(ctx, log.logger, v, configPreflightChecks(v))
+
+	peers := fetchPeers(log.logger, v, cfgPeers)
+	conns, key := getPool(ctx, log.logger, v, peers)
+
+	settings := newAppSettings(log, v)
 
 	// prepare auth center
-	ctr := auth.New(neofs.NewAuthmateNeoFS(conns), key, v.GetStringSlice(cfgAllowedAccessKeyIDPrefixes), getAccessBoxCacheConfig(v, log.logger))
+	authmateNeoFS := neofs.NewAuthmateNeoFS(conns)
+	ctr := auth.New(authmateNeoFS, key, v.GetStringSlice(cfgAllowedAccessKeyIDPrefixes), getAccessBoxCacheConfig(v, log.logger),
+		settings.presignNonces, authmateNeoFS)
 
 	app := &App{
-		ctr:  ctr,
-		log:  log.logger,
-		cfg:  v,
-		pool: conns,
-		key:  key,
+		ctr:   ctr,
+		boxes: tokens.New(authmateNeoFS, key, getAccessBoxCacheConfig(v, log.logger)),
+		log:   log.logger,
+		cfg:   v,
+		pool:  conns,
+		key:   key,
+		peers: peers,
+
+		keyUsage: auth.NewKeyUsage(),
 
 		webDone: make(chan struct{}, 1),
 		wrkDone: make(chan struct{}, 1),
 
 		maxClients: newMaxClients(v),
-		settings:   newAppSettings(log, v),
+		settings:   settings,
+		control:    api.NewControlState(),
+	}
+
+	if v.GetBool(cfgDegradationEnabled) {
+		app.storage = api.NewStorageState(v.GetDuration(cfgDegradationReadTimeout))
+	}
+
+	for _, accessKeyID := range v.GetStringSlice(cfgControlRevokedKeys) {
+		app.control.SetRevoked(accessKeyID, true)
 	}
 
 	app.init(ctx)
+	runPreflightChecks(ctx, app.log, v, app.preflightChecks())
 
 	return app
 }
@@ -131,17 +187,66 @@ func (a *App) initLayer(ctx context.Context) {
 		a.log.Fatal("couldn't generate random key", zap.Error(err))
 	}
 
+	masterKey, err := getMasterKey(a.cfg, a.key)
+	if err != nil {
+		a.log.Fatal("couldn't init master key of server-side encryption", zap.Error(err))
+	}
+
+	kmsClient, err := getKMS(a.cfg)
+	if err != nil {
+		a.log.Fatal("couldn't init kms of server-side encryption", zap.Error(err))
+	}
+
+	archiveStorageClasses, err := getArchiveStorageClasses(a.cfg)
+	if err != nil {
+		a.log.Fatal("couldn't init archive storage classes", zap.Error(err))
+	}
+
+	systemObjectPrefix := a.cfg.GetString(cfgSystemObjectPrefix)
+	if a.cfg.IsSet(cfgSystemObjectPrefix) && systemObjectPrefix == "" {
+		a.log.Fatal("system object prefix must not be empty", zap.String("parameter", cfgSystemObjectPrefix))
+	}
+
 	layerCfg := &layer.Config{
 		Caches: getCacheOptions(a.cfg, a.log),
 		AnonKey: layer.AnonymousKey{
 			Key: randomKey,
 		},
-		Resolver:    a.bucketResolver,
-		TreeService: treeService,
+		Resolver:          a.bucketResolver,
+		TreeService:       treeService,
+		ConsistentListing: a.cfg.GetBool(cfgCompatibilityS3A),
+		MasterKey:         masterKey,
+		KMS:               kmsClient,
+		KMSKeyID:          a.cfg.GetString(cfgKMSKeyID),
+
+		PartRetries:              a.cfg.GetInt(cfgPartRetries),
+		PartRetryBufferSize:      a.cfg.GetInt64(cfgPartRetryBufferSize),
+		DeleteObjectsConcurrency: a.cfg.GetInt(cfgDeleteObjectsConcurrency),
+		ReplicaNetworks:          a.initReplicaNetworks(ctx),
+
+		ArchiveStorageClasses: archiveStorageClasses,
+		SystemObjectPrefix:    systemObjectPrefix,
 	}
 
+	if a.initObjectIndex() {
+		layerCfg.ObjectIndex = a.objectIndex
+	}
+
+	if a.cfg.GetBool(cfgScanningEnabled) {
+		layerCfg.QuarantineTagKey = a.cfg.GetString(cfgScanningQuarantineTagKey)
+		layerCfg.QuarantineTagValue = a.cfg.GetString(cfgScanningQuarantineTagValue)
+	}
+
+	neoFS := neofs.NewNeoFS(a.pool)
+	peerAddresses := make([]string, len(a.peers))
+	for i, peer := range a.peers {
+		peerAddresses[i] = peer.address
+	}
+	neoFS.SetNetmapPeers(&a.key.PrivateKey, peerAddresses)
+	a.neoFS = neoFS
+
 	// prepare object layer
-	a.obj = layer.NewLayer(a.log, neofs.NewNeoFS(a.pool), layerCfg)
+	a.obj = layer.NewLayer(a.log, neoFS, layerCfg)
 
 	if a.cfg.GetBool(cfgEnableNATS) {
 		nopts := getNotificationsOptions(a.cfg, a.log)
@@ -149,6 +254,7 @@ func (a *App) initLayer(ctx context.Context) {
 		if err != nil {
 			a.log.Fatal("failed to enable notifications", zap.Error(err))
 		}
+		prometheus.MustRegister(a.nc)
 
 		if err = a.obj.Initialize(ctx, a.nc); err != nil {
 			a.log.Fatal("couldn't initialize layer", zap.Error(err))
@@ -157,14 +263,42 @@ func (a *App) initLayer(ctx context.Context) {
 }
 
 func newAppSettings(log *Logger, v *viper.Viper) *appSettings {
-	policies, err := newPlacementPolicy(getDefaultPolicyValue(v), v.GetString(cfgPolicyRegionMapFile))
+	regionPolicyMap, err := fetchRegionMap(v)
+	if err != nil {
+		log.logger.Fatal("failed to read policy mapping", zap.Error(err))
+	}
+
+	policies, err := newPlacementPolicy(getDefaultPolicyValue(v), regionPolicyMap)
 	if err != nil {
 		log.logger.Fatal("failed to create new policy mapping", zap.Error(err))
 	}
 
+	registry, err := features.NewRegistry(fetchFeatures(v))
+	if err != nil {
+		log.logger.Fatal("failed to create feature flags", zap.Error(err))
+	}
+
+	operations, err := api.NewOperations(v.GetStringSlice(cfgOperationsDisabled), v.GetString(cfgOperationsResponse))
+	if err != nil {
+		log.logger.Fatal("failed to disable operations", zap.Error(err))
+	}
+
+	var wireLog *api.WireLog
+	if v.GetBool(cfgWireLogEnabled) {
+		if wireLog, err = newWireLog(v); err != nil {
+			log.logger.Fatal("failed to create wire log", zap.Error(err))
+		}
+	}
+
 	return &appSettings{
-		logLevel: log.lvl,
-		policies: policies,
+		logLevel:   log.lvl,
+		policies:   policies,
+		features:   registry,
+		operations: operations,
+		wireLog:    wireLog,
+
+		presignNonces: auth.NewPresignNonces(v.GetBool(cfgPresignRequireNonce)),
+		operators:     newAdminOperators(v.GetStringSlice(cfgAdminOperators)),
 	}
 }
 
@@ -183,10 +317,17 @@ func (a *App) initAPI(ctx context.Context) {
 }
 
 func (a *App) initMetrics() {
-	gateMetricsProvider := newGateMetrics(neofs.NewPoolStatistic(a.pool))
+	gateMetricsProvider := newGateMetrics(neofs.NewPoolStatistic(a.pool), a)
 	a.metrics = newAppMetrics(a.log, gateMetricsProvider, a.cfg.GetBool(cfgPrometheusEnabled))
 }
 
+// CacheStats returns statistics of the layer caches and the access box cache.
+func (a *App) CacheStats() map[string]cache.Stats {
+	stats := a.obj.CacheStats()
+	stats["accessbox"] = a.ctr.CacheStats()
+	return stats
+}
+
 func (a *App) initResolver() {
 	var err error
 	a.bucketResolver, err = resolver.NewBucketResolver(a.getResolverConfig())
@@ -228,20 +369,26 @@ func newMaxClients(cfg *viper.Viper) api.MaxClients {
 	return api.NewMaxClientsMiddleware(maxClientsCount, maxClientsDeadline)
 }
 
-func getPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper) (*pool.Pool, *keys.PrivateKey) {
-	var prm pool.InitParameters
-
+func getPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper, peers []peerInfo) (*pool.Pool, *keys.PrivateKey) {
 	password := wallet.GetPassword(cfg, cfgWalletPassphrase)
 	key, err := wallet.GetKeyFromPath(cfg.GetString(cfgWalletPath), cfg.GetString(cfgWalletAddress), password)
 	if err != nil {
 		logger.Fatal("could not load NeoFS private key", zap.Error(err))
 	}
 
-	prm.SetKey(&key.PrivateKey)
 	logger.Info("using credentials", zap.String("NeoFS", hex.EncodeToString(key.PublicKey().Bytes())))
 
-	for _, peer := range fetchPeers(logger, cfg) {
-		prm.AddNode(peer)
+	return newPool(ctx, logger, cfg, key, peers), key
+}
+
+// newPool creates the connection pool to the NeoFS nodes and dials it.
+func newPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper, key *keys.PrivateKey, peers []peerInfo) *pool.Pool {
+	var prm pool.InitParameters
+
+	prm.SetKey(&key.PrivateKey)
+
+	for _, peer := range peers {
+		prm.AddNode(pool.NewNodeParam(peer.priority, peer.address, peer.weight))
 	}
 
 	connTimeout := cfg.GetDuration(cfgConnectTimeout)
@@ -284,15 +431,15 @@ func getPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper) (*pool.P
 		logger.Fatal("failed to dial connection pool", zap.Error(err))
 	}
 
-	return p, key
+	return p
 }
 
-func newPlacementPolicy(defaultPolicy string, regionPolicyFilepath string) (*placementPolicy, error) {
+func newPlacementPolicy(defaultPolicy string, regionPolicyMap map[string]string) (*placementPolicy, error) {
 	policies := &placementPolicy{
 		regionMap: make(map[string]netmap.PlacementPolicy),
 	}
 
-	return policies, policies.update(defaultPolicy, regionPolicyFilepath)
+	return policies, policies.update(defaultPolicy, regionPolicyMap)
 }
 
 func (p *placementPolicy) Default() netmap.PlacementPolicy {
@@ -309,31 +456,24 @@ func (p *placementPolicy) Get(name string) (netmap.PlacementPolicy, bool) {
 	return policy, ok
 }
 
-func (p *placementPolicy) update(defaultPolicy string, regionPolicyFilepath string) error {
+func (p *placementPolicy) update(defaultPolicy string, regionPolicyMap map[string]string) error {
 	var defaultPlacementPolicy netmap.PlacementPolicy
 	if err := defaultPlacementPolicy.DecodeString(defaultPolicy); err != nil {
 		return fmt.Errorf("parse default policy '%s': %w", defaultPolicy, err)
 	}
 
-	regionPolicyMap, err := readRegionMap(regionPolicyFilepath)
-	if err != nil {
-		return fmt.Errorf("read region map file: %w", err)
-	}
-
 	regionMap := make(map[string]netmap.PlacementPolicy, len(regionPolicyMap))
 	for region, policy := range regionPolicyMap {
 		var pp netmap.PlacementPolicy
-		if err = pp.DecodeString(policy); err == nil {
+		if err := pp.DecodeString(policy); err == nil {
 			regionMap[region] = pp
 			continue
 		}
 
-		if err = pp.UnmarshalJSON([]byte(policy)); err == nil {
-			regionMap[region] = pp
-			continue
+		if err := pp.UnmarshalJSON([]byte(policy)); err != nil {
+			return fmt.Errorf("parse region '%s' to policy mapping: %w", region, err)
 		}
-
-		return fmt.Errorf("parse region '%s' to policy mapping: %w", region, err)
+		regionMap[region] = pp
 	}
 
 	p.mu.Lock()
@@ -420,9 +560,11 @@ func (a *App) setHealthStatus() {
 func (a *App) Serve(ctx context.Context) {
 	// Attach S3 API:
 	domains := a.cfg.GetStringSlice(cfgListenDomains)
-	a.log.Info("fetch domains, prepare to use API", zap.Strings("domains", domains))
+	accelerateDomains := a.cfg.GetStringSlice(cfgAccelerateDomains)
+	a.log.Info("fetch domains, prepare to use API", zap.Strings("domains", domains),
+		zap.Strings("accelerate domains", accelerateDomains))
 	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
-	api.Attach(router, domains, a.maxClients, a.api, a.ctr, a.log)
+	api.Attach(router, domains, accelerateDomains, a.maxClients, a.storage, a.control, a.settings.operations, a.settings.wireLog, a.api, a.ctr, a.keyUsage, a.log)
 
 	// Use mux.Router as http.Handler
 	srv := new(http.Server)
@@ -431,6 +573,38 @@ func (a *App) Serve(ctx context.Context) {
 
 	a.startServices()
 
+	go a.obj.RunTrashPurger(ctx)
+	go a.obj.RunReplicator(ctx)
+
+	if a.cfg.GetBool(cfgPackingEnabled) {
+		go a.runPacking(ctx)
+	}
+
+	if a.objectIndex != nil {
+		go a.runIndexReconciliation(ctx)
+	}
+
+	if a.cfg.GetBool(cfgLifecycleEnabled) {
+		go a.runLifecycle(ctx)
+		go a.runRestores(ctx)
+	}
+
+	if a.cfg.GetBool(cfgInventoryEnabled) {
+		go a.runInventory(ctx)
+	}
+
+	if a.cfg.GetBool(cfgTrashPurgeEnabled) {
+		go a.runTrashPurge(ctx)
+	}
+
+	if a.storage != nil {
+		go a.runStorageProbe(ctx)
+	}
+
+	if a.scanner != nil {
+		a.scanner.Start(ctx)
+	}
+
 	for i := range a.servers {
 		go func(i int) {
 			a.log.Info("starting server", zap.String("address", a.servers[i].Address()))
@@ -459,9 +633,19 @@ LOOP:
 
 	a.log.Info("stopping server", zap.Error(srv.Shutdown(ctx)))
 
+	if a.scanner != nil {
+		a.scanner.Stop()
+	}
+
 	a.metrics.Shutdown()
 	a.stopServices()
 
+	if a.objectIndex != nil {
+		if err := a.objectIndex.Close(); err != nil {
+			a.log.Error("couldn't close object index", zap.Error(err))
+		}
+	}
+
 	close(a.webDone)
 }
 
@@ -472,7 +656,7 @@ func shutdownContext() (context.Context, context.CancelFunc) {
 func (a *App) configReload() {
 	a.log.Info("SIGHUP config reload started")
 
-	if !a.cfg.IsSet(cmdConfig) {
+	if !a.cfg.IsSet(cmdConfig) && !a.cfg.IsSet(cfgConfigDir) {
 		a.log.Warn("failed to reload config because it's missed")
 		return
 	}
@@ -507,9 +691,28 @@ func (a *App) updateSettings() {
 		a.settings.logLevel.SetLevel(lvl)
 	}
 
-	if err := a.settings.policies.update(getDefaultPolicyValue(a.cfg), a.cfg.GetString(cfgPolicyRegionMapFile)); err != nil {
+	if regionPolicyMap, err := fetchRegionMap(a.cfg); err != nil {
+		a.log.Warn("policies won't be updated", zap.Error(err))
+	} else if err = a.settings.policies.update(getDefaultPolicyValue(a.cfg), regionPolicyMap); err != nil {
 		a.log.Warn("policies won't be updated", zap.Error(err))
 	}
+
+	if err := a.settings.features.Update(fetchFeatures(a.cfg)); err != nil {
+		a.log.Warn("feature flags won't be updated", zap.Error(err))
+	}
+
+	if err := a.settings.operations.Update(a.cfg.GetStringSlice(cfgOperationsDisabled), a.cfg.GetString(cfgOperationsResponse)); err != nil {
+		a.log.Warn("disabled operations won't be updated", zap.Error(err))
+	}
+
+	if a.settings.wireLog != nil {
+		if err := a.settings.wireLog.Update(getWireLogConfig(a.cfg)); err != nil {
+			a.log.Warn("wire log won't be updated", zap.Error(err))
+		}
+	}
+
+	a.settings.presignNonces.SetRequired(a.cfg.GetBool(cfgPresignRequireNonce))
+	a.settings.operators.update(a.cfg.GetStringSlice(cfgAdminOperators))
 }
 
 func (a *App) startServices() {
@@ -522,6 +725,22 @@ func (a *App) startServices() {
 	prometheusService := NewPrometheusService(a.cfg, a.log)
 	a.services = append(a.services, prometheusService)
 	go prometheusService.Start()
+
+	adminService := NewAdminService(a.cfg, a.log, a.obj, a.neoFS, a.ctr, a.keyUsage, a.control, a.settings.features)
+	a.services = append(a.services, adminService)
+	go adminService.Start()
+
+	statusService := NewStatusService(a.cfg, a.log, a.storage, a.control)
+	a.services = append(a.services, statusService)
+	go statusService.Start()
+
+	controlService := NewControlService(a.cfg, a.log, control.NewServer(a.control, a.storage, a.obj))
+	a.services = append(a.services, controlService)
+	go controlService.Start()
+
+	websiteService := NewWebsiteService(a.cfg, a.log, a.maxClients, a.api)
+	a.services = append(a.services, websiteService)
+	go websiteService.Start()
 }
 
 func (a *App) initServers(ctx context.Context) {
@@ -581,6 +800,14 @@ func getNotificationsOptions(v *viper.Viper, l *zap.Logger) *notifications.Optio
 	cfg.TLSCertFilepath = v.GetString(cfgNATSTLSCertFile)
 	cfg.TLSAuthPrivateKeyFilePath = v.GetString(cfgNATSAuthPrivateKeyFile)
 	cfg.RootCAFiles = v.GetStringSlice(cfgNATSRootCAFiles)
+	cfg.QueueSize = v.GetInt(cfgNATSQueueSize)
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = notifications.DefaultQueueSize
+	}
+	cfg.ParkTimeout = notifications.DefaultParkTimeout
+	if v.IsSet(cfgNATSParkTimeout) {
+		cfg.ParkTimeout = v.GetDuration(cfgNATSParkTimeout)
+	}
 
 	return &cfg
 }
@@ -609,6 +836,94 @@ func getCacheOptions(v *viper.Viper, l *zap.Logger) *layer.CachesConfig {
 	return cacheCfg
 }
 
+// getMasterKey returns the master key of server-side encryption with keys managed by the gateway.
+// The key is taken from the config or derived from the wallet key if it isn't set.
+func getMasterKey(v *viper.Viper, key *keys.PrivateKey) (*encryption.MasterKey, error) {
+	masterKeyHex := v.GetString(cfgEncryptionMasterKey)
+	if len(masterKeyHex) == 0 {
+		return encryption.DeriveMasterKey(key.Bytes())
+	}
+
+	masterKey, err := hex.DecodeString(masterKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+
+	return encryption.NewMasterKey(masterKey)
+}
+
+// getKMS returns the client of the external KMS of server-side encryption (SSE-KMS).
+// Nil is returned if the KMS isn't configured.
+func getKMS(v *viper.Viper) (layer.KeyManagementService, error) {
+	switch kmsType := v.GetString(cfgKMSType); kmsType {
+	case "":
+		return nil, nil
+	case "vault":
+		return kms.NewVault(kms.VaultConfig{
+			Endpoint: v.GetString(cfgKMSEndpoint),
+			Token:    v.GetString(cfgKMSVaultToken),
+			Mount:    v.GetString(cfgKMSVaultMount),
+			Timeout:  v.GetDuration(cfgKMSTimeout),
+		})
+	case "aws":
+		return kms.NewAWS(kms.AWSConfig{
+			Endpoint:        v.GetString(cfgKMSEndpoint),
+			Region:          v.GetString(cfgKMSRegion),
+			AccessKeyID:     v.GetString(cfgKMSAccessKeyID),
+			SecretAccessKey: v.GetString(cfgKMSSecretAccessKey),
+			Timeout:         v.GetDuration(cfgKMSTimeout),
+		})
+	default:
+		return nil, fmt.Errorf("unknown kms type '%s'", kmsType)
+	}
+}
+
+// getArchiveStorageClasses returns containers of archive storage classes of lifecycle transitions.
+// Names of storage classes are uppercased since config keys are case-insensitive.
+func getArchiveStorageClasses(v *viper.Viper) (map[string]cid.ID, error) {
+	classes := v.GetStringMapString(cfgArchiveStorageClasses)
+	res := make(map[string]cid.ID, len(classes))
+	for name, cnr := range classes {
+		name = strings.ToUpper(name)
+		if name == data.StorageClassStandard {
+			return nil, fmt.Errorf("storage class '%s' can't be archive", name)
+		}
+
+		var cnrID cid.ID
+		if err := cnrID.DecodeString(cnr); err != nil {
+			return nil, fmt.Errorf("invalid container of storage class '%s': %w", name, err)
+		}
+		res[name] = cnrID
+	}
+
+	return res, nil
+}
+
+// getStorageClasses returns numbers of the object copies of storage classes accepted in x-amz-storage-class header.
+// Names of storage classes are uppercased since config keys are case-insensitive.
+func getStorageClasses(v *viper.Viper) (map[string]uint32, error) {
+	classes := v.GetStringMapString(cfgStorageClasses)
+	res := make(map[string]uint32, len(classes))
+	for name, copies := range classes {
+		if v.IsSet(cfgArchiveStorageClasses + "." + name) {
+			return nil, fmt.Errorf("storage class '%s' is archive", strings.ToUpper(name))
+		}
+
+		name = strings.ToUpper(name)
+		if name == data.StorageClassStandard {
+			return nil, fmt.Errorf("copies number of storage class '%s' is set by '%s'", name, cfgSetCopiesNumber)
+		}
+
+		copiesNumber, err := strconv.ParseUint(copies, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid copies number of storage class '%s': %w", name, err)
+		}
+		res[name] = uint32(copiesNumber)
+	}
+
+	return res, nil
+}
+
 func getLifetime(v *viper.Viper, l *zap.Logger, cfgEntry string, defaultValue time.Duration) time.Duration {
 	if v.IsSet(cfgEntry) {
 		lifetime := v.GetDuration(cfgEntry)
@@ -650,10 +965,15 @@ func getAccessBoxCacheConfig(v *viper.Viper, l *zap.Logger) *cache.Config {
 
 func (a *App) initHandler() {
 	cfg := &handler.Config{
-		Policy:             a.settings.policies,
-		DefaultMaxAge:      handler.DefaultMaxAge,
-		NotificatorEnabled: a.cfg.GetBool(cfgEnableNATS),
-		CopiesNumber:       handler.DefaultCopiesNumber,
+		Policy:               a.settings.policies,
+		DefaultMaxAge:        handler.DefaultMaxAge,
+		NotificatorEnabled:   a.cfg.GetBool(cfgEnableNATS),
+		CopiesNumber:         handler.DefaultCopiesNumber,
+		SOSAPIEnabled:        a.cfg.GetBool(cfgSOSAPIEnabled),
+		Features:             a.settings.features,
+		PresignNonces:        a.settings.presignNonces,
+		CompleteKeepAlive:    a.cfg.GetDuration(cfgCompleteKeepAlive),
+		PreserveMetadataCase: a.cfg.GetBool(cfgCompatibilityPreserveMetadataCase),
 	}
 
 	if a.cfg.IsSet(cfgDefaultMaxAge) {
@@ -671,13 +991,74 @@ func (a *App) initHandler() {
 		cfg.CopiesNumber = val
 	}
 
-	var err error
+	storageClasses, err := getStorageClasses(a.cfg)
+	if err != nil {
+		a.log.Fatal("couldn't init storage classes", zap.Error(err))
+	}
+	cfg.StorageClasses = storageClasses
+
+	if a.cfg.GetBool(cfgHTMLListingEnabled) {
+		var text []byte
+		if templatePath := a.cfg.GetString(cfgHTMLListingTemplate); templatePath != "" {
+			var err error
+			if text, err = os.ReadFile(templatePath); err != nil {
+				a.log.Fatal("could not read HTML listing template", zap.Error(err))
+			}
+		}
+
+		tmpl, err := handler.ParseListingTemplate(string(text))
+		if err != nil {
+			a.log.Fatal("could not parse HTML listing template", zap.Error(err))
+		}
+		cfg.ListingTemplate = tmpl
+	}
+
+	if a.cfg.GetBool(cfgScanningEnabled) {
+		scanner, err := newScanner(a.cfg, a.log, a.obj)
+		if err != nil {
+			a.log.Fatal("could not initialize scanner", zap.Error(err))
+		}
+		a.scanner = scanner
+		cfg.Scanner = scanner
+	}
+
+	cfg.Operators = a.settings.operators
+
+	if devices := fetchMFADevices(a.cfg); len(devices) != 0 {
+		validator, err := auth.NewTOTPValidator(devices)
+		if err != nil {
+			a.log.Fatal("could not initialize MFA devices", zap.Error(err))
+		}
+		cfg.MFA = validator
+	}
+
 	a.api, err = handler.New(a.log, a.obj, a.nc, cfg)
 	if err != nil {
 		a.log.Fatal("could not initialize API handler", zap.Error(err))
 	}
 }
 
+// fetchRegionMap returns policies of location constraints from the region mapping file and
+// the locations of the config. A location constraint can't be set in both of them.
+func fetchRegionMap(v *viper.Viper) (map[string]string, error) {
+	regionMap, err := readRegionMap(v.GetString(cfgPolicyRegionMapFile))
+	if err != nil {
+		return nil, fmt.Errorf("read region map file: %w", err)
+	}
+
+	for region, policy := range v.GetStringMapString(cfgPolicyLocations) {
+		if region == api.DefaultLocationConstraint {
+			return nil, fmt.Errorf("config overrides %s location constraint", api.DefaultLocationConstraint)
+		}
+		if _, ok := regionMap[region]; ok {
+			return nil, fmt.Errorf("location constraint '%s' is set both in region map file and locations", region)
+		}
+		regionMap[region] = policy
+	}
+
+	return regionMap, nil
+}
+
 func readRegionMap(filePath string) (map[string]string, error) {
 	regionMap := make(map[string]string)
 
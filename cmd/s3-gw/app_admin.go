@@ -0,0 +1,900 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	errorsStd "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type (
+	// adminError is a body of admin API error response.
+	adminError struct {
+		Error string `json:"error"`
+	}
+
+	// configHistoryResponse is a body of admin API bucket configuration history response.
+	configHistoryResponse struct {
+		Bucket  string              `json:"bucket"`
+		Changes []data.ConfigChange `json:"changes"`
+	}
+
+	// diagnosticsResponse is a body of admin API diagnostics response.
+	diagnosticsResponse struct {
+		Version  string           `json:"version"`
+		Features []features.State `json:"features"`
+	}
+
+	// featuresResponse is a body of admin API bucket feature flags response.
+	featuresResponse struct {
+		Bucket string `json:"bucket"`
+		// Overrides are values of flags set for the bucket.
+		Overrides map[string]bool `json:"overrides"`
+		// Features are values of flags used for the bucket.
+		Features []features.State `json:"features"`
+	}
+
+	// uploadValidationResponse is a body of admin API bucket upload validation rules response.
+	uploadValidationResponse struct {
+		Bucket string `json:"bucket"`
+		// Rules are nil if upload validation is disabled.
+		Rules *data.UploadValidation `json:"rules"`
+	}
+
+	// bucketFlags is a body of admin API bucket flags request and response.
+	bucketFlags struct {
+		Bucket   string `json:"bucket,omitempty"`
+		ReadOnly bool   `json:"read_only"`
+		Frozen   bool   `json:"frozen"`
+	}
+
+	// trashResponse is a body of admin API bucket trash response.
+	trashResponse struct {
+		Bucket    string               `json:"bucket"`
+		Retention string               `json:"retention"`
+		Objects   []*layer.TrashObject `json:"objects"`
+	}
+
+	// accessKeysResponse is a body of admin API access keys usage response.
+	accessKeysResponse struct {
+		AccessKeys []accessKeyUse `json:"access_keys"`
+	}
+
+	accessKeyUse struct {
+		AccessKeyID string    `json:"access_key_id"`
+		LastUsed    time.Time `json:"last_used"`
+		SourceIP    string    `json:"source_ip"`
+	}
+
+	// adminOperators are credentials of operators allowed to use operator endpoints of the admin API
+	// for buckets of any owner.
+	adminOperators struct {
+		mu         sync.RWMutex
+		accessKeys map[string]struct{}
+		// owners are issuers of bearer tokens resolved from public keys of operators.
+		owners []user.ID
+	}
+
+	// networkSource provides the state of the storage network from the gateway's view.
+	networkSource interface {
+		CurrentEpoch(context.Context) (uint64, error)
+		NetmapSnapshot(context.Context) (*netmap.NetMap, error)
+		Statistic() pool.Statistic
+	}
+
+	// networkResponse is a body of admin API storage network response.
+	networkResponse struct {
+		Epoch uint64 `json:"epoch"`
+		// Netmap is nil if the network map couldn't be read, NetmapError describes the reason.
+		Netmap      *netmapSummary `json:"netmap"`
+		NetmapError string         `json:"netmap_error,omitempty"`
+		// Nodes are statistics of requests to the nodes of the connection pool.
+		Nodes []poolNodeStatistic `json:"nodes"`
+	}
+
+	netmapSummary struct {
+		Epoch       uint64       `json:"epoch"`
+		Online      int          `json:"online"`
+		Offline     int          `json:"offline"`
+		Maintenance int          `json:"maintenance"`
+		Nodes       []netmapNode `json:"nodes"`
+	}
+
+	netmapNode struct {
+		PublicKey string   `json:"public_key"`
+		Addresses []string `json:"addresses"`
+		State     string   `json:"state"`
+		LOCODE    string   `json:"locode,omitempty"`
+	}
+
+	poolNodeStatistic struct {
+		Address       string `json:"address"`
+		Requests      uint64 `json:"requests"`
+		OverallErrors uint64 `json:"overall_errors"`
+		// CurrentErrors are errors since the last health check, the node is excluded from the pool
+		// if they exceed the error threshold.
+		CurrentErrors uint32 `json:"current_errors"`
+	}
+)
+
+// NewAdminService creates a new service with administrative API.
+// Requests are authenticated by the center the same way as S3 requests, uses of access keys are recorded to usage.
+// Credentials revoked in the control state are rejected. Endpoints wrapped by operatorOnly are available
+// to operators only.
+func NewAdminService(v *viper.Viper, l *zap.Logger, obj layer.Client, network networkSource, center auth.Center, usage *auth.KeyUsage, control *api.ControlState, registry *features.Registry) *Service {
+	log := l.With(zap.String("service", "Admin"))
+	operators := newAdminOperators(v.GetStringSlice(cfgAdminOperators))
+
+	router := mux.NewRouter()
+	router.Use(adminAuth(center, usage, control, log))
+	router.Methods(http.MethodGet).Path("/api/v1/diagnostics").
+		HandlerFunc(operatorOnly(operators, log, diagnosticsHandler(registry, log)))
+	router.Methods(http.MethodGet).Path("/api/v1/access-keys").
+		HandlerFunc(accessKeysHandler(usage, log))
+	router.Methods(http.MethodGet).Path("/api/v1/network").
+		HandlerFunc(operatorOnly(operators, log, networkHandler(network, log)))
+	router.Methods(http.MethodGet).Path("/api/v1/buckets/{bucket}/config-history").
+		HandlerFunc(configHistoryHandler(obj, log))
+	router.Methods(http.MethodGet).Path("/api/v1/buckets/{bucket}/trash").
+		HandlerFunc(listTrashHandler(obj, log))
+	router.Methods(http.MethodPut).Path("/api/v1/buckets/{bucket}/trash").
+		HandlerFunc(putTrashHandler(obj, log))
+	router.Methods(http.MethodPost).Path("/api/v1/buckets/{bucket}/trash/restore").
+		HandlerFunc(restoreTrashHandler(obj, log))
+	router.Methods(http.MethodPut).Path("/api/v1/buckets/{bucket}/etag").
+		HandlerFunc(operatorOnly(operators, log, putETagAlgorithmHandler(obj, log)))
+	router.Methods(http.MethodPut).Path("/api/v1/buckets/{bucket}/notifications/queue-policy").
+		HandlerFunc(putNotificationQueuePolicyHandler(obj, log))
+	router.Methods(http.MethodPut).Path("/api/v1/buckets/{bucket}/sync-replication").
+		HandlerFunc(operatorOnly(operators, log, putSyncReplicationHandler(v, obj, log)))
+	router.Methods(http.MethodDelete).Path("/api/v1/buckets/{bucket}/sync-replication").
+		HandlerFunc(operatorOnly(operators, log, deleteSyncReplicationHandler(obj, log)))
+	router.Methods(http.MethodPost).Path("/api/v1/buckets/{bucket}/pack").
+		HandlerFunc(packHandler(v, obj, log))
+	router.Methods(http.MethodGet).Path("/api/v1/buckets/{bucket}/features").
+		HandlerFunc(operatorOnly(operators, log, getFeaturesHandler(obj, registry, log)))
+	router.Methods(http.MethodPut).Path("/api/v1/buckets/{bucket}/features").
+		HandlerFunc(operatorOnly(operators, log, putFeaturesHandler(obj, log)))
+	router.Methods(http.MethodDelete).Path("/api/v1/buckets/{bucket}/features").
+		HandlerFunc(operatorOnly(operators, log, deleteFeaturesHandler(obj, log)))
+	router.Methods(http.MethodGet).Path("/api/v1/buckets/{bucket}/flags").
+		HandlerFunc(operatorOnly(operators, log, getBucketFlagsHandler(obj, log)))
+	router.Methods(http.MethodPut).Path("/api/v1/buckets/{bucket}/flags").
+		HandlerFunc(operatorOnly(operators, log, putBucketFlagsHandler(obj, log)))
+	router.Methods(http.MethodGet).Path("/api/v1/buckets/{bucket}/upload-validation").
+		HandlerFunc(operatorOnly(operators, log, getUploadValidationHandler(obj, log)))
+	router.Methods(http.MethodPut).Path("/api/v1/buckets/{bucket}/upload-validation").
+		HandlerFunc(operatorOnly(operators, log, putUploadValidationHandler(obj, log)))
+	router.Methods(http.MethodDelete).Path("/api/v1/buckets/{bucket}/upload-validation").
+		HandlerFunc(operatorOnly(operators, log, deleteUploadValidationHandler(obj, log)))
+
+	return &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgAdminAddress),
+			Handler: router,
+		},
+		enabled:     v.GetBool(cfgAdminEnabled),
+		serviceType: "Admin",
+		log:         log,
+	}
+}
+
+func configHistoryHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := adminBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		history, err := obj.GetBucketConfigHistory(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, configHistoryResponse{
+			Bucket:  bktInfo.Name,
+			Changes: history,
+		})
+	}
+}
+
+func listTrashHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := adminBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		objects, err := obj.ListTrash(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, trashResponse{
+			Bucket:    bktInfo.Name,
+			Retention: settings.TrashRetention.String(),
+			Objects:   objects,
+		})
+	}
+}
+
+// putTrashHandler sets retention period of the bucket trash, zero retention disables the trash.
+func putTrashHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		retention, err := time.ParseDuration(r.URL.Query().Get("retention"))
+		if err != nil || retention < 0 {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid retention: " + r.URL.Query().Get("retention")})
+			return
+		}
+
+		bktInfo, ok := adminBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		if !settings.Unversioned() {
+			writeAdminResponse(w, log, http.StatusConflict, adminError{Error: "trash is supported only for unversioned buckets"})
+			return
+		}
+
+		newSettings := *settings
+		newSettings.TrashRetention = retention
+		if err = obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings}); err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func restoreTrashHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := adminBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		trashObj, err := obj.RestoreTrashObject(r.Context(), bktInfo, r.URL.Query().Get("name"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.IsS3Error(err, errors.ErrNoSuchKey) {
+				status = http.StatusNotFound
+			} else if errorsStd.Is(err, layer.ErrObjectExists) {
+				status = http.StatusConflict
+			}
+			writeAdminResponse(w, log, status, adminError{Error: err.Error()})
+			return
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, trashObj)
+	}
+}
+
+// putETagAlgorithmHandler sets the source of ETag of new objects of the bucket,
+// ETags of existing objects are not changed.
+func putETagAlgorithmHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		algorithm := r.URL.Query().Get("algorithm")
+		switch algorithm {
+		case data.ETagAlgorithmSHA256, data.ETagAlgorithmMD5, data.ETagAlgorithmCID:
+		default:
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid etag algorithm: " + algorithm})
+			return
+		}
+
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		newSettings := *settings
+		newSettings.ETagAlgorithm = algorithm
+		if err = obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings}); err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// putNotificationQueuePolicyHandler sets the policy of notification events of the bucket
+// sent to full queues of notification targets.
+func putNotificationQueuePolicyHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policy := r.URL.Query().Get("policy")
+		switch policy {
+		case data.NotificationQueuePolicyDrop, data.NotificationQueuePolicyPark:
+		default:
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid notification queue policy: " + policy})
+			return
+		}
+
+		bktInfo, ok := adminBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		newSettings := *settings
+		newSettings.NotificationQueuePolicy = policy
+		if err = obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings}); err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// putSyncReplicationHandler makes new objects of the bucket be stored in the secondary container
+// before the request succeeds. Existing objects are not replicated.
+func putSyncReplicationHandler(v *viper.Viper, obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		replication := &data.SyncReplication{Network: r.URL.Query().Get("network")}
+		if err := replication.Container.DecodeString(r.URL.Query().Get("container")); err != nil {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid container: " + r.URL.Query().Get("container")})
+			return
+		}
+		if !isReplicaNetwork(v, replication.Network) {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "unknown network: " + replication.Network})
+			return
+		}
+
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		if replication.Network == "" && replication.Container.Equals(bktInfo.CID) {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "secondary container must differ from the bucket one"})
+			return
+		}
+
+		setSyncReplication(w, r, obj, log, bktInfo, replication)
+	}
+}
+
+func deleteSyncReplicationHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		setSyncReplication(w, r, obj, log, bktInfo, nil)
+	}
+}
+
+func setSyncReplication(w http.ResponseWriter, r *http.Request, obj layer.Client, log *zap.Logger, bktInfo *data.BucketInfo, replication *data.SyncReplication) {
+	settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+		return
+	}
+
+	newSettings := *settings
+	newSettings.SyncReplication = replication
+	if err = obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings}); err != nil {
+		writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// packHandler packs small objects of the bucket with parameters from the config.
+func packHandler(v *viper.Viper, obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := adminBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		result, err := obj.PackObjects(r.Context(), packingParams(v, bktInfo))
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, result)
+	}
+}
+
+// diagnosticsHandler reports the version of the gateway and feature flags of the deployment.
+func diagnosticsHandler(registry *features.Registry, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeAdminResponse(w, log, http.StatusOK, diagnosticsResponse{
+			Version:  version.Version,
+			Features: registry.States(nil),
+		})
+	}
+}
+
+// networkHandler reports the current epoch, the network map and statistics of the nodes of the connection pool,
+// so errors of the gateway can be correlated with changes of the storage network.
+func networkHandler(network networkSource, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		epoch, err := network.CurrentEpoch(r.Context())
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusServiceUnavailable, adminError{Error: err.Error()})
+			return
+		}
+
+		resp := networkResponse{Epoch: epoch}
+		if nm, err := network.NetmapSnapshot(r.Context()); err != nil {
+			resp.NetmapError = err.Error()
+		} else {
+			resp.Netmap = newNetmapSummary(nm)
+		}
+
+		stat := network.Statistic()
+		resp.Nodes = make([]poolNodeStatistic, 0, len(stat.Nodes()))
+		for _, node := range stat.Nodes() {
+			resp.Nodes = append(resp.Nodes, poolNodeStatistic{
+				Address:       node.Address(),
+				Requests:      node.Requests(),
+				OverallErrors: node.OverallErrors(),
+				CurrentErrors: node.CurrentErrors(),
+			})
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, resp)
+	}
+}
+
+// accessKeysHandler reports the last uses of access keys of the request owner. Keys used since
+// the time set by 'unused-for' duration are skipped, so stale keys can be found.
+func accessKeysHandler(usage *auth.KeyUsage, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var unusedSince time.Time
+		if unusedFor := r.URL.Query().Get("unused-for"); unusedFor != "" {
+			period, err := time.ParseDuration(unusedFor)
+			if err != nil || period <= 0 {
+				writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid unused-for: " + unusedFor})
+				return
+			}
+			unusedSince = time.Now().Add(-period)
+		}
+
+		box := r.Context().Value(api.BoxData).(*accessbox.Box)
+		uses := usage.List(bearer.ResolveIssuer(*box.Gate.BearerToken), unusedSince)
+
+		resp := accessKeysResponse{AccessKeys: make([]accessKeyUse, 0, len(uses))}
+		for _, use := range uses {
+			resp.AccessKeys = append(resp.AccessKeys, accessKeyUse{
+				AccessKeyID: use.AccessKeyID,
+				LastUsed:    use.LastUsed.UTC(),
+				SourceIP:    use.SourceIP,
+			})
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, resp)
+	}
+}
+
+func newNetmapSummary(nm *netmap.NetMap) *netmapSummary {
+	res := &netmapSummary{
+		Epoch: nm.Epoch(),
+		Nodes: make([]netmapNode, 0, len(nm.Nodes())),
+	}
+
+	for _, node := range nm.Nodes() {
+		info := netmapNode{
+			PublicKey: hex.EncodeToString(node.PublicKey()),
+			Addresses: make([]string, 0, node.NumberOfNetworkEndpoints()),
+			LOCODE:    node.LOCODE(),
+		}
+		netmap.IterateNetworkEndpoints(node, func(address string) {
+			info.Addresses = append(info.Addresses, address)
+		})
+
+		switch {
+		case node.IsOnline():
+			info.State = "online"
+			res.Online++
+		case node.IsMaintenance():
+			info.State = "maintenance"
+			res.Maintenance++
+		default:
+			info.State = "offline"
+			res.Offline++
+		}
+
+		res.Nodes = append(res.Nodes, info)
+	}
+
+	return res
+}
+
+func getFeaturesHandler(obj layer.Client, registry *features.Registry, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		overrides := settings.Features
+		if overrides == nil {
+			overrides = make(map[string]bool)
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, featuresResponse{
+			Bucket:    bktInfo.Name,
+			Overrides: overrides,
+			Features:  registry.States(settings.Features),
+		})
+	}
+}
+
+// putFeaturesHandler replaces feature flags of the bucket by the flags from the JSON object of the request body,
+// flags missing in the object get values of the deployment.
+func putFeaturesHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var overrides map[string]bool
+		if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid feature flags: " + err.Error()})
+			return
+		}
+		if err := features.Check(overrides); err != nil {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: err.Error()})
+			return
+		}
+
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		setFeatures(w, r, obj, log, bktInfo, overrides)
+	}
+}
+
+func deleteFeaturesHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		setFeatures(w, r, obj, log, bktInfo, nil)
+	}
+}
+
+func setFeatures(w http.ResponseWriter, r *http.Request, obj layer.Client, log *zap.Logger, bktInfo *data.BucketInfo, overrides map[string]bool) {
+	settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+		return
+	}
+
+	newSettings := *settings
+	newSettings.Features = overrides
+	if len(overrides) == 0 {
+		newSettings.Features = nil
+	}
+	if err = obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings}); err != nil {
+		writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getBucketFlagsHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, bucketFlags{
+			Bucket:   bktInfo.Name,
+			ReadOnly: settings.ReadOnly,
+			Frozen:   settings.Frozen,
+		})
+	}
+}
+
+// putBucketFlagsHandler sets read-only and frozen flags of the bucket from the JSON object of the request body.
+// Requests forbidden by the flags are rejected before the evaluation of the bucket policy.
+func putBucketFlagsHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var flags bucketFlags
+		if err := json.NewDecoder(r.Body).Decode(&flags); err != nil {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid bucket flags: " + err.Error()})
+			return
+		}
+
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		newSettings := *settings
+		newSettings.ReadOnly = flags.ReadOnly
+		newSettings.Frozen = flags.Frozen
+		if err = obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings}); err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		log.Info("bucket flags are set", zap.String("bucket", bktInfo.Name),
+			zap.Bool("read_only", flags.ReadOnly), zap.Bool("frozen", flags.Frozen))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getUploadValidationHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+		if err != nil {
+			writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+			return
+		}
+
+		writeAdminResponse(w, log, http.StatusOK, uploadValidationResponse{
+			Bucket: bktInfo.Name,
+			Rules:  settings.UploadValidation,
+		})
+	}
+}
+
+// putUploadValidationHandler replaces upload validation rules of the bucket by the rules from the JSON object
+// of the request body. Existing objects are not checked.
+func putUploadValidationHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rules := new(data.UploadValidation)
+		if err := json.NewDecoder(r.Body).Decode(rules); err != nil {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: "invalid upload validation rules: " + err.Error()})
+			return
+		}
+		if err := rules.Validate(); err != nil {
+			writeAdminResponse(w, log, http.StatusBadRequest, adminError{Error: err.Error()})
+			return
+		}
+
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		setUploadValidation(w, r, obj, log, bktInfo, rules)
+	}
+}
+
+func deleteUploadValidationHandler(obj layer.Client, log *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+		if !ok {
+			return
+		}
+
+		setUploadValidation(w, r, obj, log, bktInfo, nil)
+	}
+}
+
+func setUploadValidation(w http.ResponseWriter, r *http.Request, obj layer.Client, log *zap.Logger, bktInfo *data.BucketInfo, rules *data.UploadValidation) {
+	settings, err := obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+		return
+	}
+
+	newSettings := *settings
+	newSettings.UploadValidation = rules
+	if err = obj.PutBucketSettings(r.Context(), &layer.PutSettingsParams{BktInfo: bktInfo, Settings: &newSettings}); err != nil {
+		writeAdminResponse(w, log, http.StatusInternalServerError, adminError{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newAdminOperators(credentials []string) *adminOperators {
+	operators := new(adminOperators)
+	operators.update(credentials)
+	return operators
+}
+
+// update replaces credentials of operators.
+func (o *adminOperators) update(credentials []string) {
+	accessKeys := make(map[string]struct{})
+	var owners []user.ID
+	for _, credential := range credentials {
+		if key, err := keys.NewPublicKeyFromString(credential); err == nil {
+			var owner user.ID
+			user.IDFromKey(&owner, (ecdsa.PublicKey)(*key))
+			owners = append(owners, owner)
+			continue
+		}
+		accessKeys[credential] = struct{}{}
+	}
+
+	o.mu.Lock()
+	o.accessKeys, o.owners = accessKeys, owners
+	o.mu.Unlock()
+}
+
+// Allowed checks whether the request is made with the access key of an operator or with the bearer token
+// issued by an operator.
+func (o *adminOperators) Allowed(r *http.Request) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	accessKeyID, _ := r.Context().Value(api.AccessKeyID).(string)
+	if _, ok := o.accessKeys[accessKeyID]; ok {
+		return true
+	}
+
+	box, ok := r.Context().Value(api.BoxData).(*accessbox.Box)
+	if !ok || box.Gate == nil || box.Gate.BearerToken == nil {
+		return false
+	}
+	issuer := bearer.ResolveIssuer(*box.Gate.BearerToken)
+	for _, owner := range o.owners {
+		if owner.Equals(issuer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// operatorOnly rejects requests made without credentials of operators, the bucket ownership doesn't
+// allow to use the endpoint.
+func operatorOnly(operators *adminOperators, log *zap.Logger, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !operators.Allowed(r) {
+			writeAdminResponse(w, log, http.StatusForbidden, adminError{Error: "access denied: only operators can use the endpoint"})
+			return
+		}
+		h(w, r)
+	}
+}
+
+// adminAuth authenticates requests by the signature of the access key and puts the access box to
+// the request context, so NeoFS requests are made with the bearer token of the access box.
+// Requests with credentials revoked by the control service or the configuration are rejected.
+func adminAuth(center auth.Center, usage *auth.KeyUsage, control *api.ControlState, log *zap.Logger) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			box, err := center.Authenticate(r)
+			if err != nil {
+				log.Debug("failed to pass authentication", zap.Error(err))
+				status := http.StatusForbidden
+				if err == auth.ErrNoAuthorizationHeader {
+					status = http.StatusUnauthorized
+				}
+				writeAdminResponse(w, log, status, adminError{Error: "authentication failed: " + err.Error()})
+				return
+			}
+
+			if control.IsRevoked(box.AccessKeyID) {
+				writeAdminResponse(w, log, http.StatusForbidden, adminError{Error: "authentication failed: access key is revoked"})
+				return
+			}
+
+			if box.AccessBox.Gate == nil || box.AccessBox.Gate.BearerToken == nil {
+				writeAdminResponse(w, log, http.StatusForbidden, adminError{Error: "bearer token is required"})
+				return
+			}
+			usage.UseBox(box, api.GetSourceIP(r), time.Now())
+
+			ctx := context.WithValue(r.Context(), api.BoxData, box.AccessBox)
+			ctx = context.WithValue(ctx, api.AccessKeyID, box.AccessKeyID)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// adminBucketInfo gets info of the bucket from request path and writes error response if it's failed.
+// Only the bucket owner is allowed to manage the bucket.
+func adminBucketInfo(w http.ResponseWriter, r *http.Request, obj layer.Client, log *zap.Logger) (*data.BucketInfo, bool) {
+	bktInfo, ok := operatorBucketInfo(w, r, obj, log)
+	if !ok {
+		return nil, false
+	}
+
+	box := r.Context().Value(api.BoxData).(*accessbox.Box)
+	if !bktInfo.Owner.Equals(bearer.ResolveIssuer(*box.Gate.BearerToken)) {
+		writeAdminResponse(w, log, http.StatusForbidden, adminError{Error: "access denied: only the bucket owner can manage the bucket"})
+		return nil, false
+	}
+
+	return bktInfo, true
+}
+
+// operatorBucketInfo gets info of the bucket from request path and writes error response if it's failed.
+// The owner of the bucket isn't checked, so it must be used only by endpoints wrapped by operatorOnly.
+func operatorBucketInfo(w http.ResponseWriter, r *http.Request, obj layer.Client, log *zap.Logger) (*data.BucketInfo, bool) {
+	bktInfo, err := obj.GetBucketInfo(r.Context(), mux.Vars(r)["bucket"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsS3Error(err, errors.ErrNoSuchBucket) {
+			status = http.StatusNotFound
+		}
+		writeAdminResponse(w, log, status, adminError{Error: err.Error()})
+		return nil, false
+	}
+
+	return bktInfo, true
+}
+
+func writeAdminResponse(w http.ResponseWriter, log *zap.Logger, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Error("couldn't write admin response", zap.Error(err))
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+)
+
+// errNoBackgroundCredentials is returned if the access key id of background jobs isn't configured.
+var errNoBackgroundCredentials = errors.New("access key id of background jobs isn't set")
+
+// backgroundContext returns the context with the access box of background jobs, like packing,
+// resolved from the access key id in the config. Box is cached, so it's resolved on every run
+// to take the new access key id after the config reload.
+func (a *App) backgroundContext(ctx context.Context) (context.Context, error) {
+	accessKeyID := a.cfg.GetString(cfgBackgroundAccessKeyID)
+	if len(accessKeyID) == 0 {
+		return nil, errNoBackgroundCredentials
+	}
+
+	var addr oid.Address
+	if err := addr.DecodeString(strings.ReplaceAll(accessKeyID, "0", "/")); err != nil {
+		return nil, fmt.Errorf("invalid access key id '%s': %w", accessKeyID, err)
+	}
+
+	box, err := a.boxes.GetBox(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get access box: %w", err)
+	}
+
+	return context.WithValue(ctx, api.BoxData, box), nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/nspcc-dev/neofs-s3-gw/control"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// NewControlService creates a new service with gRPC control API. The service works over mutual TLS only:
+// clients must present certificates signed by the configured CA.
+func NewControlService(v *viper.Viper, l *zap.Logger, srv control.ControlServiceServer) *Service {
+	log := l.With(zap.String("service", "Control"))
+
+	enabled := v.GetBool(cfgControlEnabled)
+	var tlsConfig *tls.Config
+	if enabled {
+		var err error
+		if tlsConfig, err = getControlTLSConfig(v); err != nil {
+			log.Error("control service is disabled, invalid tls configuration", zap.Error(err))
+			enabled = false
+		}
+	}
+
+	grpcServer := grpc.NewServer()
+	control.RegisterControlServiceServer(grpcServer, srv)
+
+	return &Service{
+		Server: &http.Server{
+			Addr:      v.GetString(cfgControlAddress),
+			Handler:   grpcServer,
+			TLSConfig: tlsConfig,
+		},
+		enabled:     enabled,
+		serviceType: "Control",
+		log:         log,
+	}
+}
+
+func getControlTLSConfig(v *viper.Viper) (*tls.Config, error) {
+	certFile, keyFile := v.GetString(cfgControlTLSCertFile), v.GetString(cfgControlTLSKeyFile)
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("certificate and key files must be set")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load key pair: %w", err)
+	}
+
+	caFile := v.GetString(cfgControlTLSCAFile)
+	if caFile == "" {
+		return nil, errors.New("ca file of client certificates must be set")
+	}
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("read ca file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificates in ca file '%s'", caFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+	}, nil
+}
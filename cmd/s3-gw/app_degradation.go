@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	storageStatusOK       = "ok"
+	storageStatusDegraded = "degraded"
+	// gatewayStatusMaintenance is a status of the gateway in maintenance mode.
+	gatewayStatusMaintenance = "maintenance"
+)
+
+// statusResponse is a body of the status service response.
+type statusResponse struct {
+	Status string `json:"status"`
+	// Since is a time since the storage is unavailable or the gateway is in maintenance mode.
+	Since *time.Time `json:"since,omitempty"`
+}
+
+// runStorageProbe periodically checks availability of the storage network until the context is done.
+// Gateway switches to degraded mode after the configured number of failed checks in a row
+// and switches back after the first successful check.
+func (a *App) runStorageProbe(ctx context.Context) {
+	interval := a.cfg.GetDuration(cfgDegradationProbeInterval)
+	if interval <= 0 {
+		interval = defaultDegradationProbeInterval
+	}
+	threshold := a.cfg.GetInt(cfgDegradationFailureThreshold)
+	if threshold <= 0 {
+		threshold = defaultDegradationFailureThreshold
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var failures int
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := a.probeStorage(ctx)
+		if err == nil {
+			failures = 0
+			if a.storage.SetAvailable(true) {
+				a.log.Info("storage is available, degraded mode is off")
+			}
+			continue
+		}
+
+		failures++
+		a.log.Warn("storage probe failed", zap.Int("failures", failures), zap.Error(err))
+		if failures >= threshold && a.storage.SetAvailable(false) {
+			a.log.Error("storage is unavailable, degraded mode is on", zap.Error(err))
+		}
+	}
+}
+
+func (a *App) probeStorage(ctx context.Context) error {
+	timeout := a.cfg.GetDuration(cfgDegradationProbeTimeout)
+	if timeout <= 0 {
+		timeout = defaultDegradationProbeTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := a.pool.NetworkInfo(ctx)
+	return err
+}
+
+// NewStatusService creates a new service reporting the gateway state for load balancers.
+// The status is ok if degraded mode is disabled and the gateway isn't in maintenance mode.
+func NewStatusService(v *viper.Viper, l *zap.Logger, storage *api.StorageState, control *api.ControlState) *Service {
+	log := l.With(zap.String("service", "Status"))
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if maintenance, since := control.Maintenance(); maintenance {
+			writeAdminResponse(w, log, http.StatusServiceUnavailable, statusResponse{Status: gatewayStatusMaintenance, Since: &since})
+			return
+		}
+		if storage != nil {
+			if degraded, since := storage.Degraded(); degraded {
+				writeAdminResponse(w, log, http.StatusServiceUnavailable, statusResponse{Status: storageStatusDegraded, Since: &since})
+				return
+			}
+		}
+		writeAdminResponse(w, log, http.StatusOK, statusResponse{Status: storageStatusOK})
+	})
+
+	return &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgStatusAddress),
+			Handler: handler,
+		},
+		enabled:     v.GetBool(cfgStatusEnabled),
+		serviceType: "Status",
+		log:         log,
+	}
+}
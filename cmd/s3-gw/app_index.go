@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/internal/objectindex"
+	"go.uber.org/zap"
+)
+
+const objectIndexBackendBolt = "bbolt"
+
+// initObjectIndex opens the object index if it's configured and reports whether it's enabled.
+func (a *App) initObjectIndex() bool {
+	backend := a.cfg.GetString(cfgObjectIndexBackend)
+	switch backend {
+	case "":
+		return false
+	case objectIndexBackendBolt:
+		index, err := objectindex.NewBoltIndex(a.cfg.GetString(cfgObjectIndexPath))
+		if err != nil {
+			a.log.Fatal("couldn't open object index", zap.Error(err))
+		}
+		a.objectIndex = index
+	default:
+		a.log.Fatal("unsupported object index backend", zap.String("backend", backend))
+	}
+
+	a.log.Info("object index enabled", zap.String("backend", backend))
+	return true
+}
+
+// runIndexReconciliation periodically reconciles the object index of the configured buckets
+// with the tree service until the context is done.
+func (a *App) runIndexReconciliation(ctx context.Context) {
+	interval := a.cfg.GetDuration(cfgObjectIndexReconcileInterval)
+	if interval <= 0 {
+		interval = defaultObjectIndexReconcileInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.reconcileIndex(ctx)
+		}
+	}
+}
+
+func (a *App) reconcileIndex(ctx context.Context) {
+	ctx, err := a.backgroundContext(ctx)
+	if err != nil {
+		a.log.Error("couldn't get credentials to reconcile object index", zap.Error(err))
+		return
+	}
+
+	for _, bktName := range a.cfg.GetStringSlice(cfgObjectIndexBuckets) {
+		bktInfo, err := a.obj.GetBucketInfo(ctx, bktName)
+		if err != nil {
+			a.log.Error("couldn't get bucket info to reconcile object index", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		if err = a.obj.ReconcileObjectIndex(ctx, bktInfo); err != nil {
+			a.log.Error("couldn't reconcile object index", zap.String("bucket", bktName), zap.Error(err))
+		}
+	}
+}
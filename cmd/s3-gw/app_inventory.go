@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// runInventory periodically writes due inventory reports and storage class analysis exports
+// of the configured buckets until the context is done.
+func (a *App) runInventory(ctx context.Context) {
+	interval := a.cfg.GetDuration(cfgInventoryInterval)
+	if interval <= 0 {
+		interval = defaultInventoryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.writeInventoryReports(ctx)
+		}
+	}
+}
+
+func (a *App) writeInventoryReports(ctx context.Context) {
+	ctx, err := a.backgroundContext(ctx)
+	if err != nil {
+		a.log.Error("couldn't get credentials to write inventory reports", zap.Error(err))
+		return
+	}
+
+	for _, bktName := range a.cfg.GetStringSlice(cfgInventoryBuckets) {
+		bktInfo, err := a.obj.GetBucketInfo(ctx, bktName)
+		if err != nil {
+			a.log.Error("couldn't get bucket info to write inventory reports", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		written, err := a.obj.WriteInventoryReports(ctx, bktInfo)
+		if err != nil {
+			a.log.Error("couldn't write inventory reports", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		if written != 0 {
+			a.log.Info("inventory reports written", zap.String("bucket", bktName), zap.Int("reports", written))
+		}
+
+		written, err = a.obj.WriteAnalyticsExports(ctx, bktInfo)
+		if err != nil {
+			a.log.Error("couldn't write analytics exports", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		if written != 0 {
+			a.log.Info("analytics exports written", zap.String("bucket", bktName), zap.Int("exports", written))
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// runLifecycle periodically expires and transitions objects of the configured buckets according to
+// their lifecycle configurations until the context is done.
+func (a *App) runLifecycle(ctx context.Context) {
+	interval := a.cfg.GetDuration(cfgLifecycleInterval)
+	if interval <= 0 {
+		interval = defaultLifecycleInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.expireObjects(ctx)
+		}
+	}
+}
+
+func (a *App) expireObjects(ctx context.Context) {
+	ctx, err := a.backgroundContext(ctx)
+	if err != nil {
+		a.log.Error("couldn't get credentials to expire objects", zap.Error(err))
+		return
+	}
+
+	for _, bktName := range a.cfg.GetStringSlice(cfgLifecycleBuckets) {
+		bktInfo, err := a.obj.GetBucketInfo(ctx, bktName)
+		if err != nil {
+			a.log.Error("couldn't get bucket info to expire objects", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		expired, err := a.obj.ExpireObjects(ctx, bktInfo)
+		if err != nil {
+			a.log.Error("couldn't expire objects", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		if expired != 0 {
+			a.log.Info("objects expired", zap.String("bucket", bktName), zap.Int("expired", expired))
+		}
+
+		transitioned, err := a.obj.TransitionObjects(ctx, bktInfo)
+		if err != nil {
+			a.log.Error("couldn't transition objects", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		if transitioned != 0 {
+			a.log.Info("objects transitioned", zap.String("bucket", bktName), zap.Int("transitioned", transitioned))
+		}
+	}
+}
+
+// runRestores periodically restores archived objects of the configured buckets and deletes
+// expired restored copies until the context is done.
+func (a *App) runRestores(ctx context.Context) {
+	interval := a.cfg.GetDuration(cfgArchiveRestoreInterval)
+	if interval <= 0 {
+		interval = defaultArchiveRestoreInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.processRestores(ctx)
+		}
+	}
+}
+
+func (a *App) processRestores(ctx context.Context) {
+	ctx, err := a.backgroundContext(ctx)
+	if err != nil {
+		a.log.Error("couldn't get credentials to restore objects", zap.Error(err))
+		return
+	}
+
+	for _, bktName := range a.cfg.GetStringSlice(cfgLifecycleBuckets) {
+		bktInfo, err := a.obj.GetBucketInfo(ctx, bktName)
+		if err != nil {
+			a.log.Error("couldn't get bucket info to restore objects", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		restored, err := a.obj.ProcessRestores(ctx, bktInfo)
+		if err != nil {
+			a.log.Error("couldn't restore objects", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		if restored != 0 {
+			a.log.Info("objects restored", zap.String("bucket", bktName), zap.Int("restored", restored))
+		}
+	}
+}
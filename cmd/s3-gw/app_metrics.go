@@ -3,6 +3,7 @@ package main
 import (
 	"net/http"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -14,6 +15,7 @@ const (
 	namespace      = "neofs_s3_gw"
 	stateSubsystem = "state"
 	poolSubsystem  = "pool"
+	cacheSubsystem = "cache"
 
 	methodGetBalance       = "get_balance"
 	methodPutContainer     = "put_container"
@@ -36,15 +38,29 @@ type StatisticScraper interface {
 	Statistic() pool.Statistic
 }
 
+// CacheStatScraper provides statistics of the gateway caches by their names.
+type CacheStatScraper interface {
+	CacheStats() map[string]cache.Stats
+}
+
 type GateMetrics struct {
 	stateMetrics
 	poolMetricsCollector
+	cacheMetricsCollector
 }
 
 type stateMetrics struct {
 	healthCheck prometheus.Gauge
 }
 
+type cacheMetricsCollector struct {
+	cacheStatScraper CacheStatScraper
+	hits             *prometheus.Desc
+	misses           *prometheus.Desc
+	evictions        *prometheus.Desc
+	entries          *prometheus.Desc
+}
+
 type poolMetricsCollector struct {
 	poolStatScraper     StatisticScraper
 	overallErrors       prometheus.Gauge
@@ -54,22 +70,27 @@ type poolMetricsCollector struct {
 	requestDuration     *prometheus.GaugeVec
 }
 
-func newGateMetrics(scraper StatisticScraper) *GateMetrics {
+func newGateMetrics(scraper StatisticScraper, cacheScraper CacheStatScraper) *GateMetrics {
 	stateMetric := newStateMetrics()
 	stateMetric.register()
 
 	poolMetric := newPoolMetricsCollector(scraper)
 	poolMetric.register()
 
+	cacheMetric := newCacheMetricsCollector(cacheScraper)
+	cacheMetric.register()
+
 	return &GateMetrics{
-		stateMetrics:         *stateMetric,
-		poolMetricsCollector: *poolMetric,
+		stateMetrics:          *stateMetric,
+		poolMetricsCollector:  *poolMetric,
+		cacheMetricsCollector: *cacheMetric,
 	}
 }
 
 func (g *GateMetrics) Unregister() {
 	g.stateMetrics.unregister()
 	prometheus.Unregister(&g.poolMetricsCollector)
+	prometheus.Unregister(&g.cacheMetricsCollector)
 }
 
 func newStateMetrics() *stateMetrics {
@@ -222,6 +243,42 @@ func (m *poolMetricsCollector) updateRequestsDuration(node pool.NodeStatistic) {
 	m.requestDuration.WithLabelValues(node.Address(), methodCreateSession).Set(float64(node.AverageCreateSession().Milliseconds()))
 }
 
+func newCacheMetricsCollector(scraper CacheStatScraper) *cacheMetricsCollector {
+	labels := []string{"cache"}
+
+	return &cacheMetricsCollector{
+		cacheStatScraper: scraper,
+		hits: prometheus.NewDesc(prometheus.BuildFQName(namespace, cacheSubsystem, "hits_total"),
+			"Total number of lookups found in the cache", labels, nil),
+		misses: prometheus.NewDesc(prometheus.BuildFQName(namespace, cacheSubsystem, "misses_total"),
+			"Total number of lookups not found in the cache", labels, nil),
+		evictions: prometheus.NewDesc(prometheus.BuildFQName(namespace, cacheSubsystem, "evictions_total"),
+			"Total number of entries evicted from the cache because it's full or the entry lifetime is over", labels, nil),
+		entries: prometheus.NewDesc(prometheus.BuildFQName(namespace, cacheSubsystem, "entries"),
+			"Current number of entries in the cache", labels, nil),
+	}
+}
+
+func (m *cacheMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for name, stat := range m.cacheStatScraper.CacheStats() {
+		ch <- prometheus.MustNewConstMetric(m.hits, prometheus.CounterValue, float64(stat.Hits), name)
+		ch <- prometheus.MustNewConstMetric(m.misses, prometheus.CounterValue, float64(stat.Misses), name)
+		ch <- prometheus.MustNewConstMetric(m.evictions, prometheus.CounterValue, float64(stat.Evictions), name)
+		ch <- prometheus.MustNewConstMetric(m.entries, prometheus.GaugeValue, float64(stat.Entries), name)
+	}
+}
+
+func (m *cacheMetricsCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- m.hits
+	descs <- m.misses
+	descs <- m.evictions
+	descs <- m.entries
+}
+
+func (m *cacheMetricsCollector) register() {
+	prometheus.MustRegister(m)
+}
+
 // NewPrometheusService creates a new service for gathering prometheus metrics.
 func NewPrometheusService(v *viper.Viper, log *zap.Logger) *Service {
 	if log == nil {
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// packingParams returns parameters of small objects packing of the bucket from the config.
+func packingParams(v *viper.Viper, bktInfo *data.BucketInfo) *layer.PackObjectsParams {
+	return &layer.PackObjectsParams{
+		BktInfo:       bktInfo,
+		MaxObjectSize: v.GetInt64(cfgPackingMaxObjectSize),
+		MaxPackSize:   v.GetInt64(cfgPackingMaxPackSize),
+		MinObjects:    v.GetInt(cfgPackingMinObjects),
+		CopiesNumber:  v.GetUint32(cfgSetCopiesNumber),
+	}
+}
+
+// runPacking periodically packs small objects of the configured buckets until the context is done.
+func (a *App) runPacking(ctx context.Context) {
+	interval := a.cfg.GetDuration(cfgPackingInterval)
+	if interval <= 0 {
+		interval = defaultPackingInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.packBuckets(ctx)
+		}
+	}
+}
+
+func (a *App) packBuckets(ctx context.Context) {
+	ctx, err := a.backgroundContext(ctx)
+	if err != nil {
+		a.log.Error("couldn't get credentials to pack objects", zap.Error(err))
+		return
+	}
+
+	for _, bktName := range a.cfg.GetStringSlice(cfgPackingBuckets) {
+		bktInfo, err := a.obj.GetBucketInfo(ctx, bktName)
+		if err != nil {
+			a.log.Error("couldn't get bucket info to pack objects", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		result, err := a.obj.PackObjects(ctx, packingParams(a.cfg, bktInfo))
+		if err != nil {
+			a.log.Error("couldn't pack objects", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		a.log.Info("objects packed", zap.String("bucket", bktName),
+			zap.Int("packed", result.Packed),
+			zap.Int("created", result.Created),
+			zap.Int("removed", result.Removed))
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// preflightCheck is a check of the gateway run on startup before accepting requests.
+type preflightCheck struct {
+	name  string
+	check func(context.Context) error
+}
+
+// runPreflightChecks runs all checks and exits if any of them fails, so the gateway with invalid configuration
+// or without access to the storage doesn't accept requests which fail with internal errors.
+func runPreflightChecks(ctx context.Context, l *zap.Logger, v *viper.Viper, checks []preflightCheck) {
+	if !v.GetBool(cfgPreflightEnabled) {
+		return
+	}
+
+	timeout := v.GetDuration(cfgPreflightTimeout)
+	if timeout <= 0 {
+		timeout = defaultPreflightTimeout
+	}
+
+	var failed []string
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := c.check(checkCtx)
+		cancel()
+		if err != nil {
+			l.Error("preflight check failed", zap.String("check", c.name), zap.Error(err))
+			failed = append(failed, c.name)
+			continue
+		}
+		l.Debug("preflight check passed", zap.String("check", c.name))
+	}
+
+	if len(failed) != 0 {
+		l.Fatal("gateway isn't ready to accept requests", zap.Strings("failed checks", failed))
+	}
+}
+
+// configPreflightChecks returns checks of the configuration which don't need the storage.
+func configPreflightChecks(v *viper.Viper) []preflightCheck {
+	return []preflightCheck{
+		{name: "placement policies", check: func(context.Context) error {
+			return checkPlacementPolicies(v)
+		}},
+	}
+}
+
+// preflightChecks returns checks of the initialized gateway.
+func (a *App) preflightChecks() []preflightCheck {
+	return []preflightCheck{
+		{name: "wallet", check: func(context.Context) error {
+			return checkSignature(a.key)
+		}},
+		{name: "storage", check: func(ctx context.Context) error {
+			return checkStorage(ctx, a.pool)
+		}},
+		{name: "resolver", check: a.checkResolver},
+	}
+}
+
+// checkPlacementPolicies parses the default policy and policies of all regions. Unlike the policies update,
+// it reports every invalid policy.
+func checkPlacementPolicies(v *viper.Viper) error {
+	var errs []string
+
+	var pp netmap.PlacementPolicy
+	defaultPolicy := getDefaultPolicyValue(v)
+	if err := pp.DecodeString(defaultPolicy); err != nil {
+		errs = append(errs, fmt.Sprintf("default policy '%s': %s", defaultPolicy, err))
+	}
+
+	regionPolicyMap, err := fetchRegionMap(v)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("region map: %s", err))
+	}
+
+	for region, policy := range regionPolicyMap {
+		if err = pp.DecodeString(policy); err == nil {
+			continue
+		}
+		if err = pp.UnmarshalJSON([]byte(policy)); err != nil {
+			errs = append(errs, fmt.Sprintf("region '%s' policy '%s': %s", region, policy, err))
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("invalid placement policies: %v", errs)
+	}
+	return nil
+}
+
+// checkSignature checks that the wallet key is able to sign the requests.
+func checkSignature(key *keys.PrivateKey) error {
+	msg := make([]byte, 32)
+	if _, err := rand.Read(msg); err != nil {
+		return fmt.Errorf("generate message: %w", err)
+	}
+
+	hash := sha256.Sum256(msg)
+	if !key.PublicKey().Verify(key.Sign(msg), hash[:]) {
+		return errors.New("signature of the wallet key isn't verified")
+	}
+	return nil
+}
+
+// checkStorage checks that at least one NeoFS endpoint is healthy.
+func checkStorage(ctx context.Context, p *pool.Pool) error {
+	if _, err := p.NetworkInfo(ctx); err != nil {
+		return fmt.Errorf("no healthy NeoFS endpoint: %w", err)
+	}
+	return nil
+}
+
+// checkResolver checks that the configured resolvers are able to resolve buckets. The DNS resolver
+// needs the system DNS network parameter, the NNS resolver is dialed on creation. If the preflight
+// bucket is configured, it must be resolved.
+func (a *App) checkResolver(ctx context.Context) error {
+	for _, name := range a.cfg.GetStringSlice(cfgResolveOrder) {
+		if name != resolver.DNSResolver {
+			continue
+		}
+		if _, err := neofs.NewResolverNeoFS(a.pool).SystemDNS(ctx); err != nil {
+			return fmt.Errorf("%s resolver: %w", resolver.DNSResolver, err)
+		}
+	}
+
+	bktName := a.cfg.GetString(cfgPreflightResolveBucket)
+	if bktName == "" {
+		return nil
+	}
+
+	if _, err := a.bucketResolver.Resolve(ctx, bktName); err != nil {
+		return fmt.Errorf("resolve bucket '%s': %w", bktName, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// initReplicaNetworks connects to the NeoFS networks of secondary containers of synchronous replication.
+func (a *App) initReplicaNetworks(ctx context.Context) map[string]layer.NeoFS {
+	networks := make(map[string]layer.NeoFS)
+	for i, name := range replicaNetworkNames(a.cfg) {
+		peers := fetchPeers(a.log, a.cfg, cfgSyncReplicationNetworks+"."+strconv.Itoa(i)+".peers")
+		if len(peers) == 0 {
+			a.log.Fatal("no peers of sync replication network", zap.String("network", name))
+		}
+
+		networks[name] = neofs.NewNeoFS(newPool(ctx, a.log, a.cfg, a.key, peers))
+		a.log.Info("sync replication network is connected", zap.String("network", name))
+	}
+
+	return networks
+}
+
+func replicaNetworkNames(v *viper.Viper) []string {
+	var names []string
+	for i := 0; ; i++ {
+		name := v.GetString(cfgSyncReplicationNetworks + "." + strconv.Itoa(i) + ".name")
+		if name == "" {
+			return names
+		}
+		names = append(names, name)
+	}
+}
+
+// isReplicaNetwork checks if the secondary container can be placed in the network,
+// empty name means the network of the bucket.
+func isReplicaNetwork(v *viper.Viper, name string) bool {
+	if name == "" {
+		return true
+	}
+	for _, network := range replicaNetworkNames(v) {
+		if network == name {
+			return true
+		}
+	}
+	return false
+}
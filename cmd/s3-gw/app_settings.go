@@ -9,9 +9,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/features"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/api/scanning"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/config"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
-	"github.com/nspcc-dev/neofs-sdk-go/pool"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -29,6 +34,36 @@ const (
 
 	defaultMaxClientsCount    = 100
 	defaultMaxClientsDeadline = time.Second * 30
+
+	defaultPackingInterval      = time.Hour
+	defaultPackingMaxObjectSize = 128 << 10
+	defaultPackingMaxPackSize   = 64 << 20
+	defaultPackingMinObjects    = 100
+
+	defaultObjectIndexReconcileInterval = time.Hour
+
+	defaultLifecycleInterval = time.Hour
+
+	defaultArchiveRestoreInterval = 5 * time.Minute
+
+	defaultInventoryInterval = time.Hour
+
+	defaultTrashPurgeInterval = time.Hour
+
+	defaultDegradationProbeInterval    = 5 * time.Second
+	defaultDegradationProbeTimeout     = 3 * time.Second
+	defaultDegradationFailureThreshold = 3
+
+	defaultKMSTimeout = 10 * time.Second
+
+	defaultPreflightTimeout = 10 * time.Second
+
+	defaultPartRetries         = 2
+	defaultPartRetryBufferSize = 16 << 20
+
+	defaultDeleteObjectsConcurrency = layer.DefaultDeleteObjectsConcurrency
+
+	defaultCompleteKeepAlive = 10 * time.Second
 )
 
 const ( // Settings.
@@ -78,10 +113,13 @@ const ( // Settings.
 	cfgNATSTLSCertFile        = "nats.cert_file"
 	cfgNATSAuthPrivateKeyFile = "nats.key_file"
 	cfgNATSRootCAFiles        = "nats.root_ca"
+	cfgNATSQueueSize          = "nats.queue_size"
+	cfgNATSParkTimeout        = "nats.park_timeout"
 
 	// Policy.
 	cfgPolicyDefault       = "placement_policy.default"
 	cfgPolicyRegionMapFile = "placement_policy.region_mapping"
+	cfgPolicyLocations     = "placement_policy.locations"
 
 	// CORS.
 	cfgDefaultMaxAge = "cors.default_max_age"
@@ -96,7 +134,29 @@ const ( // Settings.
 	cfgPProfEnabled      = "pprof.enabled"
 	cfgPProfAddress      = "pprof.address"
 
-	cfgListenDomains = "listen_domains"
+	// Admin API.
+	cfgAdminEnabled   = "admin.enabled"
+	cfgAdminAddress   = "admin.address"
+	cfgAdminOperators = "admin.operators"
+
+	cfgStatusEnabled = "status.enabled"
+	cfgStatusAddress = "status.address"
+
+	// Control gRPC API.
+	cfgControlEnabled     = "control.enabled"
+	cfgControlAddress     = "control.address"
+	cfgControlTLSCertFile = "control.tls.cert_file"
+	cfgControlTLSKeyFile  = "control.tls.key_file"
+	cfgControlTLSCAFile   = "control.tls.ca_file"
+	cfgControlRevokedKeys = "control.revoked_access_keys"
+
+	// Website endpoint of buckets.
+	cfgWebsiteEnabled = "website.enabled"
+	cfgWebsiteAddress = "website.address"
+	cfgWebsiteDomains = "website.domains"
+
+	cfgListenDomains     = "listen_domains"
+	cfgAccelerateDomains = "accelerate_domains"
 
 	// Peers.
 	cfgPeers = "peers"
@@ -113,17 +173,141 @@ const ( // Settings.
 	cfgApplicationBuildTime = "app.build_time"
 
 	// Command line args.
-	cmdHelp    = "help"
-	cmdVersion = "version"
-	cmdConfig  = "config"
-	cmdPProf   = "pprof"
-	cmdMetrics = "metrics"
+	cmdHelp      = "help"
+	cmdVersion   = "version"
+	cmdConfig    = "config"
+	cmdConfigDir = "config-dir"
+	cmdPProf     = "pprof"
+	cmdMetrics   = "metrics"
+
+	// cfgConfigDir is a directory with YAML configuration fragments merged over the config file.
+	cfgConfigDir = "config_dir"
 
 	cmdListenAddress = "listen_address"
 
 	// Configuration of parameters of requests to NeoFS.
 	// Number of the object copies to consider PUT to NeoFS successful.
 	cfgSetCopiesNumber = "neofs.set_copies_number"
+	// Number of extra attempts to store a multipart upload part.
+	cfgPartRetries = "neofs.part_retries"
+	// Max size of the part buffered in memory to be retried.
+	cfgPartRetryBufferSize = "neofs.part_retry_buffer_size"
+	// Number of objects of DeleteObjects deleted concurrently.
+	cfgDeleteObjectsConcurrency = "neofs.delete_objects_concurrency"
+	// Numbers of the object copies of storage classes.
+	cfgStorageClasses = "neofs.storage_classes"
+	// Prefix of names of bucket system objects.
+	cfgSystemObjectPrefix = "neofs.system_object_prefix"
+
+	// Interval of whitespace written to the response of the long CompleteMultipartUpload.
+	cfgCompleteKeepAlive = "multipart.complete_keep_alive"
+
+	// Compatibility with Hadoop S3A connector.
+	cfgCompatibilityS3A = "compatibility.s3a"
+	// Case of user metadata keys.
+	cfgCompatibilityPreserveMetadataCase = "compatibility.preserve_metadata_case"
+
+	// Veeam Smart Object Storage API.
+	cfgSOSAPIEnabled = "sosapi.enabled"
+
+	// Credentials of background jobs.
+	cfgBackgroundAccessKeyID = "background.access_key_id"
+
+	// Small objects packing.
+	cfgPackingEnabled       = "packing.enabled"
+	cfgPackingInterval      = "packing.interval"
+	cfgPackingBuckets       = "packing.buckets"
+	cfgPackingMaxObjectSize = "packing.max_object_size"
+	cfgPackingMaxPackSize   = "packing.max_pack_size"
+	cfgPackingMinObjects    = "packing.min_objects"
+
+	// Object index.
+	cfgObjectIndexBackend           = "object_index.backend"
+	cfgObjectIndexPath              = "object_index.path"
+	cfgObjectIndexReconcileInterval = "object_index.reconcile_interval"
+	cfgObjectIndexBuckets           = "object_index.buckets"
+
+	// Lifecycle expiration.
+	cfgLifecycleEnabled  = "lifecycle.enabled"
+	cfgLifecycleInterval = "lifecycle.interval"
+	cfgLifecycleBuckets  = "lifecycle.buckets"
+
+	// Archive storage classes of lifecycle transitions.
+	cfgArchiveStorageClasses  = "archive.storage_classes"
+	cfgArchiveRestoreInterval = "archive.restore_interval"
+
+	// Inventory reports.
+	cfgInventoryEnabled  = "inventory.enabled"
+	cfgInventoryInterval = "inventory.interval"
+	cfgInventoryBuckets  = "inventory.buckets"
+
+	// Periodic purge of expired objects from bucket trash.
+	cfgTrashPurgeEnabled  = "trash.enabled"
+	cfgTrashPurgeInterval = "trash.interval"
+	cfgTrashPurgeBuckets  = "trash.buckets"
+
+	// Degraded mode while the storage is unavailable.
+	cfgDegradationEnabled          = "degradation.enabled"
+	cfgDegradationProbeInterval    = "degradation.probe_interval"
+	cfgDegradationProbeTimeout     = "degradation.probe_timeout"
+	cfgDegradationFailureThreshold = "degradation.failure_threshold"
+	cfgDegradationReadTimeout      = "degradation.read_timeout"
+
+	// Startup checks.
+	cfgPreflightEnabled       = "preflight.enabled"
+	cfgPreflightTimeout       = "preflight.timeout"
+	cfgPreflightResolveBucket = "preflight.resolve_bucket"
+
+	// Feature flags of experimental behaviors.
+	cfgFeatures = "features"
+
+	// Operations disabled in the deployment.
+	cfgOperationsDisabled = "operations.disabled"
+	cfgOperationsResponse = "operations.response"
+
+	// Sampled capture of full requests and responses.
+	cfgWireLogEnabled     = "wire_log.enabled"
+	cfgWireLogPath        = "wire_log.path"
+	cfgWireLogSampleRate  = "wire_log.sample_rate"
+	cfgWireLogAccessKeys  = "wire_log.access_keys"
+	cfgWireLogMaxBodySize = "wire_log.max_body_size"
+
+	// Scanning of new objects for malware.
+	cfgScanningEnabled            = "scanning.enabled"
+	cfgScanningURL                = "scanning.url"
+	cfgScanningTimeout            = "scanning.timeout"
+	cfgScanningWorkers            = "scanning.workers"
+	cfgScanningQueueSize          = "scanning.queue_size"
+	cfgScanningMaxSize            = "scanning.max_size"
+	cfgScanningQuarantineTagKey   = "scanning.quarantine_tag.key"
+	cfgScanningQuarantineTagValue = "scanning.quarantine_tag.value"
+
+	// MFA devices for buckets with MFA delete.
+	cfgMFADevices = "mfa.devices"
+
+	// Server-side encryption with keys managed by the gateway.
+	cfgEncryptionMasterKey = "encryption.master_key"
+
+	// Server-side encryption with keys managed by the external KMS.
+	cfgKMSType            = "encryption.kms.type"
+	cfgKMSEndpoint        = "encryption.kms.endpoint"
+	cfgKMSKeyID           = "encryption.kms.key_id"
+	cfgKMSTimeout         = "encryption.kms.timeout"
+	cfgKMSVaultToken      = "encryption.kms.vault_token"
+	cfgKMSVaultMount      = "encryption.kms.vault_mount"
+	cfgKMSRegion          = "encryption.kms.region"
+	cfgKMSAccessKeyID     = "encryption.kms.access_key_id"
+	cfgKMSSecretAccessKey = "encryption.kms.secret_access_key"
+
+	// NeoFS networks of secondary containers of synchronous replication.
+	cfgSyncReplicationNetworks = "sync_replication.networks"
+
+	// HTML listings of public buckets for browsers.
+	cfgHTMLListingEnabled  = "html_listing.enabled"
+	cfgHTMLListingTemplate = "html_listing.template"
+
+	// Reject presigned URLs without the nonce issued by the gateway.
+	cfgPresignRequireNonce = "presign.require_nonce"
 
 	// List of allowed AccessKeyID prefixes.
 	cfgAllowedAccessKeyIDPrefixes = "allowed_access_key_id_prefixes"
@@ -141,10 +325,34 @@ var ignore = map[string]struct{}{
 	cmdVersion: {},
 }
 
-func fetchPeers(l *zap.Logger, v *viper.Viper) []pool.NodeParam {
-	var nodes []pool.NodeParam
+// peerInfo is a NeoFS node from the config.
+type peerInfo struct {
+	address  string
+	priority int
+	weight   float64
+}
+
+// fetchFeatures returns values of feature flags set in the config, flags set by environment
+// variables are looked up by the names of known flags.
+func fetchFeatures(v *viper.Viper) map[string]bool {
+	values := make(map[string]bool)
+	for name := range v.GetStringMap(cfgFeatures) {
+		values[name] = v.GetBool(cfgFeatures + "." + name)
+	}
+
+	for _, flag := range features.Flags() {
+		if key := cfgFeatures + "." + flag.Name; v.IsSet(key) {
+			values[flag.Name] = v.GetBool(key)
+		}
+	}
+
+	return values
+}
+
+func fetchPeers(l *zap.Logger, v *viper.Viper, section string) []peerInfo {
+	var peers []peerInfo
 	for i := 0; ; i++ {
-		key := cfgPeers + "." + strconv.Itoa(i) + "."
+		key := section + "." + strconv.Itoa(i) + "."
 		address := v.GetString(key + "address")
 		weight := v.GetFloat64(key + "weight")
 		priority := v.GetInt(key + "priority")
@@ -160,14 +368,14 @@ func fetchPeers(l *zap.Logger, v *viper.Viper) []pool.NodeParam {
 			priority = 1
 		}
 
-		nodes = append(nodes, pool.NewNodeParam(priority, address, weight))
+		peers = append(peers, peerInfo{address: address, priority: priority, weight: weight})
 
 		l.Info("added connection peer",
 			zap.String("address", address),
 			zap.Float64("weight", weight))
 	}
 
-	return nodes
+	return peers
 }
 
 func fetchServers(v *viper.Viper) []ServerInfo {
@@ -215,6 +423,7 @@ func newSettings() *viper.Viper {
 	flags.StringP(cmdWallet, "w", "", `path to the wallet`)
 	flags.String(cmdAddress, "", `address of wallet account`)
 	flags.String(cmdConfig, "", "config path")
+	flags.String(cmdConfigDir, "", "path to the directory with config fragments merged in the order of file names")
 
 	flags.Duration(cfgHealthcheckTimeout, defaultHealthcheckTimeout, "set timeout to check node health during rebalance")
 	flags.Duration(cfgConnectTimeout, defaultConnectTimeout, "set timeout to connect to NeoFS nodes")
@@ -245,6 +454,58 @@ func newSettings() *viper.Viper {
 
 	v.SetDefault(cfgPProfAddress, "localhost:8085")
 	v.SetDefault(cfgPrometheusAddress, "localhost:8086")
+	v.SetDefault(cfgAdminAddress, "localhost:8087")
+	v.SetDefault(cfgStatusAddress, "localhost:8088")
+	v.SetDefault(cfgWebsiteAddress, "localhost:8089")
+	v.SetDefault(cfgControlAddress, "localhost:8090")
+
+	// packing:
+	v.SetDefault(cfgPackingInterval, defaultPackingInterval)
+	v.SetDefault(cfgPackingMaxObjectSize, defaultPackingMaxObjectSize)
+	v.SetDefault(cfgPackingMaxPackSize, defaultPackingMaxPackSize)
+	v.SetDefault(cfgPackingMinObjects, defaultPackingMinObjects)
+
+	// object index:
+	v.SetDefault(cfgObjectIndexReconcileInterval, defaultObjectIndexReconcileInterval)
+
+	// lifecycle:
+	v.SetDefault(cfgLifecycleInterval, defaultLifecycleInterval)
+
+	// archive:
+	v.SetDefault(cfgArchiveRestoreInterval, defaultArchiveRestoreInterval)
+
+	// inventory:
+	v.SetDefault(cfgInventoryInterval, defaultInventoryInterval)
+
+	// trash:
+	v.SetDefault(cfgTrashPurgeInterval, defaultTrashPurgeInterval)
+
+	// scanning:
+	v.SetDefault(cfgScanningTimeout, scanning.DefaultTimeout)
+	v.SetDefault(cfgScanningWorkers, scanning.DefaultWorkers)
+	v.SetDefault(cfgScanningQueueSize, scanning.DefaultQueueSize)
+	v.SetDefault(cfgScanningQuarantineTagKey, scanning.DefaultQuarantineTagKey)
+	v.SetDefault(cfgScanningQuarantineTagValue, scanning.DefaultQuarantineTagValue)
+
+	// degradation:
+	v.SetDefault(cfgDegradationProbeInterval, defaultDegradationProbeInterval)
+	v.SetDefault(cfgDegradationProbeTimeout, defaultDegradationProbeTimeout)
+	v.SetDefault(cfgDegradationFailureThreshold, defaultDegradationFailureThreshold)
+
+	// kms:
+	v.SetDefault(cfgKMSTimeout, defaultKMSTimeout)
+
+	// preflight:
+	v.SetDefault(cfgPreflightEnabled, true)
+	v.SetDefault(cfgPreflightTimeout, defaultPreflightTimeout)
+
+	// neofs:
+	v.SetDefault(cfgPartRetries, defaultPartRetries)
+	v.SetDefault(cfgPartRetryBufferSize, defaultPartRetryBufferSize)
+	v.SetDefault(cfgDeleteObjectsConcurrency, defaultDeleteObjectsConcurrency)
+
+	// multipart:
+	v.SetDefault(cfgCompleteKeepAlive, defaultCompleteKeepAlive)
 
 	// Bind flags
 	if err := bindFlags(v, flags); err != nil {
@@ -313,10 +574,8 @@ func newSettings() *viper.Viper {
 		os.Exit(0)
 	}
 
-	if v.IsSet(cmdConfig) {
-		if err := readConfig(v); err != nil {
-			panic(err)
-		}
+	if err := readConfig(v); err != nil {
+		panic(err)
 	}
 
 	return v
@@ -332,6 +591,9 @@ func bindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
 	if err := v.BindPFlag(cmdConfig, flags.Lookup(cmdConfig)); err != nil {
 		return err
 	}
+	if err := v.BindPFlag(cfgConfigDir, flags.Lookup(cmdConfigDir)); err != nil {
+		return err
+	}
 	if err := v.BindPFlag(cfgWalletPath, flags.Lookup(cmdWallet)); err != nil {
 		return err
 	}
@@ -370,17 +632,28 @@ func bindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
 	return nil
 }
 
+// secretKeys are parameters which can be set from files by environment variables with _FILE suffix
+// even if they are absent in the configuration.
+var secretKeys = []string{
+	cfgWalletPassphrase,
+	cfgEncryptionMasterKey,
+	cfgKMSVaultToken,
+	cfgKMSAccessKeyID,
+	cfgKMSSecretAccessKey,
+}
+
+// readConfig reads the config file and the fragments from the config directory with expanded
+// references to environment variables, then it reads values of parameters from secret files.
 func readConfig(v *viper.Viper) error {
-	cfgFileName := v.GetString(cmdConfig)
-	cfgFile, err := os.Open(cfgFileName)
+	fragments, err := config.Fragments(v.GetString(cmdConfig), v.GetString(cfgConfigDir))
 	if err != nil {
 		return err
 	}
-	if err = v.ReadConfig(cfgFile); err != nil {
+	if err = config.Read(v, fragments); err != nil {
 		return err
 	}
 
-	return cfgFile.Close()
+	return config.ReadSecretFiles(v, envPrefix, append(v.AllKeys(), secretKeys...))
 }
 
 // newLogger constructs a Logger instance for the current application.
@@ -420,6 +693,74 @@ func newLogger(v *viper.Viper) *Logger {
 	}
 }
 
+// newWireLog creates the wire log writing JSON entries to the file of the config.
+func newWireLog(v *viper.Viper) (*api.WireLog, error) {
+	path := v.GetString(cfgWireLogPath)
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty wire log path")
+	}
+
+	c := zap.NewProductionConfig()
+	c.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	c.Sampling = nil
+	c.OutputPaths = []string{path}
+	c.ErrorOutputPaths = []string{"stderr"}
+	c.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	c.DisableCaller = true
+
+	l, err := c.Build()
+	if err != nil {
+		return nil, fmt.Errorf("build wire logger: %w", err)
+	}
+
+	return api.NewWireLog(l, getWireLogConfig(v))
+}
+
+// newScanner creates the scanner of new objects sending their payloads to the HTTP callback of the config.
+func newScanner(v *viper.Viper, l *zap.Logger, obj layer.Client) (*scanning.Scanner, error) {
+	url := v.GetString(cfgScanningURL)
+	if len(url) == 0 {
+		return nil, fmt.Errorf("empty scanning url")
+	}
+
+	timeout := v.GetDuration(cfgScanningTimeout)
+	return scanning.NewScanner(l, obj, scanning.NewHTTPHook(url, timeout), scanning.Config{
+		Workers:            v.GetInt(cfgScanningWorkers),
+		QueueSize:          v.GetInt(cfgScanningQueueSize),
+		Timeout:            timeout,
+		MaxSize:            v.GetInt64(cfgScanningMaxSize),
+		QuarantineTagKey:   v.GetString(cfgScanningQuarantineTagKey),
+		QuarantineTagValue: v.GetString(cfgScanningQuarantineTagValue),
+	}), nil
+}
+
+// fetchMFADevices returns MFA devices with their secrets and owners.
+func fetchMFADevices(v *viper.Viper) []auth.MFADevice {
+	var devices []auth.MFADevice
+	for i := 0; ; i++ {
+		key := cfgMFADevices + "." + strconv.Itoa(i) + "."
+		serial := v.GetString(key + "serial")
+		if serial == "" {
+			break
+		}
+		devices = append(devices, auth.MFADevice{
+			Serial: serial,
+			Secret: v.GetString(key + "secret"),
+			Owner:  v.GetString(key + "owner"),
+		})
+	}
+
+	return devices
+}
+
+func getWireLogConfig(v *viper.Viper) api.WireLogConfig {
+	return api.WireLogConfig{
+		SampleRate:  v.GetFloat64(cfgWireLogSampleRate),
+		AccessKeys:  v.GetStringSlice(cfgWireLogAccessKeys),
+		MaxBodySize: v.GetInt64(cfgWireLogMaxBodySize),
+	}
+}
+
 func getLogLevel(v *viper.Viper) (zapcore.Level, error) {
 	var lvl zapcore.Level
 	lvlStr := v.GetString(cfgLoggerLevel)
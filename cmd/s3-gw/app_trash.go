@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// runTrashPurge periodically removes expired objects from the trash of the configured buckets
+// until the context is done. It complements purges scheduled on deletions, which are skipped
+// when the purge queue is full or no objects are deleted in the bucket.
+func (a *App) runTrashPurge(ctx context.Context) {
+	interval := a.cfg.GetDuration(cfgTrashPurgeInterval)
+	if interval <= 0 {
+		interval = defaultTrashPurgeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.purgeTrash(ctx)
+		}
+	}
+}
+
+func (a *App) purgeTrash(ctx context.Context) {
+	ctx, err := a.backgroundContext(ctx)
+	if err != nil {
+		a.log.Error("couldn't get credentials to purge trash", zap.Error(err))
+		return
+	}
+
+	for _, bktName := range a.cfg.GetStringSlice(cfgTrashPurgeBuckets) {
+		bktInfo, err := a.obj.GetBucketInfo(ctx, bktName)
+		if err != nil {
+			a.log.Error("couldn't get bucket info to purge trash", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		purged, err := a.obj.PurgeExpiredTrash(ctx, bktInfo)
+		if err != nil {
+			a.log.Error("couldn't purge expired trash", zap.String("bucket", bktName), zap.Error(err))
+			continue
+		}
+
+		if purged != 0 {
+			a.log.Info("expired trash purged", zap.String("bucket", bktName), zap.Int("purged", purged))
+		}
+	}
+}
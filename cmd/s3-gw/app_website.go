@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewWebsiteService creates a new service serving websites of buckets configured for website hosting.
+func NewWebsiteService(v *viper.Viper, l *zap.Logger, m api.MaxClients, h api.Handler) *Service {
+	log := l.With(zap.String("service", "Website"))
+
+	domains := v.GetStringSlice(cfgWebsiteDomains)
+	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
+	api.AttachWebsite(router, domains, m, h, log)
+
+	return &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgWebsiteAddress),
+			Handler: router,
+		},
+		enabled:     v.GetBool(cfgWebsiteEnabled),
+		serviceType: "Website",
+		log:         log,
+	}
+}
@@ -0,0 +1,100 @@
+package control
+
+import (
+	"context"
+	"sort"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// Server implements the control service of the gateway.
+	Server struct {
+		UnimplementedControlServiceServer
+
+		state   *api.ControlState
+		storage *api.StorageState
+		caches  CacheFlusher
+	}
+
+	// CacheFlusher deletes all entries from the gateway caches.
+	CacheFlusher interface {
+		FlushCache()
+	}
+)
+
+// NewServer creates the control service server managing the state. Nil storage state means
+// that degraded mode is disabled.
+func NewServer(state *api.ControlState, storage *api.StorageState, caches CacheFlusher) *Server {
+	return &Server{
+		state:   state,
+		storage: storage,
+		caches:  caches,
+	}
+}
+
+// HealthCheck returns maintenance mode and availability of the storage.
+func (s *Server) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
+	res := new(HealthCheckResponse)
+	res.Maintenance, _ = s.state.Maintenance()
+	if s.storage != nil {
+		res.Degraded, _ = s.storage.Degraded()
+	}
+
+	return res, nil
+}
+
+// SetMaintenanceMode switches maintenance mode of the gateway.
+func (s *Server) SetMaintenanceMode(_ context.Context, req *SetMaintenanceModeRequest) (*SetMaintenanceModeResponse, error) {
+	s.state.SetMaintenance(req.GetEnabled())
+	return new(SetMaintenanceModeResponse), nil
+}
+
+// FlushCache deletes all entries from the gateway caches.
+func (s *Server) FlushCache(context.Context, *FlushCacheRequest) (*FlushCacheResponse, error) {
+	s.caches.FlushCache()
+	return new(FlushCacheResponse), nil
+}
+
+// SetCredentialsRevoked revokes the credentials or restores them.
+func (s *Server) SetCredentialsRevoked(_ context.Context, req *SetCredentialsRevokedRequest) (*SetCredentialsRevokedResponse, error) {
+	if req.GetAccessKeyId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "access key id is empty")
+	}
+
+	s.state.SetRevoked(req.GetAccessKeyId(), req.GetRevoked())
+	return new(SetCredentialsRevokedResponse), nil
+}
+
+// ListRevokedCredentials returns access key ids of the revoked credentials.
+func (s *Server) ListRevokedCredentials(context.Context, *ListRevokedCredentialsRequest) (*ListRevokedCredentialsResponse, error) {
+	return &ListRevokedCredentialsResponse{AccessKeyIds: s.state.Revoked()}, nil
+}
+
+// SetQuota sets the request rate limit of the access key, zero rate removes the limit.
+func (s *Server) SetQuota(_ context.Context, req *SetQuotaRequest) (*SetQuotaResponse, error) {
+	quota := req.GetQuota()
+	if quota.GetAccessKeyId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "access key id is empty")
+	}
+
+	s.state.SetQuota(quota.GetAccessKeyId(), quota.GetRequestsPerSecond())
+	return new(SetQuotaResponse), nil
+}
+
+// ListQuotas returns request rate limits of access keys sorted by access key id.
+func (s *Server) ListQuotas(context.Context, *ListQuotasRequest) (*ListQuotasResponse, error) {
+	quotas := s.state.Quotas()
+
+	res := &ListQuotasResponse{Quotas: make([]*Quota, 0, len(quotas))}
+	for accessKeyID, rps := range quotas {
+		res.Quotas = append(res.Quotas, &Quota{AccessKeyId: accessKeyID, RequestsPerSecond: rps})
+	}
+	sort.Slice(res.Quotas, func(i, j int) bool {
+		return res.Quotas[i].AccessKeyId < res.Quotas[j].AccessKeyId
+	})
+
+	return res, nil
+}
@@ -0,0 +1,112 @@
+package control
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type cacheFlusherMock struct {
+	flushed int
+}
+
+func (c *cacheFlusherMock) FlushCache() {
+	c.flushed++
+}
+
+func TestControlServer(t *testing.T) {
+	ctx := context.Background()
+	state := api.NewControlState()
+	caches := new(cacheFlusherMock)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	RegisterControlServiceServer(srv, NewServer(state, nil, caches))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	cli := NewControlServiceClient(conn)
+
+	h := state.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(accessKeyID string) int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), api.AccessKeyID, accessKeyID))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("maintenance", func(t *testing.T) {
+		_, err := cli.SetMaintenanceMode(ctx, &SetMaintenanceModeRequest{Enabled: true})
+		require.NoError(t, err)
+		res, err := cli.HealthCheck(ctx, new(HealthCheckRequest))
+		require.NoError(t, err)
+		require.True(t, res.GetMaintenance())
+		require.False(t, res.GetDegraded())
+		require.Equal(t, http.StatusServiceUnavailable, serve(""))
+
+		_, err = cli.SetMaintenanceMode(ctx, &SetMaintenanceModeRequest{Enabled: false})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, serve(""))
+	})
+
+	t.Run("revoked credentials", func(t *testing.T) {
+		_, err := cli.SetCredentialsRevoked(ctx, &SetCredentialsRevokedRequest{AccessKeyId: "key", Revoked: true})
+		require.NoError(t, err)
+		res, err := cli.ListRevokedCredentials(ctx, new(ListRevokedCredentialsRequest))
+		require.NoError(t, err)
+		require.Equal(t, []string{"key"}, res.GetAccessKeyIds())
+		require.Equal(t, http.StatusForbidden, serve("key"))
+		require.Equal(t, http.StatusOK, serve("other"))
+		require.True(t, state.IsRevoked("key"))
+
+		_, err = cli.SetCredentialsRevoked(ctx, &SetCredentialsRevokedRequest{AccessKeyId: "key", Revoked: false})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, serve("key"))
+		require.False(t, state.IsRevoked("key"))
+
+		_, err = cli.SetCredentialsRevoked(ctx, &SetCredentialsRevokedRequest{Revoked: true})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("quotas", func(t *testing.T) {
+		_, err := cli.SetQuota(ctx, &SetQuotaRequest{Quota: &Quota{AccessKeyId: "key", RequestsPerSecond: 1}})
+		require.NoError(t, err)
+		res, err := cli.ListQuotas(ctx, new(ListQuotasRequest))
+		require.NoError(t, err)
+		require.Len(t, res.GetQuotas(), 1)
+		require.Equal(t, "key", res.GetQuotas()[0].GetAccessKeyId())
+		require.EqualValues(t, 1, res.GetQuotas()[0].GetRequestsPerSecond())
+
+		require.Equal(t, http.StatusOK, serve("key"))
+		require.Equal(t, http.StatusServiceUnavailable, serve("key"))
+		require.Equal(t, http.StatusOK, serve("other"))
+
+		_, err = cli.SetQuota(ctx, &SetQuotaRequest{Quota: &Quota{AccessKeyId: "key"}})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, serve("key"))
+	})
+
+	t.Run("flush cache", func(t *testing.T) {
+		_, err := cli.FlushCache(ctx, new(FlushCacheRequest))
+		require.NoError(t, err)
+		require.Equal(t, 1, caches.flushed)
+	})
+}
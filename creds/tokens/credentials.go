@@ -20,6 +20,8 @@ type (
 	Credentials interface {
 		GetBox(context.Context, oid.Address) (*accessbox.Box, error)
 		Put(context.Context, cid.ID, user.ID, *accessbox.AccessBox, uint64, ...*keys.PublicKey) (oid.Address, error)
+		// CacheStats returns statistics of the access box cache.
+		CacheStats() cache.Stats
 	}
 
 	cred struct {
@@ -79,6 +81,10 @@ func New(neoFS NeoFS, key *keys.PrivateKey, config *cache.Config) Credentials {
 	return &cred{neoFS: neoFS, key: key, cache: cache.NewAccessBoxCache(config)}
 }
 
+func (c *cred) CacheStats() cache.Stats {
+	return c.cache.Stats()
+}
+
 func (c *cred) GetBox(ctx context.Context, addr oid.Address) (*accessbox.Box, error) {
 	cachedBox := c.cache.Get(addr)
 	if cachedBox != nil {
@@ -0,0 +1,115 @@
+// Package config reads the gateway configuration in the form convenient for container orchestration systems:
+// split into fragments, with references to environment variables and secrets mounted as files.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// fileEnvSuffix is a suffix of environment variables with paths to files containing parameter values.
+const fileEnvSuffix = "_FILE"
+
+// envReference matches ${NAME} and ${NAME:-default} references to environment variables.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// ExpandEnv replaces ${NAME} references in the configuration data with values of environment variables.
+// Reference ${NAME:-default} is replaced with the default value if the variable is unset or empty,
+// references to unset variables without the default value are replaced with an empty string.
+func ExpandEnv(data []byte) []byte {
+	return envReference.ReplaceAllFunc(data, func(ref []byte) []byte {
+		match := envReference.FindSubmatch(ref)
+		if val := os.Getenv(string(match[1])); val != "" || len(match[2]) == 0 {
+			return []byte(val)
+		}
+		return match[3]
+	})
+}
+
+// Fragments returns the configuration file followed by YAML files of the directory sorted by name.
+// Empty path or directory is skipped.
+func Fragments(file, dir string) ([]string, error) {
+	var res []string
+	if file != "" {
+		res = append(res, file)
+	}
+	if dir == "" {
+		return res, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read config directory: %w", err)
+	}
+
+	fragments := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml":
+			fragments = append(fragments, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(fragments)
+
+	return append(res, fragments...), nil
+}
+
+// Read reads the configuration fragments with expanded references to environment variables.
+// Fragments are merged in the order: values of the latter fragment override the former ones.
+// Previously read configuration is replaced.
+func Read(v *viper.Viper, fragments []string) error {
+	for i, fragment := range fragments {
+		data, err := os.ReadFile(fragment)
+		if err != nil {
+			return fmt.Errorf("read config '%s': %w", fragment, err)
+		}
+
+		r := bytes.NewReader(ExpandEnv(data))
+		if i == 0 {
+			err = v.ReadConfig(r)
+		} else {
+			err = v.MergeConfig(r)
+		}
+		if err != nil {
+			return fmt.Errorf("parse config '%s': %w", fragment, err)
+		}
+	}
+
+	return nil
+}
+
+// ReadSecretFiles sets parameters to the content of files referenced by environment variables with the
+// parameter name and _FILE suffix, e.g. PREFIX_WALLET_PASSPHRASE_FILE for wallet.passphrase parameter.
+// The trailing newline of the file is trimmed. It's an error to set both the variable and the file one.
+func ReadSecretFiles(v *viper.Viper, envPrefix string, keys []string) error {
+	replacer := strings.NewReplacer(".", "_")
+	for _, key := range keys {
+		env := envPrefix + "_" + strings.ToUpper(replacer.Replace(key))
+
+		path, ok := os.LookupEnv(env + fileEnvSuffix)
+		if !ok {
+			continue
+		}
+		if _, ok = os.LookupEnv(env); ok {
+			return fmt.Errorf("both %s and %s%s are set", env, env, fileEnvSuffix)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read secret file of %s: %w", key, err)
+		}
+		v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("CONFIG_TEST_ENDPOINT", "s01.neofs.devenv:8080")
+	t.Setenv("CONFIG_TEST_EMPTY", "")
+
+	for _, tc := range []struct {
+		data, expected string
+	}{
+		{data: "address: ${CONFIG_TEST_ENDPOINT}", expected: "address: s01.neofs.devenv:8080"},
+		{data: "address: ${CONFIG_TEST_ENDPOINT:-localhost:8080}", expected: "address: s01.neofs.devenv:8080"},
+		{data: "address: ${CONFIG_TEST_EMPTY:-localhost:8080}", expected: "address: localhost:8080"},
+		{data: "address: ${CONFIG_TEST_UNSET}", expected: "address: "},
+		{data: "passphrase: pa$$word $HOME", expected: "passphrase: pa$$word $HOME"},
+	} {
+		require.Equal(t, tc.expected, string(ExpandEnv([]byte(tc.data))))
+	}
+}
+
+func TestReadFragments(t *testing.T) {
+	dir := t.TempDir()
+	fragmentsDir := filepath.Join(dir, "conf.d")
+	require.NoError(t, os.Mkdir(fragmentsDir, 0700))
+
+	writeFile := func(path, data string) {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	}
+
+	mainFile := filepath.Join(dir, "config.yaml")
+	writeFile(mainFile, "logger:\n  level: debug\npeers:\n  0:\n    address: ${CONFIG_TEST_PEER}\n")
+	writeFile(filepath.Join(fragmentsDir, "20-logger.yaml"), "logger:\n  level: info\n")
+	writeFile(filepath.Join(fragmentsDir, "10-logger.yml"), "logger:\n  level: warn\n")
+	writeFile(filepath.Join(fragmentsDir, "README"), "not a config")
+	t.Setenv("CONFIG_TEST_PEER", "s01.neofs.devenv:8080")
+
+	fragments, err := Fragments(mainFile, fragmentsDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{mainFile, filepath.Join(fragmentsDir, "10-logger.yml"),
+		filepath.Join(fragmentsDir, "20-logger.yaml")}, fragments)
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, Read(v, fragments))
+	require.Equal(t, "info", v.GetString("logger.level"))
+	require.Equal(t, "s01.neofs.devenv:8080", v.GetString("peers.0.address"))
+
+	secretFile := filepath.Join(dir, "passphrase")
+	writeFile(secretFile, "secret\n")
+	t.Setenv("CONFIG_TEST_WALLET_PASSPHRASE_FILE", secretFile)
+	require.NoError(t, ReadSecretFiles(v, "CONFIG_TEST", []string{"wallet.passphrase", "logger.level"}))
+	require.Equal(t, "secret", v.GetString("wallet.passphrase"))
+	require.Equal(t, "info", v.GetString("logger.level"))
+
+	t.Setenv("CONFIG_TEST_WALLET_PASSPHRASE", "secret")
+	require.Error(t, ReadSecretFiles(v, "CONFIG_TEST", []string{"wallet.passphrase"}))
+}
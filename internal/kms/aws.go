@@ -0,0 +1,74 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+type (
+	// AWS is a client of the AWS KMS API compatible service.
+	AWS struct {
+		client *kms.KMS
+	}
+
+	// AWSConfig contains parameters of the AWS KMS API compatible service.
+	// Default AWS credentials chain is used if the access key isn't set.
+	AWSConfig struct {
+		Endpoint        string
+		Region          string
+		AccessKeyID     string
+		SecretAccessKey string
+		Timeout         time.Duration
+	}
+)
+
+// NewAWS creates a client of the AWS KMS API compatible service.
+func NewAWS(cfg AWSConfig) (*AWS, error) {
+	awsCfg := aws.NewConfig().WithRegion(cfg.Region).WithHTTPClient(&http.Client{Timeout: cfg.Timeout})
+	if len(cfg.Endpoint) > 0 {
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint)
+	}
+	if len(cfg.AccessKeyID) > 0 {
+		awsCfg = awsCfg.WithCredentials(credentials.NewStaticCredentials(cfg.AccessKeyID, cfg.SecretAccessKey, ""))
+	}
+
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create aws session: %w", err)
+	}
+
+	return &AWS{client: kms.New(sess)}, nil
+}
+
+// GenerateDataKey returns a new AES-256 data key and the data key encrypted with the KMS key.
+func (a *AWS) GenerateDataKey(ctx context.Context, keyID string) ([]byte, []byte, error) {
+	resp, err := a.client.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(keyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("kms generate data key: %w", err)
+	}
+
+	return resp.Plaintext, resp.CiphertextBlob, nil
+}
+
+// Decrypt decrypts the data key with the KMS key.
+func (a *AWS) Decrypt(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	resp, err := a.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt: %w", err)
+	}
+
+	return resp.Plaintext, nil
+}
@@ -0,0 +1,133 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// Vault is a client of the HashiCorp Vault transit secrets engine.
+	Vault struct {
+		client   *http.Client
+		endpoint string
+		token    string
+		mount    string
+	}
+
+	// VaultConfig contains parameters of the Vault transit secrets engine.
+	VaultConfig struct {
+		Endpoint string
+		Token    string
+		// Mount is a path of the transit secrets engine, "transit" by default.
+		Mount   string
+		Timeout time.Duration
+	}
+
+	vaultResponse struct {
+		Data struct {
+			Plaintext  string `json:"plaintext"`
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+		Errors []string `json:"errors"`
+	}
+)
+
+const (
+	defaultVaultMount = "transit"
+	vaultTokenHeader  = "X-Vault-Token"
+	dataKeyBits       = 256
+)
+
+// NewVault creates a client of the Vault transit secrets engine.
+func NewVault(cfg VaultConfig) (*Vault, error) {
+	if _, err := url.Parse(cfg.Endpoint); err != nil || len(cfg.Endpoint) == 0 {
+		return nil, fmt.Errorf("invalid vault endpoint '%s'", cfg.Endpoint)
+	}
+
+	mount := strings.Trim(cfg.Mount, "/")
+	if len(mount) == 0 {
+		mount = defaultVaultMount
+	}
+
+	return &Vault{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		token:    cfg.Token,
+		mount:    mount,
+	}, nil
+}
+
+// GenerateDataKey returns a new data key generated by the transit key and the data key
+// wrapped with it. The wrapped key is the Vault ciphertext, e.g. "vault:v1:...".
+func (v *Vault) GenerateDataKey(ctx context.Context, keyID string) ([]byte, []byte, error) {
+	resp, err := v.request(ctx, "datakey/plaintext/"+url.PathEscape(keyID), map[string]interface{}{"bits": dataKeyBits})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode data key: %w", err)
+	}
+
+	return key, []byte(resp.Data.Ciphertext), nil
+}
+
+// Decrypt unwraps the data key with the transit key.
+func (v *Vault) Decrypt(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	resp, err := v.request(ctx, "decrypt/"+url.PathEscape(keyID), map[string]interface{}{"ciphertext": string(wrappedKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("decode data key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (v *Vault) request(ctx context.Context, path string, body map[string]interface{}) (*vaultResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal vault request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint+"/v1/"+v.mount+"/"+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create vault request: %w", err)
+	}
+	req.Header.Set(vaultTokenHeader, v.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read vault response: %w", err)
+	}
+
+	var resp vaultResponse
+	if err = json.Unmarshal(respBody, &resp); err != nil && httpResp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("unmarshal vault response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault response status %d: %s", httpResp.StatusCode, strings.Join(resp.Errors, "; "))
+	}
+
+	return &resp, nil
+}
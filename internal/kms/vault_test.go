@@ -0,0 +1,63 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault(t *testing.T) {
+	dataKey := make([]byte, 32)
+	dataKey[0] = 1
+	plaintext := base64.StdEncoding.EncodeToString(dataKey)
+	ciphertext := "vault:v1:wrapped"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(vaultTokenHeader) != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch r.URL.Path {
+		case "/v1/transit/datakey/plaintext/key":
+			require.EqualValues(t, dataKeyBits, body["bits"])
+			_, _ = w.Write([]byte(`{"data":{"plaintext":"` + plaintext + `","ciphertext":"` + ciphertext + `"}}`))
+		case "/v1/transit/decrypt/key":
+			require.Equal(t, ciphertext, body["ciphertext"])
+			_, _ = w.Write([]byte(`{"data":{"plaintext":"` + plaintext + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	vault, err := NewVault(VaultConfig{Endpoint: server.URL, Token: "token"})
+	require.NoError(t, err)
+
+	key, wrappedKey, err := vault.GenerateDataKey(context.Background(), "key")
+	require.NoError(t, err)
+	require.Equal(t, dataKey, key)
+	require.Equal(t, ciphertext, string(wrappedKey))
+
+	key, err = vault.Decrypt(context.Background(), "key", wrappedKey)
+	require.NoError(t, err)
+	require.Equal(t, dataKey, key)
+
+	_, _, err = vault.GenerateDataKey(context.Background(), "unknown")
+	require.Error(t, err)
+
+	vault, err = NewVault(VaultConfig{Endpoint: server.URL, Token: "invalid"})
+	require.NoError(t, err)
+	_, err = vault.Decrypt(context.Background(), "key", wrappedKey)
+	require.ErrorContains(t, err, "permission denied")
+}
@@ -3,22 +3,27 @@ package neofs
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	objectv2 "github.com/nspcc-dev/neofs-api-go/v2/object"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"github.com/nspcc-dev/neofs-s3-gw/authmate"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
 	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
 	"github.com/nspcc-dev/neofs-sdk-go/container"
 	"github.com/nspcc-dev/neofs-sdk-go/container/acl"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
 	"github.com/nspcc-dev/neofs-sdk-go/object"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
@@ -32,6 +37,12 @@ import (
 type NeoFS struct {
 	pool  *pool.Pool
 	await pool.WaitParams
+
+	netmapKey   *ecdsa.PrivateKey
+	netmapPeers []string
+
+	netmapMtx   sync.Mutex
+	netmapCache *netmap.NetMap
 }
 
 const (
@@ -87,6 +98,90 @@ func (x *NeoFS) TimeToEpoch(ctx context.Context, now, futureTime time.Time) (uin
 	return curr, epoch, nil
 }
 
+// CurrentEpoch returns the current NeoFS epoch.
+func (x *NeoFS) CurrentEpoch(ctx context.Context) (uint64, error) {
+	networkInfo, err := x.pool.NetworkInfo(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get network info via client: %w", err)
+	}
+
+	return networkInfo.CurrentEpoch(), nil
+}
+
+// Statistic returns statistics of requests to the nodes of the connection pool.
+func (x *NeoFS) Statistic() pool.Statistic {
+	return x.pool.Statistic()
+}
+
+// SetNetmapPeers sets NeoFS nodes which are asked for the network map.
+// Connection pool doesn't provide network map, so it's requested via separate
+// client connection to the first available peer.
+func (x *NeoFS) SetNetmapPeers(key *ecdsa.PrivateKey, peers []string) {
+	x.netmapKey = key
+	x.netmapPeers = peers
+}
+
+// NetmapSnapshot implements neofs.NeoFS interface method.
+//
+// Network map is cached until the next epoch. The current epoch is checked
+// via connection pool, so the separate client is dialed once per epoch.
+func (x *NeoFS) NetmapSnapshot(ctx context.Context) (*netmap.NetMap, error) {
+	if x.netmapKey == nil || len(x.netmapPeers) == 0 {
+		return nil, errors.New("peers to get network map from are not set")
+	}
+
+	networkInfo, err := x.pool.NetworkInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get network info via client: %w", err)
+	}
+
+	x.netmapMtx.Lock()
+	defer x.netmapMtx.Unlock()
+
+	if x.netmapCache != nil && x.netmapCache.Epoch() == networkInfo.CurrentEpoch() {
+		return x.netmapCache, nil
+	}
+
+	var prmInit client.PrmInit
+	prmInit.SetDefaultPrivateKey(*x.netmapKey)
+	prmInit.ResolveNeoFSFailures()
+
+	for _, peer := range x.netmapPeers {
+		var nm *netmap.NetMap
+		if nm, err = netmapSnapshot(ctx, prmInit, peer); err == nil {
+			x.netmapCache = nm
+			return nm, nil
+		}
+		err = fmt.Errorf("peer '%s': %w", peer, err)
+	}
+
+	return nil, fmt.Errorf("get network map: %w", err)
+}
+
+func netmapSnapshot(ctx context.Context, prmInit client.PrmInit, address string) (*netmap.NetMap, error) {
+	var c client.Client
+	c.Init(prmInit)
+
+	var prmDial client.PrmDial
+	prmDial.SetServerURI(address)
+	prmDial.SetContext(ctx)
+
+	if err := c.Dial(prmDial); err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	res, err := c.NetMapSnapshot(ctx, client.PrmNetMapSnapshot{})
+	if err != nil {
+		return nil, fmt.Errorf("netmap snapshot: %w", err)
+	}
+
+	nm := res.NetMap()
+	return &nm, nil
+}
+
 // Container implements neofs.NeoFS interface method.
 func (x *NeoFS) Container(ctx context.Context, idCnr cid.ID) (*container.Container, error) {
 	var prm pool.PrmContainerGet
@@ -278,19 +373,18 @@ func (x *NeoFS) CreateObject(ctx context.Context, prm layer.PrmObjectCreate) (oi
 	}
 
 	idObj, err := x.pool.PutObject(ctx, prmPut)
+	if isErrSessionToken(err) && rewindPayload(prm.Payload) {
+		idObj, err = x.pool.PutObject(ctx, prmPut)
+	}
 	if err != nil {
-		reason, ok := isErrAccessDenied(err)
-		if ok {
-			return oid.ID{}, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
-		}
-		return oid.ID{}, fmt.Errorf("save object via connection pool: %w", err)
+		return oid.ID{}, wrapError("save object via connection pool", err)
 	}
 
 	return idObj, nil
 }
 
-// wraps io.ReadCloser and transforms Read errors related to access violation
-// to neofs.ErrAccessDenied.
+// wraps io.ReadCloser and transforms Read errors with NeoFS statuses
+// to layer.NeoFSError.
 type payloadReader struct {
 	io.ReadCloser
 }
@@ -298,8 +392,8 @@ type payloadReader struct {
 func (x payloadReader) Read(p []byte) (int, error) {
 	n, err := x.ReadCloser.Read(p)
 	if err != nil {
-		if reason, ok := isErrAccessDenied(err); ok {
-			return n, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
+		if statusErr := neofsError(err); statusErr != nil {
+			return n, statusErr
 		}
 	}
 
@@ -325,18 +419,14 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 		if prm.WithPayload {
 			res, err := x.pool.GetObject(ctx, prmGet)
 			if err != nil {
-				if reason, ok := isErrAccessDenied(err); ok {
-					return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
-				}
-
-				return nil, fmt.Errorf("init full object reading via connection pool: %w", err)
+				return nil, wrapError("init full object reading via connection pool", err)
 			}
 
 			defer res.Payload.Close()
 
 			payload, err := io.ReadAll(res.Payload)
 			if err != nil {
-				return nil, fmt.Errorf("read full object payload: %w", err)
+				return nil, wrapError("read full object payload", err)
 			}
 
 			res.Header.SetPayload(payload)
@@ -357,11 +447,7 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 
 		hdr, err := x.pool.HeadObject(ctx, prmHead)
 		if err != nil {
-			if reason, ok := isErrAccessDenied(err); ok {
-				return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
-			}
-
-			return nil, fmt.Errorf("read object header via connection pool: %w", err)
+			return nil, wrapError("read object header via connection pool", err)
 		}
 
 		return &layer.ObjectPart{
@@ -370,11 +456,7 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 	} else if prm.PayloadRange[0]+prm.PayloadRange[1] == 0 {
 		res, err := x.pool.GetObject(ctx, prmGet)
 		if err != nil {
-			if reason, ok := isErrAccessDenied(err); ok {
-				return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
-			}
-
-			return nil, fmt.Errorf("init full payload range reading via connection pool: %w", err)
+			return nil, wrapError("init full payload range reading via connection pool", err)
 		}
 
 		return &layer.ObjectPart{
@@ -395,11 +477,7 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 
 	res, err := x.pool.ObjectRange(ctx, prmRange)
 	if err != nil {
-		if reason, ok := isErrAccessDenied(err); ok {
-			return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
-		}
-
-		return nil, fmt.Errorf("init payload range reading via connection pool: %w", err)
+		return nil, wrapError("init payload range reading via connection pool", err)
 	}
 
 	return &layer.ObjectPart{
@@ -407,6 +485,45 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 	}, nil
 }
 
+// SearchObjects implements neofs.NeoFS interface method.
+func (x *NeoFS) SearchObjects(ctx context.Context, prm layer.PrmObjectSearch) ([]oid.ID, error) {
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	for _, attr := range prm.ExactAttributes {
+		filters.AddFilter(attr[0], attr[1], object.MatchStringEqual)
+	}
+	for _, attr := range prm.PrefixAttributes {
+		filters.AddFilter(attr[0], attr[1], object.MatchCommonPrefix)
+	}
+
+	var prmSearch pool.PrmObjectSearch
+	prmSearch.SetContainerID(prm.Container)
+	prmSearch.SetFilters(filters)
+
+	if prm.BearerToken != nil {
+		prmSearch.UseBearer(*prm.BearerToken)
+	} else {
+		prmSearch.UseKey(prm.PrivateKey)
+	}
+
+	res, err := x.pool.SearchObjects(ctx, prmSearch)
+	if err != nil {
+		return nil, wrapError("init object search via connection pool", err)
+	}
+	defer res.Close()
+
+	var ids []oid.ID
+	err = res.Iterate(func(id oid.ID) bool {
+		ids = append(ids, id)
+		return false
+	})
+	if err != nil {
+		return nil, wrapError("read object search result", err)
+	}
+
+	return ids, nil
+}
+
 // DeleteObject implements neofs.NeoFS interface method.
 func (x *NeoFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) error {
 	var addr oid.Address
@@ -423,32 +540,84 @@ func (x *NeoFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) err
 	}
 
 	err := x.pool.DeleteObject(ctx, prmDelete)
+	if isErrSessionToken(err) {
+		err = x.pool.DeleteObject(ctx, prmDelete)
+	}
 	if err != nil {
-		if reason, ok := isErrAccessDenied(err); ok {
-			return fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
-		}
-
-		return fmt.Errorf("mark object removal via connection pool: %w", err)
+		return wrapError("mark object removal via connection pool", err)
 	}
 
 	return nil
 }
 
-func isErrAccessDenied(err error) (string, bool) {
-	unwrappedErr := errors.Unwrap(err)
-	for unwrappedErr != nil {
-		err = unwrappedErr
-		unwrappedErr = errors.Unwrap(err)
+// isErrSessionToken checks whether the default session of the pool is expired or unknown to the node.
+// It happens at epoch transitions when the node has already switched to the new epoch, but the pool
+// hasn't noticed it yet. The pool drops the cached session on such errors, so the retried request
+// opens a new session.
+//
+// Sessions aren't refreshed ahead of the epoch change: they are opened by the pool on the node serving
+// the request and kept in its private cache, so the gateway can neither replace them nor open its own
+// ones valid on the node chosen by the pool.
+func isErrSessionToken(err error) bool {
+	return client.IsErrSessionExpired(err) || client.IsErrSessionNotFound(err)
+}
+
+// rewindPayload seeks the payload of the failed write to the start, so the object can be written again.
+// Payloads which aren't io.Seeker can't be read twice.
+func rewindPayload(payload io.Reader) bool {
+	if payload == nil {
+		return true
 	}
 
-	switch err := err.(type) {
-	default:
-		return "", false
+	seeker, ok := payload.(io.Seeker)
+	if !ok {
+		return false
+	}
+	_, err := seeker.Seek(0, io.SeekStart)
+	return err == nil
+}
+
+// wrapError annotates the error of the NeoFS request with the message, known NeoFS statuses
+// are transformed to layer.NeoFSError.
+func wrapError(msg string, err error) error {
+	if statusErr := neofsError(err); statusErr != nil {
+		return fmt.Errorf("%s: %w", msg, statusErr)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// neofsError returns the typed error of the NeoFS status carried by err, nil is returned for other errors.
+func neofsError(err error) *layer.NeoFSError {
+	status := err
+	for unwrapped := errors.Unwrap(status); unwrapped != nil; unwrapped = errors.Unwrap(status) {
+		status = unwrapped
+	}
+
+	switch st := status.(type) {
 	case apistatus.ObjectAccessDenied:
-		return err.Reason(), true
+		return &layer.NeoFSError{Kind: layer.ErrAccessDenied, Reason: st.Reason(), Cause: err}
 	case *apistatus.ObjectAccessDenied:
-		return err.Reason(), true
+		return &layer.NeoFSError{Kind: layer.ErrAccessDenied, Reason: st.Reason(), Cause: err}
+	case apistatus.ObjectNotFound, *apistatus.ObjectNotFound,
+		apistatus.ObjectAlreadyRemoved, *apistatus.ObjectAlreadyRemoved:
+		return &layer.NeoFSError{Kind: layer.ErrObjectNotFound, Reason: status.Error(), Cause: err}
+	case apistatus.ServerInternal:
+		return quotaError(st.Message(), err)
+	case *apistatus.ServerInternal:
+		return quotaError(st.Message(), err)
+	default:
+		return nil
+	}
+}
+
+// quotaError returns ErrQuotaExceeded error if the internal error message of the node reports
+// that the storage has no space for the object. There is no dedicated NeoFS status for it.
+func quotaError(msg string, err error) *layer.NeoFSError {
+	lower := strings.ToLower(msg)
+	if strings.Contains(lower, "quota") || strings.Contains(lower, "no space left") {
+		return &layer.NeoFSError{Kind: layer.ErrQuotaExceeded, Reason: msg, Cause: err}
 	}
+	return nil
 }
 
 // ResolverNeoFS represents virtual connection to the NeoFS network.
@@ -502,6 +671,11 @@ func (x *AuthmateNeoFS) TimeToEpoch(ctx context.Context, futureTime time.Time) (
 	return x.neoFS.TimeToEpoch(ctx, time.Now(), futureTime)
 }
 
+// CurrentEpoch implements auth.EpochSource interface method.
+func (x *AuthmateNeoFS) CurrentEpoch(ctx context.Context) (uint64, error) {
+	return x.neoFS.CurrentEpoch(ctx)
+}
+
 // CreateContainer implements authmate.NeoFS interface method.
 func (x *AuthmateNeoFS) CreateContainer(ctx context.Context, prm authmate.PrmContainerCreate) (cid.ID, error) {
 	basicACL := acl.Private
@@ -1,7 +1,10 @@
 package neofs
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
@@ -14,12 +17,40 @@ func TestErrorChecking(t *testing.T) {
 	err := new(apistatus.ObjectAccessDenied)
 	err.WriteReason(reason)
 
-	var wrappedError error
-
-	if fetchedReason, ok := isErrAccessDenied(err); ok {
-		wrappedError = fmt.Errorf("%w: %s", layer.ErrAccessDenied, fetchedReason)
-	}
+	wrappedError := wrapError("read object", fmt.Errorf("pool: %w", err))
 
 	require.ErrorIs(t, wrappedError, layer.ErrAccessDenied)
 	require.Contains(t, wrappedError.Error(), reason)
+
+	var statusErr *layer.NeoFSError
+	require.ErrorAs(t, wrappedError, &statusErr)
+	require.Equal(t, reason, statusErr.Reason)
+	require.ErrorIs(t, statusErr.Cause, err)
+
+	require.ErrorIs(t, wrapError("head object", new(apistatus.ObjectNotFound)), layer.ErrObjectNotFound)
+	require.ErrorIs(t, wrapError("head object", apistatus.ObjectAlreadyRemoved{}), layer.ErrObjectNotFound)
+
+	internal := new(apistatus.ServerInternal)
+	internal.SetMessage("container quota exceeded")
+	require.ErrorIs(t, wrapError("save object", internal), layer.ErrQuotaExceeded)
+
+	otherErr := errors.New("connection refused")
+	wrappedError = wrapError("save object", otherErr)
+	require.ErrorIs(t, wrappedError, otherErr)
+	require.False(t, errors.As(wrappedError, &statusErr))
+}
+
+func TestRewindPayload(t *testing.T) {
+	require.True(t, rewindPayload(nil))
+
+	payload := bytes.NewReader([]byte("payload"))
+	_, err := payload.Read(make([]byte, 3))
+	require.NoError(t, err)
+
+	require.True(t, rewindPayload(payload))
+	content, err := io.ReadAll(payload)
+	require.NoError(t, err)
+	require.Equal(t, "payload", string(content))
+
+	require.False(t, rewindPayload(io.MultiReader(bytes.NewReader([]byte("payload")))))
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -50,7 +51,16 @@ type (
 
 const (
 	versioningKV        = "Versioning"
+	mfaDeleteKV         = "MFADelete"
 	lockConfigurationKV = "LockConfiguration"
+	trashRetentionKV    = "TrashRetention"
+	etagAlgorithmKV     = "ETagAlgorithm"
+	replicaNetworkKV    = "SyncReplicationNetwork"
+	replicaContainerKV  = "SyncReplicationContainer"
+	publicAccessBlockKV = "PublicAccessBlock"
+	objectOwnershipKV   = "ObjectOwnership"
+	requestPayerKV      = "RequestPayer"
+	featuresKV          = "Features"
 	oidKV               = "OID"
 	fileNameKV          = "FileName"
 	isUnversionedKV     = "IsUnversioned"
@@ -59,6 +69,8 @@ const (
 	partNumberKV        = "Number"
 	sizeKV              = "Size"
 	etagKV              = "ETag"
+	checksumAlgorithmKV = "ChecksumAlgorithm"
+	checksumKV          = "Checksum"
 
 	// keys for lock.
 	isLockKV       = "IsLock"
@@ -72,10 +84,53 @@ const (
 	ownerKV          = "Owner"
 	createdKV        = "Created"
 
+	// keys for packed object nodes.
+	packOIDKV    = "PackOID"
+	packOffsetKV = "PackOffset"
+	packMetaKV   = "PackMeta"
+	metadataKV   = "Metadata"
+
+	// keys for replicated object nodes.
+	replicaOIDKV            = "ReplicaOID"
+	versionReplicaNetworkKV = "ReplicaNetwork"
+	versionReplicaCnrKV     = "ReplicaContainer"
+
+	// keys for bucket replication, the initial state is saved in the version node and
+	// the final one is saved in the child node.
+	replicationStatusKV = "ReplicationStatus"
+	isReplicationKV     = "IsReplication"
+
+	// keys for archived object nodes, the restore state is saved in the child node.
+	archiveStorageClassKV = "ArchiveStorageClass"
+	archiveCnrKV          = "ArchiveContainer"
+	archiveOIDKV          = "ArchiveOID"
+	isRestoreKV           = "IsRestore"
+	restoreOngoingKV      = "RestoreOngoing"
+	restoreDaysKV         = "RestoreDays"
+	restoreOIDKV          = "RestoreOID"
+	restoreExpiryKV       = "RestoreExpiry"
+
+	policyKV  = "Policy"
+	websiteKV = "Website"
+
+	// keys for trash nodes.
+	trashKeyKV     = "TrashKey"
+	trashDeletedKV = "TrashDeleted"
+
 	settingsFileName      = "bucket-settings"
 	notifConfFileName     = "bucket-notifications"
 	corsFilename          = "bucket-cors"
+	configHistoryFilename = "bucket-config-history"
+	packIndexFilename     = "bucket-packs"
+	trashFilename         = "bucket-trash"
 	bucketTaggingFilename = "bucket-tagging"
+	lifecycleFilename     = "bucket-lifecycle"
+	inventoryFilename     = "bucket-inventory"
+	replicationFilename   = "bucket-replication"
+	analyticsFilename     = "bucket-analytics"
+	metricsFilename       = "bucket-metrics"
+	policyFilename        = "bucket-policy"
+	websiteFilename       = "bucket-website"
 
 	// versionTree -- ID of a tree with object versions.
 	versionTree = "version"
@@ -200,6 +255,46 @@ func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) *data.NodeV
 			Owner:   owner,
 		}
 	}
+
+	if packOIDStr, ok := treeNode.Get(packOIDKV); ok {
+		var pack data.PackInfo
+		if err := pack.OID.DecodeString(packOIDStr); err == nil {
+			if offsetStr, ok := treeNode.Get(packOffsetKV); ok {
+				pack.Offset, _ = strconv.ParseUint(offsetStr, 10, 64)
+			}
+			pack.Meta, _ = treeNode.Get(packMetaKV)
+			version.Pack = &pack
+		}
+	}
+
+	if replicaOIDStr, ok := treeNode.Get(replicaOIDKV); ok {
+		var replica data.ReplicaInfo
+		if err := replica.OID.DecodeString(replicaOIDStr); err == nil {
+			cnrStr, _ := treeNode.Get(versionReplicaCnrKV)
+			if err = replica.Container.DecodeString(cnrStr); err == nil {
+				replica.Network, _ = treeNode.Get(versionReplicaNetworkKV)
+				version.Replica = &replica
+			}
+		}
+	}
+
+	if archiveOIDStr, ok := treeNode.Get(archiveOIDKV); ok {
+		var archive data.ArchiveInfo
+		if err := archive.OID.DecodeString(archiveOIDStr); err == nil {
+			cnrStr, _ := treeNode.Get(archiveCnrKV)
+			if err = archive.Container.DecodeString(cnrStr); err == nil {
+				archive.StorageClass, _ = treeNode.Get(archiveStorageClassKV)
+				version.Archive = &archive
+			}
+		}
+	}
+
+	version.Metadata, _ = treeNode.Get(metadataKV)
+	version.ReplicationStatus, _ = treeNode.Get(replicationStatusKV)
+	if version.ChecksumAlgorithm, _ = treeNode.Get(checksumAlgorithmKV); version.ChecksumAlgorithm != "" {
+		version.Checksum, _ = treeNode.Get(checksumKV)
+	}
+
 	return version
 }
 
@@ -250,6 +345,10 @@ func newPartInfo(node NodeResponse) (*data.PartInfo, error) {
 			}
 		case etagKV:
 			partInfo.ETag = value
+		case checksumAlgorithmKV:
+			partInfo.ChecksumAlgorithm = value
+		case checksumKV:
+			partInfo.Checksum = value
 		case sizeKV:
 			if partInfo.Size, err = strconv.ParseInt(value, 10, 64); err != nil {
 				return nil, fmt.Errorf("invalid part size: %w", err)
@@ -270,76 +369,584 @@ func newPartInfo(node NodeResponse) (*data.PartInfo, error) {
 	return partInfo, nil
 }
 
-func (c *TreeClient) GetSettingsNode(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error) {
-	keysToReturn := []string{versioningKV, lockConfigurationKV}
-	node, err := c.getSystemNode(ctx, bktInfo, []string{settingsFileName}, keysToReturn)
+func (c *TreeClient) GetSettingsNode(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error) {
+	keysToReturn := []string{versioningKV, mfaDeleteKV, lockConfigurationKV, trashRetentionKV, etagAlgorithmKV,
+		replicaNetworkKV, replicaContainerKV, publicAccessBlockKV, objectOwnershipKV, requestPayerKV, featuresKV}
+	node, err := c.getSystemNode(ctx, bktInfo, []string{settingsFileName}, keysToReturn)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+	if versioningValue, ok := node.Get(versioningKV); ok {
+		settings.Versioning = versioningValue
+	}
+
+	if mfaDeleteValue, ok := node.Get(mfaDeleteKV); ok {
+		settings.MFADelete = mfaDeleteValue
+	}
+
+	if lockConfigurationValue, ok := node.Get(lockConfigurationKV); ok {
+		if settings.LockConfiguration, err = parseLockConfiguration(lockConfigurationValue); err != nil {
+			return nil, fmt.Errorf("settings node: invalid lock configuration: %w", err)
+		}
+	}
+
+	if trashRetentionValue, ok := node.Get(trashRetentionKV); ok && len(trashRetentionValue) != 0 {
+		if settings.TrashRetention, err = time.ParseDuration(trashRetentionValue); err != nil {
+			return nil, fmt.Errorf("settings node: invalid trash retention: %w", err)
+		}
+	}
+
+	if etagAlgorithmValue, ok := node.Get(etagAlgorithmKV); ok {
+		settings.ETagAlgorithm = etagAlgorithmValue
+	}
+
+	if replicaContainerValue, ok := node.Get(replicaContainerKV); ok && len(replicaContainerValue) != 0 {
+		settings.SyncReplication = new(data.SyncReplication)
+		if err = settings.SyncReplication.Container.DecodeString(replicaContainerValue); err != nil {
+			return nil, fmt.Errorf("settings node: invalid sync replication container: %w", err)
+		}
+		settings.SyncReplication.Network, _ = node.Get(replicaNetworkKV)
+	}
+
+	if publicAccessBlockValue, ok := node.Get(publicAccessBlockKV); ok {
+		if settings.PublicAccessBlock, err = parsePublicAccessBlock(publicAccessBlockValue); err != nil {
+			return nil, fmt.Errorf("settings node: invalid public access block: %w", err)
+		}
+	}
+
+	if objectOwnershipValue, ok := node.Get(objectOwnershipKV); ok {
+		settings.ObjectOwnership = objectOwnershipValue
+	}
+
+	if requestPayerValue, ok := node.Get(requestPayerKV); ok {
+		settings.RequestPayer = requestPayerValue
+	}
+
+	if featuresValue, ok := node.Get(featuresKV); ok {
+		if settings.Features, err = parseFeatures(featuresValue); err != nil {
+			return nil, fmt.Errorf("settings node: invalid features: %w", err)
+		}
+	}
+
+	return settings, nil
+}
+
+func (c *TreeClient) PutSettingsNode(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings) error {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{settingsFileName}, []string{})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := metaFromSettings(settings)
+
+	if isErrNotFound {
+		_, err = c.addNode(ctx, bktInfo, systemTree, 0, meta)
+		return err
+	}
+
+	return c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) GetNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{notifConfFileName}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{notifConfFileName}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = notifConfFileName
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{corsFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketCORS(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{corsFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = corsFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{corsFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{lifecycleFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{lifecycleFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = lifecycleFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketLifecycleConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{lifecycleFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{inventoryFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{inventoryFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = inventoryFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketInventoryConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{inventoryFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{replicationFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{replicationFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = replicationFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketReplicationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{replicationFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{analyticsFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{analyticsFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = analyticsFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketAnalyticsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{analyticsFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{metricsFilename}, []string{oidKV})
+	if err != nil {
+		return oid.ID{}, err
+	}
+
+	return node.ObjID, nil
+}
+
+func (c *TreeClient) PutBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{metricsFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = metricsFilename
+	meta[oidKV] = objID.EncodeToString()
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketMetricsConfigurations(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{metricsFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) ([]byte, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{policyFilename}, []string{policyKV})
+	if err != nil {
+		return nil, err
+	}
+
+	document, ok := node.Get(policyKV)
+	if !ok {
+		return nil, layer.ErrNodeNotFound
+	}
+
+	return []byte(document), nil
+}
+
+func (c *TreeClient) PutBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID, document []byte) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{policyFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = policyFilename
+	meta[oidKV] = objID.EncodeToString()
+	meta[policyKV] = string(document)
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketPolicy(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{policyFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) GetBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) ([]byte, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{websiteFilename}, []string{websiteKV})
+	if err != nil {
+		return nil, err
+	}
+
+	configuration, ok := node.Get(websiteKV)
+	if !ok {
+		return nil, layer.ErrNodeNotFound
+	}
+
+	return []byte(configuration), nil
+}
+
+func (c *TreeClient) PutBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID, configuration []byte) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{websiteFilename}, []string{oidKV})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := make(map[string]string)
+	meta[fileNameKV] = websiteFilename
+	meta[oidKV] = objID.EncodeToString()
+	meta[websiteKV] = string(configuration)
+
+	if isErrNotFound {
+		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+			return oid.ID{}, err
+		}
+		return oid.ID{}, layer.ErrNoNodeToRemove
+	}
+
+	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) DeleteBucketWebsite(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{websiteFilename}, []string{oidKV})
+	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+		return oid.ID{}, err
+	}
+
+	if node != nil {
+		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+	}
+
+	return oid.ID{}, layer.ErrNoNodeToRemove
+}
+
+func (c *TreeClient) AddBucketConfigChange(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) error {
+	meta := make(map[string]string)
+	meta[fileNameKV] = objID.EncodeToString()
+	meta[oidKV] = objID.EncodeToString()
+
+	_, err := c.addNodeByPath(ctx, bktInfo, systemTree, []string{configHistoryFilename}, meta)
+	return err
+}
+
+func (c *TreeClient) GetBucketConfigChanges(ctx context.Context, bktInfo *data.BucketInfo) ([]oid.ID, error) {
+	nodes, err := c.getSystemChildren(ctx, bktInfo, configHistoryFilename)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't get node: %w", err)
+		return nil, err
 	}
 
-	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
-	if versioningValue, ok := node.Get(versioningKV); ok {
-		settings.Versioning = versioningValue
+	result := make([]oid.ID, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, node.ObjID)
 	}
 
-	if lockConfigurationValue, ok := node.Get(lockConfigurationKV); ok {
-		if settings.LockConfiguration, err = parseLockConfiguration(lockConfigurationValue); err != nil {
-			return nil, fmt.Errorf("settings node: invalid lock configuration: %w", err)
-		}
+	return result, nil
+}
+
+func (c *TreeClient) AddTrashVersion(ctx context.Context, bktInfo *data.BucketInfo, trashVersion *data.TrashVersion) (uint64, error) {
+	meta := metaFromVersion(trashVersion.Version)
+	meta[fileNameKV] = trashVersion.Name()
+	meta[trashKeyKV] = trashVersion.Version.FilePath
+	meta[trashDeletedKV] = strconv.FormatInt(trashVersion.Deleted.UnixMilli(), 10)
+	for key, value := range trashVersion.Tags {
+		meta[userDefinedTagPrefix+key] = value
 	}
 
-	return settings, nil
+	return c.addNodeByPath(ctx, bktInfo, systemTree, []string{trashFilename}, meta)
 }
 
-func (c *TreeClient) PutSettingsNode(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings) error {
-	node, err := c.getSystemNode(ctx, bktInfo, []string{settingsFileName}, []string{})
-	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
-	if err != nil && !isErrNotFound {
-		return fmt.Errorf("couldn't get node: %w", err)
+func (c *TreeClient) GetTrashVersions(ctx context.Context, bktInfo *data.BucketInfo) ([]*data.TrashVersion, error) {
+	nodes, err := c.getSystemChildren(ctx, bktInfo, trashFilename)
+	if err != nil {
+		return nil, err
 	}
 
-	meta := metaFromSettings(settings)
+	result := make([]*data.TrashVersion, 0, len(nodes))
+	for _, node := range nodes {
+		key, _ := node.Get(trashKeyKV)
+		deletedStr, _ := node.Get(trashDeletedKV)
+		deletedMilli, err := strconv.ParseInt(deletedStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trash deletion time '%s': %w", deletedStr, err)
+		}
 
-	if isErrNotFound {
-		_, err = c.addNode(ctx, bktInfo, systemTree, 0, meta)
-		return err
+		trashVersion := &data.TrashVersion{
+			ID:      node.ID,
+			Version: newNodeVersionFromTreeNode(key, node),
+			Deleted: time.UnixMilli(deletedMilli).UTC(),
+			Tags:    make(map[string]string),
+		}
+		for metaKey, value := range node.Meta {
+			if strings.HasPrefix(metaKey, userDefinedTagPrefix) {
+				trashVersion.Tags[strings.TrimPrefix(metaKey, userDefinedTagPrefix)] = value
+			}
+		}
+		result = append(result, trashVersion)
 	}
 
-	return c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+	return result, nil
 }
 
-func (c *TreeClient) GetNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	node, err := c.getSystemNode(ctx, bktInfo, []string{notifConfFileName}, []string{oidKV})
-	if err != nil {
-		return oid.ID{}, err
-	}
-
-	return node.ObjID, nil
+func (c *TreeClient) RemoveTrashVersion(ctx context.Context, bktInfo *data.BucketInfo, id uint64) error {
+	return c.removeNode(ctx, bktInfo, systemTree, id)
 }
 
-func (c *TreeClient) PutNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
-	node, err := c.getSystemNode(ctx, bktInfo, []string{notifConfFileName}, []string{oidKV})
-	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
-	if err != nil && !isErrNotFound {
-		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
+// getSystemChildren returns child nodes of the system tree node with the name.
+func (c *TreeClient) getSystemChildren(ctx context.Context, bktInfo *data.BucketInfo, name string) ([]*TreeNode, error) {
+	p := &getNodesParams{
+		BktInfo: bktInfo,
+		TreeID:  systemTree,
+		Path:    []string{name},
+	}
+	parentNodes, err := c.getNodes(ctx, p)
+	if err != nil {
+		if errors.Is(err, layer.ErrNodeNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	meta := make(map[string]string)
-	meta[fileNameKV] = notifConfFileName
-	meta[oidKV] = objID.EncodeToString()
+	var result []*TreeNode
+	for _, parentNode := range parentNodes {
+		subTree, err := c.getSubTree(ctx, bktInfo, systemTree, parentNode.GetNodeId(), 2)
+		if err != nil {
+			return nil, err
+		}
 
-	if isErrNotFound {
-		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
-			return oid.ID{}, err
+		for _, node := range subTree {
+			if node.GetNodeId() == parentNode.GetNodeId() {
+				continue
+			}
+
+			treeNode, err := newTreeNode(node)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tree node: %w", err)
+			}
+			result = append(result, treeNode)
 		}
-		return oid.ID{}, layer.ErrNoNodeToRemove
 	}
 
-	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+	return result, nil
 }
 
-func (c *TreeClient) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	node, err := c.getSystemNode(ctx, bktInfo, []string{corsFilename}, []string{oidKV})
+func (c *TreeClient) GetBucketPackIndexNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{packIndexFilename}, []string{oidKV})
 	if err != nil {
 		return oid.ID{}, err
 	}
@@ -347,15 +954,15 @@ func (c *TreeClient) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo
 	return node.ObjID, nil
 }
 
-func (c *TreeClient) PutBucketCORS(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
-	node, err := c.getSystemNode(ctx, bktInfo, []string{corsFilename}, []string{oidKV})
+func (c *TreeClient) PutBucketPackIndexNode(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{packIndexFilename}, []string{oidKV})
 	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
 	if err != nil && !isErrNotFound {
 		return oid.ID{}, fmt.Errorf("couldn't get node: %w", err)
 	}
 
 	meta := make(map[string]string)
-	meta[fileNameKV] = corsFilename
+	meta[fileNameKV] = packIndexFilename
 	meta[oidKV] = objID.EncodeToString()
 
 	if isErrNotFound {
@@ -368,19 +975,6 @@ func (c *TreeClient) PutBucketCORS(ctx context.Context, bktInfo *data.BucketInfo
 	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
 }
 
-func (c *TreeClient) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	node, err := c.getSystemNode(ctx, bktInfo, []string{corsFilename}, []string{oidKV})
-	if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
-		return oid.ID{}, err
-	}
-
-	if node != nil {
-		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
-	}
-
-	return oid.ID{}, layer.ErrNoNodeToRemove
-}
-
 func (c *TreeClient) GetObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, error) {
 	tagNode, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isTagKV)
 	if err != nil {
@@ -536,7 +1130,9 @@ func (c *TreeClient) GetVersions(ctx context.Context, bktInfo *data.BucketInfo,
 }
 
 func (c *TreeClient) GetLatestVersion(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
-	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, packOIDKV, packOffsetKV, packMetaKV, metadataKV,
+		replicaOIDKV, versionReplicaNetworkKV, versionReplicaCnrKV, replicationStatusKV,
+		archiveStorageClassKV, archiveCnrKV, archiveOIDKV, checksumAlgorithmKV, checksumKV}
 	path := pathFromName(objectName)
 
 	p := &getNodesParams{
@@ -780,14 +1376,51 @@ func (c *TreeClient) GetAllVersionsByPrefix(ctx context.Context, bktInfo *data.B
 }
 
 func (c *TreeClient) getVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string, latestOnly bool) ([]*data.NodeVersion, error) {
+	shards, err := c.GetVersionsShards(ctx, bktInfo, prefix, latestOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*data.NodeVersion
+	for _, shard := range shards {
+		versions, err := c.GetShardVersions(ctx, bktInfo, shard, latestOnly)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, versions...)
+	}
+
+	return result, nil
+}
+
+func (c *TreeClient) GetVersionsShards(ctx context.Context, bktInfo *data.BucketInfo, prefix string, latestOnly bool) ([]*data.VersionsShard, error) {
 	prefixNodes, headPrefix, err := c.getSubTreeByPrefix(ctx, bktInfo, versionTree, prefix, latestOnly)
 	if err != nil {
 		return nil, err
 	}
 
+	sort.Slice(prefixNodes, func(i, j int) bool {
+		return getFilename(prefixNodes[i]) < getFilename(prefixNodes[j])
+	})
+
+	names := make([]string, len(prefixNodes))
+	for i, node := range prefixNodes {
+		names[i] = getFilename(node)
+	}
+
+	shards, indexes := data.SplitVersionsShards(headPrefix, names)
+	for i, node := range prefixNodes {
+		shard := shards[indexes[i]]
+		shard.NodeIDs = append(shard.NodeIDs, node.GetNodeId())
+	}
+
+	return shards, nil
+}
+
+func (c *TreeClient) GetShardVersions(ctx context.Context, bktInfo *data.BucketInfo, shard *data.VersionsShard, latestOnly bool) ([]*data.NodeVersion, error) {
 	var result []*data.NodeVersion
-	for _, node := range prefixNodes {
-		versions, err := c.getSubTreeVersions(ctx, bktInfo, node.GetNodeId(), headPrefix, latestOnly)
+	for _, nodeID := range shard.NodeIDs {
+		versions, err := c.getSubTreeVersions(ctx, bktInfo, nodeID, shard.Prefix, latestOnly)
 		if err != nil {
 			return nil, err
 		}
@@ -910,6 +1543,10 @@ func (c *TreeClient) AddPart(ctx context.Context, bktInfo *data.BucketInfo, mult
 		createdKV:    strconv.FormatInt(info.Created.UTC().UnixMilli(), 10),
 		etagKV:       info.ETag,
 	}
+	if info.ChecksumAlgorithm != "" {
+		meta[checksumAlgorithmKV] = info.ChecksumAlgorithm
+		meta[checksumKV] = info.Checksum
+	}
 
 	var foundPartID uint64
 	for _, part := range parts {
@@ -1020,6 +1657,107 @@ func getLock(lockNode *TreeNode) (*data.LockInfo, error) {
 	return lockInfo, nil
 }
 
+// GetReplicationStatus returns the replication state of the object version saved by PutReplicationStatus,
+// it's empty if the state isn't updated since the version creation.
+func (c *TreeClient) GetReplicationStatus(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (string, error) {
+	node, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isReplicationKV)
+	if err != nil || node == nil {
+		return "", err
+	}
+
+	status, _ := node.Get(replicationStatusKV)
+	return status, nil
+}
+
+// PutReplicationStatus saves the replication state of the object version in the child node,
+// so the version node itself isn't moved.
+func (c *TreeClient) PutReplicationStatus(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, status string) error {
+	node, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isReplicationKV)
+	if err != nil {
+		return err
+	}
+
+	meta := map[string]string{
+		isReplicationKV:     "true",
+		replicationStatusKV: status,
+	}
+
+	if node == nil {
+		_, err = c.addNode(ctx, bktInfo, versionTree, objVersion.ID, meta)
+		return err
+	}
+
+	return c.moveNode(ctx, bktInfo, versionTree, node.ID, objVersion.ID, meta)
+}
+
+// ArchiveVersion updates the version node with the location of the archived object by the tree move to the same parent.
+func (c *TreeClient) ArchiveVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	return c.moveNode(ctx, bktInfo, versionTree, version.ID, version.ParenID, metaFromVersion(version))
+}
+
+func (c *TreeClient) GetRestoreState(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (*data.RestoreInfo, error) {
+	node, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isRestoreKV)
+	if err != nil || node == nil {
+		return nil, err
+	}
+
+	restore := new(data.RestoreInfo)
+	_, restore.Ongoing = node.Get(restoreOngoingKV)
+	if daysStr, ok := node.Get(restoreDaysKV); ok {
+		restore.Days, _ = strconv.Atoi(daysStr)
+	}
+	if oidStr, ok := node.Get(restoreOIDKV); ok {
+		if err = restore.OID.DecodeString(oidStr); err != nil {
+			return nil, fmt.Errorf("invalid restored object id: %w", err)
+		}
+	}
+	if expiryStr, ok := node.Get(restoreExpiryKV); ok {
+		utcMilli, err := strconv.ParseInt(expiryStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid restore expiry: %w", err)
+		}
+		restore.Expiry = time.UnixMilli(utcMilli)
+	}
+
+	return restore, nil
+}
+
+// PutRestoreState saves the restore state of the archived version in the child node,
+// so the version node itself isn't moved.
+func (c *TreeClient) PutRestoreState(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, restore *data.RestoreInfo) error {
+	node, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isRestoreKV)
+	if err != nil {
+		return err
+	}
+
+	meta := map[string]string{
+		isRestoreKV:   "true",
+		restoreDaysKV: strconv.Itoa(restore.Days),
+	}
+	if restore.Ongoing {
+		meta[restoreOngoingKV] = "true"
+	} else {
+		meta[restoreOIDKV] = restore.OID.EncodeToString()
+		meta[restoreExpiryKV] = strconv.FormatInt(restore.Expiry.UTC().UnixMilli(), 10)
+	}
+
+	if node == nil {
+		_, err = c.addNode(ctx, bktInfo, versionTree, objVersion.ID, meta)
+		return err
+	}
+
+	return c.moveNode(ctx, bktInfo, versionTree, node.ID, objVersion.ID, meta)
+}
+
+func (c *TreeClient) DeleteRestoreState(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) error {
+	node, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isRestoreKV)
+	if err != nil || node == nil {
+		return err
+	}
+
+	return c.removeNode(ctx, bktInfo, versionTree, node.ID)
+}
+
 func (c *TreeClient) GetObjectTaggingAndLock(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, *data.LockInfo, error) {
 	nodes, err := c.getTreeNodes(ctx, bktInfo, objVersion.ID, isTagKV, isLockKV)
 	if err != nil {
@@ -1042,7 +1780,76 @@ func (c *TreeClient) Close() error {
 	return nil
 }
 
+// PackVersion updates the version node with the location of the object in the pack.
+func (c *TreeClient) PackVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	return c.moveNode(ctx, bktInfo, versionTree, version.ID, version.ParenID, metaFromVersion(version))
+}
+
+// UpdateVersionMetadata updates user metadata of the version node by the tree move to the same parent.
+func (c *TreeClient) UpdateVersionMetadata(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	return c.moveNode(ctx, bktInfo, versionTree, version.ID, version.ParenID, metaFromVersion(version))
+}
+
+// MoveVersion moves the version node to the new object name by a single tree operation,
+// so the object is always available either by the old name or by the new one.
+// Child nodes of the version (tagging and lock) are moved with it.
+func (c *TreeClient) MoveVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, newName string) error {
+	path := pathFromName(newName)
+	parentID, err := c.getOrCreateIntermediateNode(ctx, bktInfo, versionTree, path[:len(path)-1])
+	if err != nil {
+		return fmt.Errorf("couldn't get parent node of '%s': %w", newName, err)
+	}
+
+	newVersion := *version
+	newVersion.FilePath = newName
+
+	return c.moveNode(ctx, bktInfo, versionTree, version.ID, parentID, metaFromVersion(&newVersion))
+}
+
+// getOrCreateIntermediateNode returns ID of the intermediate node of the path, missing nodes of the path are created.
+func (c *TreeClient) getOrCreateIntermediateNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path []string) (uint64, error) {
+	var parentID uint64
+	for i := range path {
+		nodeID, err := c.getPrefixNodeID(ctx, bktInfo, treeID, path[:i+1])
+		if err == nil {
+			parentID = nodeID
+			continue
+		}
+		if !errors.Is(err, layer.ErrNodeNotFound) {
+			return 0, err
+		}
+
+		if parentID, err = c.addNode(ctx, bktInfo, treeID, parentID, map[string]string{fileNameKV: path[i]}); err != nil {
+			return 0, err
+		}
+	}
+
+	return parentID, nil
+}
+
 func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, treeID string, version *data.NodeVersion) (uint64, error) {
+	path := pathFromName(version.FilePath)
+	meta := metaFromVersion(version)
+
+	if version.IsUnversioned {
+		node, err := c.getUnversioned(ctx, bktInfo, treeID, version.FilePath)
+		if err == nil {
+			if err = c.moveNode(ctx, bktInfo, treeID, node.ID, node.ParenID, meta); err != nil {
+				return 0, err
+			}
+
+			return node.ID, c.clearOutdatedVersionInfo(ctx, bktInfo, treeID, node.ID)
+		}
+
+		if !errors.Is(err, layer.ErrNodeNotFound) {
+			return 0, err
+		}
+	}
+
+	return c.addNodeByPath(ctx, bktInfo, treeID, path[:len(path)-1], meta)
+}
+
+func metaFromVersion(version *data.NodeVersion) map[string]string {
 	path := pathFromName(version.FilePath)
 	meta := map[string]string{
 		oidKV:      version.OID.EncodeToString(),
@@ -1064,22 +1871,42 @@ func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, t
 
 	if version.IsUnversioned {
 		meta[isUnversionedKV] = "true"
+	}
 
-		node, err := c.getUnversioned(ctx, bktInfo, treeID, version.FilePath)
-		if err == nil {
-			if err = c.moveNode(ctx, bktInfo, treeID, node.ID, node.ParenID, meta); err != nil {
-				return 0, err
-			}
+	if version.Pack != nil {
+		meta[packOIDKV] = version.Pack.OID.EncodeToString()
+		meta[packOffsetKV] = strconv.FormatUint(version.Pack.Offset, 10)
+		meta[packMetaKV] = version.Pack.Meta
+	}
 
-			return node.ID, c.clearOutdatedVersionInfo(ctx, bktInfo, treeID, node.ID)
+	if version.Replica != nil {
+		meta[replicaOIDKV] = version.Replica.OID.EncodeToString()
+		meta[versionReplicaCnrKV] = version.Replica.Container.EncodeToString()
+		if len(version.Replica.Network) > 0 {
+			meta[versionReplicaNetworkKV] = version.Replica.Network
 		}
+	}
 
-		if !errors.Is(err, layer.ErrNodeNotFound) {
-			return 0, err
-		}
+	if len(version.Metadata) > 0 {
+		meta[metadataKV] = version.Metadata
 	}
 
-	return c.addNodeByPath(ctx, bktInfo, treeID, path[:len(path)-1], meta)
+	if len(version.ReplicationStatus) > 0 {
+		meta[replicationStatusKV] = version.ReplicationStatus
+	}
+
+	if version.Archive != nil {
+		meta[archiveStorageClassKV] = version.Archive.StorageClass
+		meta[archiveCnrKV] = version.Archive.Container.EncodeToString()
+		meta[archiveOIDKV] = version.Archive.OID.EncodeToString()
+	}
+
+	if version.ChecksumAlgorithm != "" {
+		meta[checksumAlgorithmKV] = version.ChecksumAlgorithm
+		meta[checksumKV] = version.Checksum
+	}
+
+	return meta
 }
 
 func (c *TreeClient) clearOutdatedVersionInfo(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
@@ -1095,7 +1922,9 @@ func (c *TreeClient) clearOutdatedVersionInfo(ctx context.Context, bktInfo *data
 }
 
 func (c *TreeClient) getVersions(ctx context.Context, bktInfo *data.BucketInfo, treeID, filepath string, onlyUnversioned bool) ([]*data.NodeVersion, error) {
-	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, packOIDKV, packOffsetKV, packMetaKV, metadataKV,
+		replicaOIDKV, versionReplicaNetworkKV, versionReplicaCnrKV, replicationStatusKV,
+		archiveStorageClassKV, archiveCnrKV, archiveOIDKV, checksumAlgorithmKV, checksumKV}
 	path := pathFromName(filepath)
 	p := &getNodesParams{
 		BktInfo:    bktInfo,
@@ -1170,11 +1999,24 @@ func (c *TreeClient) getSubTree(ctx context.Context, bktInfo *data.BucketInfo, t
 }
 
 func metaFromSettings(settings *data.BucketSettings) map[string]string {
-	results := make(map[string]string, 3)
+	results := make(map[string]string, 5)
 
 	results[fileNameKV] = settingsFileName
 	results[versioningKV] = settings.Versioning
+	results[mfaDeleteKV] = settings.MFADelete
 	results[lockConfigurationKV] = encodeLockConfiguration(settings.LockConfiguration)
+	results[trashRetentionKV] = settings.TrashRetention.String()
+	results[etagAlgorithmKV] = settings.ETagAlgorithm
+	if settings.SyncReplication != nil {
+		results[replicaNetworkKV] = settings.SyncReplication.Network
+		results[replicaContainerKV] = settings.SyncReplication.Container.EncodeToString()
+	} else {
+		results[replicaContainerKV] = ""
+	}
+	results[publicAccessBlockKV] = encodePublicAccessBlock(settings.PublicAccessBlock)
+	results[objectOwnershipKV] = settings.ObjectOwnership
+	results[requestPayerKV] = settings.RequestPayer
+	results[featuresKV] = encodeFeatures(settings.Features)
 
 	return results
 }
@@ -1452,3 +2294,72 @@ func encodeLockConfiguration(conf *data.ObjectLockConfiguration) string {
 	defaults := conf.Rule.DefaultRetention
 	return fmt.Sprintf("%s,%d,%s,%d", conf.ObjectLockEnabled, defaults.Days, defaults.Mode, defaults.Years)
 }
+
+func parsePublicAccessBlock(value string) (*data.PublicAccessBlockConfiguration, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	flags := strings.Split(value, ",")
+	if len(flags) != 4 {
+		return nil, fmt.Errorf("invalid public access block: %s", value)
+	}
+
+	values := make([]bool, len(flags))
+	for i := range flags {
+		var err error
+		if values[i], err = strconv.ParseBool(flags[i]); err != nil {
+			return nil, fmt.Errorf("invalid public access block: %s", value)
+		}
+	}
+
+	return &data.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       values[0],
+		IgnorePublicAcls:      values[1],
+		BlockPublicPolicy:     values[2],
+		RestrictPublicBuckets: values[3],
+	}, nil
+}
+
+func encodePublicAccessBlock(conf *data.PublicAccessBlockConfiguration) string {
+	if conf == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%t,%t,%t,%t", conf.BlockPublicAcls, conf.IgnorePublicAcls,
+		conf.BlockPublicPolicy, conf.RestrictPublicBuckets)
+}
+
+// parseFeatures parses feature flags of the bucket in format 'name=true,name2=false'.
+func parseFeatures(value string) (map[string]bool, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	flags := strings.Split(value, ",")
+	features := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		nameValue := strings.SplitN(flag, "=", 2)
+		if len(nameValue) != 2 {
+			return nil, fmt.Errorf("invalid feature flag: %s", flag)
+		}
+
+		enabled, err := strconv.ParseBool(nameValue[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature flag: %s", flag)
+		}
+		features[nameValue[0]] = enabled
+	}
+
+	return features, nil
+}
+
+func encodeFeatures(features map[string]bool) string {
+	flags := make([]string, 0, len(features))
+	for name, enabled := range features {
+		flags = append(flags, name+"="+strconv.FormatBool(enabled))
+	}
+	sort.Strings(flags)
+
+	return strings.Join(flags, ",")
+}
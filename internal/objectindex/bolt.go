@@ -0,0 +1,174 @@
+package objectindex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.etcd.io/bbolt"
+)
+
+type (
+	// BoltIndex is an object index stored in the embedded bbolt database.
+	// Objects of every bucket are stored in a separate database bucket named by container ID.
+	BoltIndex struct {
+		db *bbolt.DB
+	}
+
+	// objectRecord is a value of the indexed object.
+	objectRecord struct {
+		Size        int64             `json:"size"`
+		ContentType string            `json:"contentType,omitempty"`
+		Created     time.Time         `json:"created"`
+		HashSum     string            `json:"hashSum"`
+		Owner       string            `json:"owner"`
+		Headers     map[string]string `json:"headers,omitempty"`
+	}
+)
+
+// NewBoltIndex opens or creates the bbolt database of the object index.
+func NewBoltIndex(path string) (*BoltIndex, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt db: %w", err)
+	}
+
+	return &BoltIndex{db: db}, nil
+}
+
+// Close closes the database.
+func (b *BoltIndex) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltIndex) GetObject(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (*data.ObjectInfo, error) {
+	var value []byte
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		if bkt := tx.Bucket(bucketKey(bktInfo)); bkt != nil {
+			if v := bkt.Get(objID[:]); v != nil {
+				value = append([]byte(nil), v...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, layer.ErrNotIndexed
+	}
+
+	return decodeObjectInfo(bktInfo, objID, value)
+}
+
+func (b *BoltIndex) GetObjects(_ context.Context, bktInfo *data.BucketInfo, objIDs []oid.ID) (map[oid.ID]*data.ObjectInfo, error) {
+	objInfos := make(map[oid.ID]*data.ObjectInfo, len(objIDs))
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(bucketKey(bktInfo))
+		if bkt == nil {
+			return nil
+		}
+
+		for _, objID := range objIDs {
+			value := bkt.Get(objID[:])
+			if value == nil {
+				continue
+			}
+
+			objInfo, err := decodeObjectInfo(bktInfo, objID, value)
+			if err != nil {
+				return err
+			}
+			objInfos[objID] = objInfo
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objInfos, nil
+}
+
+func (b *BoltIndex) PutObject(_ context.Context, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) error {
+	value, err := json.Marshal(objectRecord{
+		Size:        objInfo.Size,
+		ContentType: objInfo.ContentType,
+		Created:     objInfo.Created,
+		HashSum:     objInfo.HashSum,
+		Owner:       objInfo.Owner.EncodeToString(),
+		Headers:     objInfo.Headers,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal object record: %w", err)
+	}
+
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(bucketKey(bktInfo))
+		if err != nil {
+			return err
+		}
+		return bkt.Put(objInfo.ID[:], value)
+	})
+}
+
+func (b *BoltIndex) DeleteObject(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		if bkt := tx.Bucket(bucketKey(bktInfo)); bkt != nil {
+			return bkt.Delete(objID[:])
+		}
+		return nil
+	})
+}
+
+func (b *BoltIndex) IterateObjects(_ context.Context, bktInfo *data.BucketInfo, f func(oid.ID) error) error {
+	return b.db.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(bucketKey(bktInfo))
+		if bkt == nil {
+			return nil
+		}
+
+		return bkt.ForEach(func(k, _ []byte) error {
+			var objID oid.ID
+			if len(k) != len(objID) {
+				return fmt.Errorf("invalid object key length: %d", len(k))
+			}
+			copy(objID[:], k)
+			return f(objID)
+		})
+	})
+}
+
+func decodeObjectInfo(bktInfo *data.BucketInfo, objID oid.ID, value []byte) (*data.ObjectInfo, error) {
+	var record objectRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, fmt.Errorf("unmarshal object record: %w", err)
+	}
+
+	objInfo := &data.ObjectInfo{
+		ID:          objID,
+		CID:         bktInfo.CID,
+		Bucket:      bktInfo.Name,
+		Size:        record.Size,
+		ContentType: record.ContentType,
+		Created:     record.Created,
+		HashSum:     record.HashSum,
+		Headers:     record.Headers,
+	}
+	if err := objInfo.Owner.DecodeString(record.Owner); err != nil {
+		return nil, fmt.Errorf("invalid object owner '%s': %w", record.Owner, err)
+	}
+	if objInfo.Headers == nil {
+		objInfo.Headers = make(map[string]string)
+	}
+
+	return objInfo, nil
+}
+
+func bucketKey(bktInfo *data.BucketInfo) []byte {
+	return []byte(bktInfo.CID.EncodeToString())
+}
@@ -0,0 +1,62 @@
+package objectindex
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	cidtest "github.com/nspcc-dev/neofs-sdk-go/container/id/test"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	usertest "github.com/nspcc-dev/neofs-sdk-go/user/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltIndex(t *testing.T) {
+	ctx := context.Background()
+	index, err := NewBoltIndex(filepath.Join(t.TempDir(), "index.db"))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, index.Close()) }()
+
+	bktInfo := &data.BucketInfo{Name: "bucket", CID: cidtest.ID()}
+	objInfo := &data.ObjectInfo{
+		ID:          oidtest.ID(),
+		CID:         bktInfo.CID,
+		Bucket:      bktInfo.Name,
+		Size:        10,
+		ContentType: "text/plain",
+		Created:     time.Unix(1000, 0).UTC(),
+		HashSum:     "hash",
+		Owner:       *usertest.ID(),
+		Headers:     map[string]string{"key": "value"},
+	}
+
+	_, err = index.GetObject(ctx, bktInfo, objInfo.ID)
+	require.ErrorIs(t, err, layer.ErrNotIndexed)
+
+	require.NoError(t, index.PutObject(ctx, bktInfo, objInfo))
+
+	indexed, err := index.GetObject(ctx, bktInfo, objInfo.ID)
+	require.NoError(t, err)
+	require.Equal(t, objInfo, indexed)
+
+	missedID := oidtest.ID()
+	indexedObjects, err := index.GetObjects(ctx, bktInfo, []oid.ID{objInfo.ID, missedID})
+	require.NoError(t, err)
+	require.Equal(t, map[oid.ID]*data.ObjectInfo{objInfo.ID: objInfo}, indexedObjects)
+
+	var ids []oid.ID
+	err = index.IterateObjects(ctx, bktInfo, func(objID oid.ID) error {
+		ids = append(ids, objID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []oid.ID{objInfo.ID}, ids)
+
+	require.NoError(t, index.DeleteObject(ctx, bktInfo, objInfo.ID))
+	_, err = index.GetObject(ctx, bktInfo, objInfo.ID)
+	require.ErrorIs(t, err, layer.ErrNotIndexed)
+}
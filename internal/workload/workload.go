@@ -0,0 +1,431 @@
+// Package workload generates synthetic S3 workload with configurable object sizes and operation mixes
+// and reports latencies of operations, it's used to catch performance regressions of the gateway.
+package workload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// Op is an S3 operation of the workload.
+type Op string
+
+// Operations of the workload.
+const (
+	OpPut  Op = "PutObject"
+	OpGet  Op = "GetObject"
+	OpList Op = "ListObjectsV2"
+)
+
+type (
+	// SizeClass is a range of object sizes chosen with the weight relative to other classes.
+	SizeClass struct {
+		Min, Max int64
+		Weight   int
+	}
+
+	// Config is a workload configuration.
+	Config struct {
+		// Bucket is an existing bucket the objects are written to.
+		Bucket string
+		// Operations is a total number of operations.
+		Operations int
+		// Concurrency is a number of parallel clients.
+		Concurrency int
+		// Sizes is a distribution of sizes of written objects.
+		Sizes []SizeClass
+		// Mix is weights of operations, GetObject and ListObjectsV2 are replaced with
+		// PutObject until the first object is written.
+		Mix map[Op]int
+		// Prefixes is a number of key prefixes, every listing requests a random prefix with '/' delimiter.
+		Prefixes int
+		// ListMaxKeys is max-keys parameter of listings, zero means the default of the server.
+		ListMaxKeys int
+		// Seed makes the sequence of operations of every client reproducible.
+		Seed int64
+	}
+
+	// Doer sends HTTP requests, http.Client implements it.
+	Doer interface {
+		Do(*http.Request) (*http.Response, error)
+	}
+
+	// HandlerDoer serves requests by the handler in the same process.
+	HandlerDoer struct {
+		Handler http.Handler
+	}
+
+	// signedDoer signs requests with AWS Signature Version 4 before sending them.
+	signedDoer struct {
+		client *http.Client
+		signer *v4.Signer
+		region string
+	}
+
+	// Generator runs the workload against the S3 endpoint.
+	Generator struct {
+		endpoint string
+		doer     Doer
+		cfg      Config
+		payload  []byte
+
+		mu      sync.Mutex
+		keys    []string
+		samples map[Op][]time.Duration
+		stats   map[Op]*OpStats
+	}
+
+	// OpStats is statistics of the operation.
+	OpStats struct {
+		Op     Op
+		Count  int
+		Errors int
+		// Bytes is a number of sent or received payload bytes.
+		Bytes              int64
+		P50, P90, P99, Max time.Duration
+	}
+
+	// Report is a result of the workload.
+	Report struct {
+		Ops      []OpStats
+		Duration time.Duration
+		// Allocs and AllocBytes are heap allocations of the process during the workload,
+		// they include allocations of the gateway only if it's served by HandlerDoer.
+		Allocs     uint64
+		AllocBytes uint64
+	}
+)
+
+// unsignedPayload is X-Amz-Content-Sha256 value, so the payload isn't hashed by the client.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// DefaultSizes is a distribution of object sizes dominated by small objects.
+var DefaultSizes = []SizeClass{
+	{Min: 1, Max: 4 << 10, Weight: 60},
+	{Min: 4 << 10, Max: 256 << 10, Weight: 30},
+	{Min: 256 << 10, Max: 4 << 20, Weight: 10},
+}
+
+// Do implements Doer.
+func (d HandlerDoer) Do(r *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	d.Handler.ServeHTTP(w, r)
+	return w.Result(), nil
+}
+
+// NewSignedDoer creates Doer signing requests with the credentials, e.g. to run the workload
+// against the gateway of the dev-env.
+func NewSignedDoer(client *http.Client, accessKeyID, secretAccessKey, region string) Doer {
+	return &signedDoer{
+		client: client,
+		signer: v4.NewSigner(credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")),
+		region: region,
+	}
+}
+
+func (d *signedDoer) Do(r *http.Request) (*http.Response, error) {
+	if _, err := d.signer.Sign(r, nil, "s3", d.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("sign request: %w", err)
+	}
+	return d.client.Do(r)
+}
+
+// New creates the generator of the workload against the endpoint, e.g. http://localhost:8080.
+func New(endpoint string, doer Doer, cfg Config) (*Generator, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket is not set")
+	}
+	if cfg.Operations <= 0 || cfg.Concurrency <= 0 {
+		return nil, fmt.Errorf("invalid number of operations %d or concurrency %d", cfg.Operations, cfg.Concurrency)
+	}
+	if cfg.Prefixes <= 0 {
+		cfg.Prefixes = 1
+	}
+	if len(cfg.Sizes) == 0 {
+		cfg.Sizes = DefaultSizes
+	}
+
+	var maxSize int64
+	for _, class := range cfg.Sizes {
+		if class.Min <= 0 || class.Max < class.Min || class.Weight <= 0 {
+			return nil, fmt.Errorf("invalid size class %+v", class)
+		}
+		if class.Max > maxSize {
+			maxSize = class.Max
+		}
+	}
+
+	var total int
+	for op, weight := range cfg.Mix {
+		if op != OpPut && op != OpGet && op != OpList {
+			return nil, fmt.Errorf("unknown operation %s", op)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("negative weight of %s", op)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("empty operation mix")
+	}
+
+	payload := make([]byte, maxSize)
+	rand.New(rand.NewSource(cfg.Seed)).Read(payload)
+
+	return &Generator{
+		endpoint: endpoint,
+		doer:     doer,
+		cfg:      cfg,
+		payload:  payload,
+		samples:  make(map[Op][]time.Duration),
+		stats:    make(map[Op]*OpStats),
+	}, nil
+}
+
+// Run runs the workload and reports latencies of operations. Operations answered with the error status
+// are counted in the report, Run fails only if the request can't be sent.
+func (g *Generator) Run(ctx context.Context) (*Report, error) {
+	ops := make(chan int)
+	errCh := make(chan error, g.cfg.Concurrency)
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < g.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(g.cfg.Seed + int64(client)))
+			for n := range ops {
+				if err := g.do(ctx, rnd, client, n); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}(i)
+	}
+
+	var err error
+loop:
+	for n := 0; n < g.cfg.Operations; n++ {
+		select {
+		case ops <- n:
+		case err = <-errCh:
+			break loop
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		}
+	}
+	close(ops)
+	wg.Wait()
+
+	if err == nil {
+		select {
+		case err = <-errCh:
+		default:
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	return g.report(time.Since(start), after.Mallocs-before.Mallocs, after.TotalAlloc-before.TotalAlloc), nil
+}
+
+func (g *Generator) do(ctx context.Context, rnd *rand.Rand, client, n int) error {
+	op := g.chooseOp(rnd)
+
+	g.mu.Lock()
+	if op != OpPut && len(g.keys) == 0 {
+		op = OpPut
+	}
+	var key string
+	if op == OpGet {
+		key = g.keys[rnd.Intn(len(g.keys))]
+	}
+	g.mu.Unlock()
+
+	var (
+		req  *http.Request
+		size int64
+		err  error
+	)
+	switch op {
+	case OpPut:
+		key = fmt.Sprintf("prefix-%04d/object-%04d-%08d", rnd.Intn(g.cfg.Prefixes), client, n)
+		size = g.chooseSize(rnd)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPut, g.objectURL(key), bytes.NewReader(g.payload[:size]))
+	case OpGet:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, g.objectURL(key), nil)
+	case OpList:
+		query := url.Values{
+			"list-type": []string{"2"},
+			"delimiter": []string{"/"},
+			"prefix":    []string{fmt.Sprintf("prefix-%04d/", rnd.Intn(g.cfg.Prefixes))},
+		}
+		if g.cfg.ListMaxKeys > 0 {
+			query.Set("max-keys", strconv.Itoa(g.cfg.ListMaxKeys))
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+"/"+g.cfg.Bucket+"?"+query.Encode(), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("create %s request: %w", op, err)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	start := time.Now()
+	resp, err := g.doer.Do(req)
+	if err != nil {
+		return fmt.Errorf("send %s request: %w", op, err)
+	}
+	received, err := io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	latency := time.Since(start)
+	if err != nil {
+		return fmt.Errorf("read %s response: %w", op, err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats, ok := g.stats[op]
+	if !ok {
+		stats = &OpStats{Op: op}
+		g.stats[op] = stats
+	}
+	stats.Count++
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		stats.Errors++
+		return nil
+	}
+
+	g.samples[op] = append(g.samples[op], latency)
+	switch op {
+	case OpPut:
+		stats.Bytes += size
+		g.keys = append(g.keys, key)
+	case OpGet:
+		stats.Bytes += received
+	}
+	return nil
+}
+
+func (g *Generator) objectURL(key string) string {
+	return g.endpoint + "/" + g.cfg.Bucket + "/" + key
+}
+
+func (g *Generator) chooseOp(rnd *rand.Rand) Op {
+	var total int
+	for _, op := range []Op{OpPut, OpGet, OpList} {
+		total += g.cfg.Mix[op]
+	}
+
+	n := rnd.Intn(total)
+	for _, op := range []Op{OpPut, OpGet, OpList} {
+		if n < g.cfg.Mix[op] {
+			return op
+		}
+		n -= g.cfg.Mix[op]
+	}
+	return OpPut
+}
+
+func (g *Generator) chooseSize(rnd *rand.Rand) int64 {
+	var total int
+	for _, class := range g.cfg.Sizes {
+		total += class.Weight
+	}
+
+	n := rnd.Intn(total)
+	for _, class := range g.cfg.Sizes {
+		if n < class.Weight {
+			return class.Min + rnd.Int63n(class.Max-class.Min+1)
+		}
+		n -= class.Weight
+	}
+	return g.cfg.Sizes[0].Min
+}
+
+func (g *Generator) report(duration time.Duration, allocs, allocBytes uint64) *Report {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	res := &Report{
+		Duration:   duration,
+		Allocs:     allocs,
+		AllocBytes: allocBytes,
+	}
+	for _, op := range []Op{OpPut, OpGet, OpList} {
+		stats, ok := g.stats[op]
+		if !ok {
+			continue
+		}
+
+		samples := g.samples[op]
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		if len(samples) != 0 {
+			stats.P50 = percentile(samples, 50)
+			stats.P90 = percentile(samples, 90)
+			stats.P99 = percentile(samples, 99)
+			stats.Max = samples[len(samples)-1]
+		}
+		res.Ops = append(res.Ops, *stats)
+	}
+	return res
+}
+
+// percentile returns the percentile of sorted samples.
+func percentile(samples []time.Duration, p int) time.Duration {
+	return samples[(len(samples)*p+99)/100-1]
+}
+
+// Operations returns the total number of operations.
+func (r *Report) Operations() int {
+	var n int
+	for _, stats := range r.Ops {
+		n += stats.Count
+	}
+	return n
+}
+
+// WriteTo writes the report as a table.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "operation\tcount\terrors\tbytes\tp50\tp90\tp99\tmax\t")
+	for _, stats := range r.Ops {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t\n", stats.Op, stats.Count, stats.Errors, stats.Bytes,
+			stats.P50, stats.P90, stats.P99, stats.Max)
+	}
+	_ = tw.Flush()
+
+	if ops := r.Operations(); ops != 0 {
+		fmt.Fprintf(&buf, "%d operations in %s, %.1f op/s, %d allocs/op, %d B/op\n", ops, r.Duration,
+			float64(ops)/r.Duration.Seconds(), r.Allocs/uint64(ops), r.AllocBytes/uint64(ops))
+	}
+
+	return buf.WriteTo(w)
+}
@@ -0,0 +1,83 @@
+package workload
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	objects := make(map[string]int)
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Equal(t, unsignedPayload, r.Header.Get("X-Amz-Content-Sha256"))
+		switch {
+		case r.Method == http.MethodPut:
+			requests[string(OpPut)]++
+			objects[r.URL.Path] = int(r.ContentLength)
+			require.True(t, r.ContentLength >= 10 && r.ContentLength <= 20)
+		case r.URL.Query().Get("list-type") == "2":
+			requests[string(OpList)]++
+			require.True(t, strings.HasPrefix(r.URL.Query().Get("prefix"), "prefix-000"))
+			require.Equal(t, "5", r.URL.Query().Get("max-keys"))
+		default:
+			requests[string(OpGet)]++
+			size, ok := objects[r.URL.Path]
+			require.True(t, ok, r.URL.Path)
+			_, _ = w.Write(make([]byte, size))
+		}
+	})
+
+	gen, err := New("", HandlerDoer{Handler: h}, Config{
+		Bucket:      "bucket",
+		Operations:  200,
+		Concurrency: 4,
+		Sizes:       []SizeClass{{Min: 10, Max: 20, Weight: 1}},
+		Mix:         map[Op]int{OpPut: 1, OpGet: 1, OpList: 1},
+		Prefixes:    4,
+		ListMaxKeys: 5,
+	})
+	require.NoError(t, err)
+
+	report, err := gen.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 200, report.Operations())
+	require.Len(t, report.Ops, 3)
+
+	for _, stats := range report.Ops {
+		require.Equal(t, requests[string(stats.Op)], stats.Count)
+		require.Zero(t, stats.Errors)
+		require.True(t, stats.P50 <= stats.P90 && stats.P90 <= stats.P99 && stats.P99 <= stats.Max)
+		if stats.Op != OpList {
+			require.True(t, stats.Bytes >= int64(10*stats.Count) && stats.Bytes <= int64(20*stats.Count))
+		}
+	}
+
+	var buf strings.Builder
+	_, err = report.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "200 operations")
+
+	_, err = New("", HandlerDoer{Handler: h}, Config{Bucket: "bucket", Operations: 1, Concurrency: 1})
+	require.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 100)
+	for i := range samples {
+		samples[i] = time.Duration(i + 1)
+	}
+	require.Equal(t, time.Duration(50), percentile(samples, 50))
+	require.Equal(t, time.Duration(99), percentile(samples, 99))
+	require.Equal(t, time.Duration(1), percentile(samples[:1], 99))
+}